@@ -0,0 +1,8 @@
+package alerts
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrUnknownSeverity = errors.New("unknown alert severity")
+)
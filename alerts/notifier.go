@@ -0,0 +1,91 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Notifier is the interface that wraps the Notify method.
+type Notifier interface {
+	// Notify posts the alert to the channel.
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// SlackNotifier posts alerts to a Slack incoming webhook.
+type SlackNotifier struct {
+	client     *http.Client
+	webhookURL string
+}
+
+// NewSlackNotifier creates a new Slack notifier.
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		client:     &http.Client{Timeout: 10 * time.Second},
+		webhookURL: webhookURL,
+	}
+}
+
+// Notify posts the alert to the Slack channel.
+func (n *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, n.client, n.webhookURL, map[string]interface{}{
+		"text": alert.text(),
+	})
+}
+
+// TelegramNotifier posts alerts to a Telegram chat via a bot.
+type TelegramNotifier struct {
+	client   *http.Client
+	endpoint string
+	chatID   string
+}
+
+// NewTelegramNotifier creates a new Telegram notifier.
+// The bot must be a member of the chat with the given id.
+func NewTelegramNotifier(botToken, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: fmt.Sprintf("https://api.telegram.org/bot%s/sendMessage", botToken),
+		chatID:   chatID,
+	}
+}
+
+// Notify posts the alert to the Telegram chat.
+func (n *TelegramNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, n.client, n.endpoint, map[string]interface{}{
+		"chat_id":                  n.chatID,
+		"text":                     alert.text(),
+		"disable_web_page_preview": true,
+	})
+}
+
+// postJSON sends a POST request with the JSON payload and checks the response status.
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to post alert: unexpected status code: %d: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+)
+
+// Default severity of the supported events.
+var defaultEventSeverity = map[events.EventName]Severity{
+	events.PaymentFailed:             SeverityWarning,
+	events.WebhookDeadLettered:       SeverityCritical,
+	events.ReconciliationDiscrepancy: SeverityCritical,
+}
+
+type (
+	// Service dispatches alerts to the configured channels.
+	// Each channel receives only alerts with severity equal or higher than its minimum severity.
+	Service struct {
+		channels      []channel
+		eventSeverity map[events.EventName]Severity
+		timeout       time.Duration
+	}
+
+	// ServiceOption is a function that configures the alerts service.
+	ServiceOption func(*Service)
+
+	channel struct {
+		notifier    Notifier
+		minSeverity Severity
+	}
+)
+
+// NewService creates a new alerts service.
+func NewService(opts ...ServiceOption) *Service {
+	s := &Service{
+		eventSeverity: make(map[events.EventName]Severity, len(defaultEventSeverity)),
+		timeout:       10 * time.Second,
+	}
+	for name, severity := range defaultEventSeverity {
+		s.eventSeverity[name] = severity
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithNotifier adds a channel to post alerts with the given minimum severity to.
+func WithNotifier(n Notifier, minSeverity Severity) ServiceOption {
+	return func(s *Service) {
+		s.channels = append(s.channels, channel{notifier: n, minSeverity: minSeverity})
+	}
+}
+
+// WithEventSeverity overrides the severity of the given event.
+func WithEventSeverity(name events.EventName, severity Severity) ServiceOption {
+	return func(s *Service) {
+		s.eventSeverity[name] = severity
+	}
+}
+
+// WithTimeout sets the timeout to post an alert to all channels.
+func WithTimeout(d time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.timeout = d
+	}
+}
+
+// Enabled returns true if at least one channel is configured.
+func (s *Service) Enabled() bool {
+	return len(s.channels) > 0
+}
+
+// Events returns the list of events the service posts alerts for.
+func (s *Service) Events() []events.EventName {
+	names := make([]events.EventName, 0, len(s.eventSeverity))
+	for name := range s.eventSeverity {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Notify posts the alert to all channels which accept its severity.
+func (s *Service) Notify(ctx context.Context, alert Alert) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var errs []string
+	for _, ch := range s.channels {
+		if alert.Severity < ch.minSeverity {
+			continue
+		}
+		if err := ch.notifier.Notify(ctx, alert); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("failed to post alert: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Listener returns an events listener that posts alerts for the supported events.
+func (s *Service) Listener() events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		severity, ok := s.eventSeverity[event]
+		if !ok || payload == nil {
+			return nil
+		}
+
+		alert := Alert{Severity: severity}
+		switch p := payload.(type) {
+		case events.WebhookDeadLetteredPayload:
+			alert.Title = "Webhook delivery failed permanently"
+			alert.Fields = []Field{
+				{Name: "Event", Value: p.Event},
+				{Name: "Task ID", Value: p.TaskID},
+				{Name: "Retried", Value: fmt.Sprintf("%d", p.Retried)},
+				{Name: "Error", Value: p.Error},
+			}
+		case events.ReconciliationDiscrepancyPayload:
+			alert.Title = "Reconciliation discrepancy"
+			alert.Fields = []Field{
+				{Name: "Payment ID", Value: p.GetPaymentID()},
+				{Name: "Reference", Value: p.Reference},
+				{Name: "Expected", Value: p.Expected},
+				{Name: "Actual", Value: p.Actual},
+				{Name: "Details", Value: p.Details},
+			}
+		case events.PaymentIDGetter:
+			alert.Title = fmt.Sprintf("Event %s", event)
+			if event == events.PaymentFailed {
+				alert.Title = "Payment failed"
+			}
+			alert.Fields = []Field{{Name: "Payment ID", Value: p.GetPaymentID()}}
+		default:
+			alert.Title = fmt.Sprintf("Event %s", event)
+		}
+
+		return s.Notify(context.Background(), alert)
+	}
+}
@@ -0,0 +1,83 @@
+package alerts_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/alerts"
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/stretchr/testify/require"
+)
+
+type notifierFunc func(ctx context.Context, alert alerts.Alert) error
+
+func (f notifierFunc) Notify(ctx context.Context, alert alerts.Alert) error {
+	return f(ctx, alert)
+}
+
+func TestServiceListener(t *testing.T) {
+	var warnings, criticals []alerts.Alert
+
+	svc := alerts.NewService(
+		alerts.WithNotifier(notifierFunc(func(ctx context.Context, a alerts.Alert) error {
+			warnings = append(warnings, a)
+			return nil
+		}), alerts.SeverityWarning),
+		alerts.WithNotifier(notifierFunc(func(ctx context.Context, a alerts.Alert) error {
+			criticals = append(criticals, a)
+			return nil
+		}), alerts.SeverityCritical),
+	)
+	require.True(t, svc.Enabled())
+
+	listener := svc.Listener()
+
+	err := listener(events.PaymentFailed, events.PaymentStatusUpdatedPayload{
+		PaymentID: events.PaymentID{PaymentID: "payment-id"},
+		Status:    "failed",
+	})
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	require.Len(t, criticals, 0)
+	require.Equal(t, alerts.SeverityWarning, warnings[0].Severity)
+	require.Equal(t, "Payment failed", warnings[0].Title)
+
+	err = listener(events.WebhookDeadLettered, events.WebhookDeadLetteredPayload{
+		TaskID: "task-id",
+		Event:  string(events.PaymentSucceeded),
+	})
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+	require.Len(t, criticals, 1)
+
+	// unsupported events are ignored
+	err = listener(events.PaymentCreated, events.PaymentCreatedPayload{})
+	require.NoError(t, err)
+	require.Len(t, warnings, 2)
+}
+
+func TestServiceEventSeverityOverride(t *testing.T) {
+	var got []alerts.Alert
+
+	svc := alerts.NewService(
+		alerts.WithNotifier(notifierFunc(func(ctx context.Context, a alerts.Alert) error {
+			got = append(got, a)
+			return nil
+		}), alerts.SeverityCritical),
+		alerts.WithEventSeverity(events.PaymentFailed, alerts.SeverityCritical),
+	)
+
+	err := svc.Listener()(events.PaymentFailed, events.PaymentStatusUpdatedPayload{})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, alerts.SeverityCritical, got[0].Severity)
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, err := alerts.ParseSeverity("Warning")
+	require.NoError(t, err)
+	require.Equal(t, alerts.SeverityWarning, s)
+
+	_, err = alerts.ParseSeverity("unknown")
+	require.ErrorIs(t, err, alerts.ErrUnknownSeverity)
+}
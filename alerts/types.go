@@ -0,0 +1,69 @@
+package alerts
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the severity level of an alert.
+type Severity int
+
+// Predefined severity levels.
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityCritical
+)
+
+// String returns the string representation of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityCritical:
+		return "critical"
+	default:
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+}
+
+// ParseSeverity parses the severity level from string: info, warning or critical.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "info":
+		return SeverityInfo, nil
+	case "warning", "warn":
+		return SeverityWarning, nil
+	case "critical", "crit", "error":
+		return SeverityCritical, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownSeverity, s)
+	}
+}
+
+type (
+	// Alert is a message for operators.
+	Alert struct {
+		Severity Severity
+		Title    string
+		Fields   []Field
+	}
+
+	// Field is a key-value pair attached to the alert.
+	Field struct {
+		Name  string
+		Value string
+	}
+)
+
+// text returns the plain text representation of the alert.
+func (a Alert) text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", strings.ToUpper(a.Severity.String()), a.Title)
+	for _, f := range a.Fields {
+		fmt.Fprintf(&b, "\n%s: %s", f.Name, f.Value)
+	}
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package allowances
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		GetAllowance endpoint.Endpoint
+		Approve      endpoint.Endpoint
+		Revoke       endpoint.Endpoint
+		Charge       endpoint.Endpoint
+	}
+
+	// AllowanceRequest is the request type for the GetAllowance and Revoke methods.
+	AllowanceRequest struct {
+		Wallet string `json:"wallet" validate:"required|solanaWallet"`
+		Mint   string `json:"mint" validate:"required"` // mint address or symbol, e.g. USDC
+	}
+
+	// ApproveRequest is the request type for the Approve method.
+	ApproveRequest struct {
+		Wallet string `json:"wallet" validate:"required|solanaWallet"`
+		Mint   string `json:"mint" validate:"required"`        // mint address or symbol, e.g. USDC
+		Amount uint64 `json:"amount" validate:"required|gt:0"` // allowance in minimal units of the mint
+	}
+
+	// AllowanceResponse is the response type for the GetAllowance method.
+	AllowanceResponse struct {
+		Allowance *Allowance `json:"allowance"`
+	}
+
+	// TransactionResponse is the response type for the Approve and Revoke methods.
+	TransactionResponse struct {
+		Transaction string `json:"transaction"` // base64 encoded transaction to be signed by the customer.
+	}
+
+	// ChargeRequest is the request type for the Charge method.
+	ChargeRequest struct {
+		Wallet      string `json:"wallet" validate:"required|solanaWallet"`
+		Mint        string `json:"mint" validate:"required"` // mint address or symbol, e.g. USDC
+		Amount      uint64 `json:"amount" validate:"required|gt:0"`
+		RequestedBy string `json:"-"`
+	}
+
+	// ChargeResponse is the response type for the Charge method.
+	ChargeResponse struct {
+		Charge *Charge `json:"charge"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		GetAllowance: makeGetAllowanceEndpoint(s),
+		Approve:      makeApproveEndpoint(s),
+		Revoke:       makeRevokeEndpoint(s),
+		Charge:       makeChargeEndpoint(s),
+	}
+}
+
+// makeGetAllowanceEndpoint returns an endpoint function for the GetAllowance method.
+func makeGetAllowanceEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, mint, err := allowanceRequest(request)
+		if err != nil {
+			return nil, err
+		}
+
+		allowance, err := s.GetAllowance(ctx, req.Wallet, mint)
+		if err != nil {
+			return nil, err
+		}
+
+		return AllowanceResponse{Allowance: allowance}, nil
+	}
+}
+
+// makeApproveEndpoint returns an endpoint function for the BuildApprove method.
+func makeApproveEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ApproveRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		mint, err := mintAddress(req.Mint)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err := s.BuildApprove(ctx, req.Wallet, mint, req.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		return TransactionResponse{Transaction: tx}, nil
+	}
+}
+
+// makeRevokeEndpoint returns an endpoint function for the BuildRevoke method.
+func makeRevokeEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, mint, err := allowanceRequest(request)
+		if err != nil {
+			return nil, err
+		}
+
+		tx, err := s.BuildRevoke(ctx, req.Wallet, mint)
+		if err != nil {
+			return nil, err
+		}
+
+		return TransactionResponse{Transaction: tx}, nil
+	}
+}
+
+// makeChargeEndpoint returns an endpoint function for the Charge method.
+func makeChargeEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ChargeRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		mint, err := mintAddress(req.Mint)
+		if err != nil {
+			return nil, err
+		}
+
+		charge, err := s.Charge(ctx, ChargeParams{
+			Wallet:      req.Wallet,
+			Mint:        mint,
+			Amount:      req.Amount,
+			RequestedBy: req.RequestedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return ChargeResponse{Charge: charge}, nil
+	}
+}
+
+// allowanceRequest validates the allowance request and resolves its mint address.
+func allowanceRequest(request interface{}) (AllowanceRequest, string, error) {
+	req, ok := request.(AllowanceRequest)
+	if !ok {
+		return req, "", ErrInvalidRequest
+	}
+	if v := validator.ValidateStruct(req); len(v) > 0 {
+		return req, "", validator.NewValidationError(v)
+	}
+
+	mint, err := mintAddress(req.Mint)
+	return req, mint, err
+}
+
+// mintAddress resolves the mint address of the given symbol or address.
+// SOL can't be delegated, and unknown symbols are resolved to SOL by payments.MintAddress,
+// so both are rejected.
+func mintAddress(currency string) (string, error) {
+	mint := payments.MintAddress(currency, payments.SOL)
+	if mint == payments.SOL {
+		return "", ErrMintNotSupported
+	}
+	return mint, nil
+}
@@ -0,0 +1,13 @@
+package allowances
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest      = errors.New("invalid_request")
+	ErrMintNotSupported    = errors.New("mint_not_supported")
+	ErrTokenAccountMissing = errors.New("token_account_missing")
+	ErrNotApproved         = errors.New("allowance_not_approved")
+	ErrAllowanceExceeded   = errors.New("allowance_exceeded")
+	ErrInsufficientBalance = errors.New("insufficient_balance")
+)
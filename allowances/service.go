@@ -0,0 +1,179 @@
+package allowances
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// Service manages the spending allowances the customers approve to the merchant
+// for the recurring pulls: the customer signs an approve transaction which delegates
+// the token account to the merchant delegate wallet, then the merchant charges
+// the allowance by the delegated transfers to the merchant wallet.
+type Service struct {
+	sol         solanaClient
+	destination string
+	delegate    solana.Signer
+	log         Logger
+}
+
+// NewService creates a new allowances service.
+// The delegate signs the delegated transfers and pays their fees,
+// the charged funds are transferred to the destination wallet.
+func NewService(sol solanaClient, destination string, delegate solana.Signer, log Logger) *Service {
+	if destination == "" {
+		panic("destination wallet address is required")
+	}
+	if delegate == nil {
+		panic("delegate signer is required")
+	}
+	if log == nil {
+		panic("logger is required")
+	}
+
+	return &Service{
+		sol:         sol,
+		destination: destination,
+		delegate:    delegate,
+		log:         log,
+	}
+}
+
+// Delegate returns the base58 encoded public key of the merchant delegate wallet.
+func (s *Service) Delegate() string {
+	return s.delegate.PublicKey().ToBase58()
+}
+
+// GetAllowance returns the allowance of the customer token account of the given mint.
+func (s *Service) GetAllowance(ctx context.Context, wallet, mint string) (*Allowance, error) {
+	if mint == solana.NativeMint {
+		return nil, ErrMintNotSupported
+	}
+
+	allowance, err := s.sol.GetTokenAllowance(ctx, wallet, mint)
+	if err != nil {
+		if errors.Is(err, solana.ErrTokenAccountDoesNotExist) {
+			return nil, ErrTokenAccountMissing
+		}
+		return nil, fmt.Errorf("failed to get token allowance: %w", err)
+	}
+
+	result := &Allowance{
+		Wallet:   wallet,
+		Mint:     mint,
+		Delegate: s.Delegate(),
+		Balance:  allowance.Balance,
+	}
+	if allowance.Delegate == result.Delegate {
+		result.Approved = true
+		result.Amount = allowance.Amount
+	}
+
+	return result, nil
+}
+
+// BuildApprove builds the transaction which approves the allowance of the given amount
+// to the merchant delegate wallet. It replaces the previous allowance, if any.
+// The transaction must be signed by the customer, who also pays the fee.
+func (s *Service) BuildApprove(ctx context.Context, wallet, mint string, amount uint64) (string, error) {
+	if mint == solana.NativeMint {
+		return "", ErrMintNotSupported
+	}
+
+	tx, err := solana.NewTransactionBuilder(s.sol).
+		SetFeePayer(wallet).
+		AddInstruction(solana.ApproveTokenDelegate(solana.ApproveTokenDelegateParams{
+			Owner:    wallet,
+			Mint:     mint,
+			Delegate: s.Delegate(),
+			Amount:   amount,
+		})).
+		Build(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to build approve transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// BuildRevoke builds the transaction which revokes the allowance of the customer token account.
+// The transaction must be signed by the customer, who also pays the fee.
+func (s *Service) BuildRevoke(ctx context.Context, wallet, mint string) (string, error) {
+	if mint == solana.NativeMint {
+		return "", ErrMintNotSupported
+	}
+
+	tx, err := solana.NewTransactionBuilder(s.sol).
+		SetFeePayer(wallet).
+		AddInstruction(solana.RevokeTokenDelegate(solana.RevokeTokenDelegateParams{
+			Owner: wallet,
+			Mint:  mint,
+		})).
+		Build(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to build revoke transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// Charge transfers the amount from the customer token account to the merchant wallet
+// against the approved allowance. The transfer is signed and sent by the delegate.
+func (s *Service) Charge(ctx context.Context, params ChargeParams) (*Charge, error) {
+	s.log.Infof("allowances: charge of %d %s from %s requested by %s",
+		params.Amount, params.Mint, params.Wallet, params.RequestedBy)
+
+	result, err := s.charge(ctx, params)
+	if err != nil {
+		s.log.Errorf("allowances: charge of %d %s from %s requested by %s failed: %v",
+			params.Amount, params.Mint, params.Wallet, params.RequestedBy, err)
+		return nil, err
+	}
+
+	s.log.Infof("allowances: charge of %d %s from %s requested by %s sent: %s",
+		params.Amount, params.Mint, params.Wallet, params.RequestedBy, result.Signature)
+
+	return result, nil
+}
+
+func (s *Service) charge(ctx context.Context, params ChargeParams) (*Charge, error) {
+	allowance, err := s.GetAllowance(ctx, params.Wallet, params.Mint)
+	if err != nil {
+		return nil, err
+	}
+	if !allowance.Approved {
+		return nil, ErrNotApproved
+	}
+	if params.Amount > allowance.Amount {
+		return nil, fmt.Errorf("%w: max %d", ErrAllowanceExceeded, allowance.Amount)
+	}
+	if params.Amount > allowance.Balance {
+		return nil, ErrInsufficientBalance
+	}
+
+	builder := solana.NewTransactionBuilder(s.sol).
+		SetFeePayer(s.Delegate()).
+		AddInstruction(solana.TransferToken(solana.TransferTokenParam{
+			Sender:    params.Wallet,
+			Recipient: s.destination,
+			Mint:      params.Mint,
+			Amount:    params.Amount,
+			Delegate:  s.Delegate(),
+		})).
+		AddExternalSigner(s.delegate)
+
+	signature, err := solana.NewSender(s.sol).Send(ctx, builder.Build)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send charge transaction: %w", err)
+	}
+
+	return &Charge{
+		Wallet:      params.Wallet,
+		Destination: s.destination,
+		Mint:        params.Mint,
+		Amount:      params.Amount,
+		Signature:   signature,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package allowances
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the allowances API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/{wallet}/{mint}", httptransport.NewServer(
+		e.GetAllowance,
+		decodeGetAllowanceRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/approve", httptransport.NewServer(
+		e.Approve,
+		decodeApproveRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/revoke", httptransport.NewServer(
+		e.Revoke,
+		decodeRevokeRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/charge", httptransport.NewServer(
+		e.Charge,
+		decodeChargeRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		return http.StatusPreconditionFailed, err
+	case errors.Is(err, ErrTokenAccountMissing):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrNotApproved),
+		errors.Is(err, ErrAllowanceExceeded),
+		errors.Is(err, ErrInsufficientBalance):
+		return http.StatusPaymentRequired, err.Error()
+	case errors.Is(err, ErrInvalidRequest),
+		errors.Is(err, ErrMintNotSupported):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeGetAllowanceRequest is a transport/http.DecodeRequestFunc that decodes
+// the wallet and mint from the URL path.
+func decodeGetAllowanceRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return AllowanceRequest{
+		Wallet: chi.URLParam(r, "wallet"),
+		Mint:   chi.URLParam(r, "mint"),
+	}, nil
+}
+
+// decodeApproveRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeApproveRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req ApproveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	return req, nil
+}
+
+// decodeRevokeRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeRevokeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req AllowanceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	return req, nil
+}
+
+// decodeChargeRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeChargeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req ChargeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	// OAuth2 client credentials, set by the auth middleware
+	req.RequestedBy, _ = ctx.Value(oauth.CredentialContext).(string)
+
+	return req, nil
+}
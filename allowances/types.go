@@ -0,0 +1,49 @@
+package allowances
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// Allowance is the spending allowance of a customer token account approved to the merchant.
+	Allowance struct {
+		Wallet   string `json:"wallet"`
+		Mint     string `json:"mint"`
+		Delegate string `json:"delegate"` // merchant delegate wallet.
+		Approved bool   `json:"approved"` // the token account is delegated to the merchant.
+		Amount   uint64 `json:"amount"`   // remaining allowance in minimal units of the mint.
+		Balance  uint64 `json:"balance"`  // token account balance in minimal units of the mint.
+	}
+
+	// ChargeParams defines the parameters of a pull from the customer allowance.
+	ChargeParams struct {
+		Wallet      string // base58 encoded customer wallet address.
+		Mint        string // base58 encoded mint address.
+		Amount      uint64 // amount in minimal units of the mint.
+		RequestedBy string // client which requested the charge, for the audit log.
+	}
+
+	// Charge is a sent delegated transfer from the customer wallet to the merchant wallet.
+	Charge struct {
+		Wallet      string `json:"wallet"`
+		Destination string `json:"destination"`
+		Mint        string `json:"mint"`
+		Amount      uint64 `json:"amount"`
+		Signature   string `json:"signature"`
+	}
+
+	solanaClient interface {
+		solana.SolanaClient
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+		GetTransactionStatus(ctx context.Context, txhash string, commitment solana.Commitment) (solana.TransactionStatus, error)
+		GetTokenAllowance(ctx context.Context, base58Addr, base58MintAddr string) (solana.TokenAllowance, error)
+	}
+
+	// Logger is used for the charges audit log.
+	Logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
@@ -0,0 +1,131 @@
+package apikeys
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		List   endpoint.Endpoint
+		Create endpoint.Endpoint
+		Rotate endpoint.Endpoint
+		Revoke endpoint.Endpoint
+	}
+
+	// CreateRequest is the request type for the Create method.
+	CreateRequest struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+		// Livemode is the mode of the key, the live one by default.
+		Livemode *bool `json:"livemode,omitempty"`
+		// Scopes limit the key to the routes of the scopes, e.g. ["payments:read"], all the routes of the role by default.
+		Scopes []string `json:"scopes,omitempty"`
+	}
+
+	// RotateRequest is the request type for the Rotate method.
+	RotateRequest struct {
+		ID uuid.UUID `json:"-"`
+		// GracePeriodSeconds is how long the previous key keeps working, up to MaxGracePeriod.
+		GracePeriodSeconds int64 `json:"grace_period_seconds"`
+	}
+
+	// RevokeRequest is the request type for the Revoke method.
+	RevokeRequest struct {
+		ID uuid.UUID
+	}
+
+	// ListResponse is the response type for the List method.
+	ListResponse struct {
+		APIKeys []APIKey `json:"api_keys"`
+	}
+
+	// IssuedAPIKeyResponse is the response type for the Create and Rotate methods.
+	IssuedAPIKeyResponse struct {
+		APIKey *IssuedAPIKey `json:"api_key"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		List:   makeListEndpoint(s),
+		Create: makeCreateEndpoint(s),
+		Rotate: makeRotateEndpoint(s),
+		Revoke: makeRevokeEndpoint(s),
+	}
+}
+
+// makeListEndpoint returns an endpoint function for the List method.
+func makeListEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		keys, err := s.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return ListResponse{APIKeys: keys}, nil
+	}
+}
+
+// makeCreateEndpoint returns an endpoint function for the Create method.
+func makeCreateEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CreateRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		livemode := req.Livemode == nil || *req.Livemode
+		key, err := s.Create(ctx, req.Name, req.Role, livemode, req.Scopes...)
+		if err != nil {
+			return nil, err
+		}
+
+		return IssuedAPIKeyResponse{APIKey: key}, nil
+	}
+}
+
+// makeRotateEndpoint returns an endpoint function for the Rotate method.
+func makeRotateEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(RotateRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		// checked before the conversion, so the large values don't overflow the duration
+		if req.GracePeriodSeconds > int64(MaxGracePeriod/time.Second) {
+			return nil, fmt.Errorf("%w: grace period must be between 0 and %s", ErrInvalidRequest, MaxGracePeriod)
+		}
+
+		key, err := s.Rotate(ctx, req.ID, time.Duration(req.GracePeriodSeconds)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+
+		return IssuedAPIKeyResponse{APIKey: key}, nil
+	}
+}
+
+// makeRevokeEndpoint returns an endpoint function for the Revoke method.
+func makeRevokeEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(RevokeRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		if err := s.Revoke(ctx, req.ID); err != nil {
+			return nil, err
+		}
+
+		return true, nil
+	}
+}
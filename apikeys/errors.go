@@ -0,0 +1,9 @@
+package apikeys
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest = errors.New("invalid_request")
+	ErrAPIKeyNotFound = errors.New("api_key_not_found")
+)
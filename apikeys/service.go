@@ -0,0 +1,160 @@
+package apikeys
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// MaxGracePeriod is the longest time the previous key keeps working after the rotation.
+const MaxGracePeriod = 7 * 24 * time.Hour
+
+type (
+	// Service manages the static API keys, the alternative to the OAuth2 client credentials.
+	// The key authorizes the same routes as the OAuth2 client of the role, optionally limited by the scopes,
+	// see auth.AuthorizeAPIKeys.
+	Service struct {
+		repo     apiKeyRepository
+		testMode bool
+	}
+
+	// ServiceOption is a function that configures the Service.
+	ServiceOption func(*Service)
+)
+
+// WithTestKeys allows the test-mode keys, pk_test_..., which authorize the test-mode payments.
+func WithTestKeys() ServiceOption {
+	return func(s *Service) {
+		s.testMode = true
+	}
+}
+
+// NewService creates a new API keys service.
+func NewService(repo apiKeyRepository, opts ...ServiceOption) *Service {
+	s := &Service{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// List returns all the API keys, including the revoked ones.
+func (s *Service) List(ctx context.Context) ([]APIKey, error) {
+	items, err := s.repo.GetAPIKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api keys: %w", err)
+	}
+
+	result := make([]APIKey, 0, len(items))
+	for _, k := range items {
+		result = append(result, castFromRepositoryAPIKey(k))
+	}
+
+	return result, nil
+}
+
+// Create issues a new API key of the given role and mode, limited by the scopes, if any.
+func (s *Service) Create(ctx context.Context, name, role string, livemode bool, scopes ...string) (*IssuedAPIKey, error) {
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidRequest)
+	}
+	r, err := auth.ParseRole(role)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	granted, err := auth.ParseScopes(strings.Join(scopes, " "))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	if !livemode && !s.testMode {
+		return nil, fmt.Errorf("%w: test mode is disabled", ErrInvalidRequest)
+	}
+
+	key, hash, prefix, err := auth.GenerateAPIKey(livemode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	stored, err := s.repo.CreateAPIKey(ctx, repository.CreateAPIKeyParams{
+		Name:      name,
+		KeyPrefix: prefix,
+		KeyHash:   hash,
+		Role:      string(r),
+		Livemode:  livemode,
+		Scopes:    joinScopes(granted),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store api key: %w", err)
+	}
+
+	return &IssuedAPIKey{APIKey: castFromRepositoryAPIKey(stored), Key: key}, nil
+}
+
+// Rotate replaces the key of the API key, keeping its ID, name, role and scopes.
+// The previous key keeps working for the grace period, so the clients can switch to the new one
+// without downtime; it stops working immediately if the grace period is zero.
+func (s *Service) Rotate(ctx context.Context, id uuid.UUID, gracePeriod time.Duration) (*IssuedAPIKey, error) {
+	if gracePeriod < 0 || gracePeriod > MaxGracePeriod {
+		return nil, fmt.Errorf("%w: grace period must be between 0 and %s", ErrInvalidRequest, MaxGracePeriod)
+	}
+
+	current, err := s.repo.GetAPIKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	if current.RevokedAt.Valid {
+		return nil, ErrAPIKeyNotFound
+	}
+
+	key, hash, prefix, err := auth.GenerateAPIKey(current.Livemode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	stored, err := s.repo.RotateAPIKey(ctx, repository.RotateAPIKeyParams{
+		PreviousKeyExpiresAt: sql.NullTime{Time: time.Now().Add(gracePeriod), Valid: gracePeriod > 0},
+		KeyHash:              hash,
+		KeyPrefix:            prefix,
+		ID:                   id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to rotate api key: %w", err)
+	}
+
+	return &IssuedAPIKey{APIKey: castFromRepositoryAPIKey(stored), Key: key}, nil
+}
+
+// Revoke disables the API key and its previous key, if it's still in the grace period.
+func (s *Service) Revoke(ctx context.Context, id uuid.UUID) error {
+	n, err := s.repo.RevokeAPIKey(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	if n == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// joinScopes returns the space-delimited scopes, the format of the OAuth2 scope parameter.
+func joinScopes(scopes []auth.Scope) string {
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		result = append(result, string(scope))
+	}
+	return strings.Join(result, " ")
+}
@@ -0,0 +1,131 @@
+package apikeys_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/apikeys"
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	keys map[uuid.UUID]repository.APIKey
+}
+
+func (r *repoMock) CreateAPIKey(ctx context.Context, arg repository.CreateAPIKeyParams) (repository.APIKey, error) {
+	k := repository.APIKey{
+		ID:        uuid.New(),
+		Name:      arg.Name,
+		KeyPrefix: arg.KeyPrefix,
+		KeyHash:   arg.KeyHash,
+		Role:      arg.Role,
+		Livemode:  arg.Livemode,
+		Scopes:    arg.Scopes,
+		CreatedAt: time.Now(),
+	}
+	r.keys[k.ID] = k
+	return k, nil
+}
+
+func (r *repoMock) GetAPIKey(ctx context.Context, id uuid.UUID) (repository.APIKey, error) {
+	k, ok := r.keys[id]
+	if !ok {
+		return repository.APIKey{}, sql.ErrNoRows
+	}
+	return k, nil
+}
+
+func (r *repoMock) GetAPIKeys(ctx context.Context) ([]repository.APIKey, error) {
+	result := make([]repository.APIKey, 0, len(r.keys))
+	for _, k := range r.keys {
+		result = append(result, k)
+	}
+	return result, nil
+}
+
+func (r *repoMock) RotateAPIKey(ctx context.Context, arg repository.RotateAPIKeyParams) (repository.APIKey, error) {
+	k, ok := r.keys[arg.ID]
+	if !ok || k.RevokedAt.Valid {
+		return repository.APIKey{}, sql.ErrNoRows
+	}
+	k.PreviousKeyHash = sql.NullString{String: k.KeyHash, Valid: true}
+	k.PreviousKeyExpiresAt = arg.PreviousKeyExpiresAt
+	k.KeyHash = arg.KeyHash
+	k.KeyPrefix = arg.KeyPrefix
+	k.RotatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.keys[arg.ID] = k
+	return k, nil
+}
+
+func (r *repoMock) RevokeAPIKey(ctx context.Context, id uuid.UUID) (int64, error) {
+	k, ok := r.keys[id]
+	if !ok || k.RevokedAt.Valid {
+		return 0, nil
+	}
+	k.RevokedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.keys[id] = k
+	return 1, nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoMock{keys: make(map[uuid.UUID]repository.APIKey)}
+	s := apikeys.NewService(repo)
+
+	_, err := s.Create(ctx, "shop", "admin", true)
+	require.ErrorIs(t, err, apikeys.ErrInvalidRequest)
+
+	_, err = s.Create(ctx, "", "operator", true)
+	require.ErrorIs(t, err, apikeys.ErrInvalidRequest)
+
+	_, err = s.Create(ctx, "shop", "operator", false)
+	require.ErrorIs(t, err, apikeys.ErrInvalidRequest, "test mode is disabled")
+
+	key, err := s.Create(ctx, "shop", "operator", true)
+	require.NoError(t, err)
+	require.Equal(t, "operator", key.Role)
+	require.Regexp(t, "^pk_live_", key.Key)
+	require.Equal(t, key.Key[:len(key.Prefix)], key.Prefix)
+	require.Equal(t, auth.HashAPIKey(key.Key), repo.keys[key.ID].KeyHash)
+
+	_, err = s.Rotate(ctx, key.ID, 8*24*time.Hour)
+	require.ErrorIs(t, err, apikeys.ErrInvalidRequest)
+
+	rotated, err := s.Rotate(ctx, key.ID, time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, key.ID, rotated.ID)
+	require.NotEqual(t, key.Key, rotated.Key)
+	require.NotNil(t, rotated.PreviousKeyExpiresAt)
+	require.Equal(t, auth.HashAPIKey(key.Key), repo.keys[key.ID].PreviousKeyHash.String)
+
+	list, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.NotNil(t, list[0].RotatedAt)
+
+	require.NoError(t, s.Revoke(ctx, key.ID))
+	require.ErrorIs(t, s.Revoke(ctx, key.ID), apikeys.ErrAPIKeyNotFound)
+	_, err = s.Rotate(ctx, key.ID, 0)
+	require.ErrorIs(t, err, apikeys.ErrAPIKeyNotFound)
+	_, err = s.Rotate(ctx, uuid.New(), 0)
+	require.ErrorIs(t, err, apikeys.ErrAPIKeyNotFound)
+
+	test, err := apikeys.NewService(repo, apikeys.WithTestKeys()).Create(ctx, "sandbox", "read-only", false)
+	require.NoError(t, err)
+	require.Regexp(t, "^pk_test_", test.Key)
+	require.False(t, test.Livemode)
+
+	_, err = s.Create(ctx, "reports", "operator", true, "reports:delete")
+	require.ErrorIs(t, err, apikeys.ErrInvalidRequest)
+
+	scoped, err := s.Create(ctx, "reports", "operator", true, "reports:read", "payments:read", "reports:read")
+	require.NoError(t, err)
+	require.Equal(t, []string{"reports:read", "payments:read"}, scoped.Scopes)
+	require.Equal(t, "reports:read payments:read", repo.keys[scoped.ID].Scopes)
+	require.Empty(t, key.Scopes)
+}
@@ -0,0 +1,118 @@
+package apikeys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the API key management API.
+// All the endpoints require authorization, the authMdw must allow the owners only.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/", httptransport.NewServer(
+		e.List,
+		httptransport.NopRequestDecoder,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/", httptransport.NewServer(
+		e.Create,
+		decodeCreateRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/{id}/rotate", httptransport.NewServer(
+		e.Rotate,
+		decodeRotateRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Delete("/{id}", httptransport.NewServer(
+		e.Revoke,
+		decodeRevokeRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, ErrAPIKeyNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeCreateRequest is a transport/http.DecodeRequestFunc that decodes
+// the name, the role, the mode and the scopes of the key from the request body.
+func decodeCreateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req CreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	return req, nil
+}
+
+// decodeRotateRequest is a transport/http.DecodeRequestFunc that decodes
+// the key ID from the URL path and the optional grace period from the request body.
+func decodeRotateRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req RotateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid api key id", ErrInvalidRequest)
+	}
+	req.ID = id
+
+	return req, nil
+}
+
+// decodeRevokeRequest is a transport/http.DecodeRequestFunc that decodes
+// the key ID from the URL path.
+func decodeRevokeRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid api key id", ErrInvalidRequest)
+	}
+
+	return RevokeRequest{ID: id}, nil
+}
@@ -0,0 +1,68 @@
+package apikeys
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	// APIKey is the static API key, without the key itself: only its hash is stored.
+	APIKey struct {
+		ID       uuid.UUID `json:"id"`
+		Name     string    `json:"name"`
+		Prefix   string    `json:"prefix"` // the first characters of the key, e.g. pk_live_AbCd
+		Role     string    `json:"role"`
+		Livemode bool      `json:"livemode"`
+		// Scopes limit the routes of the role the key authorizes, see auth.RequireScope;
+		// the key without scopes authorizes all the routes of the role.
+		Scopes []string `json:"scopes,omitempty"`
+		// PreviousKeyExpiresAt is the time the key replaced by the last rotation stops working.
+		PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at,omitempty"`
+		CreatedAt            time.Time  `json:"created_at"`
+		RotatedAt            *time.Time `json:"rotated_at,omitempty"`
+		RevokedAt            *time.Time `json:"revoked_at,omitempty"`
+	}
+
+	// IssuedAPIKey is the API key created or rotated, with the key itself.
+	// The key is returned once, it can't be retrieved afterwards.
+	IssuedAPIKey struct {
+		APIKey
+		Key string `json:"key"`
+	}
+
+	apiKeyRepository interface {
+		CreateAPIKey(ctx context.Context, arg repository.CreateAPIKeyParams) (repository.APIKey, error)
+		GetAPIKey(ctx context.Context, id uuid.UUID) (repository.APIKey, error)
+		GetAPIKeys(ctx context.Context) ([]repository.APIKey, error)
+		RotateAPIKey(ctx context.Context, arg repository.RotateAPIKeyParams) (repository.APIKey, error)
+		RevokeAPIKey(ctx context.Context, id uuid.UUID) (int64, error)
+	}
+)
+
+// castFromRepositoryAPIKey converts a repository API key to an API key.
+func castFromRepositoryAPIKey(k repository.APIKey) APIKey {
+	result := APIKey{
+		ID:        k.ID,
+		Name:      k.Name,
+		Prefix:    k.KeyPrefix,
+		Role:      k.Role,
+		Livemode:  k.Livemode,
+		CreatedAt: k.CreatedAt,
+		Scopes:    strings.Fields(k.Scopes),
+	}
+	if k.PreviousKeyExpiresAt.Valid && k.PreviousKeyExpiresAt.Time.After(time.Now()) {
+		result.PreviousKeyExpiresAt = &k.PreviousKeyExpiresAt.Time
+	}
+	if k.RotatedAt.Valid {
+		result.RotatedAt = &k.RotatedAt.Time
+	}
+	if k.RevokedAt.Valid {
+		result.RevokedAt = &k.RevokedAt.Time
+	}
+
+	return result
+}
@@ -2,9 +2,14 @@ package arweave
 
 import (
 	"fmt"
+	"io"
 	"math/big"
+	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/easypmnt/checkout-api/internal/httpclient"
+
 	"github.com/everFinance/goar"
 	"github.com/everFinance/goar/types"
 	"github.com/everFinance/goar/utils"
@@ -16,9 +21,13 @@ const (
 )
 
 type (
-	// Client is a wrapper for goar.Wallet
+	// Client is a wrapper for goar.Wallet.
+	// goar doesn't accept a custom HTTP client, so the requests the wrapper can make by itself,
+	// e.g. the price query, are sent by the shared outbound client, and the uploads are sent by goar.
 	Client struct {
 		ar          *goar.Wallet
+		client      *http.Client
+		nodeURL     string
 		speedFactor int64
 	}
 
@@ -29,6 +38,10 @@ type (
 // NewClient creates a new arweave client
 func NewClient(opts ...ClientOption) *Client {
 	c := &Client{
+		client: httpclient.New("arweave",
+			httpclient.WithRetries(2, httpclient.DefaultRetryWaitMin, httpclient.DefaultRetryWaitMax),
+		),
+		nodeURL:     DefaultArweaveNodeURL,
 		speedFactor: 0,
 	}
 
@@ -49,7 +62,7 @@ func NewClient(opts ...ClientOption) *Client {
 // [1] - int64 - price in Winstons
 // [2] - error
 func (c *Client) CalcPrice(data []byte) (float64, int64, error) {
-	reward, err := c.ar.Client.GetTransactionPrice(data, nil)
+	reward, err := c.getPrice(len(data))
 	if err != nil {
 		return 0, 0, fmt.Errorf("%w: %s", ErrFailedToCalcPrice, err.Error())
 	}
@@ -75,3 +88,30 @@ func (c *Client) Upload(data []byte, contentType, ext string) (string, error) {
 
 	return fmt.Sprintf("https://www.arweave.net/%s?ext=%s", tx.ID, ext), nil
 }
+
+// getPrice returns the price in Winstons of storing the given number of bytes.
+func (c *Client) getPrice(size int) (int64, error) {
+	resp, err := c.client.Get(fmt.Sprintf("%s/price/%d", c.nodeURL, size))
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, body)
+	}
+
+	reward, err := strconv.ParseInt(strings.TrimSpace(string(body)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid price: %w", err)
+	}
+	if reward <= 0 {
+		return 0, fmt.Errorf("invalid price: %d", reward)
+	}
+
+	return reward, nil
+}
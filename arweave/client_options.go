@@ -1,6 +1,11 @@
 package arweave
 
-import "github.com/everFinance/goar"
+import (
+	"net/http"
+	"strings"
+
+	"github.com/everFinance/goar"
+)
 
 // WithWalletInstance sets the wallet instance to be used for transactions.
 func WithWalletInstance(wallet *goar.Wallet) ClientOption {
@@ -44,6 +49,7 @@ func InitWalletWithPathAndNode(walletPath string, nodeURL string) ClientOption {
 		}
 
 		c.ar = wallet
+		c.nodeURL = strings.TrimRight(nodeURL, "/")
 	}
 }
 
@@ -76,6 +82,7 @@ func InitWalletWithPrivateKeyAndNode(privateKey []byte, nodeURL string) ClientOp
 		}
 
 		c.ar = wallet
+		c.nodeURL = strings.TrimRight(nodeURL, "/")
 	}
 }
 
@@ -86,3 +93,18 @@ func WithCustomSpeedFactor(speedFactor int64) ClientOption {
 		c.speedFactor = speedFactor
 	}
 }
+
+// WithHTTPClient sets the HTTP client used for the requests sent by the wrapper itself.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithNodeURL sets the node URL used for the requests sent by the wrapper itself,
+// e.g. if the wallet instance set by WithWalletInstance uses a custom node.
+func WithNodeURL(nodeURL string) ClientOption {
+	return func(c *Client) {
+		c.nodeURL = strings.TrimRight(nodeURL, "/")
+	}
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Query defaults.
+const (
+	defaultPeriod = 24 * time.Hour
+	defaultLimit  = 100
+	maxLimit      = 1000
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		ListRecords endpoint.Endpoint
+	}
+
+	// ListRecordsRequest is the request type for the ListRecords method.
+	ListRecordsRequest struct {
+		ClientID string
+		From     time.Time
+		To       time.Time
+		Limit    int
+		Offset   int
+	}
+
+	// ListRecordsResponse is the response type for the ListRecords method.
+	ListRecordsResponse struct {
+		From    time.Time `json:"from"`
+		To      time.Time `json:"to"`
+		Limit   int       `json:"limit"`
+		Offset  int       `json:"offset"`
+		Records []Record  `json:"records"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		ListRecords: makeListRecordsEndpoint(s),
+	}
+}
+
+// makeListRecordsEndpoint returns an endpoint function for the ListRecords method.
+func makeListRecordsEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ListRecordsRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		if req.To.IsZero() {
+			req.To = time.Now()
+		}
+		if req.From.IsZero() {
+			req.From = req.To.Add(-defaultPeriod)
+		}
+		if !req.From.Before(req.To) {
+			return nil, ErrInvalidPeriod
+		}
+		if req.Limit == 0 {
+			req.Limit = defaultLimit
+		}
+		if req.Limit < 0 || req.Limit > maxLimit || req.Offset < 0 {
+			return nil, ErrInvalidLimit
+		}
+
+		records, err := s.List(ctx, Filter{
+			ClientID: req.ClientID,
+			From:     req.From,
+			To:       req.To,
+			Limit:    req.Limit,
+			Offset:   req.Offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return ListRecordsResponse{
+			From:    req.From,
+			To:      req.To,
+			Limit:   req.Limit,
+			Offset:  req.Offset,
+			Records: records,
+		}, nil
+	}
+}
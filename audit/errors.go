@@ -0,0 +1,10 @@
+package audit
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrInvalidPeriod  = errors.New("invalid period")
+	ErrInvalidLimit   = errors.New("invalid limit")
+)
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"mime"
+	"net/url"
+	"strings"
+)
+
+// redacted replaces the values of the sensitive fields.
+const redacted = "[REDACTED]"
+
+// PayloadHash returns the hex-encoded SHA-256 hash of the sanitized request payload,
+// or an empty string if the payload is empty.
+// The values of the sensitive fields of the JSON and form payloads are redacted,
+// and the fields are sorted, so the same request is hashed the same way regardless of the fields order.
+// The other payloads are hashed as is.
+func PayloadHash(contentType string, payload []byte, sensitiveFields map[string]struct{}) string {
+	if len(payload) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json":
+		var v interface{}
+		if err := json.Unmarshal(payload, &v); err != nil {
+			break
+		}
+		if b, err := json.Marshal(redactJSON(v, sensitiveFields)); err == nil {
+			payload = b // the map keys are marshaled sorted
+		}
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(payload))
+		if err != nil {
+			break
+		}
+		for key := range values {
+			if isSensitive(key, sensitiveFields) {
+				values[key] = []string{redacted}
+			}
+		}
+		payload = []byte(values.Encode()) // sorted by key
+	}
+
+	return hashBytes(payload)
+}
+
+// redactJSON replaces the values of the sensitive fields of the decoded JSON payload, recursively.
+func redactJSON(v interface{}, sensitiveFields map[string]struct{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, item := range val {
+			if isSensitive(key, sensitiveFields) {
+				val[key] = redacted
+				continue
+			}
+			val[key] = redactJSON(item, sensitiveFields)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactJSON(item, sensitiveFields)
+		}
+	}
+
+	return v
+}
+
+// isSensitive reports whether the field value must be redacted.
+func isSensitive(field string, sensitiveFields map[string]struct{}) bool {
+	_, ok := sensitiveFields[strings.ToLower(field)]
+	return ok
+}
+
+// hashBytes returns the hex-encoded SHA-256 hash of the data.
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/oauth"
+)
+
+// Middleware records the call to the audit trail after it's handled.
+// It must be applied after the authorization middleware, so the client ID is known;
+// the calls without the client ID are not recorded.
+// The record is stored before the response is completed, so the call isn't lost
+// if the instance is stopped, and the failed writes are only logged.
+func (s *Service) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, _ := r.Context().Value(oauth.CredentialContext).(string)
+		if clientID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		payloadHash, err := s.readPayloadHash(r)
+		if err != nil {
+			s.log.Errorf("audit: failed to read request payload: %v", err)
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		route := r.URL.Path
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+
+		// the request context may be canceled by the client
+		ctx, cancel := context.WithTimeout(context.Background(), s.writeTimeout)
+		defer cancel()
+
+		if err := s.Add(ctx, Record{
+			ClientID:    clientID,
+			Method:      r.Method,
+			Route:       route,
+			Path:        r.URL.Path,
+			PayloadHash: payloadHash,
+			StatusCode:  status,
+			LatencyMs:   float64(time.Since(start).Microseconds()) / 1000,
+			RequestID:   middleware.GetReqID(r.Context()),
+		}); err != nil {
+			s.log.Errorf("audit: %s %s by %s: %v", r.Method, r.URL.Path, clientID, err)
+		}
+	})
+}
+
+// readPayloadHash reads the request body up to the max payload size and hashes it.
+// The body is restored, so the handler reads it as usual.
+func (s *Service) readPayloadHash(r *http.Request) (string, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return "", nil
+	}
+
+	payload, err := io.ReadAll(io.LimitReader(r.Body, s.maxPayloadSize))
+	r.Body = readCloser{
+		Reader: io.MultiReader(bytes.NewReader(payload), r.Body),
+		Closer: r.Body,
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if int64(len(payload)) == s.maxPayloadSize {
+		// the payload may be truncated, so it can't be parsed
+		return hashBytes(payload), nil
+	}
+
+	return PayloadHash(r.Header.Get("Content-Type"), payload, s.sensitiveFields), nil
+}
+
+// readCloser is the restored request body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
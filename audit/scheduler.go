@@ -0,0 +1,16 @@
+package audit
+
+import "github.com/hibiken/asynq"
+
+// Scheduler is a task scheduler for audit service.
+type Scheduler struct{}
+
+// NewScheduler creates a new task scheduler for audit service.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Schedule tasks for audit service.
+func (s *Scheduler) Schedule(scheduler *asynq.Scheduler) {
+	scheduler.Register("@every 1h", asynq.NewTask(TaskDeleteExpiredRecords, nil))
+}
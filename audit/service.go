@@ -0,0 +1,162 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+// Defaults of the audit service.
+const (
+	DefaultRetention      = 90 * 24 * time.Hour
+	DefaultMaxPayloadSize = 1 << 20 // 1 MB
+	DefaultWriteTimeout   = 5 * time.Second
+)
+
+// DefaultSensitiveFields are the payload fields redacted before hashing.
+var DefaultSensitiveFields = []string{
+	"password",
+	"secret",
+	"client_secret",
+	"token",
+	"access_token",
+	"refresh_token",
+	"api_key",
+	"private_key",
+	"mnemonic",
+	"seed",
+}
+
+type (
+	// Service records the authenticated API calls to the audit trail and queries it.
+	Service struct {
+		repo            auditRepository
+		log             Logger
+		retention       time.Duration
+		maxPayloadSize  int64
+		writeTimeout    time.Duration
+		sensitiveFields map[string]struct{}
+	}
+
+	// ServiceOption is a function that configures the audit service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new audit service.
+func NewService(repo auditRepository, log Logger, opts ...ServiceOption) *Service {
+	if log == nil {
+		panic("logger is required")
+	}
+
+	s := &Service{
+		repo:           repo,
+		log:            log,
+		retention:      DefaultRetention,
+		maxPayloadSize: DefaultMaxPayloadSize,
+		writeTimeout:   DefaultWriteTimeout,
+	}
+	WithSensitiveFields(DefaultSensitiveFields...)(s)
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithRetention sets how long the records are kept. Zero keeps them forever.
+func WithRetention(retention time.Duration) ServiceOption {
+	return func(s *Service) {
+		if retention >= 0 {
+			s.retention = retention
+		}
+	}
+}
+
+// WithMaxPayloadSize sets the max size of the request payload which is hashed.
+// The larger payloads are hashed as is, without the sanitization.
+func WithMaxPayloadSize(size int64) ServiceOption {
+	return func(s *Service) {
+		if size > 0 {
+			s.maxPayloadSize = size
+		}
+	}
+}
+
+// WithSensitiveFields replaces the payload fields redacted before hashing, case-insensitive.
+func WithSensitiveFields(fields ...string) ServiceOption {
+	return func(s *Service) {
+		s.sensitiveFields = make(map[string]struct{}, len(fields))
+		for _, f := range fields {
+			s.sensitiveFields[strings.ToLower(f)] = struct{}{}
+		}
+	}
+}
+
+// Add stores the record.
+func (s *Service) Add(ctx context.Context, r Record) error {
+	if err := s.repo.AddAuditRecord(ctx, repository.AddAuditRecordParams{
+		ClientID:    r.ClientID,
+		Method:      r.Method,
+		Route:       r.Route,
+		Path:        r.Path,
+		PayloadHash: r.PayloadHash,
+		StatusCode:  int32(r.StatusCode),
+		LatencyUs:   int64(r.LatencyMs * 1000),
+		RequestID:   r.RequestID,
+	}); err != nil {
+		return fmt.Errorf("failed to add audit record: %w", err)
+	}
+
+	return nil
+}
+
+// List returns the records created within the filter period, the newest first.
+func (s *Service) List(ctx context.Context, f Filter) ([]Record, error) {
+	rows, err := s.repo.GetAuditRecords(ctx, repository.GetAuditRecordsParams{
+		ClientID:    f.ClientID,
+		CreatedFrom: f.From,
+		CreatedTo:   f.To,
+		Limit:       int32(f.Limit),
+		Offset:      int32(f.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit records: %w", err)
+	}
+
+	result := make([]Record, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Record{
+			ID:          row.ID,
+			ClientID:    row.ClientID,
+			Method:      row.Method,
+			Route:       row.Route,
+			Path:        row.Path,
+			PayloadHash: row.PayloadHash,
+			StatusCode:  int(row.StatusCode),
+			LatencyMs:   float64(row.LatencyUs) / 1000,
+			RequestID:   row.RequestID,
+			CreatedAt:   row.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
+
+// DeleteExpired deletes the records older than the retention period.
+// Returns the number of the deleted records.
+func (s *Service) DeleteExpired(ctx context.Context) (int64, error) {
+	if s.retention == 0 {
+		return 0, nil
+	}
+
+	deleted, err := s.repo.DeleteAuditRecordsCreatedBefore(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired audit records: %w", err)
+	}
+
+	return deleted, nil
+}
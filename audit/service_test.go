@@ -0,0 +1,121 @@
+package audit_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/audit"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	records       []repository.AddAuditRecordParams
+	deletedBefore time.Time
+}
+
+func (r *repoMock) AddAuditRecord(ctx context.Context, arg repository.AddAuditRecordParams) error {
+	r.records = append(r.records, arg)
+	return nil
+}
+
+func (r *repoMock) GetAuditRecords(ctx context.Context, arg repository.GetAuditRecordsParams) ([]repository.AuditRecord, error) {
+	return nil, nil
+}
+
+func (r *repoMock) DeleteAuditRecordsCreatedBefore(ctx context.Context, createdBefore time.Time) (int64, error) {
+	r.deletedBefore = createdBefore
+	return 1, nil
+}
+
+type logMock struct{}
+
+func (logMock) Errorf(format string, args ...interface{}) {}
+
+func TestPayloadHash(t *testing.T) {
+	fields := map[string]struct{}{"client_secret": {}, "token": {}}
+
+	require.Empty(t, audit.PayloadHash("application/json", nil, fields))
+
+	// the fields order and the sensitive values don't change the hash
+	h1 := audit.PayloadHash("application/json", []byte(`{"amount":100,"client_secret":"s1","items":[{"Token":"t1"}]}`), fields)
+	h2 := audit.PayloadHash("application/json; charset=utf-8", []byte(`{"items":[{"Token":"t2"}],"client_secret":"s2","amount":100}`), fields)
+	require.Len(t, h1, 64)
+	require.Equal(t, h1, h2)
+	require.NotEqual(t, h1, audit.PayloadHash("application/json", []byte(`{"amount":200,"client_secret":"s1","items":[{"Token":"t1"}]}`), fields))
+
+	f1 := audit.PayloadHash("application/x-www-form-urlencoded", []byte("amount=100&client_secret=s1"), fields)
+	f2 := audit.PayloadHash("application/x-www-form-urlencoded", []byte("client_secret=s2&amount=100"), fields)
+	require.Equal(t, f1, f2)
+
+	// other payloads are hashed as is
+	require.NotEqual(t,
+		audit.PayloadHash("text/plain", []byte("client_secret=s1"), fields),
+		audit.PayloadHash("text/plain", []byte("client_secret=s2"), fields),
+	)
+}
+
+func TestServiceMiddleware(t *testing.T) {
+	repo := &repoMock{}
+	svc := audit.NewService(repo, logMock{})
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if client := r.Header.Get("X-Client"); client != "" {
+				r = r.WithContext(context.WithValue(r.Context(), oauth.CredentialContext, client))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}, svc.Middleware)
+	r.Post("/payment/{id}", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.Equal(t, `{"amount":100}`, string(body))
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/payment/123", strings.NewReader(`{"amount":100}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Client", "client-1")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	require.Len(t, repo.records, 1)
+	record := repo.records[0]
+	require.Equal(t, "client-1", record.ClientID)
+	require.Equal(t, http.MethodPost, record.Method)
+	require.Equal(t, "/payment/{id}", record.Route)
+	require.Equal(t, "/payment/123", record.Path)
+	require.EqualValues(t, http.StatusCreated, record.StatusCode)
+	require.Equal(t, audit.PayloadHash("application/json", []byte(`{"amount":100}`), nil), record.PayloadHash)
+
+	// the unauthenticated calls are not recorded
+	req = httptest.NewRequest(http.MethodPost, "/payment/123", strings.NewReader(`{"amount":100}`))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	require.Len(t, repo.records, 1)
+}
+
+func TestServiceDeleteExpired(t *testing.T) {
+	repo := &repoMock{}
+
+	deleted, err := audit.NewService(repo, logMock{}, audit.WithRetention(24*time.Hour)).DeleteExpired(context.Background())
+	require.NoError(t, err)
+	require.EqualValues(t, 1, deleted)
+	require.WithinDuration(t, time.Now().Add(-24*time.Hour), repo.deletedBefore, time.Minute)
+
+	// the records are kept forever
+	repo = &repoMock{}
+	deleted, err = audit.NewService(repo, logMock{}, audit.WithRetention(0)).DeleteExpired(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+	require.True(t, repo.deletedBefore.IsZero())
+}
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the audit trail query API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/", httptransport.NewServer(
+		e.ListRecords,
+		decodeListRecordsRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	if errors.Is(err, ErrInvalidRequest) || errors.Is(err, ErrInvalidPeriod) || errors.Is(err, ErrInvalidLimit) {
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeListRecordsRequest is a transport/http.DecodeRequestFunc that decodes
+// the query string: ?client_id=...&from=2023-03-01&to=2023-04-01&limit=100&offset=0.
+// All the parameters are optional, the dates accept either YYYY-MM-DD or RFC3339 format.
+func decodeListRecordsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var (
+		q   = r.URL.Query()
+		req = ListRecordsRequest{ClientID: q.Get("client_id")}
+		err error
+	)
+
+	if req.From, err = utils.ParseDate(q.Get("from")); err != nil {
+		return nil, fmt.Errorf("%w: from: %s", ErrInvalidPeriod, err.Error())
+	}
+	if req.To, err = utils.ParseDate(q.Get("to")); err != nil {
+		return nil, fmt.Errorf("%w: to: %s", ErrInvalidPeriod, err.Error())
+	}
+	if v := q.Get("limit"); v != "" {
+		if req.Limit, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidLimit, err.Error())
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if req.Offset, err = strconv.Atoi(v); err != nil {
+			return nil, fmt.Errorf("%w: offset: %s", ErrInvalidLimit, err.Error())
+		}
+	}
+
+	return req, nil
+}
@@ -0,0 +1,46 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+type (
+	// Record is the authenticated API call.
+	// The request payload is not stored, only the hash of its sanitized form,
+	// so the record proves what was sent without keeping the secrets.
+	Record struct {
+		ID          int64     `json:"id"`
+		ClientID    string    `json:"client_id"`
+		Method      string    `json:"method"`
+		Route       string    `json:"route"`
+		Path        string    `json:"path"`
+		PayloadHash string    `json:"payload_hash,omitempty"`
+		StatusCode  int       `json:"status_code"`
+		LatencyMs   float64   `json:"latency_ms"`
+		RequestID   string    `json:"request_id,omitempty"`
+		CreatedAt   time.Time `json:"created_at"`
+	}
+
+	// Filter is the audit trail query.
+	Filter struct {
+		ClientID string // all clients if empty
+		From     time.Time
+		To       time.Time
+		Limit    int
+		Offset   int
+	}
+
+	auditRepository interface {
+		AddAuditRecord(ctx context.Context, arg repository.AddAuditRecordParams) error
+		GetAuditRecords(ctx context.Context, arg repository.GetAuditRecordsParams) ([]repository.AuditRecord, error)
+		DeleteAuditRecordsCreatedBefore(ctx context.Context, createdBefore time.Time) (int64, error)
+	}
+
+	// Logger is the logger of the failed records.
+	Logger interface {
+		Errorf(format string, args ...interface{})
+	}
+)
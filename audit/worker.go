@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+// Task names.
+const (
+	TaskDeleteExpiredRecords = "delete_expired_audit_records"
+)
+
+// Worker is a task handler for the audit trail retention.
+type Worker struct {
+	svc *Service
+}
+
+// NewWorker creates a new audit task handler.
+func NewWorker(svc *Service) *Worker {
+	return &Worker{svc: svc}
+}
+
+// Register registers task handlers for the audit trail retention.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskDeleteExpiredRecords, w.DeleteExpiredRecords)
+}
+
+// DeleteExpiredRecords deletes the records older than the retention period.
+func (w *Worker) DeleteExpiredRecords(ctx context.Context, t *asynq.Task) error {
+	if _, err := w.svc.DeleteExpired(ctx); err != nil {
+		return fmt.Errorf("worker: %w", err)
+	}
+
+	return nil
+}
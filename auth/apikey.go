@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/go-chi/oauth"
+)
+
+// Prefixes of the static API keys, the mode of the key is a part of the key,
+// so the test keys can't be mistaken for the live ones.
+const (
+	APIKeyLivePrefix = "pk_live_"
+	APIKeyTestPrefix = "pk_test_"
+
+	AuthMethodAPIKey = "api_key"
+
+	// APIKeyCredentialPrefix is the prefix of the credential of the requests authorized by the API key,
+	// followed by the key ID, e.g. in the audit trail.
+	APIKeyCredentialPrefix = "api_key:"
+
+	// apiKeyDisplayLength is the length of the key prefix kept in plain text to tell the keys apart.
+	apiKeyDisplayLength = len(APIKeyLivePrefix) + 4
+)
+
+type apiKeyRepository interface {
+	GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (repository.APIKey, error)
+}
+
+// GenerateAPIKey returns a new random API key of the given mode,
+// its SHA-256 hash to store and its prefix to display.
+func GenerateAPIKey(livemode bool) (key, hash, displayPrefix string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+
+	key = APIKeyTestPrefix + base64.RawURLEncoding.EncodeToString(b)
+	if livemode {
+		key = APIKeyLivePrefix + base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	return key, HashAPIKey(key), key[:apiKeyDisplayLength], nil
+}
+
+// HashAPIKey returns the hex-encoded SHA-256 hash of the API key.
+// The keys are random, so the unsalted hash is enough to look them up without storing them.
+func HashAPIKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// IsAPIKey reports whether the bearer token is the static API key rather than the OAuth2 access token.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyLivePrefix) || strings.HasPrefix(token, APIKeyTestPrefix)
+}
+
+// AuthorizeAPIKeys returns the middleware which authorizes the requests with the static API keys,
+// "Authorization: Bearer pk_live_...", and passes the rest of the requests to the OAuth2 middleware,
+// e.g. oauth.Authorize. The request context of the API key gets the same values as the one of the client
+// credentials access token: the role and the mode claims and the scopes of the key,
+// so RequireRole, RequireScope and Livemode apply to the keys as well.
+func AuthorizeAPIKeys(repo apiKeyRepository, oauthMdw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		oauthNext := oauthMdw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := strings.TrimSpace(r.Header.Get("Authorization"))
+			if len(token) < 7 || !strings.EqualFold(token[:7], "Bearer ") || !IsAPIKey(token[7:]) {
+				oauthNext.ServeHTTP(w, r)
+				return
+			}
+
+			key, err := repo.GetActiveAPIKeyByHash(r.Context(), HashAPIKey(token[7:]))
+			if err != nil {
+				if !errors.Is(err, sql.ErrNoRows) {
+					writeError(w, ErrAPIKeyUnavailable)
+					return
+				}
+				writeError(w, ErrInvalidAPIKey)
+				return
+			}
+			// the mode of the key must match its prefix, so a leaked test key never authorizes live payments;
+			// unlike the tokens without the role claim, the keys with an unknown role don't fall back to the DefaultRole
+			role, err := ParseRole(key.Role)
+			if err != nil || key.Livemode != strings.HasPrefix(token[7:], APIKeyLivePrefix) {
+				writeError(w, ErrInvalidAPIKey)
+				return
+			}
+
+			ctx := r.Context()
+			ctx = context.WithValue(ctx, oauth.CredentialContext, APIKeyCredentialPrefix+key.ID.String())
+			ctx = context.WithValue(ctx, oauth.ClaimsContext, map[string]string{
+				LivemodeClaim:   strconv.FormatBool(key.Livemode),
+				RoleClaim:       string(role),
+				AuthMethodClaim: AuthMethodAPIKey,
+			})
+			ctx = context.WithValue(ctx, oauth.TokenTypeContext, oauth.ClientToken)
+			ctx = context.WithValue(ctx, oauth.ScopeContext, key.Scopes)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package auth_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/go-chi/oauth"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type apiKeyRepoMock map[string]repository.APIKey
+
+func (m apiKeyRepoMock) GetActiveAPIKeyByHash(_ context.Context, keyHash string) (repository.APIKey, error) {
+	key, ok := m[keyHash]
+	if !ok {
+		return repository.APIKey{}, sql.ErrNoRows
+	}
+	return key, nil
+}
+
+func TestAuthorizeAPIKeys(t *testing.T) {
+	liveKey, liveHash, prefix, err := auth.GenerateAPIKey(true)
+	require.NoError(t, err)
+	require.Equal(t, liveKey[:len(prefix)], prefix)
+	testKey, testHash, _, err := auth.GenerateAPIKey(false)
+	require.NoError(t, err)
+	require.True(t, auth.IsAPIKey(liveKey))
+	require.True(t, auth.IsAPIKey(testKey))
+
+	liveID := uuid.New()
+	repo := apiKeyRepoMock{
+		liveHash: {ID: liveID, Role: string(auth.RoleSupport), Livemode: true},
+		// the mode of the stored key doesn't match the prefix of the key
+		testHash: {ID: uuid.New(), Role: string(auth.RoleOwner), Livemode: true},
+	}
+
+	var (
+		credential string
+		role       auth.Role
+		livemode   bool
+		oauthCalls int
+	)
+	oauthMdw := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			oauthCalls++
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+	}
+	handler := auth.AuthorizeAPIKeys(repo, oauthMdw)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		credential, _ = r.Context().Value(oauth.CredentialContext).(string)
+		role = auth.RoleFromContext(r.Context())
+		livemode = auth.Livemode(r.Context())
+	}))
+
+	serve := func(authorization string) int {
+		req := httptest.NewRequest(http.MethodGet, "/payment", nil)
+		req.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("Bearer "+liveKey))
+	require.Equal(t, auth.APIKeyCredentialPrefix+liveID.String(), credential)
+	require.Equal(t, auth.RoleSupport, role)
+	require.True(t, livemode)
+
+	require.Equal(t, http.StatusUnauthorized, serve("Bearer "+testKey))
+	require.Equal(t, http.StatusUnauthorized, serve("Bearer pk_live_unknown"))
+	require.Zero(t, oauthCalls)
+
+	// the access tokens are passed to the OAuth2 middleware
+	require.Equal(t, http.StatusUnauthorized, serve("Bearer eyJhbGciOiJIUzI1NiJ9"))
+	require.Equal(t, 1, oauthCalls)
+}
+
+func TestAuthorizeAPIKeysScopes(t *testing.T) {
+	scopedKey, scopedHash, _, err := auth.GenerateAPIKey(true)
+	require.NoError(t, err)
+	fullKey, fullHash, _, err := auth.GenerateAPIKey(true)
+	require.NoError(t, err)
+
+	repo := apiKeyRepoMock{
+		scopedHash: {ID: uuid.New(), Role: string(auth.RoleOperator), Livemode: true, Scopes: "payments:read"},
+		fullHash:   {ID: uuid.New(), Role: string(auth.RoleOperator), Livemode: true},
+	}
+	oauthMdw := func(next http.Handler) http.Handler { return next }
+	handler := auth.AuthorizeAPIKeys(repo, oauthMdw)(
+		auth.RequireScope(auth.ScopePaymentsRead, auth.ScopePaymentsWrite)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})),
+	)
+
+	serve := func(method, key string) int {
+		req := httptest.NewRequest(method, "/payment", nil)
+		req.Header.Set("Authorization", "Bearer "+key)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve(http.MethodGet, scopedKey))
+	require.Equal(t, http.StatusForbidden, serve(http.MethodPost, scopedKey))
+	// the key without scopes is limited by the role only
+	require.Equal(t, http.StatusOK, serve(http.MethodPost, fullKey))
+}
@@ -10,3 +10,31 @@ var (
 	ErrPasswordNotSupported = errors.New("password grant type not supported")
 	ErrTokenExpired         = errors.New("token expired")
 )
+
+// OIDC login errors.
+var (
+	ErrOIDCLoginFailed         = errors.New("oidc login failed")
+	ErrOIDCInvalidState        = errors.New("invalid or expired oidc login state")
+	ErrOIDCInvalidIDToken      = errors.New("invalid oidc id token")
+	ErrOIDCNoRole              = errors.New("operator has no role mapped from the oidc groups")
+	ErrOIDCProviderUnavailable = errors.New("oidc provider unavailable")
+	ErrOperatorTokenNotAllowed = errors.New("operator tokens are allowed on the admin routes only")
+)
+
+// Role errors.
+var (
+	ErrInvalidRole    = errors.New("invalid role")
+	ErrRoleNotAllowed = errors.New("the role of the credential is not allowed to perform the request")
+)
+
+// Scope errors.
+var (
+	ErrInvalidScope    = errors.New("invalid scope")
+	ErrScopeNotAllowed = errors.New("the scope of the access token doesn't allow the request")
+)
+
+// API key errors.
+var (
+	ErrInvalidAPIKey     = errors.New("invalid or revoked api key")
+	ErrAPIKeyUnavailable = errors.New("api key verification is temporarily unavailable")
+)
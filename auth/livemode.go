@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/go-chi/oauth"
+)
+
+// LivemodeClaim is the access token claim with the mode of the client: "true" or "false".
+const LivemodeClaim = "livemode"
+
+// Livemode reports whether the request context is authorized by a live-mode client.
+// The tokens without the claim, e.g. issued before the test mode was added, are live-mode ones.
+func Livemode(ctx context.Context) bool {
+	claims, _ := ctx.Value(oauth.ClaimsContext).(map[string]string)
+	return claims[LivemodeClaim] != "false"
+}
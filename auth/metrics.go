@@ -0,0 +1,10 @@
+package auth
+
+import "github.com/easypmnt/checkout-api/internal/metrics"
+
+// Cleanup metrics.
+var deletedRowsTotal = metrics.NewCounterVec(
+	"checkout_cleanup_deleted_rows_total",
+	"Total number of expired rows purged by the cleanup tasks.",
+	"table",
+)
@@ -0,0 +1,428 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpclient"
+	"github.com/go-chi/oauth"
+	"github.com/google/uuid"
+)
+
+// Claims of the operator access tokens issued by the OIDC login.
+const (
+	RoleClaim       = "role"
+	EmailClaim      = "email"
+	AuthMethodClaim = "auth_method"
+
+	AuthMethodOIDC = "oidc"
+)
+
+// oidcStateCookie keeps the state, the nonce and the PKCE verifier of the login in progress.
+const oidcStateCookie = "oidc_state"
+
+type (
+	// OIDC is the OpenID Connect login of the operators to the admin API.
+	// The operator signs in with the external provider, e.g. Google or Okta, and gets the access token
+	// of the same format as the client credentials ones, with the role mapped from the provider groups.
+	// The tokens are of the oauth.UserToken type, so they're rejected by the merchant routes, see ClientTokensOnly.
+	OIDC struct {
+		issuer       string
+		clientID     string
+		clientSecret string
+		redirectURL  string
+		scopes       []string
+		groupsClaim  string
+		roleMapping  []GroupRole
+		tokenTTL     time.Duration
+		stateTTL     time.Duration
+		signingKey   []byte
+		tokens       *oauth.TokenProvider
+		client       *http.Client
+
+		mu        sync.Mutex
+		discovery *oidcDiscovery
+		keys      *jwks
+	}
+
+	// OIDCOption is a function that configures the OIDC login.
+	OIDCOption func(*OIDC)
+
+	// GroupRole maps the provider group to the operator role.
+	GroupRole struct {
+		Group string
+		Role  Role
+	}
+
+	// OperatorToken is the response of the OIDC login.
+	OperatorToken struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+		Email       string `json:"email,omitempty"`
+		Role        string `json:"role"`
+	}
+
+	// oidcDiscovery is the subset of the provider metadata used by the login.
+	oidcDiscovery struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		JWKSURI               string `json:"jwks_uri"`
+	}
+
+	// oidcState is the login in progress, signed and stored in the cookie.
+	oidcState struct {
+		State     string `json:"s"`
+		Nonce     string `json:"n"`
+		Verifier  string `json:"v"`
+		ExpiresAt int64  `json:"e"`
+	}
+)
+
+// NewOIDC creates the OIDC login. The signing key must be the one of the OAuth2 server,
+// so the issued tokens are accepted by the oauth.Authorize middleware.
+func NewOIDC(issuer, clientID, clientSecret, redirectURL, signingKey string, opts ...OIDCOption) *OIDC {
+	if issuer == "" || clientID == "" || redirectURL == "" || signingKey == "" {
+		panic("OIDC issuer, client id, redirect url and signing key are required")
+	}
+
+	o := &OIDC{
+		issuer:       strings.TrimRight(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       []string{"openid", "email", "profile"},
+		groupsClaim:  "groups",
+		tokenTTL:     8 * time.Hour,
+		stateTTL:     10 * time.Minute,
+		signingKey:   []byte(signingKey),
+		tokens:       oauth.NewTokenProvider(oauth.NewSHA256RC4TokenSecurityProvider([]byte(signingKey))),
+		client:       httpclient.New("oidc", httpclient.WithTimeout(10*time.Second)),
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// Login redirects the operator to the provider.
+func (o *OIDC) Login(w http.ResponseWriter, r *http.Request) {
+	d, err := o.getDiscovery(r.Context())
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	st := oidcState{
+		State:     randomToken(),
+		Nonce:     randomToken(),
+		Verifier:  randomToken(),
+		ExpiresAt: time.Now().Add(o.stateTTL).Unix(),
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    o.signState(st),
+		Path:     "/",
+		MaxAge:   int(o.stateTTL.Seconds()),
+		HttpOnly: true,
+		Secure:   r.TLS != nil || strings.HasPrefix(o.redirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	challenge := sha256.Sum256([]byte(st.Verifier))
+	q := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {o.clientID},
+		"redirect_uri":          {o.redirectURL},
+		"scope":                 {strings.Join(o.scopes, " ")},
+		"state":                 {st.State},
+		"nonce":                 {st.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	http.Redirect(w, r, d.AuthorizationEndpoint+"?"+q.Encode(), http.StatusFound)
+}
+
+// Callback completes the login: exchanges the code, verifies the ID token,
+// maps the groups to the role and responds with the operator access token.
+func (o *OIDC) Callback(w http.ResponseWriter, r *http.Request) {
+	if e := r.URL.Query().Get("error"); e != "" {
+		writeError(w, fmt.Errorf("%w: %s", ErrOIDCLoginFailed, e))
+		return
+	}
+
+	cookie, err := r.Cookie(oidcStateCookie)
+	if err != nil {
+		writeError(w, ErrOIDCInvalidState)
+		return
+	}
+	st, err := o.verifyState(cookie.Value)
+	if err != nil || !hmac.Equal([]byte(st.State), []byte(r.URL.Query().Get("state"))) {
+		writeError(w, ErrOIDCInvalidState)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, Path: "/", MaxAge: -1})
+
+	claims, err := o.exchange(r.Context(), r.URL.Query().Get("code"), st)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	token, err := o.issueToken(claims)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	_ = json.NewEncoder(w).Encode(token)
+}
+
+// exchange exchanges the authorization code and returns the verified claims of the ID token.
+func (o *OIDC) exchange(ctx context.Context, code string, st oidcState) (idTokenClaims, error) {
+	if code == "" {
+		return nil, fmt.Errorf("%w: missing code", ErrOIDCLoginFailed)
+	}
+
+	d, err := o.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {o.redirectURL},
+		"client_id":     {o.clientID},
+		"code_verifier": {st.Verifier},
+	}
+	if o.clientSecret != "" {
+		form.Set("client_secret", o.clientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: token request: %s", ErrOIDCLoginFailed, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: token request: unexpected status code: %d", ErrOIDCLoginFailed, resp.StatusCode)
+	}
+
+	var tokenResp struct {
+		IDToken string `json:"id_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode token response: %s", ErrOIDCLoginFailed, err)
+	}
+	if tokenResp.IDToken == "" {
+		return nil, fmt.Errorf("%w: missing id token", ErrOIDCLoginFailed)
+	}
+
+	return o.verifyIDToken(ctx, tokenResp.IDToken, st.Nonce)
+}
+
+// issueToken maps the groups of the operator to the role and issues the access token.
+func (o *OIDC) issueToken(claims idTokenClaims) (*OperatorToken, error) {
+	subject := claims.string("sub")
+	email := claims.string("email")
+	if email != "" && claims["email_verified"] == false {
+		return nil, fmt.Errorf("%w: email is not verified", ErrOIDCLoginFailed)
+	}
+
+	role := o.role(claims.strings(o.groupsClaim))
+	if role == "" {
+		return nil, ErrOIDCNoRole
+	}
+
+	credential := email
+	if credential == "" {
+		credential = subject
+	}
+
+	token := &oauth.Token{
+		ID:           uuid.New().String(),
+		CreationDate: time.Now().UTC(),
+		ExpiresIn:    o.tokenTTL,
+		Credential:   AuthMethodOIDC + ":" + credential,
+		TokenType:    oauth.UserToken,
+		Claims: map[string]string{
+			RoleClaim:       string(role),
+			EmailClaim:      email,
+			AuthMethodClaim: AuthMethodOIDC,
+			LivemodeClaim:   "true",
+		},
+	}
+
+	accessToken, err := o.tokens.CryptToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue operator token: %w", err)
+	}
+
+	return &OperatorToken{
+		AccessToken: accessToken,
+		TokenType:   string(oauth.BearerToken),
+		ExpiresIn:   int64(o.tokenTTL / time.Second),
+		Email:       email,
+		Role:        string(role),
+	}, nil
+}
+
+// role returns the role of the first mapping matching any of the groups.
+func (o *OIDC) role(groups []string) Role {
+	for _, m := range o.roleMapping {
+		for _, g := range groups {
+			if g == m.Group {
+				return m.Role
+			}
+		}
+	}
+	return ""
+}
+
+// getDiscovery returns the provider metadata, fetching it on the first call.
+func (o *OIDC) getDiscovery(ctx context.Context) (*oidcDiscovery, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.discovery != nil {
+		return o.discovery, nil
+	}
+
+	var d oidcDiscovery
+	if err := o.getJSON(ctx, o.issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, fmt.Errorf("%w: discovery: %s", ErrOIDCProviderUnavailable, err)
+	}
+	if strings.TrimRight(d.Issuer, "/") != o.issuer {
+		return nil, fmt.Errorf("%w: discovery: issuer mismatch: %s", ErrOIDCProviderUnavailable, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: discovery: missing endpoints", ErrOIDCProviderUnavailable)
+	}
+	o.discovery = &d
+
+	return o.discovery, nil
+}
+
+// getJSON fetches the JSON document.
+func (o *OIDC) getJSON(ctx context.Context, uri string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// signState returns the signed state of the cookie.
+func (o *OIDC) signState(st oidcState) string {
+	b, _ := json.Marshal(st)
+	payload := base64.RawURLEncoding.EncodeToString(b)
+	return payload + "." + o.sign(payload)
+}
+
+// verifyState verifies the signature and the expiration of the state.
+func (o *OIDC) verifyState(value string) (oidcState, error) {
+	var st oidcState
+
+	payload, sig, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(o.sign(payload))) {
+		return st, ErrOIDCInvalidState
+	}
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return st, ErrOIDCInvalidState
+	}
+	if err := json.Unmarshal(b, &st); err != nil {
+		return st, ErrOIDCInvalidState
+	}
+	if time.Now().Unix() > st.ExpiresAt {
+		return st, ErrOIDCInvalidState
+	}
+
+	return st, nil
+}
+
+// randomToken returns the random URL-safe token of 256 bits, e.g. the state or the PKCE verifier.
+func randomToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // the system random source is broken
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func (o *OIDC) sign(payload string) string {
+	mac := hmac.New(sha256.New, o.signingKey)
+	mac.Write([]byte("oidc-state:" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// oidcError writes the error response of the login.
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusUnauthorized
+	switch {
+	case errors.Is(err, ErrOIDCNoRole), errors.Is(err, ErrOperatorTokenNotAllowed), errors.Is(err, ErrRoleNotAllowed),
+		errors.Is(err, ErrScopeNotAllowed):
+		code = http.StatusForbidden
+	case errors.Is(err, ErrOIDCProviderUnavailable):
+		code = http.StatusBadGateway
+	case errors.Is(err, ErrAPIKeyUnavailable):
+		code = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":  code,
+		"error": err.Error(),
+	})
+}
+
+// ClientTokensOnly is the middleware which rejects the operator tokens issued by the OIDC login,
+// so they're accepted by the admin routes only. It must be used after the oauth.Authorize middleware.
+func ClientTokensOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if tt, _ := r.Context().Value(oauth.TokenTypeContext).(oauth.TokenType); tt == oauth.UserToken {
+			writeError(w, ErrOperatorTokenNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+)
+
+// WithOIDCScopes sets the scopes requested from the provider. Default: openid, email, profile.
+func WithOIDCScopes(scopes ...string) OIDCOption {
+	return func(o *OIDC) {
+		if len(scopes) > 0 {
+			o.scopes = scopes
+		}
+	}
+}
+
+// WithOIDCGroupsClaim sets the ID token claim with the groups of the operator. Default: groups.
+func WithOIDCGroupsClaim(claim string) OIDCOption {
+	return func(o *OIDC) {
+		if claim != "" {
+			o.groupsClaim = claim
+		}
+	}
+}
+
+// WithOIDCRoleMapping sets the mapping of the provider groups to the operator roles.
+// The first mapping matching any group of the operator wins, so the most privileged ones go first.
+// The operators without any mapped group can't log in.
+func WithOIDCRoleMapping(mapping ...GroupRole) OIDCOption {
+	return func(o *OIDC) {
+		o.roleMapping = mapping
+	}
+}
+
+// WithOIDCTokenTTL sets the TTL of the operator access tokens. Default: 8 hours.
+func WithOIDCTokenTTL(ttl time.Duration) OIDCOption {
+	return func(o *OIDC) {
+		if ttl > 0 {
+			o.tokenTTL = ttl
+		}
+	}
+}
+
+// WithOIDCHTTPClient sets the HTTP client of the provider requests.
+func WithOIDCHTTPClient(client *http.Client) OIDCOption {
+	return func(o *OIDC) {
+		o.client = client
+	}
+}
@@ -0,0 +1,172 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/go-chi/oauth"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSigningKey = "test-signing-key"
+	testClientID   = "checkout-admin"
+)
+
+// fakeProvider is the minimal OIDC provider issuing the ID tokens with the given groups.
+type fakeProvider struct {
+	*httptest.Server
+	key    *rsa.PrivateKey
+	nonce  string
+	groups []string
+}
+
+func newFakeProvider(t *testing.T) *fakeProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	p := &fakeProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 p.URL,
+			"authorization_endpoint": p.URL + "/authorize",
+			"token_endpoint":         p.URL + "/token",
+			"jwks_uri":               p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "1",
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "valid-code", r.FormValue("code"))
+		require.NotEmpty(t, r.FormValue("code_verifier"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": p.idToken(t)})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+
+	return p
+}
+
+func (p *fakeProvider) idToken(t *testing.T) string {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": "1"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":            p.URL,
+		"aud":            testClientID,
+		"sub":            "operator-1",
+		"email":          "operator@example.com",
+		"email_verified": true,
+		"groups":         p.groups,
+		"nonce":          p.nonce,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Minute).Unix(),
+	})
+	payload := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// login runs the login flow and returns the callback response.
+func login(t *testing.T, p *fakeProvider, o *auth.OIDC) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	o.Login(w, httptest.NewRequest(http.MethodGet, "/oauth/oidc/login", nil))
+	require.Equal(t, http.StatusFound, w.Code)
+
+	redirect, err := url.Parse(w.Header().Get("Location"))
+	require.NoError(t, err)
+	require.Equal(t, "S256", redirect.Query().Get("code_challenge_method"))
+	p.nonce = redirect.Query().Get("nonce")
+
+	req := httptest.NewRequest(http.MethodGet, "/oauth/oidc/callback?code=valid-code&state="+url.QueryEscape(redirect.Query().Get("state")), nil)
+	for _, c := range w.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	w = httptest.NewRecorder()
+	o.Callback(w, req)
+
+	return w
+}
+
+func newOIDC(p *fakeProvider) *auth.OIDC {
+	return auth.NewOIDC(p.URL, testClientID, "secret", "https://api.example.com/oauth/oidc/callback", testSigningKey,
+		auth.WithOIDCRoleMapping(
+			auth.GroupRole{Group: "checkout-admins", Role: "owner"},
+			auth.GroupRole{Group: "checkout-support", Role: "support"},
+		),
+	)
+}
+
+func TestOIDCLogin(t *testing.T) {
+	p := newFakeProvider(t)
+	p.groups = []string{"everyone", "checkout-support", "checkout-admins"}
+
+	w := login(t, p, newOIDC(p))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp auth.OperatorToken
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "owner", resp.Role)
+	require.Equal(t, "operator@example.com", resp.Email)
+
+	// the token is accepted by the oauth middleware of the admin routes
+	token, err := oauth.NewTokenProvider(oauth.NewSHA256RC4TokenSecurityProvider([]byte(testSigningKey))).DecryptToken(resp.AccessToken)
+	require.NoError(t, err)
+	require.Equal(t, "oidc:operator@example.com", token.Credential)
+	require.Equal(t, oauth.UserToken, token.TokenType)
+	require.Equal(t, "owner", token.Claims[auth.RoleClaim])
+}
+
+func TestOIDCLoginWithoutRole(t *testing.T) {
+	p := newFakeProvider(t)
+	p.groups = []string{"everyone"}
+
+	w := login(t, p, newOIDC(p))
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestOIDCCallbackInvalidState(t *testing.T) {
+	p := newFakeProvider(t)
+	o := newOIDC(p)
+
+	w := httptest.NewRecorder()
+	o.Callback(w, httptest.NewRequest(http.MethodGet, "/oauth/oidc/callback?code=valid-code&state=forged", nil))
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestClientTokensOnly(t *testing.T) {
+	p := newFakeProvider(t)
+	p.groups = []string{"checkout-admins"}
+
+	var resp auth.OperatorToken
+	require.NoError(t, json.NewDecoder(login(t, p, newOIDC(p)).Body).Decode(&resp))
+
+	handler := oauth.Authorize(testSigningKey, nil)(auth.ClientTokensOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	req := httptest.NewRequest(http.MethodGet, "/payment", nil)
+	req.Header.Set("Authorization", "Bearer "+resp.AccessToken)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+}
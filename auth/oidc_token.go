@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// jwksRefreshInterval limits the refetching of the provider keys on the unknown key ID.
+const jwksRefreshInterval = time.Minute
+
+// clockSkew is the allowed difference between the provider and the API clocks.
+const clockSkew = time.Minute
+
+type (
+	// idTokenClaims are the claims of the verified ID token.
+	idTokenClaims map[string]interface{}
+
+	// jwks is the cached key set of the provider.
+	jwks struct {
+		keys      map[string]*rsa.PublicKey
+		fetchedAt time.Time
+	}
+
+	jwk struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	}
+)
+
+// verifyIDToken verifies the signature, the issuer, the audience, the expiration
+// and the nonce of the ID token and returns its claims. Only the RS256 tokens are supported,
+// which is the algorithm every OIDC provider must support.
+func (o *OIDC) verifyIDToken(ctx context.Context, raw, nonce string) (idTokenClaims, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed id token", ErrOIDCInvalidIDToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrOIDCInvalidIDToken)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unsupported algorithm: %s", ErrOIDCInvalidIDToken, header.Alg)
+	}
+
+	key, err := o.publicKey(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrOIDCInvalidIDToken)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+		return nil, fmt.Errorf("%w: invalid signature", ErrOIDCInvalidIDToken)
+	}
+
+	var claims idTokenClaims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrOIDCInvalidIDToken)
+	}
+
+	now := time.Now()
+	switch {
+	case strings.TrimRight(claims.string("iss"), "/") != o.issuer:
+		return nil, fmt.Errorf("%w: issuer mismatch", ErrOIDCInvalidIDToken)
+	case !claims.hasAudience(o.clientID):
+		return nil, fmt.Errorf("%w: audience mismatch", ErrOIDCInvalidIDToken)
+	case claims.time("exp").Add(clockSkew).Before(now):
+		return nil, fmt.Errorf("%w: token expired", ErrOIDCInvalidIDToken)
+	case claims.time("iat").Add(-clockSkew).After(now):
+		return nil, fmt.Errorf("%w: token issued in the future", ErrOIDCInvalidIDToken)
+	case claims.string("nonce") != nonce:
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrOIDCInvalidIDToken)
+	}
+
+	return claims, nil
+}
+
+// publicKey returns the provider key with the given ID. The keys are refetched
+// if the ID is unknown, e.g. after the key rotation, but not more often than jwksRefreshInterval.
+func (o *OIDC) publicKey(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	d, err := o.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.keys != nil {
+		if key, ok := o.keys.get(kid); ok {
+			return key, nil
+		}
+		if time.Since(o.keys.fetchedAt) < jwksRefreshInterval {
+			return nil, fmt.Errorf("%w: unknown key: %s", ErrOIDCInvalidIDToken, kid)
+		}
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := o.getJSON(ctx, d.JWKSURI, &set); err != nil {
+		return nil, fmt.Errorf("%w: keys: %s", ErrOIDCProviderUnavailable, err)
+	}
+
+	keys := &jwks{keys: make(map[string]*rsa.PublicKey, len(set.Keys)), fetchedAt: time.Now()}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		if key, err := k.rsaPublicKey(); err == nil {
+			keys.keys[k.Kid] = key
+		}
+	}
+	o.keys = keys
+
+	key, ok := o.keys.get(kid)
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key: %s", ErrOIDCInvalidIDToken, kid)
+	}
+
+	return key, nil
+}
+
+// get returns the key by the ID, or the only key if the token has no key ID.
+func (s *jwks) get(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// rsaPublicKey decodes the RSA public key.
+func (k jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, err
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, err
+	}
+	if len(e) == 0 || len(e) > 4 {
+		return nil, fmt.Errorf("invalid exponent")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(new(big.Int).SetBytes(e).Int64()),
+	}, nil
+}
+
+// decodeSegment decodes the base64url encoded JSON segment of the token.
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// string returns the string claim.
+func (c idTokenClaims) string(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// strings returns the string list claim, e.g. the groups.
+// The single string is treated as the list of one item.
+func (c idTokenClaims) strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// time returns the numeric date claim.
+func (c idTokenClaims) time(name string) time.Time {
+	f, _ := c[name].(float64)
+	return time.Unix(int64(f), 0)
+}
+
+// hasAudience reports whether the token is issued to the client.
+func (c idTokenClaims) hasAudience(clientID string) bool {
+	for _, aud := range c.strings("aud") {
+		if aud == clientID {
+			return true
+		}
+	}
+	return false
+}
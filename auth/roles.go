@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/oauth"
+)
+
+// Role is the role of the API credential or of the operator signed in with the OIDC login.
+type Role string
+
+// Predefined roles, from the most to the least privileged.
+const (
+	RoleOwner    Role = "owner"     // everything, including the configuration and the role management
+	RoleOperator Role = "operator"  // payment mutations, refunds and escrow releases, treasury
+	RoleSupport  Role = "support"   // reads, disputes and transaction rechecks
+	RoleReadOnly Role = "read-only" // reads only
+)
+
+// DefaultRole is the role of the credentials without the assigned role,
+// so the existing clients keep the full access until they're assigned a narrower one.
+const DefaultRole = RoleOwner
+
+// Roles are all the predefined roles.
+var Roles = []Role{RoleOwner, RoleOperator, RoleSupport, RoleReadOnly}
+
+// ParseRole returns the role by its name.
+func ParseRole(s string) (Role, error) {
+	for _, r := range Roles {
+		if string(r) == s {
+			return r, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrInvalidRole, s)
+}
+
+// RoleFromContext returns the role of the request authorized by the oauth.Authorize middleware.
+// The tokens without the claim, e.g. issued before the roles were added, have the DefaultRole.
+func RoleFromContext(ctx context.Context) Role {
+	claims, _ := ctx.Value(oauth.ClaimsContext).(map[string]string)
+	if role, err := ParseRole(claims[RoleClaim]); err == nil {
+		return role
+	}
+	return DefaultRole
+}
+
+// RequireRole returns the middleware which allows the request only if the role of the credential
+// is one of the given ones. It must be used after the oauth.Authorize middleware.
+func RequireRole(roles ...Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasRole(RoleFromContext(r.Context()), roles) {
+				writeError(w, ErrRoleNotAllowed)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRoleForWrites is RequireRole for the mutations only: the safe methods (GET, HEAD, OPTIONS)
+// are allowed for any role, so e.g. the read-only credentials can read everything they're authorized to.
+func RequireRoleForWrites(roles ...Role) func(http.Handler) http.Handler {
+	require := RequireRole(roles...)
+	return func(next http.Handler) http.Handler {
+		guarded := require(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				next.ServeHTTP(w, r)
+			default:
+				guarded.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+func hasRole(role Role, roles []Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
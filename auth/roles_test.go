@@ -0,0 +1,40 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/go-chi/oauth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireRoleForWrites(t *testing.T) {
+	handler := auth.RequireRoleForWrites(auth.RoleOwner, auth.RoleOperator)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		claims map[string]string
+		code   int
+	}{
+		{"read by read-only", http.MethodGet, map[string]string{auth.RoleClaim: "read-only"}, http.StatusOK},
+		{"write by read-only", http.MethodPost, map[string]string{auth.RoleClaim: "read-only"}, http.StatusForbidden},
+		{"write by support", http.MethodDelete, map[string]string{auth.RoleClaim: "support"}, http.StatusForbidden},
+		{"write by operator", http.MethodPost, map[string]string{auth.RoleClaim: "operator"}, http.StatusOK},
+		{"write without role claim", http.MethodPost, map[string]string{}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/payment", nil)
+			req = req.WithContext(context.WithValue(req.Context(), oauth.ClaimsContext, tt.claims))
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tt.code, w.Code)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package auth
+
+import "github.com/hibiken/asynq"
+
+// Scheduler is a task scheduler for auth service.
+type Scheduler struct{}
+
+// NewScheduler creates a new task scheduler for auth service.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Schedule tasks for auth service.
+func (s *Scheduler) Schedule(scheduler *asynq.Scheduler) {
+	scheduler.Register("@every 1h", asynq.NewTask(TaskDeleteExpiredTokens, nil))
+}
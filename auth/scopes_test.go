@@ -0,0 +1,53 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/go-chi/oauth"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := auth.ParseScopes(" payments:read  reports:read payments:read reports:write")
+	require.NoError(t, err)
+	require.Equal(t, []auth.Scope{auth.ScopePaymentsRead, auth.ScopeReportsRead, auth.ScopeReportsWrite}, scopes)
+
+	_, err = auth.ParseScopes("payments:read payments:delete")
+	require.ErrorIs(t, err, auth.ErrInvalidScope)
+}
+
+func TestRequireScope(t *testing.T) {
+	handler := auth.RequireScope(auth.ScopePaymentsRead, auth.ScopePaymentsWrite)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	tests := []struct {
+		name   string
+		method string
+		scope  interface{}
+		code   int
+	}{
+		{"read with read scope", http.MethodGet, "payments:read", http.StatusOK},
+		{"read with write scope", http.MethodGet, "payments:write", http.StatusOK},
+		{"write with read scope", http.MethodPost, "payments:read reports:read", http.StatusForbidden},
+		{"write with write scope", http.MethodPost, "reports:read payments:write", http.StatusOK},
+		{"read with another scope", http.MethodGet, "webhooks:manage", http.StatusForbidden},
+		{"write without scope", http.MethodPost, "", http.StatusOK},
+		{"write without scope context", http.MethodDelete, nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/payment", nil)
+			if tt.scope != nil {
+				req = req.WithContext(context.WithValue(req.Context(), oauth.ScopeContext, tt.scope))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			require.Equal(t, tt.code, w.Code)
+		})
+	}
+}
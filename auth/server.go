@@ -10,15 +10,20 @@ import (
 
 // Set up limited oauth2 server for client_credentials, and refresh_token flows.
 // Does not support password, authorization code flow.
-func NewOAuth2Server(singingKey string, ttl time.Duration, verifier oauth.CredentialsVerifier) *oauth.BearerServer {
+// The clients may request the given scopes, all the predefined Scopes if none are given;
+// the tokens issued without the scope parameter are not limited by the scopes, see RequireScope.
+func NewOAuth2Server(singingKey string, ttl time.Duration, verifier oauth.CredentialsVerifier, scopes ...Scope) *oauth.BearerServer {
 	if ttl == 0 {
 		ttl = time.Hour
 	}
 	if verifier == nil {
 		panic("Credentials verifier is not set")
 	}
+	if len(scopes) == 0 {
+		scopes = Scopes
+	}
 
-	return oauth.NewBearerServer(singingKey, ttl, verifier, nil)
+	return oauth.NewBearerServer(singingKey, ttl, scopedVerifier{CredentialsVerifier: verifier, scopes: scopes}, nil)
 }
 
 // MakeHTTPHandler returns an http.Handler that can be used to serve the OAuth2 API.
@@ -30,3 +35,11 @@ func MakeHTTPHandler(oauthSvc interface {
 	r.Post("/token", oauthSvc.ClientCredentials)
 	return r
 }
+
+// MakeOIDCHTTPHandler returns an http.Handler that can be used to serve the OIDC login of the operators.
+func MakeOIDCHTTPHandler(o *OIDC) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/login", o.Login)
+	r.Get("/callback", o.Callback)
+	return r
+}
@@ -2,8 +2,11 @@ package auth
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/easypmnt/checkout-api/repository"
@@ -20,6 +23,8 @@ type (
 
 		clientID         string
 		clientSecretHash string // bcrypt hash of the client secret, used for comparison.
+		testClientID     string // optional client of the test-mode payments, see WithTestClient.
+		testSecretHash   string
 		accessTokenTTL   time.Duration
 		refreshTokenTTL  time.Duration
 	}
@@ -30,6 +35,7 @@ type (
 	verifierRepository interface {
 		GetToken(ctx context.Context, arg repository.GetTokenParams) (repository.Token, error)
 		StoreToken(ctx context.Context, arg repository.StoreTokenParams) (repository.Token, error)
+		GetCredentialRole(ctx context.Context, credential string) (repository.CredentialRole, error)
 	}
 )
 
@@ -61,18 +67,49 @@ func (v *Verifier) ValidateUser(username, password, scope string, r *http.Reques
 
 // Validate clientID and secret returning an error if the client credentials are wrong
 func (v *Verifier) ValidateClient(clientID, clientSecret, _ string, r *http.Request) error {
-	if clientID != v.clientID {
+	secretHash := v.clientSecretHash
+	switch {
+	case clientID == v.clientID:
+	case v.testClientID != "" && clientID == v.testClientID:
+		secretHash = v.testSecretHash
+	default:
 		return ErrInvalidCredentials
 	}
-	if bcrypt.CompareHashAndPassword([]byte(v.clientSecretHash), []byte(clientSecret)) != nil {
+	if bcrypt.CompareHashAndPassword([]byte(secretHash), []byte(clientSecret)) != nil {
 		return ErrInvalidCredentials
 	}
 	return nil
 }
 
 // Provide additional claims to the token
+// The role claim is looked up on every token issuance, including the refresh,
+// so the role changes apply to the credential within the access token TTL.
 func (v *Verifier) AddClaims(tokenType oauth.TokenType, credential, tokenID, scope string, r *http.Request) (map[string]string, error) {
-	return nil, nil
+	role, err := v.credentialRole(credential)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		LivemodeClaim: strconv.FormatBool(v.testClientID == "" || credential != v.testClientID),
+		RoleClaim:     string(role),
+	}, nil
+}
+
+// credentialRole returns the role assigned to the credential, or the DefaultRole if there is none.
+func (v *Verifier) credentialRole(credential string) (Role, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cr, err := v.repo.GetCredentialRole(ctx, credential)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DefaultRole, nil
+		}
+		return "", fmt.Errorf("failed to get credential role: %w", err)
+	}
+
+	return ParseRole(cr.Role)
 }
 
 // Provide additional information to the authorization server response
@@ -19,3 +19,14 @@ func WithRefreshTokenTTL(ttl time.Duration) VarifierOption {
 		}
 	}
 }
+
+// WithTestClient adds the client of the test-mode payments.
+// The tokens issued to the client have the livemode claim set to false, see Livemode.
+func WithTestClient(clientID, clientSecretHash string) VarifierOption {
+	return func(v *Verifier) {
+		if clientID != "" && clientSecretHash != "" {
+			v.testClientID = clientID
+			v.testSecretHash = clientSecretHash
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+// Task names.
+const (
+	TaskDeleteExpiredTokens = "delete_expired_tokens"
+)
+
+type (
+	// Worker is a task handler for the auth storage cleanup.
+	Worker struct {
+		repo workerRepository
+	}
+
+	workerRepository interface {
+		DeleteExpiredTokens(ctx context.Context) (int64, error)
+	}
+)
+
+// NewWorker creates a new auth task handler.
+func NewWorker(repo workerRepository) *Worker {
+	return &Worker{repo: repo}
+}
+
+// Register registers task handlers for the auth storage cleanup.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskDeleteExpiredTokens, w.DeleteExpiredTokens)
+}
+
+// DeleteExpiredTokens purges the tokens which can't be refreshed anymore,
+// so the tokens table doesn't grow with every issued token and the lookups stay fast.
+func (w *Worker) DeleteExpiredTokens(ctx context.Context, t *asynq.Task) error {
+	deleted, err := w.repo.DeleteExpiredTokens(ctx)
+	if err != nil {
+		return fmt.Errorf("worker: failed to delete expired tokens: %w", err)
+	}
+	deletedRowsTotal.WithLabelValues("tokens").Add(float64(deleted))
+
+	return nil
+}
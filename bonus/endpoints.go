@@ -0,0 +1,88 @@
+package bonus
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		GetAccount endpoint.Endpoint
+		Freeze     endpoint.Endpoint
+		Thaw       endpoint.Endpoint
+	}
+
+	// GetAccountRequest is the request type for the GetAccount method.
+	GetAccountRequest struct {
+		Wallet string `json:"-" validate:"required|solanaWallet"`
+	}
+
+	// FreezeRequest is the request type for the Freeze and Thaw methods.
+	FreezeRequest struct {
+		Wallet      string `json:"-" validate:"required|solanaWallet"`
+		Reason      string `json:"reason" validate:"required|max_len:500"`
+		RequestedBy string `json:"-"`
+	}
+
+	// AccountResponse is the response type for the GetAccount, Freeze and Thaw methods.
+	AccountResponse struct {
+		Account *Account `json:"account"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		GetAccount: makeGetAccountEndpoint(s),
+		Freeze:     makeFreezeEndpoint(s.Freeze),
+		Thaw:       makeFreezeEndpoint(s.Thaw),
+	}
+}
+
+// makeGetAccountEndpoint returns an endpoint function for the GetAccount method.
+func makeGetAccountEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetAccountRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		account, err := s.GetAccount(ctx, req.Wallet)
+		if err != nil {
+			return nil, err
+		}
+
+		return AccountResponse{Account: account}, nil
+	}
+}
+
+// makeFreezeEndpoint returns an endpoint function for the Freeze or Thaw method.
+func makeFreezeEndpoint(fn func(context.Context, FreezeParams) (*Account, error)) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(FreezeRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		account, err := fn(ctx, FreezeParams{
+			Wallet:      req.Wallet,
+			Reason:      req.Reason,
+			RequestedBy: req.RequestedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return AccountResponse{Account: account}, nil
+	}
+}
@@ -0,0 +1,12 @@
+package bonus
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest            = errors.New("invalid_request")
+	ErrReasonRequired            = errors.New("reason_required")
+	ErrTokenAccountNotFound      = errors.New("token_account_not_found")
+	ErrTokenAccountAlreadyFrozen = errors.New("token_account_already_frozen")
+	ErrTokenAccountNotFrozen     = errors.New("token_account_not_frozen")
+)
@@ -0,0 +1,132 @@
+package bonus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// Service freezes and thaws the customer bonus token accounts,
+// e.g. to stop a fraudulent wallet from spending or receiving bonus tokens.
+type Service struct {
+	sol       solanaClient
+	mint      string
+	authority solana.Signer
+	log       Logger
+}
+
+// NewService creates a new bonus service for the given bonus mint.
+// The authority must be the freeze authority of the mint, it also pays the transaction fees.
+func NewService(sol solanaClient, mint string, authority solana.Signer, log Logger) *Service {
+	if mint == "" {
+		panic("bonus mint address is required")
+	}
+	if authority == nil {
+		panic("bonus freeze authority is required")
+	}
+	if log == nil {
+		panic("logger is required")
+	}
+
+	return &Service{
+		sol:       sol,
+		mint:      mint,
+		authority: authority,
+		log:       log,
+	}
+}
+
+// GetAccount returns the state of the bonus token account of the given wallet.
+func (s *Service) GetAccount(ctx context.Context, wallet string) (*Account, error) {
+	ata, err := solana.AssociatedTokenAddress(wallet, s.mint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find bonus token account: %w", err)
+	}
+
+	frozen, err := s.sol.IsTokenAccountFrozen(ctx, wallet, s.mint)
+	if err != nil {
+		if errors.Is(err, solana.ErrTokenAccountDoesNotExist) {
+			return nil, ErrTokenAccountNotFound
+		}
+		return nil, fmt.Errorf("failed to get bonus token account: %w", err)
+	}
+
+	return &Account{
+		Wallet:       wallet,
+		TokenAccount: ata,
+		Frozen:       frozen,
+	}, nil
+}
+
+// Freeze freezes the bonus token account of the given wallet.
+// The frozen account can't spend or receive bonus tokens until it's thawed.
+func (s *Service) Freeze(ctx context.Context, params FreezeParams) (*Account, error) {
+	return s.apply(ctx, "freeze", params, true)
+}
+
+// Thaw thaws the frozen bonus token account of the given wallet.
+func (s *Service) Thaw(ctx context.Context, params FreezeParams) (*Account, error) {
+	return s.apply(ctx, "thaw", params, false)
+}
+
+// apply freezes or thaws the bonus token account and writes the audit log.
+func (s *Service) apply(ctx context.Context, op string, params FreezeParams, freeze bool) (*Account, error) {
+	s.log.Infof("bonus: %s of %s requested by %s: %s", op, params.Wallet, params.RequestedBy, params.Reason)
+
+	result, err := s.setFrozen(ctx, params, freeze)
+	if err != nil {
+		s.log.Errorf("bonus: %s of %s requested by %s failed: %v", op, params.Wallet, params.RequestedBy, err)
+		return nil, err
+	}
+
+	s.log.Infof("bonus: %s of %s requested by %s sent: %s", op, params.Wallet, params.RequestedBy, result.Signature)
+
+	return result, nil
+}
+
+func (s *Service) setFrozen(ctx context.Context, params FreezeParams, freeze bool) (*Account, error) {
+	if strings.TrimSpace(params.Reason) == "" {
+		return nil, ErrReasonRequired
+	}
+
+	account, err := s.GetAccount(ctx, params.Wallet)
+	if err != nil {
+		return nil, err
+	}
+	if freeze && account.Frozen {
+		return nil, ErrTokenAccountAlreadyFrozen
+	}
+	if !freeze && !account.Frozen {
+		return nil, ErrTokenAccountNotFrozen
+	}
+
+	instruction := solana.ThawTokenAccount
+	if freeze {
+		instruction = solana.FreezeTokenAccount
+	}
+
+	authority := s.authority.PublicKey().ToBase58()
+	tx, err := solana.NewTransactionBuilder(s.sol).
+		SetFeePayer(authority).
+		AddInstruction(instruction(solana.FreezeTokenAccountParams{
+			Mint:      s.mint,
+			Owner:     params.Wallet,
+			Authority: authority,
+		})).
+		AddExternalSigner(s.authority).
+		Build(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transaction: %w", err)
+	}
+
+	account.Signature, err = s.sol.SendTransaction(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+	account.Frozen = freeze
+
+	return account, nil
+}
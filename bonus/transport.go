@@ -0,0 +1,99 @@
+package bonus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the bonus accounts API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/accounts/{wallet}", httptransport.NewServer(
+		e.GetAccount,
+		decodeGetAccountRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/accounts/{wallet}/freeze", httptransport.NewServer(
+		e.Freeze,
+		decodeFreezeRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/accounts/{wallet}/thaw", httptransport.NewServer(
+		e.Thaw,
+		decodeFreezeRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		return http.StatusPreconditionFailed, err
+	case errors.Is(err, ErrTokenAccountNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrTokenAccountAlreadyFrozen),
+		errors.Is(err, ErrTokenAccountNotFrozen):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, ErrInvalidRequest),
+		errors.Is(err, ErrReasonRequired):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeGetAccountRequest is a transport/http.DecodeRequestFunc that decodes
+// the wallet address from the URL path.
+func decodeGetAccountRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return GetAccountRequest{Wallet: chi.URLParam(r, "wallet")}, nil
+}
+
+// decodeFreezeRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeFreezeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req FreezeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+	req.Wallet = chi.URLParam(r, "wallet")
+
+	// OAuth2 client credentials, set by the auth middleware
+	req.RequestedBy, _ = ctx.Value(oauth.CredentialContext).(string)
+
+	return req, nil
+}
@@ -0,0 +1,36 @@
+package bonus
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// FreezeParams defines the parameters of a freeze or thaw of a customer bonus token account.
+	FreezeParams struct {
+		Wallet      string // base58 encoded customer wallet address.
+		Reason      string // reason of the operation, e.g. fraud case id, for the audit log.
+		RequestedBy string // client which requested the operation, for the audit log.
+	}
+
+	// Account is the state of a customer bonus token account.
+	Account struct {
+		Wallet       string `json:"wallet"`
+		TokenAccount string `json:"token_account"` // associated token account of the bonus mint.
+		Frozen       bool   `json:"frozen"`
+		Signature    string `json:"signature,omitempty"` // signature of the sent freeze or thaw transaction.
+	}
+
+	solanaClient interface {
+		solana.SolanaClient
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+		IsTokenAccountFrozen(ctx context.Context, base58Addr, base58MintAddr string) (bool, error)
+	}
+
+	// Logger is used for the freeze and thaw audit log.
+	Logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
@@ -0,0 +1,198 @@
+package checkouttest
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// Test wallet addresses.
+const (
+	MerchantWallet = "3BgUdGYPPaw3k7KfhgJVT9HAvMDdhHraEjqnS6FJoqXt"
+	CustomerWallet = "61C89STBD3pBibwAA2ffWwviAcBSdnFFLbep2N3tHWNX"
+)
+
+type (
+	// PaymentOption configures the payment built by NewPayment.
+	PaymentOption func(*payments.Payment)
+
+	// TransactionOption configures the transaction built by NewTransaction.
+	TransactionOption func(*payments.Transaction)
+)
+
+// NewPayment builds a new payment of 1 SOL to the MerchantWallet, which expires in 15 minutes.
+func NewPayment(opts ...PaymentOption) *payments.Payment {
+	expiresAt := time.Now().Add(15 * time.Minute)
+	p := &payments.Payment{
+		ID:                uuid.New(),
+		DestinationWallet: MerchantWallet,
+		DestinationMint:   payments.SOL,
+		Amount:            1000000000,
+		Status:            payments.PaymentStatusNew,
+		ExpiresAt:         &expiresAt,
+		Livemode:          true,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithExternalID sets the payment external ID.
+func WithExternalID(id string) PaymentOption {
+	return func(p *payments.Payment) {
+		p.ExternalID = id
+	}
+}
+
+// WithAmount sets the payment amount.
+func WithAmount(amount uint64) PaymentOption {
+	return func(p *payments.Payment) {
+		p.Amount = amount
+	}
+}
+
+// WithDestination sets the payment destination wallet and mint.
+func WithDestination(wallet, mint string) PaymentOption {
+	return func(p *payments.Payment) {
+		p.DestinationWallet = wallet
+		p.DestinationMint = mint
+	}
+}
+
+// WithTestMode makes the payment a test-mode one.
+func WithTestMode() PaymentOption {
+	return func(p *payments.Payment) {
+		p.Livemode = false
+	}
+}
+
+// WithCallbackURL sets the payment callback URL.
+func WithCallbackURL(url string) PaymentOption {
+	return func(p *payments.Payment) {
+		p.CallbackURL = url
+	}
+}
+
+// WithStatus sets the payment status.
+func WithStatus(status payments.PaymentStatus) PaymentOption {
+	return func(p *payments.Payment) {
+		p.Status = status
+	}
+}
+
+// WithFeeOnTop makes the customer cover the payment fees.
+func WithFeeOnTop() PaymentOption {
+	return func(p *payments.Payment) {
+		p.FeeOnTop = true
+	}
+}
+
+// WithEscrow holds the payment funds in the escrow wallet until released.
+func WithEscrow() PaymentOption {
+	return func(p *payments.Payment) {
+		p.Escrow = true
+	}
+}
+
+// WithAllowPartial allows to pay the payment with several transactions.
+func WithAllowPartial() PaymentOption {
+	return func(p *payments.Payment) {
+		p.AllowPartial = true
+	}
+}
+
+// WithExpiresAt sets the payment expiration time.
+func WithExpiresAt(t time.Time) PaymentOption {
+	return func(p *payments.Payment) {
+		p.ExpiresAt = &t
+	}
+}
+
+// NewTransaction builds a new transaction of the given payment from the CustomerWallet.
+func NewTransaction(paymentID uuid.UUID, opts ...TransactionOption) *payments.Transaction {
+	tx := &payments.Transaction{
+		PaymentID:    paymentID,
+		SourceWallet: CustomerWallet,
+	}
+	for _, opt := range opts {
+		opt(tx)
+	}
+	return tx
+}
+
+// WithSource sets the transaction source wallet and mint.
+func WithSource(wallet, mint string) TransactionOption {
+	return func(tx *payments.Transaction) {
+		tx.SourceWallet = wallet
+		tx.SourceMint = mint
+	}
+}
+
+// WithApplyBonus applies the customer bonus balance to the transaction.
+func WithApplyBonus() TransactionOption {
+	return func(tx *payments.Transaction) {
+		tx.ApplyBonus = true
+	}
+}
+
+// WithApplyVoucher redeems the merchant vouchers of the customer.
+func WithApplyVoucher() TransactionOption {
+	return func(tx *payments.Transaction) {
+		tx.ApplyVoucher = true
+	}
+}
+
+// WithPartialAmount requests the transaction paying the given part of the payment amount.
+func WithPartialAmount(amount uint64) TransactionOption {
+	return func(tx *payments.Transaction) {
+		tx.PartialAmount = amount
+	}
+}
+
+// WithTip adds the tip to the transaction, sent to the given wallet or to the payment destination if it's empty.
+func WithTip(amount uint64, wallet string) TransactionOption {
+	return func(tx *payments.Transaction) {
+		tx.TipAmount = amount
+		tx.TipDestination = wallet
+	}
+}
+
+// RepositoryPayment converts the payment to the repository model,
+// e.g. to pre-populate PaymentRepository.
+func RepositoryPayment(p *payments.Payment) repository.Payment {
+	result := repository.Payment{
+		ID:                p.ID,
+		ExternalID:        sql.NullString{String: p.ExternalID, Valid: p.ExternalID != ""},
+		DestinationWallet: p.DestinationWallet,
+		DestinationMint:   p.DestinationMint,
+		Amount:            int64(p.Amount),
+		Status:            repository.PaymentStatus(p.Status),
+		Message:           sql.NullString{String: p.Message, Valid: p.Message != ""},
+		CreatedAt:         time.Now(),
+		CustomerEmail:     sql.NullString{String: p.CustomerEmail, Valid: p.CustomerEmail != ""},
+		FeeOnTop:          p.FeeOnTop,
+		Escrow:            p.Escrow,
+		Livemode:          p.Livemode,
+		CallbackUrl:       sql.NullString{String: p.CallbackURL, Valid: p.CallbackURL != ""},
+		AllowPartial:      p.AllowPartial,
+		AmountPaid:        int64(p.AmountPaid),
+	}
+	if result.Status == "" {
+		result.Status = repository.PaymentStatusNew
+	}
+	if p.ExpiresAt != nil {
+		result.ExpiresAt = sql.NullTime{Time: *p.ExpiresAt, Valid: true}
+	}
+	if len(p.Translations) > 0 {
+		result.Translations, _ = json.Marshal(p.Translations)
+	}
+	if p.Settings != nil {
+		result.Settings, _ = json.Marshal(p.Settings)
+	}
+	return result
+}
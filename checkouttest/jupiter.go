@@ -0,0 +1,86 @@
+package checkouttest
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/easypmnt/checkout-api/jupiter"
+)
+
+// ErrNotConfigured is returned by the fakes if the called method has no default behavior.
+var ErrNotConfigured = errors.New("checkouttest: method is not configured")
+
+// JupiterClient is a configurable fake of the Jupiter API client.
+// By default, it exchanges tokens at the fixed Rate (output amount per input amount unit).
+// Swap has no default behavior, since it must return a valid serialized transaction.
+type JupiterClient struct {
+	Rate float64
+
+	SwapFunc         func(ctx context.Context, params jupiter.SwapParams) (string, error)
+	ExchangeRateFunc func(ctx context.Context, params jupiter.ExchangeRateParams) (jupiter.Rate, error)
+	QuoteFunc        func(ctx context.Context, params jupiter.QuoteParams) (jupiter.QuoteResponse, error)
+}
+
+// NewJupiterClient creates a new fake Jupiter client with the given exchange rate.
+func NewJupiterClient(rate float64) *JupiterClient {
+	return &JupiterClient{Rate: rate}
+}
+
+// Swap calls SwapFunc or returns ErrNotConfigured.
+func (c *JupiterClient) Swap(ctx context.Context, params jupiter.SwapParams) (string, error) {
+	if c.SwapFunc != nil {
+		return c.SwapFunc(ctx, params)
+	}
+	return "", ErrNotConfigured
+}
+
+// ExchangeRate returns the exchange rate at the fixed rate.
+func (c *JupiterClient) ExchangeRate(ctx context.Context, params jupiter.ExchangeRateParams) (jupiter.Rate, error) {
+	if c.ExchangeRateFunc != nil {
+		return c.ExchangeRateFunc(ctx, params)
+	}
+
+	in, out := c.amounts(params.Amount, params.SwapMode)
+	return jupiter.Rate{
+		InputMint:  params.InputMint,
+		OutputMint: params.OutputMint,
+		InAmount:   in,
+		OutAmount:  out,
+	}, nil
+}
+
+// Quote returns a single direct route at the fixed rate.
+func (c *JupiterClient) Quote(ctx context.Context, params jupiter.QuoteParams) (jupiter.QuoteResponse, error) {
+	if c.QuoteFunc != nil {
+		return c.QuoteFunc(ctx, params)
+	}
+
+	in, out := c.amounts(params.Amount, params.SwapMode)
+	return jupiter.QuoteResponse{{
+		InAmount:    strconv.FormatUint(in, 10),
+		OutAmount:   strconv.FormatUint(out, 10),
+		Amount:      strconv.FormatUint(params.Amount, 10),
+		SlippageBps: int64(params.SlippageBps),
+		SwapMode:    params.SwapMode,
+		MarketInfos: []jupiter.MarketInfo{{
+			ID:         "checkouttest",
+			Label:      "checkouttest",
+			InputMint:  params.InputMint,
+			OutputMint: params.OutputMint,
+			InAmount:   strconv.FormatUint(in, 10),
+			OutAmount:  strconv.FormatUint(out, 10),
+		}},
+	}}, nil
+}
+
+// amounts returns the input and output amounts for the given amount and swap mode.
+func (c *JupiterClient) amounts(amount uint64, swapMode string) (uint64, uint64) {
+	if c.Rate <= 0 {
+		return amount, amount
+	}
+	if swapMode == jupiter.SwapModeExactOut {
+		return uint64(float64(amount) / c.Rate), amount
+	}
+	return amount, uint64(float64(amount) * c.Rate)
+}
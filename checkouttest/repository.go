@@ -0,0 +1,595 @@
+// Package checkouttest provides test doubles and builders to unit-test
+// code which embeds the payment service without a database or devnet connection.
+package checkouttest
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// PaymentRepository is an in-memory implementation of the payments repository.
+// It's safe for concurrent use.
+type PaymentRepository struct {
+	mu           sync.RWMutex
+	payments     map[uuid.UUID]repository.Payment
+	transactions map[string]repository.Transaction // keyed by reference
+	references   map[string]string                 // additional reference to the primary one
+	deposits     map[uuid.UUID]repository.PaymentDepositAddress
+	linkTokens   map[string]repository.PaymentLinkToken // keyed by token hash
+}
+
+// NewPaymentRepository creates a new in-memory payments repository,
+// optionally pre-populated with the given payments.
+func NewPaymentRepository(payments ...repository.Payment) *PaymentRepository {
+	r := &PaymentRepository{
+		payments:     make(map[uuid.UUID]repository.Payment, len(payments)),
+		transactions: make(map[string]repository.Transaction),
+		references:   make(map[string]string),
+		deposits:     make(map[uuid.UUID]repository.PaymentDepositAddress),
+		linkTokens:   make(map[string]repository.PaymentLinkToken),
+	}
+	for _, p := range payments {
+		r.payments[p.ID] = p
+	}
+	return r
+}
+
+// CreatePayment stores a new payment.
+func (r *PaymentRepository) CreatePayment(ctx context.Context, arg repository.CreatePaymentParams) (repository.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// mimic the unique index of the external ids
+	if arg.ExternalID.Valid {
+		for _, p := range r.payments {
+			if p.ExternalID.Valid && p.ExternalID.String == arg.ExternalID.String {
+				return repository.Payment{}, &pq.Error{Code: "23505", Constraint: repository.PaymentsExternalIDIndex}
+			}
+		}
+	}
+
+	p := repository.Payment{
+		ID:                uuid.New(),
+		ExternalID:        arg.ExternalID,
+		DestinationWallet: arg.DestinationWallet,
+		DestinationMint:   arg.DestinationMint,
+		Amount:            arg.Amount,
+		Status:            arg.Status,
+		Message:           arg.Message,
+		ExpiresAt:         arg.ExpiresAt,
+		CreatedAt:         time.Now(),
+		CustomerEmail:     arg.CustomerEmail,
+		Translations:      arg.Translations,
+		FeeOnTop:          arg.FeeOnTop,
+		Escrow:            arg.Escrow,
+		Livemode:          arg.Livemode,
+		CallbackUrl:       arg.CallbackUrl,
+		AllowPartial:      arg.AllowPartial,
+		FiatCurrency:      arg.FiatCurrency,
+		FiatAmount:        arg.FiatAmount,
+		Settings:          arg.Settings,
+	}
+	r.payments[p.ID] = p
+
+	return p, nil
+}
+
+// GetPayment returns the payment with the given ID or sql.ErrNoRows.
+func (r *PaymentRepository) GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.payments[id]
+	if !ok {
+		return repository.Payment{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+// GetPaymentByExternalID returns the payment with the given external ID or sql.ErrNoRows.
+func (r *PaymentRepository) GetPaymentByExternalID(ctx context.Context, externalID string) (repository.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.payments {
+		if p.ExternalID.Valid && p.ExternalID.String == externalID {
+			return p, nil
+		}
+	}
+	return repository.Payment{}, sql.ErrNoRows
+}
+
+// GetPaymentStatus returns the status of the payment with the given ID
+// and the signature of its latest signed transaction or sql.ErrNoRows.
+func (r *PaymentRepository) GetPaymentStatus(ctx context.Context, id uuid.UUID) (repository.GetPaymentStatusRow, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.payments[id]
+	if !ok {
+		return repository.GetPaymentStatusRow{}, sql.ErrNoRows
+	}
+
+	result := repository.GetPaymentStatusRow{Status: p.Status, UpdatedAt: p.CreatedAt}
+	if p.UpdatedAt.Valid {
+		result.UpdatedAt = p.UpdatedAt.Time
+	}
+
+	var latest time.Time
+	for _, t := range r.transactions {
+		if t.PaymentID != id || !t.TxSignature.Valid || t.TxSignature.String == "" {
+			continue
+		}
+		updated := t.CreatedAt
+		if t.UpdatedAt.Valid {
+			updated = t.UpdatedAt.Time
+		}
+		if result.TxSignature == "" || updated.After(latest) {
+			result.TxSignature, latest = t.TxSignature.String, updated
+		}
+	}
+
+	return result, nil
+}
+
+// MarkPaymentsExpired marks new payments with the expiration time before the given one as expired.
+func (r *PaymentRepository) MarkPaymentsExpired(ctx context.Context, expiredBefore time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, p := range r.payments {
+		if p.Status == repository.PaymentStatusNew && p.ExpiresAt.Valid && p.ExpiresAt.Time.Before(expiredBefore) {
+			p.Status = repository.PaymentStatusExpired
+			r.payments[id] = p
+		}
+	}
+	return nil
+}
+
+// UpdatePaymentStatus updates the status of the payment with the given ID.
+func (r *PaymentRepository) UpdatePaymentStatus(ctx context.Context, arg repository.UpdatePaymentStatusParams) (repository.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.payments[arg.ID]
+	if !ok {
+		return repository.Payment{}, sql.ErrNoRows
+	}
+	p.Status = arg.Status
+	p.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.payments[p.ID] = p
+
+	return p, nil
+}
+
+// RefreshPaymentAmountPaid sets the amount paid of the payment to the sum of its completed transactions.
+func (r *PaymentRepository) RefreshPaymentAmountPaid(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.payments[id]
+	if !ok {
+		return repository.Payment{}, sql.ErrNoRows
+	}
+	p.AmountPaid = 0
+	for _, t := range r.transactions {
+		if t.PaymentID == id && t.Status == repository.TransactionStatusCompleted {
+			p.AmountPaid += t.Amount
+		}
+	}
+	p.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.payments[p.ID] = p
+
+	return p, nil
+}
+
+// UpdatePaymentAmount sets the amount of the payment, e.g. converted from its fiat amount.
+func (r *PaymentRepository) UpdatePaymentAmount(ctx context.Context, arg repository.UpdatePaymentAmountParams) (repository.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.payments[arg.ID]
+	if !ok {
+		return repository.Payment{}, sql.ErrNoRows
+	}
+	p.Amount = arg.Amount
+	p.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.payments[p.ID] = p
+
+	return p, nil
+}
+
+// HoldPayment marks the payment as held in escrow until the given time.
+func (r *PaymentRepository) HoldPayment(ctx context.Context, arg repository.HoldPaymentParams) (repository.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.payments[arg.ID]
+	if !ok {
+		return repository.Payment{}, sql.ErrNoRows
+	}
+	p.Status = repository.PaymentStatusHeld
+	p.HeldUntil = arg.HeldUntil
+	p.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.payments[p.ID] = p
+
+	return p, nil
+}
+
+// ReleasePayment marks the held payment as released or returns sql.ErrNoRows if it's not held.
+func (r *PaymentRepository) ReleasePayment(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.payments[id]
+	if !ok || p.Status != repository.PaymentStatusHeld {
+		return repository.Payment{}, sql.ErrNoRows
+	}
+	p.Status = repository.PaymentStatusReleased
+	p.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.payments[p.ID] = p
+
+	return p, nil
+}
+
+// GetPaymentsToRelease returns the held payments with the release time in the past.
+func (r *PaymentRepository) GetPaymentsToRelease(ctx context.Context) ([]repository.Payment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var result []repository.Payment
+	for _, p := range r.payments {
+		if p.Status == repository.PaymentStatusHeld && p.HeldUntil.Valid && p.HeldUntil.Time.Before(now) {
+			result = append(result, p)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].HeldUntil.Time.Before(result[j].HeldUntil.Time) })
+
+	return result, nil
+}
+
+// CreateTransaction stores a new transaction.
+func (r *PaymentRepository) CreateTransaction(ctx context.Context, arg repository.CreateTransactionParams) (repository.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := repository.Transaction{
+		ID:                 uuid.New(),
+		PaymentID:          arg.PaymentID,
+		Reference:          arg.Reference,
+		SourceWallet:       arg.SourceWallet,
+		SourceMint:         arg.SourceMint,
+		DestinationWallet:  arg.DestinationWallet,
+		DestinationMint:    arg.DestinationMint,
+		Amount:             arg.Amount,
+		DiscountAmount:     arg.DiscountAmount,
+		TotalAmount:        arg.TotalAmount,
+		AccruedBonusAmount: arg.AccruedBonusAmount,
+		Message:            arg.Message,
+		Memo:               arg.Memo,
+		ApplyBonus:         arg.ApplyBonus,
+		Status:             arg.Status,
+		CreatedAt:          time.Now(),
+		NetworkFee:         arg.NetworkFee,
+		PriorityFee:        arg.PriorityFee,
+		SlippageFee:        arg.SlippageFee,
+		VoucherAmount:      arg.VoucherAmount,
+		SwapRoute:          arg.SwapRoute,
+		ExchangeRate:       arg.ExchangeRate,
+		TipAmount:          arg.TipAmount,
+		TipDestination:     arg.TipDestination,
+	}
+	if arg.ID.Valid {
+		t.ID = arg.ID.UUID
+	}
+	r.transactions[t.Reference] = t
+
+	return t, nil
+}
+
+// AddTransactionReference adds an additional reference to the transaction with the given ID.
+func (r *PaymentRepository) AddTransactionReference(ctx context.Context, arg repository.AddTransactionReferenceParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for ref, t := range r.transactions {
+		if t.ID == arg.TransactionID {
+			r.references[arg.Reference] = ref
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+
+// GetTransactionReferences returns the additional references of the transaction with the given ID.
+func (r *PaymentRepository) GetTransactionReferences(ctx context.Context, transactionID uuid.UUID) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []string
+	for ref, primary := range r.references {
+		if r.transactions[primary].ID == transactionID {
+			result = append(result, ref)
+		}
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// primaryReference returns the primary reference of the transaction with the given primary or additional reference.
+// It must be called with the lock held.
+func (r *PaymentRepository) primaryReference(reference string) string {
+	if primary, ok := r.references[reference]; ok {
+		return primary
+	}
+	return reference
+}
+
+// GetTransactionByPaymentIDSourceWalletAndMint returns the latest transaction
+// of the payment with the given source wallet and mint or sql.ErrNoRows.
+func (r *PaymentRepository) GetTransactionByPaymentIDSourceWalletAndMint(ctx context.Context, arg repository.GetTransactionByPaymentIDSourceWalletAndMintParams) (repository.Transaction, error) {
+	txs, _ := r.GetTransactionsByPaymentID(ctx, arg.PaymentID)
+	for _, t := range txs {
+		if t.SourceWallet == arg.SourceWallet && t.SourceMint == arg.SourceMint {
+			return t, nil
+		}
+	}
+	return repository.Transaction{}, sql.ErrNoRows
+}
+
+// GetTransactionByReference returns the transaction with the given primary or additional reference or sql.ErrNoRows.
+func (r *PaymentRepository) GetTransactionByReference(ctx context.Context, reference string) (repository.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.transactions[r.primaryReference(reference)]
+	if !ok {
+		return repository.Transaction{}, sql.ErrNoRows
+	}
+	return t, nil
+}
+
+// GetTransactionsByPaymentID returns the payment transactions, the latest first.
+func (r *PaymentRepository) GetTransactionsByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]repository.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []repository.Transaction
+	for _, t := range r.transactions {
+		if t.PaymentID == paymentID {
+			result = append(result, t)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.After(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
+
+// UpdateTransactionByReference updates the status and signature of the transaction with the given reference.
+func (r *PaymentRepository) UpdateTransactionByReference(ctx context.Context, arg repository.UpdateTransactionByReferenceParams) (repository.Transaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.transactions[r.primaryReference(arg.Reference)]
+	if !ok {
+		return repository.Transaction{}, sql.ErrNoRows
+	}
+	t.Status = arg.Status
+	t.TxSignature = arg.TxSignature
+	t.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.transactions[t.Reference] = t
+
+	return t, nil
+}
+
+// ExpireOtherPendingTransactions expires the pending transactions of the payment except the given one.
+func (r *PaymentRepository) ExpireOtherPendingTransactions(ctx context.Context, arg repository.ExpireOtherPendingTransactionsParams) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for ref, t := range r.transactions {
+		if t.PaymentID == arg.PaymentID && t.ID != arg.ID && t.Status == repository.TransactionStatusPending {
+			t.Status = repository.TransactionStatusExpired
+			t.UpdatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			r.transactions[ref] = t
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+// GetPendingTransactions returns all pending transactions.
+func (r *PaymentRepository) GetPendingTransactions(ctx context.Context) ([]repository.Transaction, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []repository.Transaction
+	for _, t := range r.transactions {
+		if t.Status == repository.TransactionStatusPending {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// GetPendingTransactionReferences returns the primary and the additional references of the pending transactions.
+func (r *PaymentRepository) GetPendingTransactionReferences(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []string
+	for ref, t := range r.transactions {
+		if t.Status == repository.TransactionStatusPending {
+			result = append(result, ref)
+		}
+	}
+	for ref, primary := range r.references {
+		if r.transactions[primary].Status == repository.TransactionStatusPending {
+			result = append(result, ref)
+		}
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// MarkTransactionsAsExpired marks pending transactions of the expired payments as expired.
+func (r *PaymentRepository) MarkTransactionsAsExpired(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for ref, t := range r.transactions {
+		if t.Status == repository.TransactionStatusPending && r.payments[t.PaymentID].Status == repository.PaymentStatusExpired {
+			t.Status = repository.TransactionStatusExpired
+			r.transactions[ref] = t
+		}
+	}
+	return nil
+}
+
+// CreateDepositAddress stores the deposit address of the payment.
+func (r *PaymentRepository) CreateDepositAddress(ctx context.Context, arg repository.CreateDepositAddressParams) (repository.PaymentDepositAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// mimic the primary key of the payment ids
+	if _, ok := r.deposits[arg.PaymentID]; ok {
+		return repository.PaymentDepositAddress{}, &pq.Error{Code: "23505", Constraint: "payment_deposit_addresses_pkey"}
+	}
+
+	d := repository.PaymentDepositAddress{
+		PaymentID:     arg.PaymentID,
+		TransactionID: arg.TransactionID,
+		Wallet:        arg.Wallet,
+		TokenAccount:  arg.TokenAccount,
+		Mint:          arg.Mint,
+		Amount:        arg.Amount,
+		CreatedAt:     time.Now(),
+	}
+	r.deposits[d.PaymentID] = d
+
+	return d, nil
+}
+
+// GetDepositAddressByPaymentID returns the deposit address of the payment.
+func (r *PaymentRepository) GetDepositAddressByPaymentID(ctx context.Context, paymentID uuid.UUID) (repository.PaymentDepositAddress, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.deposits[paymentID]
+	if !ok {
+		return repository.PaymentDepositAddress{}, sql.ErrNoRows
+	}
+	return d, nil
+}
+
+// GetDepositAddressesToSweep returns the unswept deposit addresses whose transaction is completed.
+func (r *PaymentRepository) GetDepositAddressesToSweep(ctx context.Context) ([]repository.PaymentDepositAddress, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var result []repository.PaymentDepositAddress
+	for _, d := range r.deposits {
+		if d.SweptAt.Valid {
+			continue
+		}
+		for _, t := range r.transactions {
+			if t.ID == d.TransactionID && t.Status == repository.TransactionStatusCompleted {
+				result = append(result, d)
+				break
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+
+	return result, nil
+}
+
+// MarkDepositAddressSwept stores the sweep signature of the unswept deposit address.
+func (r *PaymentRepository) MarkDepositAddressSwept(ctx context.Context, arg repository.MarkDepositAddressSweptParams) (repository.PaymentDepositAddress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.deposits[arg.PaymentID]
+	if !ok || d.SweptAt.Valid {
+		return repository.PaymentDepositAddress{}, sql.ErrNoRows
+	}
+	d.SweepSignature = arg.SweepSignature
+	d.SweptAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.deposits[arg.PaymentID] = d
+
+	return d, nil
+}
+
+// CreateLinkToken stores the access token of the payment link.
+func (r *PaymentRepository) CreateLinkToken(ctx context.Context, arg repository.CreateLinkTokenParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.linkTokens[arg.TokenHash]; ok {
+		return &pq.Error{Code: "23505", Constraint: "payment_link_tokens_pkey"}
+	}
+	r.linkTokens[arg.TokenHash] = repository.PaymentLinkToken{
+		TokenHash: arg.TokenHash,
+		PaymentID: arg.PaymentID,
+		ExpiresAt: arg.ExpiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	return nil
+}
+
+// GetLinkToken returns the access token of the payment link by its hash.
+func (r *PaymentRepository) GetLinkToken(ctx context.Context, tokenHash string) (repository.PaymentLinkToken, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.linkTokens[tokenHash]
+	if !ok {
+		return repository.PaymentLinkToken{}, sql.ErrNoRows
+	}
+	return t, nil
+}
+
+// UseLinkToken marks the unused and unexpired access token of the payment as used.
+func (r *PaymentRepository) UseLinkToken(ctx context.Context, arg repository.UseLinkTokenParams) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.linkTokens[arg.TokenHash]
+	if !ok || t.PaymentID != arg.PaymentID || t.UsedAt.Valid || !t.ExpiresAt.After(time.Now()) {
+		return 0, nil
+	}
+	t.UsedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	r.linkTokens[arg.TokenHash] = t
+
+	return 1, nil
+}
+
+// DeleteExpiredLinkTokens deletes the expired access tokens of the payment links.
+func (r *PaymentRepository) DeleteExpiredLinkTokens(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for hash, t := range r.linkTokens {
+		if t.ExpiresAt.Before(time.Now()) {
+			delete(r.linkTokens, hash)
+			n++
+		}
+	}
+	return n, nil
+}
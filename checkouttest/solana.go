@@ -0,0 +1,290 @@
+package checkouttest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// DefaultBlockhash is the blockhash returned by SolanaClient by default.
+const DefaultBlockhash = "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N"
+
+// SolanaClient is a configurable fake of the Solana RPC client used by the payment service.
+// Balances and mint decimals are taken from the client maps,
+// any method can be overridden by setting the corresponding function field.
+type SolanaClient struct {
+	mu sync.RWMutex
+
+	Blockhash       string
+	RentExemption   uint64
+	SOLBalances     map[string]uint64            // wallet => lamports
+	TokenBalances   map[string]map[string]uint64 // wallet => mint => amount
+	MintDecimals    map[string]uint8             // mint => decimals; 9 if not set
+	TokenAccounts   map[string]bool              // existing associated token accounts
+	InvalidMints    map[string]bool              // mints rejected by ValidateMint
+	FrozenAccounts  map[string]map[string]bool   // wallet => mint => frozen
+	InvalidOwners   map[string]bool              // destinations rejected by ValidateDestination
+	DefaultDecimals uint8
+
+	GetLatestBlockhashFunc                func(ctx context.Context) (string, error)
+	DoesTokenAccountExistFunc             func(ctx context.Context, base58AtaAddr string) (bool, error)
+	GetMinimumBalanceForRentExemptionFunc func(ctx context.Context, size uint64) (uint64, error)
+	GetSOLBalanceFunc                     func(ctx context.Context, base58Addr string) (solana.Balance, error)
+	GetTokenBalanceFunc                   func(ctx context.Context, base58Addr, base58MintAddr string) (solana.Balance, error)
+	GetTokenAccountsByOwnerFunc           func(ctx context.Context, base58Addr string) (map[string]solana.Balance, error)
+	IsTokenAccountFrozenFunc              func(ctx context.Context, base58Addr, base58MintAddr string) (bool, error)
+	GetMintDecimalsFunc                   func(ctx context.Context, base58MintAddr string) (uint8, error)
+	ValidateMintFunc                      func(ctx context.Context, base58MintAddr string) (uint8, error)
+	ValidateDestinationFunc               func(ctx context.Context, base58Addr string) error
+	ValidateTokenBurnFunc                 func(ctx context.Context, txSignature, owner, mint string, amount uint64) error
+	SendTransactionFunc                   func(ctx context.Context, txSource string) (string, error)
+	GetTransactionStatusFunc              func(ctx context.Context, txhash string, commitment solana.Commitment) (solana.TransactionStatus, error)
+	MatchTransactionByReferenceFunc       func(ctx context.Context, reference, destination string, amount uint64, mint string, commitment solana.Commitment) (*solana.ReferenceMatch, error)
+	GetRecentPrioritizationFeesFunc       func(ctx context.Context, base58Accounts ...string) ([]solana.PrioritizationFee, error)
+
+	sent []string // transactions sent by SendTransaction
+}
+
+// NewSolanaClient creates a new fake Solana client with empty balances.
+func NewSolanaClient() *SolanaClient {
+	return &SolanaClient{
+		Blockhash:       DefaultBlockhash,
+		RentExemption:   890880,
+		SOLBalances:     make(map[string]uint64),
+		TokenBalances:   make(map[string]map[string]uint64),
+		MintDecimals:    make(map[string]uint8),
+		TokenAccounts:   make(map[string]bool),
+		InvalidMints:    make(map[string]bool),
+		FrozenAccounts:  make(map[string]map[string]bool),
+		InvalidOwners:   make(map[string]bool),
+		DefaultDecimals: 9,
+	}
+}
+
+// SetSOLBalance sets the SOL balance of the wallet.
+func (c *SolanaClient) SetSOLBalance(wallet string, lamports uint64) *SolanaClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.SOLBalances[wallet] = lamports
+	return c
+}
+
+// SetTokenBalance sets the token balance of the wallet.
+func (c *SolanaClient) SetTokenBalance(wallet, mint string, amount uint64) *SolanaClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.TokenBalances[wallet] == nil {
+		c.TokenBalances[wallet] = make(map[string]uint64)
+	}
+	c.TokenBalances[wallet][mint] = amount
+	return c
+}
+
+// SetFrozen freezes or thaws the token account of the wallet.
+func (c *SolanaClient) SetFrozen(wallet, mint string, frozen bool) *SolanaClient {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.FrozenAccounts[wallet] == nil {
+		c.FrozenAccounts[wallet] = make(map[string]bool)
+	}
+	c.FrozenAccounts[wallet][mint] = frozen
+	return c
+}
+
+// GetLatestBlockhash returns the configured blockhash.
+func (c *SolanaClient) GetLatestBlockhash(ctx context.Context) (string, error) {
+	if c.GetLatestBlockhashFunc != nil {
+		return c.GetLatestBlockhashFunc(ctx)
+	}
+	return c.Blockhash, nil
+}
+
+// DoesTokenAccountExist reports whether the token account is in the TokenAccounts map.
+func (c *SolanaClient) DoesTokenAccountExist(ctx context.Context, base58AtaAddr string) (bool, error) {
+	if c.DoesTokenAccountExistFunc != nil {
+		return c.DoesTokenAccountExistFunc(ctx, base58AtaAddr)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.TokenAccounts[base58AtaAddr], nil
+}
+
+// GetMinimumBalanceForRentExemption returns the configured rent exemption minimum.
+func (c *SolanaClient) GetMinimumBalanceForRentExemption(ctx context.Context, size uint64) (uint64, error) {
+	if c.GetMinimumBalanceForRentExemptionFunc != nil {
+		return c.GetMinimumBalanceForRentExemptionFunc(ctx, size)
+	}
+	return c.RentExemption, nil
+}
+
+// GetSOLBalance returns the SOL balance of the wallet.
+func (c *SolanaClient) GetSOLBalance(ctx context.Context, base58Addr string) (solana.Balance, error) {
+	if c.GetSOLBalanceFunc != nil {
+		return c.GetSOLBalanceFunc(ctx, base58Addr)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return solana.NewBalance(c.SOLBalances[base58Addr], 9), nil
+}
+
+// GetTokenBalance returns the token balance of the wallet,
+// or solana.ErrTokenAccountDoesNotExist if the balance is not set.
+func (c *SolanaClient) GetTokenBalance(ctx context.Context, base58Addr, base58MintAddr string) (solana.Balance, error) {
+	if c.GetTokenBalanceFunc != nil {
+		return c.GetTokenBalanceFunc(ctx, base58Addr, base58MintAddr)
+	}
+
+	decimals, _ := c.GetMintDecimals(ctx, base58MintAddr)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	amount, ok := c.TokenBalances[base58Addr][base58MintAddr]
+	if !ok {
+		return solana.Balance{}, solana.ErrTokenAccountDoesNotExist
+	}
+	return solana.NewBalance(amount, decimals), nil
+}
+
+// GetTokenAccountsByOwner returns the non-zero token balances of the wallet.
+func (c *SolanaClient) GetTokenAccountsByOwner(ctx context.Context, base58Addr string) (map[string]solana.Balance, error) {
+	if c.GetTokenAccountsByOwnerFunc != nil {
+		return c.GetTokenAccountsByOwnerFunc(ctx, base58Addr)
+	}
+
+	c.mu.RLock()
+	amounts := make(map[string]uint64, len(c.TokenBalances[base58Addr]))
+	for mint, amount := range c.TokenBalances[base58Addr] {
+		if amount > 0 {
+			amounts[mint] = amount
+		}
+	}
+	c.mu.RUnlock()
+
+	result := make(map[string]solana.Balance, len(amounts))
+	for mint, amount := range amounts {
+		decimals, _ := c.GetMintDecimals(ctx, mint)
+		result[mint] = solana.NewBalance(amount, decimals)
+	}
+	return result, nil
+}
+
+// IsTokenAccountFrozen reports whether the token account of the wallet is in the FrozenAccounts map.
+func (c *SolanaClient) IsTokenAccountFrozen(ctx context.Context, base58Addr, base58MintAddr string) (bool, error) {
+	if c.IsTokenAccountFrozenFunc != nil {
+		return c.IsTokenAccountFrozenFunc(ctx, base58Addr, base58MintAddr)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.FrozenAccounts[base58Addr][base58MintAddr], nil
+}
+
+// GetMintDecimals returns the mint decimals, or DefaultDecimals if they are not set.
+func (c *SolanaClient) GetMintDecimals(ctx context.Context, base58MintAddr string) (uint8, error) {
+	if c.GetMintDecimalsFunc != nil {
+		return c.GetMintDecimalsFunc(ctx, base58MintAddr)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if decimals, ok := c.MintDecimals[base58MintAddr]; ok {
+		return decimals, nil
+	}
+	return c.DefaultDecimals, nil
+}
+
+// ValidateMint returns solana.ErrInvalidMint for the mints in the InvalidMints map.
+func (c *SolanaClient) ValidateMint(ctx context.Context, base58MintAddr string) (uint8, error) {
+	if c.ValidateMintFunc != nil {
+		return c.ValidateMintFunc(ctx, base58MintAddr)
+	}
+
+	c.mu.RLock()
+	invalid := c.InvalidMints[base58MintAddr]
+	c.mu.RUnlock()
+
+	if invalid {
+		return 0, solana.ErrInvalidMint
+	}
+	return c.GetMintDecimals(ctx, base58MintAddr)
+}
+
+// ValidateDestination returns solana.ErrInvalidDestination for the addresses in the InvalidOwners map.
+func (c *SolanaClient) ValidateDestination(ctx context.Context, base58Addr string) error {
+	if c.ValidateDestinationFunc != nil {
+		return c.ValidateDestinationFunc(ctx, base58Addr)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.InvalidOwners[base58Addr] {
+		return solana.ErrInvalidDestination
+	}
+	return nil
+}
+
+// ValidateTokenBurn calls ValidateTokenBurnFunc or accepts any burn.
+func (c *SolanaClient) ValidateTokenBurn(ctx context.Context, txSignature, owner, mint string, amount uint64) error {
+	if c.ValidateTokenBurnFunc != nil {
+		return c.ValidateTokenBurnFunc(ctx, txSignature, owner, mint, amount)
+	}
+	return nil
+}
+
+// SendTransaction calls SendTransactionFunc or records the transaction and returns a fake signature.
+func (c *SolanaClient) SendTransaction(ctx context.Context, txSource string) (string, error) {
+	if c.SendTransactionFunc != nil {
+		return c.SendTransactionFunc(ctx, txSource)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sent = append(c.sent, txSource)
+	return fmt.Sprintf("signature-%d", len(c.sent)), nil
+}
+
+// SentTransactions returns the base64 encoded transactions sent by SendTransaction.
+func (c *SolanaClient) SentTransactions() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return append([]string(nil), c.sent...)
+}
+
+// GetTransactionStatus calls GetTransactionStatusFunc or reports that the transaction is unknown.
+func (c *SolanaClient) GetTransactionStatus(ctx context.Context, txhash string, commitment solana.Commitment) (solana.TransactionStatus, error) {
+	if c.GetTransactionStatusFunc != nil {
+		return c.GetTransactionStatusFunc(ctx, txhash, commitment)
+	}
+	return solana.TransactionStatusUnknown, nil
+}
+
+// MatchTransactionByReference calls MatchTransactionByReferenceFunc or reports that no transaction is found.
+func (c *SolanaClient) MatchTransactionByReference(ctx context.Context, reference, destination string, amount uint64, mint string, commitment solana.Commitment) (*solana.ReferenceMatch, error) {
+	if c.MatchTransactionByReferenceFunc != nil {
+		return c.MatchTransactionByReferenceFunc(ctx, reference, destination, amount, mint, commitment)
+	}
+	return &solana.ReferenceMatch{ExpectedAmount: amount}, nil
+}
+
+// GetRecentPrioritizationFees returns no fees, so the payment transactions are built with the fixed compute unit price.
+func (c *SolanaClient) GetRecentPrioritizationFees(ctx context.Context, base58Accounts ...string) ([]solana.PrioritizationFee, error) {
+	if c.GetRecentPrioritizationFeesFunc != nil {
+		return c.GetRecentPrioritizationFeesFunc(ctx, base58Accounts...)
+	}
+	return nil, nil
+}
@@ -0,0 +1,59 @@
+package checkouttest
+
+import (
+	"context"
+	"sync"
+)
+
+// FiredEvent is an event recorded by WebhookEnqueuer.
+type FiredEvent struct {
+	Event   string
+	Payload interface{}
+}
+
+// WebhookEnqueuer is a fake of the webhook enqueuer which records fired events.
+type WebhookEnqueuer struct {
+	mu     sync.Mutex
+	events []FiredEvent
+
+	// Err is returned by FireEvent, if set.
+	Err error
+}
+
+// NewWebhookEnqueuer creates a new fake webhook enqueuer.
+func NewWebhookEnqueuer() *WebhookEnqueuer {
+	return &WebhookEnqueuer{}
+}
+
+// FireEvent records the event.
+func (e *WebhookEnqueuer) FireEvent(ctx context.Context, event string, payload interface{}) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.Err != nil {
+		return e.Err
+	}
+	e.events = append(e.events, FiredEvent{Event: event, Payload: payload})
+
+	return nil
+}
+
+// Events returns the recorded events.
+func (e *WebhookEnqueuer) Events() []FiredEvent {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return append([]FiredEvent(nil), e.events...)
+}
+
+// EventNames returns the names of the recorded events, in the order they were fired.
+func (e *WebhookEnqueuer) EventNames() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	names := make([]string, 0, len(e.events))
+	for _, ev := range e.events {
+		names = append(names, ev.Event)
+	}
+	return names
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/alerts"
+	"github.com/easypmnt/checkout-api/events"
+)
+
+// newAlertsService creates the operator alerts service with Slack and/or Telegram channels,
+// according to the ALERTS_* settings.
+func newAlertsService() (*alerts.Service, error) {
+	var opts []alerts.ServiceOption
+
+	if alertsSlackWebhookURL != "" {
+		severity, err := alerts.ParseSeverity(alertsSlackMinSeverity)
+		if err != nil {
+			return nil, fmt.Errorf("ALERTS_SLACK_MIN_SEVERITY: %w", err)
+		}
+		opts = append(opts, alerts.WithNotifier(alerts.NewSlackNotifier(alertsSlackWebhookURL), severity))
+	}
+
+	if alertsTelegramBotToken != "" && alertsTelegramChatID != "" {
+		severity, err := alerts.ParseSeverity(alertsTelegramMinSeverity)
+		if err != nil {
+			return nil, fmt.Errorf("ALERTS_TELEGRAM_MIN_SEVERITY: %w", err)
+		}
+		opts = append(opts, alerts.WithNotifier(
+			alerts.NewTelegramNotifier(alertsTelegramBotToken, alertsTelegramChatID),
+			severity,
+		))
+	}
+
+	// event severity overrides in format: event:severity
+	for _, item := range alertsEventSeverity {
+		name, level, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			return nil, fmt.Errorf("ALERTS_EVENT_SEVERITY: invalid value: %s", item)
+		}
+		severity, err := alerts.ParseSeverity(level)
+		if err != nil {
+			return nil, fmt.Errorf("ALERTS_EVENT_SEVERITY: %w", err)
+		}
+		opts = append(opts, alerts.WithEventSeverity(events.EventName(name), severity))
+	}
+
+	return alerts.NewService(opts...), nil
+}
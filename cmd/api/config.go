@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/dmitrymomot/go-env"
+	"github.com/easypmnt/checkout-api/internal/realip"
 	_ "github.com/joho/godotenv/autoload" // Load .env file automatically
 )
 
@@ -12,22 +13,58 @@ var (
 	appName  = env.GetString("APP_NAME", "api")
 	appDebug = env.GetBool("APP_DEBUG", false)
 
+	// Logging
+	logLevel              = env.GetString("LOG_LEVEL", "")                        // debug, info, warn, error; debug if APP_DEBUG is set, info otherwise
+	logFormat             = env.GetString("LOG_FORMAT", "json")                   // text, json
+	logModuleLevels       = env.GetStringsMap("LOG_MODULE_LEVELS", ",", ":", nil) // e.g. websocketrpc:warn,payments:debug
+	logSamplingInitial    = env.GetInt("LOG_SAMPLING_INITIAL", 10)                // debug lines with the same message logged per tick before sampling
+	logSamplingThereafter = env.GetInt("LOG_SAMPLING_THEREAFTER", 100)            // then every N-th line is logged; 0 disables sampling
+	logSamplingTick       = env.GetDuration("LOG_SAMPLING_TICK", time.Second)
+	logRedactFields       = env.GetStrings("LOG_REDACT_FIELDS", ",", nil) // redacted in addition to the wallet keys, OAuth and webhook secrets
+
 	// Product
 	productName    = env.GetString("PRODUCT_NAME", "Checkout API")                                                // To show on client side
 	productIconURI = env.GetString("PRODUCT_ICON", "https://avatars.githubusercontent.com/u/125194068?s=200&v=4") // absolute URI to product icon
 
+	// Hosted checkout page at /checkout/{payment_id}, branded with the product name and icon;
+	// not served with the checkout proof of work, the page can't solve it
+	checkoutPageEnabled    = env.GetBool("CHECKOUT_PAGE_ENABLED", true)
+	checkoutPageColor      = env.GetString("CHECKOUT_PAGE_COLOR", "#512da8") // CSS color of the buttons and the accents
+	checkoutPageSupportURL = env.GetString("CHECKOUT_PAGE_SUPPORT_URL", "")  // omitted if empty
+	checkoutPageURL        = env.GetString("CHECKOUT_PAGE_URL", "")          // e.g. https://api.example.com/checkout/{payment_id}; wallet deep links are omitted if empty
+
 	// HTTP Router
 	httpPort                  = env.GetInt("HTTP_PORT", 8080)
 	httpRequestTimeout        = env.GetDuration("HTTP_REQUEST_TIMEOUT", time.Second*10)
 	httpServerShutdownTimeout = env.GetDuration("HTTP_SERVER_SHUTDOWN_TIMEOUT", time.Second*5)
-	httpLimitRequestBodySize  = env.GetInt[int64]("HTTP_LIMIT_REQUEST_BODY_SIZE", 1<<20) // 1 MB
+	httpTransactionTimeout    = env.GetDuration("HTTP_TRANSACTION_TIMEOUT", time.Second*5) // transaction generation routes; 0 disables it
+	httpEstimateTimeout       = env.GetDuration("HTTP_ESTIMATE_TIMEOUT", time.Second*3)    // quote, exchange rate and wallet tokens routes; 0 disables it
+	httpLimitRequestBodySize  = env.GetInt[int64]("HTTP_LIMIT_REQUEST_BODY_SIZE", 1<<20)   // 1 MB
 	httpRateLimit             = env.GetInt("HTTP_RATE_LIMIT", 100)
 	httpRateLimitDuration     = env.GetDuration("HTTP_RATE_LIMIT_DURATION", time.Minute)
+	httpTrustedProxies        = env.GetStrings("HTTP_TRUSTED_PROXIES", ",", realip.DefaultTrustedProxies) // CIDRs of the proxies whose X-Forwarded-For and X-Real-IP headers are honored
+
+	// Public checkout endpoints protection
+	checkoutRateLimitPerIP      = env.GetInt("CHECKOUT_RATE_LIMIT_PER_IP", 30)      // 0 disables the limit
+	checkoutRateLimitPerPayment = env.GetInt("CHECKOUT_RATE_LIMIT_PER_PAYMENT", 60) // 0 disables the limit
+	checkoutRateLimitPeriod     = env.GetDuration("CHECKOUT_RATE_LIMIT_PERIOD", time.Minute)
+	checkoutPoWDifficulty       = env.GetInt("CHECKOUT_POW_DIFFICULTY", 0) // leading zero bits of the proof of work; 0 disables it
+	paymentStatusCacheTTL       = env.GetDuration("PAYMENT_STATUS_CACHE_TTL", time.Second*2)
+
+	// Config reload on SIGHUP or POST /admin/config/reload: webhook URI, checkout rate limits,
+	// bonus settings and accepted store currencies; the values of the file override the environment
+	configReloadFile = env.GetString("CONFIG_RELOAD_FILE", ".env") // disabled if empty, the environment is re-read only
+
+	// Metrics
+	metricsPath = env.GetString("METRICS_PATH", "/metrics") // Prometheus metrics endpoint; disabled if empty
+
+	// Kubernetes probes: /healthz and /readyz verify the dependencies on every call
+	healthCheckTimeout = env.GetDuration("HEALTH_CHECK_TIMEOUT", time.Second*3) // per dependency
 
 	// Cors
 	corsAllowedOrigins     = env.GetStrings("CORS_ALLOWED_ORIGINS", ",", []string{"*"})
 	corsAllowedMethods     = env.GetStrings("CORS_ALLOWED_METHODS", ",", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "HEAD"})
-	corsAllowedHeaders     = env.GetStrings("CORS_ALLOWED_HEADERS", ",", []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Request-ID", "X-Request-Id", "Origin", "User-Agent", "Accept-Encoding", "Accept-Language", "Cache-Control", "Connection", "DNT", "Host", "Pragma", "Referer"})
+	corsAllowedHeaders     = env.GetStrings("CORS_ALLOWED_HEADERS", ",", []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Request-ID", "X-Request-Id", "Origin", "User-Agent", "Accept-Encoding", "Accept-Language", "Cache-Control", "Connection", "DNT", "Host", "Pragma", "Referer", "X-Checkout-PoW", "X-Supported-Transaction-Versions"})
 	corsAllowedCredentials = env.GetBool("CORS_ALLOWED_CREDENTIALS", true)
 	corsMaxAge             = env.GetInt("CORS_MAX_AGE", 300)
 
@@ -36,42 +73,307 @@ var (
 	buildTagRuntime = env.GetString("COMMIT_HASH", buildTag)
 
 	// DB
-	dbConnString   = env.MustString("DATABASE_URL")
+	dbDriver       = env.GetString("DATABASE_DRIVER", "postgres") // storage backend, see repository.Backends
+	dbConnString   = env.GetString("DATABASE_URL", "")            // required, if not set in Vault
 	dbMaxOpenConns = env.GetInt("DATABASE_MAX_OPEN_CONNS", 20)
 	dbMaxIdleConns = env.GetInt("DATABASE_IDLE_CONNS", 2)
 
+	// Monthly partitioning of the high-volume tables, see repository/sql/partitioning
+	dbPartitioningEnabled = env.GetBool("DATABASE_PARTITIONING_ENABLED", false)
+	dbPartitionsAhead     = env.GetInt("DATABASE_PARTITIONS_AHEAD", 3) // months
+
 	// Redis
 	redisConnString = env.MustString("REDIS_DATABASE_URL")
 	redisPoolSize   = env.GetInt("REDIS_POOL_SIZE", 10)
 
+	// Events bus (Redis stream), so the listeners handle the events emitted on any replica once
+	eventsBusEnabled = env.GetBool("EVENTS_BUS_ENABLED", false)
+	eventsBusStream  = env.GetString("EVENTS_BUS_STREAM", "checkout:events:stream")
+	eventsBusGroup   = env.GetString("EVENTS_BUS_GROUP", "checkout")
+	eventsBusMaxLen  = env.GetInt("EVENTS_BUS_MAX_LEN", 100000) // approximate number of the events kept in the stream
+
+	// Events fanout across the API instances, required to run more than one replica
+	eventsFanoutEnabled = env.GetBool("EVENTS_FANOUT_ENABLED", false)
+	eventsFanoutChannel = env.GetString("EVENTS_FANOUT_CHANNEL", "checkout:events")
+
+	// Event sink forwarding the events to Kafka (REST Proxy) or NATS JetStream, disabled if not set
+	eventSink       = env.GetString("EVENT_SINK", "") // kafka or nats
+	eventSinkURL    = env.GetString("EVENT_SINK_URL", "")
+	eventSinkTopics = env.GetString("EVENT_SINK_TOPICS", "*=checkout.events") // pattern=topic, the first match wins
+
+	// Transactional outbox of the payment and transaction status events
+	eventsOutboxEnabled      = env.GetBool("EVENTS_OUTBOX_ENABLED", true)
+	eventsOutboxPollInterval = env.GetDuration("EVENTS_OUTBOX_POLL_INTERVAL", time.Second)
+	eventsOutboxRetention    = env.GetDuration("EVENTS_OUTBOX_RETENTION", 24*time.Hour) // published events kept for troubleshooting
+
+	// Payment status streams (SSE) of the checkout pages
+	sseHeartbeatInterval = env.GetDuration("SSE_HEARTBEAT_INTERVAL", 15*time.Second)
+
+	// Leader election, so only one instance runs the task scheduler and the websocket listener
+	leaderElectionEnabled = env.GetBool("LEADER_ELECTION_ENABLED", false)
+	leaderElectionKey     = env.GetString("LEADER_ELECTION_KEY", "checkout:leader")
+	leaderElectionTTL     = env.GetDuration("LEADER_ELECTION_TTL", time.Second*15)
+
 	// Auth
-	oauthSigningKey = env.MustString("OAUTH_SIGNING_KEY")
+	oauthSigningKey = env.GetString("OAUTH_SIGNING_KEY", "") // required, if not set in Vault
 	accessTokenTTL  = env.GetDuration("ACCESS_TOKEN_TTL", time.Minute*5)
 	refreshTokenTTL = env.GetDuration("REFRESH_TOKEN_TTL", time.Hour)
 	clientID        = env.MustString("CLIENT_ID")
-	clientSecret    = env.MustString("CLIENT_SECRET")
+	clientSecret    = env.GetString("CLIENT_SECRET", "") // required, if not set in Vault
+
+	// Static API keys, "Authorization: Bearer pk_live_...", managed via /admin/api-keys
+	apiKeysEnabled = env.GetBool("API_KEYS_ENABLED", true)
+
+	// OpenID Connect login of the operators to the admin routes: GET /oauth/oidc/login
+	oidcEnabled      = env.GetBool("OIDC_ENABLED", false)
+	oidcIssuerURL    = env.GetString("OIDC_ISSUER_URL", "") // e.g. https://accounts.google.com, https://example.okta.com
+	oidcClientID     = env.GetString("OIDC_CLIENT_ID", "")
+	oidcClientSecret = env.GetString("OIDC_CLIENT_SECRET", "")
+	oidcRedirectURL  = env.GetString("OIDC_REDIRECT_URL", "") // e.g. https://api.example.com/oauth/oidc/callback
+	oidcScopes       = env.GetStrings("OIDC_SCOPES", ",", []string{"openid", "email", "profile"})
+	oidcGroupsClaim  = env.GetString("OIDC_GROUPS_CLAIM", "groups")
+	oidcRoleMapping  = env.GetStrings("OIDC_ROLE_MAPPING", ",", nil) // group:role pairs, the first matching one wins, e.g. checkout-admins:owner,checkout-support:support
+	oidcTokenTTL     = env.GetDuration("OIDC_TOKEN_TTL", 8*time.Hour)
+
+	// Audit trail of the authenticated API calls, queried via GET /admin/audit
+	auditEnabled   = env.GetBool("AUDIT_ENABLED", false)
+	auditRetention = env.GetDuration("AUDIT_RETENTION", 90*24*time.Hour) // 0 keeps the records forever
+
+	// Test mode: the payments of the test client run against the sandbox cluster; disabled if TEST_CLIENT_ID is empty
+	testClientID       = env.GetString("TEST_CLIENT_ID", "")
+	testClientSecret   = env.GetString("TEST_CLIENT_SECRET", "")
+	sandboxRPCEndpoint = env.GetString("SANDBOX_SOLANA_RPC_ENDPOINT", "https://api.devnet.solana.com")
+	sandboxMints       = env.GetStrings("SANDBOX_MINTS", ",", nil) // test mints of the currency symbols, e.g. USDC:<devnet mint>
+
+	// Sandbox airdrop to fund the wallets of the integrators in test mode
+	sandboxFaucetAuthority    = env.GetString("SANDBOX_FAUCET_AUTHORITY", "") // mint authority of the SANDBOX_MINTS; only SOL is airdropped if empty
+	sandboxAirdropSOLAmount   = env.GetInt[int64]("SANDBOX_AIRDROP_SOL_AMOUNT", 1000000000)
+	sandboxAirdropTokenAmount = env.GetInt[int64]("SANDBOX_AIRDROP_TOKEN_AMOUNT", 100) // in the token units, e.g. 100 USDC
 
 	// Worker
 	workerConcurrency = env.GetInt("WORKER_CONCURRENCY", 10)
 	queueName         = env.GetString("QUEUE_NAME", "default")
 
+	// Queue monitoring: the health check fails if the queue is stuck
+	queueMonitorInterval = env.GetDuration("QUEUE_MONITOR_INTERVAL", time.Second*15)
+	queueMaxLatency      = env.GetDuration("QUEUE_MAX_LATENCY", time.Minute*5) // age of the oldest pending task; 0 disables the check
+	queueMaxRetry        = env.GetInt("QUEUE_MAX_RETRY", 0)                    // tasks waiting for retry; 0 disables the check
+	queueMaxArchived     = env.GetInt("QUEUE_MAX_ARCHIVED", 0)                 // archived (dead) tasks; 0 disables the check
+
 	// Webhook
-	webhookSignatureSecret = env.MustBytes("WEBHOOK_SIGNATURE_SECRET")
+	webhookSignatureSecret = []byte(env.GetString("WEBHOOK_SIGNATURE_SECRET", "")) // required, if not set in Vault
 	webhookURI             = env.MustString("WEBHOOK_URI")
+	webhookEvents          = env.GetStrings("WEBHOOK_EVENTS", ",", nil)         // events of WEBHOOK_URI, e.g. payment.succeeded,transaction.*; all if empty
+	webhookCallbackHosts   = env.GetStrings("WEBHOOK_CALLBACK_HOSTS", ",", nil) // allowlist of the payment callback_url hosts, e.g. hooks.example.com,*.example.com; disabled if empty
+
+	// Webhook egress: the deliveries to the disallowed URLs and addresses fail, see webhook.EgressPolicy
+	webhookAllowedSchemes  = env.GetStrings("WEBHOOK_ALLOWED_SCHEMES", ",", []string{"https", "http"})
+	webhookAllowedPorts    = env.GetStrings("WEBHOOK_ALLOWED_PORTS", ",", nil) // e.g. 443,8443; any port if empty
+	webhookBlockPrivateIPs = env.GetBool("WEBHOOK_BLOCK_PRIVATE_IPS", true)    // loopback, private, link-local and other non-public addresses
+	webhookProxyURL        = env.GetString("WEBHOOK_PROXY_URL", "")            // outbound proxy of the deliveries, e.g. http://egress-proxy:3128
+	webhookEgressIPs       = env.GetStrings("WEBHOOK_EGRESS_IPS", ",", nil)    // public IPs of the deliveries, returned by GET /webhooks/egress-ips
+
+	// Email notifications
+	emailProvider              = env.GetString("NOTIFICATIONS_EMAIL_PROVIDER", "") // smtp, sendgrid; disabled if empty
+	emailFrom                  = env.GetString("EMAIL_FROM", "")
+	merchantNotificationEmails = env.GetStrings("MERCHANT_NOTIFICATION_EMAILS", ",", nil)
+	notifyCustomers            = env.GetBool("NOTIFY_CUSTOMERS", false)
+	smtpHost                   = env.GetString("SMTP_HOST", "")
+	smtpPort                   = env.GetInt("SMTP_PORT", 587)
+	smtpUsername               = env.GetString("SMTP_USERNAME", "")
+	smtpPassword               = env.GetString("SMTP_PASSWORD", "")
+	sendGridAPIKey             = env.GetString("SENDGRID_API_KEY", "")
+
+	// Operator alerts
+	alertsSlackWebhookURL     = env.GetString("ALERTS_SLACK_WEBHOOK_URL", "")
+	alertsSlackMinSeverity    = env.GetString("ALERTS_SLACK_MIN_SEVERITY", "warning") // info, warning, critical
+	alertsTelegramBotToken    = env.GetString("ALERTS_TELEGRAM_BOT_TOKEN", "")
+	alertsTelegramChatID      = env.GetString("ALERTS_TELEGRAM_CHAT_ID", "")
+	alertsTelegramMinSeverity = env.GetString("ALERTS_TELEGRAM_MIN_SEVERITY", "critical") // info, warning, critical
+	alertsEventSeverity       = env.GetStrings("ALERTS_EVENT_SEVERITY", ",", nil)         // e.g. payment.failed:critical
+
+	// E-commerce integrations
+	shopifyShopDomain         = env.GetString("SHOPIFY_SHOP_DOMAIN", "") // e.g. my-store.myshopify.com; disabled if empty
+	shopifyAccessToken        = env.GetString("SHOPIFY_ACCESS_TOKEN", "")
+	shopifyWebhookSecret      = []byte(env.GetString("SHOPIFY_WEBHOOK_SECRET", ""))
+	wooCommerceStoreURL       = env.GetString("WOOCOMMERCE_STORE_URL", "") // e.g. https://my-store.com; disabled if empty
+	wooCommerceConsumerKey    = env.GetString("WOOCOMMERCE_CONSUMER_KEY", "")
+	wooCommerceConsumerSecret = env.GetString("WOOCOMMERCE_CONSUMER_SECRET", "")
+	wooCommerceWebhookSecret  = []byte(env.GetString("WOOCOMMERCE_WEBHOOK_SECRET", ""))
+	integrationsDecimals      = env.GetInt("INTEGRATIONS_AMOUNT_DECIMALS", 0)       // decimals of the merchant default mint, resolved on-chain if 0
+	integrationsCurrencies    = env.GetStrings("INTEGRATIONS_CURRENCIES", ",", nil) // accepted store currencies, e.g. USD
 
 	// Solana
-	solanaRPCEndpoint = env.GetString("SOLANA_RPC_ENDPOINT", "https://api.devnet.solana.com")
-	solanaWSSEndpoint = env.GetString("SOLANA_WSS_ENDPOINT", "wss://api.devnet.solana.com")
-	solanaPayBaseURI  = env.GetString("SOLANA_PAY_BASE_URI", "https://checkout-api.easypmnt.com/payment/checkout/")
+	solanaRPCEndpoint     = env.GetString("SOLANA_RPC_ENDPOINT", "https://api.devnet.solana.com")
+	solanaWSSEndpoint     = env.GetString("SOLANA_WSS_ENDPOINT", "wss://api.devnet.solana.com")
+	solanaPayBaseURI      = env.GetString("SOLANA_PAY_BASE_URI", "https://checkout-api.easypmnt.com/payment/checkout/")
+	tokenMetadataCacheTTL = env.GetDuration("TOKEN_METADATA_CACHE_TTL", time.Hour*24)
+	solanaBreakerFailures = env.GetInt("SOLANA_RPC_BREAKER_FAILURES", 5) // consecutive failed rpc calls to open the circuit breaker; 0 disables it
+	solanaBreakerCooldown = env.GetDuration("SOLANA_RPC_BREAKER_COOLDOWN", time.Second*30)
+	solanaCluster         = env.GetString("SOLANA_CLUSTER", "devnet")  // mainnet-beta, devnet, testnet
+	blockExplorer         = env.GetString("BLOCK_EXPLORER", "solscan") // solscan, solana or a URL template with {signature} and {cluster} placeholders; disabled if empty
+
+	// Chain connection readiness: the instance is not ready while the connection is degraded
+	solanaHealthInterval = env.GetDuration("SOLANA_HEALTH_CHECK_INTERVAL", time.Second*10)
+	solanaMaxSlotLag     = env.GetInt[int64]("SOLANA_MAX_SLOT_LAG", 150)          // slots behind the cluster; 0 disables the check
+	solanaMaxSlotStall   = env.GetDuration("SOLANA_MAX_SLOT_STALL", time.Minute)  // time without a new slot; 0 disables the check
+	solanaWSMaxSilence   = env.GetDuration("SOLANA_WSS_MAX_SILENCE", time.Minute) // time without a websocket message or pong
+
+	// Reference subscriptions are synced with the pending transactions, e.g. created before the listener has started
+	solanaWSReferenceSyncInterval = env.GetDuration("SOLANA_WSS_REFERENCE_SYNC_INTERVAL", time.Minute)
 
 	// Merchant
 	merchantWalletAddress      = env.MustString("MERCHANT_WALLET_ADDRESS")
 	merchantDefaultMint        = env.GetString("MERCHANT_DEFAULT_MINT", "SOL")
+	merchantWalletPool         = env.GetStrings("MERCHANT_WALLET_POOL", ",", nil)          // destination wallets pool; MERCHANT_WALLET_ADDRESS is used if empty
+	merchantWalletRotation     = env.GetString("MERCHANT_WALLET_ROTATION", "round_robin")  // round_robin, balance_threshold
+	merchantWalletThreshold    = env.GetInt[int64]("MERCHANT_WALLET_BALANCE_THRESHOLD", 0) // in minimal units of the payment mint
 	merchantApplyBonus         = env.GetBool("MERCHANT_APPLY_BONUS", true)
 	merchantMaxBonusPercentage = env.GetInt[int16]("MERCHANT_MAX_BONUS_PERCENTAGE", 5000)
 	maxApplyBonusAmount        = env.GetInt[int64]("MAX_APPLY_BONUS_AMOUNT", 10000000000)
 	bonusMintAddress           = env.GetString("BONUS_MINT_ADDRESS", "")
 	bonusMintAuthority         = env.GetString("BONUS_MINT_AUTHORITY", "")
+	bonusMintAuthoritySigner   = env.GetString("BONUS_MINT_AUTHORITY_SIGNER", "local") // local, aws_kms, gcp_kms
 	bonusRate                  = env.GetInt[int64]("BONUS_RATE", 100)
 	paymentTTL                 = env.GetDuration("PAYMENT_TTL", time.Minute*15)
+	paymentPriorityFee         = env.GetInt[int64]("PAYMENT_PRIORITY_FEE", 0)       // in lamports, quoted to the customer
+	paymentSwapSlippageBps     = env.GetInt[int64]("PAYMENT_SWAP_SLIPPAGE_BPS", 50) // 10000 = 100%, charged if the payment fee is on top
+	paymentComputeUnitLimit    = env.GetInt[int64]("PAYMENT_COMPUTE_UNIT_LIMIT", 0) // 0 = the runtime default
+	paymentComputeUnitPrice    = env.GetInt[int64]("PAYMENT_COMPUTE_UNIT_PRICE", 0) // micro-lamports, the minimum one with the auto priority fee; 0 = no priority fee
+	paymentAutoPriorityFee     = env.GetBool("PAYMENT_AUTO_PRIORITY_FEE", false)    // estimates the compute unit price from getRecentPrioritizationFees
+	paymentPriorityFeePct      = env.GetInt("PAYMENT_PRIORITY_FEE_PERCENTILE", 75)
+	paymentMaxComputeUnitPrice = env.GetInt[int64]("PAYMENT_MAX_COMPUTE_UNIT_PRICE", 0) // micro-lamports; 0 = no cap
+	paymentQuoteTTL            = env.GetDuration("PAYMENT_QUOTE_TTL", time.Second*30)
+	paymentMinTTL              = env.GetDuration("PAYMENT_MIN_TTL", time.Minute)        // minimum requested payment ttl; 0 = no minimum
+	paymentMaxTTL              = env.GetDuration("PAYMENT_MAX_TTL", time.Hour*24)       // maximum requested payment ttl; 0 = no maximum
+	paymentLateConfirmation    = env.GetDuration("PAYMENT_LATE_CONFIRMATION_WINDOW", 0) // submitted transactions are confirmed within the window after expiry; 0 = expire at once
+	paymentCommitment          = env.GetString("PAYMENT_COMMITMENT", "finalized")       // processed, confirmed, finalized; level the payment transactions must reach
+	depositMonitoring          = env.GetBool("DEPOSIT_MONITORING_ENABLED", false)
+	depositMonitoringMints     = env.GetStrings("DEPOSIT_MONITORING_MINTS", ",", nil) // symbols or mint addresses; merchant default mint if empty
+
+	// Signed payment links: the public checkout endpoints reject the tampered or forged links
+	paymentLinkSigningKey = env.GetString("PAYMENT_LINK_SIGNING_KEY", "")     // disabled if empty
+	paymentLinkTTL        = env.GetDuration("PAYMENT_LINK_TTL", time.Hour*24) // links expire with the payment or after the ttl, whichever is earlier
+
+	// Single-use payment links: only the holder of the link can generate the transaction, once
+	paymentLinkTokenTTL       = env.GetDuration("PAYMENT_LINK_TOKEN_TTL", 0)         // 0 = reusable links
+	checkoutRateLimitPerToken = env.GetInt("CHECKOUT_RATE_LIMIT_PER_LINK_TOKEN", 10) // 0 disables the limit
+
+	// Checkout WebSocket, authenticated by the short-lived checkout tokens issued to the merchant backend
+	checkoutTokenSigningKey = env.GetString("CHECKOUT_TOKEN_SIGNING_KEY", "") // disabled if empty
+	checkoutTokenTTL        = env.GetDuration("CHECKOUT_TOKEN_TTL", time.Minute*5)
+
+	// Transaction references are derived from the seed, so they can be re-derived for the reconciliation
+	paymentReferenceSeed = env.GetString("PAYMENT_REFERENCE_SEED", "") // base64, at least 16 bytes, e.g. generated by `cli new-kek`; random references if empty
+
+	// Pay-with-any-token swaps with a higher price impact must be accepted by the customer
+	paymentMaxPriceImpactBps = env.GetInt[int64]("PAYMENT_MAX_PRICE_IMPACT_BPS", 0) // 10000 = 100%; 0 = no limit
+
+	// A duplicate payment external id returns the existing payment instead of the conflict error
+	paymentIdempotentExternalID = env.GetBool("PAYMENT_IDEMPOTENT_EXTERNAL_ID", false)
+
+	// Merchant settings stored in the database override the merchant env config above
+	settingsReloadInterval = env.GetDuration("MERCHANT_SETTINGS_RELOAD_INTERVAL", time.Second*30)
+
+	// Treasury withdrawals from the merchant wallet
+	treasuryWithdrawWhitelist   = env.GetStrings("TREASURY_WITHDRAW_WHITELIST", ",", nil) // disabled if empty
+	treasuryWithdrawLimits      = env.GetStrings("TREASURY_WITHDRAW_LIMITS", ",", nil)    // mint:max_amount, e.g. SOL:1000000000,USDC:1000000000
+	treasuryHotWalletSigner     = env.GetString("TREASURY_HOT_WALLET_SIGNER", "")         // local, aws_kms, gcp_kms; unsigned transactions if empty
+	treasuryHotWalletPrivateKey = env.GetString("TREASURY_HOT_WALLET_PRIVATE_KEY", "")
+	treasuryAWSKMSKeyID         = env.GetString("TREASURY_AWS_KMS_KEY_ID", "")
+	treasuryGCPKMSKeyName       = env.GetString("TREASURY_GCP_KMS_KEY_NAME", "")
+
+	// Signers of the merchant wallet, if it's an SPL multisig account; the hot wallet must be one of them
+	treasuryMultisigSigners = env.GetStrings("TREASURY_MULTISIG_SIGNERS", ",", nil)
+
+	// Bonus token accounts freeze authority, used to freeze the accounts of fraudulent wallets
+	bonusFreezeAuthoritySigner = env.GetString("BONUS_FREEZE_AUTHORITY_SIGNER", "") // local, aws_kms, gcp_kms; freeze and thaw are disabled if empty
+	bonusFreezeAuthority       = env.GetString("BONUS_FREEZE_AUTHORITY", "")
+	bonusFreezeAWSKMSKeyID     = env.GetString("BONUS_FREEZE_AWS_KMS_KEY_ID", "")
+	bonusFreezeGCPKMSKeyName   = env.GetString("BONUS_FREEZE_GCP_KMS_KEY_NAME", "")
+	bonusCheckFrozenAccounts   = env.GetBool("BONUS_CHECK_FROZEN_ACCOUNTS", false) // bonus of a frozen account is not applied as a discount
+
+	// Spending allowances approved by the customers to the merchant delegate wallet for the recurring pulls
+	allowanceDelegateSigner     = env.GetString("ALLOWANCE_DELEGATE_SIGNER", "") // local, aws_kms, gcp_kms; allowances are disabled if empty
+	allowanceDelegatePrivateKey = env.GetString("ALLOWANCE_DELEGATE_PRIVATE_KEY", "")
+	allowanceAWSKMSKeyID        = env.GetString("ALLOWANCE_AWS_KMS_KEY_ID", "")
+	allowanceGCPKMSKeyName      = env.GetString("ALLOWANCE_GCP_KMS_KEY_NAME", "")
+
+	// Prepaid vouchers
+	voucherMints               = env.GetStrings("VOUCHER_MINTS", ",", nil) // voucher mint of the merchant wallet, or merchant_wallet:mint pairs; disabled if empty
+	voucherMintAuthority       = env.GetString("VOUCHER_MINT_AUTHORITY", "")
+	voucherMintAuthoritySigner = env.GetString("VOUCHER_MINT_AUTHORITY_SIGNER", "local") // local, aws_kms, gcp_kms
+	voucherAWSKMSKeyID         = env.GetString("VOUCHER_AWS_KMS_KEY_ID", "")
+	voucherGCPKMSKeyName       = env.GetString("VOUCHER_GCP_KMS_KEY_NAME", "")
+
+	// Escrow payments
+	escrowWalletSigner     = env.GetString("ESCROW_WALLET_SIGNER", "") // local, aws_kms, gcp_kms; escrow payments are disabled if empty
+	escrowWalletPrivateKey = env.GetString("ESCROW_WALLET_PRIVATE_KEY", "")
+	escrowAWSKMSKeyID      = env.GetString("ESCROW_AWS_KMS_KEY_ID", "")
+	escrowGCPKMSKeyName    = env.GetString("ESCROW_GCP_KMS_KEY_NAME", "")
+	escrowReleaseAfter     = env.GetDuration("ESCROW_RELEASE_AFTER", 0) // auto-release window of the held payments; manual release only if 0
+
+	// Payment deposit addresses, e.g. for the exchange withdrawals
+	paymentDepositSeed         = env.GetString("PAYMENT_DEPOSIT_SEED", "")     // base64, at least 16 bytes, not the reference seed; deposit addresses are disabled if empty
+	depositFeePayerSigner      = env.GetString("DEPOSIT_FEE_PAYER_SIGNER", "") // local, aws_kms, gcp_kms; pays the sweep fees, required with the seed
+	depositFeePayerPrivateKey  = env.GetString("DEPOSIT_FEE_PAYER_PRIVATE_KEY", "")
+	depositFeePayerAWSKMSKeyID = env.GetString("DEPOSIT_FEE_PAYER_AWS_KMS_KEY_ID", "")
+	depositFeePayerGCPKMSKey   = env.GetString("DEPOSIT_FEE_PAYER_GCP_KMS_KEY_NAME", "")
+
+	// NFT receipts minted to the payer wallets of the completed payments
+	receiptMintAuthoritySigner     = env.GetString("RECEIPT_MINT_AUTHORITY_SIGNER", "") // local, aws_kms, gcp_kms; pays the minting fees; receipts are disabled if empty
+	receiptMintAuthorityPrivateKey = env.GetString("RECEIPT_MINT_AUTHORITY_PRIVATE_KEY", "")
+	receiptAWSKMSKeyID             = env.GetString("RECEIPT_AWS_KMS_KEY_ID", "")
+	receiptGCPKMSKeyName           = env.GetString("RECEIPT_GCP_KMS_KEY_NAME", "")
+	receiptMintSeed                = env.GetString("RECEIPT_MINT_SEED", "") // base64, at least 16 bytes; derives the receipt mints, must not change
+	receiptArweaveKeyPath          = env.GetString("RECEIPT_ARWEAVE_KEY_PATH", "./arweave-key.json")
+	arweaveNodeURL                 = env.GetString("ARWEAVE_NODE_URL", "https://arweave.net")
+	receiptImageURL                = env.GetString("RECEIPT_IMAGE_URL", "")
+	receiptSymbol                  = env.GetString("RECEIPT_SYMBOL", "RCPT")
+	receiptMerchantName            = env.GetString("RECEIPT_MERCHANT_NAME", "") // PRODUCT_NAME if empty
+	receiptExternalURL             = env.GetString("RECEIPT_EXTERNAL_URL", "")
+	receiptNameTemplate            = env.GetString("RECEIPT_NAME_TEMPLATE", "")        // text/template with .OrderID, .PaymentID, .Amount, .Currency, .MerchantName
+	receiptDescriptionTemplate     = env.GetString("RECEIPT_DESCRIPTION_TEMPLATE", "") // same fields as the name template
+
+	// Auto-settlement swaps of the payments received in other mints
+	settlementSigner        = env.GetString("SETTLEMENT_SIGNER", "") // local, aws_kms, gcp_kms; the merchant wallet key, swaps are disabled if empty
+	settlementPrivateKey    = env.GetString("SETTLEMENT_PRIVATE_KEY", "")
+	settlementAWSKMSKeyID   = env.GetString("SETTLEMENT_AWS_KMS_KEY_ID", "")
+	settlementGCPKMSKeyName = env.GetString("SETTLEMENT_GCP_KMS_KEY_NAME", "")
+	settlementMint          = env.GetString("SETTLEMENT_MINT", "USDC") // symbol or mint address
+	settlementSlippageBps   = env.GetInt("SETTLEMENT_SLIPPAGE_BPS", 50)
+
+	// Report jobs, the asynchronous exports of the payment history and settlement reports
+	reportStorage        = env.GetString("REPORT_STORAGE", "") // local, s3; report jobs are disabled if empty
+	reportStorageDir     = env.GetString("REPORT_STORAGE_DIR", "./reports")
+	reportS3Bucket       = env.GetString("REPORT_S3_BUCKET", "")
+	reportS3Endpoint     = env.GetString("REPORT_S3_ENDPOINT", "") // S3-compatible storage, e.g. MinIO; AWS S3 if empty
+	reportExportPageSize = env.GetInt("REPORT_EXPORT_PAGE_SIZE", 1000)
+
+	// Exchange rates of the fiat-priced payments
+	exchangeRateProviders = env.GetStrings("EXCHANGE_RATE_PROVIDERS", ",", nil) // coingecko, pyth in the fallback order; fiat pricing is disabled if empty
+	exchangeRateCacheTTL  = env.GetDuration("EXCHANGE_RATE_CACHE_TTL", 30*time.Second)
+	coingeckoAPIURL       = env.GetString("COINGECKO_API_URL", "") // e.g. https://pro-api.coingecko.com/api/v3; public API if empty
+	coingeckoAPIKey       = env.GetString("COINGECKO_API_KEY", "")
+	pythHermesURL         = env.GetString("PYTH_HERMES_URL", "")         // public Hermes API if empty
+	pythPriceFeeds        = env.GetStrings("PYTH_PRICE_FEEDS", ",", nil) // MINT/FIAT:FEED_ID pairs, the mint is the address or the symbol, e.g. SOL/USD:0xef0d...
+	pythMaxPriceAge       = env.GetDuration("PYTH_MAX_PRICE_AGE", time.Minute)
+
+	// AWS KMS (bonus mint authority signer)
+	awsKMSKeyID        = env.GetString("AWS_KMS_KEY_ID", "")
+	awsRegion          = env.GetString("AWS_REGION", "")
+	awsAccessKeyID     = env.GetString("AWS_ACCESS_KEY_ID", "")
+	awsSecretAccessKey = env.GetString("AWS_SECRET_ACCESS_KEY", "")
+	awsSessionToken    = env.GetString("AWS_SESSION_TOKEN", "")
+
+	// GCP KMS (bonus mint authority signer)
+	gcpKMSKeyName = env.GetString("GCP_KMS_KEY_NAME", "") // projects/*/locations/*/keyRings/*/cryptoKeys/*/cryptoKeyVersions/*
+
+	// HashiCorp Vault (optional secrets provider)
+	vaultAddr        = env.GetString("VAULT_ADDR", "")
+	vaultToken       = env.GetString("VAULT_TOKEN", "")
+	vaultNamespace   = env.GetString("VAULT_NAMESPACE", "")
+	vaultKVMount     = env.GetString("VAULT_KV_MOUNT", "secret")
+	vaultSecretPath  = env.GetString("VAULT_SECRET_PATH", "")   // KV v2 secret with database_url, oauth_signing_key, client_secret, webhook_signature_secret, bonus_mint_authority
+	vaultDBCredsPath = env.GetString("VAULT_DB_CREDS_PATH", "") // e.g. database/creds/checkout-api
 )
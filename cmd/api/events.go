@@ -2,42 +2,81 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/internal/logging"
+	"github.com/easypmnt/checkout-api/websocketrpc"
 	"github.com/gorilla/websocket"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
 
-// Open a websocket connection and listen for events
-func openWebsocketConnection(ctx context.Context, endpoint string, log *logrus.Entry, eg *errgroup.Group) *websocket.Conn {
-	conn, _, err := websocket.DefaultDialer.Dial(endpoint, nil)
-	if err != nil {
-		log.WithError(err).Fatal("failed to connect to the websocket endpoint")
-	}
+// runWebsocketListener opens a websocket connection and listens for events until the context is cancelled.
+// A new connection and client are created on every call, so the listener can be restarted
+// when the instance regains the leadership.
+func runWebsocketListener(endpoint string, log *logging.Logger, opts ...websocketrpc.ClientOption) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		conn, _, err := websocket.DefaultDialer.DialContext(ctx, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("failed to connect to the websocket endpoint: %w", err)
+		}
+
+		conn.SetCloseHandler(func(code int, text string) error {
+			log.WithFields(map[string]interface{}{
+				"code": code,
+				"text": text,
+			}).Info("websocket connection closed")
+			return nil
+		})
+
+		client := websocketrpc.NewClient(conn, opts...)
+
+		eg, ctx := errgroup.WithContext(ctx)
+		eg.Go(func() error {
+			return client.Run(ctx)
+		})
+		eg.Go(func() error {
+			defer func() {
+				log.Info("websocket connection listener stopped")
+			}()
+
+			<-ctx.Done()
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
 
-	eg.Go(func() error {
-		defer func() {
-			log.Info("websocket connection listener stopped")
-		}()
+			wsCtx, wsCtxClose := context.WithTimeout(context.Background(), 5*time.Second)
+			defer wsCtxClose()
+			<-wsCtx.Done()
 
-		<-ctx.Done()
-		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+			return conn.Close()
+		})
 
-		wsCtx, wsCtxClose := context.WithTimeout(context.Background(), 5*time.Second)
-		defer wsCtxClose()
-		<-wsCtx.Done()
+		return eg.Wait()
+	}
+}
 
-		return conn.Close()
-	})
+// streamListenerEmitter emits the events to the local emitter,
+// but listens to the events delivered to the status streams, i.e. from all instances if the fanout is enabled.
+type streamListenerEmitter struct {
+	events.Emitter
+	stream events.Emitter
+}
 
-	conn.SetCloseHandler(func(code int, text string) error {
-		log.WithFields(logrus.Fields{
-			"code": code,
-			"text": text,
-		}).Info("websocket connection closed")
-		return nil
-	})
+// On registers a listener for the given event name on the stream emitter.
+func (e streamListenerEmitter) On(name events.EventName, listeners ...events.Listener) {
+	e.stream.On(name, listeners...)
+}
 
-	return conn
+// runLeaderTasks runs the singleton tasks until the context is cancelled or any of them fails.
+func runLeaderTasks(tasks ...func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		eg, ctx := errgroup.WithContext(ctx)
+		for _, task := range tasks {
+			task := task
+			eg.Go(func() error {
+				return task(ctx)
+			})
+		}
+		return eg.Wait()
+	}
 }
@@ -4,23 +4,34 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"strconv"
 
+	"github.com/easypmnt/checkout-api/health"
+	"github.com/easypmnt/checkout-api/internal/logging"
+	"github.com/easypmnt/checkout-api/internal/metrics"
+	"github.com/easypmnt/checkout-api/internal/realip"
 	"github.com/easypmnt/checkout-api/internal/recoverer"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
-	"github.com/sirupsen/logrus"
+	"github.com/go-chi/oauth"
 )
 
 // Init HTTP router
-func initRouter(log *logrus.Entry) *chi.Mux {
+// The health and readiness endpoints respond with 503 status if any of their checks fails.
+// The /healthz and /readyz probes verify the dependencies on every call and report the status of each one.
+// The client IP headers are honored only if the request comes from one of the trusted proxies.
+func initRouter(log *logging.Logger, healthChecks, readinessChecks []func() error, liveness, readiness *health.Checker, trustedProxies []*net.IPNet) *chi.Mux {
 	r := chi.NewRouter()
 
 	r.Use(
-		// recoverer.WithLogger(log),
-		recoverer.WithLogger(nil),
+		realip.Middleware(trustedProxies),
+		middleware.RequestID,
+		// the access log with the request, client and payment IDs; the panics are logged with them too
+		logging.RequestLogger(log, "/health", "/ready", "/healthz", "/readyz", metricsPath),
+		recoverer.WithLogger(log),
 		middleware.AllowContentType(
 			"application/json",
 			"application/x-www-form-urlencoded",
@@ -30,8 +41,6 @@ func initRouter(log *logrus.Entry) *chi.Mux {
 		middleware.StripSlashes,
 		middleware.GetHead,
 		middleware.NoCache,
-		middleware.RealIP,
-		middleware.RequestID,
 
 		// Basic CORS
 		// for more ideas, see: https://developer.github.com/v3/#cross-origin-resource-sharing
@@ -51,13 +60,19 @@ func initRouter(log *logrus.Entry) *chi.Mux {
 	r.MethodNotAllowed(methodNotAllowedHandler)
 
 	r.Get("/", mkRootHandler(buildTagRuntime))
-	r.Get("/health", healthCheckHandler)
+	r.Get("/health", mkHealthCheckHandler(healthChecks...))
+	r.Get("/ready", mkHealthCheckHandler(readinessChecks...))
+	r.Get("/healthz", liveness.Handler())
+	r.Get("/readyz", readiness.Handler())
+	if metricsPath != "" {
+		r.Method(http.MethodGet, metricsPath, metrics.Handler())
+	}
 
 	return r
 }
 
 // Run HTTP server
-func runServer(ctx context.Context, httpPort int, router http.Handler, log *logrus.Entry) func() error {
+func runServer(ctx context.Context, httpPort int, router http.Handler, log *logging.Logger) func() error {
 	return func() error {
 		log = log.WithField("port", httpPort)
 		log.Info("Starting HTTP server")
@@ -85,9 +100,35 @@ func runServer(ctx context.Context, httpPort int, router http.Handler, log *logr
 	}
 }
 
-// returns 204 HTTP status without content
-func healthCheckHandler(w http.ResponseWriter, _ *http.Request) {
-	w.WriteHeader(http.StatusNoContent)
+// returns 204 HTTP status without content, or 503 HTTP status with the failed checks
+func mkHealthCheckHandler(checks ...func() error) func(w http.ResponseWriter, _ *http.Request) {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		var errs []string
+		for _, check := range checks {
+			if err := check(); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+		if len(errs) > 0 {
+			defaultResponse(w, http.StatusServiceUnavailable, map[string]interface{}{
+				"code":   http.StatusServiceUnavailable,
+				"error":  http.StatusText(http.StatusServiceUnavailable),
+				"checks": errs,
+			})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// adds the client ID of the authorized request to the log fields, see logging.RequestLogger
+func logClientID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, _ := r.Context().Value(oauth.CredentialContext).(string)
+		logging.SetContextField(r.Context(), logging.ClientIDKey, clientID)
+		next.ServeHTTP(w, r)
+	})
 }
 
 // returns 404 HTTP status with payload
@@ -143,3 +184,11 @@ func testingMdw(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// withRole chains the role or the scope check after the authorization middleware,
+// so the claims and the scope of the authorized token are in the request context.
+func withRole(authMdw, roleMdw func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return authMdw(roleMdw(next))
+	}
+}
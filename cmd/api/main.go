@@ -3,51 +3,119 @@ package main
 import (
 	"context"
 	"database/sql"
+	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/easypmnt/checkout-api/allowances"
+	"github.com/easypmnt/checkout-api/apikeys"
+	"github.com/easypmnt/checkout-api/audit"
 	"github.com/easypmnt/checkout-api/auth"
+	"github.com/easypmnt/checkout-api/bonus"
+	"github.com/easypmnt/checkout-api/deposits"
+	"github.com/easypmnt/checkout-api/disputes"
 	"github.com/easypmnt/checkout-api/events"
-	"github.com/easypmnt/checkout-api/internal/kitlog"
+	"github.com/easypmnt/checkout-api/eventsink"
+	"github.com/easypmnt/checkout-api/funnel"
+	"github.com/easypmnt/checkout-api/health"
+	"github.com/easypmnt/checkout-api/integrations"
+	"github.com/easypmnt/checkout-api/internal/leader"
+	"github.com/easypmnt/checkout-api/internal/logging"
+	"github.com/easypmnt/checkout-api/internal/realip"
 	"github.com/easypmnt/checkout-api/jupiter"
+	"github.com/easypmnt/checkout-api/notifications"
+	"github.com/easypmnt/checkout-api/outbox"
+	"github.com/easypmnt/checkout-api/partitions"
 	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/queues"
+	"github.com/easypmnt/checkout-api/receipts"
+	"github.com/easypmnt/checkout-api/reports"
 	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/roles"
+	"github.com/easypmnt/checkout-api/sandbox"
 	"github.com/easypmnt/checkout-api/server"
+	"github.com/easypmnt/checkout-api/settings"
+	"github.com/easypmnt/checkout-api/settlements"
 	"github.com/easypmnt/checkout-api/solana"
+	"github.com/easypmnt/checkout-api/sse"
+	"github.com/easypmnt/checkout-api/subscriptions"
+	"github.com/easypmnt/checkout-api/timeline"
+	"github.com/easypmnt/checkout-api/treasury"
+	"github.com/easypmnt/checkout-api/vouchers"
 	"github.com/easypmnt/checkout-api/webhook"
+	"github.com/easypmnt/checkout-api/websocketrpc"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/oauth"
+	"github.com/go-redis/redis/v8"
 	"github.com/hibiken/asynq"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 
-	_ "github.com/lib/pq" // init pg driver
+	_ "github.com/easypmnt/checkout-api/repository/mysql" // register mysql storage backend
+	_ "github.com/lib/pq"                                 // init pg driver
 )
 
 func main() {
 	// Init logger
-	logrus.SetReportCaller(false)
-	logger := logrus.WithFields(logrus.Fields{
-		"app":       appName,
-		"build_tag": buildTagRuntime,
+	if logLevel == "" && appDebug {
+		logLevel = "debug"
+	}
+	logger, err := logging.New(logging.Config{
+		Level:        logLevel,
+		Format:       logFormat,
+		ModuleLevels: logModuleLevels,
+		Fields: map[string]interface{}{
+			"app":       appName,
+			"build_tag": buildTagRuntime,
+		},
+		Sampling: logging.SamplingConfig{
+			Initial:    logSamplingInitial,
+			Thereafter: logSamplingThereafter,
+			Tick:       logSamplingTick,
+		},
+		RedactFields: append(append([]string{}, logging.DefaultRedactFields...), logRedactFields...),
 	})
-	if appDebug {
-		logger.Logger.SetLevel(logrus.DebugLevel)
-	} else {
-		logger.Logger.SetLevel(logrus.InfoLevel)
+	if err != nil {
+		log.Fatalf("failed to init logger: %v", err)
 	}
+	// the packages logging via the standard library logger
+	stdLogWriter := logger.Module("stdlog").Writer()
+	defer stdLogWriter.Close()
+	log.SetFlags(0)
+	log.SetOutput(stdLogWriter)
 
 	defer func() { logger.Info("server successfully shutdown") }()
 
 	// Errgroup with context
 	eg, ctx := errgroup.WithContext(newCtx(logger))
 
-	// Init DB connection
-	db, err := sql.Open("postgres", dbConnString)
+	// Load secrets from Vault, if configured
+	secretsProvider, err := loadSecretsFromVault(ctx, logger.Module("vault"))
 	if err != nil {
-		logger.WithError(err).Fatal("failed to init db connection")
+		logger.WithError(err).Fatal("failed to load secrets from vault")
+	}
+	if err := validateSecrets(); err != nil {
+		logger.WithError(err).Fatal("missing required secret")
+	}
+
+	// Init DB connection
+	var db *sql.DB
+	if secretsProvider != nil {
+		// the dynamic credentials are issued for the postgres connector only
+		if dbDriver != repository.DefaultBackend {
+			logger.Fatalf("vault database credentials are not supported by %s storage backend", dbDriver)
+		}
+		db = sql.OpenDB(vaultDBConnector{dsn: dbConnString, provider: secretsProvider})
+		eg.Go(func() error {
+			return secretsProvider.Run(ctx)
+		})
+	} else {
+		db, err = sql.Open(dbDriver, dbConnString)
+		if err != nil {
+			logger.WithError(err).Fatal("failed to init db connection")
+		}
 	}
 	defer db.Close()
 
@@ -59,13 +127,11 @@ func main() {
 	}
 
 	// Init repository
-	repo, err := repository.Prepare(ctx, db)
+	repo, err := repository.Open(ctx, dbDriver, db)
 	if err != nil {
 		logger.WithError(err).Fatal("failed to init repository")
 	}
-
-	// Init event emitter
-	eventEmitter := events.NewEmitter(logger)
+	defer repo.Close()
 
 	// Redis connect options for asynq client
 	redisConnOpt, err := asynq.ParseRedisURI(redisConnString)
@@ -77,60 +143,343 @@ func main() {
 	asynqClient := asynq.NewClient(redisConnOpt)
 	defer asynqClient.Close()
 
+	// Queue stats metrics and health check
+	asynqInspector := asynq.NewInspector(redisConnOpt)
+	defer asynqInspector.Close()
+	queueMonitor := queues.NewMonitor(
+		asynqInspector, []string{queueName}, queueMonitorInterval, logger.Module("queues"),
+		queues.WithMaxLatency(queueMaxLatency),
+		queues.WithMaxRetry(queueMaxRetry),
+		queues.WithMaxArchived(queueMaxArchived),
+	)
+
+	// Init redis client for the events bus, the fanout and the leader election
+	redisClient, ok := redisConnOpt.MakeRedisClient().(redis.UniversalClient)
+	if !ok {
+		logger.Fatal("failed to create redis client")
+	}
+	defer redisClient.Close()
+
+	// Init event emitter. With the bus enabled, the events emitted on any instance
+	// are handled by the listeners of one of them.
+	var eventEmitter events.Emitter = events.NewEmitter(logger.Module("events"))
+	var eventsBus *events.RedisBus
+	if eventsBusEnabled {
+		eventsBus = events.NewRedisBus(redisClient, logger.Module("events"),
+			events.WithBusStream(eventsBusStream),
+			events.WithBusConsumerGroup(eventsBusGroup),
+			events.WithBusMaxLen(int64(eventsBusMaxLen)),
+		)
+		eventEmitter = eventsBus
+	}
+
+	// Events delivered to the status streams of this instance.
+	// With the fanout enabled, they include the events emitted on other instances.
+	var streamEmitter events.Emitter = eventEmitter
+	var eventsFanout *events.RedisFanout
+	if eventsFanoutEnabled {
+		eventsFanout = events.NewRedisFanout(eventEmitter, redisClient, eventsFanoutChannel, logger.Module("events"))
+		streamEmitter = eventsFanout
+	}
+
+	// Scheduled tasks
+	schedulers := []schedulerHandler{payments.NewScheduler(), auth.NewScheduler(), subscriptions.NewScheduler()}
+	if dbPartitioningEnabled {
+		schedulers = append(schedulers, partitions.NewScheduler())
+	}
+
+	// Audit trail of the authenticated calls
+	var auditService *audit.Service
+	if auditEnabled {
+		auditService = audit.NewService(repo, logger.Module("audit"),
+			audit.WithRetention(auditRetention),
+			audit.WithMaxPayloadSize(httpLimitRequestBodySize),
+		)
+		schedulers = append(schedulers, audit.NewScheduler())
+	}
+
+	// Singleton tasks which must run on a single instance at a time
+	leaderTasks := []func(ctx context.Context) error{
+		runScheduler(redisConnOpt, logger.Module("scheduler"), schedulers...),
+	}
+
 	// Init Solana client
 	solClient := solana.NewClient(
 		solana.WithRPCEndpoint(solanaRPCEndpoint),
+		solana.WithMintDecimalsStore(mintDecimalsStore{repo: repo}),
+		solana.WithCircuitBreaker(solanaBreakerFailures, solanaBreakerCooldown),
 	)
 
+	// Chain connection health for the readiness probe
+	rpcHealth := solana.NewRPCHealth(solClient, solanaRPCEndpoint, solanaHealthInterval, uint64(solanaMaxSlotLag), solanaMaxSlotStall)
+	wsHealth := websocketrpc.NewHealth(solanaWSMaxSilence)
+
+	// Block explorer links of the transaction signatures
+	var explorer *solana.Explorer
+	if blockExplorer != "" {
+		explorer, err = solana.NewExplorer(blockExplorer, solanaCluster)
+		if err != nil {
+			logger.WithError(err).Fatal("failed to init block explorer")
+		}
+	}
+
+	// Token metadata cache
+	tokenMetadataCache := solana.NewTokenMetadataCache(solClient, tokenMetadataCacheTTL)
+
 	// Init Jupiter client
 	jupiterClient := jupiter.NewClient()
 
+	// Kubernetes probes: the liveness one verifies the storage and the queues,
+	// the readiness one the storage and the chain connection
+	dbCheck := health.WithCheck(dbDriver, db.PingContext)
+	redisCheck := health.WithCheck("redis", func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	})
+	livenessChecker := health.NewChecker(
+		health.WithTimeout(healthCheckTimeout),
+		dbCheck, redisCheck,
+		health.WithCheck("queues", health.Passive(queueMonitor.Check)),
+	)
+	readinessOpts := []health.Option{
+		health.WithTimeout(healthCheckTimeout),
+		dbCheck, redisCheck,
+		health.WithCheck("solana_rpc", health.All(
+			func(ctx context.Context) error {
+				_, err := solClient.GetSlot(ctx)
+				return err
+			},
+			health.Passive(rpcHealth.Check), // the slot lag and stall of the background check
+		)),
+	}
+	if solanaWSSEndpoint != "" {
+		readinessOpts = append(readinessOpts, health.WithCheck("solana_websocket", func(ctx context.Context) error {
+			return wsHealth.Probe(ctx, solanaWSSEndpoint)
+		}))
+	}
+	readinessChecker := health.NewChecker(readinessOpts...)
+
 	// Init HTTP router
-	r := initRouter(logger)
+	trustedProxies, err := realip.ParseCIDRs(httpTrustedProxies)
+	if err != nil {
+		logger.WithError(err).Fatal("invalid HTTP_TRUSTED_PROXIES")
+	}
+	r := initRouter(logger.Module("http"),
+		[]func() error{queueMonitor.Check},
+		[]func() error{rpcHealth.Check, wsHealth.Check},
+		livenessChecker, readinessChecker,
+		trustedProxies,
+	)
 
-	// OAuth2 Middleware
+	// OAuth2 Middleware, the authorized calls are recorded to the audit trail, if it's enabled
 	oauthMdw := oauth.Authorize(oauthSigningKey, nil)
+	if apiKeysEnabled {
+		// the static API keys are accepted along with the access tokens
+		oauthMdw = auth.AuthorizeAPIKeys(repo, oauthMdw)
+	}
+	// the client ID of the authorized calls is added to the request log fields
+	authorized := oauthMdw
+	oauthMdw = func(next http.Handler) http.Handler {
+		return authorized(logClientID(next))
+	}
+	if auditService != nil {
+		authorize := oauthMdw
+		oauthMdw = func(next http.Handler) http.Handler {
+			return authorize(auditService.Middleware(next))
+		}
+	}
+
+	// The admin routes accept the operator tokens issued by the OIDC login as well,
+	// the rest of the routes accept the client credentials tokens only.
+	adminMdw := oauthMdw
+	oauthMdw = func(next http.Handler) http.Handler {
+		return adminMdw(auth.ClientTokensOnly(next))
+	}
+
+	// Role checks of the mutations, the reads are allowed for any role
+	operatorMdw := withRole(oauthMdw, auth.RequireRoleForWrites(auth.RoleOwner, auth.RoleOperator))
+	supportMdw := withRole(oauthMdw, auth.RequireRoleForWrites(auth.RoleOwner, auth.RoleOperator, auth.RoleSupport))
+	ownerMdw := withRole(oauthMdw, auth.RequireRoleForWrites(auth.RoleOwner))
+
+	// Scope checks of the route groups, the tokens issued without the scope parameter are limited by the role only
+	paymentsScope := auth.RequireScope(auth.ScopePaymentsRead, auth.ScopePaymentsWrite)
+	paymentsMdw := withRole(operatorMdw, paymentsScope)
+	disputesMdw := withRole(supportMdw, paymentsScope)
+	subscriptionsMdw := withRole(operatorMdw, paymentsScope)
+	treasuryMdw := withRole(operatorMdw, auth.RequireScope(auth.ScopeTreasuryRead, auth.ScopeTreasuryWrite))
+	reportsMdw := withRole(operatorMdw, auth.RequireScope(auth.ScopeReportsRead, auth.ScopeReportsWrite))
+	settingsMdw := withRole(ownerMdw, auth.RequireScope(auth.ScopeSettingsRead, auth.ScopeSettingsWrite))
+	webhooksMdw := withRole(ownerMdw, auth.RequireScope(auth.ScopeWebhooksManage, auth.ScopeWebhooksManage))
 
-	// webhook enqueuer
-	webhookEnqueuer := webhook.NewEnqueuer(asynqClient)
+	// Webhook egress policy of the merchant webhook and of the webhook destinations
+	webhookEgress, err := newWebhookEgressPolicy()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init webhook egress policy")
+	}
+	if err := webhookEgress.CheckURL(webhookURI); err != nil {
+		logger.WithError(err).Fatal("WEBHOOK_URI is not allowed by the webhook egress policy")
+	}
+
+	// webhook enqueuer, the events are fired to the merchant webhook and to the subscribed webhook destinations
+	if err := webhook.ValidateEvents(webhookEvents); err != nil {
+		logger.WithError(err).Fatal("invalid WEBHOOK_EVENTS")
+	}
+	webhookDestinations := webhook.NewDestinations(repo, webhook.WithURLCheck(webhookEgress.CheckURL))
+	webhookEnqueuer := webhook.NewEnqueuer(asynqClient,
+		webhook.WithWebhookEvents(webhookEvents),
+		webhook.WithDestinations(webhookDestinations),
+	)
 
 	// Payment worker enqueuer
 	paymentEnqueuer := payments.NewEnqueuer(asynqClient)
 
-	// Setup event listener
-	// wsConn := openWebsocketConnection(ctx, solanaWSSEndpoint, logger, eg)
-	// websocketrpcClient := websocketrpc.NewClient(wsConn,
-	// 	websocketrpc.WithEventsEmitter(eventEmitter),
-	// )
+	// Bonus mint authority signer
+	bonusAuthority, err := newBonusAuthoritySigner(ctx)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init bonus mint authority signer")
+	}
+
+	// Prepaid vouchers
+	voucherService, err := newVoucherService(ctx, solClient, logger.Module("vouchers"))
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init voucher service")
+	}
+
+	// Escrow wallet signer
+	escrowSigner, err := newEscrowSigner(ctx)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init escrow wallet signer")
+	}
+
+	// Destination wallets pool
+	walletSelector, err := payments.NewWalletSelector(
+		merchantWalletRotation, merchantWalletPool, uint64(merchantWalletThreshold), solClient,
+	)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init destination wallet selector")
+	}
+
+	// Test-mode payments
+	sandboxOpts, sandboxWorkerOpts, err := newSandboxOptions()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init test mode")
+	}
+
+	// Commitment level the payment transactions must reach to be confirmed
+	commitment, err := solana.ParseCommitment(paymentCommitment)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to parse payment commitment")
+	}
+
+	// Signed payment links
+	var linkSigner *payments.LinkSigner
+	if paymentLinkSigningKey != "" {
+		linkSigner = payments.NewLinkSigner([]byte(paymentLinkSigningKey), paymentLinkTTL)
+	}
+
+	// Checkout tokens of the checkout WebSocket
+	var checkoutTokens *server.CheckoutTokens
+	if checkoutTokenSigningKey != "" {
+		checkoutTokens = server.NewCheckoutTokens([]byte(checkoutTokenSigningKey), checkoutTokenTTL)
+	}
+
+	// Transaction references derived from the server seed
+	referenceDeriver, err := newReferenceDeriver()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init transaction reference deriver")
+	}
+
+	// Payment deposit addresses derived from the server seed
+	depositDeriver, err := newDepositDeriver()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init deposit address deriver")
+	}
+	depositFeePayer, err := newDepositFeePayerSigner(ctx)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init deposit fee payer signer")
+	}
+
+	// Exchange rates of the fiat-priced payments
+	rateProvider, err := newRateProvider()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init exchange rate provider")
+	}
+	paymentOpts := sandboxOpts
+	if rateProvider != nil {
+		paymentOpts = append(paymentOpts, payments.WithExchangeRates(rateProvider))
+	}
 
 	var paymentService payments.PaymentService
 	// Payment service
-	paymentService = payments.NewService(
+	paymentCore := payments.NewService(
 		repo, solClient, jupiterClient,
 		payments.Config{
-			ApplyBonus:           merchantApplyBonus,
-			BonusMintAddress:     bonusMintAddress,
-			BonusAuthAccount:     bonusMintAuthority,
-			MaxApplyBonusAmount:  uint64(maxApplyBonusAmount),
-			MaxApplyBonusPercent: uint16(merchantMaxBonusPercentage),
-			AccrueBonus:          bonusRate > 0,
-			AccrueBonusRate:      uint64(bonusRate),
-			DestinationMint:      merchantDefaultMint,
-			DestinationWallet:    merchantWalletAddress,
-			PaymentTTL:           paymentTTL,
-			SolPayBaseURL:        solanaPayBaseURI,
+			ApplyBonus:            merchantApplyBonus,
+			BonusMintAddress:      bonusMintAddress,
+			BonusAuthority:        bonusAuthority,
+			MaxApplyBonusAmount:   uint64(maxApplyBonusAmount),
+			MaxApplyBonusPercent:  uint16(merchantMaxBonusPercentage),
+			CheckFrozenBonus:      bonusCheckFrozenAccounts,
+			AccrueBonus:           bonusRate > 0,
+			AccrueBonusRate:       uint64(bonusRate),
+			DestinationMint:       merchantDefaultMint,
+			DestinationWallet:     merchantWalletAddress,
+			WalletSelector:        walletSelector,
+			PaymentTTL:            paymentTTL,
+			MinPaymentTTL:         paymentMinTTL,
+			MaxPaymentTTL:         paymentMaxTTL,
+			LateConfirmation:      paymentLateConfirmation,
+			SolPayBaseURL:         solanaPayBaseURI,
+			LinkSigner:            linkSigner,
+			LinkTokenTTL:          paymentLinkTokenTTL,
+			ReferenceDeriver:      referenceDeriver,
+			PriorityFee:           uint64(paymentPriorityFee),
+			ComputeUnitLimit:      uint32(paymentComputeUnitLimit),
+			ComputeUnitPrice:      uint64(paymentComputeUnitPrice),
+			AutoPriorityFee:       paymentAutoPriorityFee,
+			PriorityFeePercentile: paymentPriorityFeePct,
+			MaxComputeUnitPrice:   uint64(paymentMaxComputeUnitPrice),
+			SwapSlippageBps:       uint16(paymentSwapSlippageBps),
+			MaxPriceImpactBps:     uint16(paymentMaxPriceImpactBps),
+			QuoteTTL:              paymentQuoteTTL,
+			VoucherMints:          voucherService.Mints(),
+			EscrowSigner:          escrowSigner,
+			EscrowReleaseAfter:    escrowReleaseAfter,
+			DepositDeriver:        depositDeriver,
+			DepositFeePayer:       depositFeePayer,
+			Commitment:            commitment,
+			CallbackHosts:         webhookCallbackHosts,
+			IdempotentExternalID:  paymentIdempotentExternalID,
 		},
+		paymentOpts...,
 	)
-	// Events decorator
-	paymentService = payments.NewServiceEvents(paymentService, eventEmitter.Emit)
-	// Logging decorator
-	paymentService = payments.NewServiceLogger(paymentService, logger)
+	// Events, metrics and logging decorators
+	paymentMiddlewares := []payments.MiddlewareOption{
+		payments.WithEvents(eventEmitter.Emit),
+		payments.WithMetrics(),
+		payments.WithLogging(logger.Module("payments")),
+	}
+	if eventsOutboxEnabled {
+		// the status events are stored along with the updates and fired by the leader after the commit
+		paymentMiddlewares = append(paymentMiddlewares, payments.WithOutbox(outbox.New(repo)))
+		leaderTasks = append(leaderTasks, outbox.NewPoller(repo, eventEmitter.Emit, logger.Module("outbox"),
+			outbox.WithPollInterval(eventsOutboxPollInterval),
+			outbox.WithRetention(eventsOutboxRetention),
+		).Run)
+	}
+	paymentService = payments.NewServiceChain(paymentCore, paymentMiddlewares...)
 
-	// Init sse service
-	// sseService := sse.NewService(sse.NewMemStorage())
+	// Merchant settings, stored in the database and applied without restart.
+	// The env config is used until the settings are stored.
+	settingsService := settings.NewService(repo, paymentCore, paymentCore.Settings(), settingsReloadInterval, logger.Module("settings"))
+	if err := settingsService.Reload(ctx); err != nil {
+		logger.WithError(err).Fatal("failed to load merchant settings")
+	}
 
 	// Event listener
 	eventEmitter.On(events.TransactionUpdated, payments.UpdateTransactionStatusListener(paymentService))
+	if depositDeriver != nil {
+		eventEmitter.On(events.TransactionUpdated, payments.SweepDepositAddressesListener(paymentEnqueuer))
+	}
 	eventEmitter.On(events.TransactionCreated, payments.TransactionCreatedListener(paymentService, paymentEnqueuer))
 	eventEmitter.On(
 		events.TransactionReferenceNotification,
@@ -140,13 +489,256 @@ func main() {
 		webhook.TranslateEventsToWebhookEvents(webhookEnqueuer),
 		events.AllEvents...,
 	)
-	// eventEmitter.ListenEvents(
-	// 	sse.TranslateEventsToSSEChannel(sseService),
-	// 	events.AllEvents...,
-	// )
+	if len(webhookCallbackHosts) > 0 {
+		// per-payment callback URLs receive the events of their payments
+		eventEmitter.ListenEvents(
+			webhook.TranslateEventsToCallbacks(webhookEnqueuer, paymentCallbackResolver(ctx, paymentService)),
+			events.AllEvents...,
+		)
+	}
+	// Payment disputes
+	disputesService := disputes.NewService(repo, eventEmitter.Emit)
+
+	// Recurring payments, the installments are billed with the payment service
+	subscriptionsService := subscriptions.NewService(repo, paymentService, eventEmitter.Emit)
+	eventEmitter.ListenEvents(subscriptions.Listener(subscriptionsService), subscriptions.Events()...)
+	eventEmitter.On(events.SubscriptionInstallmentDue, webhook.TranslateEventsToWebhookEvents(webhookEnqueuer))
+	eventEmitter.On(events.SubscriptionInstallmentPaid, webhook.TranslateEventsToWebhookEvents(webhookEnqueuer))
+	eventEmitter.On(events.SubscriptionInstallmentMissed, webhook.TranslateEventsToWebhookEvents(webhookEnqueuer))
+
+	// Checkout conversion funnel
+	funnelService := funnel.NewService(repo)
+	eventEmitter.ListenEvents(funnel.Listener(funnelService), funnel.Events()...)
+
+	// Payment events timeline for support debugging
+	timelineService := timeline.NewService(repo)
+	eventEmitter.ListenEvents(timeline.Listener(timelineService), timeline.Events()...)
+
+	// Webhook delivery
+	webhookService := webhook.NewService(
+		webhook.WithEgressPolicy(webhookEgress),
+		webhook.WithSignatureSecret(webhookSignatureSecret),
+		webhook.WithWebhookURI(webhookURI),
+		webhook.WithExplorer(explorer),
+		webhook.WithLivemodeResolver(paymentLivemodeResolver(ctx, paymentService)),
+	)
+
+	// Queue task handlers
+	queueHandlers := []taskHandler{
+		payments.NewWorker(
+			paymentService, solClient, paymentEnqueuer,
+			append(sandboxWorkerOpts, payments.WithCommitment(commitment))...,
+		),
+		auth.NewWorker(repo),
+		webhook.NewWorker(webhookService, webhook.WithEvents(eventEmitter.Emit)),
+		subscriptions.NewWorker(subscriptionsService),
+	}
+	if dbPartitioningEnabled {
+		queueHandlers = append(queueHandlers, partitions.NewWorker(repo, dbPartitionsAhead))
+	}
+	if auditService != nil {
+		queueHandlers = append(queueHandlers, audit.NewWorker(auditService))
+	}
+	if eventSink != "" {
+		sinkPublisher, err := eventsink.NewPublisher(eventSink, eventSinkURL)
+		if err != nil {
+			logger.WithError(err).Fatal("failed to init event sink")
+		}
+		sinkTopics, err := eventsink.ParseTopics(eventSinkTopics)
+		if err != nil {
+			logger.WithError(err).Fatal("failed to parse event sink topics")
+		}
+		eventEmitter.ListenEvents(eventsink.Listener(eventsink.NewEnqueuer(asynqClient), sinkTopics), eventsink.Events...)
+		queueHandlers = append(queueHandlers, eventsink.NewWorker(sinkPublisher))
+	}
+
+	// Report jobs
+	reportStore, err := newReportStorage()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init report storage")
+	}
+	reportOpts := []reports.ServiceOption{reports.WithExportPageSize(reportExportPageSize)}
+	if reportStore != nil {
+		reportOpts = append(reportOpts,
+			reports.WithStorage(reportStore),
+			reports.WithEnqueuer(reports.NewEnqueuer(asynqClient)),
+		)
+		eventEmitter.On(events.ReportCompleted, webhook.TranslateEventsToWebhookEvents(webhookEnqueuer))
+		eventEmitter.On(events.ReportFailed, webhook.TranslateEventsToWebhookEvents(webhookEnqueuer))
+	}
+	reportsService := reports.NewService(repo, reportOpts...)
+	if reportStore != nil {
+		queueHandlers = append(queueHandlers, reports.NewWorker(reportsService, eventEmitter.Emit))
+	}
+
+	// Email notifications
+	emailSender, err := newEmailSender()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init email sender")
+	}
+	if emailSender != nil {
+		eventEmitter.ListenEvents(
+			notifications.PaymentEmailsListener(notifications.NewEnqueuer(asynqClient)),
+			events.PaymentSucceeded, events.PaymentFailed,
+		)
+		queueHandlers = append(queueHandlers, notifications.NewWorker(
+			notifications.NewService(emailSender, emailFrom,
+				notifications.WithProductName(productName),
+				notifications.WithMerchantEmails(merchantNotificationEmails...),
+				notifications.WithCustomerNotifications(notifyCustomers),
+				notifications.WithMintDecimals(solClient),
+			),
+			paymentService,
+		))
+	}
+
+	// NFT receipts of the completed payments
+	receiptsService, err := newReceiptsService(ctx, solClient, commitment)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init receipts service")
+	}
+	if receiptsService != nil {
+		eventEmitter.On(events.PaymentSucceeded, receipts.PaymentSucceededListener(receipts.NewEnqueuer(asynqClient)))
+		queueHandlers = append(queueHandlers, receipts.NewWorker(receiptsService, paymentService))
+	}
+
+	// Settlement swaps of the payments received in other mints
+	settlementsService, err := newSettlementsService(ctx, repo, solClient, jupiterClient, commitment)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init settlements service")
+	}
+	if settlementsService != nil {
+		settlementEnqueuer := settlements.NewEnqueuer(asynqClient)
+		eventEmitter.On(events.PaymentSucceeded, settlements.PaymentSettledListener(settlementEnqueuer))
+		eventEmitter.On(events.PaymentReleased, settlements.PaymentSettledListener(settlementEnqueuer))
+		queueHandlers = append(queueHandlers, settlements.NewWorker(settlementsService, paymentService))
+	}
+	// Operator alerts
+	alertsService, err := newAlertsService()
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init alerts service")
+	}
+	if alertsService.Enabled() {
+		eventEmitter.ListenEvents(alertsService.Listener(), alertsService.Events()...)
+	}
+	// E-commerce integrations
+	var integrationOpts []integrations.ServiceOption
+	if shopifyShopDomain != "" {
+		integrationOpts = append(integrationOpts, integrations.WithShopify(
+			integrations.NewShopifyClient(shopifyShopDomain, shopifyAccessToken),
+		))
+	}
+	if wooCommerceStoreURL != "" {
+		integrationOpts = append(integrationOpts, integrations.WithWooCommerce(
+			integrations.NewWooCommerceClient(wooCommerceStoreURL, wooCommerceConsumerKey, wooCommerceConsumerSecret),
+		))
+	}
+	// Resolve decimals of the merchant default mint, if they are not set explicitly
+	if len(integrationOpts) > 0 && integrationsDecimals <= 0 {
+		decimals, err := solClient.GetMintDecimals(ctx, payments.MintAddress(merchantDefaultMint, payments.SOL))
+		if err != nil {
+			logger.WithError(err).Fatal("failed to get merchant default mint decimals")
+		}
+		integrationsDecimals = int(decimals)
+	}
+	integrationsService := integrations.NewService(paymentService, append(integrationOpts,
+		integrations.WithDecimals(uint8(integrationsDecimals)),
+		integrations.WithCurrencies(integrationsCurrencies...),
+	)...)
+	if len(integrationOpts) > 0 {
+		eventEmitter.ListenEvents(
+			integrations.SyncOrderStatusListener(integrations.NewEnqueuer(asynqClient)),
+			integrations.OrderStatusEvents...,
+		)
+		queueHandlers = append(queueHandlers, integrations.NewWorker(integrationsService, paymentService))
+	}
+	// Treasury withdrawals
+	treasuryService, err := newTreasuryService(ctx, solClient, logger.Module("treasury"))
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init treasury service")
+	}
+	// Spending allowances for the recurring pulls
+	allowanceDelegate, err := newAllowanceDelegateSigner(ctx)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init allowance delegate signer")
+	}
+	// Sandbox airdrop of the test mode
+	sandboxService, err := newSandboxService(logger.Module("sandbox"))
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init sandbox service")
+	}
+	// Bonus token accounts freeze and thaw
+	bonusFreezeAuthority, err := newBonusFreezeAuthoritySigner(ctx)
+	if err != nil {
+		logger.WithError(err).Fatal("failed to init bonus freeze authority signer")
+	}
+	// Solana websocket listener, it subscribes to the references of the pending transactions
+	// created on any instance and keeps the subscriptions in sync with the database
+	wsOpts := []websocketrpc.ClientOption{
+		websocketrpc.WithEventsEmitter(streamListenerEmitter{Emitter: eventEmitter, stream: streamEmitter}),
+		websocketrpc.WithLogger(logger.Module("websocketrpc")),
+		websocketrpc.WithHealth(wsHealth),
+		websocketrpc.WithReferences(paymentService.GetPendingReferences, solanaWSReferenceSyncInterval),
+	}
+
+	// Merchant wallet deposit monitoring
+	if depositMonitoring {
+		mints := depositMonitoringMints
+		if len(mints) == 0 {
+			mints = []string{merchantDefaultMint}
+		}
+		for i, mint := range mints {
+			mints[i] = payments.MintAddress(mint, payments.SOL)
+		}
+		depositsService := deposits.NewService(repo, solClient, merchantWalletAddress, deposits.WithMints(mints...))
+
+		wsOpts = append(wsOpts, websocketrpc.WithWatchedAddresses(depositsService.Addresses()...))
+
+		eventEmitter.On(events.WalletAccountNotification, deposits.WalletNotificationListener(deposits.NewEnqueuer(asynqClient)))
+		eventEmitter.On(events.DepositReceived, webhook.TranslateEventsToWebhookEvents(webhookEnqueuer))
+		queueHandlers = append(queueHandlers, deposits.NewWorker(depositsService, eventEmitter.Emit))
+	}
+	if solanaWSSEndpoint != "" {
+		leaderTasks = append(leaderTasks, runWebsocketListener(solanaWSSEndpoint, logger.Module("websocketrpc"), wsOpts...))
+	}
+
+	// Payment status streams of the checkout pages, on every instance with the fanout
+	sseStorage := sse.NewMemStorage()
+	sseService := sse.NewService(sseStorage, sse.WithLogger(logger.Module("sse")))
+	streamEmitter.ListenEvents(sse.TranslateEventsToSSEChannel(sseService), sse.PaymentStatusEvents...)
+
+	// Stricter limits for the public checkout endpoints
+	checkoutProtectionOpts := []server.CheckoutProtectionOption{
+		server.WithIPRateLimit(checkoutRateLimitPerIP, checkoutRateLimitPeriod),
+		server.WithPaymentRateLimit(checkoutRateLimitPerPayment, checkoutRateLimitPeriod),
+	}
+	if linkSigner != nil {
+		checkoutProtectionOpts = append(checkoutProtectionOpts, server.WithLinkSigner(linkSigner))
+	}
+	if paymentLinkTokenTTL > 0 {
+		checkoutProtectionOpts = append(checkoutProtectionOpts,
+			server.WithLinkTokens(paymentCore, checkoutRateLimitPerToken, checkoutRateLimitPeriod),
+		)
+	}
+	if checkoutPoWDifficulty > 0 {
+		checkoutProtectionOpts = append(checkoutProtectionOpts,
+			server.WithChallenge(server.ProofOfWorkChallenge(checkoutPoWDifficulty)),
+		)
+	}
+	checkoutProtection := server.NewCheckoutProtection(logger.Module("http"), checkoutProtectionOpts...)
+
+	// Config reload without restart
+	reloader := &configReloader{
+		file:         configReloadFile,
+		webhooks:     webhookService,
+		protection:   checkoutProtection,
+		settings:     settingsService,
+		integrations: integrationsService,
+		log:          logger.Module("config"),
+	}
 
 	// Event broadcaster
-	eventBroadcaster := events.NewEventBroadcaster(eventEmitter, logger)
+	eventBroadcaster := events.NewEventBroadcaster(streamEmitter, logger.Module("events"))
 
 	// Mount HTTP endpoints
 	{
@@ -162,60 +754,306 @@ func main() {
 						clientSecret,
 						auth.WithAccessTokenTTL(accessTokenTTL),
 						auth.WithRefreshTokenTTL(refreshTokenTTL),
+						auth.WithTestClient(testClientID, testClientSecret),
 					),
 				),
 			))
 
+		// operators login to the admin routes
+		if oidcEnabled {
+			oidcLogin, err := newOIDC(oauthSigningKey)
+			if err != nil {
+				logger.WithError(err).Fatal("failed to init oidc login")
+			}
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/oauth/oidc", auth.MakeOIDCHTTPHandler(oidcLogin))
+		}
+
 		// payment service
+		serverConfig := server.Config{
+			AppName:               productName,
+			AppIconURI:            productIconURI,
+			PaymentStatusCacheTTL: paymentStatusCacheTTL,
+			Explorer:              explorer,
+			CheckoutTokens:        checkoutTokens,
+			CheckoutPage: server.CheckoutPage{
+				PrimaryColor: checkoutPageColor,
+				SupportURL:   checkoutPageSupportURL,
+				Cluster:      solanaCluster,
+				APIPath:      "/payment",
+				URL:          checkoutPageURL,
+				// the page solves the proof of work of the transaction requests
+				ProofOfWorkDifficulty: checkoutPoWDifficulty,
+			},
+		}
+		paymentEndpoints := server.MakeEndpoints(
+			paymentService,
+			jupiterClient,
+			tokenMetadataCache,
+			timelineService,
+			reportsService,
+			serverConfig,
+		)
 		r.With(middleware.Timeout(httpRequestTimeout)).
 			Mount("/payment", server.MakeHTTPHandler(
-				server.MakeEndpoints(
+				paymentEndpoints,
+				logger.Module("http"),
+				paymentsMdw,
+				server.Timeouts{
+					Transaction: httpTransactionTimeout,
+					Estimate:    httpEstimateTimeout,
+				},
+				checkoutProtection.Middleware,
+			))
+
+		// hosted checkout page
+		if checkoutPageEnabled {
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/checkout", server.MakeCheckoutPageHandler(
 					paymentService,
-					jupiterClient,
-					server.Config{
-						AppName:    productName,
-						AppIconURI: productIconURI,
-					},
-				),
-				kitlog.NewLogger(logger),
-				oauthMdw,
+					tokenMetadataCache,
+					serverConfig,
+					logger.Module("http"),
+					checkoutProtection.PageMiddleware,
+				))
+		}
+
+		// operator tools, e.g. on-chain re-verification of a transaction
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/admin", server.MakeAdminHTTPHandler(
+				paymentEndpoints,
+				logger.Module("http"),
+				withRole(adminMdw, auth.RequireRoleForWrites(auth.RoleOwner, auth.RoleOperator, auth.RoleSupport)),
+			))
+		r.With(middleware.Timeout(httpRequestTimeout), adminMdw, auth.RequireRole(auth.RoleOwner)).
+			Post("/admin/config/reload", mkReloadConfigHandler(reloader))
+
+		// roles of the API credentials (owners only)
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/admin/roles", roles.MakeHTTPHandler(
+				roles.MakeEndpoints(roles.NewService(repo)),
+				logger.Module("http"),
+				withRole(adminMdw, auth.RequireRole(auth.RoleOwner)),
+			))
+
+		// static API keys (owners only)
+		if apiKeysEnabled {
+			var apiKeyOpts []apikeys.ServiceOption
+			if testClientID != "" {
+				apiKeyOpts = append(apiKeyOpts, apikeys.WithTestKeys())
+			}
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/admin/api-keys", apikeys.MakeHTTPHandler(
+					apikeys.MakeEndpoints(apikeys.NewService(repo, apiKeyOpts...)),
+					logger.Module("http"),
+					withRole(adminMdw, auth.RequireRole(auth.RoleOwner)),
+				))
+		}
+
+		// public IPs of the webhook deliveries
+		r.With(middleware.Timeout(httpRequestTimeout), webhooksMdw).
+			Get("/webhooks/egress-ips", mkWebhookEgressIPsHandler(webhookService))
+
+		// webhook destinations subscribed to the specific events
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/webhooks/destinations", webhook.MakeHTTPHandler(
+				webhook.MakeEndpoints(webhookDestinations),
+				logger.Module("http"),
+				webhooksMdw,
+			))
+
+		// audit trail of the authenticated calls (owners only)
+		if auditService != nil {
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/admin/audit", audit.MakeHTTPHandler(
+					audit.MakeEndpoints(auditService),
+					logger.Module("http"),
+					withRole(adminMdw, auth.RequireRole(auth.RoleOwner)),
+				))
+		}
+
+		// checkout conversion funnel report
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/funnel", funnel.MakeHTTPHandler(
+				funnel.MakeEndpoints(funnelService),
+				logger.Module("http"),
+				reportsMdw,
 			))
 
+		// revenue and bonus reports, report jobs
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/reports", reports.MakeHTTPHandler(
+				reports.MakeEndpoints(reportsService),
+				logger.Module("http"),
+				reportsMdw,
+			))
+
+		// merchant settings
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/settings", settings.MakeHTTPHandler(
+				settings.MakeEndpoints(settingsService),
+				logger.Module("http"),
+				settingsMdw,
+			))
+
+		// payment disputes
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/disputes", disputes.MakeHTTPHandler(
+				disputes.MakeEndpoints(disputesService),
+				logger.Module("http"),
+				disputesMdw,
+			))
+
+		// recurring billing plans and subscriptions
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/subscriptions", subscriptions.MakeHTTPHandler(
+				subscriptions.MakeEndpoints(subscriptionsService),
+				logger.Module("http"),
+				subscriptionsMdw,
+			))
+
+		// e-commerce integrations (authorized by the platform webhook signatures)
+		r.With(middleware.Timeout(httpRequestTimeout)).
+			Mount("/integrations", integrations.MakeHTTPHandler(
+				integrations.MakeEndpoints(integrationsService),
+				logger.Module("http"),
+				shopifyWebhookSecret,
+				wooCommerceWebhookSecret,
+			))
+
+		// treasury withdrawals
+		if treasuryService.Enabled() {
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/treasury", treasury.MakeHTTPHandler(
+					treasury.MakeEndpoints(treasuryService),
+					logger.Module("http"),
+					treasuryMdw,
+				))
+		}
+
+		// spending allowances for the recurring pulls
+		if allowanceDelegate != nil {
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/allowances", allowances.MakeHTTPHandler(
+					allowances.MakeEndpoints(allowances.NewService(solClient, merchantWalletAddress, allowanceDelegate, logger.Module("allowances"))),
+					logger.Module("http"),
+					treasuryMdw,
+				))
+		}
+
+		// sandbox airdrop (test-mode clients only)
+		if sandboxService != nil {
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/sandbox", sandbox.MakeHTTPHandler(
+					sandbox.MakeEndpoints(sandboxService),
+					logger.Module("http"),
+					paymentsMdw,
+				))
+		}
+
+		// bonus token accounts freeze and thaw (fraud response)
+		if bonusFreezeAuthority != nil {
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/bonus", bonus.MakeHTTPHandler(
+					bonus.MakeEndpoints(bonus.NewService(solClient, bonusMintAddress, bonusFreezeAuthority, logger.Module("bonus"))),
+					logger.Module("http"),
+					treasuryMdw,
+				))
+		}
+
+		// prepaid vouchers issuance
+		if voucherService.Enabled() {
+			r.With(middleware.Timeout(httpRequestTimeout)).
+				Mount("/vouchers", vouchers.MakeHTTPHandler(
+					vouchers.MakeEndpoints(voucherService),
+					logger.Module("http"),
+					treasuryMdw,
+				))
+		}
+
 		// sse service
 		r.With(middleware.Timeout(time.Hour)).
 			Mount("/ws", events.MakeHTTPHandler(eventBroadcaster))
+		r.With(middleware.Timeout(time.Hour), checkoutProtection.Middleware).
+			Get("/payment/checkout/{payment_id}/events", sse.MakePaymentEventsHandler(
+				sseService,
+				paymentService,
+				sseHeartbeatInterval,
+				logger.Module("sse"),
+			))
+		if checkoutTokens != nil {
+			// the connection lifetime is bounded by the pings, not by the request timeout
+			r.With(checkoutProtection.Middleware).
+				Get("/ws/checkout/{payment_id}", server.NewCheckoutWebSocket(
+					streamEmitter,
+					paymentService,
+					checkoutTokens,
+					logger.Module("http"),
+				).ServeHTTP)
+		}
 	}
 
 	// Run HTTP server
-	eg.Go(runServer(ctx, httpPort, r, logger))
+	eg.Go(runServer(ctx, httpPort, r, logger.Module("http")))
 
 	// Run asynq worker
-	eg.Go(runQueueServer(
-		redisConnOpt,
-		logger,
-		payments.NewWorker(paymentService, solClient, paymentEnqueuer),
-		webhook.NewWorker(webhook.NewService(
-			webhook.WithSignatureSecret(webhookSignatureSecret),
-			webhook.WithWebhookURI(webhookURI),
-		)),
-	))
+	eg.Go(runQueueServer(redisConnOpt, logger.Module("queues"), webhook.DeadLetterHandler(eventEmitter.Emit), queueHandlers...))
+
+	// Run asynq scheduler and websocket listener
+	if leaderElectionEnabled {
+		elector := leader.NewElector(redisClient, leaderElectionKey, leaderElectionTTL, logger.Module("leader"))
+		eg.Go(func() error {
+			return elector.Run(ctx, runLeaderTasks(leaderTasks...))
+		})
+	} else {
+		eg.Go(func() error {
+			return runLeaderTasks(leaderTasks...)(ctx)
+		})
+	}
+
+	// Run merchant settings reload
+	eg.Go(func() error {
+		return settingsService.Run(ctx)
+	})
 
-	// Run asynq scheduler
-	eg.Go(runScheduler(
-		redisConnOpt,
-		logger,
-		payments.NewScheduler(),
-	))
+	// Reload the config on SIGHUP
+	eg.Go(func() error {
+		return reloader.Run(ctx)
+	})
+
+	// Collect queue stats
+	eg.Go(func() error {
+		return queueMonitor.Run(ctx)
+	})
+
+	// Check the chain connection health
+	eg.Go(func() error {
+		return rpcHealth.Run(ctx)
+	})
 
 	// Run event broadcaster
 	eg.Go(func() error {
 		return eventBroadcaster.Run(ctx)
 	})
 
-	// Run event listener
-	// eg.Go(func() error {
-	// 	return websocketrpcClient.Run(ctx)
-	// })
+	// Drop the expired events of the payment status streams
+	eg.Go(func() error {
+		return sseStorage.GC(ctx, sse.EventTTL, time.Minute)
+	})
+
+	// Run events bus
+	if eventsBus != nil {
+		eg.Go(func() error {
+			return eventsBus.Run(ctx)
+		})
+	}
+
+	// Run events fanout
+	if eventsFanout != nil {
+		eg.Go(func() error {
+			return eventsFanout.Run(ctx)
+		})
+	}
 
 	// Run all goroutines
 	if err := eg.Wait(); err != nil {
@@ -224,13 +1062,14 @@ func main() {
 }
 
 // newCtx creates a new context that is cancelled when an interrupt signal is received.
-func newCtx(log *logrus.Entry) context.Context {
+// SIGHUP is not a shutdown signal, it reloads the config, see configReloader.
+func newCtx(log *logging.Logger) context.Context {
 	ctx, cancel := context.WithCancel(context.Background())
 	go func() {
 		defer cancel()
 
 		sCh := make(chan os.Signal, 1)
-		signal.Notify(sCh, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGPIPE)
+		signal.Notify(sCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGPIPE)
 		<-sCh
 
 		// Shutdown signal with grace period of N seconds (default: 5 seconds)
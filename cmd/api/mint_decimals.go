@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+// mintDecimalsStore is an adapter of the repository to the solana.MintDecimalsStore interface.
+type mintDecimalsStore struct {
+	repo repository.Storage
+}
+
+// GetMintDecimals returns the stored decimals of the given mint.
+func (s mintDecimalsStore) GetMintDecimals(ctx context.Context, base58MintAddr string) (uint8, error) {
+	decimals, err := s.repo.GetMintDecimals(ctx, base58MintAddr)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(decimals), nil
+}
+
+// StoreMintDecimals stores decimals of the given mint.
+func (s mintDecimalsStore) StoreMintDecimals(ctx context.Context, base58MintAddr string, decimals uint8) error {
+	return s.repo.StoreMintDecimals(ctx, repository.StoreMintDecimalsParams{
+		Address:  base58MintAddr,
+		Decimals: int16(decimals),
+	})
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/notifications"
+)
+
+// Supported email notification providers.
+const (
+	emailProviderSMTP     = "smtp"
+	emailProviderSendGrid = "sendgrid"
+)
+
+// newEmailSender creates an email sender according to the NOTIFICATIONS_EMAIL_PROVIDER setting.
+// Returns nil if the email notifications are disabled.
+func newEmailSender() (notifications.Sender, error) {
+	switch emailProvider {
+	case "":
+		return nil, nil
+	case emailProviderSMTP:
+		if smtpHost == "" {
+			return nil, fmt.Errorf("SMTP_HOST is required for the smtp email provider")
+		}
+		return notifications.NewSMTPSender(smtpHost, smtpPort, smtpUsername, smtpPassword), nil
+	case emailProviderSendGrid:
+		if sendGridAPIKey == "" {
+			return nil, fmt.Errorf("SENDGRID_API_KEY is required for the sendgrid email provider")
+		}
+		return notifications.NewSendGridSender(sendGridAPIKey), nil
+	default:
+		return nil, fmt.Errorf("unsupported email provider: %s", emailProvider)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/auth"
+)
+
+// newOIDC returns the OIDC login of the operators configured by the OIDC_* variables.
+func newOIDC(signingKey string) (*auth.OIDC, error) {
+	if oidcIssuerURL == "" || oidcClientID == "" || oidcRedirectURL == "" {
+		return nil, fmt.Errorf("OIDC_ISSUER_URL, OIDC_CLIENT_ID and OIDC_REDIRECT_URL are required")
+	}
+
+	mapping := make([]auth.GroupRole, 0, len(oidcRoleMapping))
+	for _, pair := range oidcRoleMapping {
+		group, role, ok := strings.Cut(pair, ":")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" || role == "" {
+			return nil, fmt.Errorf("invalid OIDC_ROLE_MAPPING pair: %s", pair)
+		}
+		r, err := auth.ParseRole(role)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OIDC_ROLE_MAPPING pair: %s: %w", pair, err)
+		}
+		mapping = append(mapping, auth.GroupRole{Group: group, Role: r})
+	}
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("OIDC_ROLE_MAPPING is required")
+	}
+
+	return auth.NewOIDC(oidcIssuerURL, oidcClientID, oidcClientSecret, oidcRedirectURL, signingKey,
+		auth.WithOIDCScopes(oidcScopes...),
+		auth.WithOIDCGroupsClaim(oidcGroupsClaim),
+		auth.WithOIDCRoleMapping(mapping...),
+		auth.WithOIDCTokenTTL(oidcTokenTTL),
+	), nil
+}
@@ -12,14 +12,16 @@ type (
 )
 
 // setupQueue creates a new queue client and registers task handlers.
-func runQueueServer(redisConnOpt asynq.RedisConnOpt, log asynq.Logger, handlers ...taskHandler) func() error {
+// errHandler is optional and is called on every failed task.
+func runQueueServer(redisConnOpt asynq.RedisConnOpt, log asynq.Logger, errHandler asynq.ErrorHandler, handlers ...taskHandler) func() error {
 	return func() error {
 		// Setup asynq server
 		srv := asynq.NewServer(
 			redisConnOpt,
 			asynq.Config{
-				Concurrency: workerConcurrency,
-				Logger:      log,
+				Concurrency:  workerConcurrency,
+				Logger:       log,
+				ErrorHandler: errHandler,
 				Queues: map[string]int{
 					queueName: workerConcurrency,
 				},
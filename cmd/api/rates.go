@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/rates"
+)
+
+// Supported exchange rate providers.
+const (
+	rateProviderCoinGecko = "coingecko"
+	rateProviderPyth      = "pyth"
+)
+
+// newRateProvider creates the exchange rate provider of the fiat-priced payments according to the EXCHANGE_RATE_* settings.
+// The providers are queried in the configured order, the next one is used if the previous one fails.
+// Returns nil if the fiat pricing is disabled.
+func newRateProvider() (rates.Provider, error) {
+	if len(exchangeRateProviders) == 0 {
+		return nil, nil
+	}
+
+	providers := make([]rates.Provider, 0, len(exchangeRateProviders))
+	for _, name := range exchangeRateProviders {
+		switch strings.TrimSpace(name) {
+		case rateProviderCoinGecko:
+			opts := []rates.CoinGeckoOption{rates.WithCoinGeckoAPIKey(coingeckoAPIKey)}
+			if coingeckoAPIURL != "" {
+				opts = append(opts, rates.WithCoinGeckoAPIURL(coingeckoAPIURL))
+			}
+			providers = append(providers, rates.NewCoinGecko(opts...))
+		case rateProviderPyth:
+			feeds, err := parsePythPriceFeeds()
+			if err != nil {
+				return nil, err
+			}
+			opts := []rates.PythOption{rates.WithPythMaxPriceAge(pythMaxPriceAge)}
+			if pythHermesURL != "" {
+				opts = append(opts, rates.WithPythAPIURL(pythHermesURL))
+			}
+			providers = append(providers, rates.NewPyth(feeds, opts...))
+		default:
+			return nil, fmt.Errorf("unsupported exchange rate provider: %s", name)
+		}
+	}
+
+	return rates.NewCache(rates.Fallback(providers...), exchangeRateCacheTTL), nil
+}
+
+// parsePythPriceFeeds parses the PYTH_PRICE_FEEDS setting, e.g. "SOL/USD:0xef0d...,USDC/USD:0xeaa0...".
+// The currency of the pair is the mint address or its symbol.
+func parsePythPriceFeeds() (map[string]string, error) {
+	feeds := make(map[string]string, len(pythPriceFeeds))
+	for _, item := range pythPriceFeeds {
+		pair, id, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("PYTH_PRICE_FEEDS: invalid price feed %q, want MINT/FIAT:FEED_ID", item)
+		}
+		mint, fiat, ok := strings.Cut(pair, "/")
+		if !ok || mint == "" || fiat == "" {
+			return nil, fmt.Errorf("PYTH_PRICE_FEEDS: invalid currency pair %q", pair)
+		}
+		// the unknown symbols fall back to SOL, the pair would get the wrong price
+		address := payments.MintAddress(mint, "")
+		if address == payments.SOL && !payments.IsSOL(mint) {
+			return nil, fmt.Errorf("PYTH_PRICE_FEEDS: unknown currency %q, use the mint address", mint)
+		}
+		feeds[address+"/"+fiat] = id
+	}
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("PYTH_PRICE_FEEDS is required with the pyth exchange rate provider")
+	}
+	return feeds, nil
+}
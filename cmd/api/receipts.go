@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+
+	"github.com/easypmnt/checkout-api/arweave"
+	"github.com/easypmnt/checkout-api/receipts"
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// newReceiptsService creates the NFT receipts service according to the RECEIPT_* settings.
+// Returns nil if the receipts are disabled.
+func newReceiptsService(ctx context.Context, sol *solana.Client, commitment solana.Commitment) (*receipts.Service, error) {
+	if receiptMintAuthoritySigner == "" {
+		return nil, nil
+	}
+	if receiptMintSeed == "" {
+		return nil, fmt.Errorf("RECEIPT_MINT_SEED is required with RECEIPT_MINT_AUTHORITY_SIGNER")
+	}
+	if receiptImageURL == "" {
+		return nil, fmt.Errorf("RECEIPT_IMAGE_URL is required with RECEIPT_MINT_AUTHORITY_SIGNER")
+	}
+	if _, err := os.Stat(receiptArweaveKeyPath); err != nil {
+		return nil, fmt.Errorf("failed to read RECEIPT_ARWEAVE_KEY_PATH: %w", err)
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(receiptMintSeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode RECEIPT_MINT_SEED: %w", err)
+	}
+
+	authority, err := newSigner(ctx, receiptMintAuthoritySigner, receiptMintAuthorityPrivateKey, receiptAWSKMSKeyID, receiptGCPKMSKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("receipt mint authority: %w", err)
+	}
+
+	merchantName := receiptMerchantName
+	if merchantName == "" {
+		merchantName = productName
+	}
+
+	return receipts.NewService(
+		arweave.NewClient(arweave.InitWalletWithPathAndNode(receiptArweaveKeyPath, arweaveNodeURL)),
+		sol, authority, seed,
+		receipts.WithMerchantName(merchantName),
+		receipts.WithSymbol(receiptSymbol),
+		receipts.WithImageURL(receiptImageURL),
+		receipts.WithExternalURL(receiptExternalURL),
+		receipts.WithTemplates(receiptNameTemplate, receiptDescriptionTemplate),
+		receipts.WithCommitment(commitment),
+	)
+}
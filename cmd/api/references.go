@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/payments"
+)
+
+// newReferenceDeriver returns the deriver of the transaction references from the PAYMENT_REFERENCE_SEED,
+// or nil if the seed is not set, so the references are random.
+func newReferenceDeriver() (*payments.ReferenceDeriver, error) {
+	if paymentReferenceSeed == "" {
+		return nil, nil
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(paymentReferenceSeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PAYMENT_REFERENCE_SEED: %w", err)
+	}
+
+	return payments.NewReferenceDeriver(seed)
+}
+
+// newDepositDeriver returns the deriver of the payment deposit addresses from the PAYMENT_DEPOSIT_SEED,
+// or nil if the seed is not set, so the deposit addresses are disabled.
+func newDepositDeriver() (*payments.DepositDeriver, error) {
+	if paymentDepositSeed == "" {
+		return nil, nil
+	}
+	if paymentDepositSeed == paymentReferenceSeed {
+		return nil, fmt.Errorf("PAYMENT_DEPOSIT_SEED must differ from PAYMENT_REFERENCE_SEED")
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(paymentDepositSeed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PAYMENT_DEPOSIT_SEED: %w", err)
+	}
+
+	return payments.NewDepositDeriver(seed)
+}
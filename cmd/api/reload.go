@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/dmitrymomot/go-env"
+	"github.com/easypmnt/checkout-api/integrations"
+	"github.com/easypmnt/checkout-api/internal/logging"
+	"github.com/easypmnt/checkout-api/server"
+	"github.com/easypmnt/checkout-api/settings"
+	"github.com/easypmnt/checkout-api/webhook"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/joho/godotenv"
+)
+
+type (
+	// reloadableConfig is the part of the config which is applied without restart.
+	reloadableConfig struct {
+		webhookURI                  string
+		checkoutRateLimitPerIP      int
+		checkoutRateLimitPerPayment int
+		checkoutRateLimitPeriod     time.Duration
+		merchantApplyBonus          bool
+		maxApplyBonusAmount         int64
+		merchantMaxBonusPercentage  int16
+		bonusRate                   int64
+		integrationsCurrencies      []string
+	}
+
+	// configReloader re-reads the reloadable config on SIGHUP or on the admin request
+	// and applies it to the running services.
+	configReloader struct {
+		file         string
+		webhooks     *webhook.Service
+		protection   *server.CheckoutProtection
+		settings     *settings.Service
+		integrations *integrations.Service
+		log          *logging.Logger
+
+		mu sync.Mutex
+	}
+)
+
+// loadReloadableConfig reads the reloadable config from the environment.
+// The current values are used as the defaults, so the removed variables keep their values until restart.
+func loadReloadableConfig() reloadableConfig {
+	return reloadableConfig{
+		webhookURI:                  env.GetString("WEBHOOK_URI", webhookURI),
+		checkoutRateLimitPerIP:      env.GetInt("CHECKOUT_RATE_LIMIT_PER_IP", checkoutRateLimitPerIP),
+		checkoutRateLimitPerPayment: env.GetInt("CHECKOUT_RATE_LIMIT_PER_PAYMENT", checkoutRateLimitPerPayment),
+		checkoutRateLimitPeriod:     env.GetDuration("CHECKOUT_RATE_LIMIT_PERIOD", checkoutRateLimitPeriod),
+		merchantApplyBonus:          env.GetBool("MERCHANT_APPLY_BONUS", merchantApplyBonus),
+		maxApplyBonusAmount:         env.GetInt("MAX_APPLY_BONUS_AMOUNT", maxApplyBonusAmount),
+		merchantMaxBonusPercentage:  env.GetInt("MERCHANT_MAX_BONUS_PERCENTAGE", merchantMaxBonusPercentage),
+		bonusRate:                   env.GetInt("BONUS_RATE", bonusRate),
+		integrationsCurrencies:      env.GetStrings("INTEGRATIONS_CURRENCIES", ",", integrationsCurrencies),
+	}
+}
+
+// Reload re-reads the config file into the environment and applies the reloadable config.
+// The values of the file override the environment, so the variables set by the process manager
+// must not be set in the file, if they're changed on reload.
+func (r *configReloader) Reload(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file != "" {
+		if err := godotenv.Overload(r.file); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to read config file %s: %w", r.file, err)
+		}
+	}
+	conf := loadReloadableConfig()
+
+	if conf.webhookURI == "" {
+		return fmt.Errorf("WEBHOOK_URI must not be empty")
+	}
+	if err := r.webhooks.CheckURL(conf.webhookURI); err != nil {
+		return fmt.Errorf("WEBHOOK_URI: %w", err)
+	}
+
+	// The bonus settings are validated by the payment service, so they're applied first
+	// and nothing is changed if they're invalid.
+	defaults := r.settings.Defaults()
+	defaults.ApplyBonus = conf.merchantApplyBonus
+	defaults.MaxApplyBonusAmount = uint64(conf.maxApplyBonusAmount)
+	defaults.MaxApplyBonusPercent = uint16(conf.merchantMaxBonusPercentage)
+	defaults.AccrueBonusRate = 0
+	if conf.bonusRate > 0 {
+		defaults.AccrueBonusRate = uint64(conf.bonusRate)
+	}
+	if err := r.settings.SetDefaults(defaults); err != nil {
+		return fmt.Errorf("failed to apply bonus settings: %w", err)
+	}
+	// the stored merchant settings override the env config, so they're reloaded as well
+	if err := r.settings.Reload(ctx); err != nil {
+		return err
+	}
+
+	r.webhooks.SetWebhookURI(conf.webhookURI)
+	r.protection.SetRateLimits(conf.checkoutRateLimitPerIP, conf.checkoutRateLimitPerPayment, conf.checkoutRateLimitPeriod)
+	r.integrations.SetCurrencies(conf.integrationsCurrencies...)
+
+	r.log.Info("configuration reloaded")
+
+	return nil
+}
+
+// Run reloads the config on every SIGHUP until the context is canceled.
+func (r *configReloader) Run(ctx context.Context) error {
+	sCh := make(chan os.Signal, 1)
+	signal.Notify(sCh, syscall.SIGHUP)
+	defer signal.Stop(sCh)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sCh:
+		}
+
+		if err := r.Reload(ctx); err != nil {
+			r.log.WithError(err).Error("failed to reload configuration")
+		}
+	}
+}
+
+// returns 204 HTTP status on the successful config reload, or 422 HTTP status with the error
+func mkReloadConfigHandler(r *configReloader) func(w http.ResponseWriter, req *http.Request) {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if err := r.Reload(req.Context()); err != nil {
+			defaultResponse(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"code":       http.StatusUnprocessableEntity,
+				"error":      err.Error(),
+				"request_id": middleware.GetReqID(req.Context()),
+			})
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
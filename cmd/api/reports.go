@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/internal/awssig"
+	"github.com/easypmnt/checkout-api/reports"
+)
+
+// Supported report storages.
+const (
+	reportStorageLocal = "local"
+	reportStorageS3    = "s3"
+)
+
+// newReportStorage creates the storage of the report files according to the REPORT_STORAGE setting.
+// Returns nil if the report jobs are disabled.
+func newReportStorage() (reports.Storage, error) {
+	switch reportStorage {
+	case "":
+		return nil, nil
+	case reportStorageLocal:
+		return reports.NewLocalStorage(reportStorageDir)
+	case reportStorageS3:
+		return reports.NewS3Storage(reportS3Bucket, awsRegion, awssig.Credentials{
+			AccessKeyID:     awsAccessKeyID,
+			SecretAccessKey: awsSecretAccessKey,
+			SessionToken:    awsSessionToken,
+		}, reports.WithS3Endpoint(reportS3Endpoint))
+	default:
+		return nil, fmt.Errorf("unsupported report storage: %s", reportStorage)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/sandbox"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/easypmnt/checkout-api/webhook"
+	"github.com/google/uuid"
+)
+
+// newSandboxOptions returns the payment service and worker options of the test-mode payments
+// according to the TEST_CLIENT_* and SANDBOX_* settings. The test mode is disabled without the test client.
+func newSandboxOptions() ([]payments.ServiceOption, []payments.WorkerOption, error) {
+	if testClientID == "" {
+		return nil, nil, nil
+	}
+
+	mints, err := parseSandboxMints()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sol := newSandboxClient()
+
+	return []payments.ServiceOption{payments.WithSandbox(sol, mints)},
+		[]payments.WorkerOption{payments.WithSandboxClient(sol)},
+		nil
+}
+
+// newSandboxService creates the sandbox airdrop service according to the SANDBOX_* settings.
+// Returns nil if the test mode is disabled.
+func newSandboxService(log sandbox.Logger) (*sandbox.Service, error) {
+	if testClientID == "" {
+		return nil, nil
+	}
+
+	mints, err := parseSandboxMints()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []sandbox.ServiceOption{
+		sandbox.WithSOLAmount(uint64(sandboxAirdropSOLAmount)),
+		sandbox.WithTokenAmount(uint64(sandboxAirdropTokenAmount)),
+	}
+	if sandboxFaucetAuthority != "" {
+		faucet, err := solana.NewLocalSignerFromBase58(sandboxFaucetAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("SANDBOX_FAUCET_AUTHORITY: %w", err)
+		}
+		opts = append(opts, sandbox.WithFaucet(faucet))
+	}
+
+	return sandbox.NewService(newSandboxClient(), mints, log, opts...), nil
+}
+
+// newSandboxClient creates the Solana client of the sandbox cluster.
+func newSandboxClient() *solana.Client {
+	return solana.NewClient(
+		solana.WithRPCEndpoint(sandboxRPCEndpoint),
+		solana.WithCircuitBreaker(solanaBreakerFailures, solanaBreakerCooldown),
+	)
+}
+
+// parseSandboxMints parses the test mints in format: symbol:mint.
+func parseSandboxMints() (map[string]string, error) {
+	mints := make(map[string]string, len(sandboxMints))
+	for _, item := range sandboxMints {
+		symbol, mint, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || symbol == "" || mint == "" {
+			return nil, fmt.Errorf("SANDBOX_MINTS: invalid value: %s", item)
+		}
+		mints[strings.ToUpper(symbol)] = mint
+	}
+	return mints, nil
+}
+
+// paymentLivemodeResolver returns the webhook resolver of the payment mode.
+func paymentLivemodeResolver(ctx context.Context, ps payments.PaymentService) webhook.LivemodeResolver {
+	return func(paymentID string) (bool, error) {
+		id, err := uuid.Parse(paymentID)
+		if err != nil {
+			return true, nil
+		}
+
+		payment, err := ps.GetPayment(ctx, id)
+		if err != nil {
+			return false, err
+		}
+
+		return payment.Livemode, nil
+	}
+}
@@ -1,6 +1,10 @@
 package main
 
-import "github.com/hibiken/asynq"
+import (
+	"context"
+
+	"github.com/hibiken/asynq"
+)
 
 type (
 	schedulerHandler interface {
@@ -9,8 +13,10 @@ type (
 )
 
 // runScheduler creates a new scheduler server and registers task handlers.
-func runScheduler(redisConnOpt asynq.RedisConnOpt, log asynq.Logger, handlers ...schedulerHandler) func() error {
-	return func() error {
+// The scheduler runs until the context is cancelled, so it can be stopped
+// when the instance loses the leadership. A new scheduler is created on every call.
+func runScheduler(redisConnOpt asynq.RedisConnOpt, log asynq.Logger, handlers ...schedulerHandler) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
 		// Setup asynq scheduler
 		scheduler := asynq.NewScheduler(
 			redisConnOpt,
@@ -26,6 +32,12 @@ func runScheduler(redisConnOpt asynq.RedisConnOpt, log asynq.Logger, handlers ..
 		}
 
 		// Run scheduler
-		return scheduler.Run()
+		if err := scheduler.Start(); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		scheduler.Shutdown()
+
+		return nil
 	}
 }
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/jupiter"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/settlements"
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// newSettlementsService creates the settlement swaps service according to the SETTLEMENT_* settings.
+// Returns nil if the settlement swaps are disabled.
+func newSettlementsService(ctx context.Context, repo repository.Storage, sol *solana.Client, jup *jupiter.Client, commitment solana.Commitment) (*settlements.Service, error) {
+	if settlementSigner == "" {
+		return nil, nil
+	}
+	if settlementSlippageBps <= 0 {
+		return nil, fmt.Errorf("SETTLEMENT_SLIPPAGE_BPS must be positive")
+	}
+
+	signer, err := newSigner(ctx, settlementSigner, settlementPrivateKey, settlementAWSKMSKeyID, settlementGCPKMSKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("settlement signer: %w", err)
+	}
+	if signer.PublicKey().ToBase58() != merchantWalletAddress {
+		return nil, fmt.Errorf("SETTLEMENT_SIGNER must be the key of MERCHANT_WALLET_ADDRESS")
+	}
+
+	return settlements.NewService(
+		repo, sol, jup, signer,
+		payments.MintAddress(settlementMint, payments.USDC),
+		settlements.WithSlippage(uint64(settlementSlippageBps)),
+		settlements.WithCommitment(commitment),
+	), nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/internal/awssig"
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// Supported signer types.
+const (
+	signerTypeLocal  = "local"
+	signerTypeAWSKMS = "aws_kms"
+	signerTypeGCPKMS = "gcp_kms"
+)
+
+// newBonusAuthoritySigner creates a signer for the bonus mint authority
+// according to the BONUS_MINT_AUTHORITY_SIGNER setting.
+// Returns nil if the bonus accrual is disabled.
+func newBonusAuthoritySigner(ctx context.Context) (solana.Signer, error) {
+	if bonusRate <= 0 {
+		return nil, nil
+	}
+
+	signer, err := newSigner(ctx, bonusMintAuthoritySigner, bonusMintAuthority, awsKMSKeyID, gcpKMSKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("bonus mint authority: %w", err)
+	}
+
+	return signer, nil
+}
+
+// newBonusFreezeAuthoritySigner creates a signer for the bonus mint freeze authority
+// according to the BONUS_FREEZE_AUTHORITY_SIGNER setting.
+// Returns nil if the freeze authority is not configured, so the freeze and thaw are disabled.
+func newBonusFreezeAuthoritySigner(ctx context.Context) (solana.Signer, error) {
+	if bonusFreezeAuthoritySigner == "" || bonusMintAddress == "" {
+		return nil, nil
+	}
+
+	signer, err := newSigner(ctx, bonusFreezeAuthoritySigner, bonusFreezeAuthority, bonusFreezeAWSKMSKeyID, bonusFreezeGCPKMSKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("bonus freeze authority: %w", err)
+	}
+
+	return signer, nil
+}
+
+// newAllowanceDelegateSigner creates a signer of the merchant delegate wallet
+// according to the ALLOWANCE_DELEGATE_SIGNER setting.
+// Returns nil if the allowances are disabled.
+func newAllowanceDelegateSigner(ctx context.Context) (solana.Signer, error) {
+	if allowanceDelegateSigner == "" {
+		return nil, nil
+	}
+
+	signer, err := newSigner(ctx, allowanceDelegateSigner, allowanceDelegatePrivateKey, allowanceAWSKMSKeyID, allowanceGCPKMSKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("allowance delegate wallet: %w", err)
+	}
+
+	return signer, nil
+}
+
+// newTreasurySigner creates a hot wallet signer of the settlement wallet
+// according to the TREASURY_HOT_WALLET_SIGNER setting.
+// Returns nil if the hot wallet is not configured, so withdrawals are returned unsigned.
+func newTreasurySigner(ctx context.Context) (solana.Signer, error) {
+	if treasuryHotWalletSigner == "" {
+		return nil, nil
+	}
+
+	signer, err := newSigner(ctx, treasuryHotWalletSigner, treasuryHotWalletPrivateKey, treasuryAWSKMSKeyID, treasuryGCPKMSKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("treasury hot wallet: %w", err)
+	}
+
+	return signer, nil
+}
+
+// newVoucherAuthoritySigner creates a signer for the voucher mints authority
+// according to the VOUCHER_MINT_AUTHORITY_SIGNER setting.
+// Returns nil if the vouchers are disabled.
+func newVoucherAuthoritySigner(ctx context.Context) (solana.Signer, error) {
+	if len(voucherMints) == 0 {
+		return nil, nil
+	}
+
+	signer, err := newSigner(ctx, voucherMintAuthoritySigner, voucherMintAuthority, voucherAWSKMSKeyID, voucherGCPKMSKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("voucher mint authority: %w", err)
+	}
+
+	return signer, nil
+}
+
+// newEscrowSigner creates a signer of the escrow wallet
+// according to the ESCROW_WALLET_SIGNER setting.
+// Returns nil if the escrow payments are disabled.
+func newEscrowSigner(ctx context.Context) (solana.Signer, error) {
+	if escrowWalletSigner == "" {
+		return nil, nil
+	}
+
+	signer, err := newSigner(ctx, escrowWalletSigner, escrowWalletPrivateKey, escrowAWSKMSKeyID, escrowGCPKMSKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("escrow wallet: %w", err)
+	}
+
+	return signer, nil
+}
+
+// newDepositFeePayerSigner creates a signer of the deposit sweeps fee payer
+// according to the DEPOSIT_FEE_PAYER_SIGNER setting.
+// Returns nil if the deposit addresses are disabled.
+func newDepositFeePayerSigner(ctx context.Context) (solana.Signer, error) {
+	if paymentDepositSeed == "" {
+		return nil, nil
+	}
+	if depositFeePayerSigner == "" {
+		return nil, fmt.Errorf("deposit fee payer: DEPOSIT_FEE_PAYER_SIGNER is required with PAYMENT_DEPOSIT_SEED")
+	}
+
+	signer, err := newSigner(ctx, depositFeePayerSigner, depositFeePayerPrivateKey, depositFeePayerAWSKMSKeyID, depositFeePayerGCPKMSKey)
+	if err != nil {
+		return nil, fmt.Errorf("deposit fee payer: %w", err)
+	}
+
+	return signer, nil
+}
+
+// newSigner creates a signer of the given type.
+func newSigner(ctx context.Context, signerType, base58PrivateKey, awsKeyID, gcpKeyName string) (solana.Signer, error) {
+	switch signerType {
+	case signerTypeLocal, "":
+		return solana.NewLocalSignerFromBase58(base58PrivateKey)
+	case signerTypeAWSKMS:
+		return solana.NewAWSKMSSigner(ctx, awsKeyID, awsRegion, awssig.Credentials{
+			AccessKeyID:     awsAccessKeyID,
+			SecretAccessKey: awsSecretAccessKey,
+			SessionToken:    awsSessionToken,
+		})
+	case signerTypeGCPKMS:
+		return solana.NewGCPKMSSigner(ctx, gcpKeyName)
+	}
+
+	return nil, fmt.Errorf("unsupported signer: %s", signerType)
+}
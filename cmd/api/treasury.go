@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/easypmnt/checkout-api/treasury"
+)
+
+// newTreasuryService creates the treasury service according to the TREASURY_* settings.
+func newTreasuryService(ctx context.Context, sol *solana.Client, log treasury.Logger) (*treasury.Service, error) {
+	// withdrawals are disabled
+	if len(treasuryWithdrawWhitelist) == 0 {
+		return treasury.NewService(sol, merchantWalletAddress, log), nil
+	}
+
+	opts := []treasury.ServiceOption{
+		treasury.WithWhitelist(treasuryWithdrawWhitelist...),
+	}
+	if len(treasuryMultisigSigners) > 0 {
+		opts = append(opts, treasury.WithMultisig(treasuryMultisigSigners...))
+	}
+
+	// withdrawal limits in format: mint:max_amount
+	for _, item := range treasuryWithdrawLimits {
+		mint, amount, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			return nil, fmt.Errorf("TREASURY_WITHDRAW_LIMITS: invalid value: %s", item)
+		}
+		max, err := strconv.ParseUint(amount, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("TREASURY_WITHDRAW_LIMITS: invalid amount: %s", item)
+		}
+		address := payments.MintAddress(mint, payments.SOL)
+		if address == payments.SOL && !payments.IsSOL(mint) {
+			return nil, fmt.Errorf("TREASURY_WITHDRAW_LIMITS: unknown mint: %s", item)
+		}
+		opts = append(opts, treasury.WithAmountLimit(address, max))
+	}
+
+	signer, err := newTreasurySigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if signer != nil {
+		if !isTreasurySigner(signer.PublicKey().ToBase58()) {
+			return nil, fmt.Errorf("treasury hot wallet: %w", treasury.ErrSignerWalletMismatched)
+		}
+		opts = append(opts, treasury.WithSigner(signer))
+	}
+
+	return treasury.NewService(sol, merchantWalletAddress, log, opts...), nil
+}
+
+// isTreasurySigner returns true if the given address can sign the merchant wallet transactions.
+func isTreasurySigner(addr string) bool {
+	if len(treasuryMultisigSigners) == 0 {
+		return addr == merchantWalletAddress
+	}
+	for _, signer := range treasuryMultisigSigners {
+		if signer == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+
+	"github.com/easypmnt/checkout-api/vault"
+	"github.com/lib/pq"
+)
+
+// loadSecretsFromVault fetches secrets from Vault and overrides the ones loaded from the environment.
+// Returns nil provider if Vault is not configured.
+func loadSecretsFromVault(ctx context.Context, log vaultLogger) (*vault.Provider, error) {
+	if vaultAddr == "" {
+		return nil, nil
+	}
+
+	provider := vault.NewProvider(
+		vault.NewClient(vaultAddr, vaultToken,
+			vault.WithNamespace(vaultNamespace),
+			vault.WithKVMount(vaultKVMount),
+		),
+		vaultSecretPath,
+		vaultDBCredsPath,
+		log,
+	)
+
+	secrets, err := provider.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if secrets.DatabaseURL != "" {
+		dbConnString = secrets.DatabaseURL
+	}
+	if secrets.OAuthSigningKey != "" {
+		oauthSigningKey = secrets.OAuthSigningKey
+	}
+	if secrets.ClientSecret != "" {
+		clientSecret = secrets.ClientSecret
+	}
+	if secrets.WebhookSignatureSecret != "" {
+		webhookSignatureSecret = []byte(secrets.WebhookSignatureSecret)
+	}
+	if secrets.BonusMintAuthority != "" {
+		bonusMintAuthority = secrets.BonusMintAuthority
+	}
+
+	return provider, nil
+}
+
+// validateSecrets checks that all required secrets are set either via the environment or Vault.
+func validateSecrets() error {
+	required := map[string]bool{
+		"DATABASE_URL":             dbConnString != "",
+		"OAUTH_SIGNING_KEY":        oauthSigningKey != "",
+		"CLIENT_SECRET":            clientSecret != "",
+		"WEBHOOK_SIGNATURE_SECRET": len(webhookSignatureSecret) > 0,
+	}
+	for name, ok := range required {
+		if !ok {
+			return fmt.Errorf("%s is required", name)
+		}
+	}
+
+	return nil
+}
+
+type (
+	vaultLogger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+
+	// vaultDBConnector is a database/sql connector that opens new connections
+	// with the current dynamic credentials issued by Vault,
+	// so rotated credentials are picked up without restarting the process.
+	vaultDBConnector struct {
+		dsn      string
+		provider *vault.Provider
+	}
+)
+
+// Connect returns a new connection to the database with the current credentials.
+func (c vaultDBConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	dsn, err := url.Parse(c.dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database url: %w", err)
+	}
+	if username, password, ok := c.provider.DatabaseCredentials(); ok {
+		dsn.User = url.UserPassword(username, password)
+	}
+
+	connector, err := pq.NewConnector(dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database connector: %w", err)
+	}
+
+	return connector.Connect(ctx)
+}
+
+// Driver returns the underlying driver of the connector.
+func (c vaultDBConnector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/easypmnt/checkout-api/vouchers"
+)
+
+// newVoucherService creates the prepaid vouchers service according to the VOUCHER_* settings.
+func newVoucherService(ctx context.Context, sol *solana.Client, log vouchers.Logger) (*vouchers.Service, error) {
+	// vouchers are disabled
+	if len(voucherMints) == 0 {
+		return vouchers.NewService(sol, nil, merchantWalletAddress, log), nil
+	}
+
+	// voucher mints in format: mint or merchant_wallet:mint
+	var opts []vouchers.ServiceOption
+	for _, item := range voucherMints {
+		merchant, mint, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			merchant, mint = merchantWalletAddress, merchant
+		}
+		if mint == "" {
+			return nil, fmt.Errorf("VOUCHER_MINTS: invalid value: %s", item)
+		}
+		opts = append(opts, vouchers.WithVoucherMint(merchant, mint))
+	}
+
+	signer, err := newVoucherAuthoritySigner(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return vouchers.NewService(sol, signer, merchantWalletAddress, log, opts...), nil
+}
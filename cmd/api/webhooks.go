@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/webhook"
+	"github.com/google/uuid"
+)
+
+// paymentCallbackResolver returns the webhook resolver of the payment callback URL.
+func paymentCallbackResolver(ctx context.Context, ps payments.PaymentService) webhook.CallbackResolver {
+	return func(paymentID string) (string, error) {
+		id, err := uuid.Parse(paymentID)
+		if err != nil {
+			return "", nil
+		}
+
+		payment, err := ps.GetPayment(ctx, id)
+		if err != nil {
+			return "", err
+		}
+
+		return payment.CallbackURL, nil
+	}
+}
+
+// newWebhookEgressPolicy builds the egress policy of the webhook deliveries from the WEBHOOK_* settings.
+func newWebhookEgressPolicy() (webhook.EgressPolicy, error) {
+	p := webhook.EgressPolicy{
+		AllowedSchemes:  webhookAllowedSchemes,
+		BlockPrivateIPs: webhookBlockPrivateIPs,
+		EgressIPs:       webhookEgressIPs,
+	}
+
+	for _, port := range webhookAllowedPorts {
+		n, err := strconv.Atoi(port)
+		if err != nil || n <= 0 || n > 65535 {
+			return webhook.EgressPolicy{}, fmt.Errorf("invalid WEBHOOK_ALLOWED_PORTS port: %s", port)
+		}
+		p.AllowedPorts = append(p.AllowedPorts, n)
+	}
+
+	if webhookProxyURL != "" {
+		proxy, err := url.Parse(webhookProxyURL)
+		if err != nil || proxy.Host == "" {
+			return webhook.EgressPolicy{}, fmt.Errorf("invalid WEBHOOK_PROXY_URL: %s", webhookProxyURL)
+		}
+		p.Proxy = proxy
+	}
+
+	return p, nil
+}
+
+// returns the public IPs the webhooks are sent from, so the merchants can allowlist them
+func mkWebhookEgressIPsHandler(s *webhook.Service) func(w http.ResponseWriter, _ *http.Request) {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		ips := s.EgressIPs()
+		if ips == nil {
+			ips = []string{}
+		}
+		defaultResponse(w, http.StatusOK, map[string]interface{}{
+			"egress_ips": ips,
+		})
+	}
+}
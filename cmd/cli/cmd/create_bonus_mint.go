@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/portto/solana-go-sdk/types"
+	"github.com/spf13/cobra"
+)
+
+// createBonusMintCmd represents the createBonusMint command
+var createBonusMintCmd = &cobra.Command{
+	Use:     "create-bonus-mint",
+	Aliases: []string{"cbm", "bonus"},
+	Short:   "Creates the bonus token mint",
+	Long: `
+Creates the fungible token used for the bonus (cashback) program:
+the mint is created with the given mint and freeze authorities,
+the token metadata is uploaded to Arweave and set on chain.
+
+The resulting BONUS_* settings are printed to the console, and written
+to the env file if the --env-file flag is set, so the API can be restarted
+with the bonus program enabled.
+
+To create a new token, the creator must pay a network fee, so make sure
+to have enough funds in your account to cover the fee (recommend to have at least 0.2 SOL).
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		decimals, err := cmd.Flags().GetInt8("decimals")
+		if err != nil {
+			return fmt.Errorf("decimals: %w", err)
+		}
+
+		params := MintFungibleTokenParams{
+			SolanaRPCEndpoint: cmd.Flag("solana-rpc-endpoint").Value.String(),
+			ArweaveKey:        cmd.Flag("arweave-key").Value.String(),
+			MintAuthority:     cmd.Flag("mint-authority").Value.String(),
+			FeePayer:          cmd.Flag("fee-payer").Value.String(),
+			FreezeAuthority:   cmd.Flag("freeze-authority").Value.String(),
+
+			Name:        cmd.Flag("name").Value.String(),
+			Symbol:      cmd.Flag("symbol").Value.String(),
+			Decimals:    decimals,
+			Icon:        cmd.Flag("icon").Value.String(),
+			ExternalURL: cmd.Flag("external_url").Value.String(),
+			Description: cmd.Flag("description").Value.String(),
+		}
+		if params.MintAuthority == "" {
+			params.MintAuthority = params.FeePayer
+		}
+
+		mintAuth, err := types.AccountFromBase58(params.MintAuthority)
+		if err != nil {
+			return fmt.Errorf("failed to parse mint authority: %w", err)
+		}
+		if params.FreezeAuthority == "" {
+			params.FreezeAuthority = mintAuth.PublicKey.ToBase58()
+		}
+
+		mintAddr, err := mintFungibleToken(cmd.Context(), params)
+		if err != nil {
+			return fmt.Errorf("mint bonus token: %w", err)
+		}
+
+		color.Green("Bonus token created successfully. Mint address: %s", mintAddr)
+		bold := color.New(color.Bold).SprintFunc()
+		fmt.Println("---------------------------------------------------------------------------------")
+		fmt.Println(bold("Mint address:     "), mintAddr)
+		fmt.Println(bold("Mint authority:   "), mintAuth.PublicKey.ToBase58())
+		fmt.Println(bold("Freeze authority: "), params.FreezeAuthority)
+		fmt.Println("---------------------------------------------------------------------------------")
+
+		envFile := cmd.Flag("env-file").Value.String()
+		if envFile == "" {
+			color.Yellow("Add the following settings to the API environment:")
+			fmt.Printf("BONUS_MINT_ADDRESS=%s\n", mintAddr)
+			fmt.Println("BONUS_MINT_AUTHORITY=<mint authority private key>")
+			fmt.Println("BONUS_MINT_AUTHORITY_SIGNER=local")
+			return nil
+		}
+
+		if err := updateEnvFile(envFile, map[string]string{
+			"BONUS_MINT_ADDRESS":          mintAddr,
+			"BONUS_MINT_AUTHORITY":        params.MintAuthority,
+			"BONUS_MINT_AUTHORITY_SIGNER": "local",
+		}); err != nil {
+			return fmt.Errorf("env file: %w", err)
+		}
+		color.Green("Bonus settings are written to %s, restart the API to apply them.", envFile)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(createBonusMintCmd)
+
+	createBonusMintCmd.Flags().String("solana-rpc-endpoint", "https://api.devnet.solana.com", "Solana RPC endpoint URL.")
+	createBonusMintCmd.Flags().String("name", "Cashback", "Name of the bonus token")
+	createBonusMintCmd.Flags().String("symbol", "CASHBACK", "Symbol of the bonus token")
+	createBonusMintCmd.Flags().Int8("decimals", 9, "Number of decimals for the token. Recommend to set the same as the default payment mint.")
+	createBonusMintCmd.Flags().String("icon", "./arweave/cashback_logo.png", "Path to the icon of the token.")
+	createBonusMintCmd.Flags().String("external_url", "", "External URL of the token (optional).")
+	createBonusMintCmd.Flags().String("description", "Cashback bonus, applied as a discount at checkout", "Description of the token.")
+	createBonusMintCmd.Flags().String("arweave-key", "./arweave-key.json", "Path to the arweave key to upload the token metadata to Arweave.")
+	createBonusMintCmd.Flags().String("mint-authority", "", "Base58 encoded private key of the mint authority (is signer); the fee payer is used if empty.")
+	createBonusMintCmd.Flags().String("freeze-authority", "", "Base58 encoded public key of the freeze authority; the mint authority is used if empty.")
+	createBonusMintCmd.Flags().String("fee-payer", "", "Base58 encoded private key of the fee payer.")
+	createBonusMintCmd.Flags().String("env-file", "", "Path to the API env file to write the bonus settings to (optional).")
+}
+
+// updateEnvFile sets the given values in the env file, keeping the other lines as is.
+// The missing keys are appended to the end of the file, which is created if it does not exist.
+func updateEnvFile(path string, values map[string]string) error {
+	var lines []string
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		f.Close() // nolint:errcheck
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	written := make(map[string]bool, len(values))
+	for i, line := range lines {
+		key, _, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if value, ok := values[key]; ok {
+			lines[i] = key + "=" + value
+			written[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, key+"="+values[key])
+	}
+
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+}
@@ -81,6 +81,7 @@ type MintFungibleTokenParams struct {
 	ArweaveKey        string
 	MintAuthority     string
 	FeePayer          string
+	FreezeAuthority   string // optional; base58 encoded public key, the mint authority is used if empty
 
 	Name        string
 	Symbol      string
@@ -162,12 +163,13 @@ func mintFungibleToken(ctx context.Context, arg MintFungibleTokenParams) (string
 		AddSigner(mintAuth).
 		AddSigner(feePayer).
 		AddInstruction(solana.CreateFungibleToken(solana.CreateFungibleTokenParam{
-			Mint:        mint.PublicKey.ToBase58(),
-			Owner:       mintAuth.PublicKey.ToBase58(),
-			FeePayer:    feePayer.PublicKey.ToBase58(),
-			Decimals:    uint8(arg.Decimals),
-			TokenName:   arg.Name,
-			TokenSymbol: arg.Symbol,
+			Mint:            mint.PublicKey.ToBase58(),
+			Owner:           mintAuth.PublicKey.ToBase58(),
+			FeePayer:        feePayer.PublicKey.ToBase58(),
+			Decimals:        uint8(arg.Decimals),
+			TokenName:       arg.Name,
+			TokenSymbol:     arg.Symbol,
+			FreezeAuthority: arg.FreezeAuthority,
 		})).
 		Build(ctx)
 	if err != nil {
@@ -183,7 +185,7 @@ func mintFungibleToken(ctx context.Context, arg MintFungibleTokenParams) (string
 
 	// wait for transaction to be confirmed
 	color.Yellow("Waiting for transaction to be confirmed...")
-	status, err := client.WaitForTransactionConfirmed(ctx, txSig, time.Minute)
+	status, err := client.WaitForTransactionConfirmed(ctx, txSig, solana.CommitmentFinalized, time.Minute)
 	if err != nil {
 		return "", fmt.Errorf("failed to wait for transaction to be confirmed: %w", err)
 	}
@@ -280,7 +282,7 @@ func mintFungibleToken(ctx context.Context, arg MintFungibleTokenParams) (string
 
 	// wait for transaction to be confirmed
 	color.Yellow("Waiting for transaction to be confirmed...")
-	status, err = client.WaitForTransactionConfirmed(ctx, txSig, time.Minute)
+	status, err = client.WaitForTransactionConfirmed(ctx, txSig, solana.CommitmentFinalized, time.Minute)
 	if err != nil {
 		return "", fmt.Errorf("failed to wait for transaction to be confirmed: %w", err)
 	}
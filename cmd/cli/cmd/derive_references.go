@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+// deriveReferencesCmd represents the deriveReferences command
+var deriveReferencesCmd = &cobra.Command{
+	Use:     "derive-references",
+	Aliases: []string{"refs"},
+	Short:   "Derives the references of a payment transaction",
+	Long: `
+Derives the reference public keys of the payment transaction from the PAYMENT_REFERENCE_SEED
+and prints them to the console, e.g. to find the transactions on-chain for the reconciliation
+after a data loss. The first reference is the main one, the next ones are the references
+of the extra instructions, so the transaction can be found by any of them.
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		seed, err := base64.StdEncoding.DecodeString(cmd.Flag("seed").Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to decode seed: %w", err)
+		}
+		paymentID, err := uuid.Parse(cmd.Flag("payment").Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to parse payment id: %w", err)
+		}
+		transactionID, err := uuid.Parse(cmd.Flag("transaction").Value.String())
+		if err != nil {
+			return fmt.Errorf("failed to parse transaction id: %w", err)
+		}
+		count, err := cmd.Flags().GetUint32("count")
+		if err != nil {
+			return fmt.Errorf("count: %w", err)
+		}
+
+		deriver, err := payments.NewReferenceDeriver(seed)
+		if err != nil {
+			return err
+		}
+
+		color.Green("\nReferences of the transaction %s", transactionID)
+		fmt.Println("---------------------------------------------------------------------------------")
+		for i := uint32(0); i < count; i++ {
+			fmt.Printf("%d: %s\n", i, deriver.Reference(paymentID, transactionID, i))
+		}
+		fmt.Println("---------------------------------------------------------------------------------")
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deriveReferencesCmd)
+
+	deriveReferencesCmd.Flags().String("seed", "", "Base64 encoded PAYMENT_REFERENCE_SEED.")
+	deriveReferencesCmd.Flags().String("payment", "", "Payment ID.")
+	deriveReferencesCmd.Flags().String("transaction", "", "Transaction ID.")
+	deriveReferencesCmd.Flags().Uint32("count", 4, "Number of the references to derive.")
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/internal/envelope"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// newKEKCmd represents the newKEK command
+var newKEKCmd = &cobra.Command{
+	Use:     "new-kek",
+	Aliases: []string{"kek"},
+	Short:   "Generates a new key encryption key",
+	Long: `
+Generates a new random 32 bytes key encryption key (KEK) and prints it base64 encoded to the console.
+The KEK is used to encrypt secrets stored in the database (envelope encryption).
+To rotate the key, set the new key as primary and keep the previous one in the list of old keys
+until all stored secrets are re-encrypted.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		key, err := envelope.GenerateKey()
+		if err != nil {
+			panic(err)
+		}
+
+		color.Green("\nNew key encryption key generated")
+		bold := color.New(color.Bold).SprintFunc()
+		fmt.Println("---------------------------------------------------------------------------------")
+		fmt.Println(bold("Key: "), key)
+		fmt.Println("---------------------------------------------------------------------------------")
+		color.Yellow("Save the key somewhere safe, data encrypted with it can't be decrypted without it")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(newKEKCmd)
+}
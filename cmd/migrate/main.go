@@ -5,20 +5,40 @@ import (
 	"fmt"
 	"log"
 
-	_ "github.com/lib/pq" // init pg driver
+	_ "github.com/go-sql-driver/mysql" // init mysql driver
+	_ "github.com/lib/pq"              // init pg driver
 
 	"github.com/dmitrymomot/go-env"
 	migrate "github.com/rubenv/sql-migrate"
 )
 
 var (
+	dbDriver        = env.GetString("DATABASE_DRIVER", "postgres") // postgres or mysql
 	dbConnString    = env.MustString("DATABASE_URL")
-	migrationsDir   = env.GetString("DATABASE_MIGRATIONS_DIR", "./repository/sql/migrations")
+	migrationsDir   = env.GetString("DATABASE_MIGRATIONS_DIR", defaultMigrationsDir[dbDriver])
 	migrationsTable = env.GetString("DATABASE_MIGRATIONS_TABLE", "migrations")
+
+	// Optional monthly partitioning of the high-volume tables
+	partitioningEnabled = env.GetBool("DATABASE_PARTITIONING_ENABLED", false)
+	partitioningDir     = env.GetString("DATABASE_PARTITIONING_DIR", "./repository/sql/partitioning")
+	partitioningTable   = env.GetString("DATABASE_PARTITIONING_TABLE", "migrations_partitioning")
 )
 
+// defaultMigrationsDir is the schema of each supported database driver.
+var defaultMigrationsDir = map[string]string{
+	"postgres": "./repository/sql/migrations",
+	"mysql":    "./repository/mysql/sql/migrations",
+}
+
 func main() {
-	db, err := sql.Open("postgres", dbConnString)
+	if _, ok := defaultMigrationsDir[dbDriver]; !ok {
+		log.Fatalf("unsupported database driver: %s", dbDriver)
+	}
+	if partitioningEnabled && dbDriver != "postgres" {
+		log.Fatalf("partitioning is not supported by %s database driver", dbDriver)
+	}
+
+	db, err := sql.Open(dbDriver, dbConnString)
 	if err != nil {
 		log.Fatalf("init db connection error: %v", err)
 	}
@@ -34,10 +54,26 @@ func main() {
 	migrations := &migrate.FileMigrationSource{
 		Dir: migrationsDir,
 	}
-	n, err := m.Exec(db, "postgres", migrations, migrate.Up)
+	n, err := m.Exec(db, dbDriver, migrations, migrate.Up)
 	if err != nil {
 		log.Fatalf("could not exec migrations: %v", err)
 	}
 
 	fmt.Printf("Applied %d migrations!\n", n)
+
+	if !partitioningEnabled {
+		return
+	}
+
+	// The partitioning migrations are tracked separately,
+	// since they are applied on top of the regular ones.
+	pm := migrate.MigrationSet{
+		TableName: partitioningTable,
+	}
+	n, err = pm.Exec(db, "postgres", &migrate.FileMigrationSource{Dir: partitioningDir}, migrate.Up)
+	if err != nil {
+		log.Fatalf("could not exec partitioning migrations: %v", err)
+	}
+
+	fmt.Printf("Applied %d partitioning migrations!\n", n)
 }
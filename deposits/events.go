@@ -0,0 +1,27 @@
+package deposits
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/events"
+)
+
+type depositsEnqueuer interface {
+	CheckDeposits(ctx context.Context, address string) error
+}
+
+// WalletNotificationListener enqueues the deposits check on the watched wallet account notification.
+func WalletNotificationListener(enq depositsEnqueuer) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		if payload == nil {
+			return nil
+		}
+
+		p, ok := payload.(events.AccountPayload)
+		if !ok {
+			return nil
+		}
+
+		return enq.CheckDeposits(context.Background(), p.Address)
+	}
+}
@@ -0,0 +1,149 @@
+package deposits
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// Service records inbound transfers to the merchant wallet
+	// which are not matched to any payment reference.
+	Service struct {
+		repo   depositRepository
+		sol    solanaClient
+		wallet string
+		mints  []string
+		limit  int
+
+		// watched account address -> mint address
+		accounts map[string]string
+	}
+
+	// ServiceOption is a function that configures the deposits service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new deposits service for the given merchant wallet.
+// The wallet itself is watched for SOL deposits, and its associated token accounts
+// are watched for deposits of the mints set with WithMints.
+func NewService(repo depositRepository, sol solanaClient, wallet string, opts ...ServiceOption) *Service {
+	if wallet == "" {
+		panic("wallet address is required")
+	}
+
+	s := &Service{
+		repo:     repo,
+		sol:      sol,
+		wallet:   wallet,
+		limit:    10,
+		accounts: map[string]string{wallet: solana.NativeMint},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for _, mint := range s.mints {
+		if mint == solana.NativeMint {
+			continue
+		}
+		ata, err := solana.AssociatedTokenAddress(wallet, mint)
+		if err != nil {
+			panic(fmt.Sprintf("failed to derive token account of mint %s: %v", mint, err))
+		}
+		s.accounts[ata] = mint
+	}
+
+	return s
+}
+
+// WithMints sets the SPL token mints to watch deposits of.
+func WithMints(mints ...string) ServiceOption {
+	return func(s *Service) {
+		s.mints = append(s.mints, mints...)
+	}
+}
+
+// WithSignaturesLimit sets the number of the latest transactions to check on each notification.
+// Default is 10.
+func WithSignaturesLimit(limit int) ServiceOption {
+	return func(s *Service) {
+		if limit > 0 {
+			s.limit = limit
+		}
+	}
+}
+
+// Addresses returns the account addresses to subscribe to: the wallet and its token accounts.
+func (s *Service) Addresses() []string {
+	result := make([]string, 0, len(s.accounts))
+	for addr := range s.accounts {
+		result = append(result, addr)
+	}
+	return result
+}
+
+// CheckDeposits checks the latest transactions of the watched address and records
+// inbound transfers which are not matched to any payment reference.
+// Returns the newly recorded deposits.
+func (s *Service) CheckDeposits(ctx context.Context, address string) ([]Deposit, error) {
+	mint, ok := s.accounts[address]
+	if !ok {
+		return nil, fmt.Errorf("address %s is not watched", address)
+	}
+
+	signatures, err := s.sol.GetSignaturesForAddress(ctx, address, s.limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest transactions: %w", err)
+	}
+
+	var result []Deposit
+	for _, sig := range signatures {
+		exists, err := s.repo.DepositExists(ctx, repository.DepositExistsParams{
+			TxSignature: sig,
+			Mint:        mint,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to check deposit: %w", err)
+		}
+		if exists {
+			continue
+		}
+
+		transfer, err := s.sol.GetInboundTransfer(ctx, sig, s.wallet, mint)
+		if err != nil {
+			return result, fmt.Errorf("failed to get transaction %s: %w", sig, err)
+		}
+		if transfer.Amount == 0 {
+			continue
+		}
+
+		// transfers made via generated payment transactions contain the payment reference
+		matched, err := s.repo.AnyTransactionReferenceExists(ctx, transfer.Accounts)
+		if err != nil {
+			return result, fmt.Errorf("failed to match transaction %s: %w", sig, err)
+		}
+		if matched {
+			continue
+		}
+
+		d, err := s.repo.CreateDeposit(ctx, repository.CreateDepositParams{
+			Wallet:       s.wallet,
+			Mint:         mint,
+			Amount:       int64(transfer.Amount),
+			SourceWallet: sql.NullString{String: transfer.Sender, Valid: transfer.Sender != ""},
+			TxSignature:  sig,
+		})
+		if err != nil {
+			return result, fmt.Errorf("failed to store deposit: %w", err)
+		}
+
+		result = append(result, castFromRepositoryDeposit(d))
+	}
+
+	return result, nil
+}
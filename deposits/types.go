@@ -0,0 +1,58 @@
+package deposits
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/google/uuid"
+)
+
+// Worker task types
+const (
+	TaskCheckDeposits = "deposits:check_deposits"
+)
+
+// CheckDepositsPayload is the payload for the deposits:check_deposits task.
+type CheckDepositsPayload struct {
+	Address string `json:"address"`
+}
+
+type (
+	// Deposit is an inbound transfer to the merchant wallet,
+	// which is not matched to any payment reference.
+	Deposit struct {
+		ID           uuid.UUID `json:"id"`
+		Wallet       string    `json:"wallet"`
+		Mint         string    `json:"mint"`
+		Amount       uint64    `json:"amount"`
+		SourceWallet string    `json:"source_wallet,omitempty"`
+		Signature    string    `json:"signature"`
+		CreatedAt    time.Time `json:"created_at"`
+	}
+
+	depositRepository interface {
+		CreateDeposit(ctx context.Context, arg repository.CreateDepositParams) (repository.Deposit, error)
+		DepositExists(ctx context.Context, arg repository.DepositExistsParams) (bool, error)
+		AnyTransactionReferenceExists(ctx context.Context, references []string) (bool, error)
+	}
+
+	solanaClient interface {
+		GetSignaturesForAddress(ctx context.Context, base58Addr string, limit int) ([]string, error)
+		GetInboundTransfer(ctx context.Context, txSignature, base58Addr, base58MintAddr string) (*solana.InboundTransfer, error)
+	}
+)
+
+// castFromRepositoryDeposit converts a repository deposit to a deposit.
+func castFromRepositoryDeposit(d repository.Deposit) Deposit {
+	return Deposit{
+		ID:           d.ID,
+		Wallet:       d.Wallet,
+		Mint:         d.Mint,
+		Amount:       uint64(d.Amount),
+		SourceWallet: d.SourceWallet.String,
+		Signature:    d.TxSignature,
+		CreatedAt:    d.CreatedAt,
+	}
+}
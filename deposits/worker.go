@@ -0,0 +1,60 @@
+package deposits
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/hibiken/asynq"
+)
+
+type (
+	// Worker is a task handler for the deposit checks.
+	Worker struct {
+		svc  service
+		emit func(events.EventName, interface{})
+	}
+
+	service interface {
+		CheckDeposits(ctx context.Context, address string) ([]Deposit, error)
+	}
+)
+
+// NewWorker creates a new deposits task handler.
+// The emit function is used to fire the deposit.received event for each new deposit.
+func NewWorker(svc service, emit func(events.EventName, interface{})) *Worker {
+	return &Worker{svc: svc, emit: emit}
+}
+
+// Register registers task handlers for the deposit checks.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskCheckDeposits, w.CheckDeposits)
+}
+
+// CheckDeposits records new deposits to the watched address and emits the deposit.received events.
+func (w *Worker) CheckDeposits(ctx context.Context, t *asynq.Task) error {
+	var p CheckDepositsPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	deposits, err := w.svc.CheckDeposits(ctx, p.Address)
+	// emit events for the recorded deposits even if the check failed in the middle
+	for _, d := range deposits {
+		w.emit(events.DepositReceived, events.DepositReceivedPayload{
+			Occurrence:   events.OccurredNow(),
+			DepositID:    d.ID.String(),
+			Wallet:       d.Wallet,
+			Mint:         d.Mint,
+			Amount:       d.Amount,
+			SourceWallet: d.SourceWallet,
+			Signature:    d.Signature,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check deposits: %w", err)
+	}
+
+	return nil
+}
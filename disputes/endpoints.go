@@ -0,0 +1,134 @@
+package disputes
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+// Max number of evidence URLs per dispute.
+const maxEvidenceURLs = 20
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		Open    endpoint.Endpoint
+		Resolve endpoint.Endpoint
+		List    endpoint.Endpoint
+	}
+
+	// OpenRequest is the request type for the Open method.
+	OpenRequest struct {
+		PaymentID    uuid.UUID `json:"-"`
+		Reason       string    `json:"reason" validate:"required|min_len:2|max_len:500"`
+		EvidenceURLs []string  `json:"evidence_urls,omitempty" validate:"-"`
+	}
+
+	// ResolveRequest is the request type for the Resolve method.
+	ResolveRequest struct {
+		PaymentID  uuid.UUID `json:"-"`
+		Resolution string    `json:"resolution,omitempty" validate:"max_len:500"`
+	}
+
+	// DisputeResponse is the response type for the Open and Resolve methods.
+	DisputeResponse struct {
+		Dispute *Dispute `json:"dispute"`
+	}
+
+	// ListResponse is the response type for the List method.
+	ListResponse struct {
+		Disputes []Dispute `json:"disputes"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		Open:    makeOpenEndpoint(s),
+		Resolve: makeResolveEndpoint(s),
+		List:    makeListEndpoint(s),
+	}
+}
+
+// makeOpenEndpoint returns an endpoint function for the Open method.
+func makeOpenEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(OpenRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+		if err := validateEvidenceURLs(req.EvidenceURLs); err != nil {
+			return nil, err
+		}
+
+		dispute, err := s.Open(ctx, OpenParams{
+			PaymentID:    req.PaymentID,
+			Reason:       req.Reason,
+			EvidenceURLs: req.EvidenceURLs,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return DisputeResponse{Dispute: dispute}, nil
+	}
+}
+
+// makeResolveEndpoint returns an endpoint function for the Resolve method.
+func makeResolveEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ResolveRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		dispute, err := s.Resolve(ctx, req.PaymentID, req.Resolution)
+		if err != nil {
+			return nil, err
+		}
+
+		return DisputeResponse{Dispute: dispute}, nil
+	}
+}
+
+// makeListEndpoint returns an endpoint function for the List method.
+func makeListEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		paymentID, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		disputes, err := s.List(ctx, paymentID)
+		if err != nil {
+			return nil, err
+		}
+
+		return ListResponse{Disputes: disputes}, nil
+	}
+}
+
+// validateEvidenceURLs checks that the evidence URLs are absolute http(s) URLs.
+func validateEvidenceURLs(urls []string) error {
+	if len(urls) > maxEvidenceURLs {
+		return fmt.Errorf("%w: too many evidence urls, max %d", ErrInvalidRequest, maxEvidenceURLs)
+	}
+	for _, v := range urls {
+		u, err := url.Parse(v)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: invalid evidence url: %s", ErrInvalidRequest, v)
+		}
+	}
+	return nil
+}
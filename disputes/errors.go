@@ -0,0 +1,11 @@
+package disputes
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest         = errors.New("invalid_request")
+	ErrPaymentNotDisputable   = errors.New("payment_not_disputable")
+	ErrPaymentAlreadyDisputed = errors.New("payment_already_disputed")
+	ErrPaymentNotDisputed     = errors.New("payment_not_disputed")
+)
@@ -0,0 +1,142 @@
+package disputes
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// disputableStatuses are the statuses of the paid payments, which can be disputed.
+var disputableStatuses = map[repository.PaymentStatus]bool{
+	repository.PaymentStatusCompleted: true,
+	repository.PaymentStatusHeld:      true,
+	repository.PaymentStatusReleased:  true,
+}
+
+// Service manages the payment disputes.
+// It doesn't resolve disputes itself, it freezes the payment and notifies
+// the merchant, so the resolution is up to the marketplace process.
+type Service struct {
+	repo disputeRepository
+	emit func(events.EventName, interface{})
+}
+
+// NewService creates a new disputes service.
+// The emit function is used to fire the payment.disputed and payment.dispute_resolved events.
+func NewService(repo disputeRepository, emit func(events.EventName, interface{})) *Service {
+	return &Service{repo: repo, emit: emit}
+}
+
+// Open marks the paid payment as disputed.
+// The disputed payment is excluded from the escrow release until the dispute is resolved.
+func (s *Service) Open(ctx context.Context, params OpenParams) (*Dispute, error) {
+	payment, err := s.repo.GetPayment(ctx, params.PaymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment.Status == repository.PaymentStatusDisputed {
+		return nil, ErrPaymentAlreadyDisputed
+	}
+	if !disputableStatuses[payment.Status] {
+		return nil, fmt.Errorf("%w: payment is %s", ErrPaymentNotDisputable, payment.Status)
+	}
+
+	if params.EvidenceURLs == nil {
+		params.EvidenceURLs = []string{}
+	}
+	evidence, err := json.Marshal(params.EvidenceURLs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode evidence urls: %w", err)
+	}
+
+	// the unique index on the open disputes guards against concurrent requests
+	d, err := s.repo.CreatePaymentDispute(ctx, repository.CreatePaymentDisputeParams{
+		PaymentID:      params.PaymentID,
+		Reason:         params.Reason,
+		EvidenceUrls:   evidence,
+		PreviousStatus: payment.Status,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment dispute: %w", err)
+	}
+
+	if _, err := s.repo.UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
+		ID:     params.PaymentID,
+		Status: repository.PaymentStatusDisputed,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	result := castFromRepositoryDispute(d)
+	s.emit(events.PaymentDisputed, events.PaymentDisputedPayload{
+		PaymentID:    events.PaymentID{PaymentID: result.PaymentID.String()},
+		Occurrence:   events.OccurredNow(),
+		DisputeID:    result.ID.String(),
+		Reason:       result.Reason,
+		EvidenceURLs: result.EvidenceURLs,
+	})
+
+	return &result, nil
+}
+
+// Resolve closes the open dispute of the payment with the given resolution note
+// and restores the payment status it had before the dispute.
+func (s *Service) Resolve(ctx context.Context, paymentID uuid.UUID, resolution string) (*Dispute, error) {
+	open, err := s.repo.GetOpenPaymentDispute(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotDisputed
+		}
+		return nil, fmt.Errorf("failed to get open payment dispute: %w", err)
+	}
+
+	d, err := s.repo.ResolvePaymentDispute(ctx, repository.ResolvePaymentDisputeParams{
+		ID:         open.ID,
+		Resolution: sql.NullString{String: resolution, Valid: resolution != ""},
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotDisputed
+		}
+		return nil, fmt.Errorf("failed to resolve payment dispute: %w", err)
+	}
+
+	if _, err := s.repo.UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
+		ID:     paymentID,
+		Status: d.PreviousStatus,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update payment status: %w", err)
+	}
+
+	result := castFromRepositoryDispute(d)
+	s.emit(events.PaymentDisputeResolved, events.PaymentDisputeResolvedPayload{
+		PaymentID:  events.PaymentID{PaymentID: result.PaymentID.String()},
+		Occurrence: events.OccurredNow(),
+		DisputeID:  result.ID.String(),
+		Resolution: result.Resolution,
+		Status:     result.PreviousStatus,
+	})
+
+	return &result, nil
+}
+
+// List returns all the disputes of the payment, the latest first.
+func (s *Service) List(ctx context.Context, paymentID uuid.UUID) ([]Dispute, error) {
+	items, err := s.repo.GetPaymentDisputes(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment disputes: %w", err)
+	}
+
+	result := make([]Dispute, 0, len(items))
+	for _, d := range items {
+		result = append(result, castFromRepositoryDispute(d))
+	}
+
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package disputes_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/disputes"
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	payment  repository.Payment
+	disputes []repository.PaymentDispute
+}
+
+func (r *repoMock) GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	return r.payment, nil
+}
+
+func (r *repoMock) UpdatePaymentStatus(ctx context.Context, arg repository.UpdatePaymentStatusParams) (repository.Payment, error) {
+	r.payment.Status = arg.Status
+	return r.payment, nil
+}
+
+func (r *repoMock) CreatePaymentDispute(ctx context.Context, arg repository.CreatePaymentDisputeParams) (repository.PaymentDispute, error) {
+	d := repository.PaymentDispute{
+		ID:             uuid.New(),
+		PaymentID:      arg.PaymentID,
+		Reason:         arg.Reason,
+		EvidenceUrls:   arg.EvidenceUrls,
+		PreviousStatus: arg.PreviousStatus,
+		CreatedAt:      time.Now(),
+	}
+	r.disputes = append(r.disputes, d)
+	return d, nil
+}
+
+func (r *repoMock) GetOpenPaymentDispute(ctx context.Context, paymentID uuid.UUID) (repository.PaymentDispute, error) {
+	for _, d := range r.disputes {
+		if d.PaymentID == paymentID && !d.ResolvedAt.Valid {
+			return d, nil
+		}
+	}
+	return repository.PaymentDispute{}, sql.ErrNoRows
+}
+
+func (r *repoMock) GetPaymentDisputes(ctx context.Context, paymentID uuid.UUID) ([]repository.PaymentDispute, error) {
+	return r.disputes, nil
+}
+
+func (r *repoMock) ResolvePaymentDispute(ctx context.Context, arg repository.ResolvePaymentDisputeParams) (repository.PaymentDispute, error) {
+	for i, d := range r.disputes {
+		if d.ID == arg.ID && !d.ResolvedAt.Valid {
+			d.Resolution = arg.Resolution
+			d.ResolvedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			r.disputes[i] = d
+			return d, nil
+		}
+	}
+	return repository.PaymentDispute{}, sql.ErrNoRows
+}
+
+func TestDisputeWorkflow(t *testing.T) {
+	ctx := context.Background()
+	pid := uuid.New()
+	repo := &repoMock{payment: repository.Payment{ID: pid, Status: repository.PaymentStatusHeld}}
+
+	var fired []events.EventName
+	svc := disputes.NewService(repo, func(name events.EventName, payload interface{}) {
+		fired = append(fired, name)
+	})
+
+	dispute, err := svc.Open(ctx, disputes.OpenParams{
+		PaymentID:    pid,
+		Reason:       "item not received",
+		EvidenceURLs: []string{"https://example.com/chat.png"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://example.com/chat.png"}, dispute.EvidenceURLs)
+	require.Equal(t, repository.PaymentStatusDisputed, repo.payment.Status)
+
+	_, err = svc.Open(ctx, disputes.OpenParams{PaymentID: pid, Reason: "again"})
+	require.ErrorIs(t, err, disputes.ErrPaymentAlreadyDisputed)
+
+	dispute, err = svc.Resolve(ctx, pid, "refunded off-chain")
+	require.NoError(t, err)
+	require.NotNil(t, dispute.ResolvedAt)
+	require.Equal(t, repository.PaymentStatusHeld, repo.payment.Status)
+
+	_, err = svc.Resolve(ctx, pid, "")
+	require.ErrorIs(t, err, disputes.ErrPaymentNotDisputed)
+
+	require.Equal(t, []events.EventName{events.PaymentDisputed, events.PaymentDisputeResolved}, fired)
+}
+
+func TestOpenNotPaidPayment(t *testing.T) {
+	repo := &repoMock{payment: repository.Payment{ID: uuid.New(), Status: repository.PaymentStatusNew}}
+	svc := disputes.NewService(repo, func(events.EventName, interface{}) {})
+
+	_, err := svc.Open(context.Background(), disputes.OpenParams{PaymentID: repo.payment.ID, Reason: "fraud"})
+	require.ErrorIs(t, err, disputes.ErrPaymentNotDisputable)
+}
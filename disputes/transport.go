@@ -0,0 +1,127 @@
+package disputes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the payment disputes API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/{payment_id}", httptransport.NewServer(
+		e.List,
+		decodeListRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/{payment_id}", httptransport.NewServer(
+		e.Open,
+		decodeOpenRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/{payment_id}/resolve", httptransport.NewServer(
+		e.Resolve,
+		decodeResolveRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		return http.StatusPreconditionFailed, err
+	case errors.Is(err, ErrPaymentAlreadyDisputed),
+		errors.Is(err, ErrPaymentNotDisputable),
+		errors.Is(err, ErrPaymentNotDisputed):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeListRequest is a transport/http.DecodeRequestFunc that decodes
+// the payment ID from the URL path.
+func decodeListRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return parsePaymentID(r)
+}
+
+// decodeOpenRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeOpenRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req OpenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	pid, err := parsePaymentID(r)
+	if err != nil {
+		return nil, err
+	}
+	req.PaymentID = pid
+
+	return req, nil
+}
+
+// decodeResolveRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body. The body is optional.
+func decodeResolveRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req ResolveRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+		}
+	}
+
+	pid, err := parsePaymentID(r)
+	if err != nil {
+		return nil, err
+	}
+	req.PaymentID = pid
+
+	return req, nil
+}
+
+// parsePaymentID parses the payment ID from the URL path.
+func parsePaymentID(r *http.Request) (uuid.UUID, error) {
+	pid, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid payment id: %v", ErrInvalidRequest, err)
+	}
+	return pid, nil
+}
@@ -0,0 +1,60 @@
+package disputes
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	// Dispute is a flag raised on a paid payment, e.g. by a marketplace on a buyer complaint.
+	// The disputed payment is frozen until the dispute is resolved.
+	Dispute struct {
+		ID             uuid.UUID  `json:"id"`
+		PaymentID      uuid.UUID  `json:"payment_id"`
+		Reason         string     `json:"reason"`
+		EvidenceURLs   []string   `json:"evidence_urls,omitempty"`
+		PreviousStatus string     `json:"previous_status"` // payment status restored on resolution
+		Resolution     string     `json:"resolution,omitempty"`
+		ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+		CreatedAt      time.Time  `json:"created_at"`
+	}
+
+	// OpenParams defines the parameters of a new dispute.
+	OpenParams struct {
+		PaymentID    uuid.UUID
+		Reason       string
+		EvidenceURLs []string
+	}
+
+	disputeRepository interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error)
+		UpdatePaymentStatus(ctx context.Context, arg repository.UpdatePaymentStatusParams) (repository.Payment, error)
+		CreatePaymentDispute(ctx context.Context, arg repository.CreatePaymentDisputeParams) (repository.PaymentDispute, error)
+		GetOpenPaymentDispute(ctx context.Context, paymentID uuid.UUID) (repository.PaymentDispute, error)
+		GetPaymentDisputes(ctx context.Context, paymentID uuid.UUID) ([]repository.PaymentDispute, error)
+		ResolvePaymentDispute(ctx context.Context, arg repository.ResolvePaymentDisputeParams) (repository.PaymentDispute, error)
+	}
+)
+
+// castFromRepositoryDispute converts a repository payment dispute to a dispute.
+func castFromRepositoryDispute(d repository.PaymentDispute) Dispute {
+	result := Dispute{
+		ID:             d.ID,
+		PaymentID:      d.PaymentID,
+		Reason:         d.Reason,
+		PreviousStatus: string(d.PreviousStatus),
+		Resolution:     d.Resolution.String,
+		CreatedAt:      d.CreatedAt,
+	}
+	if len(d.EvidenceUrls) > 0 {
+		_ = json.Unmarshal(d.EvidenceUrls, &result.EvidenceURLs)
+	}
+	if d.ResolvedAt.Valid {
+		result.ResolvedAt = &d.ResolvedAt.Time
+	}
+	return result
+}
@@ -0,0 +1,291 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Default event bus settings.
+const (
+	DefaultBusStream        = "checkout:events:stream"
+	DefaultBusConsumerGroup = "checkout"
+	DefaultBusMaxLen        = 100000
+	DefaultBusClaimIdle     = time.Minute
+
+	busPublishTimeout = 5 * time.Second
+	busReadBlock      = 5 * time.Second
+	busReadCount      = 100
+)
+
+type (
+	// RedisBus is the Emitter backed by a Redis stream, so the listeners see the events emitted on any instance.
+	// The events are appended to the stream and read by the consumer group of all API instances:
+	// every event is handled by the listeners of one instance and acknowledged after they return,
+	// so the listeners with side effects, e.g. the TransactionUpdated ones, run once per event.
+	// The events of the instance which stopped before the acknowledgement are claimed by another one
+	// after DefaultBusClaimIdle. It requires Redis 6.2 or later.
+	// The listeners which must see every event on every instance, e.g. the status streams, use RedisFanout.
+	RedisBus struct {
+		client    redis.UniversalClient
+		stream    string
+		group     string
+		consumer  string
+		maxLen    int64
+		claimIdle time.Duration
+		log       Logger
+
+		mu        sync.RWMutex
+		listeners map[EventName][]Listener
+	}
+
+	// RedisBusOption is a function that configures the event bus.
+	RedisBusOption func(*RedisBus)
+
+	// busMessage is the event appended to the stream.
+	busMessage struct {
+		Name    EventName       `json:"name"`
+		Payload json.RawMessage `json:"payload"`
+	}
+)
+
+// NewRedisBus creates a new event bus on top of the Redis client.
+// Run must be called to deliver the events to the listeners.
+func NewRedisBus(client redis.UniversalClient, log Logger, opts ...RedisBusOption) *RedisBus {
+	b := &RedisBus{
+		client:    client,
+		stream:    DefaultBusStream,
+		group:     DefaultBusConsumerGroup,
+		maxLen:    DefaultBusMaxLen,
+		claimIdle: DefaultBusClaimIdle,
+		log:       log,
+		listeners: make(map[EventName][]Listener),
+	}
+	// the host name is stable across the restarts of the instance, so it claims its own pending events back
+	if host, err := os.Hostname(); err == nil && host != "" {
+		b.consumer = host
+	} else {
+		b.consumer = uuid.New().String()
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithBusStream configures the name of the stream.
+func WithBusStream(stream string) RedisBusOption {
+	return func(b *RedisBus) {
+		b.stream = stream
+	}
+}
+
+// WithBusConsumerGroup configures the consumer group of the instances sharing the events.
+func WithBusConsumerGroup(group string) RedisBusOption {
+	return func(b *RedisBus) {
+		b.group = group
+	}
+}
+
+// WithBusMaxLen configures the approximate number of the events kept in the stream.
+func WithBusMaxLen(n int64) RedisBusOption {
+	return func(b *RedisBus) {
+		b.maxLen = n
+	}
+}
+
+// WithBusClaimIdle configures how long the event stays unacknowledged before another instance claims it.
+func WithBusClaimIdle(d time.Duration) RedisBusOption {
+	return func(b *RedisBus) {
+		b.claimIdle = d
+	}
+}
+
+// Emit appends the event to the stream. If Redis is not available, the event is delivered
+// to the listeners of this instance, so the events are not lost while the bus is down.
+func (b *RedisBus) Emit(name EventName, payload interface{}) {
+	if err := b.publish(name, payload); err != nil {
+		b.log.Errorf("events bus: %s; delivered locally", err.Error())
+		go b.dispatch(name, payload)
+	}
+}
+
+// On registers the listeners for the given event name.
+func (b *RedisBus) On(name EventName, listeners ...Listener) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listeners[name] = append(b.listeners[name], listeners...)
+}
+
+// ListenEvents registers the listener for the given event names.
+func (b *RedisBus) ListenEvents(listener Listener, names ...EventName) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, name := range names {
+		b.listeners[name] = append(b.listeners[name], listener)
+	}
+}
+
+// Run reads the events from the stream and delivers them to the listeners until the context is cancelled.
+func (b *RedisBus) Run(ctx context.Context) error {
+	err := b.client.XGroupCreateMkStream(ctx, b.stream, b.group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("events bus: create consumer group %s: %w", b.group, err)
+	}
+
+	b.log.Infof("events bus: consuming %s as %s/%s", b.stream, b.group, b.consumer)
+
+	lastClaim := time.Time{}
+	for {
+		if ctx.Err() != nil {
+			b.log.Infof("events bus: stopped")
+			return nil
+		}
+
+		if time.Since(lastClaim) > b.claimIdle {
+			if err := b.claim(ctx); err != nil && ctx.Err() == nil {
+				b.log.Errorf("events bus: %s", err.Error())
+			}
+			lastClaim = time.Now()
+		}
+
+		streams, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    b.group,
+			Consumer: b.consumer,
+			Streams:  []string{b.stream, ">"},
+			Count:    busReadCount,
+			Block:    busReadBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			b.log.Errorf("events bus: read %s: %s", b.stream, err.Error())
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, s := range streams {
+			b.handle(ctx, s.Messages)
+		}
+	}
+}
+
+// claim takes over the events left unacknowledged by the stopped instances.
+func (b *RedisBus) claim(ctx context.Context) error {
+	start := "0-0"
+	for {
+		messages, next, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   b.stream,
+			Group:    b.group,
+			Consumer: b.consumer,
+			MinIdle:  b.claimIdle,
+			Start:    start,
+			Count:    busReadCount,
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("claim pending events: %w", err)
+		}
+
+		b.handle(ctx, messages)
+
+		if next == "0-0" || len(messages) == 0 {
+			return nil
+		}
+		start = next
+	}
+}
+
+// handle delivers the stream messages to the listeners and acknowledges them.
+// The malformed messages are acknowledged as well, so they're not claimed over and over.
+func (b *RedisBus) handle(ctx context.Context, messages []redis.XMessage) {
+	for _, m := range messages {
+		if err := b.deliver(m); err != nil {
+			b.log.Errorf("events bus: skip message %s: %s", m.ID, err.Error())
+		}
+		if err := b.client.XAck(ctx, b.stream, b.group, m.ID).Err(); err != nil {
+			b.log.Errorf("events bus: ack message %s: %s", m.ID, err.Error())
+		}
+	}
+}
+
+// deliver decodes the stream message and waits for the listeners to handle it.
+func (b *RedisBus) deliver(m redis.XMessage) error {
+	raw, _ := m.Values["event"].(string)
+
+	var msg busMessage
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		return fmt.Errorf("unmarshal message: %w", err)
+	}
+
+	payload, err := DecodePayload(msg.Name, msg.Payload)
+	if err != nil {
+		return fmt.Errorf("decode %s payload: %w", msg.Name, err)
+	}
+
+	b.dispatch(msg.Name, payload)
+
+	return nil
+}
+
+// dispatch calls the listeners of the event concurrently and waits for them to return.
+func (b *RedisBus) dispatch(name EventName, payload interface{}) {
+	b.mu.RLock()
+	listeners := b.listeners[name]
+	b.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, listener := range listeners {
+		if listener == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(fn Listener) {
+			defer wg.Done()
+			if err := fn(name, payload); err != nil {
+				b.log.Errorf("failed to handle event %s: %s", name, err.Error())
+			}
+		}(listener)
+	}
+	wg.Wait()
+}
+
+// publish appends the event to the stream.
+func (b *RedisBus) publish(name EventName, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", name, err)
+	}
+
+	msg, err := json.Marshal(busMessage{Name: name, Payload: data})
+	if err != nil {
+		return fmt.Errorf("marshal %s message: %w", name, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), busPublishTimeout)
+	defer cancel()
+
+	if err := b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: b.stream,
+		MaxLen: b.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{"event": msg},
+	}).Err(); err != nil {
+		return fmt.Errorf("publish %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -1,5 +1,7 @@
 package events
 
+import "time"
+
 // Predefined
 const (
 	PaymentCreated                   EventName = "payment.created"
@@ -8,12 +10,47 @@ const (
 	PaymentFailed                    EventName = "payment.failed"
 	PaymentExpired                   EventName = "payment.expired"
 	PaymentSucceeded                 EventName = "payment.succeeded"
+	PaymentPartiallyPaid             EventName = "payment.partially_paid"
+	PaymentHeld                      EventName = "payment.held"
+	PaymentReleased                  EventName = "payment.released"
+	PaymentDisputed                  EventName = "payment.disputed"
+	PaymentDisputeResolved           EventName = "payment.dispute_resolved"
 	PaymentLinkGenerated             EventName = "payment.link.generated"
 	TransactionCreated               EventName = "transaction.created"
 	TransactionUpdated               EventName = "transaction.updated"
 	TransactionReferenceNotification EventName = "transaction.reference.notification"
 )
 
+// Operator events. They are not included into AllEvents,
+// so they are not forwarded to the merchant webhook.
+const (
+	WebhookDelivered          EventName = "webhook.delivered"
+	WebhookDeadLettered       EventName = "webhook.dead_lettered"
+	ReconciliationDiscrepancy EventName = "reconciliation.discrepancy"
+)
+
+// Merchant wallet events. They are not bound to a payment,
+// so they are not included into AllEvents.
+const (
+	WalletAccountNotification EventName = "wallet.account.notification"
+	DepositReceived           EventName = "deposit.received"
+)
+
+// Report events. They are not bound to a payment,
+// so they are not included into AllEvents.
+const (
+	ReportCompleted EventName = "report.completed"
+	ReportFailed    EventName = "report.failed"
+)
+
+// Subscription events. They are fired for the installment payments
+// in addition to the payment events, so they are not included into AllEvents.
+const (
+	SubscriptionInstallmentDue    EventName = "subscription.installment_due"
+	SubscriptionInstallmentPaid   EventName = "subscription.installment_paid"
+	SubscriptionInstallmentMissed EventName = "subscription.installment_missed"
+)
+
 var AllEvents = []EventName{
 	PaymentCreated,
 	PaymentProcessing,
@@ -21,6 +58,11 @@ var AllEvents = []EventName{
 	PaymentFailed,
 	PaymentExpired,
 	PaymentSucceeded,
+	PaymentPartiallyPaid,
+	PaymentHeld,
+	PaymentReleased,
+	PaymentDisputed,
+	PaymentDisputeResolved,
 	PaymentLinkGenerated,
 	TransactionCreated,
 	TransactionUpdated,
@@ -38,29 +80,68 @@ type (
 		PaymentID string `json:"payment_id"`
 	}
 
+	// Occurrence is the time the event occurred at, set when the event is fired and kept when it's redelivered,
+	// e.g. by the outbox or the bus. It tells apart the occurrences of the same event with the same data,
+	// e.g. the payment link generated twice, see webhook.EventID.
+	Occurrence struct {
+		OccurredAt time.Time `json:"occurred_at"`
+	}
+
 	PaymentCreatedPayload struct {
 		PaymentID
+		Occurrence
 	}
 
 	PaymentStatusUpdatedPayload struct {
 		PaymentID
+		Occurrence
 		Status string `json:"status"`
 	}
 
+	PaymentReleasedPayload struct {
+		PaymentID
+		Occurrence
+		Recipient string `json:"recipient"`
+		Mint      string `json:"mint"`
+		Amount    uint64 `json:"amount"`
+		Signature string `json:"signature,omitempty"`
+	}
+
+	PaymentDisputedPayload struct {
+		PaymentID
+		Occurrence
+		DisputeID    string   `json:"dispute_id"`
+		Reason       string   `json:"reason"`
+		EvidenceURLs []string `json:"evidence_urls,omitempty"`
+	}
+
+	PaymentDisputeResolvedPayload struct {
+		PaymentID
+		Occurrence
+		DisputeID  string `json:"dispute_id"`
+		Resolution string `json:"resolution,omitempty"`
+		Status     string `json:"status"` // payment status after the dispute is resolved
+	}
+
 	PaymentLinkGeneratedPayload struct {
 		PaymentID
+		Occurrence
 		Link string `json:"link"`
 	}
 
 	TransactionCreatedPayload struct {
 		PaymentID
-		TransactionID string `json:"transaction_id"`
-		Reference     string `json:"reference"`
+		Occurrence
+		TransactionID string   `json:"transaction_id"`
+		Reference     string   `json:"reference"`
+		References    []string `json:"references,omitempty"` // additional references
 	}
 
 	TransactionUpdatedPayload struct {
 		PaymentID
+		Occurrence
 		Reference   string      `json:"reference"`
+		References  []string    `json:"references,omitempty"` // additional references
 		Status      string      `json:"status"`
 		Signature   string      `json:"signature"`
 		Transaction interface{} `json:"transaction,omitempty"`
@@ -69,8 +150,67 @@ type (
 	ReferencePayload struct {
 		Reference string `json:"reference"`
 	}
+
+	AccountPayload struct {
+		Address string `json:"address"`
+	}
+
+	DepositReceivedPayload struct {
+		Occurrence
+		DepositID    string `json:"deposit_id"`
+		Wallet       string `json:"wallet"`
+		Mint         string `json:"mint"`
+		Amount       uint64 `json:"amount"`
+		SourceWallet string `json:"source_wallet,omitempty"`
+		Signature    string `json:"signature"`
+	}
+
+	ReportJobPayload struct {
+		Occurrence
+		JobID  string `json:"job_id"`
+		Kind   string `json:"kind"`
+		Status string `json:"status"`
+		Rows   int64  `json:"rows"`
+		Error  string `json:"error,omitempty"`
+	}
+
+	SubscriptionInstallmentPayload struct {
+		PaymentID
+		Occurrence
+		SubscriptionID string `json:"subscription_id"`
+		InstallmentID  string `json:"installment_id"`
+		Status         string `json:"status"`
+		DueAt          int64  `json:"due_at"` // unix timestamp
+	}
+
+	WebhookDeliveredPayload struct {
+		PaymentID
+		TaskID  string `json:"task_id"`
+		Event   string `json:"event"`
+		Retried int    `json:"retried"`
+	}
+
+	WebhookDeadLetteredPayload struct {
+		TaskID  string `json:"task_id"`
+		Event   string `json:"event"`
+		Retried int    `json:"retried"`
+		Error   string `json:"error"`
+	}
+
+	ReconciliationDiscrepancyPayload struct {
+		PaymentID
+		Reference string `json:"reference,omitempty"`
+		Expected  string `json:"expected"`
+		Actual    string `json:"actual"`
+		Details   string `json:"details,omitempty"`
+	}
 )
 
+// OccurredNow returns the occurrence of the event fired now.
+func OccurredNow() Occurrence {
+	return Occurrence{OccurredAt: time.Now().UTC()}
+}
+
 // GetPaymentID returns payment_id from event payload.
 // This method is required for PaymentIDGetter interface.
 func (p PaymentID) GetPaymentID() string {
@@ -0,0 +1,197 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// Default fanout settings.
+const (
+	DefaultFanoutChannel = "checkout:events"
+	fanoutPublishTimeout = 5 * time.Second
+)
+
+type (
+	// RedisFanout publishes the events emitted on this instance to a Redis channel
+	// and delivers the events from all API instances to its own listeners.
+	// Listeners which must see every event regardless of the instance it was emitted on,
+	// e.g. SSE or WebSocket status streams, are registered on the fanout.
+	// Listeners with side effects, e.g. webhooks, must stay on the source emitter,
+	// so they're called exactly once per event.
+	RedisFanout struct {
+		Emitter
+
+		client     redis.UniversalClient
+		channel    string
+		instanceID string
+		log        Logger
+	}
+
+	// fanoutMessage is the event published to the Redis channel.
+	fanoutMessage struct {
+		Instance string          `json:"instance"`
+		Name     EventName       `json:"name"`
+		Payload  json.RawMessage `json:"payload"`
+	}
+)
+
+// payloadTypes maps the event names to their payload types,
+// so the events received from other instances have the same payload types as the local ones.
+var payloadTypes = map[EventName]interface{}{
+	PaymentCreated:                   PaymentCreatedPayload{},
+	PaymentProcessing:                PaymentStatusUpdatedPayload{},
+	PaymentCancelled:                 PaymentStatusUpdatedPayload{},
+	PaymentFailed:                    PaymentStatusUpdatedPayload{},
+	PaymentExpired:                   PaymentStatusUpdatedPayload{},
+	PaymentSucceeded:                 PaymentStatusUpdatedPayload{},
+	PaymentPartiallyPaid:             PaymentStatusUpdatedPayload{},
+	PaymentHeld:                      PaymentStatusUpdatedPayload{},
+	PaymentReleased:                  PaymentReleasedPayload{},
+	PaymentDisputed:                  PaymentDisputedPayload{},
+	PaymentDisputeResolved:           PaymentDisputeResolvedPayload{},
+	PaymentLinkGenerated:             PaymentLinkGeneratedPayload{},
+	TransactionCreated:               TransactionCreatedPayload{},
+	TransactionUpdated:               TransactionUpdatedPayload{},
+	TransactionReferenceNotification: ReferencePayload{},
+	WebhookDelivered:                 WebhookDeliveredPayload{},
+	WebhookDeadLettered:              WebhookDeadLetteredPayload{},
+	ReconciliationDiscrepancy:        ReconciliationDiscrepancyPayload{},
+	WalletAccountNotification:        AccountPayload{},
+	DepositReceived:                  DepositReceivedPayload{},
+	ReportCompleted:                  ReportJobPayload{},
+	ReportFailed:                     ReportJobPayload{},
+	SubscriptionInstallmentDue:       SubscriptionInstallmentPayload{},
+	SubscriptionInstallmentPaid:      SubscriptionInstallmentPayload{},
+	SubscriptionInstallmentMissed:    SubscriptionInstallmentPayload{},
+}
+
+// NewRedisFanout creates a new Redis fanout of the events emitted by the source emitter.
+// If no event names are given, AllEvents are published.
+// Run must be called to receive the events from other instances.
+func NewRedisFanout(source Emitter, client redis.UniversalClient, channel string, log Logger, names ...EventName) *RedisFanout {
+	if channel == "" {
+		channel = DefaultFanoutChannel
+	}
+	if len(names) == 0 {
+		names = AllEvents
+	}
+
+	f := &RedisFanout{
+		Emitter:    NewEmitter(log),
+		client:     client,
+		channel:    channel,
+		instanceID: uuid.New().String(),
+		log:        log,
+	}
+
+	source.ListenEvents(f.publish, names...)
+
+	return f
+}
+
+// publish delivers the local event to the fanout listeners and publishes it to other instances.
+func (f *RedisFanout) publish(name EventName, payload interface{}) error {
+	f.Emitter.Emit(name, payload)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("events fanout: marshal payload: %w", err)
+	}
+
+	msg, err := json.Marshal(fanoutMessage{
+		Instance: f.instanceID,
+		Name:     name,
+		Payload:  data,
+	})
+	if err != nil {
+		return fmt.Errorf("events fanout: marshal message: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fanoutPublishTimeout)
+	defer cancel()
+
+	if err := f.client.Publish(ctx, f.channel, msg).Err(); err != nil {
+		return fmt.Errorf("events fanout: publish %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Run subscribes to the Redis channel and delivers the events emitted on other instances
+// to the fanout listeners until the context is cancelled.
+func (f *RedisFanout) Run(ctx context.Context) error {
+	pubsub := f.client.Subscribe(ctx, f.channel)
+	defer pubsub.Close()
+
+	// wait for the subscription confirmation, so the connection errors are not silently ignored
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return fmt.Errorf("events fanout: subscribe to %s: %w", f.channel, err)
+	}
+
+	f.log.Infof("events fanout: subscribed to %s", f.channel)
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			f.log.Infof("events fanout: stopped")
+			return nil
+		case m, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := f.receive(m.Payload); err != nil {
+				f.log.Errorf("events fanout: %s", err.Error())
+			}
+		}
+	}
+}
+
+// receive delivers the event published by another instance to the fanout listeners.
+func (f *RedisFanout) receive(raw string) error {
+	var msg fanoutMessage
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		return fmt.Errorf("unmarshal message: %w", err)
+	}
+
+	// the local events are already delivered by publish
+	if msg.Instance == f.instanceID {
+		return nil
+	}
+
+	payload, err := DecodePayload(msg.Name, msg.Payload)
+	if err != nil {
+		return fmt.Errorf("decode %s payload: %w", msg.Name, err)
+	}
+
+	f.Emitter.Emit(msg.Name, payload)
+
+	return nil
+}
+
+// DecodePayload decodes the JSON-encoded event payload into its registered type, e.g. to emit the events
+// received from other instances or stored in the outbox with the same payload types as the local ones.
+// Unknown events are decoded into a generic map.
+func DecodePayload(name EventName, data json.RawMessage) (interface{}, error) {
+	typ, ok := payloadTypes[name]
+	if !ok {
+		var payload map[string]interface{}
+		if err := json.Unmarshal(data, &payload); err != nil {
+			return nil, err
+		}
+		return payload, nil
+	}
+
+	ptr := reflect.New(reflect.TypeOf(typ))
+	if err := json.Unmarshal(data, ptr.Interface()); err != nil {
+		return nil, err
+	}
+
+	return ptr.Elem().Interface(), nil
+}
@@ -3,7 +3,6 @@ package events
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"sync"
 
@@ -105,7 +104,7 @@ func (b *EventBroadcaster) handleWebSocket(w http.ResponseWriter, r *http.Reques
 
 	conn, err := b.upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("error upgrading connection to websocket: %v", err)
+		b.log.Errorf("event Broadcaster: error upgrading connection to websocket: %v", err)
 		return
 	}
 
@@ -125,7 +124,7 @@ func (b *EventBroadcaster) handleWebSocket(w http.ResponseWriter, r *http.Reques
 	// 		return
 	// 	default:
 	// 		if _, _, err := conn.ReadMessage(); err != nil {
-	// 			log.Printf("error reading message from websocket: %v", err)
+	// 			b.log.Errorf("event Broadcaster: error reading message from websocket: %v", err)
 	// 			break
 	// 		}
 	// 	}
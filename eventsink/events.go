@@ -0,0 +1,64 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/google/uuid"
+)
+
+// Events are all the events emitted by the API, the ones forwarded to the sink are selected by Topics.
+var Events = append(append([]events.EventName{}, events.AllEvents...),
+	events.TransactionReferenceNotification,
+	events.WebhookDelivered,
+	events.WebhookDeadLettered,
+	events.ReconciliationDiscrepancy,
+	events.WalletAccountNotification,
+	events.DepositReceived,
+	events.ReportCompleted,
+	events.ReportFailed,
+	events.SubscriptionInstallmentDue,
+	events.SubscriptionInstallmentPaid,
+	events.SubscriptionInstallmentMissed,
+)
+
+type sinkEnqueuer interface {
+	PublishEvent(ctx context.Context, topic string, msg Message) error
+}
+
+// Listener enqueues publishing of the events mapped to a topic.
+// The message ID is assigned here, so the retries of the task publish the same message.
+func Listener(enq sinkEnqueuer, topics Topics) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		topic := topics.Topic(string(event))
+		if topic == "" || payload == nil {
+			return nil
+		}
+
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s payload: %w", event, err)
+		}
+
+		msg := Message{
+			ID:        uuid.New().String(),
+			Event:     string(event),
+			CreatedAt: time.Now().UTC(),
+			Data:      data,
+		}
+		if p, ok := payload.(events.PaymentIDGetter); ok {
+			msg.PaymentID = p.GetPaymentID()
+		} else {
+			var p struct {
+				PaymentID string `json:"payment_id"`
+			}
+			_ = json.Unmarshal(data, &p)
+			msg.PaymentID = p.PaymentID
+		}
+
+		return enq.PublishEvent(context.Background(), topic, msg)
+	}
+}
@@ -0,0 +1,115 @@
+package eventsink
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpclient"
+)
+
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+type (
+	// KafkaPublisher publishes the records via the Kafka REST Proxy (API v2),
+	// so the API doesn't depend on the Kafka client and its native libraries.
+	// The user info of the URL is sent as the basic auth credentials.
+	KafkaPublisher struct {
+		client   *http.Client
+		baseURL  string
+		username string
+		password string
+	}
+
+	kafkaRecord struct {
+		Key   *string         `json:"key"`
+		Value json.RawMessage `json:"value"`
+	}
+
+	kafkaProduceRequest struct {
+		Records []kafkaRecord `json:"records"`
+	}
+
+	kafkaProduceResponse struct {
+		Offsets []struct {
+			Partition int    `json:"partition"`
+			Offset    int64  `json:"offset"`
+			ErrorCode *int   `json:"error_code"`
+			Error     string `json:"error"`
+		} `json:"offsets"`
+	}
+)
+
+// NewKafkaPublisher creates the publisher for the Kafka REST Proxy at the given URL.
+func NewKafkaPublisher(rawURL string) (*KafkaPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kafka rest proxy url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid kafka rest proxy url: unsupported scheme %q", u.Scheme)
+	}
+
+	p := &KafkaPublisher{
+		client: httpclient.New("kafka", httpclient.WithTimeout(10*time.Second)),
+	}
+	if u.User != nil {
+		p.username = u.User.Username()
+		p.password, _ = u.User.Password()
+		u.User = nil
+	}
+	p.baseURL = strings.TrimSuffix(u.String(), "/")
+
+	return p, nil
+}
+
+// Publish produces the record to the topic and waits for its offset.
+func (p *KafkaPublisher) Publish(ctx context.Context, r Record) error {
+	rec := kafkaRecord{Value: r.Value}
+	if r.Key != "" {
+		rec.Key = &r.Key
+	}
+	body, err := json.Marshal(kafkaProduceRequest{Records: []kafkaRecord{rec}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal kafka record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/topics/"+url.PathEscape(r.Topic), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create kafka request: %w", err)
+	}
+	req.Header.Set("Content-Type", kafkaContentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish to kafka topic %s: %w", r.Topic, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to publish to kafka topic %s: status %d: %s", r.Topic, resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var result kafkaProduceResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("failed to decode kafka response: %w", err)
+	}
+	for _, o := range result.Offsets {
+		if o.ErrorCode != nil || o.Error != "" {
+			return fmt.Errorf("failed to publish to kafka topic %s: %s", r.Topic, o.Error)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package eventsink_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/eventsink"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaPublisher(t *testing.T) {
+	var got struct {
+		Records []struct {
+			Key   string          `json:"key"`
+			Value json.RawMessage `json:"value"`
+		} `json:"records"`
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		require.Equal(t, "user", user)
+		require.Equal(t, "secret", pass)
+		require.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+
+		if r.URL.Path != "/topics/checkout.events" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401,"message":"Topic not found."}`))
+			return
+		}
+
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		_, _ = w.Write([]byte(`{"offsets":[{"partition":0,"offset":42,"error_code":null,"error":null}]}`))
+	}))
+	defer srv.Close()
+
+	u := "http://user:secret@" + srv.Listener.Addr().String()
+	p, err := eventsink.NewKafkaPublisher(u)
+	require.NoError(t, err)
+
+	err = p.Publish(context.Background(), eventsink.Record{
+		Topic: "checkout.events",
+		Key:   "pid",
+		Value: []byte(`{"event":"payment.succeeded"}`),
+	})
+	require.NoError(t, err)
+	require.Len(t, got.Records, 1)
+	require.Equal(t, "pid", got.Records[0].Key)
+	require.JSONEq(t, `{"event":"payment.succeeded"}`, string(got.Records[0].Value))
+
+	err = p.Publish(context.Background(), eventsink.Record{Topic: "unknown", Value: []byte(`{}`)})
+	require.ErrorContains(t, err, "status 404")
+}
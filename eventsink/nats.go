@@ -0,0 +1,323 @@
+package eventsink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	natsTimeout     = 10 * time.Second
+	natsMaxLineSize = 64 << 10
+)
+
+type (
+	// NATSPublisher publishes the records to the NATS JetStream over the NATS client protocol
+	// and waits for the stream acknowledgement, so the subjects must be bound to a stream.
+	// The record ID is sent as the Nats-Msg-Id header, so the stream drops the redelivered records
+	// within its duplicate window. The records are published one by one over a single connection,
+	// which is re-established after any error.
+	NATSPublisher struct {
+		addr    string
+		tls     bool
+		user    string
+		pass    string
+		token   string
+		timeout time.Duration
+
+		mu      sync.Mutex
+		conn    net.Conn
+		r       *bufio.Reader
+		headers bool   // the server supports the message headers
+		inbox   string // the prefix of the reply subjects of the connection
+		seq     int
+	}
+
+	natsInfo struct {
+		Headers     bool `json:"headers"`
+		TLSRequired bool `json:"tls_required"`
+		MaxPayload  int  `json:"max_payload"`
+	}
+
+	natsConnect struct {
+		Verbose      bool   `json:"verbose"`
+		Pedantic     bool   `json:"pedantic"`
+		Headers      bool   `json:"headers"`
+		NoResponders bool   `json:"no_responders"`
+		User         string `json:"user,omitempty"`
+		Pass         string `json:"pass,omitempty"`
+		AuthToken    string `json:"auth_token,omitempty"`
+		Name         string `json:"name"`
+		Lang         string `json:"lang"`
+		Version      string `json:"version"`
+		Protocol     int    `json:"protocol"`
+	}
+
+	natsPubAck struct {
+		Stream    string `json:"stream"`
+		Seq       uint64 `json:"seq"`
+		Duplicate bool   `json:"duplicate"`
+		Error     *struct {
+			Code        int    `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	}
+)
+
+// NewNATSPublisher creates the publisher for the NATS server at the given URL:
+// nats://host:port, or tls://host:port for the TLS connection. The credentials are
+// taken from the user info of the URL: user:pass, or the token as the user name.
+func NewNATSPublisher(rawURL string) (*NATSPublisher, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nats url: %w", err)
+	}
+	if u.Scheme != "nats" && u.Scheme != "tls" {
+		return nil, fmt.Errorf("invalid nats url: unsupported scheme %q", u.Scheme)
+	}
+
+	p := &NATSPublisher{
+		addr:    u.Host,
+		tls:     u.Scheme == "tls",
+		timeout: natsTimeout,
+	}
+	if u.Port() == "" {
+		p.addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			p.user, p.pass = u.User.Username(), pass
+		} else {
+			p.token = u.User.Username()
+		}
+	}
+
+	return p, nil
+}
+
+// Publish publishes the record to the subject and waits for the JetStream acknowledgement.
+// The record key is not used: the stream keeps the records in the order they're published.
+func (p *NATSPublisher) Publish(ctx context.Context, r Record) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.publish(ctx, r); err != nil {
+		p.close()
+		return fmt.Errorf("failed to publish to nats subject %s: %w", r.Topic, err)
+	}
+	return nil
+}
+
+// Close closes the connection to the server.
+func (p *NATSPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.close()
+	return nil
+}
+
+func (p *NATSPublisher) publish(ctx context.Context, r Record) error {
+	if p.conn == nil {
+		if err := p.connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(p.timeout)
+	}
+	if err := p.conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	p.seq++
+	reply := p.inbox + strconv.Itoa(p.seq)
+
+	var buf bytes.Buffer
+	if r.ID != "" && p.headers {
+		hdr := "NATS/1.0\r\nNats-Msg-Id: " + r.ID + "\r\n\r\n"
+		fmt.Fprintf(&buf, "HPUB %s %s %d %d\r\n%s", r.Topic, reply, len(hdr), len(hdr)+len(r.Value), hdr)
+	} else {
+		fmt.Fprintf(&buf, "PUB %s %s %d\r\n", r.Topic, reply, len(r.Value))
+	}
+	buf.Write(r.Value)
+	buf.WriteString("\r\n")
+	if _, err := p.conn.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	for {
+		subject, hdr, payload, err := p.readMsg()
+		if err != nil {
+			return err
+		}
+		if subject != reply {
+			continue // the late acknowledgement of the timed out publish
+		}
+		// the status header without the payload, e.g. "NATS/1.0 503" if no stream is bound to the subject
+		if len(payload) == 0 && bytes.HasPrefix(hdr, []byte("NATS/1.0 ")) {
+			status, _, _ := strings.Cut(strings.TrimPrefix(string(hdr), "NATS/1.0 "), "\r\n")
+			if strings.HasPrefix(status, "503") {
+				return errors.New("no stream is bound to the subject")
+			}
+			return fmt.Errorf("unexpected status: %s", status)
+		}
+
+		var ack natsPubAck
+		if err := json.Unmarshal(payload, &ack); err != nil {
+			return fmt.Errorf("failed to decode acknowledgement: %w", err)
+		}
+		if ack.Error != nil {
+			return fmt.Errorf("jetstream error %d: %s", ack.Error.Code, ack.Error.Description)
+		}
+		return nil
+	}
+}
+
+// connect dials the server, authenticates and subscribes to the replies of the connection.
+func (p *NATSPublisher) connect(ctx context.Context) error {
+	conn, err := (&net.Dialer{Timeout: p.timeout}).DialContext(ctx, "tcp", p.addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(time.Now().Add(p.timeout)); err != nil {
+		conn.Close()
+		return err
+	}
+	p.conn, p.r = conn, bufio.NewReaderSize(conn, natsMaxLineSize)
+
+	line, err := p.readLine()
+	if err != nil {
+		return fmt.Errorf("failed to read server info: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		return fmt.Errorf("unexpected server greeting: %s", line)
+	}
+	var info natsInfo
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		return fmt.Errorf("failed to decode server info: %w", err)
+	}
+	p.headers = info.Headers
+
+	if p.tls || info.TLSRequired {
+		host, _, _ := net.SplitHostPort(p.addr)
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			return fmt.Errorf("tls handshake: %w", err)
+		}
+		p.conn, p.r = tlsConn, bufio.NewReaderSize(tlsConn, natsMaxLineSize)
+	}
+
+	connect, err := json.Marshal(natsConnect{
+		Headers:      info.Headers,
+		NoResponders: info.Headers,
+		User:         p.user,
+		Pass:         p.pass,
+		AuthToken:    p.token,
+		Name:         "checkout-api",
+		Lang:         "go",
+		Version:      "1.0.0",
+		Protocol:     1,
+	})
+	if err != nil {
+		return err
+	}
+
+	p.inbox = "_INBOX." + strings.ReplaceAll(uuid.New().String(), "-", "") + "."
+	p.seq = 0
+	if _, err := fmt.Fprintf(p.conn, "CONNECT %s\r\nSUB %s* 1\r\nPING\r\n", connect, p.inbox); err != nil {
+		return err
+	}
+
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("connect: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// readMsg reads the protocol messages until the next MSG or HMSG one and returns its subject, headers and payload.
+func (p *NATSPublisher) readMsg() (subject string, hdr, payload []byte, err error) {
+	for {
+		line, err := p.readLine()
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		op, args, _ := strings.Cut(line, " ")
+		switch op {
+		case "PING":
+			if _, err := io.WriteString(p.conn, "PONG\r\n"); err != nil {
+				return "", nil, nil, err
+			}
+		case "-ERR":
+			return "", nil, nil, fmt.Errorf("server error: %s", args)
+		case "MSG", "HMSG":
+			// MSG <subject> <sid> [reply] <size>, HMSG <subject> <sid> [reply] <header size> <total size>
+			fields := strings.Fields(args)
+			n := 3
+			if op == "HMSG" {
+				n = 4
+			}
+			if len(fields) < n {
+				return "", nil, nil, fmt.Errorf("malformed %s: %s", op, line)
+			}
+			total, err := strconv.Atoi(fields[len(fields)-1])
+			if err != nil {
+				return "", nil, nil, fmt.Errorf("malformed %s: %s", op, line)
+			}
+			hdrSize := 0
+			if op == "HMSG" {
+				if hdrSize, err = strconv.Atoi(fields[len(fields)-2]); err != nil || hdrSize > total {
+					return "", nil, nil, fmt.Errorf("malformed %s: %s", op, line)
+				}
+			}
+
+			data := make([]byte, total+2)
+			if _, err := io.ReadFull(p.r, data); err != nil {
+				return "", nil, nil, err
+			}
+			return fields[0], data[:hdrSize], data[hdrSize:total], nil
+		}
+	}
+}
+
+func (p *NATSPublisher) readLine() (string, error) {
+	line, err := p.r.ReadSlice('\n')
+	if err != nil {
+		if errors.Is(err, bufio.ErrBufferFull) {
+			return "", fmt.Errorf("protocol line exceeds %d bytes", natsMaxLineSize)
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+func (p *NATSPublisher) close() {
+	if p.conn != nil {
+		p.conn.Close()
+		p.conn, p.r = nil, nil
+	}
+}
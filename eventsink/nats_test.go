@@ -0,0 +1,102 @@
+package eventsink_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/eventsink"
+	"github.com/stretchr/testify/require"
+)
+
+// natsServer is the minimal JetStream server: it acknowledges the messages published
+// to the bound subject and drops the ones with the known Nats-Msg-Id.
+func natsServer(t *testing.T, subject string) (addr string, published chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	published = make(chan string, 10)
+	go func() {
+		seen := map[string]bool{}
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				fmt.Fprint(conn, "INFO {\"headers\":true,\"max_payload\":1048576}\r\n")
+
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					fields := strings.Fields(line)
+					switch fields[0] {
+					case "PING":
+						fmt.Fprint(conn, "PONG\r\n")
+					case "HPUB":
+						hdrSize, _ := strconv.Atoi(fields[3])
+						total, _ := strconv.Atoi(fields[4])
+						data := make([]byte, total+2)
+						if _, err := io.ReadFull(r, data); err != nil {
+							return
+						}
+						id := strings.TrimSpace(strings.SplitN(string(data[:hdrSize]), "Nats-Msg-Id:", 2)[1])
+
+						var ack string
+						switch {
+						case fields[1] != subject:
+							fmt.Fprintf(conn, "HMSG %s 1 %d %d\r\nNATS/1.0 503\r\n\r\n\r\n", fields[2], 16, 16)
+							continue
+						case seen[id]:
+							ack = `{"stream":"EVENTS","seq":1,"duplicate":true}`
+						default:
+							seen[id] = true
+							published <- string(data[hdrSize:total])
+							ack = `{"stream":"EVENTS","seq":1}`
+						}
+						fmt.Fprintf(conn, "PING\r\nMSG %s 1 %d\r\n%s\r\n", fields[2], len(ack), ack)
+					}
+				}
+			}()
+		}
+	}()
+
+	return l.Addr().String(), published
+}
+
+func TestNATSPublisher(t *testing.T) {
+	addr, published := natsServer(t, "checkout.events")
+
+	p, err := eventsink.NewNATSPublisher("nats://" + addr)
+	require.NoError(t, err)
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rec := eventsink.Record{Topic: "checkout.events", ID: "evt-1", Value: []byte(`{"event":"payment.succeeded"}`)}
+	require.NoError(t, p.Publish(ctx, rec))
+	require.Equal(t, `{"event":"payment.succeeded"}`, <-published)
+
+	// the redelivered message is acknowledged, but not stored again
+	require.NoError(t, p.Publish(ctx, rec))
+	require.Empty(t, published)
+
+	// no stream is bound to the subject; the publisher reconnects after the error
+	err = p.Publish(ctx, eventsink.Record{Topic: "unknown", ID: "evt-2", Value: []byte(`{}`)})
+	require.ErrorContains(t, err, "no stream")
+
+	require.NoError(t, p.Publish(ctx, eventsink.Record{Topic: "checkout.events", ID: "evt-3", Value: []byte(`{}`)}))
+	require.Equal(t, `{}`, <-published)
+}
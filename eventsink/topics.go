@@ -0,0 +1,52 @@
+package eventsink
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/webhook"
+)
+
+type (
+	// Topics maps the events to the topics. The first rule matching the event wins,
+	// the events matching no rule are not forwarded.
+	Topics []TopicRule
+
+	// TopicRule forwards the events matching the pattern to the topic.
+	// The pattern is the event name, the prefix followed by the wildcard, e.g. "payment.*", or "*".
+	TopicRule struct {
+		Pattern string
+		Topic   string
+	}
+)
+
+// ParseTopics parses the comma-separated "pattern=topic" rules,
+// e.g. "payment.*=checkout.payments,*=checkout.events".
+func ParseTopics(s string) (Topics, error) {
+	var topics Topics
+	for _, rule := range strings.Split(s, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+
+		pattern, topic, ok := strings.Cut(rule, "=")
+		pattern, topic = strings.TrimSpace(pattern), strings.TrimSpace(topic)
+		if !ok || pattern == "" || topic == "" {
+			return nil, fmt.Errorf("invalid topic rule %q, expected pattern=topic", rule)
+		}
+
+		topics = append(topics, TopicRule{Pattern: pattern, Topic: topic})
+	}
+	return topics, nil
+}
+
+// Topic returns the topic of the event, or an empty string if the event is not forwarded.
+func (t Topics) Topic(event string) string {
+	for _, r := range t {
+		if webhook.MatchEvent([]string{r.Pattern}, event) {
+			return r.Topic
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package eventsink_test
+
+import (
+	"testing"
+
+	"github.com/easypmnt/checkout-api/eventsink"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTopics(t *testing.T) {
+	topics, err := eventsink.ParseTopics(" payment.*=checkout.payments, payment.succeeded = ignored ,*=checkout.events")
+	require.NoError(t, err)
+	require.Equal(t, eventsink.Topics{
+		{Pattern: "payment.*", Topic: "checkout.payments"},
+		{Pattern: "payment.succeeded", Topic: "ignored"},
+		{Pattern: "*", Topic: "checkout.events"},
+	}, topics)
+
+	// the first matching rule wins
+	require.Equal(t, "checkout.payments", topics.Topic("payment.succeeded"))
+	require.Equal(t, "checkout.events", topics.Topic("transaction.created"))
+
+	topics, err = eventsink.ParseTopics("payment.*=checkout.payments")
+	require.NoError(t, err)
+	require.Empty(t, topics.Topic("transaction.created"))
+
+	for _, s := range []string{"payment.*", "=topic", "payment.*="} {
+		_, err := eventsink.ParseTopics(s)
+		require.Error(t, err, s)
+	}
+}
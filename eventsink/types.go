@@ -0,0 +1,29 @@
+package eventsink
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Worker task types
+const (
+	TaskPublishEvent = "eventsink:publish_event"
+)
+
+type (
+	// PublishEventPayload is the payload for the eventsink:publish_event task.
+	PublishEventPayload struct {
+		Topic   string  `json:"topic"`
+		Message Message `json:"message"`
+	}
+
+	// Message is the event forwarded to the sink.
+	// It's delivered at least once, so the consumers should drop the duplicates by ID.
+	Message struct {
+		ID        string          `json:"id"`
+		Event     string          `json:"event"`
+		PaymentID string          `json:"payment_id,omitempty"` // the message key, so the events of a payment keep their order
+		CreatedAt time.Time       `json:"created_at"`
+		Data      json.RawMessage `json:"data"`
+	}
+)
@@ -0,0 +1,49 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+// Worker is a task handler for the event publishing.
+type Worker struct {
+	pub Publisher
+}
+
+// NewWorker creates a new event sink task handler.
+func NewWorker(pub Publisher) *Worker {
+	return &Worker{pub: pub}
+}
+
+// Register registers task handlers for the event publishing.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskPublishEvent, w.PublishEvent)
+}
+
+// PublishEvent publishes the event message to the topic.
+// The task is retried until the broker acknowledges the message, so it's delivered at least once.
+func (w *Worker) PublishEvent(ctx context.Context, t *asynq.Task) error {
+	var p PublishEventPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	value, err := json.Marshal(p.Message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	if err := w.pub.Publish(ctx, Record{
+		Topic: p.Topic,
+		Key:   p.Message.PaymentID,
+		ID:    p.Message.ID,
+		Value: value,
+	}); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", p.Message.Event, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package funnel
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+// Default report period, if it is not set in the request.
+const defaultReportPeriod = 30 * 24 * time.Hour
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		GetReport endpoint.Endpoint
+	}
+
+	// GetReportRequest is the request type for the GetReport method.
+	GetReportRequest struct {
+		From time.Time
+		To   time.Time
+	}
+
+	// GetReportResponse is the response type for the GetReport method.
+	GetReportResponse struct {
+		From   time.Time   `json:"from"`
+		To     time.Time   `json:"to"`
+		Stages []Stage     `json:"stages"`
+		Report []ReportRow `json:"report"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		GetReport: makeGetReportEndpoint(s),
+	}
+}
+
+// makeGetReportEndpoint returns an endpoint function for the GetReport method.
+func makeGetReportEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetReportRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		if req.To.IsZero() {
+			req.To = time.Now()
+		}
+		if req.From.IsZero() {
+			req.From = req.To.Add(-defaultReportPeriod)
+		}
+		if !req.From.Before(req.To) {
+			return nil, ErrInvalidPeriod
+		}
+
+		report, err := s.Report(ctx, req.From, req.To)
+		if err != nil {
+			return nil, err
+		}
+
+		return GetReportResponse{
+			From:   req.From,
+			To:     req.To,
+			Stages: Stages,
+			Report: report,
+		}, nil
+	}
+}
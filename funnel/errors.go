@@ -0,0 +1,9 @@
+package funnel
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrInvalidPeriod  = errors.New("invalid report period")
+)
@@ -0,0 +1,54 @@
+package funnel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/google/uuid"
+)
+
+// eventStages maps the payment events to the funnel stages.
+var eventStages = map[events.EventName]Stage{
+	events.PaymentCreated:       StagePaymentCreated,
+	events.PaymentLinkGenerated: StageLinkGenerated,
+	events.TransactionCreated:   StageTransactionGenerated,
+	events.PaymentProcessing:    StageSubmitted,
+	events.PaymentSucceeded:     StageConfirmed,
+	events.PaymentHeld:          StageConfirmed,
+}
+
+// Events returns the list of events the funnel listener is subscribed to.
+func Events() []events.EventName {
+	result := make([]events.EventName, 0, len(eventStages))
+	for name := range eventStages {
+		result = append(result, name)
+	}
+	return result
+}
+
+// Listener tracks the funnel stage of the payment from the payment events.
+func Listener(s *Service) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		stage, ok := eventStages[event]
+		if !ok || payload == nil {
+			return nil
+		}
+
+		p, ok := payload.(events.PaymentIDGetter)
+		if !ok {
+			return nil
+		}
+
+		pid, err := uuid.Parse(p.GetPaymentID())
+		if err != nil {
+			return fmt.Errorf("failed to parse payment id: %s", err.Error())
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return s.Track(ctx, pid, stage)
+	}
+}
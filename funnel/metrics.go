@@ -0,0 +1,12 @@
+package funnel
+
+import "github.com/easypmnt/checkout-api/internal/metrics"
+
+// Funnel metrics.
+// The drop-off between the stages is the ratio of their counters, e.g.:
+// sum(rate(checkout_funnel_total{stage="confirmed"}[1h])) / sum(rate(checkout_funnel_total{stage="payment_created"}[1h]))
+var stagesTotal = metrics.NewCounterVec(
+	"checkout_funnel_total",
+	"Total number of payments which reached the checkout funnel stage.",
+	"stage", "merchant", "currency",
+)
@@ -0,0 +1,69 @@
+package funnel
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// Service tracks the checkout conversion funnel.
+type Service struct {
+	repo funnelRepository
+}
+
+// NewService creates a new funnel service.
+func NewService(repo funnelRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Track records that the payment reached the funnel stage.
+// Each stage is counted once per payment, so repeated events don't inflate the funnel.
+func (s *Service) Track(ctx context.Context, paymentID uuid.UUID, stage Stage) error {
+	payment, err := s.repo.GetPayment(ctx, paymentID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	tracked, err := s.repo.TrackFunnelStage(ctx, repository.TrackFunnelStageParams{
+		PaymentID: paymentID,
+		Stage:     string(stage),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to track funnel stage %s: %w", stage, err)
+	}
+	// the test-mode payments are excluded from the reports
+	if tracked > 0 && payment.Livemode {
+		stagesTotal.WithLabelValues(string(stage), payment.DestinationWallet, payment.DestinationMint).Inc()
+	}
+
+	return nil
+}
+
+// Report returns the number of payments created within the given period
+// that reached each funnel stage, grouped by the merchant wallet and currency.
+func (s *Service) Report(ctx context.Context, from, to time.Time) ([]ReportRow, error) {
+	rows, err := s.repo.GetFunnelReport(ctx, repository.GetFunnelReportParams{
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get funnel report: %w", err)
+	}
+
+	result := make([]ReportRow, 0, len(rows))
+	for _, row := range rows {
+		if n := len(result); n == 0 || result[n-1].Merchant != row.DestinationWallet || result[n-1].Currency != row.DestinationMint {
+			result = append(result, ReportRow{
+				Merchant: row.DestinationWallet,
+				Currency: row.DestinationMint,
+				Stages:   make(map[Stage]uint64, len(Stages)),
+			})
+		}
+		result[len(result)-1].Stages[Stage(row.Stage)] = uint64(row.Total)
+	}
+
+	return result, nil
+}
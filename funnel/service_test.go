@@ -0,0 +1,78 @@
+package funnel_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/funnel"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	tracked map[string]bool
+	rows    []repository.GetFunnelReportRow
+}
+
+func (r *repoMock) GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	return repository.Payment{ID: id, DestinationWallet: "wallet", DestinationMint: "mint", Livemode: true}, nil
+}
+
+func (r *repoMock) TrackFunnelStage(ctx context.Context, arg repository.TrackFunnelStageParams) (int64, error) {
+	key := arg.PaymentID.String() + arg.Stage
+	if r.tracked[key] {
+		return 0, nil
+	}
+	r.tracked[key] = true
+	return 1, nil
+}
+
+func (r *repoMock) GetFunnelReport(ctx context.Context, arg repository.GetFunnelReportParams) ([]repository.GetFunnelReportRow, error) {
+	return r.rows, nil
+}
+
+func TestListener(t *testing.T) {
+	repo := &repoMock{tracked: make(map[string]bool)}
+	listener := funnel.Listener(funnel.NewService(repo))
+	pid := uuid.New()
+
+	err := listener(events.PaymentLinkGenerated, events.PaymentLinkGeneratedPayload{
+		PaymentID: events.PaymentID{PaymentID: pid.String()},
+	})
+	require.NoError(t, err)
+	require.True(t, repo.tracked[pid.String()+string(funnel.StageLinkGenerated)])
+
+	// not a funnel event
+	err = listener(events.PaymentCancelled, events.PaymentStatusUpdatedPayload{
+		PaymentID: events.PaymentID{PaymentID: pid.String()},
+	})
+	require.NoError(t, err)
+	require.Len(t, repo.tracked, 1)
+
+	err = listener(events.PaymentSucceeded, events.PaymentStatusUpdatedPayload{
+		PaymentID: events.PaymentID{PaymentID: "invalid"},
+	})
+	require.Error(t, err)
+}
+
+func TestServiceReport(t *testing.T) {
+	repo := &repoMock{rows: []repository.GetFunnelReportRow{
+		{DestinationWallet: "wallet1", DestinationMint: "SOL", Stage: "payment_created", Total: 10},
+		{DestinationWallet: "wallet1", DestinationMint: "SOL", Stage: "confirmed", Total: 4},
+		{DestinationWallet: "wallet1", DestinationMint: "USDC", Stage: "payment_created", Total: 3},
+		{DestinationWallet: "wallet2", DestinationMint: "USDC", Stage: "payment_created", Total: 1},
+	}}
+
+	report, err := funnel.NewService(repo).Report(context.Background(), time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	require.Len(t, report, 3)
+	require.Equal(t, "wallet1", report[0].Merchant)
+	require.Equal(t, "SOL", report[0].Currency)
+	require.EqualValues(t, 10, report[0].Stages[funnel.StagePaymentCreated])
+	require.EqualValues(t, 4, report[0].Stages[funnel.StageConfirmed])
+	require.EqualValues(t, 3, report[1].Stages[funnel.StagePaymentCreated])
+	require.Equal(t, "wallet2", report[2].Merchant)
+}
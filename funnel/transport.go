@@ -0,0 +1,72 @@
+package funnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the funnel reporting API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/report", httptransport.NewServer(
+		e.GetReport,
+		decodeGetReportRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	if errors.Is(err, ErrInvalidRequest) || errors.Is(err, ErrInvalidPeriod) {
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeGetReportRequest is a transport/http.DecodeRequestFunc that decodes
+// the report period from the query string: ?from=2023-03-01&to=2023-04-01.
+// Both dates are optional and accept either YYYY-MM-DD or RFC3339 format.
+func decodeGetReportRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var (
+		req GetReportRequest
+		err error
+	)
+
+	if req.From, err = utils.ParseDate(r.URL.Query().Get("from")); err != nil {
+		return nil, fmt.Errorf("%w: from: %s", ErrInvalidPeriod, err.Error())
+	}
+	if req.To, err = utils.ParseDate(r.URL.Query().Get("to")); err != nil {
+		return nil, fmt.Errorf("%w: to: %s", ErrInvalidPeriod, err.Error())
+	}
+
+	return req, nil
+}
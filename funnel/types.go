@@ -0,0 +1,45 @@
+package funnel
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// Stage is a step of the checkout conversion funnel.
+type Stage string
+
+// Funnel stages, in the checkout order.
+const (
+	StagePaymentCreated       Stage = "payment_created"
+	StageLinkGenerated        Stage = "link_generated"
+	StageTransactionGenerated Stage = "transaction_generated"
+	StageSubmitted            Stage = "submitted"
+	StageConfirmed            Stage = "confirmed"
+)
+
+// Stages is the list of the funnel stages, in the checkout order.
+var Stages = []Stage{
+	StagePaymentCreated,
+	StageLinkGenerated,
+	StageTransactionGenerated,
+	StageSubmitted,
+	StageConfirmed,
+}
+
+type (
+	// ReportRow is the number of payments that reached each funnel stage
+	// for the merchant wallet and currency.
+	ReportRow struct {
+		Merchant string           `json:"merchant"`
+		Currency string           `json:"currency"`
+		Stages   map[Stage]uint64 `json:"stages"`
+	}
+
+	funnelRepository interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error)
+		TrackFunnelStage(ctx context.Context, arg repository.TrackFunnelStageParams) (int64, error)
+		GetFunnelReport(ctx context.Context, arg repository.GetFunnelReportParams) ([]repository.GetFunnelReportRow, error)
+	}
+)
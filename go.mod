@@ -14,6 +14,8 @@ require (
 	github.com/go-chi/cors v1.2.1
 	github.com/go-chi/oauth v0.0.0-20210913085627-d937e221b3ef
 	github.com/go-kit/kit v0.10.0
+	github.com/go-redis/redis/v8 v8.11.5
+	github.com/go-sql-driver/mysql v1.6.0
 	github.com/google/go-querystring v1.1.0
 	github.com/google/uuid v1.3.0
 	github.com/gookit/validate v1.4.6
@@ -52,7 +54,6 @@ require (
 	github.com/everFinance/ttcrsa v1.1.3 // indirect
 	github.com/go-gorp/gorp/v3 v3.1.0 // indirect
 	github.com/go-logfmt/logfmt v0.5.1 // indirect
-	github.com/go-redis/redis/v8 v8.11.5 // indirect
 	github.com/go-stack/stack v1.8.1 // indirect
 	github.com/gofrs/uuid v4.0.0+incompatible // indirect
 	github.com/golang/protobuf v1.5.3 // indirect
@@ -0,0 +1,181 @@
+// Package health runs the dependency checks of the Kubernetes probes, see Checker.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Check statuses.
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// DefaultTimeout is the timeout of a single check.
+const DefaultTimeout = 3 * time.Second
+
+type (
+	// CheckFunc verifies the dependency, e.g. pings the database. It must return once the context is done.
+	CheckFunc func(ctx context.Context) error
+
+	// Checker runs the dependency checks concurrently, each one with its own timeout,
+	// so a hanging dependency doesn't hide the status of the others.
+	Checker struct {
+		checks  []check
+		timeout time.Duration
+	}
+
+	// Option is a function that configures the checker.
+	Option func(*Checker)
+
+	// Report is the result of all the checks, the response body of the probe.
+	Report struct {
+		Status string                 `json:"status"` // down if any of the checks is down
+		Checks map[string]CheckResult `json:"checks"`
+	}
+
+	// CheckResult is the result of a single check.
+	CheckResult struct {
+		Status    string  `json:"status"`
+		LatencyMs float64 `json:"latency_ms"`
+		Error     string  `json:"error,omitempty"`
+	}
+
+	check struct {
+		name string
+		fn   CheckFunc
+	}
+)
+
+// NewChecker creates a new checker of the dependencies.
+func NewChecker(opts ...Option) *Checker {
+	c := &Checker{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithCheck adds the check of the named dependency, e.g. "postgres".
+func WithCheck(name string, fn CheckFunc) Option {
+	return func(c *Checker) {
+		c.checks = append(c.checks, check{name: name, fn: fn})
+	}
+}
+
+// WithTimeout configures the timeout of a single check.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Checker) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// Run runs all the checks and returns their results.
+func (c *Checker) Run(ctx context.Context) Report {
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]CheckResult, len(c.checks)),
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+
+			result := c.run(ctx, chk.fn)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			report.Checks[chk.name] = result
+			if result.Status != StatusUp {
+				report.Status = StatusDown
+			}
+		}(chk)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run runs the check with the timeout. The check which doesn't return in time is reported as down,
+// while its goroutine is left to finish on its own.
+func (c *Checker) run(ctx context.Context, fn CheckFunc) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+
+	result := CheckResult{
+		Status:    StatusUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// Handler returns the probe handler: 200 status if all the checks are up, 503 otherwise,
+// with the Report in the response body.
+func (c *Checker) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context())
+
+		status := http.StatusOK
+		if report.Status != StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(report)
+	}
+}
+
+// Passive adapts the check which reports the result of the latest background check, e.g. solana.RPCHealth.Check.
+func Passive(fn func() error) CheckFunc {
+	return func(context.Context) error {
+		return fn()
+	}
+}
+
+// All returns the check which runs the checks one by one and fails with the first error.
+func All(checks ...CheckFunc) CheckFunc {
+	return func(ctx context.Context) error {
+		for _, fn := range checks {
+			if err := fn(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/health"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker(t *testing.T) {
+	c := health.NewChecker(
+		health.WithTimeout(50*time.Millisecond),
+		health.WithCheck("postgres", func(ctx context.Context) error { return nil }),
+		health.WithCheck("redis", health.Passive(func() error { return errors.New("connection refused") })),
+		health.WithCheck("solana_rpc", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		health.WithCheck("hanging", func(context.Context) error {
+			select {} // ignores the context
+		}),
+	)
+
+	report := c.Run(context.Background())
+	require.Equal(t, health.StatusDown, report.Status)
+	require.Len(t, report.Checks, 4)
+	require.Equal(t, health.StatusUp, report.Checks["postgres"].Status)
+	require.Empty(t, report.Checks["postgres"].Error)
+	require.Equal(t, health.StatusDown, report.Checks["redis"].Status)
+	require.Equal(t, "connection refused", report.Checks["redis"].Error)
+	require.Equal(t, health.StatusDown, report.Checks["solana_rpc"].Status)
+	require.Equal(t, "timed out after 50ms", report.Checks["hanging"].Error)
+}
+
+func TestHandler(t *testing.T) {
+	var failing bool
+	c := health.NewChecker(
+		health.WithCheck("postgres", func(ctx context.Context) error { return nil }),
+		health.WithCheck("redis", health.All(
+			func(ctx context.Context) error { return nil },
+			health.Passive(func() error {
+				if failing {
+					return errors.New("ping timeout")
+				}
+				return nil
+			}),
+		)),
+	)
+
+	rec := httptest.NewRecorder()
+	c.Handler()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	require.Equal(t, health.StatusUp, report.Status)
+	require.Equal(t, health.StatusUp, report.Checks["redis"].Status)
+
+	failing = true
+	rec = httptest.NewRecorder()
+	c.Handler()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	require.Equal(t, health.StatusDown, report.Status)
+	require.Equal(t, "ping timeout", report.Checks["redis"].Error)
+	require.Equal(t, health.StatusUp, report.Checks["postgres"].Status)
+}
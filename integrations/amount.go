@@ -0,0 +1,57 @@
+package integrations
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseDecimalAmount converts a decimal string, e.g. "10.50", to base units with the given decimals
+// without floating point rounding errors.
+func parseDecimalAmount(s string, decimals uint8) (uint64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	if intPart == "" {
+		intPart = "0"
+	}
+	if len(fracPart) > int(decimals) {
+		// platforms send at most 2-3 decimals, so the extra digits must be zeros
+		if strings.Trim(fracPart[decimals:], "0") != "" {
+			return 0, fmt.Errorf("%w: %q has too many decimals", ErrInvalidAmount, s)
+		}
+		fracPart = fracPart[:decimals]
+	}
+	fracPart += strings.Repeat("0", int(decimals)-len(fracPart))
+
+	i, err := strconv.ParseUint(intPart, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	var f uint64
+	if fracPart != "" {
+		if f, err = strconv.ParseUint(fracPart, 10, 64); err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+		}
+	}
+
+	mul := uint64(math.Pow10(int(decimals)))
+	if i > (math.MaxUint64-f)/mul {
+		return 0, fmt.Errorf("%w: %q is too large", ErrInvalidAmount, s)
+	}
+
+	return i*mul + f, nil
+}
+
+// formatDecimalAmount converts the amount in base units to a decimal string with the given decimals.
+func formatDecimalAmount(amount uint64, decimals uint8) string {
+	if decimals == 0 {
+		return strconv.FormatUint(amount, 10)
+	}
+	s := fmt.Sprintf("%0*d", int(decimals)+1, amount)
+	return s[:len(s)-int(decimals)] + "." + s[len(s)-int(decimals):]
+}
@@ -0,0 +1,44 @@
+package integrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDecimalAmount(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals uint8
+		want     uint64
+		wantErr  bool
+	}{
+		{in: "10.50", decimals: 6, want: 10500000},
+		{in: "10", decimals: 6, want: 10000000},
+		{in: ".5", decimals: 2, want: 50},
+		{in: "0.1", decimals: 9, want: 100000000},
+		{in: "1.500", decimals: 2, want: 150},
+		{in: "1.505", decimals: 2, wantErr: true},
+		{in: "-1", decimals: 6, wantErr: true},
+		{in: "abc", decimals: 6, wantErr: true},
+		{in: "", decimals: 6, wantErr: true},
+		{in: "18446744073709551615", decimals: 6, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseDecimalAmount(tt.in, tt.decimals)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidAmount)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatDecimalAmount(t *testing.T) {
+	require.Equal(t, "10.500000", formatDecimalAmount(10500000, 6))
+	require.Equal(t, "0.000001", formatDecimalAmount(1, 6))
+	require.Equal(t, "42", formatDecimalAmount(42, 0))
+}
@@ -0,0 +1,69 @@
+package integrations
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		ShopifyOrderCreated     endpoint.Endpoint
+		WooCommerceOrderCreated endpoint.Endpoint
+	}
+
+	// OrderCreatedResponse is the response type for the order created callbacks.
+	OrderCreatedResponse struct {
+		Payment *payments.Payment `json:"payment,omitempty"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		ShopifyOrderCreated:     makeShopifyOrderCreatedEndpoint(s),
+		WooCommerceOrderCreated: makeWooCommerceOrderCreatedEndpoint(s),
+	}
+}
+
+// makeShopifyOrderCreatedEndpoint returns an endpoint function for the Shopify orders/create webhook.
+func makeShopifyOrderCreatedEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ShopifyOrder)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		payment, err := s.CreateShopifyPayment(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return OrderCreatedResponse{Payment: payment}, nil
+	}
+}
+
+// makeWooCommerceOrderCreatedEndpoint returns an endpoint function for the WooCommerce order.created webhook.
+func makeWooCommerceOrderCreatedEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(WooCommerceOrder)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		// WooCommerce sends a ping without an order when the webhook is created.
+		if req.ID == 0 {
+			return OrderCreatedResponse{}, nil
+		}
+
+		payment, err := s.CreateWooCommercePayment(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		return OrderCreatedResponse{Payment: payment}, nil
+	}
+}
@@ -0,0 +1,12 @@
+package integrations
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest      = errors.New("invalid_request")
+	ErrInvalidSignature    = errors.New("invalid_signature")
+	ErrInvalidAmount       = errors.New("invalid_amount")
+	ErrUnsupportedCurrency = errors.New("unsupported_currency")
+	ErrNotConfigured       = errors.New("integration_not_configured")
+)
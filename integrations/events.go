@@ -0,0 +1,36 @@
+package integrations
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/events"
+)
+
+// OrderStatusEvents is the list of payment events which change the platform order status.
+var OrderStatusEvents = []events.EventName{
+	events.PaymentSucceeded,
+	events.PaymentHeld,
+	events.PaymentFailed,
+	events.PaymentCancelled,
+	events.PaymentExpired,
+}
+
+type orderStatusEnqueuer interface {
+	SyncOrderStatus(ctx context.Context, event, paymentID string) error
+}
+
+// SyncOrderStatusListener enqueues the platform order status sync on the payment status change.
+func SyncOrderStatusListener(enq orderStatusEnqueuer) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		if payload == nil {
+			return nil
+		}
+
+		p, ok := payload.(events.PaymentIDGetter)
+		if !ok {
+			return nil
+		}
+
+		return enq.SyncOrderStatus(context.Background(), string(event), p.GetPaymentID())
+	}
+}
@@ -0,0 +1,208 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/payments"
+)
+
+type (
+	// Service translates e-commerce platform orders into payments and back.
+	Service struct {
+		paymentSvc  paymentService
+		shopify     *ShopifyClient
+		woocommerce *WooCommerceClient
+		decimals    uint8
+
+		mu         sync.RWMutex // guards currencies, see SetCurrencies
+		currencies []string
+	}
+
+	// ServiceOption is a function that configures the integrations service.
+	ServiceOption func(*Service)
+
+	paymentService interface {
+		CreatePayment(ctx context.Context, payment *payments.Payment) (*payments.Payment, error)
+	}
+)
+
+// NewService creates a new integrations service.
+// Order totals are converted to the merchant destination mint base units,
+// so the store currency must match the destination mint, e.g. USD store with USDC destination mint.
+// Default values are:
+//   - decimals: 6 (USDC, USDT)
+func NewService(paymentSvc paymentService, opts ...ServiceOption) *Service {
+	s := &Service{
+		paymentSvc: paymentSvc,
+		decimals:   6,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithShopify enables the Shopify integration.
+func WithShopify(c *ShopifyClient) ServiceOption {
+	return func(s *Service) {
+		s.shopify = c
+	}
+}
+
+// WithWooCommerce enables the WooCommerce integration.
+func WithWooCommerce(c *WooCommerceClient) ServiceOption {
+	return func(s *Service) {
+		s.woocommerce = c
+	}
+}
+
+// WithDecimals sets the decimals of the destination mint to convert the order total.
+func WithDecimals(decimals uint8) ServiceOption {
+	return func(s *Service) {
+		s.decimals = decimals
+	}
+}
+
+// WithCurrencies restricts the accepted store currencies, e.g. "USD".
+// All currencies are accepted if the list is empty.
+func WithCurrencies(currencies ...string) ServiceOption {
+	return func(s *Service) {
+		s.currencies = currencies
+	}
+}
+
+// SetCurrencies replaces the accepted store currencies, e.g. on the config reload.
+// All currencies are accepted if the list is empty.
+func (s *Service) SetCurrencies(currencies ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.currencies = currencies
+}
+
+// CreateShopifyPayment creates a payment for the Shopify order.
+func (s *Service) CreateShopifyPayment(ctx context.Context, order ShopifyOrder) (*payments.Payment, error) {
+	if s.shopify == nil {
+		return nil, ErrNotConfigured
+	}
+	if order.ID == 0 {
+		return nil, ErrInvalidRequest
+	}
+
+	return s.createPayment(ctx, PlatformShopify, order.ID, order.Name, order.TotalPrice, order.Currency, order.Email)
+}
+
+// CreateWooCommercePayment creates a payment for the WooCommerce order.
+func (s *Service) CreateWooCommercePayment(ctx context.Context, order WooCommerceOrder) (*payments.Payment, error) {
+	if s.woocommerce == nil {
+		return nil, ErrNotConfigured
+	}
+	if order.ID == 0 {
+		return nil, ErrInvalidRequest
+	}
+
+	name := order.Number
+	if name == "" {
+		name = strconv.FormatInt(order.ID, 10)
+	}
+
+	return s.createPayment(ctx, PlatformWooCommerce, order.ID, "#"+strings.TrimPrefix(name, "#"), order.Total, order.Currency, order.Billing.Email)
+}
+
+// createPayment creates a payment for the platform order.
+func (s *Service) createPayment(ctx context.Context, platform string, orderID int64, orderName, total, currency, email string) (*payments.Payment, error) {
+	if !s.currencySupported(currency) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCurrency, currency)
+	}
+
+	amount, err := parseDecimalAmount(total, s.decimals)
+	if err != nil {
+		return nil, err
+	}
+	if amount == 0 {
+		return nil, fmt.Errorf("%w: order total is zero", ErrInvalidAmount)
+	}
+
+	return s.paymentSvc.CreatePayment(ctx, &payments.Payment{
+		ExternalID:    externalID(platform, orderID),
+		Amount:        amount,
+		Message:       fmt.Sprintf("Order %s", orderName),
+		CustomerEmail: email,
+	})
+}
+
+// SyncOrderStatus updates the platform order status according to the payment event.
+// Payments which were not created by an integration are ignored.
+func (s *Service) SyncOrderStatus(ctx context.Context, event string, payment *payments.Payment) error {
+	platform, orderID, ok := parseExternalID(payment.ExternalID)
+	if !ok {
+		return nil
+	}
+
+	switch platform {
+	case PlatformShopify:
+		if s.shopify == nil {
+			return nil
+		}
+		switch events.EventName(event) {
+		case events.PaymentSucceeded, events.PaymentHeld:
+			return s.shopify.MarkOrderAsPaid(ctx, orderID, formatDecimalAmount(payment.Amount, s.decimals))
+		case events.PaymentFailed, events.PaymentCancelled, events.PaymentExpired:
+			return s.shopify.CancelOrder(ctx, orderID, "declined")
+		}
+	case PlatformWooCommerce:
+		if s.woocommerce == nil {
+			return nil
+		}
+		switch events.EventName(event) {
+		case events.PaymentSucceeded, events.PaymentHeld:
+			return s.woocommerce.UpdateOrderStatus(ctx, orderID, WooCommerceStatusProcessing)
+		case events.PaymentFailed:
+			return s.woocommerce.UpdateOrderStatus(ctx, orderID, WooCommerceStatusFailed)
+		case events.PaymentCancelled, events.PaymentExpired:
+			return s.woocommerce.UpdateOrderStatus(ctx, orderID, WooCommerceStatusCancelled)
+		}
+	}
+
+	return nil
+}
+
+// currencySupported checks if the store currency is accepted.
+func (s *Service) currencySupported(currency string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.currencies) == 0 {
+		return true
+	}
+	for _, c := range s.currencies {
+		if strings.EqualFold(c, currency) {
+			return true
+		}
+	}
+	return false
+}
+
+// externalID returns the payment external ID for the platform order.
+func externalID(platform string, orderID int64) string {
+	return platform + ":" + strconv.FormatInt(orderID, 10)
+}
+
+// parseExternalID returns the platform and order ID from the payment external ID.
+func parseExternalID(id string) (platform, orderID string, ok bool) {
+	platform, orderID, ok = strings.Cut(id, ":")
+	if !ok || orderID == "" {
+		return "", "", false
+	}
+	switch platform {
+	case PlatformShopify, PlatformWooCommerce:
+		return platform, orderID, true
+	}
+	return "", "", false
+}
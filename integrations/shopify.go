@@ -0,0 +1,103 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Default Shopify Admin API version.
+const defaultShopifyAPIVersion = "2023-04"
+
+type (
+	// ShopifyClient is a minimal Shopify Admin REST API client to update orders.
+	ShopifyClient struct {
+		client      *http.Client
+		baseURL     string
+		accessToken string
+	}
+
+	// ShopifyClientOption is a function that configures the ShopifyClient.
+	ShopifyClientOption func(*ShopifyClient)
+)
+
+// NewShopifyClient creates a new Shopify client for the given shop domain, e.g. "my-store.myshopify.com".
+func NewShopifyClient(shopDomain, accessToken string, opts ...ShopifyClientOption) *ShopifyClient {
+	c := &ShopifyClient{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		baseURL:     fmt.Sprintf("https://%s/admin/api/%s/", strings.Trim(shopDomain, "/"), defaultShopifyAPIVersion),
+		accessToken: accessToken,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithShopifyHTTPClient sets a custom HTTP client.
+func WithShopifyHTTPClient(client *http.Client) ShopifyClientOption {
+	return func(c *ShopifyClient) {
+		c.client = client
+	}
+}
+
+// WithShopifyBaseURL sets a custom Admin API base URL, e.g. to use another API version.
+func WithShopifyBaseURL(baseURL string) ShopifyClientOption {
+	return func(c *ShopifyClient) {
+		c.baseURL = strings.TrimRight(baseURL, "/") + "/"
+	}
+}
+
+// MarkOrderAsPaid captures the order amount, so the order becomes paid.
+func (c *ShopifyClient) MarkOrderAsPaid(ctx context.Context, orderID, amount string) error {
+	return c.call(ctx, fmt.Sprintf("orders/%s/transactions.json", orderID), map[string]interface{}{
+		"transaction": map[string]interface{}{
+			"kind":   "capture",
+			"status": "success",
+			"source": "external",
+			"amount": amount,
+		},
+	})
+}
+
+// CancelOrder cancels the order.
+func (c *ShopifyClient) CancelOrder(ctx context.Context, orderID, reason string) error {
+	return c.call(ctx, fmt.Sprintf("orders/%s/cancel.json", orderID), map[string]interface{}{
+		"reason": reason,
+	})
+}
+
+// call makes a POST request to the Shopify Admin API.
+func (c *ShopifyClient) call(ctx context.Context, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("shopify: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("shopify: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Shopify-Access-Token", c.accessToken)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("shopify: failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("shopify: unexpected status code: %d: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
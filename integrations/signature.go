@@ -0,0 +1,25 @@
+package integrations
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// VerifySignature checks the base64-encoded HMAC-SHA256 signature of the webhook body.
+// Both Shopify and WooCommerce sign webhooks this way.
+func VerifySignature(body []byte, signature string, secret []byte) bool {
+	if len(secret) == 0 || signature == "" {
+		return false
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	return hmac.Equal(sig, mac.Sum(nil))
+}
@@ -0,0 +1,24 @@
+package integrations
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"id":1}`)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	require.True(t, VerifySignature(body, signature, secret))
+	require.False(t, VerifySignature([]byte(`{"id":2}`), signature, secret))
+	require.False(t, VerifySignature(body, signature, nil))
+	require.False(t, VerifySignature(body, "", secret))
+}
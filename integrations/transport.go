@@ -0,0 +1,121 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+// Max webhook body size to read.
+const maxBodySize = 1 << 20 // 1 MB
+
+type logger interface {
+	Log(keyvals ...interface{}) error
+}
+
+// MakeHTTPHandler returns an http.Handler that serves the platform callbacks.
+// The callbacks are authorized by the platform webhook signatures instead of OAuth2.
+func MakeHTTPHandler(e Endpoints, log logger, shopifySecret, wooCommerceSecret []byte) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Post("/shopify/orders", httptransport.NewServer(
+		e.ShopifyOrderCreated,
+		decodeShopifyOrderRequest(shopifySecret),
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/woocommerce/orders", httptransport.NewServer(
+		e.WooCommerceOrderCreated,
+		decodeWooCommerceOrderRequest(wooCommerceSecret),
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, ErrInvalidSignature):
+		return http.StatusUnauthorized, err.Error()
+	case errors.Is(err, ErrNotConfigured):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrInvalidRequest),
+		errors.Is(err, ErrInvalidAmount),
+		errors.Is(err, ErrUnsupportedCurrency):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeShopifyOrderRequest returns a transport/http.DecodeRequestFunc that verifies
+// the Shopify webhook signature and decodes the order from the HTTP request body.
+func decodeShopifyOrderRequest(secret []byte) httptransport.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		body, err := readSignedBody(r, ShopifySignatureHeader, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		var req ShopifyOrder
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+		}
+
+		return req, nil
+	}
+}
+
+// decodeWooCommerceOrderRequest returns a transport/http.DecodeRequestFunc that verifies
+// the WooCommerce webhook signature and decodes the order from the HTTP request body.
+func decodeWooCommerceOrderRequest(secret []byte) httptransport.DecodeRequestFunc {
+	return func(ctx context.Context, r *http.Request) (interface{}, error) {
+		// The webhook ping is sent as a form without a signature.
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			return WooCommerceOrder{}, nil
+		}
+
+		body, err := readSignedBody(r, WooCommerceSignatureHeader, secret)
+		if err != nil {
+			return nil, err
+		}
+
+		var req WooCommerceOrder
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+		}
+
+		return req, nil
+	}
+}
+
+// readSignedBody reads the request body and verifies its signature.
+func readSignedBody(r *http.Request, header string, secret []byte) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	if !VerifySignature(body, r.Header.Get(header), secret) {
+		return nil, ErrInvalidSignature
+	}
+
+	return body, nil
+}
@@ -0,0 +1,47 @@
+package integrations
+
+// Supported platforms.
+// The platform name is used as a prefix of the payment external ID, e.g. "shopify:450789469".
+const (
+	PlatformShopify     = "shopify"
+	PlatformWooCommerce = "woocommerce"
+)
+
+// Webhook signature headers.
+const (
+	ShopifySignatureHeader     = "X-Shopify-Hmac-Sha256"
+	WooCommerceSignatureHeader = "X-WC-Webhook-Signature"
+)
+
+type (
+	// ShopifyOrder is a subset of the Shopify order object sent with the orders/create webhook.
+	ShopifyOrder struct {
+		ID         int64  `json:"id"`
+		Name       string `json:"name"`        // e.g. "#1001"
+		TotalPrice string `json:"total_price"` // decimal string, e.g. "10.50"
+		Currency   string `json:"currency"`
+		Email      string `json:"email"`
+	}
+
+	// WooCommerceOrder is a subset of the WooCommerce order object sent with the order.created webhook.
+	WooCommerceOrder struct {
+		ID       int64  `json:"id"`
+		Number   string `json:"number"`
+		Total    string `json:"total"` // decimal string, e.g. "10.50"
+		Currency string `json:"currency"`
+		Billing  struct {
+			Email string `json:"email"`
+		} `json:"billing"`
+	}
+)
+
+// Worker task types
+const (
+	TaskSyncOrderStatus = "integrations:sync_order_status"
+)
+
+// SyncOrderStatusPayload is the payload for the integrations:sync_order_status task.
+type SyncOrderStatusPayload struct {
+	Event     string `json:"event"`
+	PaymentID string `json:"payment_id"`
+}
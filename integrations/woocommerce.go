@@ -0,0 +1,86 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WooCommerce order statuses.
+const (
+	WooCommerceStatusProcessing = "processing"
+	WooCommerceStatusFailed     = "failed"
+	WooCommerceStatusCancelled  = "cancelled"
+)
+
+type (
+	// WooCommerceClient is a minimal WooCommerce REST API client to update orders.
+	WooCommerceClient struct {
+		client         *http.Client
+		baseURL        string
+		consumerKey    string
+		consumerSecret string
+	}
+
+	// WooCommerceClientOption is a function that configures the WooCommerceClient.
+	WooCommerceClientOption func(*WooCommerceClient)
+)
+
+// NewWooCommerceClient creates a new WooCommerce client for the given store URL, e.g. "https://my-store.com".
+func NewWooCommerceClient(storeURL, consumerKey, consumerSecret string, opts ...WooCommerceClientOption) *WooCommerceClient {
+	c := &WooCommerceClient{
+		client:         &http.Client{Timeout: 10 * time.Second},
+		baseURL:        strings.TrimRight(storeURL, "/") + "/wp-json/wc/v3/",
+		consumerKey:    consumerKey,
+		consumerSecret: consumerSecret,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithWooCommerceHTTPClient sets a custom HTTP client.
+func WithWooCommerceHTTPClient(client *http.Client) WooCommerceClientOption {
+	return func(c *WooCommerceClient) {
+		c.client = client
+	}
+}
+
+// UpdateOrderStatus sets the status of the order.
+func (c *WooCommerceClient) UpdateOrderStatus(ctx context.Context, orderID, status string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"status":   status,
+		"set_paid": status == WooCommerceStatusProcessing,
+	})
+	if err != nil {
+		return fmt.Errorf("woocommerce: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.baseURL+"orders/"+orderID, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("woocommerce: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.consumerKey, c.consumerSecret)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("woocommerce: failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("woocommerce: unexpected status code: %d: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+type (
+	// Worker is a task handler for the order status sync.
+	Worker struct {
+		svc        service
+		paymentSvc paymentGetter
+	}
+
+	service interface {
+		SyncOrderStatus(ctx context.Context, event string, payment *payments.Payment) error
+	}
+
+	paymentGetter interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (*payments.Payment, error)
+	}
+)
+
+// NewWorker creates a new order status sync task handler.
+func NewWorker(svc service, paymentSvc paymentGetter) *Worker {
+	return &Worker{svc: svc, paymentSvc: paymentSvc}
+}
+
+// Register registers task handlers for the order status sync.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskSyncOrderStatus, w.SyncOrderStatus)
+}
+
+// SyncOrderStatus updates the platform order status according to the payment event.
+func (w *Worker) SyncOrderStatus(ctx context.Context, t *asynq.Task) error {
+	var p SyncOrderStatusPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	pid, err := uuid.Parse(p.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to parse payment id: %w", err)
+	}
+
+	payment, err := w.paymentSvc.GetPayment(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	if err := w.svc.SyncOrderStatus(ctx, p.Event, payment); err != nil {
+		return fmt.Errorf("failed to sync order status: %w", err)
+	}
+
+	return nil
+}
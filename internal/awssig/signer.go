@@ -0,0 +1,154 @@
+package awssig
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Algorithm is the signing algorithm used by AWS Signature Version 4.
+	Algorithm = "AWS4-HMAC-SHA256"
+
+	timeFormat  = "20060102T150405Z"
+	shortFormat = "20060102"
+)
+
+// Credentials represents AWS credentials used to sign requests.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // optional; set only for temporary credentials
+}
+
+// SignRequest signs the given HTTP request with AWS Signature Version 4.
+// The body must be the same bytes that will be sent with the request.
+// It sets X-Amz-Date, X-Amz-Security-Token (if needed) and Authorization headers.
+func SignRequest(req *http.Request, body []byte, creds Credentials, region, service string, t time.Time) error {
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return fmt.Errorf("aws credentials are not set")
+	}
+
+	t = t.UTC()
+	amzDate := t.Format(timeFormat)
+	date := t.Format(shortFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+	if req.Header.Get("Host") == "" {
+		req.Header.Set("Host", req.URL.Host)
+	}
+
+	canonicalHeaders, signedHeaders := canonicalHeaders(req.Header)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI(req.URL),
+		canonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		hashHex(body),
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{
+		Algorithm,
+		amzDate,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), []byte(date))
+	key = hmacSHA256(key, []byte(region))
+	key = hmacSHA256(key, []byte(service))
+	key = hmacSHA256(key, []byte("aws4_request"))
+	signature := hex.EncodeToString(hmacSHA256(key, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		Algorithm, creds.AccessKeyID, scope, signedHeaders, signature,
+	))
+
+	return nil
+}
+
+// canonicalURI returns the URI-encoded path of the request.
+func canonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// canonicalQuery returns the sorted, URI-encoded query string of the request.
+func canonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values := query[k]
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, escape(k)+"="+escape(v))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// canonicalHeaders returns canonical headers and the list of signed headers.
+func canonicalHeaders(h http.Header) (string, string) {
+	names := make([]string, 0, len(h))
+	values := make(map[string]string, len(h))
+	for k, v := range h {
+		name := strings.ToLower(k)
+		if name == "authorization" || name == "user-agent" {
+			continue
+		}
+		names = append(names, name)
+		trimmed := make([]string, 0, len(v))
+		for _, s := range v {
+			trimmed = append(trimmed, strings.Join(strings.Fields(s), " "))
+		}
+		values[name] = strings.Join(trimmed, ",")
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteString(":")
+		b.WriteString(values[name])
+		b.WriteString("\n")
+	}
+
+	return b.String(), strings.Join(names, ";")
+}
+
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func hashHex(b []byte) string {
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
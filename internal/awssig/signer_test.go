@@ -0,0 +1,38 @@
+package awssig_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/awssig"
+	"github.com/stretchr/testify/require"
+)
+
+// Example from the AWS documentation:
+// https://docs.aws.amazon.com/general/latest/gr/sigv4-create-canonical-request.html
+func TestSignRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	err = awssig.SignRequest(req, nil, awssig.Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "iam", time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	auth := req.Header.Get("Authorization")
+	require.True(t, strings.HasPrefix(auth, awssig.Algorithm+" Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request"))
+	require.Contains(t, auth, "SignedHeaders=content-type;host;x-amz-date")
+	require.Contains(t, auth, "Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7")
+}
+
+func TestSignRequest_MissingCredentials(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://kms.us-east-1.amazonaws.com/", nil)
+	require.NoError(t, err)
+
+	err = awssig.SignRequest(req, nil, awssig.Credentials{}, "us-east-1", "kms", time.Now())
+	require.Error(t, err)
+}
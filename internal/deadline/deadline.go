@@ -0,0 +1,66 @@
+// Package deadline enforces the per-route deadlines of the API,
+// e.g. the tighter ones of the routes which call the Solana RPC and Jupiter.
+package deadline
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ErrDeadlineExceeded is the machine-readable error of the requests which exceeded the deadline.
+var ErrDeadlineExceeded = errors.New("deadline_exceeded")
+
+// Message is the error message of the requests which exceeded the deadline.
+const Message = "The request took too long to process, please try again later"
+
+// Middleware returns the middleware which sets the deadline of the request context,
+// so the chain calls made by the handler are cancelled once it's exceeded.
+// The handler is expected to respond with ErrDeadlineExceeded, see Error;
+// if it hasn't written anything by the deadline, the middleware responds instead.
+// The zero timeout disables the middleware.
+func Middleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			if ww.Status() == 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				writeError(ww, r)
+			}
+		})
+	}
+}
+
+// Error returns ErrDeadlineExceeded if the error is caused by the exceeded deadline,
+// e.g. of the request context or of the outbound request, otherwise the error as is.
+func Error(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrDeadlineExceeded
+	}
+	return err
+}
+
+// writeError writes the 504 response with the ErrDeadlineExceeded code.
+func writeError(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set(httpencoder.ContentTypeHeader, httpencoder.ContentType)
+	w.WriteHeader(http.StatusGatewayTimeout)
+	_ = json.NewEncoder(w).Encode(httpencoder.ErrorResponse{
+		Code:      http.StatusGatewayTimeout,
+		Error:     ErrDeadlineExceeded.Error(),
+		Message:   Message,
+		RequestID: middleware.GetReqID(r.Context()),
+	})
+}
@@ -0,0 +1,57 @@
+package deadline_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/deadline"
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	w := httptest.NewRecorder()
+	deadline.Middleware(10*time.Millisecond)(slow).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	var resp httpencoder.ErrorResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	require.Equal(t, "deadline_exceeded", resp.Error)
+}
+
+func TestMiddlewareResponded(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	w := httptest.NewRecorder()
+	deadline.Middleware(10*time.Millisecond)(handler).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	require.Equal(t, http.StatusTeapot, w.Code)
+}
+
+func TestMiddlewareDisabled(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		require.False(t, ok)
+	})
+
+	w := httptest.NewRecorder()
+	deadline.Middleware(0)(handler).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestError(t *testing.T) {
+	err := fmt.Errorf("failed to get swap quote: %w", context.DeadlineExceeded)
+	require.Equal(t, deadline.ErrDeadlineExceeded, deadline.Error(err))
+	require.Equal(t, context.Canceled, deadline.Error(context.Canceled))
+}
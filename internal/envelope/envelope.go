@@ -0,0 +1,179 @@
+package envelope
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// KeySize is the size of the data encryption key and the local key encryption key (AES-256).
+const KeySize = 32
+
+// version is the version prefix of the serialized envelope.
+const version = "v1"
+
+type (
+	// Encrypter encrypts secrets with the envelope encryption scheme:
+	// every secret is encrypted with a unique data encryption key (AES-256-GCM),
+	// which is wrapped with the primary key encryption key and stored along with the ciphertext.
+	// Old key encryption keys are used only to decrypt data encrypted before the key rotation.
+	Encrypter struct {
+		primary KEK
+		keys    map[string]KEK
+	}
+)
+
+// NewEncrypter creates a new envelope encrypter.
+// The primary key is used to encrypt new data; old keys are used only for decryption.
+func NewEncrypter(primary KEK, old ...KEK) (*Encrypter, error) {
+	if primary == nil {
+		return nil, ErrNoPrimaryKey
+	}
+
+	e := &Encrypter{
+		primary: primary,
+		keys:    make(map[string]KEK, len(old)+1),
+	}
+	for _, k := range append(old, primary) {
+		if k.ID() == "" {
+			return nil, ErrInvalidKeyID
+		}
+		e.keys[k.ID()] = k
+	}
+
+	return e, nil
+}
+
+// Encrypt encrypts the given plaintext and returns the serialized envelope,
+// which is safe to store in the database as a string.
+func (e *Encrypter) Encrypt(ctx context.Context, plaintext []byte) (string, error) {
+	dek := make([]byte, KeySize)
+	if _, err := rand.Read(dek); err != nil {
+		return "", fmt.Errorf("failed to generate data encryption key: %w", err)
+	}
+
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return "", err
+	}
+
+	ciphertext, err := seal(aead, plaintext, []byte(e.primary.ID()))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt data: %w", err)
+	}
+
+	wrappedDEK, err := e.primary.Wrap(ctx, dek)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data encryption key: %w", err)
+	}
+
+	return marshal(e.primary.ID(), wrappedDEK, ciphertext), nil
+}
+
+// EncryptString is a shortcut for Encrypt with a string plaintext.
+func (e *Encrypter) EncryptString(ctx context.Context, plaintext string) (string, error) {
+	return e.Encrypt(ctx, []byte(plaintext))
+}
+
+// Decrypt decrypts the given serialized envelope.
+func (e *Encrypter) Decrypt(ctx context.Context, envelope string) ([]byte, error) {
+	kekID, wrappedDEK, ciphertext, err := unmarshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	kek, ok := e.keys[kekID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, kekID)
+	}
+
+	dek, err := kek.Unwrap(ctx, wrappedDEK)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data encryption key: %w", err)
+	}
+
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	return open(aead, ciphertext, []byte(kekID))
+}
+
+// DecryptString is a shortcut for Decrypt with a string result.
+func (e *Encrypter) DecryptString(ctx context.Context, envelope string) (string, error) {
+	plaintext, err := e.Decrypt(ctx, envelope)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation returns true if the given envelope is not encrypted with the primary key.
+func (e *Encrypter) NeedsRotation(envelope string) bool {
+	kekID, _, _, err := unmarshal(envelope)
+	if err != nil {
+		return false
+	}
+
+	return kekID != e.primary.ID()
+}
+
+// Rotate re-encrypts the given envelope with the primary key.
+// Returns the envelope as is, if it is already encrypted with the primary key.
+func (e *Encrypter) Rotate(ctx context.Context, envelope string) (string, error) {
+	if !e.NeedsRotation(envelope) {
+		return envelope, nil
+	}
+
+	plaintext, err := e.Decrypt(ctx, envelope)
+	if err != nil {
+		return "", fmt.Errorf("failed to rotate key: %w", err)
+	}
+
+	return e.Encrypt(ctx, plaintext)
+}
+
+// GenerateKey generates a new random base64 encoded key, which can be used as a local key encryption key.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// marshal serializes the envelope parts into the string: v1.<kek id>.<wrapped dek>.<ciphertext>
+func marshal(kekID string, wrappedDEK, ciphertext []byte) string {
+	return strings.Join([]string{
+		version,
+		base64.RawURLEncoding.EncodeToString([]byte(kekID)),
+		base64.RawURLEncoding.EncodeToString(wrappedDEK),
+		base64.RawURLEncoding.EncodeToString(ciphertext),
+	}, ".")
+}
+
+// unmarshal parses the serialized envelope.
+func unmarshal(envelope string) (kekID string, wrappedDEK, ciphertext []byte, err error) {
+	parts := strings.Split(envelope, ".")
+	if len(parts) != 4 || parts[0] != version {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+
+	id, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+	if wrappedDEK, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+	if ciphertext, err = base64.RawURLEncoding.DecodeString(parts[3]); err != nil {
+		return "", nil, nil, ErrMalformedCiphertext
+	}
+
+	return string(id), wrappedDEK, ciphertext, nil
+}
@@ -0,0 +1,75 @@
+package envelope_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/internal/envelope"
+	"github.com/stretchr/testify/require"
+)
+
+func newLocalKEK(t *testing.T, id string) *envelope.LocalKEK {
+	key, err := envelope.GenerateKey()
+	require.NoError(t, err)
+
+	kek, err := envelope.NewLocalKEKFromBase64(id, key)
+	require.NoError(t, err)
+
+	return kek
+}
+
+func TestEncrypter(t *testing.T) {
+	ctx := context.Background()
+
+	enc, err := envelope.NewEncrypter(newLocalKEK(t, "key-1"))
+	require.NoError(t, err)
+
+	ciphertext, err := enc.EncryptString(ctx, "private key")
+	require.NoError(t, err)
+	require.NotContains(t, ciphertext, "private key")
+
+	plaintext, err := enc.DecryptString(ctx, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "private key", plaintext)
+
+	// tampered ciphertext
+	_, err = enc.Decrypt(ctx, ciphertext[:len(ciphertext)-2]+"AA")
+	require.Error(t, err)
+
+	// malformed ciphertext
+	_, err = enc.Decrypt(ctx, "private key")
+	require.ErrorIs(t, err, envelope.ErrMalformedCiphertext)
+}
+
+func TestEncrypter_Rotate(t *testing.T) {
+	ctx := context.Background()
+	oldKEK := newLocalKEK(t, "key-1")
+	newKEK := newLocalKEK(t, "key-2")
+
+	oldEnc, err := envelope.NewEncrypter(oldKEK)
+	require.NoError(t, err)
+
+	ciphertext, err := oldEnc.EncryptString(ctx, "private key")
+	require.NoError(t, err)
+
+	newEnc, err := envelope.NewEncrypter(newKEK, oldKEK)
+	require.NoError(t, err)
+	require.True(t, newEnc.NeedsRotation(ciphertext))
+
+	// old data is still readable after the key rotation
+	plaintext, err := newEnc.DecryptString(ctx, ciphertext)
+	require.NoError(t, err)
+	require.Equal(t, "private key", plaintext)
+
+	rotated, err := newEnc.Rotate(ctx, ciphertext)
+	require.NoError(t, err)
+	require.False(t, newEnc.NeedsRotation(rotated))
+
+	plaintext, err = newEnc.DecryptString(ctx, rotated)
+	require.NoError(t, err)
+	require.Equal(t, "private key", plaintext)
+
+	// the old key can't decrypt data encrypted with the new key
+	_, err = oldEnc.Decrypt(ctx, rotated)
+	require.ErrorIs(t, err, envelope.ErrUnknownKey)
+}
@@ -0,0 +1,13 @@
+package envelope
+
+import "errors"
+
+// Predefined package errors.
+var (
+	ErrInvalidKeyID        = errors.New("key encryption key id is required")
+	ErrInvalidKeySize      = errors.New("key must be 32 bytes long")
+	ErrUnknownKey          = errors.New("unknown key encryption key")
+	ErrMalformedCiphertext = errors.New("malformed ciphertext")
+	ErrDecryptionFailed    = errors.New("failed to decrypt data")
+	ErrNoPrimaryKey        = errors.New("primary key encryption key is required")
+)
@@ -0,0 +1,218 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/awssig"
+)
+
+type (
+	// KEK is a key encryption key, which is used to wrap and unwrap data encryption keys.
+	KEK interface {
+		// ID returns the unique identifier of the key, it is stored with the encrypted data
+		// to find the right key for decryption after the key rotation.
+		ID() string
+		// Wrap encrypts the given data encryption key.
+		Wrap(ctx context.Context, dek []byte) ([]byte, error)
+		// Unwrap decrypts the given wrapped data encryption key.
+		Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+	}
+
+	// LocalKEK is a key encryption key kept in memory, e.g. loaded from the environment.
+	LocalKEK struct {
+		id   string
+		aead cipher.AEAD
+	}
+
+	// AWSKMSKEK is a key encryption key stored in AWS KMS (symmetric key).
+	AWSKMSKEK struct {
+		client   *http.Client
+		endpoint string
+		region   string
+		keyID    string
+		creds    awssig.Credentials
+	}
+)
+
+// NewLocalKEK creates a new local key encryption key from the given 32 bytes key.
+func NewLocalKEK(id string, key []byte) (*LocalKEK, error) {
+	if id == "" {
+		return nil, ErrInvalidKeyID
+	}
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalKEK{id: id, aead: aead}, nil
+}
+
+// NewLocalKEKFromBase64 creates a new local key encryption key from the given base64 encoded 32 bytes key.
+func NewLocalKEKFromBase64(id, base64Key string) (*LocalKEK, error) {
+	key, err := base64.StdEncoding.DecodeString(base64Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode key: %w", err)
+	}
+
+	return NewLocalKEK(id, key)
+}
+
+// ID returns the key identifier.
+func (k *LocalKEK) ID() string {
+	return k.id
+}
+
+// Wrap encrypts the given data encryption key.
+func (k *LocalKEK) Wrap(_ context.Context, dek []byte) ([]byte, error) {
+	return seal(k.aead, dek, []byte(k.id))
+}
+
+// Unwrap decrypts the given wrapped data encryption key.
+func (k *LocalKEK) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	return open(k.aead, wrapped, []byte(k.id))
+}
+
+// NewAWSKMSKEK creates a new key encryption key backed by the AWS KMS symmetric key.
+// The key ID is used as the KEK identifier, so use the key ARN or alias ARN
+// to be able to rotate the key.
+func NewAWSKMSKEK(keyID, region string, creds awssig.Credentials) (*AWSKMSKEK, error) {
+	if keyID == "" {
+		return nil, ErrInvalidKeyID
+	}
+	if region == "" {
+		return nil, fmt.Errorf("aws region is required")
+	}
+
+	return &AWSKMSKEK{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: fmt.Sprintf("https://kms.%s.amazonaws.com/", region),
+		region:   region,
+		keyID:    keyID,
+		creds:    creds,
+	}, nil
+}
+
+// ID returns the key identifier.
+func (k *AWSKMSKEK) ID() string {
+	return k.keyID
+}
+
+// Wrap encrypts the given data encryption key with the KMS key.
+func (k *AWSKMSKEK) Wrap(ctx context.Context, dek []byte) ([]byte, error) {
+	var resp struct {
+		CiphertextBlob string `json:"CiphertextBlob"`
+	}
+	if err := k.call(ctx, "TrentService.Encrypt", map[string]interface{}{
+		"KeyId":     k.keyID,
+		"Plaintext": base64.StdEncoding.EncodeToString(dek),
+	}, &resp); err != nil {
+		return nil, fmt.Errorf("aws kms: encrypt: %w", err)
+	}
+
+	return base64.StdEncoding.DecodeString(resp.CiphertextBlob)
+}
+
+// Unwrap decrypts the given wrapped data encryption key with the KMS key.
+func (k *AWSKMSKEK) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var resp struct {
+		Plaintext string `json:"Plaintext"`
+	}
+	if err := k.call(ctx, "TrentService.Decrypt", map[string]interface{}{
+		"KeyId":          k.keyID,
+		"CiphertextBlob": base64.StdEncoding.EncodeToString(wrapped),
+	}, &resp); err != nil {
+		return nil, fmt.Errorf("aws kms: decrypt: %w", err)
+	}
+
+	return base64.StdEncoding.DecodeString(resp.Plaintext)
+}
+
+// call makes a signed request to the AWS KMS JSON API.
+func (k *AWSKMSKEK) call(ctx context.Context, target string, payload, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+
+	if err := awssig.SignRequest(req, body, k.creds, k.region, "kms", time.Now()); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, string(msg))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// newAEAD creates a new AES-GCM cipher with the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcm: %w", err)
+	}
+
+	return aead, nil
+}
+
+// seal encrypts the plaintext and prepends a random nonce to the ciphertext.
+func seal(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// open decrypts the ciphertext with the prepended nonce.
+func open(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, ErrMalformedCiphertext
+	}
+
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,268 @@
+// Command gendecorators generates the logging, metrics and tracing decorators
+// for a service interface, so every interface method is always covered.
+//
+// Usage (from a go:generate directive in the package of the interface):
+//
+//	go run github.com/easypmnt/checkout-api/internal/gendecorators -source interface.go -type PaymentService -output service_middleware_gen.go
+//
+// Every method of the interface must accept a context.Context as the first argument
+// and return an error as the last result.
+// The generated decorators call the hooks defined by hand in the same package:
+//
+//	func (m *loggingMiddleware) logCall(ctx context.Context, method string, begin time.Time, err error, args ...interface{})
+//	func (m *metricsMiddleware) observeCall(method string, begin time.Time, err error)
+//	m.tracer.Start(ctx context.Context, name string) (context.Context, func(err error))
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+type (
+	method struct {
+		Name    string
+		Ctx     string // name of the context argument
+		Params  string // full parameter list, e.g. "ctx context.Context, id uuid.UUID"
+		Results string // named result list, e.g. "r0 *Payment, err error"
+		Args    string // forwarded arguments, e.g. "ctx, id"
+		LogArgs string // arguments to log, e.g. ", id"
+	}
+
+	file struct {
+		Package    string
+		Type       string
+		Source     string
+		TraceName  string
+		StdImports []string
+		Imports    []string
+		Methods    []method
+	}
+)
+
+func main() {
+	source := flag.String("source", "interface.go", "file with the interface declaration")
+	typeName := flag.String("type", "", "interface name")
+	output := flag.String("output", "", "output file name")
+	flag.Parse()
+
+	if *typeName == "" || *output == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := parse(*source, *typeName)
+	if err != nil {
+		log.Fatalf("gendecorators: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, f); err != nil {
+		log.Fatalf("gendecorators: execute template: %v", err)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("gendecorators: format output: %v\n%s", err, buf.String())
+	}
+
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatalf("gendecorators: write output: %v", err)
+	}
+}
+
+// parse parses the source file and collects the methods of the given interface.
+func parse(source, typeName string) (*file, error) {
+	fset := token.NewFileSet()
+	astFile, err := parser.ParseFile(fset, source, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", source, err)
+	}
+
+	iface := findInterface(astFile, typeName)
+	if iface == nil {
+		return nil, fmt.Errorf("interface %s not found in %s", typeName, source)
+	}
+
+	f := &file{
+		Package:    astFile.Name.Name,
+		Type:       typeName,
+		Source:     source,
+		TraceName:  astFile.Name.Name,
+		StdImports: []string{strconv.Quote("time")},
+	}
+	for _, imp := range astFile.Imports {
+		if imp.Path.Value == strconv.Quote("time") {
+			continue
+		}
+		path := imp.Path.Value
+		if imp.Name != nil {
+			path = imp.Name.Name + " " + path
+		}
+		// standard library packages have no dots in the first path element
+		if strings.Contains(strings.SplitN(imp.Path.Value, "/", 2)[0], ".") {
+			f.Imports = append(f.Imports, path)
+		} else {
+			f.StdImports = append(f.StdImports, path)
+		}
+	}
+	sort.Strings(f.StdImports)
+	sort.Strings(f.Imports)
+
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			return nil, fmt.Errorf("%s: embedded interfaces are not supported", typeName)
+		}
+		m, err := parseMethod(fset, field.Names[0].Name, fn)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", typeName, field.Names[0].Name, err)
+		}
+		f.Methods = append(f.Methods, m)
+	}
+
+	return f, nil
+}
+
+// findInterface returns the declaration of the interface with the given name.
+func findInterface(f *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if ts.Name.Name != name {
+				continue
+			}
+			if iface, ok := ts.Type.(*ast.InterfaceType); ok {
+				return iface
+			}
+		}
+	}
+	return nil
+}
+
+// parseMethod builds the method signature with named parameters and results.
+func parseMethod(fset *token.FileSet, name string, fn *ast.FuncType) (method, error) {
+	m := method{Name: name}
+
+	var params, args, logArgs []string
+	for i, p := range fn.Params.List {
+		typ := exprString(fset, p.Type)
+		names := p.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("a%d", i))}
+		}
+		for _, n := range names {
+			params = append(params, n.Name+" "+typ)
+			arg := n.Name
+			if strings.HasPrefix(typ, "...") {
+				arg += "..."
+			}
+			args = append(args, arg)
+			if typ != "context.Context" {
+				logArgs = append(logArgs, n.Name)
+			}
+		}
+	}
+	if len(params) == 0 || !strings.HasSuffix(params[0], " context.Context") {
+		return m, fmt.Errorf("the first argument must be context.Context")
+	}
+	m.Ctx = strings.TrimSuffix(params[0], " context.Context")
+
+	if fn.Results == nil || len(fn.Results.List) == 0 {
+		return m, fmt.Errorf("the last result must be error")
+	}
+	var results []string
+	for i, r := range fn.Results.List {
+		if len(r.Names) > 1 {
+			return m, fmt.Errorf("grouped results are not supported")
+		}
+		typ := exprString(fset, r.Type)
+		if i == len(fn.Results.List)-1 {
+			if typ != "error" {
+				return m, fmt.Errorf("the last result must be error")
+			}
+			results = append(results, "err error")
+			continue
+		}
+		results = append(results, fmt.Sprintf("r%d %s", i, typ))
+	}
+
+	m.Params = strings.Join(params, ", ")
+	m.Results = strings.Join(results, ", ")
+	m.Args = strings.Join(args, ", ")
+	if len(logArgs) > 0 {
+		m.LogArgs = ", " + strings.Join(logArgs, ", ")
+	}
+
+	return m, nil
+}
+
+// exprString returns the source code of the expression.
+func exprString(fset *token.FileSet, expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		log.Fatalf("gendecorators: print expression: %v", err)
+	}
+	return buf.String()
+}
+
+var tmpl = template.Must(template.New("decorators").Parse(`// Code generated by gendecorators from {{.Source}}; DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .StdImports}}
+	{{.}}
+{{- end}}
+{{if .Imports}}
+{{- range .Imports}}
+	{{.}}
+{{- end}}
+{{- end}}
+)
+
+// Ensure the decorators implement every method of the interface.
+var (
+	_ {{.Type}} = (*loggingMiddleware)(nil)
+	_ {{.Type}} = (*metricsMiddleware)(nil)
+	_ {{.Type}} = (*tracingMiddleware)(nil)
+)
+{{range .Methods}}
+// {{.Name}} logs the call of {{.Name}}.
+func (m *loggingMiddleware) {{.Name}}({{.Params}}) ({{.Results}}) {
+	defer func(begin time.Time) { m.logCall({{.Ctx}}, "{{.Name}}", begin, err{{.LogArgs}}) }(time.Now())
+	return m.next.{{.Name}}({{.Args}})
+}
+{{end}}
+{{- range .Methods}}
+// {{.Name}} records the metrics of {{.Name}}.
+func (m *metricsMiddleware) {{.Name}}({{.Params}}) ({{.Results}}) {
+	defer func(begin time.Time) { m.observeCall("{{.Name}}", begin, err) }(time.Now())
+	return m.next.{{.Name}}({{.Args}})
+}
+{{end}}
+{{- $pkg := .TraceName}}
+{{- range .Methods}}
+// {{.Name}} traces the call of {{.Name}}.
+func (m *tracingMiddleware) {{.Name}}({{.Params}}) ({{.Results}}) {
+	{{.Ctx}}, end := m.tracer.Start({{.Ctx}}, "{{$pkg}}.{{.Name}}")
+	defer func() { end(err) }()
+	return m.next.{{.Name}}({{.Args}})
+}
+{{end}}`))
@@ -0,0 +1,78 @@
+// Package hdkey implements the hierarchical deterministic derivation of ed25519 keys (SLIP-0010),
+// the scheme the Solana wallets use for the keys derived from a mnemonic seed.
+// Only the hardened derivation is defined for ed25519, so every index of the path is hardened.
+package hdkey
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+)
+
+// HardenedOffset is added to every index of the derivation path.
+const HardenedOffset uint32 = 0x80000000
+
+// MinSeedSize is the minimum size of the master seed, as required by BIP-32.
+const MinSeedSize = 16
+
+// ErrInvalidSeed is returned if the master seed is too short.
+var ErrInvalidSeed = errors.New("hd seed must be at least 16 bytes long")
+
+// Key is an extended private key: the ed25519 seed and the chain code.
+type Key struct {
+	key       [32]byte
+	chainCode [32]byte
+}
+
+// NewMaster returns the master key of the seed.
+func NewMaster(seed []byte) (*Key, error) {
+	if len(seed) < MinSeedSize {
+		return nil, ErrInvalidSeed
+	}
+	return split(hmacSHA512([]byte("ed25519 seed"), seed)), nil
+}
+
+// Derive returns the child key of the path. The indices below HardenedOffset are hardened,
+// so Derive(0, 1) is m/0'/1'.
+func (k *Key) Derive(path ...uint32) *Key {
+	child := k
+	for _, index := range path {
+		data := make([]byte, 0, 37)
+		data = append(data, 0)
+		data = append(data, child.key[:]...)
+		data = binary.BigEndian.AppendUint32(data, index|HardenedOffset)
+		child = split(hmacSHA512(child.chainCode[:], data))
+	}
+	return child
+}
+
+// PrivateKey returns the ed25519 private key.
+func (k *Key) PrivateKey() ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(k.key[:])
+}
+
+// PublicKey returns the ed25519 public key.
+func (k *Key) PublicKey() ed25519.PublicKey {
+	return k.PrivateKey().Public().(ed25519.PublicKey)
+}
+
+// ChainCode returns the chain code of the key.
+func (k *Key) ChainCode() []byte {
+	return k.chainCode[:]
+}
+
+// split returns the key of the left half of the HMAC and the chain code of the right one.
+func split(sum []byte) *Key {
+	k := &Key{}
+	copy(k.key[:], sum[:32])
+	copy(k.chainCode[:], sum[32:])
+	return k
+}
+
+func hmacSHA512(key, data []byte) []byte {
+	h := hmac.New(sha512.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
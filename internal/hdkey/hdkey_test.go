@@ -0,0 +1,50 @@
+package hdkey_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/internal/hdkey"
+	"github.com/stretchr/testify/require"
+)
+
+// SLIP-0010 test vector 1 for ed25519
+func TestDerive(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	master, err := hdkey.NewMaster(seed)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path      []uint32
+		chainCode string
+		private   string
+		public    string
+	}{
+		{
+			path:      nil,
+			chainCode: "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			private:   "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7",
+			public:    "a4b2856bfec510abab89753fac1ac0e1112364e7d250545963f135f2a33188ed",
+		},
+		{
+			path:      []uint32{0},
+			chainCode: "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+			private:   "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3",
+			public:    "8c8a13df77a28f3445213a0f432fde644acaa215fc72dcdf300d5efaa85d350c",
+		},
+	}
+	for _, tt := range tests {
+		k := master.Derive(tt.path...)
+		require.Equal(t, tt.chainCode, hex.EncodeToString(k.ChainCode()))
+		require.Equal(t, tt.private, hex.EncodeToString(k.PrivateKey().Seed()))
+		require.Equal(t, tt.public, hex.EncodeToString(k.PublicKey()))
+	}
+
+	// hardened and non-hardened indices are the same path
+	require.Equal(t, master.Derive(1, 2).PublicKey(), master.Derive(1|hdkey.HardenedOffset, 2).PublicKey())
+}
+
+func TestNewMasterShortSeed(t *testing.T) {
+	_, err := hdkey.NewMaster([]byte("short"))
+	require.ErrorIs(t, err, hdkey.ErrInvalidSeed)
+}
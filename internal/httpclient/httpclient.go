@@ -0,0 +1,116 @@
+// Package httpclient is the factory of the outbound HTTP clients.
+// Every client built by New shares the same behavior: the request timeout, the connection pool,
+// the retries of the idempotent requests, the metrics and the propagation of the request ID,
+// so the integrations don't construct their own bare http.Client with divergent settings.
+package httpclient
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Default settings of the clients.
+const (
+	DefaultTimeout             = 30 * time.Second
+	DefaultDialTimeout         = 5 * time.Second
+	DefaultTLSHandshakeTimeout = 5 * time.Second
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultMaxIdleConns        = 100
+	DefaultMaxIdleConnsPerHost = 10
+	DefaultRetryWaitMin        = 100 * time.Millisecond
+	DefaultRetryWaitMax        = 2 * time.Second
+)
+
+// RequestIDHeader is the header with the ID of the incoming request which caused the outbound one.
+const RequestIDHeader = "X-Request-ID"
+
+type (
+	// Option configures the client.
+	Option func(*config)
+
+	config struct {
+		timeout       time.Duration
+		transport     *http.Transport
+		maxRetries    int
+		retryWaitMin  time.Duration
+		retryWaitMax  time.Duration
+		checkRedirect func(req *http.Request, via []*http.Request) error
+	}
+)
+
+// WithTimeout sets the timeout of the whole request, including the retries.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetries enables the retries of the idempotent requests which failed with a network error
+// or with a 429, 502, 503 or 504 status code. The wait between the attempts grows exponentially
+// from min to max, unless the server sets the Retry-After header.
+func WithRetries(max int, min, maxWait time.Duration) Option {
+	return func(c *config) {
+		c.maxRetries = max
+		c.retryWaitMin = min
+		c.retryWaitMax = maxWait
+	}
+}
+
+// WithTransport sets the base transport, e.g. with the custom dialer or proxy.
+// The transport is used as is, so the pool settings of the factory aren't applied to it.
+func WithTransport(transport *http.Transport) Option {
+	return func(c *config) {
+		c.transport = transport
+	}
+}
+
+// WithCheckRedirect sets the redirect policy of the client.
+func WithCheckRedirect(fn func(req *http.Request, via []*http.Request) error) Option {
+	return func(c *config) {
+		c.checkRedirect = fn
+	}
+}
+
+// New creates the HTTP client. The name identifies the client in the metrics, e.g. "jupiter".
+func New(name string, opts ...Option) *http.Client {
+	c := &config{
+		timeout:      DefaultTimeout,
+		retryWaitMin: DefaultRetryWaitMin,
+		retryWaitMax: DefaultRetryWaitMax,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.transport == nil {
+		c.transport = NewTransport()
+	}
+
+	return &http.Client{
+		Timeout: c.timeout,
+		Transport: &roundTripper{
+			name:         name,
+			next:         c.transport,
+			maxRetries:   c.maxRetries,
+			retryWaitMin: c.retryWaitMin,
+			retryWaitMax: c.retryWaitMax,
+		},
+		CheckRedirect: c.checkRedirect,
+	}
+}
+
+// NewTransport returns the pooled transport with the default settings.
+// It's the base of the clients which need to customize the transport before passing it to WithTransport.
+func NewTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   DefaultDialTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = DefaultTLSHandshakeTimeout
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	transport.MaxIdleConns = DefaultMaxIdleConns
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+
+	return transport
+}
@@ -0,0 +1,76 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpclient"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := httpclient.New("test", httpclient.WithRetries(2, time.Millisecond, 10*time.Millisecond))
+
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.EqualValues(t, 3, atomic.LoadInt32(&calls))
+
+	// The non-idempotent requests aren't retried.
+	atomic.StoreInt32(&calls, 0)
+	resp, err = client.Post(srv.URL, "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestNoRetriesByDefault(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	resp, err := httpclient.New("test").Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	require.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestRequestIDPropagation(t *testing.T) {
+	ids := make(chan string, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids <- r.Header.Get(httpclient.RequestIDHeader)
+	}))
+	defer srv.Close()
+
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	resp, err := httpclient.New("test").Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, "req-1", <-ids)
+	require.Empty(t, req.Header.Get(httpclient.RequestIDHeader), "the request must not be modified")
+}
@@ -0,0 +1,38 @@
+package httpclient
+
+import "github.com/easypmnt/checkout-api/internal/metrics"
+
+// Request phases traced by the clients.
+const (
+	phaseDNS       = "dns"
+	phaseConnect   = "connect"
+	phaseTLS       = "tls"
+	phaseFirstByte = "first_byte"
+)
+
+// Outbound request metrics, labeled with the name of the client and the host of the request.
+// The code label is the status code of the response, or "error" if no response was received.
+var (
+	requestsTotal = metrics.NewCounterVec(
+		"http_client_requests_total",
+		"Total number of outbound HTTP request attempts.",
+		"client", "host", "method", "code",
+	)
+	requestDuration = metrics.NewHistogramVec(
+		"http_client_request_duration_seconds",
+		"Duration of outbound HTTP request attempts in seconds.",
+		nil,
+		"client", "host",
+	)
+	phaseDuration = metrics.NewHistogramVec(
+		"http_client_phase_duration_seconds",
+		"Duration of the outbound HTTP request phases (dns, connect, tls, first_byte) in seconds.",
+		nil,
+		"client", "phase",
+	)
+	retriesTotal = metrics.NewCounterVec(
+		"http_client_retries_total",
+		"Total number of outbound HTTP request retries.",
+		"client", "host",
+	)
+)
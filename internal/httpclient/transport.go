@@ -0,0 +1,167 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// roundTripper instruments and retries the requests of the client.
+type roundTripper struct {
+	name         string
+	next         http.RoundTripper
+	maxRetries   int
+	retryWaitMin time.Duration
+	retryWaitMax time.Duration
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The request must not be modified, so the headers are set on the copy.
+	req = req.Clone(req.Context())
+	if req.Header.Get(RequestIDHeader) == "" {
+		if id := middleware.GetReqID(req.Context()); id != "" {
+			req.Header.Set(RequestIDHeader, id)
+		}
+	}
+
+	host := req.URL.Host
+	for attempt := 0; ; attempt++ {
+		resp, err := t.attempt(req, host)
+		if attempt >= t.maxRetries || !retryable(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			// Drain the body to reuse the connection.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+			resp.Body.Close()
+		}
+		if req.Body != nil && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		retriesTotal.WithLabelValues(t.name, host).Inc()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends the request once and records its metrics.
+func (t *roundTripper) attempt(req *http.Request, host string) (*http.Response, error) {
+	start := time.Now()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), t.trace(start)))
+
+	resp, err := t.next.RoundTrip(req)
+
+	code := "error"
+	if err == nil {
+		code = strconv.Itoa(resp.StatusCode)
+	}
+	requestsTotal.WithLabelValues(t.name, host, req.Method, code).Inc()
+	requestDuration.WithLabelValues(t.name, host).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
+
+// trace returns the client trace which records the duration of the request phases.
+// The connections may be dialed concurrently, e.g. to the IPv4 and IPv6 addresses, so the
+// start times are guarded by the mutex and only the first dial is recorded.
+func (t *roundTripper) trace(start time.Time) *httptrace.ClientTrace {
+	var (
+		mu                               sync.Mutex
+		dnsStart, connectStart, tlsStart time.Time
+	)
+	mark := func(ts *time.Time) {
+		mu.Lock()
+		defer mu.Unlock()
+		if ts.IsZero() {
+			*ts = time.Now()
+		}
+	}
+	observe := func(phase string, ts *time.Time) {
+		mu.Lock()
+		since := *ts
+		mu.Unlock()
+		if !since.IsZero() {
+			phaseDuration.WithLabelValues(t.name, phase).Observe(time.Since(since).Seconds())
+		}
+	}
+
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { mark(&dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { observe(phaseDNS, &dnsStart) },
+		ConnectStart:         func(_, _ string) { mark(&connectStart) },
+		ConnectDone:          func(_, _ string, _ error) { observe(phaseConnect, &connectStart) },
+		TLSHandshakeStart:    func() { mark(&tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { observe(phaseTLS, &tlsStart) },
+		GotFirstResponseByte: func() { observe(phaseFirstByte, &start) },
+	}
+}
+
+// backoff returns the wait before the next attempt: the Retry-After of the response
+// if it's set, otherwise the exponential backoff, both capped at the max wait.
+func (t *roundTripper) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s >= 0 {
+			return minDuration(time.Duration(s)*time.Second, t.retryWaitMax)
+		}
+	}
+
+	wait := t.retryWaitMin << attempt
+	if wait <= 0 { // overflow
+		return t.retryWaitMax
+	}
+	return minDuration(wait, t.retryWaitMax)
+}
+
+// retryable reports whether the failed attempt may be retried.
+// Only the idempotent requests are retried, since the server may have processed the failed one.
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+	return b
+}
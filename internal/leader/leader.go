@@ -0,0 +1,160 @@
+// Package leader implements a Redis-based leader election, so only one of the API instances
+// runs the singleton tasks, e.g. the task scheduler, with automatic failover.
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// DefaultTTL is the default leadership lock TTL.
+// The leader renews the lock every TTL/3, so a crashed leader is replaced within the TTL.
+const DefaultTTL = 15 * time.Second
+
+var (
+	// renewScript extends the lock TTL only if the lock is still held by the instance.
+	renewScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	// releaseScript deletes the lock only if it's held by the instance.
+	releaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+)
+
+type (
+	// Elector campaigns for the leadership and runs the leader function while it holds it.
+	Elector struct {
+		client redis.UniversalClient
+		key    string
+		id     string
+		ttl    time.Duration
+		log    Logger
+		leader int32
+	}
+
+	// Logger is an interface that allows to log the leadership changes.
+	Logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
+
+// NewElector creates a new leader elector for the given lock key.
+// All instances which compete for the same leadership must use the same key.
+// DefaultTTL is used if ttl is zero.
+func NewElector(client redis.UniversalClient, key string, ttl time.Duration, log Logger) *Elector {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+
+	return &Elector{
+		client: client,
+		key:    key,
+		id:     uuid.New().String(),
+		ttl:    ttl,
+		log:    log,
+	}
+}
+
+// IsLeader returns true if the instance currently holds the leadership.
+func (e *Elector) IsLeader() bool {
+	return atomic.LoadInt32(&e.leader) == 1
+}
+
+// Run campaigns for the leadership until the context is cancelled.
+// Every time the instance becomes the leader, fn is called with a context
+// which is cancelled when the leadership is lost.
+// If fn returns nil, the leadership is released and the instance campaigns again,
+// so another instance may take over. If fn returns an error, Run returns it.
+func (e *Elector) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		acquired, err := e.client.SetNX(ctx, e.key, e.id, e.ttl).Result()
+		if err != nil && ctx.Err() == nil {
+			e.log.Errorf("leader: failed to acquire %s: %v", e.key, err)
+		}
+		if acquired {
+			if err := e.lead(ctx, fn); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead runs fn and renews the lock until fn returns or the leadership is lost.
+func (e *Elector) lead(ctx context.Context, fn func(ctx context.Context) error) error {
+	atomic.StoreInt32(&e.leader, 1)
+	defer atomic.StoreInt32(&e.leader, 0)
+	defer e.release()
+
+	e.log.Infof("leader: acquired %s", e.key)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- fn(leaderCtx) }()
+
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+	for {
+		select {
+		case err := <-done:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return fmt.Errorf("leader: %s: %w", e.key, err)
+			}
+			return nil
+		case <-ticker.C:
+			res, err := renewScript.Run(ctx, e.client, []string{e.key}, e.id, e.ttl.Milliseconds()).Int()
+			if err == nil && res == 1 {
+				renewed = time.Now()
+				continue
+			}
+			// keep the leadership on transient errors until the lock expires
+			if err != nil && time.Since(renewed) < e.ttl {
+				e.log.Errorf("leader: failed to renew %s: %v", e.key, err)
+				continue
+			}
+
+			e.log.Errorf("leader: lost %s", e.key)
+			cancel()
+			<-done
+			return nil
+		}
+	}
+}
+
+// release releases the lock, so another instance can take over immediately.
+func (e *Elector) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := releaseScript.Run(ctx, e.client, []string{e.key}, e.id).Err(); err != nil {
+		e.log.Errorf("leader: failed to release %s: %v", e.key, err)
+		return
+	}
+
+	e.log.Infof("leader: released %s", e.key)
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"sync"
+)
+
+// The request fields added to the log lines, see WithContext.
+const (
+	RequestIDKey = "request_id"
+	ClientIDKey  = "client_id"
+	PaymentIDKey = "payment_id"
+)
+
+type (
+	// contextFields are the log fields of the request. They're set by the middlewares and handlers
+	// down the chain, so the fields known only after the authorization are logged by RequestLogger too.
+	contextFields struct {
+		mu     sync.RWMutex
+		fields map[string]interface{}
+	}
+
+	contextFieldsKey struct{}
+)
+
+// ContextWithFields returns the context carrying the log fields of the request, see SetContextField.
+// The context is returned as is if it carries them already.
+func ContextWithFields(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(contextFieldsKey{}).(*contextFields); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, contextFieldsKey{}, &contextFields{fields: make(map[string]interface{})})
+}
+
+// SetContextField sets the log field of the request. It's a no-op if the context carries no fields,
+// i.e. it's not derived from the ContextWithFields one. The empty values are ignored.
+func SetContextField(ctx context.Context, key string, value interface{}) {
+	cf, ok := ctx.Value(contextFieldsKey{}).(*contextFields)
+	if !ok || value == nil || value == "" {
+		return
+	}
+
+	cf.mu.Lock()
+	defer cf.mu.Unlock()
+
+	cf.fields[key] = value
+}
+
+// ContextFields returns a copy of the log fields of the request, nil if there are none.
+func ContextFields(ctx context.Context) map[string]interface{} {
+	cf, ok := ctx.Value(contextFieldsKey{}).(*contextFields)
+	if !ok {
+		return nil
+	}
+
+	cf.mu.RLock()
+	defer cf.mu.RUnlock()
+
+	if len(cf.fields) == 0 {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(cf.fields))
+	for k, v := range cf.fields {
+		fields[k] = v
+	}
+	return fields
+}
+
+// WithContext returns the logger which adds the log fields of the request to every line,
+// e.g. the request, client and payment IDs.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	fields := ContextFields(ctx)
+	if fields == nil {
+		return l
+	}
+	return l.WithFields(fields)
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithContext(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(Config{Format: FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	// no fields without ContextWithFields
+	SetContextField(context.Background(), ClientIDKey, "cid")
+	require.Same(t, log, log.WithContext(context.Background()))
+
+	ctx := ContextWithFields(context.Background())
+	require.Equal(t, ctx, ContextWithFields(ctx))
+	SetContextField(ctx, RequestIDKey, "rid")
+	SetContextField(ctx, PaymentIDKey, "")
+
+	log.WithContext(ctx).Infof("logged")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "rid", line[RequestIDKey])
+	require.NotContains(t, line, PaymentIDKey)
+}
+
+func TestRequestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(Config{Format: FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID, RequestLogger(log, "/health"))
+	r.Group(func(r chi.Router) {
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				SetContextField(r.Context(), ClientIDKey, "cid")
+				next.ServeHTTP(w, r)
+			})
+		})
+		r.Get("/payments/pid/{payment_id}", func(w http.ResponseWriter, r *http.Request) {
+			log.WithContext(r.Context()).Infof("handled")
+			w.WriteHeader(http.StatusAccepted)
+		})
+	})
+	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/payments/pid/123", nil))
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.NotEmpty(t, rec.Header().Get(RequestIDHeader))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2) // the probe is logged at the debug level
+
+	var handled, access map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &handled))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &access))
+
+	require.Equal(t, rec.Header().Get(RequestIDHeader), handled[RequestIDKey])
+	require.Equal(t, "cid", handled[ClientIDKey])
+
+	require.Equal(t, "GET /payments/pid/123 202", access["msg"])
+	require.Equal(t, handled[RequestIDKey], access[RequestIDKey])
+	require.Equal(t, "cid", access[ClientIDKey])
+	require.Equal(t, "123", access[PaymentIDKey])
+	require.Equal(t, "/payments/pid/{payment_id}", access["route"])
+	require.Equal(t, float64(http.StatusAccepted), access["status"])
+}
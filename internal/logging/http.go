@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// RequestIDHeader is the response header with the request ID, so the clients report it along with the errors.
+const RequestIDHeader = "X-Request-Id"
+
+// RequestLogger attaches the log fields to the request context and logs every request once it's handled.
+// It must be applied after middleware.RequestID, so the request ID is known. The client ID is added by
+// the authorization middleware, see SetContextField, and the payment ID is taken from the {payment_id}
+// URL parameter, unless the handler sets it. The requests to the skipped paths, e.g. the probes,
+// are logged at the debug level.
+func RequestLogger(log *Logger, skipPaths ...string) func(next http.Handler) http.Handler {
+	skip := make(map[string]bool, len(skipPaths))
+	for _, p := range skipPaths {
+		skip[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := ContextWithFields(r.Context())
+			reqID := middleware.GetReqID(ctx)
+			SetContextField(ctx, RequestIDKey, reqID)
+			if reqID != "" {
+				w.Header().Set(RequestIDHeader, reqID)
+			}
+
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			route := r.URL.Path
+			if rctx := chi.RouteContext(ctx); rctx != nil {
+				if pattern := rctx.RoutePattern(); pattern != "" {
+					route = pattern
+				}
+				if _, ok := ContextFields(ctx)[PaymentIDKey]; !ok {
+					SetContextField(ctx, PaymentIDKey, rctx.URLParam("payment_id"))
+				}
+			}
+
+			l := log.WithContext(ctx).WithFields(map[string]interface{}{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"route":       route,
+				"status":      status,
+				"bytes":       ww.BytesWritten(),
+				"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+				"remote_ip":   r.RemoteAddr,
+			})
+			switch {
+			case status >= http.StatusInternalServerError:
+				l.Errorf("%s %s %d", r.Method, r.URL.Path, status)
+			case skip[r.URL.Path]:
+				l.Debugf("%s %s %d", r.Method, r.URL.Path, status)
+			default:
+				l.Infof("%s %s %d", r.Method, r.URL.Path, status)
+			}
+		})
+	}
+}
@@ -0,0 +1,286 @@
+// Package logging is the structured logging facade of the API.
+// Every module logs through a Logger returned by Logger.Module, so the output format,
+// the levels, the sampling of the high-volume debug lines and the redaction of the secrets
+// are configured in one place. The request handlers log through Logger.WithContext,
+// so their lines carry the request, client and payment IDs, see RequestLogger.
+// The Logger implements the logger interfaces of the packages, the go-kit log.Logger
+// and the asynq.Logger, so it's passed to them as is.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Output formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ModuleKey is the field with the name of the module which logged the line.
+const ModuleKey = "module"
+
+type (
+	// Config is the logging configuration.
+	Config struct {
+		Level        string            // debug, info, warn, error; info if empty
+		Format       string            // text, json; text if empty
+		ModuleLevels map[string]string // levels of the modules overriding Level, e.g. websocketrpc: warn
+		Fields       map[string]interface{}
+		Output       io.Writer // os.Stderr if nil
+		Sampling     SamplingConfig
+		RedactFields []string // keys of the values redacted from the lines, DefaultRedactFields if nil
+	}
+
+	// Logger is a structured logger of a module.
+	Logger struct {
+		entry   *logrus.Entry
+		sampler *sampler
+		root    *root
+	}
+
+	// root keeps the configuration and the loggers of the modules.
+	root struct {
+		conf      Config
+		level     logrus.Level
+		formatter logrus.Formatter
+		output    io.Writer
+
+		mu      sync.Mutex
+		modules map[string]*Logger
+	}
+)
+
+// New creates the root logger.
+func New(conf Config) (*Logger, error) {
+	r := &root{
+		conf:    conf,
+		level:   logrus.InfoLevel,
+		output:  conf.Output,
+		modules: make(map[string]*Logger),
+	}
+	if r.output == nil {
+		r.output = os.Stderr
+	}
+
+	if conf.Level != "" {
+		level, err := logrus.ParseLevel(conf.Level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid log level: %w", err)
+		}
+		r.level = level
+	}
+	for module, level := range conf.ModuleLevels {
+		if _, err := logrus.ParseLevel(level); err != nil {
+			return nil, fmt.Errorf("invalid log level of module %s: %w", module, err)
+		}
+	}
+
+	switch strings.ToLower(conf.Format) {
+	case "", FormatText:
+		r.formatter = &logrus.TextFormatter{FullTimestamp: true, TimestampFormat: time.RFC3339Nano}
+	case FormatJSON:
+		r.formatter = &logrus.JSONFormatter{TimestampFormat: time.RFC3339Nano}
+	default:
+		return nil, fmt.Errorf("invalid log format: %s", conf.Format)
+	}
+
+	redactFields := conf.RedactFields
+	if redactFields == nil {
+		redactFields = DefaultRedactFields
+	}
+	r.formatter = &redactingFormatter{next: r.formatter, redactor: newRedactor(redactFields)}
+
+	return r.module(""), nil
+}
+
+// Default returns the logger with the default config, e.g. the fallback of the optional loggers.
+func Default() *Logger {
+	l, _ := New(Config{}) // the zero config is valid
+	return l
+}
+
+// Module returns the logger of the module with its own level and sampling.
+// The loggers are created once, so the same module shares the sampling counters.
+func (l *Logger) Module(name string) *Logger {
+	return l.root.module(name)
+}
+
+// module returns the logger of the module, creating it on the first call.
+func (r *root) module(name string) *Logger {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.modules[name]; ok {
+		return l
+	}
+
+	level := r.level
+	if lvl, ok := r.conf.ModuleLevels[name]; ok {
+		level, _ = logrus.ParseLevel(lvl) // validated by New
+	}
+
+	fields := make(logrus.Fields, len(r.conf.Fields)+1)
+	for k, v := range r.conf.Fields {
+		fields[k] = v
+	}
+	if name != "" {
+		fields[ModuleKey] = name
+	}
+
+	l := &Logger{
+		entry: logrus.NewEntry(&logrus.Logger{
+			Out:       r.output,
+			Formatter: r.formatter,
+			Hooks:     make(logrus.LevelHooks),
+			Level:     level,
+			ExitFunc:  os.Exit,
+		}).WithFields(fields),
+		sampler: newSampler(r.conf.Sampling),
+		root:    r,
+	}
+	r.modules[name] = l
+
+	return l
+}
+
+// WithField returns the logger which adds the field to every line.
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	return l.with(l.entry.WithField(key, value))
+}
+
+// WithFields returns the logger which adds the fields to every line.
+func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
+	return l.with(l.entry.WithFields(fields))
+}
+
+// WithError returns the logger which adds the error field to every line.
+func (l *Logger) WithError(err error) *Logger {
+	return l.with(l.entry.WithError(err))
+}
+
+// with returns a copy of the logger with the given entry.
+func (l *Logger) with(entry *logrus.Entry) *Logger {
+	return &Logger{entry: entry, sampler: l.sampler, root: l.root}
+}
+
+// DebugEnabled reports whether the debug lines of the module are logged,
+// so the expensive arguments are only computed if they're needed.
+func (l *Logger) DebugEnabled() bool {
+	return l.entry.Logger.IsLevelEnabled(logrus.DebugLevel)
+}
+
+// Debugf logs the debug line, subject to the sampling of the module.
+// The lines are sampled by the format, so the lines with different arguments are counted together.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	if l.DebugEnabled() && l.sampler.allow(format) {
+		l.entry.Debugf(format, args...)
+	}
+}
+
+// Infof logs the info line.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.entry.Infof(format, args...)
+}
+
+// Warnf logs the warning line.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.entry.Warnf(format, args...)
+}
+
+// Errorf logs the error line.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.entry.Errorf(format, args...)
+}
+
+// Fatalf logs the fatal line and exits the process.
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.entry.Fatalf(format, args...)
+}
+
+// Debug logs the debug line, subject to the sampling of the module.
+func (l *Logger) Debug(args ...interface{}) {
+	if l.DebugEnabled() && l.sampler.allow(fmt.Sprint(args...)) {
+		l.entry.Debug(args...)
+	}
+}
+
+// Info logs the info line.
+func (l *Logger) Info(args ...interface{}) {
+	l.entry.Info(args...)
+}
+
+// Warn logs the warning line.
+func (l *Logger) Warn(args ...interface{}) {
+	l.entry.Warn(args...)
+}
+
+// Error logs the error line.
+func (l *Logger) Error(args ...interface{}) {
+	l.entry.Error(args...)
+}
+
+// Fatal logs the fatal line and exits the process.
+func (l *Logger) Fatal(args ...interface{}) {
+	l.entry.Fatal(args...)
+}
+
+// Log implements the go-kit log.Logger: the key-value pairs are logged as the fields.
+// The "msg" value is the message, the "level" value is the level, info by default,
+// and the lines with the "err" key are logged as errors.
+func (l *Logger) Log(keyvals ...interface{}) error {
+	var (
+		level  = logrus.InfoLevel
+		msg    string
+		fields = make(logrus.Fields, len(keyvals)/2)
+	)
+
+	for i := 0; i < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+
+		switch key {
+		case "level":
+			if lvl, err := logrus.ParseLevel(fmt.Sprint(value)); err == nil {
+				level = lvl
+			}
+		case "msg", "message":
+			msg = fmt.Sprint(value)
+		case "err", logrus.ErrorKey:
+			if err, ok := value.(error); ok {
+				value = err.Error()
+			}
+			fields[logrus.ErrorKey] = value
+			if level > logrus.ErrorLevel {
+				level = logrus.ErrorLevel
+			}
+		default:
+			fields[key] = value
+		}
+	}
+
+	if level == logrus.DebugLevel {
+		if !l.DebugEnabled() || !l.sampler.allow(msg) {
+			return nil
+		}
+	}
+	l.entry.WithFields(fields).Log(level, msg)
+
+	return nil
+}
+
+// Writer returns the writer which logs every line as the info line,
+// e.g. to redirect the standard library logger. The writer must be closed when it's not used anymore.
+func (l *Logger) Writer() *io.PipeWriter {
+	return l.entry.WriterLevel(logrus.InfoLevel)
+}
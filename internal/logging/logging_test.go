@@ -0,0 +1,93 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(Config{
+		Level:        "info",
+		Format:       FormatJSON,
+		ModuleLevels: map[string]string{"websocketrpc": "warn", "payments": "debug"},
+		Fields:       map[string]interface{}{"app": "test"},
+		Output:       &buf,
+	})
+	require.NoError(t, err)
+
+	log.Module("websocketrpc").Infof("skipped")
+	log.Module("websocketrpc").Warnf("logged %d", 1)
+	log.Module("payments").Debugf("logged %d", 2)
+	log.Module("events").Debugf("skipped")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	require.Equal(t, "logged 1", line["msg"])
+	require.Equal(t, "warning", line["level"])
+	require.Equal(t, "websocketrpc", line[ModuleKey])
+	require.Equal(t, "test", line["app"])
+
+	// the same module logger is returned
+	require.Same(t, log.Module("payments"), log.Module("payments"))
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(Config{Level: "loud"})
+	require.Error(t, err)
+
+	_, err = New(Config{ModuleLevels: map[string]string{"payments": "loud"}})
+	require.Error(t, err)
+
+	_, err = New(Config{Format: "xml"})
+	require.Error(t, err)
+}
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(Config{Format: FormatJSON, Output: &buf})
+	require.NoError(t, err)
+
+	require.NoError(t, log.Module("http").Log("transport", "HTTP", "err", errors.New("boom")))
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "error", line["level"])
+	require.Equal(t, "boom", line["error"])
+	require.Equal(t, "HTTP", line["transport"])
+	require.Equal(t, "http", line[ModuleKey])
+}
+
+func TestSampler(t *testing.T) {
+	now := time.Now()
+	s := newSampler(SamplingConfig{Initial: 2, Thereafter: 3, Tick: time.Second})
+	s.now = func() time.Time { return now }
+
+	var allowed []int
+	for i := 1; i <= 8; i++ {
+		if s.allow("message") {
+			allowed = append(allowed, i)
+		}
+	}
+	require.Equal(t, []int{1, 2, 5, 8}, allowed)
+
+	// other messages are counted separately
+	require.True(t, s.allow("other"))
+
+	// the next tick
+	now = now.Add(time.Second)
+	require.True(t, s.allow("message"))
+
+	// the sampling is disabled
+	require.Nil(t, newSampler(SamplingConfig{Initial: 2}))
+	require.True(t, (*sampler)(nil).allow("message"))
+}
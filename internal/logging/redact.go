@@ -0,0 +1,137 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedValue replaces the sensitive values in the log lines.
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactFields are the keys of the values which are never logged as is:
+// the wallet private keys, the OAuth client secrets and tokens, and the webhook signing secrets.
+var DefaultRedactFields = []string{
+	"private_key",
+	"secret_key",
+	"seed",
+	"mnemonic",
+	"secret",
+	"client_secret",
+	"access_token",
+	"refresh_token",
+	"authorization",
+	"password",
+	"api_key",
+	"webhook_secret",
+	"signing_secret",
+}
+
+// redactor redacts the values of the sensitive keys in the log fields and messages.
+// The keys are matched case-insensitively as the field names, the JSON object keys,
+// and the key=value pairs, e.g. of the form-encoded OAuth token requests.
+type redactor struct {
+	keys   map[string]bool
+	jsonRe *regexp.Regexp
+	kvRe   *regexp.Regexp
+}
+
+func newRedactor(keys []string) *redactor {
+	r := &redactor{keys: make(map[string]bool, len(keys))}
+	quoted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		k = strings.ToLower(strings.TrimSpace(k))
+		if k == "" || r.keys[k] {
+			continue
+		}
+		r.keys[k] = true
+		quoted = append(quoted, regexp.QuoteMeta(k))
+	}
+	if len(quoted) == 0 {
+		return r
+	}
+
+	alt := strings.Join(quoted, "|")
+	r.jsonRe = regexp.MustCompile(`(?i)("(?:` + alt + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	r.kvRe = regexp.MustCompile(`(?i)(\b(?:` + alt + `)=)[^&\s,;"]+`)
+
+	return r
+}
+
+// text redacts the JSON values and the key=value pairs of the sensitive keys in the string.
+func (r *redactor) text(s string) string {
+	if r.jsonRe == nil {
+		return s
+	}
+	s = r.jsonRe.ReplaceAllString(s, `${1}"`+RedactedValue+`"`)
+	return r.kvRe.ReplaceAllString(s, "${1}"+RedactedValue)
+}
+
+// value returns the copy of the field value with the sensitive data redacted.
+// The structs are logged as their JSON representation, so their sensitive fields are redacted as well.
+func (r *redactor) value(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil, bool, int, int32, int64, uint, uint32, uint64, float32, float64:
+		return v
+	case string:
+		return r.text(val)
+	case []byte:
+		return r.text(string(val))
+	case error:
+		return r.text(val.Error())
+	case fmt.Stringer:
+		return r.text(val.String())
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(val))
+		for k, fv := range val {
+			if r.keys[strings.ToLower(k)] {
+				result[k] = RedactedValue
+				continue
+			}
+			result[k] = r.value(fv)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(val))
+		for i, item := range val {
+			result[i] = r.value(item)
+		}
+		return result
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return r.text(fmt.Sprint(v))
+	}
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return r.text(string(b))
+	}
+	return r.value(data)
+}
+
+// redactingFormatter redacts the entry before it's formatted.
+type redactingFormatter struct {
+	next     logrus.Formatter
+	redactor *redactor
+}
+
+// Format implements logrus.Formatter. The entry is a copy made by logrus for the line,
+// so its data is replaced, while the values shared with the caller are not modified.
+func (f *redactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		if f.redactor.keys[strings.ToLower(k)] {
+			data[k] = RedactedValue
+			continue
+		}
+		data[k] = f.redactor.value(v)
+	}
+	entry.Data = data
+	entry.Message = f.redactor.text(entry.Message)
+
+	return f.next.Format(entry)
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(Config{Format: FormatJSON, Output: &buf, RedactFields: append(DefaultRedactFields, "customer_email")})
+	require.NoError(t, err)
+
+	payload := map[string]interface{}{
+		"payment_id": "pid",
+		"wallet":     map[string]interface{}{"Private_Key": "5Kb8kLf9zgWQnogidDA76Mz"},
+	}
+	log.Module("webhook").
+		WithField("webhook_secret", "whsec_123").
+		WithField("payload", payload).
+		WithField("request", struct {
+			ClientID     string `json:"client_id"`
+			ClientSecret string `json:"client_secret"`
+		}{"cid", "csecret"}).
+		WithError(errors.New(`token request failed: grant_type=client_credentials&client_secret=csecret`)).
+		Infof(`webhook %s: {"secret": "whsec_123", "customer_email":"a@b.c"}`, "sent")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, `webhook sent: {"secret": "[REDACTED]", "customer_email":"[REDACTED]"}`, line["msg"])
+	require.Equal(t, RedactedValue, line["webhook_secret"])
+	require.Equal(t, map[string]interface{}{
+		"payment_id": "pid",
+		"wallet":     map[string]interface{}{"Private_Key": RedactedValue},
+	}, line["payload"])
+	require.Equal(t, map[string]interface{}{"client_id": "cid", "client_secret": RedactedValue}, line["request"])
+	require.Equal(t, "token request failed: grant_type=client_credentials&client_secret=[REDACTED]", line["error"])
+
+	// the values shared with the caller are not modified
+	require.Equal(t, "5Kb8kLf9zgWQnogidDA76Mz", payload["wallet"].(map[string]interface{})["Private_Key"])
+}
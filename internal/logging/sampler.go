@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// SamplingConfig is the sampling of the debug lines.
+	// In every Tick the first Initial lines with the same message are logged,
+	// and then every Thereafter-th line. The sampling is disabled if Thereafter is 0.
+	SamplingConfig struct {
+		Initial    int
+		Thereafter int
+		Tick       time.Duration
+	}
+
+	// sampler counts the lines per message in the current tick.
+	sampler struct {
+		conf SamplingConfig
+		now  func() time.Time
+
+		mu        sync.Mutex
+		tickStart time.Time
+		counts    map[string]int
+	}
+)
+
+// newSampler creates the sampler, or returns nil if the sampling is disabled.
+func newSampler(conf SamplingConfig) *sampler {
+	if conf.Thereafter <= 0 {
+		return nil
+	}
+	if conf.Tick <= 0 {
+		conf.Tick = time.Second
+	}
+	if conf.Initial < 0 {
+		conf.Initial = 0
+	}
+
+	return &sampler{
+		conf:   conf,
+		now:    time.Now,
+		counts: make(map[string]int),
+	}
+}
+
+// allow reports whether the line with the message is logged.
+// A nil sampler allows every line.
+func (s *sampler) allow(msg string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := s.now(); now.Sub(s.tickStart) >= s.conf.Tick {
+		s.tickStart = now
+		s.counts = make(map[string]int, len(s.counts))
+	}
+
+	s.counts[msg]++
+	n := s.counts[msg]
+	if n <= s.conf.Initial {
+		return true
+	}
+
+	return (n-s.conf.Initial)%s.conf.Thereafter == 0
+}
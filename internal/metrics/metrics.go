@@ -0,0 +1,214 @@
+// Package metrics is a minimal, dependency-free implementation of Prometheus metrics
+// (counters, gauges and histograms with labels) exposed in the text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefBuckets are the default histogram buckets, in seconds.
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type (
+	// Counter is a metric that can only increase.
+	Counter struct {
+		bits uint64
+	}
+
+	// Gauge is a metric that can go up and down.
+	Gauge struct {
+		bits uint64
+	}
+
+	// Histogram counts observations in configurable buckets.
+	Histogram struct {
+		mu      sync.Mutex
+		buckets []float64
+		counts  []uint64
+		count   uint64
+		sum     float64
+	}
+)
+
+// Inc increments the counter by 1.
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+// Add adds the given value to the counter. Negative values are ignored.
+func (c *Counter) Add(v float64) {
+	if v < 0 {
+		return
+	}
+	addFloat(&c.bits, v)
+}
+
+// Value returns the current value of the counter.
+func (c *Counter) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&c.bits))
+}
+
+// Set sets the gauge to the given value.
+func (g *Gauge) Set(v float64) {
+	atomic.StoreUint64(&g.bits, math.Float64bits(v))
+}
+
+// Inc increments the gauge by 1.
+func (g *Gauge) Inc() {
+	addFloat(&g.bits, 1)
+}
+
+// Dec decrements the gauge by 1.
+func (g *Gauge) Dec() {
+	addFloat(&g.bits, -1)
+}
+
+// Add adds the given value to the gauge.
+func (g *Gauge) Add(v float64) {
+	addFloat(&g.bits, v)
+}
+
+// Value returns the current value of the gauge.
+func (g *Gauge) Value() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&g.bits))
+}
+
+// Observe adds a single observation to the histogram.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, b := range h.buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// snapshot returns the cumulative bucket counts, total count and sum.
+func (h *Histogram) snapshot() ([]uint64, uint64, float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	counts := make([]uint64, len(h.counts))
+	copy(counts, h.counts)
+
+	return counts, h.count, h.sum
+}
+
+// addFloat atomically adds v to the float64 stored as bits.
+func addFloat(bits *uint64, v float64) {
+	for {
+		old := atomic.LoadUint64(bits)
+		n := math.Float64bits(math.Float64frombits(old) + v)
+		if atomic.CompareAndSwapUint64(bits, old, n) {
+			return
+		}
+	}
+}
+
+// vec is a collection of metrics of the same type partitioned by label values.
+type vec[T any] struct {
+	name    string
+	help    string
+	typ     string
+	labels  []string
+	newItem func() *T
+
+	mu    sync.RWMutex
+	items map[string]*T
+	order []string
+}
+
+func newVec[T any](name, help, typ string, labels []string, newItem func() *T) *vec[T] {
+	return &vec[T]{
+		name:    name,
+		help:    help,
+		typ:     typ,
+		labels:  labels,
+		newItem: newItem,
+		items:   make(map[string]*T),
+	}
+}
+
+// with returns the metric for the given label values, creating it if needed.
+// Panics if the number of values does not match the number of labels,
+// since it is always a programming error.
+func (v *vec[T]) with(values ...string) *T {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s: expected %d label values, got %d", v.name, len(v.labels), len(values)))
+	}
+	key := strings.Join(values, "\xff")
+
+	v.mu.RLock()
+	item, ok := v.items[key]
+	v.mu.RUnlock()
+	if ok {
+		return item
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if item, ok := v.items[key]; ok {
+		return item
+	}
+	item = v.newItem()
+	v.items[key] = item
+	v.order = append(v.order, key)
+
+	return item
+}
+
+// each calls fn for every metric in the stable label values order.
+func (v *vec[T]) each(fn func(values []string, item *T)) {
+	v.mu.RLock()
+	keys := make([]string, len(v.order))
+	copy(keys, v.order)
+	v.mu.RUnlock()
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v.mu.RLock()
+		item := v.items[key]
+		v.mu.RUnlock()
+
+		var values []string
+		if len(v.labels) > 0 {
+			values = strings.Split(key, "\xff")
+		}
+		fn(values, item)
+	}
+}
+
+type (
+	// CounterVec is a collection of counters partitioned by label values.
+	CounterVec struct{ *vec[Counter] }
+
+	// GaugeVec is a collection of gauges partitioned by label values.
+	GaugeVec struct{ *vec[Gauge] }
+
+	// HistogramVec is a collection of histograms partitioned by label values.
+	HistogramVec struct{ *vec[Histogram] }
+)
+
+// WithLabelValues returns the counter for the given label values.
+func (v *CounterVec) WithLabelValues(values ...string) *Counter {
+	return v.with(values...)
+}
+
+// WithLabelValues returns the gauge for the given label values.
+func (v *GaugeVec) WithLabelValues(values ...string) *Gauge {
+	return v.with(values...)
+}
+
+// WithLabelValues returns the histogram for the given label values.
+func (v *HistogramVec) WithLabelValues(values ...string) *Histogram {
+	return v.with(values...)
+}
@@ -0,0 +1,75 @@
+package metrics_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/internal/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	r := metrics.NewRegistry()
+
+	counter := r.NewCounterVec("test_requests_total", "Total requests.", "code")
+	counter.WithLabelValues("200").Inc()
+	counter.WithLabelValues("200").Add(2)
+	counter.WithLabelValues("500").Inc()
+	counter.WithLabelValues("500").Add(-1) // ignored
+
+	gauge := r.NewGaugeVec("test_in_flight", "In-flight requests.")
+	gauge.WithLabelValues().Inc()
+	gauge.WithLabelValues().Inc()
+	gauge.WithLabelValues().Dec()
+
+	hist := r.NewHistogramVec("test_duration_seconds", "Request duration.", []float64{1, 0.1}, "path")
+	hist.WithLabelValues(`/a"b`).Observe(0.05)
+	hist.WithLabelValues(`/a"b`).Observe(0.5)
+	hist.WithLabelValues(`/a"b`).Observe(5)
+
+	var buf bytes.Buffer
+	require.NoError(t, r.Write(&buf))
+
+	expected := `# HELP test_duration_seconds Request duration.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{path="/a\"b",le="0.1"} 1
+test_duration_seconds_bucket{path="/a\"b",le="1"} 2
+test_duration_seconds_bucket{path="/a\"b",le="+Inf"} 3
+test_duration_seconds_sum{path="/a\"b"} 5.55
+test_duration_seconds_count{path="/a\"b"} 3
+# HELP test_in_flight In-flight requests.
+# TYPE test_in_flight gauge
+test_in_flight 1
+# HELP test_requests_total Total requests.
+# TYPE test_requests_total counter
+test_requests_total{code="200"} 3
+test_requests_total{code="500"} 1
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestRegistryDuplicateName(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.NewCounterVec("test_total", "")
+	require.Panics(t, func() { r.NewGaugeVec("test_total", "") })
+}
+
+func TestLabelValuesMismatch(t *testing.T) {
+	r := metrics.NewRegistry()
+	c := r.NewCounterVec("test_total", "", "a", "b")
+	require.Panics(t, func() { c.WithLabelValues("a") })
+}
+
+func TestHandler(t *testing.T) {
+	r := metrics.NewRegistry()
+	r.NewCounterVec("test_total", "Test.").WithLabelValues().Inc()
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, metrics.ContentType, rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Body.String(), "test_total 1\n")
+}
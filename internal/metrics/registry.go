@@ -0,0 +1,207 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type (
+	// Registry holds the registered metrics and exposes them.
+	Registry struct {
+		mu         sync.RWMutex
+		collectors map[string]collector
+	}
+
+	collector interface {
+		write(w *bufio.Writer)
+	}
+)
+
+// DefaultRegistry is the registry used by the package-level functions.
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// register adds the collector to the registry.
+// Panics if a metric with the same name is already registered.
+func (r *Registry) register(name string, c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.collectors[name]; ok {
+		panic(fmt.Sprintf("metrics: duplicate metric name: %s", name))
+	}
+	r.collectors[name] = c
+}
+
+// NewCounterVec registers a new counter vector.
+func (r *Registry) NewCounterVec(name, help string, labels ...string) *CounterVec {
+	v := &CounterVec{newVec(name, help, "counter", labels, func() *Counter { return &Counter{} })}
+	r.register(name, v)
+	return v
+}
+
+// NewGaugeVec registers a new gauge vector.
+func (r *Registry) NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	v := &GaugeVec{newVec(name, help, "gauge", labels, func() *Gauge { return &Gauge{} })}
+	r.register(name, v)
+	return v
+}
+
+// NewHistogramVec registers a new histogram vector.
+// DefBuckets are used if buckets are empty.
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	v := &HistogramVec{newVec(name, help, "histogram", labels, func() *Histogram {
+		return &Histogram{buckets: buckets, counts: make([]uint64, len(buckets))}
+	})}
+	r.register(name, v)
+	return v
+}
+
+// Write writes all metrics in the Prometheus text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		r.mu.RLock()
+		c := r.collectors[name]
+		r.mu.RUnlock()
+		c.write(bw)
+	}
+
+	return bw.Flush()
+}
+
+// Handler returns an http.Handler that exposes the registry metrics.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = r.Write(w)
+	})
+}
+
+// NewCounterVec registers a new counter vector in the default registry.
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return DefaultRegistry.NewCounterVec(name, help, labels...)
+}
+
+// NewGaugeVec registers a new gauge vector in the default registry.
+func NewGaugeVec(name, help string, labels ...string) *GaugeVec {
+	return DefaultRegistry.NewGaugeVec(name, help, labels...)
+}
+
+// NewHistogramVec registers a new histogram vector in the default registry.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return DefaultRegistry.NewHistogramVec(name, help, buckets, labels...)
+}
+
+// Handler returns an http.Handler that exposes the default registry metrics.
+func Handler() http.Handler {
+	return DefaultRegistry.Handler()
+}
+
+func (v *CounterVec) write(w *bufio.Writer) {
+	writeHeader(w, v.name, v.help, v.typ)
+	v.each(func(values []string, c *Counter) {
+		writeSample(w, v.name, v.labels, values, "", "", c.Value())
+	})
+}
+
+func (v *GaugeVec) write(w *bufio.Writer) {
+	writeHeader(w, v.name, v.help, v.typ)
+	v.each(func(values []string, g *Gauge) {
+		writeSample(w, v.name, v.labels, values, "", "", g.Value())
+	})
+}
+
+func (v *HistogramVec) write(w *bufio.Writer) {
+	writeHeader(w, v.name, v.help, v.typ)
+	v.each(func(values []string, h *Histogram) {
+		counts, count, sum := h.snapshot()
+		for i, b := range h.buckets {
+			writeSample(w, v.name+"_bucket", v.labels, values, "le", formatFloat(b), float64(counts[i]))
+		}
+		writeSample(w, v.name+"_bucket", v.labels, values, "le", "+Inf", float64(count))
+		writeSample(w, v.name+"_sum", v.labels, values, "", "", sum)
+		writeSample(w, v.name+"_count", v.labels, values, "", "", float64(count))
+	})
+}
+
+func writeHeader(w *bufio.Writer, name, help, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, escapeHelp(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, typ)
+}
+
+func writeSample(w *bufio.Writer, name string, labels, values []string, extraLabel, extraValue string, v float64) {
+	w.WriteString(name)
+	if len(labels) > 0 || extraLabel != "" {
+		w.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", l, escapeLabelValue(values[i]))
+		}
+		if extraLabel != "" {
+			if len(labels) > 0 {
+				w.WriteByte(',')
+			}
+			fmt.Fprintf(w, "%s=\"%s\"", extraLabel, extraValue)
+		}
+		w.WriteByte('}')
+	}
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelReplacer.Replace(s)
+}
@@ -0,0 +1,172 @@
+package qrcode
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNumDataCodewords(t *testing.T) {
+	// ISO/IEC 18004 table 7, the medium level
+	for version, want := range map[int]int{1: 16, 2: 28, 5: 86, 7: 124, 10: 216, 20: 669, 40: 2334} {
+		require.Equal(t, want, numDataCodewords(version), "version %d", version)
+	}
+}
+
+func TestReedSolomonRemainder(t *testing.T) {
+	// "HELLO WORLD" of the 1-M code in the alphanumeric mode
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	require.Equal(t, want, reedSolomonRemainder(data, reedSolomonDivisor(10)))
+}
+
+func TestDrawFormatBits(t *testing.T) {
+	c := newCode(1)
+	c.drawFormatBits(0)
+
+	// the first copy of the format bits, from the bottom left to the top right
+	var got strings.Builder
+	for y := 0; y <= 8; y++ {
+		if y == 6 {
+			continue
+		}
+		got.WriteByte(map[bool]byte{true: '1', false: '0'}[c.Dark(8, y)])
+	}
+	for x := 7; x >= 0; x-- {
+		if x == 6 {
+			continue
+		}
+		got.WriteByte(map[bool]byte{true: '1', false: '0'}[c.Dark(x, 8)])
+	}
+
+	// the format bits of the medium level and the mask 0, the least significant bit first
+	require.Equal(t, reverse("101010000010010"), got.String())
+}
+
+func TestEncode(t *testing.T) {
+	link := "solana:https%3A%2F%2Fapi.example.com%2Fpayment%2Fcheckout%2F0b3e5d4e-3a3b-4c1e-9a59-3b5f2c1e7d10" +
+		"%2FEPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v%2Ffalse%3Fexpires_at%3D1700000000%26signature%3DqLb0"
+
+	for _, data := range []string{"", "hello", link, strings.Repeat("x", 2331)} {
+		c, err := Encode([]byte(data))
+		require.NoError(t, err)
+		require.Equal(t, c.Version*4+17, c.Size)
+		require.Equal(t, data, string(decode(t, c)))
+	}
+
+	_, err := Encode(make([]byte, 2332))
+	require.ErrorIs(t, err, ErrTooLong)
+}
+
+func TestSVG(t *testing.T) {
+	c, err := Encode([]byte("hello"))
+	require.NoError(t, err)
+
+	svg := c.SVG(4)
+	require.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 29 29"`))
+	require.Contains(t, svg, "M4,4h1v1h-1z") // the top left corner of the finder pattern
+}
+
+// decode reads the data back from the code: it undoes the mask of the format bits,
+// reads and de-interleaves the codewords, checks the error correction and parses the byte mode segment.
+func decode(t *testing.T, c *Code) []byte {
+	t.Helper()
+
+	format := 0
+	for i := 0; i < 8; i++ {
+		if c.Dark(c.Size-1-i, 8) {
+			format |= 1 << i
+		}
+	}
+	for i := 8; i < 15; i++ {
+		if c.Dark(8, c.Size-15+i) {
+			format |= 1 << i
+		}
+	}
+	format ^= 0x5412
+	require.Equal(t, formatBitsM, format>>13, "error correction level")
+	mask := (format >> 10) & 7
+
+	// read the codewords in the zigzag order, the same as drawn
+	var bits bitBuffer
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if !c.isFunction[y][x] {
+					bits = append(bits, c.modules[y][x] != maskBit(mask, x, y))
+				}
+			}
+		}
+	}
+	raw := bits.bytes()[:numRawDataModules(c.Version)/8]
+
+	numBlocks := numErrorCorrectionBlocks[c.Version]
+	eccLen := eccCodewordsPerBlock[c.Version]
+	numShortBlocks := numBlocks - len(raw)%numBlocks
+	shortDataLen := len(raw)/numBlocks - eccLen
+
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i <= shortDataLen; i++ {
+		for j := range blocks {
+			if i < shortDataLen || j >= numShortBlocks {
+				blocks[j] = append(blocks[j], raw[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], raw[k])
+			k++
+		}
+	}
+
+	divisor := reedSolomonDivisor(eccLen)
+	var data []byte
+	for _, block := range blocks {
+		n := len(block) - eccLen
+		require.Equal(t, block[n:], reedSolomonRemainder(block[:n], divisor), "error correction codewords")
+		data = append(data, block[:n]...)
+	}
+
+	require.Equal(t, byte(0x4), data[0]>>4, "byte mode")
+	var r bitBuffer
+	r.append(int(data[0]&0x0f), 4)
+	for _, b := range data[1:] {
+		r.append(int(b), 8)
+	}
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v <<= 1
+			if r[i] {
+				v |= 1
+			}
+		}
+		r = r[n:]
+		return v
+	}
+
+	result := make([]byte, read(charCountBits(c.Version)))
+	for i := range result {
+		result[i] = byte(read(8))
+	}
+	return result
+}
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
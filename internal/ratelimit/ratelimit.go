@@ -0,0 +1,82 @@
+// Package ratelimit implements an in-memory fixed window rate limiter.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Limiter allows up to limit events per period for every key, e.g. a client IP address.
+	// The counters are kept in memory, so every API instance limits the requests it serves.
+	Limiter struct {
+		mu        sync.Mutex
+		limit     int
+		period    time.Duration
+		windows   map[string]*window
+		lastSweep time.Time
+		now       func() time.Time
+	}
+
+	window struct {
+		start time.Time
+		count int
+	}
+)
+
+// New creates a new rate limiter which allows up to limit events per period for every key.
+func New(limit int, period time.Duration) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		period:  period,
+		windows: make(map[string]*window),
+		now:     time.Now,
+	}
+}
+
+// SetLimit changes the limit and the period, e.g. on the config reload.
+// The current windows are kept, so the events already recorded count towards the new limit.
+func (l *Limiter) SetLimit(limit int, period time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = limit
+	l.period = period
+}
+
+// Allow records the event for the given key and reports whether it's allowed.
+// If it's not, the returned duration is the time until the next event is allowed.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.period {
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+
+	if w.count >= l.limit {
+		return false, w.start.Add(l.period).Sub(now)
+	}
+	w.count++
+
+	return true, 0
+}
+
+// sweep removes the expired windows once per period to keep the memory usage bounded.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.period {
+		return
+	}
+	l.lastSweep = now
+
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.period {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := New(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		ok, _ := l.Allow("1.1.1.1")
+		require.True(t, ok)
+	}
+
+	ok, retryAfter := l.Allow("1.1.1.1")
+	require.False(t, ok)
+	require.Equal(t, time.Minute, retryAfter)
+
+	// other keys are limited separately
+	ok, _ = l.Allow("2.2.2.2")
+	require.True(t, ok)
+
+	// the next window
+	now = now.Add(time.Minute)
+	ok, _ = l.Allow("1.1.1.1")
+	require.True(t, ok)
+
+	// expired windows are removed
+	now = now.Add(2 * time.Minute)
+	_, _ = l.Allow("3.3.3.3")
+	require.Len(t, l.windows, 1)
+}
+
+func TestLimiterSetLimit(t *testing.T) {
+	now := time.Now()
+	l := New(1, time.Minute)
+	l.now = func() time.Time { return now }
+
+	ok, _ := l.Allow("1.1.1.1")
+	require.True(t, ok)
+	ok, _ = l.Allow("1.1.1.1")
+	require.False(t, ok)
+
+	// the recorded events count towards the new limit
+	l.SetLimit(2, time.Hour)
+	ok, _ = l.Allow("1.1.1.1")
+	require.True(t, ok)
+	ok, retryAfter := l.Allow("1.1.1.1")
+	require.False(t, ok)
+	require.Equal(t, time.Hour, retryAfter)
+}
@@ -0,0 +1,114 @@
+// Package realip resolves the client IP address of the requests forwarded by the trusted proxies.
+package realip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// DefaultTrustedProxies are the loopback and private networks, e.g. of the load balancer or the ingress.
+// The clients connecting from the internet can't spoof their address by the forwarding headers.
+var DefaultTrustedProxies = []string{
+	"127.0.0.0/8",
+	"::1/128",
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"fc00::/7",
+}
+
+// ParseCIDRs parses the networks of the trusted proxies. A single IP address is accepted as well.
+func ParseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	result := make([]*net.IPNet, 0, len(cidrs))
+	for _, s := range cidrs {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy: %s", s)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			result = append(result, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %w", err)
+		}
+		result = append(result, network)
+	}
+	return result, nil
+}
+
+// Middleware sets RemoteAddr to the client IP address of the requests from the trusted proxies:
+// the right-most untrusted address of the X-Forwarded-For header, or the X-Real-IP header.
+// The headers of the other requests are ignored, so RemoteAddr stays the address of the socket peer.
+// It replaces chi middleware.RealIP, which trusts the headers of any request.
+func Middleware(trusted []*net.IPNet) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip := clientIP(r, trusted); ip != "" {
+				r.RemoteAddr = ip
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the client IP address forwarded by the trusted proxy, or an empty string.
+func clientIP(r *http.Request, trusted []*net.IPNet) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+	if !contains(trusted, net.ParseIP(peer)) {
+		return ""
+	}
+
+	// every proxy appends the address of its peer, so the addresses left to the first untrusted one
+	// are set by the client and can't be trusted
+	if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+		addrs := strings.Split(strings.Join(xff, ","), ",")
+		var client string
+		for i := len(addrs) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(addrs[i]))
+			if ip == nil {
+				break
+			}
+			client = ip.String()
+			if !contains(trusted, ip) {
+				break
+			}
+		}
+		if client != "" {
+			return client
+		}
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+
+	return ""
+}
+
+// contains reports whether the IP address belongs to any of the networks.
+func contains(networks []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package realip
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	trusted, err := ParseCIDRs([]string{"10.0.0.0/8", "192.168.1.1"})
+	require.NoError(t, err)
+
+	var remoteAddr string
+	handler := Middleware(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteAddr = r.RemoteAddr
+	}))
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		want       string
+	}{
+		{"untrusted peer", "203.0.113.7:1234", map[string]string{"X-Forwarded-For": "1.1.1.1", "X-Real-IP": "1.1.1.1"}, "203.0.113.7:1234"},
+		{"trusted peer without headers", "10.0.0.2:1234", nil, "10.0.0.2:1234"},
+		{"forwarded for", "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "203.0.113.7"}, "203.0.113.7"},
+		{"spoofed forwarded for", "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "1.1.1.1, 203.0.113.7, 192.168.1.1"}, "203.0.113.7"},
+		{"all forwarded trusted", "10.0.0.2:1234", map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.4"}, "10.0.0.3"},
+		{"real ip", "192.168.1.1:1234", map[string]string{"X-Real-IP": "203.0.113.7"}, "203.0.113.7"},
+		{"invalid real ip", "192.168.1.1:1234", map[string]string{"X-Real-IP": "unknown"}, "192.168.1.1:1234"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+			require.Equal(t, tt.want, remoteAddr)
+		})
+	}
+}
+
+func TestParseCIDRs(t *testing.T) {
+	networks, err := ParseCIDRs(DefaultTrustedProxies)
+	require.NoError(t, err)
+	require.Len(t, networks, len(DefaultTrustedProxies))
+
+	_, err = ParseCIDRs([]string{"10.0.0.0/33"})
+	require.Error(t, err)
+	_, err = ParseCIDRs([]string{"proxy"})
+	require.Error(t, err)
+}
@@ -5,12 +5,12 @@ import (
 	"net/http"
 	"runtime/debug"
 
+	"github.com/easypmnt/checkout-api/internal/logging"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/sirupsen/logrus"
 )
 
 // WithLogger is a custom recovery middleware that logs the error and stacktrace
-func WithLogger(log *logrus.Entry) func(next http.Handler) http.Handler {
+func WithLogger(log *logging.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		fn := func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
@@ -19,7 +19,7 @@ func WithLogger(log *logrus.Entry) func(next http.Handler) http.Handler {
 					reqID := middleware.GetReqID(r.Context())
 
 					if log != nil {
-						log.WithFields(logrus.Fields{
+						log.WithContext(r.Context()).WithFields(map[string]interface{}{
 							"request_id": reqID,
 							"panic":      rvr,
 							"stack":      string(debug.Stack()),
@@ -22,6 +22,20 @@ func AmountToString(amount uint64, decimals uint8) string {
 	return Float64ToString(f)
 }
 
+// ConvertAmount converts amount in minimal units from one decimals to another,
+// e.g. 1 USDC (1000000, 6 decimals) to 1 token with 9 decimals (1000000000).
+// Extra precision is truncated when converting to fewer decimals.
+func ConvertAmount(amount uint64, fromDecimals, toDecimals uint8) uint64 {
+	switch {
+	case fromDecimals > toDecimals:
+		return amount / uint64(math.Pow10(int(fromDecimals-toDecimals)))
+	case fromDecimals < toDecimals:
+		return amount * uint64(math.Pow10(int(toDecimals-fromDecimals)))
+	default:
+		return amount
+	}
+}
+
 // IntAmountToFloat64 converts int64 amount lamports to float64 with given decimals.
 func IntAmountToFloat64(amount int64, decimals uint8) float64 {
 	return float64(amount) / math.Pow10(int(decimals))
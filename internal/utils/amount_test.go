@@ -161,3 +161,60 @@ func TestAmountToString(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertAmount(t *testing.T) {
+	type args struct {
+		amount       uint64
+		fromDecimals uint8
+		toDecimals   uint8
+	}
+	tests := []struct {
+		name string
+		args args
+		want uint64
+	}{
+		{
+			name: "same decimals",
+			args: args{
+				amount:       1000000,
+				fromDecimals: 6,
+				toDecimals:   6,
+			},
+			want: 1000000,
+		},
+		{
+			name: "1 USDC to 9 decimals",
+			args: args{
+				amount:       1000000,
+				fromDecimals: 6,
+				toDecimals:   9,
+			},
+			want: 1000000000,
+		},
+		{
+			name: "1 SOL to 6 decimals",
+			args: args{
+				amount:       1000000000,
+				fromDecimals: 9,
+				toDecimals:   6,
+			},
+			want: 1000000,
+		},
+		{
+			name: "truncate extra precision",
+			args: args{
+				amount:       1999,
+				fromDecimals: 9,
+				toDecimals:   6,
+			},
+			want: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := utils.ConvertAmount(tt.args.amount, tt.args.fromDecimals, tt.args.toDecimals); got != tt.want {
+				t.Errorf("ConvertAmount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
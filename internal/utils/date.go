@@ -0,0 +1,18 @@
+package utils
+
+import "time"
+
+// DateFormat is the short date format, YYYY-MM-DD.
+const DateFormat = "2006-01-02"
+
+// ParseDate parses the date in YYYY-MM-DD or RFC3339 format.
+// Returns zero time if the value is empty.
+func ParseDate(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(DateFormat, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
@@ -0,0 +1,34 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/utils"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "empty", value: "", want: time.Time{}},
+		{name: "date", value: "2026-10-16", want: time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "rfc3339", value: "2026-10-16T10:30:00Z", want: time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC)},
+		{name: "invalid", value: "16.10.2026", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := utils.ParseDate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -38,6 +38,14 @@ func init() {
 
 			return true
 		},
+		"solanaWallet": func(val interface{}) bool {
+			addr, ok := val.(string)
+			if !ok {
+				return false
+			}
+
+			return ValidateSolanaWalletAddr(addr) == nil
+		},
 	})
 
 	// Add global filters
@@ -55,5 +63,6 @@ func init() {
 	validate.AddGlobalMessages(map[string]string{
 		"realEmail":     "Email address is not real",
 		"sanitizeEmail": "Invalid email address",
+		"solanaWallet":  "Invalid solana wallet address",
 	})
 }
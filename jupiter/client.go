@@ -2,13 +2,14 @@ package jupiter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
-	"time"
 
+	"github.com/easypmnt/checkout-api/internal/httpclient"
 	"github.com/easypmnt/checkout-api/internal/utils"
 )
 
@@ -43,9 +44,9 @@ type (
 // NewClient returns a new Jupiter client.
 func NewClient(opts ...ClientOption) *Client {
 	c := &Client{
-		client: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		client: httpclient.New("jupiter",
+			httpclient.WithRetries(2, httpclient.DefaultRetryWaitMin, httpclient.DefaultRetryWaitMax),
+		),
 
 		apiURL:            "https://quote-api.jup.ag/v4",
 		endpointQuote:     "/quote",
@@ -64,7 +65,7 @@ func NewClient(opts ...ClientOption) *Client {
 // get makes a GET request to the specified endpoint with the given parameters.
 // It returns the response as is without parsing or any error encountered.
 // The caller is responsible for closing the response body.
-func (c *Client) get(endpoint string, params interface{}) (*http.Response, error) {
+func (c *Client) get(ctx context.Context, endpoint string, params interface{}) (*http.Response, error) {
 	uv, err := utils.StructToUrlValues(params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to convert params to url values: %w", err)
@@ -79,7 +80,7 @@ func (c *Client) get(endpoint string, params interface{}) (*http.Response, error
 		parsedURL.RawQuery = uv.Encode()
 	}
 
-	req, err := http.NewRequest(http.MethodGet, parsedURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create GET request: %w", err)
 	}
@@ -96,13 +97,13 @@ func (c *Client) get(endpoint string, params interface{}) (*http.Response, error
 // postRaw makes a POST request to the specified URL with the given parameters.
 // It returns the response as is without parsing or any error encountered.
 // The caller is responsible for closing the response body.
-func (c *Client) post(endpoint string, params interface{}) (*http.Response, error) {
+func (c *Client) post(ctx context.Context, endpoint string, params interface{}) (*http.Response, error) {
 	body, err := json.Marshal(params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal POST params: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.apiURL+endpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.apiURL+endpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create POST request: %w", err)
 	}
@@ -134,8 +135,8 @@ func (c *Client) parseResponse(resp *http.Response) (json.RawMessage, error) {
 }
 
 // Quote returns a quote for a given input mint, output mint and amount
-func (c *Client) Quote(params QuoteParams) (QuoteResponse, error) {
-	resp, err := c.get(c.endpointQuote, params)
+func (c *Client) Quote(ctx context.Context, params QuoteParams) (QuoteResponse, error) {
+	resp, err := c.get(ctx, c.endpointQuote, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make quote request: %w", err)
 	}
@@ -159,8 +160,8 @@ func (c *Client) Quote(params QuoteParams) (QuoteResponse, error) {
 
 // Swap returns swap base64 serialized transaction for a route.
 // The caller is responsible for signing the transactions.
-func (c *Client) Swap(params SwapParams) (string, error) {
-	resp, err := c.post(c.endpointSwap, params)
+func (c *Client) Swap(ctx context.Context, params SwapParams) (string, error) {
+	resp, err := c.post(ctx, c.endpointSwap, params)
 	if err != nil {
 		return "", fmt.Errorf("failed to make swap request: %w", err)
 	}
@@ -177,8 +178,8 @@ func (c *Client) Swap(params SwapParams) (string, error) {
 }
 
 // Price returns simple price for a given input mint, output mint and amount.
-func (c *Client) Price(params PriceParams) (PriceMap, error) {
-	resp, err := c.get(c.endpointPrice, params)
+func (c *Client) Price(ctx context.Context, params PriceParams) (PriceMap, error) {
+	resp, err := c.get(ctx, c.endpointPrice, params)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make price request: %w", err)
 	}
@@ -198,8 +199,8 @@ func (c *Client) Price(params PriceParams) (PriceMap, error) {
 
 // RoutesMap returns a hash map, input mint as key and an array of valid output mint as values,
 // token mints are indexed to reduce the file size.
-func (c *Client) RoutesMap(onlyDirectRoutes bool) (IndexedRoutesMap, error) {
-	resp, err := c.get(c.endpointRoutesMap, url.Values{
+func (c *Client) RoutesMap(ctx context.Context, onlyDirectRoutes bool) (IndexedRoutesMap, error) {
+	resp, err := c.get(ctx, c.endpointRoutesMap, url.Values{
 		"onlyDirectRoutes": []string{strconv.FormatBool(onlyDirectRoutes)},
 	})
 	if err != nil {
@@ -218,11 +219,11 @@ func (c *Client) RoutesMap(onlyDirectRoutes bool) (IndexedRoutesMap, error) {
 // for a given input mint, output mint and amount.
 // Default swap mode: ExactOut, so the amount is the amount of output token.
 // Default wrap unwrap sol: true
-func (c *Client) BestSwap(params BestSwapParams) (string, error) {
+func (c *Client) BestSwap(ctx context.Context, params BestSwapParams) (string, error) {
 	if params.SwapMode == "" {
 		params.SwapMode = SwapModeExactIn
 	}
-	routes, err := c.Quote(QuoteParams{
+	routes, err := c.Quote(ctx, QuoteParams{
 		InputMint:        params.InputMint,
 		OutputMint:       params.OutputMint,
 		Amount:           params.Amount,
@@ -239,7 +240,7 @@ func (c *Client) BestSwap(params BestSwapParams) (string, error) {
 		return "", err
 	}
 
-	swap, err := c.Swap(SwapParams{
+	swap, err := c.Swap(ctx, SwapParams{
 		Route:               route,
 		UserPublicKey:       params.UserPublicKey,
 		DestinationWallet:   params.DestinationPublicKey,
@@ -256,12 +257,12 @@ func (c *Client) BestSwap(params BestSwapParams) (string, error) {
 
 // ExchangeRate returns the exchange rate for a given input mint, output mint and amount.
 // Default swap mode: ExactOut, so the amount is the amount of output token.
-func (c *Client) ExchangeRate(params ExchangeRateParams) (Rate, error) {
+func (c *Client) ExchangeRate(ctx context.Context, params ExchangeRateParams) (Rate, error) {
 	result := Rate{
 		InputMint:  params.InputMint,
 		OutputMint: params.OutputMint,
 	}
-	routes, err := c.Quote(QuoteParams{
+	routes, err := c.Quote(ctx, QuoteParams{
 		InputMint:        params.InputMint,
 		OutputMint:       params.OutputMint,
 		Amount:           params.Amount,
@@ -1,6 +1,7 @@
 package jupiter_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/easypmnt/checkout-api/internal/utils"
@@ -16,7 +17,7 @@ const (
 
 func TestQuote(t *testing.T) {
 	c := jupiter.NewClient()
-	quotes, err := c.Quote(jupiter.QuoteParams{
+	quotes, err := c.Quote(context.Background(), jupiter.QuoteParams{
 		InputMint:        wSolMint,
 		OutputMint:       usdcMint,
 		Amount:           100000,
@@ -40,7 +41,7 @@ func TestSwap(t *testing.T) {
 	var route jupiter.Route
 
 	t.Run("get best route", func(t *testing.T) {
-		quotes, err := c.Quote(jupiter.QuoteParams{
+		quotes, err := c.Quote(context.Background(), jupiter.QuoteParams{
 			InputMint:        wSolMint,
 			OutputMint:       usdcMint,
 			Amount:           100000,
@@ -58,7 +59,7 @@ func TestSwap(t *testing.T) {
 	})
 
 	t.Run("create swap tx", func(t *testing.T) {
-		swapTx, err := c.Swap(jupiter.SwapParams{
+		swapTx, err := c.Swap(context.Background(), jupiter.SwapParams{
 			UserPublicKey: "8HwPMNxtFDrvxXn1fJsAYB258TnA6Ydr1DWCtVYgRW4W",
 			Route:         route,
 			WrapUnwrapSol: utils.Pointer(true),
@@ -73,7 +74,7 @@ func TestSwap(t *testing.T) {
 func TestPrice(t *testing.T) {
 	c := jupiter.NewClient()
 
-	price, err := c.Price(jupiter.PriceParams{
+	price, err := c.Price(context.Background(), jupiter.PriceParams{
 		IDs:     "SOL",
 		VsToken: usdcMint,
 	})
@@ -89,7 +90,7 @@ func TestPrice(t *testing.T) {
 func TestRoutesMap(t *testing.T) {
 	c := jupiter.NewClient()
 
-	routesMap, err := c.RoutesMap(true)
+	routesMap, err := c.RoutesMap(context.Background(), true)
 	require.NoError(t, err)
 	require.NotEmpty(t, routesMap)
 	assert.Greater(t, len(routesMap.GetRoutesForMint(usdcMint)), 0)
@@ -99,7 +100,7 @@ func TestExchangeRate(t *testing.T) {
 	c := jupiter.NewClient()
 
 	var amount uint64 = 100000
-	exchangeRate, err := c.ExchangeRate(jupiter.ExchangeRateParams{
+	exchangeRate, err := c.ExchangeRate(context.Background(), jupiter.ExchangeRateParams{
 		InputMint:  wSolMint,
 		OutputMint: usdcMint,
 		Amount:     amount,
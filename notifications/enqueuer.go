@@ -0,0 +1,107 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+type (
+	// Enqueuer is a helper struct for enqueuing email tasks.
+	Enqueuer struct {
+		client       *asynq.Client
+		queueName    string
+		taskDeadline time.Duration
+		maxRetry     int
+	}
+
+	// EnqueuerOption is a function that configures an enqueuer.
+	EnqueuerOption func(*Enqueuer)
+)
+
+// NewEnqueuer creates a new email enqueuer.
+// This function accepts EnqueuerOption to configure the enqueuer.
+// Default values are used if no option is provided.
+// Default values are:
+//   - queue name: "default"
+//   - task deadline: 1 minute
+//   - max retry: 3
+func NewEnqueuer(client *asynq.Client, opt ...EnqueuerOption) *Enqueuer {
+	if client == nil {
+		panic("client is nil")
+	}
+
+	e := &Enqueuer{
+		client:       client,
+		queueName:    "default",
+		taskDeadline: time.Minute,
+		maxRetry:     3,
+	}
+
+	for _, o := range opt {
+		o(e)
+	}
+
+	return e
+}
+
+// WithQueueName configures the queue name.
+func WithQueueName(name string) EnqueuerOption {
+	return func(e *Enqueuer) {
+		e.queueName = name
+	}
+}
+
+// WithTaskDeadline configures the task deadline.
+func WithTaskDeadline(d time.Duration) EnqueuerOption {
+	return func(e *Enqueuer) {
+		e.taskDeadline = d
+	}
+}
+
+// WithMaxRetry configures the max retry.
+func WithMaxRetry(n int) EnqueuerOption {
+	return func(e *Enqueuer) {
+		e.maxRetry = n
+	}
+}
+
+// enqueueTask enqueues a task to the queue.
+func (e *Enqueuer) enqueueTask(ctx context.Context, task *asynq.Task) error {
+	if _, err := e.client.Enqueue(
+		task,
+		asynq.Queue(e.queueName),
+		asynq.Deadline(time.Now().Add(e.taskDeadline)),
+		asynq.MaxRetry(e.maxRetry),
+		asynq.Unique(e.taskDeadline),
+	); err != nil {
+		return fmt.Errorf("failed to enqueue task: %w", err)
+	}
+
+	return nil
+}
+
+// SendPaymentEmail enqueues the tasks to send emails about the payment event,
+// one task per recipient, so they are retried independently.
+// This function returns an error if a task could not be enqueued.
+func (e *Enqueuer) SendPaymentEmail(ctx context.Context, event, paymentID string) error {
+	for _, recipient := range []string{RecipientMerchant, RecipientCustomer} {
+		task, err := json.Marshal(SendPaymentEmailPayload{
+			Event:     event,
+			PaymentID: paymentID,
+			Recipient: recipient,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal task payload: %w", err)
+		}
+
+		if err := e.enqueueTask(ctx, asynq.NewTask(TaskSendPaymentEmail, task)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package notifications
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/events"
+)
+
+type emailEnqueuer interface {
+	SendPaymentEmail(ctx context.Context, event, paymentID string) error
+}
+
+// PaymentEmailsListener enqueues email notifications for the payment.succeeded and payment.failed events.
+func PaymentEmailsListener(enq emailEnqueuer) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		if payload == nil {
+			return nil
+		}
+
+		if event != events.PaymentSucceeded && event != events.PaymentFailed {
+			return nil
+		}
+
+		p, ok := payload.(events.PaymentIDGetter)
+		if !ok {
+			return nil
+		}
+
+		return enq.SendPaymentEmail(context.Background(), string(event), p.GetPaymentID())
+	}
+}
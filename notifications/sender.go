@@ -0,0 +1,10 @@
+package notifications
+
+import "context"
+
+// Sender is the interface that wraps the Send method.
+// Implement it to deliver emails through any provider.
+type Sender interface {
+	// Send sends the given email message.
+	Send(ctx context.Context, msg Message) error
+}
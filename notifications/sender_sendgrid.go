@@ -0,0 +1,126 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"time"
+)
+
+// SendGrid v3 mail send endpoint.
+const sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+type (
+	// SendGridSender sends emails through the SendGrid v3 API.
+	SendGridSender struct {
+		client   *http.Client
+		endpoint string
+		apiKey   string
+	}
+
+	// SendGridSenderOption is a function that configures the SendGridSender.
+	SendGridSenderOption func(*SendGridSender)
+
+	sendGridAddress struct {
+		Email string `json:"email"`
+		Name  string `json:"name,omitempty"`
+	}
+
+	sendGridPersonalization struct {
+		To []sendGridAddress `json:"to"`
+	}
+
+	sendGridContent struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	}
+
+	sendGridRequest struct {
+		Personalizations []sendGridPersonalization `json:"personalizations"`
+		From             sendGridAddress           `json:"from"`
+		Subject          string                    `json:"subject"`
+		Content          []sendGridContent         `json:"content"`
+	}
+)
+
+// NewSendGridSender creates a new SendGrid sender.
+func NewSendGridSender(apiKey string, opts ...SendGridSenderOption) *SendGridSender {
+	s := &SendGridSender{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: sendGridEndpoint,
+		apiKey:   apiKey,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithSendGridHTTPClient sets a custom HTTP client.
+func WithSendGridHTTPClient(client *http.Client) SendGridSenderOption {
+	return func(s *SendGridSender) {
+		s.client = client
+	}
+}
+
+// WithSendGridEndpoint sets a custom SendGrid API endpoint, e.g. for EU data residency.
+func WithSendGridEndpoint(endpoint string) SendGridSenderOption {
+	return func(s *SendGridSender) {
+		s.endpoint = endpoint
+	}
+}
+
+// Send sends the given email message.
+func (s *SendGridSender) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(msg.From)
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
+	}
+
+	to := make([]sendGridAddress, 0, len(msg.To))
+	for _, addr := range msg.To {
+		to = append(to, sendGridAddress{Email: addr})
+	}
+
+	// SendGrid requires the text/plain part to go first.
+	content := []sendGridContent{{Type: "text/plain", Value: msg.Text}}
+	if msg.HTML != "" {
+		content = append(content, sendGridContent{Type: "text/html", Value: msg.HTML})
+	}
+
+	body, err := json.Marshal(sendGridRequest{
+		Personalizations: []sendGridPersonalization{{To: to}},
+		From:             sendGridAddress{Email: from.Address, Name: from.Name},
+		Subject:          msg.Subject,
+		Content:          content,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to send email: unexpected status code: %d: %s", resp.StatusCode, string(msg))
+	}
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/easypmnt/checkout-api/payments"
+)
+
+// Known token decimals to format the payment amount.
+var knownMints = map[string]struct {
+	symbol   string
+	decimals uint8
+}{
+	payments.SOL:  {"SOL", 9},
+	payments.USDC: {"USDC", 6},
+	payments.USDT: {"USDT", 6},
+}
+
+type (
+	// Service builds and sends payment notification emails.
+	Service struct {
+		sender          Sender
+		from            string
+		productName     string
+		merchantEmails  []string
+		notifyCustomers bool
+		mintDecimals    mintDecimalsGetter
+	}
+
+	// mintDecimalsGetter resolves decimals of an arbitrary SPL token mint.
+	mintDecimalsGetter interface {
+		GetMintDecimals(ctx context.Context, base58MintAddr string) (uint8, error)
+	}
+
+	// ServiceOption is a function that configures the notifications service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new notifications service.
+func NewService(sender Sender, from string, opts ...ServiceOption) *Service {
+	if sender == nil {
+		panic("sender is nil")
+	}
+
+	s := &Service{
+		sender:      sender,
+		from:        from,
+		productName: "Checkout API",
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithProductName sets the product name used in the email footer.
+func WithProductName(name string) ServiceOption {
+	return func(s *Service) {
+		s.productName = name
+	}
+}
+
+// WithMerchantEmails sets the merchant email addresses to notify about payments.
+func WithMerchantEmails(emails ...string) ServiceOption {
+	return func(s *Service) {
+		s.merchantEmails = emails
+	}
+}
+
+// WithCustomerNotifications enables emails to customers, if an email is attached to the payment.
+func WithCustomerNotifications(enabled bool) ServiceOption {
+	return func(s *Service) {
+		s.notifyCustomers = enabled
+	}
+}
+
+// WithMintDecimals sets the mint decimals getter to format amounts of arbitrary SPL tokens.
+// Without it, amounts of unknown mints are formatted in base units.
+func WithMintDecimals(g mintDecimalsGetter) ServiceOption {
+	return func(s *Service) {
+		s.mintDecimals = g
+	}
+}
+
+// SendPaymentEmails sends emails about the given payment event to the recipient:
+// RecipientMerchant, RecipientCustomer, or both of them if empty.
+// Only payment.succeeded and payment.failed events are supported, other events are ignored.
+func (s *Service) SendPaymentEmails(ctx context.Context, event, recipient string, payment *payments.Payment) error {
+	var succeeded bool
+	switch events.EventName(event) {
+	case events.PaymentSucceeded:
+		succeeded = true
+	case events.PaymentFailed:
+		succeeded = false
+	default:
+		return nil
+	}
+
+	data := templateData{
+		ProductName: s.productName,
+		Succeeded:   succeeded,
+		PaymentID:   payment.ID.String(),
+		ExternalID:  payment.ExternalID,
+		Message:     payment.Message,
+	}
+	data.Amount, data.Currency = s.formatAmount(ctx, payment.Amount, payment.DestinationMint)
+
+	if (recipient == "" || recipient == RecipientMerchant) && len(s.merchantEmails) > 0 {
+		data.ForMerchant = true
+		if err := s.send(ctx, s.merchantEmails, data); err != nil {
+			return fmt.Errorf("failed to notify merchant: %w", err)
+		}
+	}
+
+	if (recipient == "" || recipient == RecipientCustomer) && s.notifyCustomers && payment.CustomerEmail != "" {
+		data.ForMerchant = false
+		if err := s.send(ctx, []string{payment.CustomerEmail}, data); err != nil {
+			return fmt.Errorf("failed to notify customer: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// send renders the templates and sends the email to the given recipients.
+func (s *Service) send(ctx context.Context, to []string, data templateData) error {
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, data); err != nil {
+		return fmt.Errorf("failed to render text template: %w", err)
+	}
+	if err := htmlTemplate.Execute(&html, data); err != nil {
+		return fmt.Errorf("failed to render html template: %w", err)
+	}
+
+	return s.sender.Send(ctx, Message{
+		From:    s.from,
+		To:      to,
+		Subject: subject(data),
+		Text:    text.String(),
+		HTML:    html.String(),
+	})
+}
+
+// formatAmount returns the human-readable amount and currency of the payment.
+// Unknown mints are formatted with the mint address as currency, and in base units
+// if their decimals can't be resolved.
+func (s *Service) formatAmount(ctx context.Context, amount uint64, mint string) (string, string) {
+	mint = payments.MintAddress(mint, payments.SOL)
+	if m, ok := knownMints[mint]; ok {
+		return utils.AmountToString(amount, m.decimals), m.symbol
+	}
+	if s.mintDecimals != nil {
+		if decimals, err := s.mintDecimals.GetMintDecimals(ctx, mint); err == nil {
+			return utils.AmountToString(amount, decimals), mint
+		}
+	}
+	return fmt.Sprintf("%d", amount), mint
+}
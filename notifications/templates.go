@@ -0,0 +1,67 @@
+package notifications
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// templateData is the data passed to the email templates.
+type templateData struct {
+	ProductName string
+	Succeeded   bool
+	ForMerchant bool
+	PaymentID   string
+	ExternalID  string
+	Amount      string
+	Currency    string
+	Message     string
+}
+
+var textTemplate = texttemplate.Must(texttemplate.New("text").Parse(strings.TrimSpace(`
+{{if .Succeeded}}{{if .ForMerchant}}You have received a payment.{{else}}Your payment has been confirmed. Thank you!{{end}}{{else}}{{if .ForMerchant}}A payment has failed.{{else}}Unfortunately, your payment has failed. No funds have been charged.{{end}}{{end}}
+
+Amount: {{.Amount}} {{.Currency}}
+Payment ID: {{.PaymentID}}
+{{- if .ExternalID}}
+Order ID: {{.ExternalID}}
+{{- end}}
+{{- if .Message}}
+Message: {{.Message}}
+{{- end}}
+
+--
+{{.ProductName}}
+`)))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(strings.TrimSpace(`
+<!DOCTYPE html>
+<html>
+<body style="font-family: sans-serif; color: #222;">
+<p>{{if .Succeeded}}{{if .ForMerchant}}You have received a payment.{{else}}Your payment has been confirmed. Thank you!{{end}}{{else}}{{if .ForMerchant}}A payment has failed.{{else}}Unfortunately, your payment has failed. No funds have been charged.{{end}}{{end}}</p>
+<table cellpadding="4">
+<tr><td>Amount</td><td><strong>{{.Amount}} {{.Currency}}</strong></td></tr>
+<tr><td>Payment ID</td><td>{{.PaymentID}}</td></tr>
+{{- if .ExternalID}}
+<tr><td>Order ID</td><td>{{.ExternalID}}</td></tr>
+{{- end}}
+{{- if .Message}}
+<tr><td>Message</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+<p style="color: #888;">{{.ProductName}}</p>
+</body>
+</html>
+`)))
+
+// subject returns the email subject for the given template data.
+func subject(d templateData) string {
+	switch {
+	case d.Succeeded && d.ForMerchant:
+		return "Payment received: " + d.Amount + " " + d.Currency
+	case d.Succeeded:
+		return "Payment confirmed: " + d.Amount + " " + d.Currency
+	default:
+		return "Payment failed: " + d.Amount + " " + d.Currency
+	}
+}
@@ -0,0 +1,33 @@
+package notifications
+
+// Worker task types
+const (
+	TaskSendPaymentEmail = "notifications:send_payment_email"
+)
+
+// Payment email recipients, each one is notified by its own task,
+// so a failed delivery to one of them doesn't resend the email to the other on retry.
+const (
+	RecipientMerchant = "merchant"
+	RecipientCustomer = "customer"
+)
+
+type (
+	// SendPaymentEmailPayload is the payload for the notifications:send_payment_email task.
+	SendPaymentEmailPayload struct {
+		Event     string `json:"event"`
+		PaymentID string `json:"payment_id"`
+		// Recipient is RecipientMerchant or RecipientCustomer,
+		// both of them if empty, e.g. for the tasks enqueued by the previous versions.
+		Recipient string `json:"recipient,omitempty"`
+	}
+
+	// Message is an email message to send.
+	Message struct {
+		From    string   `json:"from"`
+		To      []string `json:"to"`
+		Subject string   `json:"subject"`
+		Text    string   `json:"text"`
+		HTML    string   `json:"html,omitempty"`
+	}
+)
@@ -0,0 +1,61 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+type (
+	// Worker is a task handler for email delivery.
+	Worker struct {
+		svc        service
+		paymentSvc paymentService
+	}
+
+	service interface {
+		SendPaymentEmails(ctx context.Context, event, recipient string, payment *payments.Payment) error
+	}
+
+	paymentService interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (*payments.Payment, error)
+	}
+)
+
+// NewWorker creates a new email task handler.
+func NewWorker(svc service, paymentSvc paymentService) *Worker {
+	return &Worker{svc: svc, paymentSvc: paymentSvc}
+}
+
+// Register registers task handlers for email delivery.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskSendPaymentEmail, w.SendPaymentEmail)
+}
+
+// SendPaymentEmail sends emails about the payment event to the recipient of the task.
+func (w *Worker) SendPaymentEmail(ctx context.Context, t *asynq.Task) error {
+	var p SendPaymentEmailPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	pid, err := uuid.Parse(p.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to parse payment id: %w", err)
+	}
+
+	payment, err := w.paymentSvc.GetPayment(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	if err := w.svc.SendPaymentEmails(ctx, p.Event, p.Recipient, payment); err != nil {
+		return fmt.Errorf("failed to send payment emails: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,64 @@
+// Package outbox implements the transactional outbox of the events: the events are stored
+// in the same database transaction as the changes they're about and published after the commit,
+// so a crash between the commit and the in-memory emit doesn't drop the notifications.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+type (
+	// Outbox records the events in the event_outbox table, see Poller.
+	Outbox struct {
+		repo txRepository
+	}
+
+	txRepository interface {
+		InTx(ctx context.Context, fn func(q repository.Querier) error) error
+	}
+
+	// record is the event fired in the transaction.
+	record struct {
+		name    events.EventName
+		payload interface{}
+	}
+)
+
+// New creates a new outbox on top of the storage.
+func New(repo txRepository) *Outbox {
+	return &Outbox{repo: repo}
+}
+
+// InTx runs fn in a database transaction. The context passed to fn carries the queries of the transaction,
+// see repository.ContextWithTx, and the events fired by fn are stored in the outbox in the same transaction,
+// so they're published only if the transaction is committed.
+func (o *Outbox) InTx(ctx context.Context, fn func(ctx context.Context, fireEvent func(events.EventName, interface{})) error) error {
+	return o.repo.InTx(ctx, func(q repository.Querier) error {
+		var records []record
+		if err := fn(repository.ContextWithTx(ctx, q), func(name events.EventName, payload interface{}) {
+			records = append(records, record{name: name, payload: payload})
+		}); err != nil {
+			return err
+		}
+
+		for _, r := range records {
+			payload, err := json.Marshal(r.payload)
+			if err != nil {
+				return fmt.Errorf("failed to marshal %s event payload: %w", r.name, err)
+			}
+			if err := q.AddOutboxEvent(ctx, repository.AddOutboxEventParams{
+				Event:   string(r.name),
+				Payload: payload,
+			}); err != nil {
+				return fmt.Errorf("failed to store %s event in outbox: %w", r.name, err)
+			}
+		}
+
+		return nil
+	})
+}
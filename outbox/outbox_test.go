@@ -0,0 +1,137 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/outbox"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// repoMock keeps the outbox in memory; the changes of the failed transactions are discarded.
+type repoMock struct {
+	repository.Querier // the queries not used by the outbox panic
+
+	mu     sync.Mutex
+	events []repository.EventOutbox
+}
+
+func (r *repoMock) InTx(ctx context.Context, fn func(q repository.Querier) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := &repoMock{events: append([]repository.EventOutbox(nil), r.events...)}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	r.events = tx.events
+	return nil
+}
+
+func (r *repoMock) AddOutboxEvent(ctx context.Context, arg repository.AddOutboxEventParams) error {
+	r.events = append(r.events, repository.EventOutbox{
+		ID:        int64(len(r.events) + 1),
+		Event:     arg.Event,
+		Payload:   arg.Payload,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (r *repoMock) GetPendingOutboxEvents(ctx context.Context, batchSize int32) ([]repository.EventOutbox, error) {
+	var result []repository.EventOutbox
+	for _, e := range r.events {
+		if !e.PublishedAt.Valid && len(result) < int(batchSize) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func (r *repoMock) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	r.events[id-1].PublishedAt.Time, r.events[id-1].PublishedAt.Valid = time.Now(), true
+	return nil
+}
+
+func (r *repoMock) DeleteOutboxEventsPublishedBefore(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+type logMock struct{}
+
+func (logMock) Infof(string, ...interface{})  {}
+func (logMock) Errorf(string, ...interface{}) {}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoMock{}
+	o := outbox.New(repo)
+
+	err := o.InTx(ctx, func(ctx context.Context, fireEvent func(events.EventName, interface{})) error {
+		_, ok := repository.TxFromContext(ctx)
+		require.True(t, ok)
+
+		fireEvent(events.PaymentSucceeded, events.PaymentStatusUpdatedPayload{
+			PaymentID: events.PaymentID{PaymentID: "pid"},
+			Status:    "completed",
+		})
+		return nil
+	})
+	require.NoError(t, err)
+
+	// the events of the failed transaction are not stored
+	err = o.InTx(ctx, func(ctx context.Context, fireEvent func(events.EventName, interface{})) error {
+		fireEvent(events.PaymentFailed, events.PaymentStatusUpdatedPayload{PaymentID: events.PaymentID{PaymentID: "pid"}})
+		return errors.New("update failed")
+	})
+	require.Error(t, err)
+	require.Len(t, repo.events, 1)
+
+	var published []interface{}
+	p := outbox.NewPoller(repo, func(name events.EventName, payload interface{}) {
+		require.Equal(t, events.PaymentSucceeded, name)
+		published = append(published, payload)
+	}, logMock{})
+
+	n, err := p.Publish(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Equal(t, []interface{}{events.PaymentStatusUpdatedPayload{
+		PaymentID: events.PaymentID{PaymentID: "pid"},
+		Status:    "completed",
+	}}, published)
+
+	// the published events are not published again
+	n, err = p.Publish(ctx)
+	require.NoError(t, err)
+	require.Zero(t, n)
+	require.Len(t, published, 1)
+}
+
+func TestPollerBatches(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoMock{}
+	o := outbox.New(repo)
+
+	for i := 0; i < 5; i++ {
+		require.NoError(t, o.InTx(ctx, func(ctx context.Context, fireEvent func(events.EventName, interface{})) error {
+			fireEvent(events.PaymentCreated, events.PaymentCreatedPayload{PaymentID: events.PaymentID{PaymentID: "pid"}})
+			return nil
+		}))
+	}
+
+	var count int
+	p := outbox.NewPoller(repo, func(events.EventName, interface{}) { count++ }, logMock{}, outbox.WithBatchSize(2))
+
+	for _, want := range []int{2, 2, 1, 0} {
+		n, err := p.Publish(ctx)
+		require.NoError(t, err)
+		require.Equal(t, want, n)
+	}
+	require.Equal(t, 5, count)
+}
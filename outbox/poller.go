@@ -0,0 +1,154 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+// Default poller settings.
+const (
+	DefaultPollInterval = time.Second
+	DefaultBatchSize    = 100
+	DefaultRetention    = 24 * time.Hour
+)
+
+type (
+	// Poller publishes the events stored in the outbox to the emitter, in the order they were stored.
+	// The batch is locked, published and marked as published in one transaction, so the instances
+	// polling the same outbox don't publish the same events. An event is published again only if
+	// the transaction fails after the batch is published, e.g. the instance crashes; the webhooks
+	// of such an event get the same ID, see webhook.EventID, so the duplicates can be dropped.
+	Poller struct {
+		repo      pollerRepository
+		publish   func(events.EventName, interface{})
+		log       Logger
+		interval  time.Duration
+		batchSize int
+		retention time.Duration
+	}
+
+	// PollerOption is a function that configures the poller.
+	PollerOption func(*Poller)
+
+	// Logger is the logger of the poller.
+	Logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+
+	pollerRepository interface {
+		InTx(ctx context.Context, fn func(q repository.Querier) error) error
+		DeleteOutboxEventsPublishedBefore(ctx context.Context, publishedBefore time.Time) (int64, error)
+	}
+)
+
+// NewPoller creates a new poller publishing the outbox events with the given function, e.g. events.Emitter.Emit.
+func NewPoller(repo pollerRepository, publish func(events.EventName, interface{}), log Logger, opts ...PollerOption) *Poller {
+	p := &Poller{
+		repo:      repo,
+		publish:   publish,
+		log:       log,
+		interval:  DefaultPollInterval,
+		batchSize: DefaultBatchSize,
+		retention: DefaultRetention,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithPollInterval configures how often the outbox is polled while there are no pending events.
+func WithPollInterval(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.interval = d
+	}
+}
+
+// WithBatchSize configures the number of events published in one transaction.
+func WithBatchSize(n int) PollerOption {
+	return func(p *Poller) {
+		p.batchSize = n
+	}
+}
+
+// WithRetention configures how long the published events are kept in the outbox, e.g. for troubleshooting.
+func WithRetention(d time.Duration) PollerOption {
+	return func(p *Poller) {
+		p.retention = d
+	}
+}
+
+// Run polls the outbox until the context is done. The full batches are followed by the next one at once.
+func (p *Poller) Run(ctx context.Context) error {
+	p.log.Infof("outbox: polling every %s", p.interval)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	lastCleanup := time.Now()
+	for {
+		n, err := p.Publish(ctx)
+		if err != nil {
+			p.log.Errorf("outbox: %s", err.Error())
+		}
+
+		if time.Since(lastCleanup) > time.Minute {
+			if _, err := p.repo.DeleteOutboxEventsPublishedBefore(ctx, time.Now().Add(-p.retention)); err != nil {
+				p.log.Errorf("outbox: failed to delete published events: %s", err.Error())
+			}
+			lastCleanup = time.Now()
+		}
+
+		if err == nil && n == p.batchSize {
+			if ctx.Err() != nil {
+				return nil
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Publish publishes the next batch of the pending events and returns the number of the published ones.
+// The events with the payload which can't be decoded are marked as published without publishing,
+// so they don't block the outbox.
+func (p *Poller) Publish(ctx context.Context) (int, error) {
+	var published int
+	err := p.repo.InTx(ctx, func(q repository.Querier) error {
+		pending, err := q.GetPendingOutboxEvents(ctx, int32(p.batchSize))
+		if err != nil {
+			return fmt.Errorf("failed to get pending events: %w", err)
+		}
+
+		for _, e := range pending {
+			payload, err := events.DecodePayload(events.EventName(e.Event), e.Payload)
+			if err != nil {
+				p.log.Errorf("outbox: skip event %d: failed to decode %s payload: %s", e.ID, e.Event, err.Error())
+			} else {
+				p.publish(events.EventName(e.Event), payload)
+			}
+
+			if err := q.MarkOutboxEventPublished(ctx, e.ID); err != nil {
+				return fmt.Errorf("failed to mark event %d as published: %w", e.ID, err)
+			}
+		}
+		published = len(pending)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return published, nil
+}
@@ -0,0 +1,10 @@
+package partitions
+
+import "github.com/easypmnt/checkout-api/internal/metrics"
+
+// Partitions metrics.
+var partitionsCreatedTotal = metrics.NewCounterVec(
+	"checkout_partitions_created_total",
+	"Total number of the monthly table partitions created.",
+	"table",
+)
@@ -0,0 +1,16 @@
+package partitions
+
+import "github.com/hibiken/asynq"
+
+// Scheduler is a task scheduler for the partitions management.
+type Scheduler struct{}
+
+// NewScheduler creates a new task scheduler for the partitions management.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Schedule tasks for the partitions management.
+func (s *Scheduler) Schedule(scheduler *asynq.Scheduler) {
+	scheduler.Register("@every 24h", asynq.NewTask(TaskCreatePartitions, nil))
+}
@@ -0,0 +1,66 @@
+// Package partitions keeps the monthly partitions of the partitioned tables
+// created ahead of time, see repository/sql/partitioning.
+package partitions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/hibiken/asynq"
+)
+
+// Task names.
+const (
+	TaskCreatePartitions = "create_partitions"
+)
+
+// Tables is the list of the tables which can be partitioned by month.
+// The partitions are created only for the tables which are actually partitioned.
+var Tables = []string{"transactions", "payment_events"}
+
+type (
+	// Worker is a task handler for the partitions management.
+	Worker struct {
+		repo        workerRepository
+		monthsAhead int
+	}
+
+	workerRepository interface {
+		CreateMonthlyPartitions(ctx context.Context, arg repository.CreateMonthlyPartitionsParams) (int32, error)
+	}
+)
+
+// NewWorker creates a new partitions task handler,
+// which creates the partitions for the current and the given number of upcoming months.
+func NewWorker(repo workerRepository, monthsAhead int) *Worker {
+	if monthsAhead < 1 {
+		monthsAhead = 1
+	}
+	return &Worker{repo: repo, monthsAhead: monthsAhead}
+}
+
+// Register registers task handlers for the partitions management.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskCreatePartitions, w.CreatePartitions)
+}
+
+// CreatePartitions creates the missing monthly partitions of the partitioned tables,
+// so the new rows don't end up in the default partitions.
+func (w *Worker) CreatePartitions(ctx context.Context, t *asynq.Task) error {
+	now := time.Now().UTC()
+	for _, table := range Tables {
+		created, err := w.repo.CreateMonthlyPartitions(ctx, repository.CreateMonthlyPartitionsParams{
+			ParentTable: table,
+			FromDate:    now,
+			ToDate:      now.AddDate(0, w.monthsAhead, 0),
+		})
+		if err != nil {
+			return fmt.Errorf("worker: failed to create %s partitions: %w", table, err)
+		}
+		partitionsCreatedTotal.WithLabelValues(table).Add(float64(created))
+	}
+
+	return nil
+}
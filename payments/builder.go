@@ -5,11 +5,16 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/easypmnt/checkout-api/internal/utils"
 	"github.com/easypmnt/checkout-api/jupiter"
 	"github.com/easypmnt/checkout-api/solana"
+	"github.com/google/uuid"
 	"github.com/portto/solana-go-sdk/types"
 )
 
+// lamportsPerSignature is the base network fee per transaction signature.
+const lamportsPerSignature uint64 = 5000
+
 type (
 	// PaymentBuilder is a builder for creating a payment transaction.
 	PaymentBuilder struct {
@@ -19,87 +24,145 @@ type (
 		tx     *Transaction
 
 		availableBonusAmount uint64
-		referenceAccount     types.Account
-		bonusAuthAccount     *types.Account
+		feeOnTop             bool
+
+		// main reference of the transaction and the number of the extra ones.
+		reference      string
+		referenceIndex uint32
+
+		// merchant wallet, differs from the transaction destination wallet
+		// if the payment is held in the escrow wallet.
+		merchantWallet string
+
+		// voucher mint of the merchant and the amount of voucher tokens to burn.
+		voucherMint       string
+		voucherBurnAmount uint64
+
+		// decimals of the bonus and destination mints,
+		// used to convert amounts between them.
+		bonusDecimals       uint8
+		destinationDecimals uint8
 	}
 )
 
 // NewPaymentTransactionBuilder creates a new PaymentTransactionBuilder.
 func NewPaymentTransactionBuilder(sc solanaClient, jc jupiterClient, config Config) *PaymentBuilder {
 	b := &PaymentBuilder{
-		sol:              sc,
-		jup:              jc,
-		config:           config,
-		referenceAccount: types.NewAccount(),
+		sol:    sc,
+		jup:    jc,
+		config: config,
 	}
 
 	if b.config.ApplyBonus && b.config.BonusMintAddress == "" {
 		panic("bonus mint address is required")
 	}
-	if b.config.AccrueBonus && b.config.BonusAuthAccount == "" {
-		panic("bonus auth account is required")
+	if b.config.AccrueBonus && b.config.BonusAuthority == nil {
+		panic("bonus authority is required")
 	}
 	if b.config.AccrueBonusRate == 0 {
 		b.config.AccrueBonusRate = 100
 	}
 
-	mintAuth, err := types.AccountFromBase58(config.BonusAuthAccount)
-	if err != nil {
-		panic(fmt.Errorf("failed to parse bonus auth account: %w", err))
-	}
-	b.bonusAuthAccount = &mintAuth
-
 	return b
 }
 
 // SetTransaction sets the transaction.
+// The transaction ID is generated if it's not set, the references are derived from it.
 func (b *PaymentBuilder) SetTransaction(tx *Transaction, p *Payment) *PaymentBuilder {
+	if tx.ID == uuid.Nil {
+		tx.ID = uuid.New()
+	}
 	tx.DestinationWallet = p.DestinationWallet
 	tx.DestinationMint = p.DestinationMint
-	tx.Reference = b.referenceAccount.PublicKey.ToBase58()
-	tx.Amount = p.Amount
-	tx.Message = p.Message
+	b.tx = tx
+	b.reference = b.nextReference()
+	tx.Reference = b.reference
+	tx.References = nil
+	// the top-up transaction of the partially paid payment pays the rest of the amount
+	tx.Amount = p.AmountDue()
+	if p.AllowPartial && tx.PartialAmount > 0 && tx.PartialAmount < tx.Amount {
+		tx.Amount = tx.PartialAmount
+	}
+	tx.Message = p.Translate(tx.Locale).Message
 	tx.Memo = p.ExternalID
+	b.feeOnTop = p.FeeOnTop
 	tx.DestinationMint = MintAddress(tx.DestinationMint, b.config.DestinationMint)
 	tx.SourceMint = MintAddress(tx.SourceMint, tx.DestinationMint)
 	if tx.DestinationWallet == "" {
 		tx.DestinationWallet = b.config.DestinationWallet
 	}
+	b.merchantWallet = tx.DestinationWallet
+	if p.Escrow && b.config.EscrowSigner != nil {
+		tx.DestinationWallet = b.config.EscrowSigner.PublicKey().ToBase58()
+	}
 	if tx.TotalAmount == 0 {
 		tx.TotalAmount = tx.Amount - tx.DiscountAmount
 	}
-	b.tx = tx
+	if tx.TipAmount == 0 {
+		tx.TipDestination = ""
+	} else if tx.TipDestination == "" {
+		tx.TipDestination = tx.DestinationWallet
+	}
 	return b
 }
 
 // GetReferenceAddress returns the reference address.
 func (b *PaymentBuilder) GetReferenceAddress() string {
-	return b.referenceAccount.PublicKey.ToBase58()
+	return b.reference
+}
+
+// newReference generates an additional reference of the transaction.
+// Every instruction which moves funds carries its own reference, so the transaction
+// can be found even if the wallet strips the extra account metas from some of the instructions,
+// e.g. rebuilds the token transfer.
+func (b *PaymentBuilder) newReference() string {
+	reference := b.nextReference()
+	b.tx.References = append(b.tx.References, reference)
+	return reference
+}
+
+// nextReference returns the next reference of the transaction: derived by the Config.ReferenceDeriver,
+// if it's set, or a random public key otherwise.
+func (b *PaymentBuilder) nextReference() string {
+	defer func() { b.referenceIndex++ }()
+	if b.config.ReferenceDeriver != nil {
+		return b.config.ReferenceDeriver.Reference(b.tx.PaymentID, b.tx.ID, b.referenceIndex)
+	}
+	return types.NewAccount().PublicKey.ToBase58()
 }
 
 // Build builds the payment transaction.
+// If the swap and the payout don't fit in one transaction and the client accepts a set of them,
+// see Transaction.AllowSplit, the swap is moved to a separate transaction to be sent first.
+// The returned transaction is the payout then, and the ordered set is in Transaction.Transactions.
 func (b *PaymentBuilder) Build(ctx context.Context) (string, *Transaction, error) {
-	if err := b.validate(); err != nil {
-		return "", nil, fmt.Errorf("failed to validate builder parameters: %w", err)
+	if err := b.prepare(ctx); err != nil {
+		return "", nil, err
 	}
 
-	bonusBalance, _ := b.sol.GetTokenBalance(ctx, b.tx.SourceWallet, b.config.BonusMintAddress)
-	b.availableBonusAmount = bonusBalance.Amount
-	b.tx = b.recalculateTotalAmount(b.tx)
-
-	builder := solana.NewTransactionBuilder(b.sol).SetFeePayer(b.tx.SourceWallet)
-	builder = b.burnBonus(builder)
-	builder, err := b.swap(builder)
+	swap, err := b.swap(ctx)
 	if err != nil {
 		return "", nil, err
 	}
-	if IsSOL(b.tx.DestinationMint) {
-		builder = b.transferSOL(builder)
-	} else {
-		builder = b.transferToken(builder)
+
+	payout := b.newTransactionBuilder()
+	payout = b.burnBonus(payout)
+	payout = b.burnVoucher(payout)
+	// nothing to transfer if the payment is fully covered by the voucher
+	if b.tx.TotalAmount > 0 {
+		if IsSOL(b.tx.DestinationMint) {
+			payout = b.transferSOL(payout)
+		} else {
+			payout = b.transferToken(payout)
+		}
+	}
+	payout = b.transferTip(payout)
+	payout = b.mintBonus(payout)
+
+	base64Tx, err := payout.Clone().AddRawInstructionsToBeginning(swap...).Build(ctx)
+	if errors.Is(err, solana.ErrTransactionTooLarge) && len(swap) > 0 && b.tx.AllowSplit {
+		return b.buildSplit(ctx, swap, payout)
 	}
-	builder = b.mintBonus(builder)
-	base64Tx, err := builder.Build(ctx)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to build transaction: %w", err)
 	}
@@ -107,6 +170,122 @@ func (b *PaymentBuilder) Build(ctx context.Context) (string, *Transaction, error
 	return base64Tx, b.tx, nil
 }
 
+// buildSplit builds the swap and the payout as two transactions, the swap first.
+// The payout carries all the references, the burns and the transfer, so the payment
+// is confirmed by it alone: it can't land before the swap provides the destination mint.
+func (b *PaymentBuilder) buildSplit(ctx context.Context, swap []types.Instruction, payout *solana.TransactionBuilder) (string, *Transaction, error) {
+	swapTx, err := b.newTransactionBuilder().AddRawInstructionsToBeginning(swap...).Build(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build swap transaction: %w", err)
+	}
+	payoutTx, err := payout.Build(ctx)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build payout transaction: %w", err)
+	}
+
+	b.tx.Transactions = []string{swapTx, payoutTx}
+
+	return payoutTx, b.tx, nil
+}
+
+// newTransactionBuilder returns a transaction builder paid by the customer,
+// with the compute budget of the config, so the transaction lands during the congestion.
+func (b *PaymentBuilder) newTransactionBuilder() *solana.TransactionBuilder {
+	builder := solana.NewTransactionBuilder(b.sol).
+		SetFeePayer(b.tx.SourceWallet).
+		SetVersion(b.tx.Version).
+		SetComputeUnitLimit(b.config.ComputeUnitLimit).
+		SetComputeUnitPrice(b.config.ComputeUnitPrice)
+	if b.config.AutoPriorityFee {
+		builder = builder.SetPriorityFeeEstimator(b.sol, b.config.PriorityFeePercentile, b.config.MaxComputeUnitPrice)
+	}
+	return builder
+}
+
+// Quote estimates the payment transaction without building it:
+// the exact amount of the source mint to pay, the swap route, the fees and the bonus discount.
+func (b *PaymentBuilder) Quote(ctx context.Context) (*Quote, error) {
+	if err := b.prepare(ctx); err != nil {
+		return nil, err
+	}
+
+	quote := &Quote{
+		PaymentID:       b.tx.PaymentID,
+		SourceWallet:    b.tx.SourceWallet,
+		SourceMint:      b.tx.SourceMint,
+		DestinationMint: b.tx.DestinationMint,
+		InAmount:        b.outAmount(),
+		Amount:          b.tx.Amount,
+		DiscountAmount:  b.tx.DiscountAmount,
+		VoucherAmount:   b.tx.VoucherAmount,
+		TotalAmount:     b.tx.TotalAmount,
+		NetworkFee:      b.signersCount()*lamportsPerSignature + b.config.PriorityFee,
+		Surcharge:       b.tx.Surcharge,
+	}
+
+	if b.tx.SourceMint == b.tx.DestinationMint || b.outAmount() == 0 {
+		return quote, nil
+	}
+
+	route, err := b.bestRoute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var impactErr *PriceImpactError
+	if err := b.checkPriceImpact(route); errors.As(err, &impactErr) {
+		quote.PriceImpactExceeded = true
+	}
+	quote.Route = newSwapRoute(route)
+	quote.InAmount = quote.Route.InAmount
+
+	return quote, nil
+}
+
+// bonusBalance returns the bonus token balance of the source wallet.
+// Tokens of a frozen account can't be burned, so its balance is not available
+// if the frozen accounts check is enabled.
+func (b *PaymentBuilder) bonusBalance(ctx context.Context) uint64 {
+	if b.config.CheckFrozenBonus && b.tx.ApplyBonus {
+		if frozen, err := b.sol.IsTokenAccountFrozen(ctx, b.tx.SourceWallet, b.config.BonusMintAddress); err == nil && frozen {
+			return 0
+		}
+	}
+
+	balance, _ := b.sol.GetTokenBalance(ctx, b.tx.SourceWallet, b.config.BonusMintAddress)
+	return balance.Amount
+}
+
+// prepare validates the builder parameters and calculates the transaction amounts.
+func (b *PaymentBuilder) prepare(ctx context.Context) error {
+	if err := b.validate(); err != nil {
+		return fmt.Errorf("failed to validate builder parameters: %w", err)
+	}
+
+	if err := b.resolveDecimals(ctx); err != nil {
+		return err
+	}
+
+	b.availableBonusAmount = b.toDestinationAmount(b.bonusBalance(ctx))
+	b.tx = b.recalculateTotalAmount(b.tx)
+
+	if err := b.applyVoucher(ctx); err != nil {
+		return err
+	}
+	b.applySurcharge()
+
+	return nil
+}
+
+// signersCount returns the number of the transaction signers.
+func (b *PaymentBuilder) signersCount() uint64 {
+	signers := uint64(1) // fee payer
+	if b.config.AccrueBonus {
+		signers++ // bonus mint authority
+	}
+	return signers
+}
+
 // validate builder parameters.
 func (b *PaymentBuilder) validate() error {
 	if b.tx.SourceWallet == "" {
@@ -127,8 +306,8 @@ func (b *PaymentBuilder) validate() error {
 	if b.tx.ApplyBonus && b.config.BonusMintAddress == "" {
 		return errors.New("bonus mint address is required")
 	}
-	if b.config.AccrueBonus && b.bonusAuthAccount == nil {
-		return errors.New("bonus auth account is required")
+	if b.config.AccrueBonus && b.config.BonusAuthority == nil {
+		return errors.New("bonus authority is required")
 	}
 	if b.config.AccrueBonus && b.config.AccrueBonusRate == 0 {
 		return errors.New("accrue bonus rate is required")
@@ -174,24 +353,145 @@ func (b *PaymentBuilder) recalculateTotalAmount(tx *Transaction) *Transaction {
 	return tx
 }
 
+// applySurcharge grosses up the total amount if the payment fee is on top,
+// so the customer covers the swap slippage. The network and priority fees are not charged,
+// since the customer is the fee payer of the transaction and pays them in SOL anyway.
+func (b *PaymentBuilder) applySurcharge() {
+	if !b.feeOnTop || b.tx.TotalAmount == 0 || b.tx.SourceMint == b.tx.DestinationMint {
+		return
+	}
+
+	surcharge := &Surcharge{
+		Slippage: b.tx.TotalAmount * uint64(b.config.SwapSlippageBps) / 10000,
+	}
+	if surcharge.Slippage == 0 {
+		return
+	}
+
+	b.tx.Surcharge = surcharge
+	b.tx.TotalAmount += surcharge.Total()
+}
+
+// applyVoucher redeems the merchant voucher tokens of the customer as a full or partial payment.
+// Vouchers are redeemed 1:1 for the destination mint, adjusted to the mints decimals.
+func (b *PaymentBuilder) applyVoucher(ctx context.Context) error {
+	if !b.tx.ApplyVoucher || b.tx.TotalAmount == 0 {
+		return nil
+	}
+
+	mint, ok := b.config.VoucherMints[b.merchantWallet]
+	if !ok || mint == "" {
+		return ErrVoucherNotSupported
+	}
+
+	voucherDecimals, err := b.sol.GetMintDecimals(ctx, mint)
+	if err != nil {
+		return fmt.Errorf("failed to get voucher mint decimals: %w", err)
+	}
+	destinationDecimals, err := b.sol.GetMintDecimals(ctx, b.tx.DestinationMint)
+	if err != nil {
+		return fmt.Errorf("failed to get destination mint decimals: %w", err)
+	}
+
+	// no voucher token account means no vouchers to redeem
+	balance, _ := b.sol.GetTokenBalance(ctx, b.tx.SourceWallet, mint)
+	amount := utils.ConvertAmount(balance.Amount, voucherDecimals, destinationDecimals)
+	if amount > b.tx.TotalAmount {
+		amount = b.tx.TotalAmount
+	}
+	// round the redeemed amount to the voucher precision, so the burned tokens cover it exactly
+	burnAmount := utils.ConvertAmount(amount, destinationDecimals, voucherDecimals)
+	amount = utils.ConvertAmount(burnAmount, voucherDecimals, destinationDecimals)
+	if amount == 0 {
+		return nil
+	}
+
+	b.voucherMint = mint
+	b.voucherBurnAmount = burnAmount
+	b.tx.VoucherAmount = amount
+	b.tx.TotalAmount -= amount
+
+	return nil
+}
+
+// resolveDecimals resolves decimals of the bonus and destination mints.
+// It's needed only if bonus is applied or accrued, since the bonus mint
+// may have different decimals than the payment currency.
+func (b *PaymentBuilder) resolveDecimals(ctx context.Context) error {
+	if !b.tx.ApplyBonus && !b.config.AccrueBonus {
+		return nil
+	}
+
+	bonusDecimals, err := b.sol.GetMintDecimals(ctx, b.config.BonusMintAddress)
+	if err != nil {
+		return fmt.Errorf("failed to get bonus mint decimals: %w", err)
+	}
+	destinationDecimals, err := b.sol.GetMintDecimals(ctx, b.tx.DestinationMint)
+	if err != nil {
+		return fmt.Errorf("failed to get destination mint decimals: %w", err)
+	}
+
+	b.bonusDecimals = bonusDecimals
+	b.destinationDecimals = destinationDecimals
+
+	return nil
+}
+
+// toBonusAmount converts amount in the destination mint to the bonus mint amount.
+func (b *PaymentBuilder) toBonusAmount(amount uint64) uint64 {
+	return utils.ConvertAmount(amount, b.destinationDecimals, b.bonusDecimals)
+}
+
+// toDestinationAmount converts amount in the bonus mint to the destination mint amount.
+func (b *PaymentBuilder) toDestinationAmount(amount uint64) uint64 {
+	return utils.ConvertAmount(amount, b.bonusDecimals, b.destinationDecimals)
+}
+
 func (b *PaymentBuilder) burnBonus(builder *solana.TransactionBuilder) *solana.TransactionBuilder {
 	if !b.tx.ApplyBonus || b.tx.DiscountAmount == 0 {
 		return builder
 	}
 
-	return builder.AddInstruction(solana.BurnToken(solana.BurnTokenParams{
-		Mint:              b.config.BonusMintAddress,
-		TokenAccountOwner: b.tx.SourceWallet,
-		Amount:            b.tx.DiscountAmount,
+	return builder.AddInstruction(solana.BurnTokenChecked(solana.BurnTokenCheckedParams{
+		BurnTokenParams: solana.BurnTokenParams{
+			Mint:              b.config.BonusMintAddress,
+			TokenAccountOwner: b.tx.SourceWallet,
+			Reference:         b.newReference(),
+			Amount:            b.toBonusAmount(b.tx.DiscountAmount),
+		},
+		Decimals: b.bonusDecimals,
 	}))
 }
 
+// burnVoucher burns the redeemed voucher tokens.
+// If the payment is fully covered by the voucher, the primary reference is attached to the burn instruction,
+// since there is no transfer to the merchant. Otherwise, it carries an additional reference.
+func (b *PaymentBuilder) burnVoucher(builder *solana.TransactionBuilder) *solana.TransactionBuilder {
+	if b.voucherBurnAmount == 0 {
+		return builder
+	}
+
+	params := solana.BurnTokenParams{
+		Mint:              b.voucherMint,
+		TokenAccountOwner: b.tx.SourceWallet,
+		Amount:            b.voucherBurnAmount,
+	}
+	if b.tx.TotalAmount == 0 {
+		params.Reference = b.tx.Reference
+	} else {
+		params.Reference = b.newReference()
+	}
+
+	return builder.AddInstruction(solana.BurnToken(params))
+}
+
 func (b *PaymentBuilder) mintBonus(builder *solana.TransactionBuilder) *solana.TransactionBuilder {
 	if !b.config.AccrueBonus {
 		return builder
 	}
 
-	bonusAmount := b.tx.TotalAmount * b.config.AccrueBonusRate / 10000
+	// the surcharge covers fees, so it's not a subject to accrue bonus
+	bonusAmount := b.toBonusAmount((b.tx.TotalAmount - b.tx.Surcharge.Total()) * b.config.AccrueBonusRate / 10000)
 	if bonusAmount == 0 {
 		return builder
 	}
@@ -201,10 +501,10 @@ func (b *PaymentBuilder) mintBonus(builder *solana.TransactionBuilder) *solana.T
 	return builder.AddInstruction(solana.MintFungibleToken(solana.MintFungibleTokenParams{
 		Funder:    b.tx.SourceWallet,
 		Mint:      b.config.BonusMintAddress,
-		MintOwner: b.bonusAuthAccount.PublicKey.ToBase58(),
+		MintOwner: b.config.BonusAuthority.PublicKey().ToBase58(),
 		MintTo:    b.tx.SourceWallet,
 		Amount:    bonusAmount,
-	})).AddSigner(*b.bonusAuthAccount)
+	})).AddExternalSigner(b.config.BonusAuthority)
 }
 
 func (b *PaymentBuilder) transferToken(builder *solana.TransactionBuilder) *solana.TransactionBuilder {
@@ -226,25 +526,107 @@ func (b *PaymentBuilder) transferSOL(builder *solana.TransactionBuilder) *solana
 	}))
 }
 
-func (b *PaymentBuilder) swap(builder *solana.TransactionBuilder) (*solana.TransactionBuilder, error) {
-	if b.tx.SourceMint == b.tx.DestinationMint {
-		return builder, nil
+// transferTip transfers the tip to the tip destination wallet, in the destination mint.
+// The transfer carries its own reference, so the tip is not mixed with the payment transfer.
+func (b *PaymentBuilder) transferTip(builder *solana.TransactionBuilder) *solana.TransactionBuilder {
+	if b.tx.TipAmount == 0 {
+		return builder
+	}
+
+	if IsSOL(b.tx.DestinationMint) {
+		return builder.AddInstruction(solana.TransferSOL(solana.TransferSOLParams{
+			Sender:    b.tx.SourceWallet,
+			Recipient: b.tx.TipDestination,
+			Reference: b.newReference(),
+			Amount:    b.tx.TipAmount,
+		}))
+	}
+
+	return builder.AddInstruction(solana.TransferToken(solana.TransferTokenParam{
+		Sender:    b.tx.SourceWallet,
+		Recipient: b.tx.TipDestination,
+		Mint:      b.tx.DestinationMint,
+		Reference: b.newReference(),
+		Amount:    b.tx.TipAmount,
+	}))
+}
+
+// outAmount returns the amount of the destination mint paid with the transaction: the total amount and the tip.
+func (b *PaymentBuilder) outAmount() uint64 {
+	return b.tx.TotalAmount + b.tx.TipAmount
+}
+
+// swap returns the Jupiter swap instructions of the source mint to the destination mint,
+// none if the customer pays with the destination mint.
+func (b *PaymentBuilder) swap(ctx context.Context) ([]types.Instruction, error) {
+	if b.tx.SourceMint == b.tx.DestinationMint || b.outAmount() == 0 {
+		return nil, nil
 	}
 
-	jupTx, err := b.jup.BestSwap(jupiter.BestSwapParams{
-		UserPublicKey: b.tx.SourceWallet,
-		InputMint:     b.tx.SourceMint,
-		OutputMint:    b.tx.DestinationMint,
-		Amount:        b.tx.TotalAmount,
+	route, err := b.bestRoute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !b.tx.AcceptPriceImpact {
+		if err := b.checkPriceImpact(route); err != nil {
+			return nil, err
+		}
+	}
+
+	jupTx, err := b.jup.Swap(ctx, jupiter.SwapParams{
+		Route:               route,
+		UserPublicKey:       b.tx.SourceWallet,
+		WrapUnwrapSol:       utils.Pointer(true),
+		AsLegacyTransaction: utils.Pointer(true),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get best swap transaction: %w", err)
 	}
+	b.tx.Route = newSwapRoute(route)
 
 	jtx, err := solana.DecodeTransaction(jupTx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode jupiter transaction: %w", err)
 	}
 
-	return builder.AddRawInstructionsToBeginning(jtx.Message.DecompileInstructions()...), nil
+	return jtx.Message.DecompileInstructions(), nil
+}
+
+// bestRoute returns the best Jupiter route to swap the source mint to exactly the transaction total amount
+// and the tip of the destination mint, so the swap built for the transaction is the one quoted by Quote.
+func (b *PaymentBuilder) bestRoute(ctx context.Context) (jupiter.Route, error) {
+	routes, err := b.jup.Quote(ctx, jupiter.QuoteParams{
+		InputMint:  b.tx.SourceMint,
+		OutputMint: b.tx.DestinationMint,
+		Amount:     b.outAmount(),
+		SwapMode:   jupiter.SwapModeExactOut,
+	})
+	if err != nil {
+		return jupiter.Route{}, fmt.Errorf("failed to get swap quote: %w", err)
+	}
+	route, err := routes.GetBestRoute()
+	if err != nil {
+		return jupiter.Route{}, fmt.Errorf("failed to get best swap route: %w", err)
+	}
+
+	return route, nil
+}
+
+// checkPriceImpact returns PriceImpactError if the price impact of the route
+// exceeds the merchant limit, so the customers are not silently routed through illiquid pairs.
+// Jupiter reports the price impact as a fraction, e.g. 0.01 = 1%.
+func (b *PaymentBuilder) checkPriceImpact(route jupiter.Route) error {
+	if b.config.MaxPriceImpactBps == 0 {
+		return nil
+	}
+
+	impactBps := route.PriceImpactPct * 10000
+	if impactBps <= float64(b.config.MaxPriceImpactBps) {
+		return nil
+	}
+
+	return &PriceImpactError{
+		ImpactPct: route.PriceImpactPct * 100,
+		MaxPct:    float64(b.config.MaxPriceImpactBps) / 100,
+	}
 }
@@ -0,0 +1,41 @@
+package payments
+
+import "net/url"
+
+// Universal links of the mobile wallets opening a web page in their in-app browser,
+// where the page can connect the wallet, e.g. the hosted checkout page.
+const (
+	phantomBrowseLink  = "https://phantom.app/ul/browse/"
+	solflareBrowseLink = "https://solflare.com/ul/v1/browse/"
+)
+
+// WalletDeepLinks are the links opening the payment in the mobile wallet apps,
+// so the mobile checkout doesn't rely on scanning the QR code.
+type WalletDeepLinks struct {
+	Phantom  string `json:"phantom,omitempty"`  // opens the checkout page in the Phantom in-app browser
+	Solflare string `json:"solflare,omitempty"` // opens the checkout page in the Solflare in-app browser
+	// Fallback is the Solana Pay link itself, opened by any installed wallet handling the solana: scheme.
+	Fallback string `json:"fallback"`
+}
+
+// NewWalletDeepLinks returns the deep links of the Solana Pay link and the checkout page of the payment.
+// The universal links can't carry the Solana Pay link, they open the page instead,
+// so they are omitted if the page URL is empty.
+func NewWalletDeepLinks(link, pageURL string) WalletDeepLinks {
+	result := WalletDeepLinks{Fallback: link}
+	if pageURL == "" {
+		return result
+	}
+
+	// the ref is the origin shown by the wallet
+	ref := pageURL
+	if u, err := url.Parse(pageURL); err == nil && u.Scheme != "" && u.Host != "" {
+		ref = u.Scheme + "://" + u.Host
+	}
+	query := "?ref=" + url.QueryEscape(ref)
+
+	result.Phantom = phantomBrowseLink + url.QueryEscape(pageURL) + query
+	result.Solflare = solflareBrowseLink + url.QueryEscape(pageURL) + query
+
+	return result
+}
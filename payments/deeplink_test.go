@@ -0,0 +1,28 @@
+package payments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWalletDeepLinks(t *testing.T) {
+	link := "solana:https://api.example.com/payment/checkout/3f1c0d7e-5a3b-4e9a-9f41-0c2d7b6e8a10/SOL/false"
+
+	t.Run("with checkout page", func(t *testing.T) {
+		links := NewWalletDeepLinks(link, "https://api.example.com/checkout/3f1c0d7e-5a3b-4e9a-9f41-0c2d7b6e8a10?locale=de")
+		require.Equal(t, WalletDeepLinks{
+			Phantom: "https://phantom.app/ul/browse/" +
+				"https%3A%2F%2Fapi.example.com%2Fcheckout%2F3f1c0d7e-5a3b-4e9a-9f41-0c2d7b6e8a10%3Flocale%3Dde" +
+				"?ref=https%3A%2F%2Fapi.example.com",
+			Solflare: "https://solflare.com/ul/v1/browse/" +
+				"https%3A%2F%2Fapi.example.com%2Fcheckout%2F3f1c0d7e-5a3b-4e9a-9f41-0c2d7b6e8a10%3Flocale%3Dde" +
+				"?ref=https%3A%2F%2Fapi.example.com",
+			Fallback: link,
+		}, links)
+	})
+
+	t.Run("without checkout page", func(t *testing.T) {
+		require.Equal(t, WalletDeepLinks{Fallback: link}, NewWalletDeepLinks(link, ""))
+	})
+}
@@ -0,0 +1,251 @@
+package payments
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/internal/hdkey"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/google/uuid"
+	"github.com/portto/solana-go-sdk/types"
+)
+
+// DepositDeriver derives the keys of the payment deposit addresses from the server seed,
+// so the keys are never stored and the deposits can be swept by re-deriving them.
+//
+// The key of the payment is the SLIP-0010 path m/<payment id>', the uuid split into four 31-bit indices.
+// The seed must differ from the reference seed: the reference keys are public, the deposit keys hold funds.
+type DepositDeriver struct {
+	master *hdkey.Key
+}
+
+// NewDepositDeriver creates a new deposit key deriver from the seed of at least 16 bytes.
+func NewDepositDeriver(seed []byte) (*DepositDeriver, error) {
+	master, err := hdkey.NewMaster(seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive master key: %w", err)
+	}
+	return &DepositDeriver{master: master}, nil
+}
+
+// Account returns the deposit account of the payment.
+func (d *DepositDeriver) Account(paymentID uuid.UUID) (types.Account, error) {
+	return types.AccountFromSeed(d.master.Derive(uuidPath(paymentID)...).PrivateKey().Seed())
+}
+
+// CreateDepositAddress returns the dedicated deposit address of the payment, creating it on the first call.
+// Unlike the generated transactions, the transfer to the address needs no reference accounts,
+// so it can be made by an exchange withdrawal. The address is watched as a pending transaction
+// of the payment, so the transfer of the exact amount before the payment expires completes the payment.
+func (s *Service) CreateDepositAddress(ctx context.Context, paymentID uuid.UUID) (*DepositAddress, error) {
+	conf := s.config()
+	if conf.DepositDeriver == nil || conf.DepositFeePayer == nil {
+		return nil, ErrDepositAddressNotSupported
+	}
+
+	payment, err := s.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if payment.Status != PaymentStatusNew && payment.Status != PaymentStatusPending {
+		return nil, fmt.Errorf("payment already %s", payment.Status)
+	}
+	if payment.Expired() {
+		return nil, ErrPaymentExpired
+	}
+	// the transfer to the address can't be priced at the rate of the moment it's made
+	if payment.FiatCurrency != "" {
+		return nil, fmt.Errorf("%w: deposit addresses", ErrFiatPricedPayment)
+	}
+
+	deposit, err := s.repo.GetDepositAddressByPaymentID(ctx, paymentID)
+	switch {
+	case err == nil:
+	case errors.Is(err, sql.ErrNoRows):
+		deposit, err = s.newDepositAddress(ctx, conf, payment)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("failed to get deposit address: %w", err)
+	}
+
+	if err := s.ensureDepositTransaction(ctx, payment, deposit); err != nil {
+		return nil, err
+	}
+
+	return castFromRepositoryDepositAddress(deposit, payment), nil
+}
+
+// newDepositAddress derives and stores the deposit address of the payment.
+// The address of a concurrent request wins, so the payment has a single address.
+func (s *Service) newDepositAddress(ctx context.Context, conf Config, payment *Payment) (repository.PaymentDepositAddress, error) {
+	account, err := conf.DepositDeriver.Account(payment.ID)
+	if err != nil {
+		return repository.PaymentDepositAddress{}, fmt.Errorf("failed to derive deposit account: %w", err)
+	}
+
+	mint := MintAddress(payment.DestinationMint, conf.DestinationMint)
+	wallet := account.PublicKey.ToBase58()
+	tokenAccount := wallet
+	if !IsSOL(mint) {
+		tokenAccount, err = solana.AssociatedTokenAddress(wallet, mint)
+		if err != nil {
+			return repository.PaymentDepositAddress{}, fmt.Errorf("failed to derive deposit token account: %w", err)
+		}
+	}
+
+	deposit, err := s.repo.CreateDepositAddress(ctx, repository.CreateDepositAddressParams{
+		PaymentID:     payment.ID,
+		TransactionID: uuid.New(),
+		Wallet:        wallet,
+		TokenAccount:  tokenAccount,
+		Mint:          mint,
+		Amount:        int64(payment.Amount),
+	})
+	if err != nil {
+		if repository.IsUniqueViolation(err, "") {
+			deposit, err = s.repo.GetDepositAddressByPaymentID(ctx, payment.ID)
+			if err != nil {
+				return repository.PaymentDepositAddress{}, fmt.Errorf("failed to get deposit address: %w", err)
+			}
+			return deposit, nil
+		}
+		return repository.PaymentDepositAddress{}, fmt.Errorf("failed to create deposit address: %w", err)
+	}
+
+	return deposit, nil
+}
+
+// ensureDepositTransaction creates the pending transaction watching the deposit address, if it's missing,
+// e.g. the previous call failed after the address was stored.
+// The token account is the reference: it's the only account of the transfer known in advance,
+// the wallet itself is not included in the token transfers.
+func (s *Service) ensureDepositTransaction(ctx context.Context, payment *Payment, deposit repository.PaymentDepositAddress) error {
+	_, err := s.repo.GetTransactionByReference(ctx, deposit.TokenAccount)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get deposit transaction: %w", err)
+	}
+
+	if _, err := s.repo.CreateTransaction(ctx, repository.CreateTransactionParams{
+		ID:                uuid.NullUUID{UUID: deposit.TransactionID, Valid: true},
+		PaymentID:         payment.ID,
+		Reference:         deposit.TokenAccount,
+		SourceMint:        deposit.Mint,
+		DestinationWallet: deposit.Wallet,
+		DestinationMint:   deposit.Mint,
+		Amount:            deposit.Amount,
+		TotalAmount:       deposit.Amount,
+		Message:           sql.NullString{String: payment.Message, Valid: payment.Message != ""},
+		ApplyBonus:        sql.NullBool{Bool: false, Valid: true},
+		Status:            repository.TransactionStatusPending,
+	}); err != nil {
+		return fmt.Errorf("failed to create deposit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SweepDepositAddresses transfers the funds of the paid deposit addresses to the merchant wallet,
+// or to the escrow wallet if the payment is held in escrow.
+// The transfers are paid by the deposit fee payer, so the addresses need no SOL.
+func (s *Service) SweepDepositAddresses(ctx context.Context) error {
+	conf := s.config()
+	if conf.DepositDeriver == nil || conf.DepositFeePayer == nil {
+		return nil
+	}
+
+	deposits, err := s.repo.GetDepositAddressesToSweep(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get deposit addresses to sweep: %w", err)
+	}
+
+	var lastErr error
+	for _, d := range deposits {
+		if err := s.sweepDepositAddress(ctx, conf, d); err != nil {
+			lastErr = fmt.Errorf("failed to sweep deposit address of payment %s: %w", d.PaymentID, err)
+		}
+	}
+
+	return lastErr
+}
+
+// sweepDepositAddress sends the deposit to the payment destination and stores the sweep signature.
+// The concurrent sweeps of the same address can't transfer the funds twice: the second one has nothing to send.
+func (s *Service) sweepDepositAddress(ctx context.Context, conf Config, d repository.PaymentDepositAddress) error {
+	account, err := conf.DepositDeriver.Account(d.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to derive deposit account: %w", err)
+	}
+	if account.PublicKey.ToBase58() != d.Wallet {
+		return ErrDepositAddressMismatched
+	}
+
+	payment, err := s.GetPayment(ctx, d.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+	recipient := payment.DestinationWallet
+	if payment.Escrow && conf.EscrowSigner != nil {
+		recipient = conf.EscrowSigner.PublicKey().ToBase58()
+	}
+
+	sol := s.solanaFor(payment.Livemode)
+	feePayer := conf.DepositFeePayer.PublicKey().ToBase58()
+	builder := solana.NewTransactionBuilder(sol).SetFeePayer(feePayer)
+	if IsSOL(d.Mint) {
+		builder = builder.AddInstruction(solana.TransferSOL(solana.TransferSOLParams{
+			Sender:    d.Wallet,
+			Recipient: recipient,
+			Amount:    uint64(d.Amount),
+		}))
+	} else {
+		builder = builder.AddInstruction(solana.TransferToken(solana.TransferTokenParam{
+			Sender:    d.Wallet,
+			Recipient: recipient,
+			Mint:      d.Mint,
+			Amount:    uint64(d.Amount),
+		}))
+	}
+	builder = builder.AddSigner(account).AddExternalSigner(conf.DepositFeePayer)
+
+	// the sweep is rebuilt with a fresh blockhash if it's expired before landing
+	signature, err := solana.NewSender(sol).Send(ctx, builder.Build)
+	if err != nil {
+		return fmt.Errorf("failed to send sweep transaction: %w", err)
+	}
+
+	if _, err := s.repo.MarkDepositAddressSwept(ctx, repository.MarkDepositAddressSweptParams{
+		SweepSignature: sql.NullString{String: signature, Valid: true},
+		PaymentID:      d.PaymentID,
+	}); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to store sweep signature %s: %w", signature, err)
+	}
+
+	return nil
+}
+
+// castFromRepositoryDepositAddress converts the stored deposit address of the payment.
+func castFromRepositoryDepositAddress(d repository.PaymentDepositAddress, payment *Payment) *DepositAddress {
+	result := &DepositAddress{
+		PaymentID:      d.PaymentID,
+		Address:        d.Wallet,
+		Mint:           d.Mint,
+		Amount:         uint64(d.Amount),
+		ExpiresAt:      payment.ExpiresAt,
+		SweepSignature: d.SweepSignature.String,
+	}
+	if d.TokenAccount != d.Wallet {
+		result.TokenAccount = d.TokenAccount
+	}
+	if d.SweptAt.Valid {
+		result.SweptAt = &d.SweptAt.Time
+	}
+	return result
+}
@@ -3,6 +3,7 @@ package payments
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -101,3 +102,16 @@ func (e *Enqueuer) CheckPaymentByReference(ctx context.Context, reference string
 
 	return nil
 }
+
+// SweepDepositAddresses enqueues a task to sweep the paid deposit addresses.
+// The task which is already enqueued is not duplicated.
+func (e *Enqueuer) SweepDepositAddresses(ctx context.Context) error {
+	if err := e.enqueueTask(ctx, asynq.NewTask(TaskSweepDepositAddresses, nil)); err != nil {
+		if errors.Is(err, asynq.ErrDuplicateTask) {
+			return nil
+		}
+		return fmt.Errorf("SweepDepositAddresses: %w", err)
+	}
+
+	return nil
+}
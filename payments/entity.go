@@ -1,9 +1,13 @@
 package payments
 
 import (
+	"encoding/json"
+	"strconv"
 	"time"
 
+	"github.com/easypmnt/checkout-api/jupiter"
 	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/solana"
 	"github.com/google/uuid"
 )
 
@@ -18,6 +22,11 @@ const (
 	PaymentStatusFailed    PaymentStatus = "failed"
 	PaymentStatusCanceled  PaymentStatus = "canceled"
 	PaymentStatusExpired   PaymentStatus = "expired"
+	PaymentStatusHeld      PaymentStatus = "held"     // paid to the escrow wallet, waiting for release
+	PaymentStatusReleased  PaymentStatus = "released" // released from the escrow wallet to the merchant
+	PaymentStatusDisputed  PaymentStatus = "disputed" // frozen until the dispute is resolved
+
+	PaymentStatusPartiallyPaid PaymentStatus = "partially_paid" // paid in part, waiting for the top-up transactions
 )
 
 // TransactionStatus represents the status of a transaction.
@@ -28,24 +37,79 @@ const (
 	TransactionStatusPending   TransactionStatus = "pending"
 	TransactionStatusCompleted TransactionStatus = "completed"
 	TransactionStatusFailed    TransactionStatus = "failed"
+	TransactionStatusExpired   TransactionStatus = "expired" // the payment is expired or settled by another attempt
 )
 
 // Payment represents an initial payment request.
 type Payment struct {
-	ID                uuid.UUID     `json:"id,omitempty"`
-	ExternalID        string        `json:"external_id,omitempty"`
-	DestinationWallet string        `json:"destination_wallet,omitempty"`
-	DestinationMint   string        `json:"destination_mint,omitempty"`
-	Amount            uint64        `json:"amount,omitempty"`
-	Status            PaymentStatus `json:"status,omitempty"`
-	Message           string        `json:"message,omitempty"`
-	ExpiresAt         *time.Time    `json:"expires_at,omitempty"`
+	ID                uuid.UUID              `json:"id,omitempty"`
+	ExternalID        string                 `json:"external_id,omitempty"`
+	DestinationWallet string                 `json:"destination_wallet,omitempty"`
+	DestinationMint   string                 `json:"destination_mint,omitempty"`
+	Amount            uint64                 `json:"amount,omitempty"`
+	Status            PaymentStatus          `json:"status,omitempty"`
+	Message           string                 `json:"message,omitempty"`
+	CustomerEmail     string                 `json:"customer_email,omitempty"`
+	Translations      map[string]Translation `json:"translations,omitempty"`
+	FeeOnTop          bool                   `json:"fee_on_top,omitempty"` // customer covers network, priority and swap fees
+	Escrow            bool                   `json:"escrow,omitempty"`     // funds are held in the escrow wallet until released
+	ExpiresAt         *time.Time             `json:"expires_at,omitempty"`
+	HeldUntil         *time.Time             `json:"held_until,omitempty"` // escrow auto-release time, if any
+	Livemode          bool                   `json:"livemode"`             // false for the test-mode payments, see WithLivemode
+	// CallbackURL receives the payment webhooks in addition to the merchant webhook.
+	CallbackURL string `json:"callback_url,omitempty"`
+	// AllowPartial allows to pay the payment with several transactions, each one paying a part of the amount.
+	AllowPartial bool `json:"allow_partial,omitempty"`
+	// AmountPaid is the sum of the completed transactions of the payment.
+	AmountPaid uint64 `json:"amount_paid"`
+	// FiatCurrency is the currency of the fiat-priced payment, e.g. USD, see WithExchangeRates.
+	// The FiatAmount is converted to the destination mint when the transaction is built,
+	// the Amount is the converted amount of the latest transaction, zero until the first one.
+	FiatCurrency string `json:"fiat_currency,omitempty"`
+	// FiatAmount is the price of the fiat-priced payment in the minor units of the currency, e.g. cents.
+	FiatAmount uint64 `json:"fiat_amount,omitempty"`
+	// Settings override the merchant settings for this payment only, nil if the merchant settings are used.
+	Settings *PaymentSettings `json:"settings,omitempty"`
+}
+
+// AmountDue returns the part of the amount which is not paid yet.
+func (p *Payment) AmountDue() uint64 {
+	if p.AmountPaid >= p.Amount {
+		return 0
+	}
+	return p.Amount - p.AmountPaid
+}
+
+// Payable reports whether a new transaction can be built for the payment:
+// it's not paid yet or paid in part.
+func (p *Payment) Payable() bool {
+	switch p.Status {
+	case PaymentStatusNew, PaymentStatusPending, PaymentStatusPartiallyPaid:
+		return true
+	}
+	return false
+}
+
+// Settled reports whether the payment is paid, e.g. by one of its attempts.
+func (p *Payment) Settled() bool {
+	switch p.Status {
+	case PaymentStatusCompleted, PaymentStatusHeld, PaymentStatusReleased, PaymentStatusDisputed:
+		return true
+	}
+	return false
+}
+
+// Expired reports whether the payment expiration time has passed.
+// New transactions can't be built for the expired payment, even if it's not marked as expired yet.
+func (p *Payment) Expired() bool {
+	return p.ExpiresAt != nil && time.Now().After(*p.ExpiresAt)
 }
 
 type Transaction struct {
 	ID                 uuid.UUID         `json:"id,omitempty"`
 	PaymentID          uuid.UUID         `json:"payment_id,omitempty"`
 	Reference          string            `json:"reference,omitempty"`
+	References         []string          `json:"references,omitempty"` // additional references, the transaction is verified against any of them
 	SourceWallet       string            `json:"source_wallet,omitempty"`
 	SourceMint         string            `json:"source_mint,omitempty"`
 	DestinationWallet  string            `json:"destination_wallet,omitempty"`
@@ -57,9 +121,238 @@ type Transaction struct {
 	Message            string            `json:"message,omitempty"`
 	Memo               string            `json:"memo,omitempty"`
 	ApplyBonus         bool              `json:"apply_bonus,omitempty"`
+	ApplyVoucher       bool              `json:"apply_voucher,omitempty"`
+	VoucherAmount      uint64            `json:"voucher_amount,omitempty"`
+	Locale             string            `json:"-"` // Locale is used to localize the transaction message, not stored.
+	AcceptPriceImpact  bool              `json:"-"` // the customer accepted the swap price impact above the limit, not stored.
 	Transaction        string            `json:"transaction,omitempty"`
 	Status             TransactionStatus `json:"status,omitempty"`
 	Signature          string            `json:"signature,omitempty"`
+	Surcharge          *Surcharge        `json:"surcharge,omitempty"`
+	Route              *SwapRoute        `json:"route,omitempty"` // nil if no swap is needed
+
+	// Version is the serialization format of the built transaction requested by the wallet, not stored.
+	// Empty means legacy: the older wallets can't deserialize the versioned transactions.
+	Version solana.TransactionVersion `json:"-"`
+	// LinkToken is the single-use access token of the payment link the transaction is requested with, not stored.
+	// It's used up once the transaction is built.
+	LinkToken string `json:"-"`
+	// AllowSplit allows to build the payment as a set of transactions if it doesn't fit in one, not stored.
+	// Solana Pay wallets handle a single transaction, so the client must sign and send the whole set.
+	AllowSplit bool `json:"-"`
+	// Transactions is the ordered set of the transactions to send one after another, if the payment is split.
+	// The last one is the payout, the Transaction.
+	Transactions []string `json:"transactions,omitempty"`
+	// PartialAmount is the amount to pay with the transaction if the payment allows partial payments, not stored.
+	// Zero or the amount above the amount due means the whole amount due.
+	PartialAmount uint64 `json:"-"`
+	// ExchangeRate is the price of the whole destination token in the fiat currency of the payment
+	// the transaction amount is converted at, empty if the payment is not fiat-priced.
+	ExchangeRate string `json:"exchange_rate,omitempty"`
+	// TipAmount is the tip paid on top of the total amount, in the destination mint.
+	// It's transferred by a separate instruction, so it's not a part of the amount due or the bonus accrual.
+	TipAmount uint64 `json:"tip_amount,omitempty"`
+	// TipDestination is the wallet receiving the tip, the transaction destination wallet if empty.
+	TipDestination string `json:"tip_destination,omitempty"`
+}
+
+// PaymentAttempt is a transaction generated for the payment by one of its payers,
+// e.g. several wallets splitting a bill or a retry from another device.
+// The first confirmed attempt settles the payment, the pending ones are expired.
+type PaymentAttempt struct {
+	Reference    string            `json:"reference"`
+	SourceWallet string            `json:"source_wallet"`
+	SourceMint   string            `json:"source_mint"`
+	TotalAmount  uint64            `json:"total_amount"`
+	Status       TransactionStatus `json:"status"`
+	Signature    string            `json:"signature,omitempty"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    *time.Time        `json:"updated_at,omitempty"`
+}
+
+// ReceivedAmount returns the amount the destination wallet receives with the transaction:
+// the total amount and the tip, if the tip is paid to the same wallet.
+func (t *Transaction) ReceivedAmount() uint64 {
+	if t.TipAmount > 0 && t.TipDestination == t.DestinationWallet {
+		return t.TotalAmount + t.TipAmount
+	}
+	return t.TotalAmount
+}
+
+// AllReferences returns the primary and the additional references of the transaction.
+func (t *Transaction) AllReferences() []string {
+	return append([]string{t.Reference}, t.References...)
+}
+
+// Surcharge is the itemized amount added on top of the payment amount
+// if the customer covers the fees. All amounts are in the destination mint.
+// The network and priority fees are paid by the customer as the transaction fee payer,
+// so they are not a part of the surcharge.
+type Surcharge struct {
+	Slippage uint64 `json:"slippage"`
+}
+
+// Total returns the total surcharge amount.
+func (s *Surcharge) Total() uint64 {
+	if s == nil {
+		return 0
+	}
+	return s.Slippage
+}
+
+// Quote is an estimate of the checkout total for the given customer wallet and currency.
+type Quote struct {
+	PaymentID       uuid.UUID  `json:"payment_id"`
+	SourceWallet    string     `json:"source_wallet"`
+	SourceMint      string     `json:"source_mint"`
+	DestinationMint string     `json:"destination_mint"`
+	InAmount        uint64     `json:"in_amount"` // exact amount of the source mint to pay
+	Amount          uint64     `json:"amount"`
+	DiscountAmount  uint64     `json:"discount_amount,omitempty"` // bonus discount, in the destination mint
+	VoucherAmount   uint64     `json:"voucher_amount,omitempty"`  // redeemed voucher amount, in the destination mint
+	TotalAmount     uint64     `json:"total_amount"`              // amount to be received, in the destination mint
+	NetworkFee      uint64     `json:"network_fee"`               // estimated network and priority fees, in lamports
+	Surcharge       *Surcharge `json:"surcharge,omitempty"`
+	Route           *SwapRoute `json:"route,omitempty"` // nil if no swap is needed
+	ExpiresAt       time.Time  `json:"expires_at"`
+
+	// PriceImpactExceeded is set if the swap price impact is above the merchant limit,
+	// so the transaction can be generated only if the customer accepts the price impact.
+	PriceImpactExceeded bool `json:"price_impact_exceeded,omitempty"`
+}
+
+// WalletToken is a token held by the customer wallet that can be used to pay for the payment.
+type WalletToken struct {
+	Mint       string         `json:"mint"`
+	Balance    solana.Balance `json:"balance"`
+	InAmount   uint64         `json:"in_amount"`  // estimated amount of the mint to pay
+	Sufficient bool           `json:"sufficient"` // wallet balance covers the estimated amount
+}
+
+// TransactionRecheck is the result of the on-chain re-verification of a transaction.
+type TransactionRecheck struct {
+	Reference      string                 `json:"reference"`
+	PreviousStatus TransactionStatus      `json:"previous_status"`
+	Status         TransactionStatus      `json:"status"`
+	Updated        bool                   `json:"updated"` // the stored status is fixed to match the chain
+	Match          *solana.ReferenceMatch `json:"match"`
+}
+
+// EscrowRelease is the transfer of the held payment funds from the escrow wallet to the merchant.
+type EscrowRelease struct {
+	PaymentID uuid.UUID `json:"payment_id"`
+	Recipient string    `json:"recipient"`
+	Mint      string    `json:"mint"`
+	Amount    uint64    `json:"amount"`
+	Signature string    `json:"signature,omitempty"` // empty if there is nothing to transfer, e.g. the payment is covered by a voucher
+}
+
+// DepositAddress is the dedicated address of the payment, e.g. for the exchange withdrawals,
+// which can't include the reference accounts. The payment is paid by a transfer of the exact amount
+// to the address before the payment expires, then the funds are swept to the merchant wallet.
+type DepositAddress struct {
+	PaymentID      uuid.UUID  `json:"payment_id"`
+	Address        string     `json:"address"`                 // wallet address to send the funds to
+	TokenAccount   string     `json:"token_account,omitempty"` // associated token account of the address, if the mint is a token
+	Mint           string     `json:"mint"`
+	Amount         uint64     `json:"amount"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	SweepSignature string     `json:"sweep_signature,omitempty"`
+	SweptAt        *time.Time `json:"swept_at,omitempty"`
+}
+
+// TransferRequest is the Solana Pay transfer-request link of the payment, for the wallets
+// which don't support the transaction requests, and the pending transaction it's watched as.
+type TransferRequest struct {
+	Link        string       `json:"link"`
+	Transaction *Transaction `json:"transaction"`
+}
+
+// PaymentStatusInfo is the lightweight payment status for the high-frequency polling by checkout pages.
+type PaymentStatusInfo struct {
+	Status    PaymentStatus `json:"status"`
+	Signature string        `json:"signature,omitempty"` // signature of the latest transaction, if any
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// SwapRoute is a summary of the swap route from the source mint to the destination mint,
+// so the wallets can show why the amount to pay differs from the payment amount.
+type SwapRoute struct {
+	SwapMode       string     `json:"swap_mode,omitempty"`
+	InAmount       uint64     `json:"in_amount"`
+	OutAmount      uint64     `json:"out_amount"`
+	MinOutAmount   uint64     `json:"min_out_amount,omitempty"` // minimum received with the slippage, ExactIn swaps only
+	MaxInAmount    uint64     `json:"max_in_amount,omitempty"`  // maximum paid with the slippage, ExactOut swaps only
+	PriceImpactPct float64    `json:"price_impact_pct"`
+	SlippageBps    int64      `json:"slippage_bps"`
+	Markets        []string   `json:"markets,omitempty"`
+	Hops           []*SwapHop `json:"hops,omitempty"`
+}
+
+// SwapHop is a single market of the swap route.
+type SwapHop struct {
+	Market         string  `json:"market"`
+	InputMint      string  `json:"input_mint"`
+	OutputMint     string  `json:"output_mint"`
+	InAmount       uint64  `json:"in_amount"`
+	OutAmount      uint64  `json:"out_amount"`
+	PriceImpactPct float64 `json:"price_impact_pct"`
+}
+
+// newSwapRoute creates a swap route summary from the Jupiter route.
+func newSwapRoute(route jupiter.Route) *SwapRoute {
+	result := &SwapRoute{
+		SwapMode:       route.SwapMode,
+		PriceImpactPct: route.PriceImpactPct,
+		SlippageBps:    route.SlippageBps,
+	}
+	result.InAmount, _ = strconv.ParseUint(route.InAmount, 10, 64)
+	result.OutAmount, _ = strconv.ParseUint(route.OutAmount, 10, 64)
+
+	threshold, _ := strconv.ParseUint(route.OtherAmountThreshold, 10, 64)
+	if route.SwapMode == jupiter.SwapModeExactOut {
+		result.MaxInAmount = threshold
+	} else {
+		result.MinOutAmount = threshold
+	}
+
+	for _, m := range route.MarketInfos {
+		result.Markets = append(result.Markets, m.Label)
+
+		hop := &SwapHop{
+			Market:         m.Label,
+			InputMint:      m.InputMint,
+			OutputMint:     m.OutputMint,
+			PriceImpactPct: m.PriceImpactPct,
+		}
+		hop.InAmount, _ = strconv.ParseUint(m.InAmount, 10, 64)
+		hop.OutAmount, _ = strconv.ParseUint(m.OutAmount, 10, 64)
+		result.Hops = append(result.Hops, hop)
+	}
+	return result
+}
+
+// marshalSwapRoute encodes the swap route to be stored in the database.
+func marshalSwapRoute(route *SwapRoute) (json.RawMessage, error) {
+	if route == nil {
+		return json.RawMessage("{}"), nil
+	}
+	return json.Marshal(route)
+}
+
+// unmarshalSwapRoute decodes the swap route stored in the database.
+// Invalid data is ignored, since the route is informational only.
+func unmarshalSwapRoute(data json.RawMessage) *SwapRoute {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var result SwapRoute
+	if err := json.Unmarshal(data, &result); err != nil || result.InAmount == 0 {
+		return nil
+	}
+
+	return &result
 }
 
 // cast repository.Payment to payments.Payment
@@ -72,11 +365,25 @@ func castFromRepositoryPayment(p repository.Payment) *Payment {
 		Amount:            uint64(p.Amount),
 		Status:            castFromRepositoryPaymentStatus(p.Status),
 		Message:           p.Message.String,
+		CustomerEmail:     p.CustomerEmail.String,
+		Translations:      unmarshalTranslations(p.Translations),
+		FeeOnTop:          p.FeeOnTop,
+		Escrow:            p.Escrow,
+		Livemode:          p.Livemode,
+		CallbackURL:       p.CallbackUrl.String,
+		AllowPartial:      p.AllowPartial,
+		AmountPaid:        uint64(p.AmountPaid),
+		FiatCurrency:      p.FiatCurrency.String,
+		FiatAmount:        uint64(p.FiatAmount),
+		Settings:          unmarshalPaymentSettings(p.Settings),
 	}
 
 	if p.ExpiresAt.Valid {
 		result.ExpiresAt = &p.ExpiresAt.Time
 	}
+	if p.HeldUntil.Valid {
+		result.HeldUntil = &p.HeldUntil.Time
+	}
 
 	return result
 }
@@ -96,6 +403,14 @@ func castFromRepositoryPaymentStatus(status repository.PaymentStatus) PaymentSta
 		return PaymentStatusCanceled
 	case repository.PaymentStatusExpired:
 		return PaymentStatusExpired
+	case repository.PaymentStatusHeld:
+		return PaymentStatusHeld
+	case repository.PaymentStatusReleased:
+		return PaymentStatusReleased
+	case repository.PaymentStatusDisputed:
+		return PaymentStatusDisputed
+	case repository.PaymentStatusPartiallyPaid:
+		return PaymentStatusPartiallyPaid
 	default:
 		return PaymentStatusNew
 	}
@@ -116,6 +431,14 @@ func castToRepositoryPaymentStatus(status PaymentStatus) repository.PaymentStatu
 		return repository.PaymentStatusCanceled
 	case PaymentStatusExpired:
 		return repository.PaymentStatusExpired
+	case PaymentStatusHeld:
+		return repository.PaymentStatusHeld
+	case PaymentStatusReleased:
+		return repository.PaymentStatusReleased
+	case PaymentStatusDisputed:
+		return repository.PaymentStatusDisputed
+	case PaymentStatusPartiallyPaid:
+		return repository.PaymentStatusPartiallyPaid
 	}
 
 	return repository.PaymentStatusNew
@@ -137,8 +460,13 @@ func castFromRepositoryTransaction(t repository.Transaction, conf Config) *Trans
 		AccruedBonusAmount: uint64(t.AccruedBonusAmount),
 		Message:            t.Message.String,
 		Memo:               t.Memo.String,
+		ApplyVoucher:       t.VoucherAmount > 0,
+		VoucherAmount:      uint64(t.VoucherAmount),
 		Status:             castFromRepositoryTransactionStatus(t.Status),
 		Signature:          t.TxSignature.String,
+		ExchangeRate:       t.ExchangeRate.String,
+		TipAmount:          uint64(t.TipAmount),
+		TipDestination:     t.TipDestination.String,
 	}
 
 	if t.ApplyBonus.Valid {
@@ -147,6 +475,12 @@ func castFromRepositoryTransaction(t repository.Transaction, conf Config) *Trans
 		result.ApplyBonus = conf.ApplyBonus
 	}
 
+	result.Route = unmarshalSwapRoute(t.SwapRoute)
+
+	if t.SlippageFee > 0 {
+		result.Surcharge = &Surcharge{Slippage: uint64(t.SlippageFee)}
+	}
+
 	if t.DestinationWallet == "" {
 		result.DestinationWallet = conf.DestinationWallet
 	}
@@ -173,6 +507,8 @@ func castToRepositoryTransactionStatus(status TransactionStatus) repository.Tran
 		return repository.TransactionStatusCompleted
 	case TransactionStatusFailed:
 		return repository.TransactionStatusFailed
+	case TransactionStatusExpired:
+		return repository.TransactionStatusExpired
 	}
 
 	return repository.TransactionStatusPending
@@ -187,6 +523,8 @@ func castFromRepositoryTransactionStatus(status repository.TransactionStatus) Tr
 		return TransactionStatusCompleted
 	case repository.TransactionStatusFailed:
 		return TransactionStatusFailed
+	case repository.TransactionStatusExpired:
+		return TransactionStatusExpired
 	}
 
 	return TransactionStatusPending
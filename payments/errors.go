@@ -0,0 +1,88 @@
+package payments
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Predefined package errors.
+var (
+	ErrInvalidMint         = errors.New("payment currency is not a valid spl token mint")
+	ErrVoucherNotSupported = errors.New("merchant has no voucher mint")
+	ErrVoucherNotRedeemed  = errors.New("voucher tokens are not burned in the transaction")
+	ErrEscrowNotSupported  = errors.New("escrow wallet is not configured")
+	ErrPaymentNotHeld      = errors.New("payment is not held in escrow")
+	ErrInvalidSettings     = errors.New("invalid merchant settings")
+	ErrTestModeDisabled    = errors.New("test mode is not configured")
+	ErrInvalidTTL          = errors.New("payment ttl is out of the allowed range")
+	ErrPaymentExpired      = errors.New("payment is expired")
+	ErrCallbackURLDenied   = errors.New("payment callback url is not allowed")
+	ErrInvalidDestination  = errors.New("payment destination can't own token accounts")
+	ErrPriceImpactTooHigh  = errors.New("swap price impact exceeds the allowed maximum")
+	ErrPaymentExists       = errors.New("payment with the given external id already exists")
+)
+
+// Signed payment link errors, see LinkSigner.
+var (
+	ErrInvalidLinkSignature = errors.New("payment link signature is invalid")
+	ErrLinkExpired          = errors.New("payment link is expired")
+)
+
+// Single-use payment link errors, see Config.LinkTokenTTL.
+var (
+	ErrInvalidLinkToken = errors.New("payment link token is invalid")
+	ErrLinkTokenUsed    = errors.New("payment link is already used")
+)
+
+// ErrPartialPaymentNotAllowed is returned if a part of the amount is requested
+// for the payment which must be paid in full, see Payment.AllowPartial.
+var ErrPartialPaymentNotAllowed = errors.New("payment doesn't allow partial payments")
+
+// Deposit address errors, see Service.CreateDepositAddress.
+var (
+	ErrDepositAddressNotSupported = errors.New("deposit addresses are not configured")
+	ErrDepositAddressMismatched   = errors.New("deposit address doesn't match the derived key")
+)
+
+// Fiat pricing errors, see WithExchangeRates.
+var (
+	ErrFiatPricingNotSupported = errors.New("fiat pricing is not configured")
+	ErrUnsupportedFiatCurrency = errors.New("fiat currency is not supported")
+	ErrFiatPricedPayment       = errors.New("option is not available for the fiat-priced payments")
+	ErrExchangeRateUnavailable = errors.New("exchange rate is temporarily unavailable")
+)
+
+// PaymentExistsError is returned if a payment with the same external ID already exists
+// and the idempotent mode is disabled. It wraps ErrPaymentExists.
+type PaymentExistsError struct {
+	PaymentID uuid.UUID // ID of the existing payment
+}
+
+// Error returns the error message with the existing payment ID.
+func (e *PaymentExistsError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPaymentExists, e.PaymentID)
+}
+
+// Unwrap returns ErrPaymentExists.
+func (e *PaymentExistsError) Unwrap() error {
+	return ErrPaymentExists
+}
+
+// PriceImpactError is returned if the price impact of the best swap route exceeds
+// the merchant limit and the customer didn't accept it. It wraps ErrPriceImpactTooHigh.
+type PriceImpactError struct {
+	ImpactPct float64 // price impact of the route, in percents
+	MaxPct    float64 // allowed price impact, in percents
+}
+
+// Error returns the error message with the price impact.
+func (e *PriceImpactError) Error() string {
+	return fmt.Sprintf("%s: %.2f%% > %.2f%%", ErrPriceImpactTooHigh, e.ImpactPct, e.MaxPct)
+}
+
+// Unwrap returns ErrPriceImpactTooHigh.
+func (e *PriceImpactError) Unwrap() error {
+	return ErrPriceImpactTooHigh
+}
@@ -17,12 +17,20 @@ func getEventName(status PaymentStatus) events.EventName {
 		return events.PaymentProcessing
 	case PaymentStatusCompleted:
 		return events.PaymentSucceeded
+	case PaymentStatusPartiallyPaid:
+		return events.PaymentPartiallyPaid
 	case PaymentStatusFailed:
 		return events.PaymentFailed
 	case PaymentStatusCanceled:
 		return events.PaymentCancelled
 	case PaymentStatusExpired:
 		return events.PaymentExpired
+	case PaymentStatusHeld:
+		return events.PaymentHeld
+	case PaymentStatusReleased:
+		return events.PaymentReleased
+	case PaymentStatusDisputed:
+		return events.PaymentDisputed
 	default:
 		return ""
 	}
@@ -49,23 +57,83 @@ func UpdateTransactionStatusListener(service PaymentService) events.Listener {
 			return fmt.Errorf("failed to parse payment id: %s", err.Error())
 		}
 
-		status := PaymentStatusPending
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		payment, err := service.GetPayment(ctx, pid)
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+
+		var status PaymentStatus
 		switch TransactionStatus(p.Status) {
 		case TransactionStatusCompleted:
+			// the first confirmed attempt settles the payment, the later attempts don't change it
+			if payment.Settled() {
+				return nil
+			}
+			// the partial payment is settled once the confirmed transactions cover the whole amount
+			if payment.AllowPartial && payment.AmountDue() > 0 {
+				if payment.Status == PaymentStatusPartiallyPaid {
+					return nil
+				}
+				status = PaymentStatusPartiallyPaid
+				break
+			}
 			status = PaymentStatusCompleted
+			// the escrow payment funds are held until released to the merchant
+			if payment.Escrow {
+				status = PaymentStatusHeld
+			}
 		case TransactionStatusFailed:
+			// the payment fails only if none of its other attempts can still settle it
+			active, err := hasActiveAttempts(ctx, service, pid, p.Reference)
+			if err != nil {
+				return err
+			}
+			if active {
+				return nil
+			}
 			status = PaymentStatusFailed
-		case TransactionStatusPending:
-			status = PaymentStatusPending
+		default:
+			return nil
 		}
 
-		ctx, cancel := context.WithCancel(context.Background())
-		defer cancel()
-
 		return service.UpdatePaymentStatus(ctx, pid, status)
 	}
 }
 
+// hasActiveAttempts reports whether the payment has a pending or completed attempt other than the given one.
+func hasActiveAttempts(ctx context.Context, service PaymentService, paymentID uuid.UUID, reference string) (bool, error) {
+	attempts, err := service.GetPaymentAttempts(ctx, paymentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get payment attempts: %w", err)
+	}
+	for _, a := range attempts {
+		if a.Reference != reference && (a.Status == TransactionStatusPending || a.Status == TransactionStatusCompleted) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type depositSweepEnqueuer interface {
+	SweepDepositAddresses(ctx context.Context) error
+}
+
+// SweepDepositAddressesListener enqueues the sweep of the deposit addresses on the completed transactions,
+// so the deposit is swept at once instead of the next scheduled run.
+func SweepDepositAddressesListener(enq depositSweepEnqueuer) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		p, ok := payload.(events.TransactionUpdatedPayload)
+		if !ok || p.Status != string(TransactionStatusCompleted) {
+			return nil
+		}
+
+		return enq.SweepDepositAddresses(context.Background())
+	}
+}
+
 type eventsEnqueuer interface {
 	CheckPaymentByReference(ctx context.Context, reference string) error
 }
@@ -0,0 +1,148 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+type (
+	// rateProvider returns the price of the whole token of the mint in the fiat currency, see rates.Provider.
+	rateProvider interface {
+		Price(ctx context.Context, mint, fiat string) (float64, error)
+	}
+)
+
+// fiatDecimals are the minor units of the supported fiat currencies.
+var fiatDecimals = map[string]uint8{
+	"USD": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"CHF": 2,
+	"CAD": 2,
+	"AUD": 2,
+	"JPY": 0,
+}
+
+// WithExchangeRates enables the fiat-priced payments, see Payment.FiatCurrency.
+// The payment is converted to the destination mint at the provider price when the transaction is built,
+// so the customer pays the current price, and the transaction keeps the rate it's converted at.
+func WithExchangeRates(rates rateProvider) ServiceOption {
+	return func(s *Service) {
+		s.rates = rates
+	}
+}
+
+// validateFiatPrice checks the fiat price of the new payment.
+func (s *Service) validateFiatPrice(payment *Payment) error {
+	if s.rates == nil {
+		return ErrFiatPricingNotSupported
+	}
+	payment.FiatCurrency = strings.ToUpper(payment.FiatCurrency)
+	if _, ok := fiatDecimals[payment.FiatCurrency]; !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedFiatCurrency, payment.FiatCurrency)
+	}
+	if payment.FiatAmount == 0 {
+		return fmt.Errorf("payment fiat amount must be greater than 0")
+	}
+	// the paid parts would be converted at the different rates
+	if payment.AllowPartial {
+		return fmt.Errorf("%w: partial payments", ErrFiatPricedPayment)
+	}
+	return nil
+}
+
+// convertFiatAmount sets the amount of the fiat-priced payment to its fiat amount converted
+// to the destination mint at the current rate, and returns the rate.
+// The other payments are left as is, the rate is empty.
+func (s *Service) convertFiatAmount(ctx context.Context, payment *Payment) (string, error) {
+	if payment.FiatCurrency == "" {
+		return "", nil
+	}
+	if s.rates == nil {
+		return "", ErrFiatPricingNotSupported
+	}
+	fiatDec, ok := fiatDecimals[payment.FiatCurrency]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFiatCurrency, payment.FiatCurrency)
+	}
+
+	mintDec, err := s.solanaFor(payment.Livemode).GetMintDecimals(ctx, payment.DestinationMint)
+	if err != nil {
+		return "", fmt.Errorf("failed to get mint decimals: %w", err)
+	}
+
+	// the test mints have no market price, they are priced as the mainnet mints of the same currency
+	price, err := s.rates.Price(ctx, s.liveMint(payment.DestinationMint), payment.FiatCurrency)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: %s", ErrExchangeRateUnavailable, err.Error())
+	}
+
+	amount, err := fiatToTokenAmount(payment.FiatAmount, fiatDec, price, mintDec)
+	if err != nil {
+		return "", err
+	}
+	payment.Amount = amount
+
+	return strconv.FormatFloat(price, 'f', -1, 64), nil
+}
+
+// storeFiatAmount stores the amount the fiat-priced payment is converted to, see convertFiatAmount,
+// so the payment amount is the amount of its latest transaction, e.g. for the notifications.
+func (s *Service) storeFiatAmount(ctx context.Context, payment *Payment) error {
+	if payment.FiatCurrency == "" {
+		return nil
+	}
+	if _, err := s.repoOf(ctx).UpdatePaymentAmount(ctx, repository.UpdatePaymentAmountParams{
+		Amount: int64(payment.Amount),
+		ID:     payment.ID,
+	}); err != nil {
+		return fmt.Errorf("failed to update payment amount: %w", err)
+	}
+	return nil
+}
+
+// liveMint returns the mainnet mint of the currency the test mint stands for, or the mint as is.
+func (s *Service) liveMint(mint string) string {
+	if s.sandbox != nil {
+		for symbol, m := range s.sandbox.mints {
+			if m == mint {
+				return MintAddress(symbol, mint)
+			}
+		}
+	}
+	return mint
+}
+
+// fiatToTokenAmount converts the fiat amount in the minor units to the token amount in the base units
+// at the price of the whole token. The amount is rounded up, so the merchant is never underpaid.
+func fiatToTokenAmount(fiatAmount uint64, fiatDecimals uint8, price float64, mintDecimals uint8) (uint64, error) {
+	p := new(big.Rat)
+	if p.SetFloat64(price) == nil || p.Sign() <= 0 {
+		return 0, fmt.Errorf("%w: invalid price %v", ErrExchangeRateUnavailable, price)
+	}
+
+	// fiatAmount / 10^fiatDecimals / price * 10^mintDecimals
+	amount := new(big.Rat).SetInt(new(big.Int).SetUint64(fiatAmount))
+	amount.Mul(amount, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(mintDecimals)), nil)))
+	amount.Quo(amount, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(fiatDecimals)), nil)))
+	amount.Quo(amount, p)
+
+	result, rem := new(big.Int).QuoRem(amount.Num(), amount.Denom(), new(big.Int))
+	if rem.Sign() > 0 {
+		result.Add(result, big.NewInt(1))
+	}
+	if !result.IsUint64() || result.Uint64() == 0 {
+		return 0, fmt.Errorf("fiat amount %d can't be converted to the token amount at the price %v", fiatAmount, price)
+	}
+
+	return result.Uint64(), nil
+}
@@ -0,0 +1,30 @@
+package payments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFiatToTokenAmount(t *testing.T) {
+	// $10.00 at $20 per SOL is 0.5 SOL
+	amount, err := fiatToTokenAmount(1000, 2, 20, 9)
+	require.NoError(t, err)
+	require.EqualValues(t, 500_000_000, amount)
+
+	// 12.34 EUR at 0.92 EUR per USDC, rounded up to the base unit
+	amount, err = fiatToTokenAmount(1234, 2, 0.92, 6)
+	require.NoError(t, err)
+	require.EqualValues(t, 13_413_044, amount)
+
+	// zero-decimal currencies
+	amount, err = fiatToTokenAmount(1500, 0, 150, 6)
+	require.NoError(t, err)
+	require.EqualValues(t, 10_000_000, amount)
+
+	_, err = fiatToTokenAmount(1000, 2, 0, 9)
+	require.ErrorIs(t, err, ErrExchangeRateUnavailable)
+
+	_, err = fiatToTokenAmount(1000, 2, -1, 9)
+	require.ErrorIs(t, err, ErrExchangeRateUnavailable)
+}
@@ -0,0 +1,91 @@
+package payments
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Translation is a localized version of the payment texts.
+type Translation struct {
+	Label   string `json:"label,omitempty"`   // Label is the merchant name shown in the wallet.
+	Message string `json:"message,omitempty"` // Message is the payment description shown in the wallet.
+}
+
+// BCP 47 language tag subset: language with optional script/region subtags, e.g. "en", "pt-BR", "zh-Hant-TW".
+var localeRegexp = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// NormalizeLocale returns the locale in lower case with "-" as a separator, e.g. "pt_BR" -> "pt-br".
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// ValidLocale checks if the locale is a valid language tag.
+func ValidLocale(locale string) bool {
+	return localeRegexp.MatchString(NormalizeLocale(locale))
+}
+
+// Translate returns the translation for the given locale.
+// It falls back to the base language, e.g. "pt-br" -> "pt", and then to the default payment message
+// with an empty label, so the caller can use its default label.
+func (p *Payment) Translate(locale string) Translation {
+	result := Translation{Message: p.Message}
+
+	locale = NormalizeLocale(locale)
+	for locale != "" {
+		if t, ok := p.Translations[locale]; ok {
+			if t.Label != "" {
+				result.Label = t.Label
+			}
+			if t.Message != "" {
+				result.Message = t.Message
+			}
+			return result
+		}
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+
+	return result
+}
+
+// normalizeTranslations returns translations with normalized locale keys.
+func normalizeTranslations(translations map[string]Translation) map[string]Translation {
+	if len(translations) == 0 {
+		return nil
+	}
+
+	result := make(map[string]Translation, len(translations))
+	for locale, t := range translations {
+		result[NormalizeLocale(locale)] = t
+	}
+
+	return result
+}
+
+// marshalTranslations encodes translations to store them in the database.
+func marshalTranslations(translations map[string]Translation) (json.RawMessage, error) {
+	if len(translations) == 0 {
+		return json.RawMessage("{}"), nil
+	}
+	return json.Marshal(normalizeTranslations(translations))
+}
+
+// unmarshalTranslations decodes translations stored in the database.
+// Invalid data is ignored, since translations are optional.
+func unmarshalTranslations(data json.RawMessage) map[string]Translation {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var result map[string]Translation
+	if err := json.Unmarshal(data, &result); err != nil || len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
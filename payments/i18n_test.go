@@ -0,0 +1,44 @@
+package payments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentTranslate(t *testing.T) {
+	p := &Payment{
+		Message: "Order #1",
+		Translations: normalizeTranslations(map[string]Translation{
+			"es":    {Label: "Tienda", Message: "Pedido #1"},
+			"pt_BR": {Message: "Pedido nº 1"},
+		}),
+	}
+
+	require.Equal(t, Translation{Label: "Tienda", Message: "Pedido #1"}, p.Translate("es"))
+	require.Equal(t, Translation{Label: "Tienda", Message: "Pedido #1"}, p.Translate("es-MX"))
+	require.Equal(t, Translation{Message: "Pedido nº 1"}, p.Translate("pt-BR"))
+	require.Equal(t, Translation{Message: "Order #1"}, p.Translate("pt"))
+	require.Equal(t, Translation{Message: "Order #1"}, p.Translate("de"))
+	require.Equal(t, Translation{Message: "Order #1"}, p.Translate(""))
+}
+
+func TestValidLocale(t *testing.T) {
+	for _, l := range []string{"en", "pt-BR", "pt_BR", "zh-Hant-TW", "fil"} {
+		require.True(t, ValidLocale(l), l)
+	}
+	for _, l := range []string{"", "e", "english", "en-", "en us", "../en"} {
+		require.False(t, ValidLocale(l), l)
+	}
+}
+
+func TestTranslationsRoundTrip(t *testing.T) {
+	data, err := marshalTranslations(map[string]Translation{"ES": {Message: "Hola"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]Translation{"es": {Message: "Hola"}}, unmarshalTranslations(data))
+
+	data, err = marshalTranslations(nil)
+	require.NoError(t, err)
+	require.Equal(t, "{}", string(data))
+	require.Nil(t, unmarshalTranslations(data))
+}
@@ -14,8 +14,14 @@ type PaymentService interface {
 	GetPayment(ctx context.Context, id uuid.UUID) (*Payment, error)
 	// GetPaymentByExternalID returns the payment with the given external ID.
 	GetPaymentByExternalID(ctx context.Context, externalID string) (*Payment, error)
+	// GetPaymentStatus returns only the status of the payment with the given ID.
+	GetPaymentStatus(ctx context.Context, id uuid.UUID) (*PaymentStatusInfo, error)
 	// GeneratePaymentLink generates a new payment link for the given payment.
 	GeneratePaymentLink(ctx context.Context, paymentID uuid.UUID, mint string, applyBonus bool) (string, error)
+	// CheckoutLink returns the payment link of the checkout page opened with the given access token, if any.
+	CheckoutLink(ctx context.Context, paymentID uuid.UUID, token string) (string, error)
+	// GenerateTransferRequest generates the Solana Pay transfer-request link of the payment.
+	GenerateTransferRequest(ctx context.Context, paymentID uuid.UUID, locale string) (*TransferRequest, error)
 	// UpdatePaymentStatus updates the status of the payment with the given ID.
 	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status PaymentStatus) error
 	// CancelPayment cancels the payment with the given ID.
@@ -26,12 +32,36 @@ type PaymentService interface {
 	MarkPaymentsAsExpired(ctx context.Context) error
 	// BuildTransaction builds a new transaction for the given payment.
 	BuildTransaction(ctx context.Context, tx *Transaction) (*Transaction, error)
+	// QuoteTransaction estimates the checkout total for the given payment, customer wallet and currency.
+	QuoteTransaction(ctx context.Context, tx *Transaction) (*Quote, error)
+	// GetWalletTokens returns the tokens held by the given wallet that can be used to pay for the payment.
+	GetWalletTokens(ctx context.Context, paymentID uuid.UUID, wallet string) ([]*WalletToken, error)
+	// VerifyVoucherRedemption checks that the voucher tokens redeemed in the transaction are burned.
+	VerifyVoucherRedemption(ctx context.Context, tx *Transaction, signature string) error
+	// ReleasePayment transfers the held payment funds from the escrow wallet to the merchant wallet.
+	ReleasePayment(ctx context.Context, id uuid.UUID) (*EscrowRelease, error)
+	// GetPaymentsToRelease returns the held payments whose escrow release window has passed.
+	GetPaymentsToRelease(ctx context.Context) ([]*Payment, error)
+	// CreateDepositAddress returns the dedicated deposit address of the payment, creating it on the first call.
+	CreateDepositAddress(ctx context.Context, paymentID uuid.UUID) (*DepositAddress, error)
+	// SweepDepositAddresses transfers the funds of the paid deposit addresses to the merchant wallet.
+	SweepDepositAddresses(ctx context.Context) error
+	// DeleteExpiredLinkTokens purges the expired single-use tokens of the payment links.
+	DeleteExpiredLinkTokens(ctx context.Context) error
 	// GetTransactionByReference returns the transaction with the given reference.
 	GetTransactionByReference(ctx context.Context, reference string) (*Transaction, error)
+	// GetLatestTransaction returns the latest transaction generated for the payment with the given ID.
+	GetLatestTransaction(ctx context.Context, paymentID uuid.UUID) (*Transaction, error)
+	// GetPaymentAttempts returns all the transactions generated for the payment, the latest first.
+	GetPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]*PaymentAttempt, error)
+	// RecheckTransaction re-runs the on-chain validation of the transaction and fixes the stored status.
+	RecheckTransaction(ctx context.Context, reference string) (*TransactionRecheck, error)
 	// UpdateTransaction updates the status and signature of the transaction with the given reference.
 	UpdateTransaction(ctx context.Context, reference string, status TransactionStatus, signature string) error
 	// GetPendingTransactions returns all pending transactions.
 	GetPendingTransactions(ctx context.Context) ([]*Transaction, error)
+	// GetPendingReferences returns the primary and the additional references of all pending transactions.
+	GetPendingReferences(ctx context.Context) ([]string, error)
 	// MarkTransactionsAsExpired marks all transactions that are expired as expired.
 	MarkTransactionsAsExpired(ctx context.Context) error
 }
@@ -0,0 +1,52 @@
+package payments
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkSigner(t *testing.T) {
+	signer := NewLinkSigner([]byte("secret"), time.Hour)
+	params := LinkParams{
+		PaymentID:  "3f1c0d7e-5a3b-4e9a-9f41-0c2d7b6e8a10",
+		Mint:       "So11111111111111111111111111111111111111112",
+		ApplyBonus: "true",
+		Amount:     "1000000",
+	}
+
+	q := signer.Sign(params, nil)
+	require.Equal(t, "1000000", q.Get(LinkAmountParam))
+	require.NoError(t, signer.Verify(params, q))
+
+	t.Run("tampered path", func(t *testing.T) {
+		tampered := params
+		tampered.ApplyBonus = "false"
+		require.ErrorIs(t, signer.Verify(tampered, q), ErrInvalidLinkSignature)
+	})
+
+	t.Run("tampered amount hint", func(t *testing.T) {
+		tq := url.Values{}
+		for k, v := range q {
+			tq[k] = v
+		}
+		tq.Set(LinkAmountParam, "1")
+		require.ErrorIs(t, signer.Verify(params, tq), ErrInvalidLinkSignature)
+	})
+
+	t.Run("another key", func(t *testing.T) {
+		other := NewLinkSigner([]byte("another"), time.Hour)
+		require.ErrorIs(t, other.Verify(params, q), ErrInvalidLinkSignature)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		require.ErrorIs(t, signer.Verify(params, url.Values{}), ErrInvalidLinkSignature)
+	})
+
+	t.Run("expired with the payment", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Minute)
+		require.ErrorIs(t, signer.Verify(params, signer.Sign(params, &expiresAt)), ErrLinkExpired)
+	})
+}
@@ -0,0 +1,108 @@
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// LinkTokenParam is the query parameter of the single-use access token of the payment link.
+const LinkTokenParam = "token"
+
+// issueLinkToken creates a new single-use access token of the payment link.
+// The token expires after the ttl or with the payment, whichever is earlier.
+// Only the hash of the token is stored, so the stored tokens can't be used to pay.
+func (s *Service) issueLinkToken(ctx context.Context, payment *Payment, ttl time.Duration) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate link token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	expiresAt := time.Now().Add(ttl)
+	if payment.ExpiresAt != nil && payment.ExpiresAt.Before(expiresAt) {
+		expiresAt = *payment.ExpiresAt
+	}
+
+	if err := s.repo.CreateLinkToken(ctx, repository.CreateLinkTokenParams{
+		TokenHash: linkTokenHash(token),
+		PaymentID: payment.ID,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return "", fmt.Errorf("failed to store link token: %w", err)
+	}
+
+	return token, nil
+}
+
+// VerifyLinkToken checks the access token of the payment link without using it up,
+// e.g. before the checkout request does any RPC call.
+// It returns ErrInvalidLinkToken if the token is missing or issued for another payment,
+// ErrLinkTokenUsed if a transaction is already generated with it and ErrLinkExpired if it's expired.
+func (s *Service) VerifyLinkToken(ctx context.Context, paymentID uuid.UUID, token string) error {
+	if token == "" {
+		return fmt.Errorf("%w: missing token", ErrInvalidLinkToken)
+	}
+
+	t, err := s.repo.GetLinkToken(ctx, linkTokenHash(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrInvalidLinkToken
+		}
+		return fmt.Errorf("failed to get link token: %w", err)
+	}
+
+	switch {
+	case t.PaymentID != paymentID:
+		return ErrInvalidLinkToken
+	case t.UsedAt.Valid:
+		return ErrLinkTokenUsed
+	case time.Now().After(t.ExpiresAt):
+		return ErrLinkExpired
+	}
+
+	return nil
+}
+
+// useLinkToken invalidates the access token once the transaction is generated with it.
+// Of the concurrent requests with the same token, only one uses it, the others get ErrLinkTokenUsed.
+func (s *Service) useLinkToken(ctx context.Context, paymentID uuid.UUID, token string) error {
+	n, err := s.repoOf(ctx).UseLinkToken(ctx, repository.UseLinkTokenParams{
+		TokenHash: linkTokenHash(token),
+		PaymentID: paymentID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to use link token: %w", err)
+	}
+	if n == 0 {
+		// the token is used concurrently or it's expired in the meantime
+		if err := s.VerifyLinkToken(ctx, paymentID, token); err != nil {
+			return err
+		}
+		return ErrLinkTokenUsed
+	}
+	return nil
+}
+
+// DeleteExpiredLinkTokens purges the expired access tokens of the payment links.
+func (s *Service) DeleteExpiredLinkTokens(ctx context.Context) error {
+	if _, err := s.repo.DeleteExpiredLinkTokens(ctx); err != nil {
+		return fmt.Errorf("failed to delete expired link tokens: %w", err)
+	}
+	return nil
+}
+
+// linkTokenHash returns the hex encoded sha256 hash of the token, the key of the stored token.
+func linkTokenHash(token string) string {
+	h := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,173 @@
+package payments
+
+//go:generate go run github.com/easypmnt/checkout-api/internal/gendecorators -source interface.go -type PaymentService -output service_middleware_gen.go
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/logging"
+	"github.com/easypmnt/checkout-api/internal/metrics"
+)
+
+type (
+	// Tracer starts a span for every payment service call.
+	// It's a thin hook to plug in any tracing library, e.g. OpenTelemetry.
+	// The returned function ends the span with the call error, if any.
+	Tracer interface {
+		Start(ctx context.Context, name string) (context.Context, func(err error))
+	}
+
+	// TracerFunc is an adapter to use an ordinary function as a Tracer.
+	TracerFunc func(ctx context.Context, name string) (context.Context, func(err error))
+
+	// MiddlewareOption configures the payment service middleware chain.
+	MiddlewareOption func(*middlewareChain)
+
+	middlewareChain struct {
+		fireEvent fireEventFunc
+		outbox    eventsOutbox
+		log       Logger
+		metrics   bool
+		tracer    Tracer
+	}
+
+	// loggingMiddleware logs every payment service call with the redacted arguments.
+	loggingMiddleware struct {
+		next PaymentService
+		log  Logger
+	}
+
+	// metricsMiddleware records the number, the result and the duration of every payment service call.
+	metricsMiddleware struct {
+		next PaymentService
+	}
+
+	// tracingMiddleware starts a span for every payment service call.
+	tracingMiddleware struct {
+		next   PaymentService
+		tracer Tracer
+	}
+)
+
+// Start calls f(ctx, name).
+func (f TracerFunc) Start(ctx context.Context, name string) (context.Context, func(err error)) {
+	return f(ctx, name)
+}
+
+// Payment service metrics.
+// The per-method error rate is the ratio of the failed calls to all calls, e.g.:
+// sum by (method) (rate(payment_service_calls_total{result="failure"}[5m])) / sum by (method) (rate(payment_service_calls_total[5m]))
+var (
+	serviceCallsTotal = metrics.NewCounterVec(
+		"payment_service_calls_total",
+		"Total number of payment service calls.",
+		"method", "result",
+	)
+	serviceCallDuration = metrics.NewHistogramVec(
+		"payment_service_call_duration_seconds",
+		"Duration of payment service calls in seconds.",
+		nil,
+		"method",
+	)
+)
+
+// NewServiceChain wraps the payment service with the configured decorators in one call.
+// The decorators are applied in a fixed order regardless of the options order:
+// events (innermost), metrics, logging and tracing (outermost).
+// The logging, metrics and tracing decorators are generated from the PaymentService
+// interface (see service_middleware_gen.go), so they always cover every method.
+func NewServiceChain(svc PaymentService, opts ...MiddlewareOption) PaymentService {
+	c := &middlewareChain{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.fireEvent != nil {
+		se := NewServiceEvents(svc, c.fireEvent)
+		se.outbox = c.outbox
+		svc = se
+	}
+	if c.metrics {
+		svc = &metricsMiddleware{next: svc}
+	}
+	if c.log != nil {
+		svc = &loggingMiddleware{next: svc, log: c.log}
+	}
+	if c.tracer != nil {
+		svc = &tracingMiddleware{next: svc, tracer: c.tracer}
+	}
+
+	return svc
+}
+
+// WithEvents fires the payment events on successful service calls.
+func WithEvents(fn fireEventFunc) MiddlewareOption {
+	return func(c *middlewareChain) {
+		c.fireEvent = fn
+	}
+}
+
+// WithOutbox records the events of the payment and transaction status updates in the outbox
+// in the same database transaction as the updates, instead of firing them right away.
+// The outbox poller fires them after the commit, so they're not lost if the instance crashes in between.
+// It applies along with WithEvents only.
+func WithOutbox(o eventsOutbox) MiddlewareOption {
+	return func(c *middlewareChain) {
+		c.outbox = o
+	}
+}
+
+// WithLogging logs every service call. Sensitive fields are redacted, see Redact.
+func WithLogging(log Logger) MiddlewareOption {
+	return func(c *middlewareChain) {
+		c.log = log
+	}
+}
+
+// WithMetrics records the number, the result and the duration of every service call.
+func WithMetrics() MiddlewareOption {
+	return func(c *middlewareChain) {
+		c.metrics = true
+	}
+}
+
+// WithTracing starts a span for every service call.
+func WithTracing(tracer Tracer) MiddlewareOption {
+	return func(c *middlewareChain) {
+		c.tracer = tracer
+	}
+}
+
+// logCall logs the payment service call with the redacted arguments.
+// The logging.Logger adds the request fields of the context, e.g. the request and client IDs.
+func (m *loggingMiddleware) logCall(ctx context.Context, method string, begin time.Time, err error, args ...interface{}) {
+	redacted := make([]string, 0, len(args))
+	for _, arg := range args {
+		redacted = append(redacted, Redact(arg))
+	}
+
+	log := m.log
+	if l, ok := log.(*logging.Logger); ok {
+		log = l.WithContext(ctx)
+	}
+
+	if err != nil {
+		log.Errorf("payments: %s(%s) failed in %s: %s", method, strings.Join(redacted, ", "), time.Since(begin), err.Error())
+		return
+	}
+
+	log.Debugf("payments: %s(%s) took %s", method, strings.Join(redacted, ", "), time.Since(begin))
+}
+
+// observeCall records the payment service call metrics.
+func (m *metricsMiddleware) observeCall(method string, begin time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	serviceCallsTotal.WithLabelValues(method, result).Inc()
+	serviceCallDuration.WithLabelValues(method).Observe(time.Since(begin).Seconds())
+}
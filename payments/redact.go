@@ -0,0 +1,100 @@
+package payments
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// redactedValue replaces the sensitive values in logs.
+const redactedValue = "[REDACTED]"
+
+// sensitiveFields are the JSON fields which must never be written to logs as is:
+// the serialized transactions and any wallet private data.
+var sensitiveFields = map[string]bool{
+	"transaction": true,
+	"private_key": true,
+	"secret_key":  true,
+	"secret":      true,
+	"seed":        true,
+	"mnemonic":    true,
+}
+
+// maskedFields are the JSON fields which are partially masked in logs.
+var maskedFields = map[string]func(string) string{
+	"customer_email": maskEmail,
+}
+
+// Redact returns the JSON representation of the value with the sensitive fields redacted.
+// Strings and other scalar values are returned as is.
+func Redact(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return val
+	case fmt.Stringer:
+		return val.String()
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return string(b)
+	}
+	switch data.(type) {
+	case map[string]interface{}, []interface{}:
+	default:
+		return fmt.Sprint(v)
+	}
+
+	b, err = json.Marshal(redactValue(data))
+	if err != nil {
+		return err.Error()
+	}
+
+	return string(b)
+}
+
+// redactValue walks the decoded JSON value and redacts the sensitive fields.
+func redactValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range val {
+			key := strings.ToLower(k)
+			if sensitiveFields[key] {
+				val[k] = redactedValue
+				continue
+			}
+			if mask, ok := maskedFields[key]; ok {
+				if s, ok := fv.(string); ok {
+					val[k] = mask(s)
+					continue
+				}
+			}
+			val[k] = redactValue(fv)
+		}
+		return val
+	case []interface{}:
+		for i, item := range val {
+			val[i] = redactValue(item)
+		}
+		return val
+	default:
+		return v
+	}
+}
+
+// maskEmail keeps the first letter of the local part and the domain of the email,
+// e.g. "john@example.com" -> "j***@example.com".
+func maskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return redactedValue
+	}
+	return email[:1] + "***" + email[at:]
+}
@@ -0,0 +1,36 @@
+package payments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	t.Run("struct", func(t *testing.T) {
+		got := Redact(&Transaction{
+			Reference:   "ref",
+			Transaction: "base64-encoded-transaction",
+		})
+		require.Contains(t, got, `"reference":"ref"`)
+		require.Contains(t, got, `"transaction":"[REDACTED]"`)
+		require.NotContains(t, got, "base64-encoded-transaction")
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		got := Redact(map[string]interface{}{
+			"wallets": []interface{}{
+				map[string]interface{}{"address": "addr", "private_key": "secret"},
+			},
+			"customer_email": "john@example.com",
+		})
+		require.Equal(t, `{"customer_email":"j***@example.com","wallets":[{"address":"addr","private_key":"[REDACTED]"}]}`, got)
+	})
+
+	t.Run("scalar", func(t *testing.T) {
+		require.Equal(t, "ref", Redact("ref"))
+		require.Equal(t, "completed", Redact(PaymentStatusCompleted))
+		require.Equal(t, "true", Redact(true))
+		require.Equal(t, "null", Redact(nil))
+	})
+}
@@ -0,0 +1,50 @@
+package payments
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/internal/hdkey"
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/google/uuid"
+)
+
+// ReferenceDeriver derives the reference public keys of the transactions from the server seed,
+// so the references of any transaction can be re-derived for the reconciliation,
+// e.g. after a data loss, and their private keys are never generated or stored.
+//
+// The n-th reference of the transaction is the public key of the SLIP-0010 path
+// m/<payment id>'/<transaction id>'/n', where every uuid is split into four 31-bit indices.
+type ReferenceDeriver struct {
+	master *hdkey.Key
+}
+
+// NewReferenceDeriver creates a new reference deriver from the seed of at least 16 bytes.
+func NewReferenceDeriver(seed []byte) (*ReferenceDeriver, error) {
+	master, err := hdkey.NewMaster(seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive master key: %w", err)
+	}
+	return &ReferenceDeriver{master: master}, nil
+}
+
+// Reference returns the base58 encoded n-th reference of the transaction.
+// The 0 reference is the main one, the next ones are the references of the extra instructions.
+func (d *ReferenceDeriver) Reference(paymentID, transactionID uuid.UUID, n uint32) string {
+	path := make([]uint32, 0, 9)
+	path = append(path, uuidPath(paymentID)...)
+	path = append(path, uuidPath(transactionID)...)
+	path = append(path, n)
+
+	return utils.BytesToBase58(d.master.Derive(path...).PublicKey())
+}
+
+// uuidPath splits the uuid into four derivation indices.
+// The highest bit of every index is the hardened flag, so it's dropped.
+func uuidPath(id uuid.UUID) []uint32 {
+	path := make([]uint32, 4)
+	for i := range path {
+		path[i] = binary.BigEndian.Uint32(id[i*4:]) &^ hdkey.HardenedOffset
+	}
+	return path
+}
@@ -0,0 +1,37 @@
+package payments
+
+import (
+	"testing"
+
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferenceDeriver(t *testing.T) {
+	d, err := NewReferenceDeriver([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+
+	paymentID, txID := uuid.New(), uuid.New()
+	ref := d.Reference(paymentID, txID, 0)
+
+	pub, err := utils.Base58ToBytes(ref)
+	require.NoError(t, err)
+	require.Len(t, pub, 32)
+
+	// deterministic
+	require.Equal(t, ref, d.Reference(paymentID, txID, 0))
+
+	// unique per reference index, transaction and payment
+	require.NotEqual(t, ref, d.Reference(paymentID, txID, 1))
+	require.NotEqual(t, ref, d.Reference(paymentID, uuid.New(), 0))
+	require.NotEqual(t, ref, d.Reference(uuid.New(), txID, 0))
+
+	// unique per seed
+	other, err := NewReferenceDeriver([]byte("fedcba9876543210fedcba9876543210"))
+	require.NoError(t, err)
+	require.NotEqual(t, ref, other.Reference(paymentID, txID, 0))
+
+	_, err = NewReferenceDeriver([]byte("short"))
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+package payments
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type (
+	// ServiceOption is a function that configures the payment service.
+	ServiceOption func(*Service)
+
+	// sandbox runs the test-mode payments, see WithSandbox.
+	sandbox struct {
+		sol   solanaClient
+		mints map[string]string // currency symbol => test mint address
+	}
+
+	livemodeCtxKey struct{}
+)
+
+// WithSandbox configures the service to run the test-mode payments against the given Solana client,
+// e.g. a devnet one. The test mints replace the mainnet mints of the currency symbols,
+// e.g. {"USDC": "<devnet USDC mint address>"}. The test-mode payments can't be created without the sandbox.
+func WithSandbox(sol solanaClient, mints map[string]string) ServiceOption {
+	return func(s *Service) {
+		s.sandbox = &sandbox{sol: sol, mints: mints}
+	}
+}
+
+// WithLivemode returns a copy of the context in which the payments are created in the given mode.
+// The payments are created in live mode by default.
+func WithLivemode(ctx context.Context, livemode bool) context.Context {
+	return context.WithValue(ctx, livemodeCtxKey{}, livemode)
+}
+
+// livemodeFromContext returns the mode of the payments created in the context.
+func livemodeFromContext(ctx context.Context) bool {
+	livemode, ok := ctx.Value(livemodeCtxKey{}).(bool)
+	return !ok || livemode
+}
+
+// solanaFor returns the Solana client of the payments in the given mode.
+func (s *Service) solanaFor(livemode bool) solanaClient {
+	if !livemode && s.sandbox != nil {
+		return s.sandbox.sol
+	}
+	return s.sol
+}
+
+// solanaForPayment returns the Solana client of the payment with the given ID.
+// The payment is loaded only if the sandbox is configured.
+func (s *Service) solanaForPayment(ctx context.Context, paymentID uuid.UUID) (solanaClient, error) {
+	if s.sandbox == nil {
+		return s.sol, nil
+	}
+
+	payment, err := s.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.solanaFor(payment.Livemode), nil
+}
+
+// testMint returns the test mint address of the currency symbol, or the currency as is.
+func (s *Service) testMint(currency string) string {
+	if s.sandbox != nil {
+		if m, ok := s.sandbox.mints[strings.ToUpper(currency)]; ok {
+			return m
+		}
+	}
+	return currency
+}
@@ -15,4 +15,7 @@ func (s *Scheduler) Schedule(scheduler *asynq.Scheduler) {
 	scheduler.Register("@every 5m", asynq.NewTask(TastMarkPaymentsAsExpired, nil))
 	scheduler.Register("@every 5m", asynq.NewTask(TaskMarkTransactionsAsExpired, nil))
 	scheduler.Register("@every 5m", asynq.NewTask(TaskCheckPendingTransactions, nil))
+	scheduler.Register("@every 5m", asynq.NewTask(TaskReleaseHeldPayments, nil))
+	scheduler.Register("@every 5m", asynq.NewTask(TaskSweepDepositAddresses, nil))
+	scheduler.Register("@every 1h", asynq.NewTask(TaskDeleteExpiredLinkTokens, nil))
 }
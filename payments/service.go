@@ -5,12 +5,16 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/easypmnt/checkout-api/internal/utils"
 	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/solana"
 	"github.com/google/uuid"
 )
 
@@ -19,27 +23,141 @@ type (
 		repo paymentRepository
 		sol  solanaClient
 		jup  jupiterClient
+
+		sandbox *sandbox     // optional; runs the test-mode payments
+		rates   rateProvider // optional; converts the fiat-priced payments, see WithExchangeRates
+
+		mu   sync.RWMutex // guards conf, see ApplySettings
 		conf Config
 	}
 )
 
+// DefaultPaymentTTL is the default time the payment can be paid in.
+const DefaultPaymentTTL = 15 * time.Minute
+
 // NewService creates a new payment service instance.
-func NewService(repo paymentRepository, sol solanaClient, jup jupiterClient, conf Config) *Service {
-	return &Service{
+func NewService(repo paymentRepository, sol solanaClient, jup jupiterClient, conf Config, opts ...ServiceOption) *Service {
+	if conf.QuoteTTL == 0 {
+		conf.QuoteTTL = 30 * time.Second
+	}
+	if conf.PaymentTTL == 0 {
+		conf.PaymentTTL = DefaultPaymentTTL
+	}
+
+	s := &Service{
 		repo: repo,
 		sol:  sol,
 		jup:  jup,
 		conf: conf,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// repoOf returns the queries bound to the transaction of the context, see repository.ContextWithTx,
+// so the payment updates are committed along with the events recorded in the outbox, or the repository otherwise.
+func (s *Service) repoOf(ctx context.Context) paymentRepository {
+	if q, ok := repository.TxFromContext(ctx); ok {
+		return q
+	}
+	return s.repo
+}
+
+// inTx runs fn in a database transaction, so the queries fn runs with repoOf(ctx) are committed together.
+// It joins the transaction of the context, if any, and runs fn as is if the repository doesn't support
+// the transactions, e.g. in the tests.
+func (s *Service) inTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := repository.TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+	r, ok := s.repo.(txRepository)
+	if !ok {
+		return fn(ctx)
+	}
+	return r.InTx(ctx, func(q repository.Querier) error {
+		return fn(repository.ContextWithTx(ctx, q))
+	})
 }
 
 // CreatePayment creates a new payment.
 func (s *Service) CreatePayment(ctx context.Context, payment *Payment) (*Payment, error) {
+	conf := s.config()
+	if payment.ExternalID != "" {
+		existing, err := s.repo.GetPaymentByExternalID(ctx, payment.ExternalID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed to get payment by external id: %w", err)
+		}
+		if err == nil {
+			return s.existingPayment(conf, existing)
+		}
+	}
+	if payment.Settings != nil {
+		if err := s.checkPaymentSettings(conf, payment.Settings); err != nil {
+			return nil, err
+		}
+		if payment.DestinationMint == "" {
+			payment.DestinationMint = payment.Settings.DestinationMint
+		}
+	}
+	if payment.DestinationWallet == "" && conf.WalletSelector != nil {
+		wallet, err := conf.WalletSelector.SelectWallet(ctx, MintAddress(payment.DestinationMint, conf.DestinationMint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to select destination wallet: %w", err)
+		}
+		payment.DestinationWallet = wallet
+	}
+	if payment.ExpiresAt != nil {
+		if err := conf.validateTTL(time.Until(*payment.ExpiresAt)); err != nil {
+			return nil, err
+		}
+	}
 	payment = s.mergePaymentWithDefaultConfig(payment)
-	if payment.Amount == 0 {
+	if payment.FiatCurrency != "" {
+		// the amount is converted from the fiat amount when the transaction is built
+		if err := s.validateFiatPrice(payment); err != nil {
+			return nil, err
+		}
+		payment.Amount = 0
+	} else if payment.Amount == 0 {
 		return nil, fmt.Errorf("payment amount must be greater than 0")
 	}
-	payment.DestinationMint = MintAddress(payment.DestinationMint, s.conf.DestinationMint)
+	if payment.Escrow && conf.EscrowSigner == nil {
+		return nil, ErrEscrowNotSupported
+	}
+	if payment.CallbackURL != "" {
+		if err := conf.validateCallbackURL(payment.CallbackURL); err != nil {
+			return nil, err
+		}
+	}
+	payment.Livemode = livemodeFromContext(ctx)
+	if !payment.Livemode {
+		if s.sandbox == nil {
+			return nil, ErrTestModeDisabled
+		}
+		payment.DestinationMint = s.testMint(payment.DestinationMint)
+	}
+	payment.DestinationMint = MintAddress(payment.DestinationMint, conf.DestinationMint)
+	if err := s.validateMint(ctx, payment.Livemode, payment.DestinationMint); err != nil {
+		return nil, err
+	}
+	if err := s.validateDestination(ctx, payment.Livemode, payment.DestinationWallet); err != nil {
+		return nil, err
+	}
+	if payment.Settings != nil && payment.Settings.DestinationMint != "" {
+		payment.Settings.DestinationMint = payment.DestinationMint
+	}
+
+	translations, err := marshalTranslations(payment.Translations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payment translations: %w", err)
+	}
+	settings, err := marshalPaymentSettings(payment.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payment settings: %w", err)
+	}
 
 	result, err := s.repo.CreatePayment(ctx, repository.CreatePaymentParams{
 		ExternalID:        sql.NullString{String: payment.ExternalID, Valid: payment.ExternalID != ""},
@@ -49,17 +167,44 @@ func (s *Service) CreatePayment(ctx context.Context, payment *Payment) (*Payment
 		Status:            repository.PaymentStatusNew,
 		Message:           sql.NullString{String: payment.Message, Valid: payment.Message != ""},
 		ExpiresAt:         sql.NullTime{Time: *payment.ExpiresAt, Valid: payment.ExpiresAt != nil},
+		CustomerEmail:     sql.NullString{String: payment.CustomerEmail, Valid: payment.CustomerEmail != ""},
+		Translations:      translations,
+		FeeOnTop:          payment.FeeOnTop,
+		Escrow:            payment.Escrow,
+		Livemode:          payment.Livemode,
+		CallbackUrl:       sql.NullString{String: payment.CallbackURL, Valid: payment.CallbackURL != ""},
+		AllowPartial:      payment.AllowPartial,
+		FiatCurrency:      sql.NullString{String: payment.FiatCurrency, Valid: payment.FiatCurrency != ""},
+		FiatAmount:        int64(payment.FiatAmount),
+		Settings:          settings,
 	})
 	if err != nil {
+		// a concurrent request with the same external id has created the payment after the check above
+		if repository.IsUniqueViolation(err, repository.PaymentsExternalIDIndex) {
+			existing, getErr := s.repo.GetPaymentByExternalID(ctx, payment.ExternalID)
+			if getErr != nil {
+				return nil, fmt.Errorf("failed to get payment by external id: %w", getErr)
+			}
+			return s.existingPayment(conf, existing)
+		}
 		return nil, fmt.Errorf("failed to create payment: %w", err)
 	}
 
 	return castFromRepositoryPayment(result), nil
 }
 
+// existingPayment returns the payment with the duplicate external id in the idempotent mode,
+// or PaymentExistsError otherwise.
+func (s *Service) existingPayment(conf Config, existing repository.Payment) (*Payment, error) {
+	if !conf.IdempotentExternalID {
+		return nil, &PaymentExistsError{PaymentID: existing.ID}
+	}
+	return castFromRepositoryPayment(existing), nil
+}
+
 // GetPayment returns the payment with the given ID.
 func (s *Service) GetPayment(ctx context.Context, id uuid.UUID) (*Payment, error) {
-	result, err := s.repo.GetPayment(ctx, id)
+	result, err := s.repoOf(ctx).GetPayment(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get payment: %w", err)
 	}
@@ -69,7 +214,7 @@ func (s *Service) GetPayment(ctx context.Context, id uuid.UUID) (*Payment, error
 
 // GetPaymentByExternalID returns the payment with the given external ID.
 func (s *Service) GetPaymentByExternalID(ctx context.Context, externalID string) (*Payment, error) {
-	result, err := s.repo.GetPaymentByExternalID(ctx, externalID)
+	result, err := s.repoOf(ctx).GetPaymentByExternalID(ctx, externalID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get payment: %w", err)
 	}
@@ -77,31 +222,119 @@ func (s *Service) GetPaymentByExternalID(ctx context.Context, externalID string)
 	return castFromRepositoryPayment(result), nil
 }
 
+// GetPaymentStatus returns only the status, the latest transaction signature and the update time
+// of the payment with the given ID. It's a single cheap query for the checkout pages polling.
+func (s *Service) GetPaymentStatus(ctx context.Context, id uuid.UUID) (*PaymentStatusInfo, error) {
+	result, err := s.repo.GetPaymentStatus(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment status: %w", err)
+	}
+
+	return &PaymentStatusInfo{
+		Status:    PaymentStatus(result.Status),
+		Signature: result.TxSignature,
+		UpdatedAt: result.UpdatedAt,
+	}, nil
+}
+
 // GeneratePaymentLink generates a new payment link for the given payment.
 func (s *Service) GeneratePaymentLink(ctx context.Context, paymentID uuid.UUID, mint string, applyBonus bool) (string, error) {
 	payment, err := s.GetPayment(ctx, paymentID)
 	if err != nil {
 		return "", fmt.Errorf("failed to get payment: %w", err)
 	}
-	if payment.Status != PaymentStatusNew && payment.Status != PaymentStatusPending {
+	if !payment.Payable() {
 		return "", fmt.Errorf("payment already %s", payment.Status)
 	}
+	if payment.Expired() {
+		return "", ErrPaymentExpired
+	}
 
-	mint = MintAddress(mint, payment.DestinationMint)
+	conf := s.config().withPaymentSettings(payment.Settings)
+	var token string
+	if conf.LinkTokenTTL > 0 {
+		token, err = s.issueLinkToken(ctx, payment, conf.LinkTokenTTL)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return paymentLink(conf, payment, MintAddress(mint, payment.DestinationMint), applyBonus, token), nil
+}
 
+// CheckoutLink returns the payment link of the checkout page opened with the given access token of the payment link.
+// Unlike GeneratePaymentLink, the link carries the same token rather than a new one, so the page can be reloaded
+// without issuing the tokens. The token is verified, see VerifyLinkToken, if the links require the tokens,
+// see Config.LinkTokenTTL, and ignored otherwise.
+func (s *Service) CheckoutLink(ctx context.Context, paymentID uuid.UUID, token string) (string, error) {
+	payment, err := s.GetPayment(ctx, paymentID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get payment: %w", err)
+	}
+	if !payment.Payable() {
+		return "", fmt.Errorf("payment already %s", payment.Status)
+	}
+	if payment.Expired() {
+		return "", ErrPaymentExpired
+	}
+
+	conf := s.config().withPaymentSettings(payment.Settings)
+	if conf.LinkTokenTTL <= 0 {
+		token = ""
+	} else if err := s.VerifyLinkToken(ctx, paymentID, token); err != nil {
+		return "", err
+	}
+
+	return paymentLink(conf, payment, payment.DestinationMint, false, token), nil
+}
+
+// paymentLink returns the Solana Pay transaction request link of the payment with the access token, if any.
+func paymentLink(conf Config, payment *Payment, mint string, applyBonus bool, token string) string {
 	uri := strings.Join([]string{
-		strings.TrimRight(s.conf.SolPayBaseURL, "/"),
-		strings.Trim(paymentID.String(), "/"),
+		strings.TrimRight(conf.SolPayBaseURL, "/"),
+		strings.Trim(payment.ID.String(), "/"),
 		strings.Trim(mint, "/"),
 		strconv.FormatBool(applyBonus),
 	}, "/")
 
-	return fmt.Sprintf("solana:%s", uri), nil
+	q := url.Values{}
+	if conf.LinkSigner != nil {
+		q = conf.LinkSigner.Sign(LinkParams{
+			PaymentID:  payment.ID.String(),
+			Mint:       mint,
+			ApplyBonus: strconv.FormatBool(applyBonus),
+			Amount:     strconv.FormatUint(payment.AmountDue(), 10),
+		}, payment.ExpiresAt)
+	}
+	if token != "" {
+		q.Set(LinkTokenParam, token)
+	}
+	if len(q) > 0 {
+		// Solana Pay requires the url with a query to be url-encoded
+		return fmt.Sprintf("solana:%s", url.QueryEscape(uri+"?"+q.Encode()))
+	}
+
+	return fmt.Sprintf("solana:%s", uri)
 }
 
 // UpdatePaymentStatus updates the status of the payment with the given ID.
+// The held payment is scheduled for auto-release if the escrow release window is set.
 func (s *Service) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status PaymentStatus) error {
-	if _, err := s.repo.UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
+	conf := s.config()
+	if status == PaymentStatusHeld {
+		if _, err := s.repoOf(ctx).HoldPayment(ctx, repository.HoldPaymentParams{
+			ID: id,
+			HeldUntil: sql.NullTime{
+				Time:  time.Now().Add(conf.EscrowReleaseAfter),
+				Valid: conf.EscrowReleaseAfter > 0,
+			},
+		}); err != nil {
+			return fmt.Errorf("failed to hold payment: %w", err)
+		}
+		return nil
+	}
+
+	if _, err := s.repoOf(ctx).UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
 		ID:     id,
 		Status: castToRepositoryPaymentStatus(status),
 	}); err != nil {
@@ -113,7 +346,7 @@ func (s *Service) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status
 
 // CancelPayment cancels the payment with the given ID.
 func (s *Service) CancelPayment(ctx context.Context, id uuid.UUID) error {
-	if _, err := s.repo.UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
+	if _, err := s.repoOf(ctx).UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
 		ID:     id,
 		Status: repository.PaymentStatusCanceled,
 	}); err != nil {
@@ -130,7 +363,7 @@ func (s *Service) CancelPaymentByExternalID(ctx context.Context, externalID stri
 		return err
 	}
 
-	if _, err := s.repo.UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
+	if _, err := s.repoOf(ctx).UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
 		ID:     payment.ID,
 		Status: repository.PaymentStatusCanceled,
 	}); err != nil {
@@ -142,6 +375,7 @@ func (s *Service) CancelPaymentByExternalID(ctx context.Context, externalID stri
 
 // BuildTransaction builds a new transaction for the given payment.
 func (s *Service) BuildTransaction(ctx context.Context, tx *Transaction) (*Transaction, error) {
+	conf := s.config()
 	if tx.PaymentID == uuid.Nil {
 		return nil, fmt.Errorf("payment ID is required")
 	}
@@ -152,20 +386,39 @@ func (s *Service) BuildTransaction(ctx context.Context, tx *Transaction) (*Trans
 	if err != nil {
 		return nil, fmt.Errorf("failed to get payment: %w", err)
 	}
-	if payment.Status != PaymentStatusNew && payment.Status != PaymentStatusPending {
+	if !payment.Payable() {
 		return nil, fmt.Errorf("payment already %s", payment.Status)
 	}
-	payment.DestinationMint = MintAddress(payment.DestinationMint, s.conf.DestinationMint)
+	if payment.Expired() {
+		return nil, ErrPaymentExpired
+	}
+	conf = conf.withPaymentSettings(payment.Settings)
+	payment.DestinationMint = MintAddress(payment.DestinationMint, conf.DestinationMint)
+	// the fiat-priced payment is converted at the current rate, the transaction locks it
+	exchangeRate, err := s.convertFiatAmount(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+	// the amount at least equal to the amount due is the whole amount, e.g. the hint of a signed link
+	if !payment.AllowPartial && tx.PartialAmount > 0 && tx.PartialAmount < payment.AmountDue() {
+		return nil, ErrPartialPaymentNotAllowed
+	}
 	tx.SourceMint = MintAddress(tx.SourceMint, payment.DestinationMint)
+	if tx.TipAmount > 0 && tx.TipDestination != "" {
+		if err := s.validateDestination(ctx, payment.Livemode, tx.TipDestination); err != nil {
+			return nil, err
+		}
+	}
 
-	base64Tx, tx, err := NewPaymentTransactionBuilder(s.sol, s.jup, s.conf).
+	base64Tx, tx, err := NewPaymentTransactionBuilder(s.solanaFor(payment.Livemode), s.jup, conf).
 		SetTransaction(tx, payment).
 		Build(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build transaction: %w", err)
 	}
 
-	repoTx, err := s.repo.CreateTransaction(ctx, repository.CreateTransactionParams{
+	params := repository.CreateTransactionParams{
+		ID:                 uuid.NullUUID{UUID: tx.ID, Valid: true}, // the references are derived from it
 		PaymentID:          tx.PaymentID,
 		Reference:          tx.Reference,
 		SourceWallet:       tx.SourceWallet,
@@ -179,31 +432,442 @@ func (s *Service) BuildTransaction(ctx context.Context, tx *Transaction) (*Trans
 		Memo:               sql.NullString{String: tx.Memo, Valid: tx.Memo != ""},
 		ApplyBonus:         sql.NullBool{Bool: tx.ApplyBonus, Valid: true},
 		AccruedBonusAmount: int64(tx.AccruedBonusAmount),
+		VoucherAmount:      int64(tx.VoucherAmount),
 		Status:             repository.TransactionStatusPending,
-	})
+		ExchangeRate:       sql.NullString{String: exchangeRate, Valid: exchangeRate != ""},
+		TipAmount:          int64(tx.TipAmount),
+		TipDestination:     sql.NullString{String: tx.TipDestination, Valid: tx.TipDestination != ""},
+	}
+	if tx.Surcharge != nil {
+		params.SlippageFee = int64(tx.Surcharge.Slippage)
+	}
+	params.SwapRoute, err = marshalSwapRoute(tx.Route)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create transaction: %w", err)
+		return nil, fmt.Errorf("failed to encode swap route: %w", err)
+	}
+
+	// the single-use link is used up along with storing the transaction, so the token isn't lost
+	// if the transaction isn't stored, and the transaction isn't stored if the token is used concurrently
+	var repoTx repository.Transaction
+	if err := s.inTx(ctx, func(ctx context.Context) error {
+		if tx.LinkToken != "" {
+			if err := s.useLinkToken(ctx, tx.PaymentID, tx.LinkToken); err != nil {
+				return err
+			}
+		}
+
+		created, err := s.repoOf(ctx).CreateTransaction(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+		repoTx = created
+		for _, reference := range tx.References {
+			if err := s.repoOf(ctx).AddTransactionReference(ctx, repository.AddTransactionReferenceParams{
+				TransactionID: created.ID,
+				Reference:     reference,
+			}); err != nil {
+				return fmt.Errorf("failed to add transaction reference: %w", err)
+			}
+		}
+		return s.storeFiatAmount(ctx, payment)
+	}); err != nil {
+		return nil, err
 	}
 
-	result := castFromRepositoryTransaction(repoTx, s.conf)
+	result := castFromRepositoryTransaction(repoTx, conf)
+	result.References = tx.References
 	result.Transaction = base64Tx
+	result.Transactions = tx.Transactions
+
+	return result, nil
+}
+
+// QuoteTransaction estimates the checkout total for the given payment, customer wallet and currency.
+// It doesn't create a transaction.
+func (s *Service) QuoteTransaction(ctx context.Context, tx *Transaction) (*Quote, error) {
+	conf := s.config()
+	if tx.PaymentID == uuid.Nil {
+		return nil, fmt.Errorf("payment ID is required")
+	}
+	if tx.SourceWallet == "" {
+		return nil, fmt.Errorf("sender wallet address is required")
+	}
+	payment, err := s.GetPayment(ctx, tx.PaymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if !payment.Payable() {
+		return nil, fmt.Errorf("payment already %s", payment.Status)
+	}
+	if payment.Expired() {
+		return nil, ErrPaymentExpired
+	}
+	conf = conf.withPaymentSettings(payment.Settings)
+	payment.DestinationMint = MintAddress(payment.DestinationMint, conf.DestinationMint)
+	if _, err := s.convertFiatAmount(ctx, payment); err != nil {
+		return nil, err
+	}
+	tx.SourceMint = MintAddress(tx.SourceMint, payment.DestinationMint)
+
+	quote, err := NewPaymentTransactionBuilder(s.solanaFor(payment.Livemode), s.jup, conf).
+		SetTransaction(tx, payment).
+		Quote(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote transaction: %w", err)
+	}
+
+	quote.ExpiresAt = time.Now().Add(conf.QuoteTTL)
+	if payment.ExpiresAt != nil && payment.ExpiresAt.Before(quote.ExpiresAt) {
+		quote.ExpiresAt = *payment.ExpiresAt
+	}
+
+	return quote, nil
+}
+
+// GetWalletTokens returns the tokens held by the given wallet that can be used to pay for the payment:
+// SOL, the payment destination mint and any SPL token that Jupiter can swap into the destination mint.
+// Tokens are sorted by the sufficient balance first, then by mint address.
+func (s *Service) GetWalletTokens(ctx context.Context, paymentID uuid.UUID, wallet string) ([]*WalletToken, error) {
+	conf := s.config()
+	if wallet == "" {
+		return nil, fmt.Errorf("wallet address is required")
+	}
+	payment, err := s.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if !payment.Payable() {
+		return nil, fmt.Errorf("payment already %s", payment.Status)
+	}
+	if payment.Expired() {
+		return nil, ErrPaymentExpired
+	}
+	conf = conf.withPaymentSettings(payment.Settings)
+	payment.DestinationMint = MintAddress(payment.DestinationMint, conf.DestinationMint)
+	if _, err := s.convertFiatAmount(ctx, payment); err != nil {
+		return nil, err
+	}
+	sol := s.solanaFor(payment.Livemode)
+
+	balances, err := sol.GetTokenAccountsByOwner(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet token accounts: %w", err)
+	}
+	solBalance, err := sol.GetSOLBalance(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet SOL balance: %w", err)
+	}
+	if balances == nil {
+		balances = make(map[string]solana.Balance, 1)
+	}
+	if solBalance.Amount > 0 {
+		balances[SOL] = solBalance
+	}
+	delete(balances, conf.BonusMintAddress) // bonus tokens are applied as a discount, not as a currency
+
+	result := make([]*WalletToken, 0, len(balances))
+	for mint, balance := range balances {
+		quote, err := NewPaymentTransactionBuilder(sol, s.jup, conf).
+			SetTransaction(&Transaction{
+				PaymentID:    payment.ID,
+				SourceWallet: wallet,
+				SourceMint:   mint,
+			}, payment).
+			Quote(ctx)
+		if err != nil {
+			if mint == payment.DestinationMint {
+				return nil, fmt.Errorf("failed to quote transaction: %w", err)
+			}
+			// the mint can't be swapped into the destination mint
+			continue
+		}
+
+		required := quote.InAmount
+		if mint == SOL {
+			required += quote.NetworkFee
+		}
+
+		result = append(result, &WalletToken{
+			Mint:       mint,
+			Balance:    balance,
+			InAmount:   quote.InAmount,
+			Sufficient: balance.Amount >= required,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Sufficient != result[j].Sufficient {
+			return result[i].Sufficient
+		}
+		return result[i].Mint < result[j].Mint
+	})
+
+	return result, nil
+}
+
+// VerifyVoucherRedemption checks that the voucher tokens redeemed in the transaction are burned
+// in the transaction with the given signature.
+// Returns ErrVoucherNotRedeemed if the burned amount is less than the redeemed one.
+func (s *Service) VerifyVoucherRedemption(ctx context.Context, tx *Transaction, signature string) error {
+	if tx.VoucherAmount == 0 {
+		return nil
+	}
+
+	// the transaction destination is the escrow wallet if the payment is held in escrow
+	payment, err := s.GetPayment(ctx, tx.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	mint, ok := s.config().VoucherMints[payment.DestinationWallet]
+	if !ok || mint == "" {
+		return ErrVoucherNotSupported
+	}
+
+	sol := s.solanaFor(payment.Livemode)
+	voucherDecimals, err := sol.GetMintDecimals(ctx, mint)
+	if err != nil {
+		return fmt.Errorf("failed to get voucher mint decimals: %w", err)
+	}
+	destinationDecimals, err := sol.GetMintDecimals(ctx, tx.DestinationMint)
+	if err != nil {
+		return fmt.Errorf("failed to get destination mint decimals: %w", err)
+	}
+
+	amount := utils.ConvertAmount(tx.VoucherAmount, destinationDecimals, voucherDecimals)
+	if err := sol.ValidateTokenBurn(ctx, signature, tx.SourceWallet, mint, amount); err != nil {
+		return fmt.Errorf("%w: %v", ErrVoucherNotRedeemed, err)
+	}
+
+	return nil
+}
+
+// ReleasePayment transfers the held payment funds from the escrow wallet to the merchant wallet.
+// Returns ErrPaymentNotHeld if the payment is not held in escrow or is being released.
+func (s *Service) ReleasePayment(ctx context.Context, id uuid.UUID) (*EscrowRelease, error) {
+	if s.config().EscrowSigner == nil {
+		return nil, ErrEscrowNotSupported
+	}
+
+	// claim the payment, so concurrent releases don't transfer the funds twice
+	payment, err := s.repo.ReleasePayment(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPaymentNotHeld
+		}
+		return nil, fmt.Errorf("failed to release payment: %w", err)
+	}
+
+	result, err := s.releaseEscrow(ctx, castFromRepositoryPayment(payment))
+	if err != nil {
+		// put the payment back on hold, so the release can be retried
+		if _, uerr := s.repo.UpdatePaymentStatus(ctx, repository.UpdatePaymentStatusParams{
+			ID:     id,
+			Status: repository.PaymentStatusHeld,
+		}); uerr != nil {
+			return nil, fmt.Errorf("%w; failed to put payment back on hold: %v", err, uerr)
+		}
+		return nil, err
+	}
 
 	return result, nil
 }
 
-// GetTransactionByReference returns the transaction with the given reference.
+// GetPaymentsToRelease returns the held payments whose escrow release window has passed.
+func (s *Service) GetPaymentsToRelease(ctx context.Context) ([]*Payment, error) {
+	held, err := s.repo.GetPaymentsToRelease(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments to release: %w", err)
+	}
+
+	result := make([]*Payment, 0, len(held))
+	for _, p := range held {
+		result = append(result, castFromRepositoryPayment(p))
+	}
+
+	return result, nil
+}
+
+// releaseEscrow sends the amount received by the completed payment transaction
+// from the escrow wallet to the merchant wallet.
+func (s *Service) releaseEscrow(ctx context.Context, payment *Payment) (*EscrowRelease, error) {
+	conf := s.config()
+	txs, err := s.repo.GetTransactionsByPaymentID(ctx, payment.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment transactions: %w", err)
+	}
+
+	var paid *Transaction
+	for _, tx := range txs {
+		if tx.Status == repository.TransactionStatusCompleted {
+			paid = castFromRepositoryTransaction(tx, conf)
+			break
+		}
+	}
+	if paid == nil {
+		return nil, fmt.Errorf("payment %s has no completed transaction", payment.ID)
+	}
+
+	result := &EscrowRelease{
+		PaymentID: payment.ID,
+		Recipient: payment.DestinationWallet,
+		Mint:      paid.DestinationMint,
+		Amount:    paid.TotalAmount,
+	}
+	if result.Amount == 0 {
+		return result, nil
+	}
+
+	sol := s.solanaFor(payment.Livemode)
+	escrow := conf.EscrowSigner.PublicKey().ToBase58()
+	builder := solana.NewTransactionBuilder(sol).SetFeePayer(escrow)
+	if IsSOL(result.Mint) {
+		builder = builder.AddInstruction(solana.TransferSOL(solana.TransferSOLParams{
+			Sender:    escrow,
+			Recipient: result.Recipient,
+			Amount:    result.Amount,
+		}))
+	} else {
+		builder = builder.AddInstruction(solana.TransferToken(solana.TransferTokenParam{
+			Sender:    escrow,
+			Recipient: result.Recipient,
+			Mint:      result.Mint,
+			Amount:    result.Amount,
+		}))
+	}
+
+	// the release is rebuilt with a fresh blockhash if it's expired before landing
+	result.Signature, err = solana.NewSender(sol).Send(ctx, builder.AddExternalSigner(conf.EscrowSigner).Build)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send escrow release transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetTransactionByReference returns the transaction with the given primary or additional reference.
 func (s *Service) GetTransactionByReference(ctx context.Context, reference string) (*Transaction, error) {
-	result, err := s.repo.GetTransactionByReference(ctx, reference)
+	result, err := s.repoOf(ctx).GetTransactionByReference(ctx, reference)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction by reference=%s: %w", reference, err)
 	}
 
-	return castFromRepositoryTransaction(result, s.conf), nil
+	references, err := s.repoOf(ctx).GetTransactionReferences(ctx, result.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction references: %w", err)
+	}
+
+	tx := castFromRepositoryTransaction(result, s.config())
+	tx.References = references
+
+	return tx, nil
+}
+
+// GetLatestTransaction returns the latest transaction generated for the payment with the given ID.
+// Returns sql.ErrNoRows if there is no transaction yet.
+func (s *Service) GetLatestTransaction(ctx context.Context, paymentID uuid.UUID) (*Transaction, error) {
+	txs, err := s.repo.GetTransactionsByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment transactions: %w", err)
+	}
+	if len(txs) == 0 {
+		return nil, fmt.Errorf("payment %s has no transactions: %w", paymentID, sql.ErrNoRows)
+	}
+
+	return castFromRepositoryTransaction(txs[0], s.config()), nil
+}
+
+// GetPaymentAttempts returns all the transactions generated for the payment with the given ID, the latest first,
+// e.g. one per wallet if several wallets attempt the same payment.
+func (s *Service) GetPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]*PaymentAttempt, error) {
+	txs, err := s.repo.GetTransactionsByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment transactions: %w", err)
+	}
+
+	result := make([]*PaymentAttempt, 0, len(txs))
+	for _, tx := range txs {
+		attempt := &PaymentAttempt{
+			Reference:    tx.Reference,
+			SourceWallet: tx.SourceWallet,
+			SourceMint:   tx.SourceMint,
+			TotalAmount:  uint64(tx.TotalAmount),
+			Status:       castFromRepositoryTransactionStatus(tx.Status),
+			Signature:    tx.TxSignature.String,
+			CreatedAt:    tx.CreatedAt,
+		}
+		if tx.UpdatedAt.Valid {
+			attempt.UpdatedAt = &tx.UpdatedAt.Time
+		}
+		result = append(result, attempt)
+	}
+
+	return result, nil
+}
+
+// RecheckTransaction re-runs the on-chain validation of the transaction with the given reference
+// and fixes the stored status if the chain disagrees.
+// The status is left as is while the chain has no final answer, i.e. the transaction is not found or not confirmed.
+func (s *Service) RecheckTransaction(ctx context.Context, reference string) (*TransactionRecheck, error) {
+	tx, err := s.GetTransactionByReference(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+
+	sol, err := s.solanaForPayment(ctx, tx.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the transaction may be found by any of its references,
+	// if the wallet stripped some of them
+	commitment := s.config().Commitment
+	var match *solana.ReferenceMatch
+	for _, ref := range tx.AllReferences() {
+		match, err = sol.MatchTransactionByReference(ctx, ref, tx.DestinationWallet, tx.ReceivedAmount(), tx.DestinationMint, commitment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recheck transaction: %w", err)
+		}
+		if match.Found {
+			break
+		}
+	}
+
+	result := &TransactionRecheck{
+		Reference:      tx.Reference,
+		PreviousStatus: tx.Status,
+		Status:         tx.Status,
+		Match:          match,
+	}
+
+	switch {
+	case match.Matched:
+		result.Status = TransactionStatusCompleted
+		if err := s.VerifyVoucherRedemption(ctx, tx, match.Signature); err != nil {
+			if !errors.Is(err, ErrVoucherNotRedeemed) {
+				return nil, fmt.Errorf("failed to recheck transaction: %w", err)
+			}
+			result.Status = TransactionStatusFailed
+			match.Error = err.Error()
+		}
+	case match.Confirmed:
+		result.Status = TransactionStatusFailed
+	}
+
+	if result.Status != tx.Status {
+		if err := s.UpdateTransaction(ctx, reference, result.Status, match.Signature); err != nil {
+			return nil, err
+		}
+		result.Updated = true
+	}
+
+	return result, nil
 }
 
 // MarkPaymentsAsExpired marks all payments that are expired as expired.
+// The submitted transactions of the payments are still confirmed within the late confirmation window.
 func (s *Service) MarkPaymentsAsExpired(ctx context.Context) error {
-	if err := s.repo.MarkPaymentsExpired(ctx); err != nil {
+	if err := s.repo.MarkPaymentsExpired(ctx, time.Now().Add(-s.config().LateConfirmation)); err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
 			return fmt.Errorf("failed to mark payments as expired: %w", err)
 		}
@@ -213,15 +877,36 @@ func (s *Service) MarkPaymentsAsExpired(ctx context.Context) error {
 }
 
 // UpdateTransaction updates the status and signature of the transaction with the given reference.
+// The completed transaction settles the payment, so the other pending attempts of the payment are expired
+// and not tracked anymore. The partial payment is settled once its completed transactions pay the whole amount.
 func (s *Service) UpdateTransaction(ctx context.Context, reference string, status TransactionStatus, signature string) error {
-	if _, err := s.repo.UpdateTransactionByReference(ctx, repository.UpdateTransactionByReferenceParams{
+	tx, err := s.repoOf(ctx).UpdateTransactionByReference(ctx, repository.UpdateTransactionByReferenceParams{
 		Reference:   reference,
 		Status:      castToRepositoryTransactionStatus(status),
 		TxSignature: sql.NullString{String: signature, Valid: signature != ""},
-	}); err != nil {
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update transaction status: %w", err)
 	}
 
+	if status == TransactionStatusCompleted {
+		payment, err := s.repoOf(ctx).RefreshPaymentAmountPaid(ctx, tx.PaymentID)
+		if err != nil {
+			return fmt.Errorf("failed to update payment amount paid: %w", err)
+		}
+		// the other attempts can still pay the rest of the partially paid payment
+		if payment.AllowPartial && payment.AmountPaid < payment.Amount {
+			return nil
+		}
+
+		if _, err := s.repoOf(ctx).ExpireOtherPendingTransactions(ctx, repository.ExpireOtherPendingTransactionsParams{
+			PaymentID: tx.PaymentID,
+			ID:        tx.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to expire other payment attempts: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -234,12 +919,23 @@ func (s *Service) GetPendingTransactions(ctx context.Context) ([]*Transaction, e
 
 	result := make([]*Transaction, 0, len(pendingTxs))
 	for _, tx := range pendingTxs {
-		result = append(result, castFromRepositoryTransaction(tx, s.conf))
+		result = append(result, castFromRepositoryTransaction(tx, s.config()))
 	}
 
 	return result, nil
 }
 
+// GetPendingReferences returns the primary and the additional references of all pending transactions,
+// e.g. to subscribe to the reference account notifications.
+func (s *Service) GetPendingReferences(ctx context.Context) ([]string, error) {
+	references, err := s.repo.GetPendingTransactionReferences(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending transaction references: %w", err)
+	}
+
+	return references, nil
+}
+
 // MarkTransactionsAsExpired marks all transactions that are expired as expired.
 func (s *Service) MarkTransactionsAsExpired(ctx context.Context) error {
 	if err := s.repo.MarkTransactionsAsExpired(ctx); err != nil {
@@ -251,15 +947,83 @@ func (s *Service) MarkTransactionsAsExpired(ctx context.Context) error {
 	return nil
 }
 
+// validateMint verifies on-chain that the given non-default mint is an initialized SPL token mint.
+// Known mints and the merchant default mint are trusted in live mode.
+func (s *Service) validateMint(ctx context.Context, livemode bool, mint string) error {
+	if _, ok := knownMintAddresses[mint]; livemode && (ok || mint == MintAddress(s.config().DestinationMint, SOL)) {
+		return nil
+	}
+
+	if _, err := s.solanaFor(livemode).ValidateMint(ctx, mint); err != nil {
+		if errors.Is(err, solana.ErrInvalidMint) {
+			return fmt.Errorf("%w: %s", ErrInvalidMint, mint)
+		}
+		return fmt.Errorf("failed to validate payment currency mint: %w", err)
+	}
+
+	return nil
+}
+
+// validateDestination verifies on-chain that the given non-default destination wallet can own
+// the token accounts, e.g. it's a wallet, a multisig or a vault, but not a token account or a mint.
+// The merchant default wallet is trusted.
+func (s *Service) validateDestination(ctx context.Context, livemode bool, wallet string) error {
+	if wallet == s.config().DestinationWallet {
+		return nil
+	}
+
+	if err := s.solanaFor(livemode).ValidateDestination(ctx, wallet); err != nil {
+		if errors.Is(err, solana.ErrInvalidDestination) {
+			return fmt.Errorf("%w: %s", ErrInvalidDestination, wallet)
+		}
+		return fmt.Errorf("failed to validate payment destination: %w", err)
+	}
+
+	return nil
+}
+
+// validateTTL verifies that the requested payment TTL is within the configured bounds.
+func (c Config) validateTTL(ttl time.Duration) error {
+	// the expiration time is set by the caller a moment before
+	ttl = ttl.Round(time.Second)
+	if ttl <= 0 || (c.MinPaymentTTL > 0 && ttl < c.MinPaymentTTL) || (c.MaxPaymentTTL > 0 && ttl > c.MaxPaymentTTL) {
+		return fmt.Errorf("%w: %s", ErrInvalidTTL, ttl)
+	}
+	return nil
+}
+
+// validateCallbackURL verifies that the payment callback URL is an https URL
+// with the host from the allowlist. The "*." prefix of an allowlist host matches any of its subdomains.
+func (c Config) validateCallbackURL(callbackURL string) error {
+	u, err := url.Parse(callbackURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return fmt.Errorf("%w: %s", ErrCallbackURLDenied, callbackURL)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range c.CallbackHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if host == allowed {
+			return nil
+		}
+		if suffix := strings.TrimPrefix(allowed, "*"); suffix != allowed && strings.HasSuffix(host, suffix) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrCallbackURLDenied, callbackURL)
+}
+
 func (s *Service) mergePaymentWithDefaultConfig(payment *Payment) *Payment {
+	conf := s.config()
 	if payment.DestinationWallet == "" {
-		payment.DestinationWallet = s.conf.DestinationWallet
+		payment.DestinationWallet = conf.DestinationWallet
 	}
 	if payment.DestinationMint == "" {
-		payment.DestinationMint = s.conf.DestinationMint
+		payment.DestinationMint = conf.DestinationMint
 	}
 	if payment.ExpiresAt == nil {
-		payment.ExpiresAt = utils.Pointer(time.Now().Add(s.conf.PaymentTTL))
+		payment.ExpiresAt = utils.Pointer(time.Now().Add(conf.PaymentTTL))
 	}
 	return payment
 }
@@ -12,9 +12,16 @@ type (
 	ServiceEvents struct {
 		PaymentService
 		fireEvent fireEventFunc
+		outbox    eventsOutbox // optional; records the payment status events, see WithOutbox
 	}
 
 	fireEventFunc func(events.EventName, interface{})
+
+	// eventsOutbox runs fn in a database transaction and records the events it fires
+	// in the same transaction, e.g. outbox.Outbox.
+	eventsOutbox interface {
+		InTx(ctx context.Context, fn func(ctx context.Context, fireEvent func(events.EventName, interface{})) error) error
+	}
 )
 
 func NewServiceEvents(svc PaymentService, eventFn fireEventFunc) *ServiceEvents {
@@ -32,7 +39,8 @@ func (s *ServiceEvents) CreatePayment(ctx context.Context, payment *Payment) (*P
 	}
 
 	s.fireEvent(events.PaymentCreated, events.PaymentCreatedPayload{
-		PaymentID: events.PaymentID{PaymentID: result.ID.String()},
+		PaymentID:  events.PaymentID{PaymentID: result.ID.String()},
+		Occurrence: events.OccurredNow(),
 	})
 
 	return result, nil
@@ -46,69 +54,120 @@ func (s *ServiceEvents) GeneratePaymentLink(ctx context.Context, paymentID uuid.
 	}
 
 	s.fireEvent(events.PaymentLinkGenerated, events.PaymentLinkGeneratedPayload{
-		PaymentID: events.PaymentID{PaymentID: paymentID.String()},
-		Link:      result,
+		PaymentID:  events.PaymentID{PaymentID: paymentID.String()},
+		Occurrence: events.OccurredNow(),
+		Link:       result,
 	})
 
 	return result, nil
 }
 
-// CancelPayment cancels the payment with the given ID.
-func (s *ServiceEvents) CancelPayment(ctx context.Context, id uuid.UUID) error {
-	if err := s.PaymentService.CancelPayment(ctx, id); err != nil {
-		return err
+// GenerateTransferRequest generates the Solana Pay transfer-request link of the payment.
+func (s *ServiceEvents) GenerateTransferRequest(ctx context.Context, paymentID uuid.UUID, locale string) (*TransferRequest, error) {
+	result, err := s.PaymentService.GenerateTransferRequest(ctx, paymentID, locale)
+	if err != nil {
+		return nil, err
 	}
 
-	s.fireEvent(events.PaymentCancelled, events.PaymentStatusUpdatedPayload{
-		PaymentID: events.PaymentID{PaymentID: id.String()},
-		Status:    string(PaymentStatusCanceled),
+	s.fireEvent(events.TransactionCreated, events.TransactionCreatedPayload{
+		Occurrence:    events.OccurredNow(),
+		TransactionID: result.Transaction.ID.String(),
+		PaymentID:     events.PaymentID{PaymentID: paymentID.String()},
+		Reference:     result.Transaction.Reference,
 	})
+	s.fireEvent(events.PaymentLinkGenerated, events.PaymentLinkGeneratedPayload{
+		PaymentID:  events.PaymentID{PaymentID: paymentID.String()},
+		Occurrence: events.OccurredNow(),
+		Link:       result.Link,
+	})
+
+	return result, nil
+}
+
+// CancelPayment cancels the payment with the given ID.
+func (s *ServiceEvents) CancelPayment(ctx context.Context, id uuid.UUID) error {
+	return s.inTx(ctx, func(ctx context.Context, fireEvent fireEventFunc) error {
+		if err := s.PaymentService.CancelPayment(ctx, id); err != nil {
+			return err
+		}
+
+		fireEvent(events.PaymentCancelled, events.PaymentStatusUpdatedPayload{
+			PaymentID:  events.PaymentID{PaymentID: id.String()},
+			Occurrence: events.OccurredNow(),
+			Status:     string(PaymentStatusCanceled),
+		})
 
-	return nil
+		return nil
+	})
 }
 
 // CancelPaymentByExternalID cancels the payment with the given external ID.
 func (s *ServiceEvents) CancelPaymentByExternalID(ctx context.Context, externalID string) error {
-	payment, err := s.GetPaymentByExternalID(ctx, externalID)
-	if err != nil {
-		return err
-	}
+	return s.inTx(ctx, func(ctx context.Context, fireEvent fireEventFunc) error {
+		payment, err := s.GetPaymentByExternalID(ctx, externalID)
+		if err != nil {
+			return err
+		}
 
-	if err := s.PaymentService.CancelPaymentByExternalID(ctx, externalID); err != nil {
-		return err
-	}
+		if err := s.PaymentService.CancelPaymentByExternalID(ctx, externalID); err != nil {
+			return err
+		}
 
-	s.fireEvent(events.PaymentCancelled, events.PaymentStatusUpdatedPayload{
-		PaymentID: events.PaymentID{PaymentID: payment.ID.String()},
-		Status:    string(PaymentStatusCanceled),
-	})
+		fireEvent(events.PaymentCancelled, events.PaymentStatusUpdatedPayload{
+			PaymentID:  events.PaymentID{PaymentID: payment.ID.String()},
+			Occurrence: events.OccurredNow(),
+			Status:     string(PaymentStatusCanceled),
+		})
 
-	return nil
+		return nil
+	})
 }
 
 // UpdatePaymentStatus updates the status of the payment with the given ID.
 func (s *ServiceEvents) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status PaymentStatus) error {
-	prev, err := s.GetPayment(ctx, id)
-	if err != nil {
-		return err
-	}
+	return s.inTx(ctx, func(ctx context.Context, fireEvent fireEventFunc) error {
+		prev, err := s.GetPayment(ctx, id)
+		if err != nil {
+			return err
+		}
 
-	if err := s.PaymentService.UpdatePaymentStatus(ctx, id, status); err != nil {
-		return err
-	}
+		if err := s.PaymentService.UpdatePaymentStatus(ctx, id, status); err != nil {
+			return err
+		}
 
-	if prev.Status != status {
-		eventName := getEventName(status)
-		if eventName == "" {
-			return fmt.Errorf("unknown payment status %s", status)
+		if prev.Status != status {
+			eventName := getEventName(status)
+			if eventName == "" {
+				return fmt.Errorf("unknown payment status %s", status)
+			}
+			fireEvent(eventName, events.PaymentStatusUpdatedPayload{
+				PaymentID:  events.PaymentID{PaymentID: id.String()},
+				Occurrence: events.OccurredNow(),
+				Status:     string(status),
+			})
 		}
-		s.fireEvent(eventName, events.PaymentStatusUpdatedPayload{
-			PaymentID: events.PaymentID{PaymentID: id.String()},
-			Status:    string(status),
-		})
+
+		return nil
+	})
+}
+
+// ReleasePayment transfers the held payment funds from the escrow wallet to the merchant wallet.
+func (s *ServiceEvents) ReleasePayment(ctx context.Context, id uuid.UUID) (*EscrowRelease, error) {
+	result, err := s.PaymentService.ReleasePayment(ctx, id)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	s.fireEvent(events.PaymentReleased, events.PaymentReleasedPayload{
+		PaymentID:  events.PaymentID{PaymentID: id.String()},
+		Occurrence: events.OccurredNow(),
+		Recipient:  result.Recipient,
+		Mint:       result.Mint,
+		Amount:     result.Amount,
+		Signature:  result.Signature,
+	})
+
+	return result, nil
 }
 
 // BuildTransaction builds a new transaction for the given payment.
@@ -119,9 +178,11 @@ func (s *ServiceEvents) BuildTransaction(ctx context.Context, tx *Transaction) (
 	}
 
 	s.fireEvent(events.TransactionCreated, events.TransactionCreatedPayload{
+		Occurrence:    events.OccurredNow(),
 		TransactionID: result.ID.String(),
 		PaymentID:     events.PaymentID{PaymentID: result.PaymentID.String()},
 		Reference:     result.Reference,
+		References:    result.References,
 	})
 
 	return result, nil
@@ -129,22 +190,66 @@ func (s *ServiceEvents) BuildTransaction(ctx context.Context, tx *Transaction) (
 
 // UpdateTransaction updates the status and signature of the transaction with the given reference.
 func (s *ServiceEvents) UpdateTransaction(ctx context.Context, reference string, status TransactionStatus, signature string) error {
-	if err := s.PaymentService.UpdateTransaction(ctx, reference, status, signature); err != nil {
-		return err
+	return s.inTx(ctx, func(ctx context.Context, fireEvent fireEventFunc) error {
+		if err := s.PaymentService.UpdateTransaction(ctx, reference, status, signature); err != nil {
+			return err
+		}
+
+		tx, err := s.GetTransactionByReference(ctx, reference)
+		if err != nil {
+			return err
+		}
+
+		fireEvent(events.TransactionUpdated, events.TransactionUpdatedPayload{
+			PaymentID:   events.PaymentID{PaymentID: tx.PaymentID.String()},
+			Occurrence:  events.OccurredNow(),
+			Reference:   tx.Reference,
+			References:  tx.References,
+			Status:      string(tx.Status),
+			Signature:   tx.Signature,
+			Transaction: tx,
+		})
+
+		return nil
+	})
+}
+
+// RecheckTransaction re-runs the on-chain validation of the transaction and fixes the stored status.
+// The transaction.updated event is fired if the status is fixed, so the payment status follows it.
+func (s *ServiceEvents) RecheckTransaction(ctx context.Context, reference string) (*TransactionRecheck, error) {
+	result, err := s.PaymentService.RecheckTransaction(ctx, reference)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Updated {
+		return result, nil
 	}
 
 	tx, err := s.GetTransactionByReference(ctx, reference)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	s.fireEvent(events.TransactionUpdated, events.TransactionUpdatedPayload{
 		PaymentID:   events.PaymentID{PaymentID: tx.PaymentID.String()},
+		Occurrence:  events.OccurredNow(),
 		Reference:   tx.Reference,
+		References:  tx.References,
 		Status:      string(tx.Status),
 		Signature:   tx.Signature,
 		Transaction: tx,
 	})
 
-	return nil
+	return result, nil
+}
+
+// inTx runs fn in the outbox transaction, so the payment updates and their events are committed together.
+// Without the outbox, fn runs as is and the events are fired right away.
+func (s *ServiceEvents) inTx(ctx context.Context, fn func(ctx context.Context, fireEvent fireEventFunc) error) error {
+	if s.outbox == nil {
+		return fn(ctx, s.fireEvent)
+	}
+	return s.outbox.InTx(ctx, func(ctx context.Context, fireEvent func(events.EventName, interface{})) error {
+		return fn(ctx, fireEvent)
+	})
 }
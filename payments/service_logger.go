@@ -3,10 +3,13 @@ package payments
 import (
 	"context"
 
-	"github.com/easypmnt/checkout-api/internal/utils"
 	"github.com/google/uuid"
 )
 
+// ServiceLogger is the hand-written logging decorator of the payment service.
+//
+// Deprecated: use NewServiceChain with WithLogging, which covers every method
+// of the PaymentService interface and redacts the sensitive fields.
 type ServiceLogger struct {
 	PaymentService
 	log Logger
@@ -18,6 +21,9 @@ type Logger interface {
 	Errorf(format string, args ...interface{})
 }
 
+// NewServiceLogger creates a new logging decorator of the payment service.
+//
+// Deprecated: use NewServiceChain with WithLogging.
 func NewServiceLogger(svc PaymentService, log Logger) *ServiceLogger {
 	return &ServiceLogger{
 		PaymentService: svc,
@@ -27,7 +33,7 @@ func NewServiceLogger(svc PaymentService, log Logger) *ServiceLogger {
 
 // CreatePayment creates a new payment.
 func (s *ServiceLogger) CreatePayment(ctx context.Context, payment *Payment) (*Payment, error) {
-	s.log.Debugf("creating payment: %s", utils.AnyToString(payment))
+	s.log.Debugf("creating payment: %s", Redact(payment))
 
 	result, err := s.PaymentService.CreatePayment(ctx, payment)
 	if err != nil {
@@ -81,6 +87,21 @@ func (s *ServiceLogger) GeneratePaymentLink(ctx context.Context, paymentID uuid.
 	return result, nil
 }
 
+// GenerateTransferRequest generates the Solana Pay transfer-request link of the payment.
+func (s *ServiceLogger) GenerateTransferRequest(ctx context.Context, paymentID uuid.UUID, locale string) (*TransferRequest, error) {
+	s.log.Debugf("generating transfer request: id=%s, locale=%s", paymentID.String(), locale)
+
+	result, err := s.PaymentService.GenerateTransferRequest(ctx, paymentID, locale)
+	if err != nil {
+		s.log.Errorf("failed to generate transfer request: %s", err.Error())
+		return nil, err
+	}
+
+	s.log.Debugf("transfer request generated: %s", result.Link)
+
+	return result, nil
+}
+
 // UpdatePaymentStatus updates the status of the payment with the given ID.
 func (s *ServiceLogger) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status PaymentStatus) error {
 	s.log.Debugf("updating payment status: id=%s, status=%s", id.String(), status)
@@ -125,7 +146,7 @@ func (s *ServiceLogger) CancelPaymentByExternalID(ctx context.Context, externalI
 
 // BuildTransaction builds a new transaction for the given payment.
 func (s *ServiceLogger) BuildTransaction(ctx context.Context, tx *Transaction) (*Transaction, error) {
-	s.log.Debugf("building transaction: %s", utils.AnyToString(tx))
+	s.log.Debugf("building transaction: %s", Redact(tx))
 
 	result, err := s.PaymentService.BuildTransaction(ctx, tx)
 	if err != nil {
@@ -138,6 +159,89 @@ func (s *ServiceLogger) BuildTransaction(ctx context.Context, tx *Transaction) (
 	return result, nil
 }
 
+// QuoteTransaction estimates the checkout total for the given payment, customer wallet and currency.
+func (s *ServiceLogger) QuoteTransaction(ctx context.Context, tx *Transaction) (*Quote, error) {
+	s.log.Debugf("quoting transaction: %s", Redact(tx))
+
+	result, err := s.PaymentService.QuoteTransaction(ctx, tx)
+	if err != nil {
+		s.log.Errorf("failed to quote transaction: %s", err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetWalletTokens returns the tokens held by the given wallet that can be used to pay for the payment.
+func (s *ServiceLogger) GetWalletTokens(ctx context.Context, paymentID uuid.UUID, wallet string) ([]*WalletToken, error) {
+	s.log.Debugf("getting wallet tokens: payment_id=%s, wallet=%s", paymentID, wallet)
+
+	result, err := s.PaymentService.GetWalletTokens(ctx, paymentID, wallet)
+	if err != nil {
+		s.log.Errorf("failed to get wallet %s tokens: %s", wallet, err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// VerifyVoucherRedemption checks that the voucher tokens redeemed in the transaction are burned.
+func (s *ServiceLogger) VerifyVoucherRedemption(ctx context.Context, tx *Transaction, signature string) error {
+	s.log.Debugf("verifying voucher redemption: reference=%s, signature=%s", tx.Reference, signature)
+
+	if err := s.PaymentService.VerifyVoucherRedemption(ctx, tx, signature); err != nil {
+		s.log.Errorf("failed to verify voucher redemption of transaction %s: %s", tx.Reference, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ReleasePayment transfers the held payment funds from the escrow wallet to the merchant wallet.
+func (s *ServiceLogger) ReleasePayment(ctx context.Context, id uuid.UUID) (*EscrowRelease, error) {
+	s.log.Debugf("releasing payment: %s", id.String())
+
+	result, err := s.PaymentService.ReleasePayment(ctx, id)
+	if err != nil {
+		s.log.Errorf("failed to release payment %s: %s", id.String(), err.Error())
+		return nil, err
+	}
+
+	s.log.Infof("payment released: %s, signature=%s", id.String(), result.Signature)
+
+	return result, nil
+}
+
+// GetPaymentsToRelease returns the held payments whose escrow release window has passed.
+func (s *ServiceLogger) GetPaymentsToRelease(ctx context.Context) ([]*Payment, error) {
+	s.log.Debugf("getting payments to release")
+
+	result, err := s.PaymentService.GetPaymentsToRelease(ctx)
+	if err != nil {
+		s.log.Errorf("failed to get payments to release: %s", err.Error())
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RecheckTransaction re-runs the on-chain validation of the transaction and fixes the stored status.
+func (s *ServiceLogger) RecheckTransaction(ctx context.Context, reference string) (*TransactionRecheck, error) {
+	s.log.Debugf("rechecking transaction: %s", reference)
+
+	result, err := s.PaymentService.RecheckTransaction(ctx, reference)
+	if err != nil {
+		s.log.Errorf("failed to recheck transaction %s: %s", reference, err.Error())
+		return nil, err
+	}
+
+	if result.Updated {
+		s.log.Infof("transaction %s status fixed by recheck: %s -> %s", reference, result.PreviousStatus, result.Status)
+	}
+
+	return result, nil
+}
+
 // GetTransactionByReference returns the transaction with the given reference.
 func (s *ServiceLogger) GetTransactionByReference(ctx context.Context, reference string) (*Transaction, error) {
 	s.log.Debugf("getting transaction by reference: %s", reference)
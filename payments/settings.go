@@ -0,0 +1,187 @@
+package payments
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/validator"
+)
+
+// Settings are the merchant bonus and settlement settings,
+// which can be changed without restarting the service, see Service.ApplySettings.
+type Settings struct {
+	ApplyBonus           bool   `json:"apply_bonus"`
+	MaxApplyBonusAmount  uint64 `json:"max_apply_bonus_amount"`
+	MaxApplyBonusPercent uint16 `json:"max_apply_bonus_percent"` // 10000 = 100%, 100 = 1%, 1 = 0.01%
+	AccrueBonusRate      uint64 `json:"accrue_bonus_rate"`       // 10000 = 100%; 0 = no bonus accrual
+	DestinationMint      string `json:"destination_mint"`
+	DestinationWallet    string `json:"destination_wallet"`
+	EscrowReleaseAfter   int64  `json:"escrow_release_after"` // seconds; 0 = manual release only
+}
+
+// PaymentSettings override the merchant settings for a single payment, see Payment.Settings.
+// The unset fields keep the merchant settings current at the time the transaction is built.
+type PaymentSettings struct {
+	DestinationMint      string  `json:"destination_mint,omitempty"`
+	ApplyBonus           *bool   `json:"apply_bonus,omitempty"`
+	MaxApplyBonusAmount  *uint64 `json:"max_apply_bonus_amount,omitempty"`
+	MaxApplyBonusPercent *uint16 `json:"max_apply_bonus_percent,omitempty"` // 10000 = 100%, 100 = 1%, 1 = 0.01%
+	AccrueBonusRate      *uint64 `json:"accrue_bonus_rate,omitempty"`       // 10000 = 100%; 0 = no bonus accrual
+	// SolPayBaseURL is the base URL of the transaction request links of the payment, see Config.SolPayBaseURL.
+	SolPayBaseURL string `json:"solana_pay_base_uri,omitempty"`
+}
+
+// Settings returns the runtime settings of the config.
+func (c Config) Settings() Settings {
+	s := Settings{
+		ApplyBonus:           c.ApplyBonus,
+		MaxApplyBonusAmount:  c.MaxApplyBonusAmount,
+		MaxApplyBonusPercent: c.MaxApplyBonusPercent,
+		DestinationMint:      c.DestinationMint,
+		DestinationWallet:    c.DestinationWallet,
+		EscrowReleaseAfter:   int64(c.EscrowReleaseAfter / time.Second),
+	}
+	if c.AccrueBonus {
+		s.AccrueBonusRate = c.AccrueBonusRate
+	}
+	return s
+}
+
+// WithSettings returns a copy of the config with the given runtime settings.
+func (c Config) WithSettings(s Settings) Config {
+	c.ApplyBonus = s.ApplyBonus
+	c.MaxApplyBonusAmount = s.MaxApplyBonusAmount
+	c.MaxApplyBonusPercent = s.MaxApplyBonusPercent
+	c.AccrueBonus = s.AccrueBonusRate > 0
+	c.AccrueBonusRate = s.AccrueBonusRate
+	c.DestinationMint = s.DestinationMint
+	c.DestinationWallet = s.DestinationWallet
+	c.EscrowReleaseAfter = time.Duration(s.EscrowReleaseAfter) * time.Second
+	return c
+}
+
+// withPaymentSettings returns a copy of the config with the settings overridden by the payment.
+func (c Config) withPaymentSettings(ps *PaymentSettings) Config {
+	if ps == nil {
+		return c
+	}
+	if ps.DestinationMint != "" {
+		c.DestinationMint = ps.DestinationMint
+	}
+	if ps.ApplyBonus != nil {
+		c.ApplyBonus = *ps.ApplyBonus
+	}
+	if ps.MaxApplyBonusAmount != nil {
+		c.MaxApplyBonusAmount = *ps.MaxApplyBonusAmount
+	}
+	if ps.MaxApplyBonusPercent != nil {
+		c.MaxApplyBonusPercent = *ps.MaxApplyBonusPercent
+	}
+	if ps.AccrueBonusRate != nil {
+		c.AccrueBonus = *ps.AccrueBonusRate > 0
+		c.AccrueBonusRate = *ps.AccrueBonusRate
+	}
+	if ps.SolPayBaseURL != "" {
+		c.SolPayBaseURL = ps.SolPayBaseURL
+	}
+	return c
+}
+
+// Settings returns the current runtime settings of the service.
+func (s *Service) Settings() Settings {
+	return s.config().Settings()
+}
+
+// CheckSettings returns an error if the settings are invalid
+// or can't be applied to the service, e.g. the bonus mint is not configured.
+func (s *Service) CheckSettings(settings Settings) error {
+	conf := s.config()
+
+	if settings.DestinationMint == "" {
+		return fmt.Errorf("%w: destination mint is required", ErrInvalidSettings)
+	}
+	if err := validator.ValidateSolanaWalletAddr(settings.DestinationWallet); err != nil {
+		return fmt.Errorf("%w: destination wallet: %s", ErrInvalidSettings, err.Error())
+	}
+	if settings.MaxApplyBonusPercent > 10000 {
+		return fmt.Errorf("%w: max apply bonus percent must not exceed 10000", ErrInvalidSettings)
+	}
+	if settings.AccrueBonusRate > 10000 {
+		return fmt.Errorf("%w: accrue bonus rate must not exceed 10000", ErrInvalidSettings)
+	}
+	if settings.EscrowReleaseAfter < 0 {
+		return fmt.Errorf("%w: escrow release window must not be negative", ErrInvalidSettings)
+	}
+	if settings.ApplyBonus && conf.BonusMintAddress == "" {
+		return fmt.Errorf("%w: bonus mint is not configured", ErrInvalidSettings)
+	}
+	if settings.AccrueBonusRate > 0 && conf.BonusAuthority == nil {
+		return fmt.Errorf("%w: bonus mint authority is not configured", ErrInvalidSettings)
+	}
+
+	return nil
+}
+
+// checkPaymentSettings returns an error if the payment settings are invalid
+// or can't be applied on top of the given config.
+func (s *Service) checkPaymentSettings(conf Config, ps *PaymentSettings) error {
+	if ps == nil {
+		return nil
+	}
+	if err := s.CheckSettings(conf.withPaymentSettings(ps).Settings()); err != nil {
+		return err
+	}
+	if ps.SolPayBaseURL != "" {
+		u, err := url.Parse(ps.SolPayBaseURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("%w: solana pay base uri must be an absolute https url", ErrInvalidSettings)
+		}
+	}
+	return nil
+}
+
+// ApplySettings replaces the runtime settings of the service.
+// The payments and transactions in progress keep the settings they were created with.
+func (s *Service) ApplySettings(settings Settings) error {
+	if err := s.CheckSettings(settings); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.conf = s.conf.WithSettings(settings)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// config returns the current service config.
+func (s *Service) config() Config {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.conf
+}
+
+// marshalPaymentSettings encodes the payment settings to store them in the database.
+func marshalPaymentSettings(ps *PaymentSettings) (json.RawMessage, error) {
+	if ps == nil {
+		return nil, nil
+	}
+	return json.Marshal(ps)
+}
+
+// unmarshalPaymentSettings decodes the payment settings stored in the database.
+// Nil if the payment uses the merchant settings.
+func unmarshalPaymentSettings(data json.RawMessage) *PaymentSettings {
+	if len(data) == 0 {
+		return nil
+	}
+
+	var result PaymentSettings
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil
+	}
+
+	return &result
+}
@@ -15,6 +15,13 @@ var defaultMints = map[string]string{
 	"SOL":  SOL,
 }
 
+// Default mint addresses.
+var knownMintAddresses = map[string]struct{}{
+	USDC: {},
+	USDT: {},
+	SOL:  {},
+}
+
 // MintAddress returns the mint address by symbol.
 // If the symbol is not found, it returns the fallback address.
 // Supports only default mints.
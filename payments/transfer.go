@@ -0,0 +1,110 @@
+package payments
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// GenerateTransferRequest generates the Solana Pay transfer-request link of the payment,
+// e.g. solana:<recipient>?amount=1.5&spl-token=<mint>&reference=<reference>&message=...&memo=...
+// for the wallets which don't support the transaction requests.
+//
+// The wallet builds the transfer itself, so the link pays the whole amount due in the destination mint
+// to the single destination wallet: no swap, bonus, voucher or fee surcharge is applied.
+// The link carries a new reference, which is watched as a pending transaction of the payment.
+// The locale selects the translation of the label and the message, see Payment.Translate.
+func (s *Service) GenerateTransferRequest(ctx context.Context, paymentID uuid.UUID, locale string) (*TransferRequest, error) {
+	conf := s.config()
+	payment, err := s.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if !payment.Payable() {
+		return nil, fmt.Errorf("payment already %s", payment.Status)
+	}
+	if payment.Expired() {
+		return nil, ErrPaymentExpired
+	}
+	conf = conf.withPaymentSettings(payment.Settings)
+	payment.DestinationMint = MintAddress(payment.DestinationMint, conf.DestinationMint)
+	exchangeRate, err := s.convertFiatAmount(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+
+	sol := s.solanaFor(payment.Livemode)
+	tx := &Transaction{PaymentID: payment.ID, Locale: locale}
+	NewPaymentTransactionBuilder(sol, s.jup, conf).SetTransaction(tx, payment)
+
+	decimals, err := sol.GetMintDecimals(ctx, tx.DestinationMint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mint decimals: %w", err)
+	}
+
+	repoTx, err := s.repo.CreateTransaction(ctx, repository.CreateTransactionParams{
+		ID:                uuid.NullUUID{UUID: tx.ID, Valid: true},
+		PaymentID:         payment.ID,
+		Reference:         tx.Reference,
+		SourceMint:        tx.DestinationMint,
+		DestinationWallet: tx.DestinationWallet,
+		DestinationMint:   tx.DestinationMint,
+		Amount:            int64(tx.Amount),
+		TotalAmount:       int64(tx.TotalAmount),
+		Message:           sql.NullString{String: tx.Message, Valid: tx.Message != ""},
+		Memo:              sql.NullString{String: tx.Memo, Valid: tx.Memo != ""},
+		ApplyBonus:        sql.NullBool{Bool: false, Valid: true},
+		Status:            repository.TransactionStatusPending,
+		ExchangeRate:      sql.NullString{String: exchangeRate, Valid: exchangeRate != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	if err := s.storeFiatAmount(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	q.Set("amount", formatAmount(tx.TotalAmount, decimals))
+	if !IsSOL(tx.DestinationMint) {
+		q.Set("spl-token", tx.DestinationMint)
+	}
+	q.Set("reference", tx.Reference)
+	if label := payment.Translate(locale).Label; label != "" {
+		q.Set("label", label)
+	}
+	if tx.Message != "" {
+		q.Set("message", tx.Message)
+	}
+	if tx.Memo != "" {
+		q.Set("memo", tx.Memo)
+	}
+
+	return &TransferRequest{
+		Link:        fmt.Sprintf("solana:%s?%s", tx.DestinationWallet, q.Encode()),
+		Transaction: castFromRepositoryTransaction(repoTx, conf),
+	}, nil
+}
+
+// formatAmount formats the amount in the base units as the decimal amount of the whole tokens,
+// without the trailing zeros, e.g. 1500000 with 6 decimals is "1.5".
+func formatAmount(amount uint64, decimals uint8) string {
+	s := strconv.FormatUint(amount, 10)
+	if decimals == 0 {
+		return s
+	}
+	if len(s) <= int(decimals) {
+		s = strings.Repeat("0", int(decimals)-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-int(decimals)], strings.TrimRight(s[len(s)-int(decimals):], "0")
+	if frac == "" {
+		return whole
+	}
+	return whole + "." + frac
+}
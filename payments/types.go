@@ -12,17 +12,42 @@ import (
 
 type (
 	Config struct {
-		ApplyBonus           bool
-		BonusMintAddress     string
-		BonusAuthAccount     string
-		MaxApplyBonusAmount  uint64
-		MaxApplyBonusPercent uint16 // 10000 = 100%, 100 = 1%, 1 = 0.01%
-		AccrueBonus          bool
-		AccrueBonusRate      uint64
-		DestinationMint      string
-		DestinationWallet    string
-		PaymentTTL           time.Duration
-		SolPayBaseURL        string
+		ApplyBonus            bool
+		BonusMintAddress      string
+		BonusAuthority        solana.Signer // signer of the bonus mint authority, e.g. local key or KMS key
+		MaxApplyBonusAmount   uint64
+		MaxApplyBonusPercent  uint16 // 10000 = 100%, 100 = 1%, 1 = 0.01%
+		CheckFrozenBonus      bool   // bonus of a frozen token account is not applied as a discount
+		AccrueBonus           bool
+		AccrueBonusRate       uint64
+		DestinationMint       string
+		DestinationWallet     string
+		WalletSelector        WalletSelector // optional; selects destination wallet from the merchant wallets pool
+		PaymentTTL            time.Duration  // default payment TTL, DefaultPaymentTTL if 0
+		MinPaymentTTL         time.Duration  // minimum requested payment TTL; 0 = no minimum
+		MaxPaymentTTL         time.Duration  // maximum requested payment TTL; 0 = no maximum
+		LateConfirmation      time.Duration  // window after the expiry in which the submitted transactions are still confirmed; 0 = expire at once
+		SolPayBaseURL         string
+		LinkSigner            *LinkSigner       // optional; signs the payment links, see server.WithLinkSigner
+		LinkTokenTTL          time.Duration     // lifetime of the single-use payment link tokens, see server.WithLinkTokens; 0 = reusable links
+		ReferenceDeriver      *ReferenceDeriver // optional; derives the transaction references from the server seed, random if nil
+		PriorityFee           uint64            // estimated priority fee in lamports, quoted to the customer
+		ComputeUnitLimit      uint32            // compute unit limit of the payment transactions; 0 = the runtime default
+		ComputeUnitPrice      uint64            // compute unit price in micro-lamports, the minimum one with AutoPriorityFee; 0 = no priority fee
+		AutoPriorityFee       bool              // estimates the compute unit price from the recent prioritization fees of the transaction accounts
+		PriorityFeePercentile int               // percentile of the recent prioritization fees, solana.DefaultPriorityFeePercentile if 0
+		MaxComputeUnitPrice   uint64            // cap of the estimated compute unit price in micro-lamports; 0 = no cap
+		SwapSlippageBps       uint16            // 10000 = 100%, 100 = 1%, 1 = 0.01%; charged if the payment fee is on top
+		MaxPriceImpactBps     uint16            // 10000 = 100%, 100 = 1%; swaps with a higher price impact must be accepted by the customer; 0 = no limit
+		QuoteTTL              time.Duration     // how long a checkout quote is valid
+		VoucherMints          map[string]string // merchant (destination) wallet => voucher mint, redeemable 1:1 for the destination mint
+		EscrowSigner          solana.Signer     // optional; signer of the escrow wallet, it also pays the release transaction fees
+		DepositDeriver        *DepositDeriver   // optional; derives the payment deposit addresses, see Service.CreateDepositAddress
+		DepositFeePayer       solana.Signer     // optional; pays the fees of the deposit sweeps, required with DepositDeriver
+		EscrowReleaseAfter    time.Duration     // auto-release window of the held payments; 0 = manual release only
+		Commitment            solana.Commitment // commitment level the payment transactions must reach to be confirmed; finalized if empty
+		CallbackHosts         []string          // allowlist of the payment callback url hosts, e.g. hooks.example.com or *.example.com; callbacks are disabled if empty
+		IdempotentExternalID  bool              // a duplicate external id returns the existing payment instead of ErrPaymentExists
 	}
 
 	// solanaClient is an RPC client for Solana.
@@ -30,27 +55,65 @@ type (
 		GetLatestBlockhash(ctx context.Context) (string, error)
 		DoesTokenAccountExist(ctx context.Context, base58AtaAddr string) (bool, error)
 		GetMinimumBalanceForRentExemption(ctx context.Context, size uint64) (uint64, error)
+		GetSOLBalance(ctx context.Context, base58Addr string) (solana.Balance, error)
 		GetTokenBalance(ctx context.Context, base58Addr, base58MintAddr string) (solana.Balance, error)
+		GetTokenAccountsByOwner(ctx context.Context, base58Addr string) (map[string]solana.Balance, error)
+		IsTokenAccountFrozen(ctx context.Context, base58Addr, base58MintAddr string) (bool, error)
+		GetMintDecimals(ctx context.Context, base58MintAddr string) (uint8, error)
+		ValidateMint(ctx context.Context, base58MintAddr string) (uint8, error)
+		ValidateDestination(ctx context.Context, base58Addr string) error
+		ValidateTokenBurn(ctx context.Context, txSignature, owner, mint string, amount uint64) error
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+		GetTransactionStatus(ctx context.Context, txhash string, commitment solana.Commitment) (solana.TransactionStatus, error)
+		MatchTransactionByReference(ctx context.Context, reference, destination string, amount uint64, mint string, commitment solana.Commitment) (*solana.ReferenceMatch, error)
+		GetRecentPrioritizationFees(ctx context.Context, base58Accounts ...string) ([]solana.PrioritizationFee, error)
 	}
 
 	// jupiterClient is an REST API client for Jupiter.
 	jupiterClient interface {
-		BestSwap(params jupiter.BestSwapParams) (string, error)
+		Swap(ctx context.Context, params jupiter.SwapParams) (string, error)
+		Quote(ctx context.Context, params jupiter.QuoteParams) (jupiter.QuoteResponse, error)
 	}
 
 	paymentRepository interface {
 		CreatePayment(ctx context.Context, arg repository.CreatePaymentParams) (repository.Payment, error)
 		GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error)
 		GetPaymentByExternalID(ctx context.Context, externalID string) (repository.Payment, error)
-		MarkPaymentsExpired(ctx context.Context) error
+		GetPaymentStatus(ctx context.Context, id uuid.UUID) (repository.GetPaymentStatusRow, error)
+		MarkPaymentsExpired(ctx context.Context, expiredBefore time.Time) error
+		HoldPayment(ctx context.Context, arg repository.HoldPaymentParams) (repository.Payment, error)
+		ReleasePayment(ctx context.Context, id uuid.UUID) (repository.Payment, error)
+		GetPaymentsToRelease(ctx context.Context) ([]repository.Payment, error)
 		UpdatePaymentStatus(ctx context.Context, arg repository.UpdatePaymentStatusParams) (repository.Payment, error)
+		RefreshPaymentAmountPaid(ctx context.Context, id uuid.UUID) (repository.Payment, error)
+		UpdatePaymentAmount(ctx context.Context, arg repository.UpdatePaymentAmountParams) (repository.Payment, error)
 
 		CreateTransaction(ctx context.Context, arg repository.CreateTransactionParams) (repository.Transaction, error)
+		AddTransactionReference(ctx context.Context, arg repository.AddTransactionReferenceParams) error
+		GetTransactionReferences(ctx context.Context, transactionID uuid.UUID) ([]string, error)
 		GetTransactionByPaymentIDSourceWalletAndMint(ctx context.Context, arg repository.GetTransactionByPaymentIDSourceWalletAndMintParams) (repository.Transaction, error)
 		GetTransactionByReference(ctx context.Context, reference string) (repository.Transaction, error)
 		GetTransactionsByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]repository.Transaction, error)
 		UpdateTransactionByReference(ctx context.Context, arg repository.UpdateTransactionByReferenceParams) (repository.Transaction, error)
+		ExpireOtherPendingTransactions(ctx context.Context, arg repository.ExpireOtherPendingTransactionsParams) (int64, error)
 		GetPendingTransactions(ctx context.Context) ([]repository.Transaction, error)
+		GetPendingTransactionReferences(ctx context.Context) ([]string, error)
 		MarkTransactionsAsExpired(ctx context.Context) error
+
+		CreateDepositAddress(ctx context.Context, arg repository.CreateDepositAddressParams) (repository.PaymentDepositAddress, error)
+		GetDepositAddressByPaymentID(ctx context.Context, paymentID uuid.UUID) (repository.PaymentDepositAddress, error)
+		GetDepositAddressesToSweep(ctx context.Context) ([]repository.PaymentDepositAddress, error)
+		MarkDepositAddressSwept(ctx context.Context, arg repository.MarkDepositAddressSweptParams) (repository.PaymentDepositAddress, error)
+
+		CreateLinkToken(ctx context.Context, arg repository.CreateLinkTokenParams) error
+		GetLinkToken(ctx context.Context, tokenHash string) (repository.PaymentLinkToken, error)
+		UseLinkToken(ctx context.Context, arg repository.UseLinkTokenParams) (int64, error)
+		DeleteExpiredLinkTokens(ctx context.Context) (int64, error)
+	}
+
+	// txRepository is the payment repository which runs the queries in a database transaction,
+	// e.g. repository.Storage, see Service.inTx.
+	txRepository interface {
+		InTx(ctx context.Context, fn func(q repository.Querier) error) error
 	}
 )
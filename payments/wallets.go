@@ -0,0 +1,134 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// Supported wallet rotation strategies.
+const (
+	WalletRotationRoundRobin       = "round_robin"
+	WalletRotationBalanceThreshold = "balance_threshold"
+)
+
+type (
+	// WalletSelector selects a destination wallet from the merchant wallets pool for a new payment.
+	WalletSelector interface {
+		SelectWallet(ctx context.Context, mint string) (string, error)
+	}
+
+	// RoundRobinWalletSelector rotates the pool wallets one by one.
+	RoundRobinWalletSelector struct {
+		wallets []string
+		next    uint64
+	}
+
+	// BalanceThresholdWalletSelector selects the first pool wallet which balance of the payment mint
+	// is below the threshold, so funds are accumulated in one wallet at a time.
+	// If all the wallets are above the threshold, the wallet with the lowest balance is selected.
+	BalanceThresholdWalletSelector struct {
+		wallets   []string
+		threshold uint64
+		sol       walletBalanceGetter
+	}
+
+	walletBalanceGetter interface {
+		DoesTokenAccountExist(ctx context.Context, base58AtaAddr string) (bool, error)
+		GetSOLBalance(ctx context.Context, base58Addr string) (solana.Balance, error)
+		GetTokenBalance(ctx context.Context, base58Addr, base58MintAddr string) (solana.Balance, error)
+	}
+)
+
+// NewWalletSelector creates a wallet selector with the given rotation strategy.
+// Returns nil if the pool is empty, so the default destination wallet is used.
+func NewWalletSelector(strategy string, wallets []string, threshold uint64, sol walletBalanceGetter) (WalletSelector, error) {
+	if len(wallets) == 0 {
+		return nil, nil
+	}
+
+	switch strategy {
+	case WalletRotationRoundRobin, "":
+		return NewRoundRobinWalletSelector(wallets...), nil
+	case WalletRotationBalanceThreshold:
+		return NewBalanceThresholdWalletSelector(sol, threshold, wallets...), nil
+	}
+
+	return nil, fmt.Errorf("unsupported wallet rotation strategy: %s", strategy)
+}
+
+// NewRoundRobinWalletSelector creates a new round-robin wallet selector.
+func NewRoundRobinWalletSelector(wallets ...string) *RoundRobinWalletSelector {
+	if len(wallets) == 0 {
+		panic("wallets pool is empty")
+	}
+	return &RoundRobinWalletSelector{wallets: wallets}
+}
+
+// SelectWallet returns the next wallet of the pool.
+func (s *RoundRobinWalletSelector) SelectWallet(_ context.Context, _ string) (string, error) {
+	n := atomic.AddUint64(&s.next, 1) - 1
+	return s.wallets[n%uint64(len(s.wallets))], nil
+}
+
+// NewBalanceThresholdWalletSelector creates a new balance-threshold wallet selector.
+// The threshold is in minimal units of the payment mint.
+func NewBalanceThresholdWalletSelector(sol walletBalanceGetter, threshold uint64, wallets ...string) *BalanceThresholdWalletSelector {
+	if len(wallets) == 0 {
+		panic("wallets pool is empty")
+	}
+	return &BalanceThresholdWalletSelector{
+		wallets:   wallets,
+		threshold: threshold,
+		sol:       sol,
+	}
+}
+
+// SelectWallet returns the first wallet which balance is below the threshold.
+func (s *BalanceThresholdWalletSelector) SelectWallet(ctx context.Context, mint string) (string, error) {
+	var (
+		lowest        string
+		lowestBalance uint64
+	)
+
+	for _, wallet := range s.wallets {
+		balance, err := s.balance(ctx, wallet, mint)
+		if err != nil {
+			return "", fmt.Errorf("failed to get balance of wallet %s: %w", wallet, err)
+		}
+		if balance < s.threshold {
+			return wallet, nil
+		}
+		if lowest == "" || balance < lowestBalance {
+			lowest, lowestBalance = wallet, balance
+		}
+	}
+
+	return lowest, nil
+}
+
+// balance returns the wallet balance of the given mint.
+// A missing token account means zero balance.
+func (s *BalanceThresholdWalletSelector) balance(ctx context.Context, wallet, mint string) (uint64, error) {
+	if IsSOL(mint) {
+		b, err := s.sol.GetSOLBalance(ctx, wallet)
+		return b.Amount, err
+	}
+
+	ata, err := solana.AssociatedTokenAddress(wallet, mint)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := s.sol.DoesTokenAccountExist(ctx, ata); err != nil {
+		if errors.Is(err, solana.ErrTokenAccountDoesNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	b, err := s.sol.GetTokenBalance(ctx, wallet, mint)
+	return b.Amount, err
+}
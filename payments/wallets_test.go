@@ -0,0 +1,54 @@
+package payments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBalanceGetter map[string]uint64
+
+func (f fakeBalanceGetter) DoesTokenAccountExist(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
+
+func (f fakeBalanceGetter) GetSOLBalance(_ context.Context, addr string) (solana.Balance, error) {
+	return solana.NewBalance(f[addr], 9), nil
+}
+
+func (f fakeBalanceGetter) GetTokenBalance(_ context.Context, addr, _ string) (solana.Balance, error) {
+	return solana.NewBalance(f[addr], 6), nil
+}
+
+func TestRoundRobinWalletSelector(t *testing.T) {
+	s := NewRoundRobinWalletSelector("a", "b", "c")
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		w, err := s.SelectWallet(context.Background(), SOL)
+		require.NoError(t, err)
+		got = append(got, w)
+	}
+
+	require.Equal(t, []string{"a", "b", "c", "a"}, got)
+}
+
+func TestBalanceThresholdWalletSelector(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("first below threshold", func(t *testing.T) {
+		s := NewBalanceThresholdWalletSelector(fakeBalanceGetter{"a": 100, "b": 5, "c": 1}, 10, "a", "b", "c")
+		w, err := s.SelectWallet(ctx, SOL)
+		require.NoError(t, err)
+		require.Equal(t, "b", w)
+	})
+
+	t.Run("all above threshold", func(t *testing.T) {
+		s := NewBalanceThresholdWalletSelector(fakeBalanceGetter{"a": 100, "b": 50, "c": 70}, 10, "a", "b", "c")
+		w, err := s.SelectWallet(ctx, SOL)
+		require.NoError(t, err)
+		require.Equal(t, "b", w)
+	})
+}
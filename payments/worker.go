@@ -3,9 +3,12 @@ package payments
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 )
 
@@ -15,6 +18,9 @@ const (
 	TaskCheckPaymentByReference   = "check_payment_by_reference"
 	TaskMarkTransactionsAsExpired = "mark_transactions_as_expired"
 	TaskCheckPendingTransactions  = "check_pending_transactions"
+	TaskReleaseHeldPayments       = "release_held_payments"
+	TaskSweepDepositAddresses     = "sweep_deposit_addresses"
+	TaskDeleteExpiredLinkTokens   = "delete_expired_link_tokens"
 )
 
 // Reference payload to check payment by reference task.
@@ -25,21 +31,35 @@ type ReferencePayload struct {
 type (
 	// Worker is a task handler for email delivery.
 	Worker struct {
-		svc paymentService
-		sol workerSolanaClient
-		enq paymentEnqueuer
+		svc     paymentService
+		sol     workerSolanaClient
+		sandbox workerSolanaClient // optional; validates the test-mode payments
+		enq     paymentEnqueuer
+
+		commitment solana.Commitment // finalized if empty
 	}
 
+	// WorkerOption is a function that configures the payments worker.
+	WorkerOption func(*Worker)
+
 	paymentService interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (*Payment, error)
 		MarkPaymentsAsExpired(ctx context.Context) error
 		GetTransactionByReference(ctx context.Context, reference string) (*Transaction, error)
 		UpdateTransaction(ctx context.Context, reference string, status TransactionStatus, signature string) error
 		MarkTransactionsAsExpired(ctx context.Context) error
 		GetPendingTransactions(ctx context.Context) ([]*Transaction, error)
+		VerifyVoucherRedemption(ctx context.Context, tx *Transaction, signature string) error
+		GetPaymentsToRelease(ctx context.Context) ([]*Payment, error)
+		ReleasePayment(ctx context.Context, id uuid.UUID) (*EscrowRelease, error)
+		SweepDepositAddresses(ctx context.Context) error
+		DeleteExpiredLinkTokens(ctx context.Context) error
 	}
 
 	workerSolanaClient interface {
-		ValidateTransactionByReference(ctx context.Context, reference, destination string, amount uint64, mint string) (string, error)
+		ValidateTransactionByReference(ctx context.Context, reference, destination string, amount uint64, mint string, commitment solana.Commitment) (string, error)
+		// Available returns false during the RPC outage, when the calls fail fast.
+		Available() bool
 	}
 
 	paymentEnqueuer interface {
@@ -48,8 +68,28 @@ type (
 )
 
 // NewWorker creates a new payments task handler.
-func NewWorker(svc paymentService, sol workerSolanaClient, enq paymentEnqueuer) *Worker {
-	return &Worker{svc: svc, sol: sol, enq: enq}
+func NewWorker(svc paymentService, sol workerSolanaClient, enq paymentEnqueuer, opts ...WorkerOption) *Worker {
+	w := &Worker{svc: svc, sol: sol, enq: enq}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// WithSandboxClient configures the worker to validate the test-mode payment transactions
+// with the given Solana client, e.g. a devnet one. See WithSandbox.
+func WithSandboxClient(sol workerSolanaClient) WorkerOption {
+	return func(w *Worker) {
+		w.sandbox = sol
+	}
+}
+
+// WithCommitment sets the commitment level the payment transactions must reach
+// to be confirmed by the worker. It should match Config.Commitment of the payments service.
+func WithCommitment(commitment solana.Commitment) WorkerOption {
+	return func(w *Worker) {
+		w.commitment = commitment
+	}
 }
 
 // Register registers task handlers for email delivery.
@@ -58,6 +98,9 @@ func (w *Worker) Register(mux *asynq.ServeMux) {
 	mux.HandleFunc(TaskCheckPaymentByReference, w.CheckPaymentByReference)
 	mux.HandleFunc(TaskMarkTransactionsAsExpired, w.MarkTransactionsAsExpired)
 	mux.HandleFunc(TaskCheckPendingTransactions, w.CheckPendingTransactions)
+	mux.HandleFunc(TaskReleaseHeldPayments, w.ReleaseHeldPayments)
+	mux.HandleFunc(TaskSweepDepositAddresses, w.SweepDepositAddresses)
+	mux.HandleFunc(TaskDeleteExpiredLinkTokens, w.DeleteExpiredLinkTokens)
 }
 
 // FireEvent sends a webhook event to the specified URL.
@@ -70,12 +113,17 @@ func (w *Worker) MarkPaymentsAsExpired(ctx context.Context, t *asynq.Task) error
 }
 
 // CheckPaymentByReference checks payment status by reference and unsubscribes from account notifications.
+// The task is retried later if the chain is temporarily unavailable.
 func (w *Worker) CheckPaymentByReference(ctx context.Context, t *asynq.Task) error {
 	var p ReferencePayload
 	if err := json.Unmarshal(t.Payload(), &p); err != nil {
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
+	if !w.sol.Available() {
+		return fmt.Errorf("worker: %w", solana.ErrChainUnavailable)
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, time.Minute*2)
 	defer cancel()
 
@@ -97,18 +145,27 @@ func (w *Worker) CheckPaymentByReference(ctx context.Context, t *asynq.Task) err
 				return nil
 			}
 
-			txSign, err := w.sol.ValidateTransactionByReference(
-				ctx,
-				p.Reference,
-				tx.DestinationWallet,
-				tx.TotalAmount,
-				tx.DestinationMint,
-			)
+			txSign, err := w.validateTransaction(ctx, tx)
 			if err != nil {
+				// don't hold the worker during the rpc outage
+				if errors.Is(err, solana.ErrChainUnavailable) {
+					return fmt.Errorf("worker: %w", err)
+				}
 				continue
 				// return fmt.Errorf("failed to validate transaction by reference: %w", err)
 			}
 
+			// the transaction is confirmed, so the missing voucher burn can't be fixed by retries
+			if err := w.svc.VerifyVoucherRedemption(ctx, tx, txSign); err != nil {
+				if errors.Is(err, ErrVoucherNotRedeemed) {
+					if err := w.svc.UpdateTransaction(ctx, p.Reference, TransactionStatusFailed, txSign); err != nil {
+						continue
+					}
+					return nil
+				}
+				continue
+			}
+
 			if err := w.svc.UpdateTransaction(ctx, p.Reference, TransactionStatusCompleted, txSign); err != nil {
 				continue
 				// return fmt.Errorf("failed to update transaction status: %w", err)
@@ -119,6 +176,36 @@ func (w *Worker) CheckPaymentByReference(ctx context.Context, t *asynq.Task) err
 	}
 }
 
+// validateTransaction validates the transaction against each of its references
+// and returns the signature of the first matched one.
+// The references may be stripped by the wallet, so a single match is enough.
+func (w *Worker) validateTransaction(ctx context.Context, tx *Transaction) (string, error) {
+	sol := w.sol
+	if w.sandbox != nil {
+		payment, err := w.svc.GetPayment(ctx, tx.PaymentID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get payment: %w", err)
+		}
+		if !payment.Livemode {
+			sol = w.sandbox
+		}
+	}
+
+	var lastErr error
+	for _, ref := range tx.AllReferences() {
+		txSign, err := sol.ValidateTransactionByReference(ctx, ref, tx.DestinationWallet, tx.ReceivedAmount(), tx.DestinationMint, w.commitment)
+		if err == nil {
+			return txSign, nil
+		}
+		if errors.Is(err, solana.ErrChainUnavailable) {
+			return "", err
+		}
+		lastErr = err
+	}
+
+	return "", lastErr
+}
+
 // MarkTransactionsAsExpired marks transactions as expired.
 func (w *Worker) MarkTransactionsAsExpired(ctx context.Context, t *asynq.Task) error {
 	if err := w.svc.MarkTransactionsAsExpired(ctx); err != nil {
@@ -129,7 +216,12 @@ func (w *Worker) MarkTransactionsAsExpired(ctx context.Context, t *asynq.Task) e
 }
 
 // CheckPendingTransactions checks pending transactions.
+// It's skipped if the chain is temporarily unavailable, the next scheduled run will check them.
 func (w *Worker) CheckPendingTransactions(ctx context.Context, t *asynq.Task) error {
+	if !w.sol.Available() {
+		return nil
+	}
+
 	txs, err := w.svc.GetPendingTransactions(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get pending transactions: %w", err)
@@ -143,3 +235,49 @@ func (w *Worker) CheckPendingTransactions(ctx context.Context, t *asynq.Task) er
 
 	return nil
 }
+
+// ReleaseHeldPayments releases the held payments whose escrow release window has passed.
+// The payment that is already being released is skipped.
+// It's skipped if the chain is temporarily unavailable, the next scheduled run will release them.
+func (w *Worker) ReleaseHeldPayments(ctx context.Context, t *asynq.Task) error {
+	if !w.sol.Available() {
+		return nil
+	}
+
+	held, err := w.svc.GetPaymentsToRelease(ctx)
+	if err != nil {
+		return fmt.Errorf("worker: %w", err)
+	}
+
+	var lastErr error
+	for _, p := range held {
+		if _, err := w.svc.ReleasePayment(ctx, p.ID); err != nil && !errors.Is(err, ErrPaymentNotHeld) {
+			lastErr = fmt.Errorf("worker: failed to release payment %s: %w", p.ID, err)
+		}
+	}
+
+	return lastErr
+}
+
+// SweepDepositAddresses transfers the funds of the paid deposit addresses to the merchant wallet.
+// It's skipped if the chain is temporarily unavailable, the next scheduled run will sweep them.
+func (w *Worker) SweepDepositAddresses(ctx context.Context, t *asynq.Task) error {
+	if !w.sol.Available() {
+		return nil
+	}
+
+	if err := w.svc.SweepDepositAddresses(ctx); err != nil {
+		return fmt.Errorf("worker: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredLinkTokens purges the expired single-use tokens of the payment links.
+func (w *Worker) DeleteExpiredLinkTokens(ctx context.Context, t *asynq.Task) error {
+	if err := w.svc.DeleteExpiredLinkTokens(ctx); err != nil {
+		return fmt.Errorf("worker: %w", err)
+	}
+
+	return nil
+}
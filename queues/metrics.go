@@ -0,0 +1,22 @@
+package queues
+
+import "github.com/easypmnt/checkout-api/internal/metrics"
+
+// Queue metrics.
+var (
+	queueTasks = metrics.NewGaugeVec(
+		"asynq_queue_tasks",
+		"Number of the tasks in the queue by state.",
+		"queue", "state",
+	)
+	queueLatency = metrics.NewGaugeVec(
+		"asynq_queue_latency_seconds",
+		"Age of the oldest pending task in the queue in seconds.",
+		"queue",
+	)
+	queuePaused = metrics.NewGaugeVec(
+		"asynq_queue_paused",
+		"Whether the queue is paused (1) or not (0).",
+		"queue",
+	)
+)
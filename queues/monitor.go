@@ -0,0 +1,177 @@
+// Package queues exports the asynq queue stats as metrics
+// and reports the stuck queues through the health check.
+package queues
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// DefaultInterval is the default interval of the queue stats collection.
+const DefaultInterval = 15 * time.Second
+
+type (
+	// Monitor periodically collects the stats of the queues.
+	Monitor struct {
+		insp     inspector
+		queues   []string
+		interval time.Duration
+		log      logger
+
+		maxLatency  time.Duration
+		maxRetry    int
+		maxArchived int
+
+		mu       sync.RWMutex
+		problems map[string]string // queue => problem, if the queue is unhealthy
+	}
+
+	// MonitorOption is a function that configures the queue monitor.
+	MonitorOption func(*Monitor)
+
+	// inspector returns the queue stats, e.g. asynq.Inspector.
+	inspector interface {
+		GetQueueInfo(queue string) (*asynq.QueueInfo, error)
+	}
+
+	logger interface {
+		Errorf(format string, args ...interface{})
+	}
+)
+
+// NewMonitor creates a new monitor of the given queues.
+// DefaultInterval is used if interval is zero.
+func NewMonitor(insp inspector, queues []string, interval time.Duration, log logger, opts ...MonitorOption) *Monitor {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+
+	m := &Monitor{
+		insp:     insp,
+		queues:   queues,
+		interval: interval,
+		log:      log,
+		problems: make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// WithMaxLatency marks the queue unhealthy if its oldest pending task waits longer than the given duration.
+// Zero disables the check.
+func WithMaxLatency(d time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		m.maxLatency = d
+	}
+}
+
+// WithMaxRetry marks the queue unhealthy if it has more than the given number of tasks waiting for a retry.
+// Zero disables the check.
+func WithMaxRetry(n int) MonitorOption {
+	return func(m *Monitor) {
+		m.maxRetry = n
+	}
+}
+
+// WithMaxArchived marks the queue unhealthy if it has more than the given number of archived (dead) tasks.
+// Zero disables the check.
+func WithMaxArchived(n int) MonitorOption {
+	return func(m *Monitor) {
+		m.maxArchived = n
+	}
+}
+
+// Run collects the queue stats periodically until the context is canceled.
+func (m *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.Collect()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect updates the queue metrics and health.
+func (m *Monitor) Collect() {
+	problems := make(map[string]string)
+	for _, queue := range m.queues {
+		info, err := m.insp.GetQueueInfo(queue)
+		if err != nil {
+			m.log.Errorf("queues: failed to get %s queue info: %s", queue, err.Error())
+			problems[queue] = "stats are unavailable"
+			continue
+		}
+
+		queueTasks.WithLabelValues(queue, "pending").Set(float64(info.Pending))
+		queueTasks.WithLabelValues(queue, "active").Set(float64(info.Active))
+		queueTasks.WithLabelValues(queue, "scheduled").Set(float64(info.Scheduled))
+		queueTasks.WithLabelValues(queue, "retry").Set(float64(info.Retry))
+		queueTasks.WithLabelValues(queue, "archived").Set(float64(info.Archived))
+		queueLatency.WithLabelValues(queue).Set(info.Latency.Seconds())
+		queuePaused.WithLabelValues(queue).Set(boolToFloat(info.Paused))
+
+		if problem := m.check(info); problem != "" {
+			problems[queue] = problem
+		}
+	}
+
+	m.mu.Lock()
+	m.problems = problems
+	m.mu.Unlock()
+}
+
+// Check returns an error describing the unhealthy queues, if any.
+// It's meant for the health check endpoint.
+func (m *Monitor) Check() error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if len(m.problems) == 0 {
+		return nil
+	}
+
+	result := make([]string, 0, len(m.problems))
+	for queue, problem := range m.problems {
+		result = append(result, queue+": "+problem)
+	}
+	sort.Strings(result)
+
+	return fmt.Errorf("unhealthy queues: %s", strings.Join(result, "; "))
+}
+
+// check returns the problem of the queue, or an empty string if the queue is healthy.
+func (m *Monitor) check(info *asynq.QueueInfo) string {
+	switch {
+	case info.Paused:
+		return "paused"
+	case m.maxLatency > 0 && info.Latency > m.maxLatency:
+		return fmt.Sprintf("oldest pending task waits for %s", info.Latency.Round(time.Second))
+	case m.maxRetry > 0 && info.Retry > m.maxRetry:
+		return fmt.Sprintf("%d tasks wait for retry", info.Retry)
+	case m.maxArchived > 0 && info.Archived > m.maxArchived:
+		return fmt.Sprintf("%d tasks are archived", info.Archived)
+	}
+	return ""
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,59 @@
+package queues
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/stretchr/testify/require"
+)
+
+type inspectorMock map[string]*asynq.QueueInfo
+
+func (i inspectorMock) GetQueueInfo(queue string) (*asynq.QueueInfo, error) {
+	info, ok := i[queue]
+	if !ok {
+		return nil, errors.New("queue not found")
+	}
+	return info, nil
+}
+
+type loggerMock struct{}
+
+func (loggerMock) Errorf(format string, args ...interface{}) {}
+
+func TestMonitor(t *testing.T) {
+	insp := inspectorMock{
+		"default": {Queue: "default", Pending: 3, Latency: time.Second},
+	}
+	m := NewMonitor(insp, []string{"default"}, 0, loggerMock{},
+		WithMaxLatency(time.Minute),
+		WithMaxRetry(10),
+	)
+
+	m.Collect()
+	require.NoError(t, m.Check())
+	require.Equal(t, float64(3), queueTasks.WithLabelValues("default", "pending").Value())
+	require.Equal(t, float64(1), queueLatency.WithLabelValues("default").Value())
+
+	// stuck queue
+	insp["default"] = &asynq.QueueInfo{Queue: "default", Pending: 100, Latency: 5 * time.Minute}
+	m.Collect()
+	require.EqualError(t, m.Check(), "unhealthy queues: default: oldest pending task waits for 5m0s")
+
+	// too many retries
+	insp["default"] = &asynq.QueueInfo{Queue: "default", Retry: 11}
+	m.Collect()
+	require.EqualError(t, m.Check(), "unhealthy queues: default: 11 tasks wait for retry")
+
+	// recovered
+	insp["default"] = &asynq.QueueInfo{Queue: "default"}
+	m.Collect()
+	require.NoError(t, m.Check())
+
+	// stats are unavailable
+	m = NewMonitor(insp, []string{"webhooks"}, 0, loggerMock{})
+	m.Collect()
+	require.EqualError(t, m.Check(), "unhealthy queues: webhooks: stats are unavailable")
+}
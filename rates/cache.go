@@ -0,0 +1,57 @@
+package rates
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Cache keeps the prices of the provider for the TTL,
+	// so the checkout doesn't hit the provider rate limits.
+	Cache struct {
+		provider Provider
+		ttl      time.Duration
+
+		mu     sync.RWMutex
+		prices map[string]cachedPrice
+	}
+
+	cachedPrice struct {
+		price     float64
+		expiresAt time.Time
+	}
+)
+
+// NewCache creates a new cached rate store of the provider.
+func NewCache(provider Provider, ttl time.Duration) *Cache {
+	return &Cache{
+		provider: provider,
+		ttl:      ttl,
+		prices:   make(map[string]cachedPrice),
+	}
+}
+
+// Price returns the cached price of the pair, or gets it from the provider once the cached one expires.
+func (c *Cache) Price(ctx context.Context, mint, fiat string) (float64, error) {
+	key := mint + "/" + strings.ToUpper(fiat)
+
+	c.mu.RLock()
+	cached, ok := c.prices[key]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.price, nil
+	}
+
+	price, err := c.provider.Price(ctx, mint, fiat)
+	if err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.prices[key] = cachedPrice{price: price, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return price, nil
+}
@@ -0,0 +1,114 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/internal/httpclient"
+)
+
+// CoinGecko public API base URL.
+const coinGeckoAPIURL = "https://api.coingecko.com/api/v3"
+
+type (
+	// CoinGecko gets the token prices from the CoinGecko simple token price API.
+	CoinGecko struct {
+		client *http.Client
+		apiURL string
+		apiKey string
+	}
+
+	// CoinGeckoOption is a function that configures the CoinGecko provider.
+	CoinGeckoOption func(*CoinGecko)
+)
+
+// NewCoinGecko creates a new CoinGecko rates provider.
+func NewCoinGecko(opts ...CoinGeckoOption) *CoinGecko {
+	c := &CoinGecko{
+		client: httpclient.New("coingecko",
+			httpclient.WithRetries(2, httpclient.DefaultRetryWaitMin, httpclient.DefaultRetryWaitMax),
+		),
+		apiURL: coinGeckoAPIURL,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithCoinGeckoAPIURL sets a custom CoinGecko API base URL, e.g. https://pro-api.coingecko.com/api/v3.
+func WithCoinGeckoAPIURL(apiURL string) CoinGeckoOption {
+	return func(c *CoinGecko) {
+		c.apiURL = strings.TrimRight(apiURL, "/")
+	}
+}
+
+// WithCoinGeckoAPIKey sets the CoinGecko Pro API key.
+func WithCoinGeckoAPIKey(key string) CoinGeckoOption {
+	return func(c *CoinGecko) {
+		c.apiKey = key
+	}
+}
+
+// WithCoinGeckoHTTPClient sets a custom HTTP client.
+func WithCoinGeckoHTTPClient(client *http.Client) CoinGeckoOption {
+	return func(c *CoinGecko) {
+		c.client = client
+	}
+}
+
+// Price returns the price of the whole token of the Solana mint in the fiat currency.
+func (c *CoinGecko) Price(ctx context.Context, mint, fiat string) (float64, error) {
+	vs := strings.ToLower(fiat)
+	q := url.Values{}
+	q.Set("contract_addresses", mint)
+	q.Set("vs_currencies", vs)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/simple/token_price/solana?"+q.Encode(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.apiKey != "" {
+		req.Header.Set("x-cg-pro-api-key", c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: coingecko: %s", ErrRateUnavailable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("%w: coingecko: unexpected status code: %d: %s", ErrRateUnavailable, resp.StatusCode, string(msg))
+	}
+
+	// {"<mint>": {"usd": 20.5}}, the token is missing if CoinGecko doesn't track it
+	var result map[string]map[string]float64
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("%w: coingecko: failed to decode response: %s", ErrRateUnavailable, err.Error())
+	}
+	for addr, prices := range result {
+		if !strings.EqualFold(addr, mint) {
+			continue
+		}
+		price, ok := prices[vs]
+		if !ok {
+			break
+		}
+		if err := validPrice(price); err != nil {
+			return 0, err
+		}
+		return price, nil
+	}
+
+	return 0, fmt.Errorf("%w: coingecko: %s/%s", ErrUnsupportedPair, mint, strings.ToUpper(fiat))
+}
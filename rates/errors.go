@@ -0,0 +1,9 @@
+package rates
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrUnsupportedPair = errors.New("unsupported_currency_pair")
+	ErrRateUnavailable = errors.New("exchange_rate_unavailable")
+)
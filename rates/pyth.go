@@ -0,0 +1,151 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpclient"
+)
+
+// Pyth Hermes public API base URL.
+const pythHermesURL = "https://hermes.pyth.network"
+
+// DefaultPythMaxPriceAge is the default age of the Pyth price after which it's considered stale.
+const DefaultPythMaxPriceAge = time.Minute
+
+type (
+	// Pyth gets the token prices from the Pyth price feeds through the Hermes API.
+	// Pyth publishes a price feed per currency pair, so the feed IDs of the pairs must be configured.
+	Pyth struct {
+		client      *http.Client
+		apiURL      string
+		feeds       map[string]string // mint/FIAT => price feed ID
+		maxPriceAge time.Duration
+	}
+
+	// PythOption is a function that configures the Pyth provider.
+	PythOption func(*Pyth)
+
+	pythResponse struct {
+		Parsed []struct {
+			ID    string `json:"id"`
+			Price struct {
+				Price       string `json:"price"`
+				Expo        int    `json:"expo"`
+				PublishTime int64  `json:"publish_time"`
+			} `json:"price"`
+		} `json:"parsed"`
+	}
+)
+
+// NewPyth creates a new Pyth rates provider with the price feed IDs of the pairs,
+// e.g. {"So11111111111111111111111111111111111111112/USD": "0xef0d8b6f..."}.
+func NewPyth(feeds map[string]string, opts ...PythOption) *Pyth {
+	p := &Pyth{
+		client: httpclient.New("pyth",
+			httpclient.WithRetries(2, httpclient.DefaultRetryWaitMin, httpclient.DefaultRetryWaitMax),
+		),
+		apiURL:      pythHermesURL,
+		feeds:       make(map[string]string, len(feeds)),
+		maxPriceAge: DefaultPythMaxPriceAge,
+	}
+	for pair, id := range feeds {
+		p.feeds[pairKey(pair)] = strings.TrimPrefix(strings.ToLower(id), "0x")
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// WithPythAPIURL sets a custom Hermes API base URL, e.g. of a private Hermes node.
+func WithPythAPIURL(apiURL string) PythOption {
+	return func(p *Pyth) {
+		p.apiURL = strings.TrimRight(apiURL, "/")
+	}
+}
+
+// WithPythMaxPriceAge sets the age of the price after which it's considered stale, DefaultPythMaxPriceAge by default.
+func WithPythMaxPriceAge(age time.Duration) PythOption {
+	return func(p *Pyth) {
+		p.maxPriceAge = age
+	}
+}
+
+// WithPythHTTPClient sets a custom HTTP client.
+func WithPythHTTPClient(client *http.Client) PythOption {
+	return func(p *Pyth) {
+		p.client = client
+	}
+}
+
+// Price returns the latest price of the pair's feed.
+// The stale prices are rejected, e.g. if the market is closed.
+func (p *Pyth) Price(ctx context.Context, mint, fiat string) (float64, error) {
+	id, ok := p.feeds[pairKey(mint+"/"+fiat)]
+	if !ok {
+		return 0, fmt.Errorf("%w: pyth: %s/%s", ErrUnsupportedPair, mint, strings.ToUpper(fiat))
+	}
+
+	q := url.Values{}
+	q.Set("ids[]", id)
+	q.Set("parsed", "true")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+"/v2/updates/price/latest?"+q.Encode(), nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("%w: pyth: %s", ErrRateUnavailable, err.Error())
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("%w: pyth: unexpected status code: %d: %s", ErrRateUnavailable, resp.StatusCode, string(msg))
+	}
+
+	var result pythResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("%w: pyth: failed to decode response: %s", ErrRateUnavailable, err.Error())
+	}
+	for _, feed := range result.Parsed {
+		if strings.TrimPrefix(strings.ToLower(feed.ID), "0x") != id {
+			continue
+		}
+		if p.maxPriceAge > 0 && time.Since(time.Unix(feed.Price.PublishTime, 0)) > p.maxPriceAge {
+			return 0, fmt.Errorf("%w: pyth: stale price of feed %s", ErrRateUnavailable, id)
+		}
+		// the price is a fixed-point number: price * 10^expo
+		mantissa, err := strconv.ParseInt(feed.Price.Price, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: pyth: invalid price %q", ErrRateUnavailable, feed.Price.Price)
+		}
+		price := float64(mantissa) * math.Pow10(feed.Price.Expo)
+		if err := validPrice(price); err != nil {
+			return 0, err
+		}
+		return price, nil
+	}
+
+	return 0, fmt.Errorf("%w: pyth: no price of feed %s", ErrRateUnavailable, id)
+}
+
+// pairKey returns the lookup key of the mint/FIAT pair, the fiat currency is case-insensitive.
+func pairKey(pair string) string {
+	mint, fiat, _ := strings.Cut(pair, "/")
+	return mint + "/" + strings.ToUpper(fiat)
+}
@@ -0,0 +1,106 @@
+package rates_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/rates"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	solMint    = "So11111111111111111111111111111111111111112"
+	solUSDFeed = "ef0d8b6fda2ceba41da15d4095d1da392a0d2f8ed0c6c7bc0f4cfac8c280b56d"
+)
+
+type providerFunc func(ctx context.Context, mint, fiat string) (float64, error)
+
+func (f providerFunc) Price(ctx context.Context, mint, fiat string) (float64, error) {
+	return f(ctx, mint, fiat)
+}
+
+func TestCoinGecko(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/simple/token_price/solana", r.URL.Path)
+		require.Equal(t, "test-key", r.Header.Get("x-cg-pro-api-key"))
+		if r.URL.Query().Get("contract_addresses") != solMint {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		fmt.Fprintf(w, `{"%s": {"%s": 20.5}}`, solMint, r.URL.Query().Get("vs_currencies"))
+	}))
+	defer srv.Close()
+
+	p := rates.NewCoinGecko(rates.WithCoinGeckoAPIURL(srv.URL), rates.WithCoinGeckoAPIKey("test-key"))
+
+	price, err := p.Price(context.Background(), solMint, "USD")
+	require.NoError(t, err)
+	require.Equal(t, 20.5, price)
+
+	_, err = p.Price(context.Background(), "unknown", "USD")
+	require.ErrorIs(t, err, rates.ErrUnsupportedPair)
+}
+
+func TestPyth(t *testing.T) {
+	publishTime := time.Now().Unix()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v2/updates/price/latest", r.URL.Path)
+		require.Equal(t, solUSDFeed, r.URL.Query().Get("ids[]"))
+		fmt.Fprintf(w, `{"parsed": [{"id": "%s", "price": {"price": "2050000000", "conf": "100000", "expo": -8, "publish_time": %d}}]}`,
+			solUSDFeed, publishTime)
+	}))
+	defer srv.Close()
+
+	p := rates.NewPyth(map[string]string{solMint + "/usd": "0x" + solUSDFeed}, rates.WithPythAPIURL(srv.URL))
+
+	price, err := p.Price(context.Background(), solMint, "USD")
+	require.NoError(t, err)
+	require.InDelta(t, 20.5, price, 1e-9)
+
+	_, err = p.Price(context.Background(), solMint, "EUR")
+	require.ErrorIs(t, err, rates.ErrUnsupportedPair)
+
+	// the price published before the max age is stale
+	publishTime = time.Now().Add(-2 * rates.DefaultPythMaxPriceAge).Unix()
+	_, err = p.Price(context.Background(), solMint, "USD")
+	require.ErrorIs(t, err, rates.ErrRateUnavailable)
+}
+
+func TestCache(t *testing.T) {
+	calls := 0
+	c := rates.NewCache(providerFunc(func(ctx context.Context, mint, fiat string) (float64, error) {
+		calls++
+		return float64(calls), nil
+	}), time.Hour)
+
+	for i := 0; i < 3; i++ {
+		price, err := c.Price(context.Background(), solMint, "usd")
+		require.NoError(t, err)
+		require.Equal(t, 1.0, price)
+	}
+	require.Equal(t, 1, calls)
+
+	price, err := c.Price(context.Background(), solMint, "EUR")
+	require.NoError(t, err)
+	require.Equal(t, 2.0, price)
+}
+
+func TestFallback(t *testing.T) {
+	unsupported := providerFunc(func(ctx context.Context, mint, fiat string) (float64, error) {
+		return 0, rates.ErrUnsupportedPair
+	})
+	p := rates.Fallback(unsupported, providerFunc(func(ctx context.Context, mint, fiat string) (float64, error) {
+		return 1.5, nil
+	}))
+
+	price, err := p.Price(context.Background(), solMint, "USD")
+	require.NoError(t, err)
+	require.Equal(t, 1.5, price)
+
+	_, err = rates.Fallback(unsupported, unsupported).Price(context.Background(), solMint, "USD")
+	require.ErrorIs(t, err, rates.ErrRateUnavailable)
+}
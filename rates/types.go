@@ -0,0 +1,55 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Provider is the interface that wraps the Price method.
+// Implement it to get the exchange rates from any source.
+type Provider interface {
+	// Price returns the price of the whole token of the mint in the fiat currency,
+	// e.g. 20.5 for 1 SOL in USD.
+	Price(ctx context.Context, mint, fiat string) (float64, error)
+}
+
+// fallback asks the providers in order, see Fallback.
+type fallback []Provider
+
+// Fallback returns the provider asking the given providers in order
+// until one of them returns the price, e.g. Pyth with CoinGecko for the pairs without a price feed.
+func Fallback(providers ...Provider) Provider {
+	if len(providers) == 1 {
+		return providers[0]
+	}
+	return fallback(providers)
+}
+
+// Price returns the price of the first provider which has it.
+func (f fallback) Price(ctx context.Context, mint, fiat string) (float64, error) {
+	var errs []string
+	for _, p := range f {
+		price, err := p.Price(ctx, mint, fiat)
+		if err == nil {
+			return price, nil
+		}
+		if ctx.Err() != nil {
+			return 0, err
+		}
+		errs = append(errs, err.Error())
+	}
+	if len(errs) == 0 {
+		return 0, ErrRateUnavailable
+	}
+	return 0, fmt.Errorf("%w: %s", ErrRateUnavailable, strings.Join(errs, "; "))
+}
+
+// validPrice checks that the price returned by the provider can be used for the conversion.
+func validPrice(price float64) error {
+	if math.IsNaN(price) || math.IsInf(price, 0) || price <= 0 {
+		return fmt.Errorf("%w: invalid price %v", ErrRateUnavailable, price)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package receipts
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/events"
+)
+
+type receiptEnqueuer interface {
+	MintReceipt(ctx context.Context, paymentID string) error
+}
+
+// PaymentSucceededListener enqueues minting of the NFT receipt for the payment.succeeded events.
+func PaymentSucceededListener(enq receiptEnqueuer) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		if payload == nil || event != events.PaymentSucceeded {
+			return nil
+		}
+
+		p, ok := payload.(events.PaymentIDGetter)
+		if !ok {
+			return nil
+		}
+
+		return enq.MintReceipt(context.Background(), p.GetPaymentID())
+	}
+}
@@ -0,0 +1,311 @@
+package receipts
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/hdkey"
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/easypmnt/checkout-api/solana/metadata"
+	"github.com/google/uuid"
+	"github.com/portto/solana-go-sdk/common"
+	"github.com/portto/solana-go-sdk/types"
+)
+
+// Known token symbols to format the payment amount.
+var knownMints = map[string]struct {
+	symbol   string
+	decimals uint8
+}{
+	payments.SOL:  {"SOL", 9},
+	payments.USDC: {"USDC", 6},
+	payments.USDT: {"USDT", 6},
+}
+
+// confirmationTimeout is the max time to wait for the mint transaction to be confirmed.
+const confirmationTimeout = time.Minute
+
+type (
+	// Service mints the Metaplex NFT receipts of the completed payments to the wallets which paid them.
+	// The receipt metadata is uploaded to Arweave, the name and the description are rendered
+	// from the templates with the order id, the amount and the merchant name.
+	//
+	// The mint account of the receipt is derived from the seed and the payment ID,
+	// so the receipt of the payment is minted only once, however many times the task is retried.
+	Service struct {
+		uploader     uploader
+		sol          solanaClient
+		authority    solana.Signer
+		mints        *hdkey.Key
+		commitment   solana.Commitment
+		merchantName string
+		symbol       string
+		imageURL     string
+		externalURL  string
+
+		nameTemplate        string
+		descriptionTemplate string
+		name                *template.Template
+		description         *template.Template
+	}
+
+	// uploader uploads the receipt metadata, e.g. arweave.Client.
+	uploader interface {
+		Upload(data []byte, contentType, ext string) (string, error)
+	}
+
+	solanaClient interface {
+		solana.SolanaClient
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+		GetTransactionStatus(ctx context.Context, txhash string, commitment solana.Commitment) (solana.TransactionStatus, error)
+		WaitForTransactionConfirmed(ctx context.Context, txhash string, commitment solana.Commitment, maxDuration time.Duration) (solana.TransactionStatus, error)
+		GetMintDecimals(ctx context.Context, base58MintAddr string) (uint8, error)
+	}
+
+	// ServiceOption is a function that configures the receipts service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new receipts service.
+// The authority is the mint and update authority of the receipts and pays the minting fees.
+// The seed of at least 16 bytes derives the mint accounts of the receipts, it must not change.
+func NewService(up uploader, sol solanaClient, authority solana.Signer, seed []byte, opts ...ServiceOption) (*Service, error) {
+	if up == nil {
+		panic("uploader is nil")
+	}
+	if sol == nil {
+		panic("solana client is nil")
+	}
+	if authority == nil {
+		panic("authority is nil")
+	}
+
+	master, err := hdkey.NewMaster(seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive master key: %w", err)
+	}
+
+	s := &Service{
+		uploader:     up,
+		sol:          sol,
+		authority:    authority,
+		mints:        master,
+		commitment:   solana.CommitmentConfirmed,
+		merchantName: "Checkout API",
+		symbol:       "RCPT",
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.name, err = parseTemplate("name", s.nameTemplate, DefaultNameTemplate); err != nil {
+		return nil, err
+	}
+	if s.description, err = parseTemplate("description", s.descriptionTemplate, DefaultDescriptionTemplate); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// WithMerchantName sets the merchant name of the receipt templates.
+func WithMerchantName(name string) ServiceOption {
+	return func(s *Service) {
+		s.merchantName = name
+	}
+}
+
+// WithSymbol sets the token symbol of the receipts, up to 10 characters.
+func WithSymbol(symbol string) ServiceOption {
+	return func(s *Service) {
+		s.symbol = symbol
+	}
+}
+
+// WithImageURL sets the image of the receipts.
+func WithImageURL(url string) ServiceOption {
+	return func(s *Service) {
+		s.imageURL = url
+	}
+}
+
+// WithExternalURL sets the merchant website linked from the receipts.
+func WithExternalURL(url string) ServiceOption {
+	return func(s *Service) {
+		s.externalURL = url
+	}
+}
+
+// WithTemplates sets the text/template templates of the receipt name and description, see TemplateData.
+// The default template is used if the text is empty. The rendered name is cut to 32 bytes.
+func WithTemplates(name, description string) ServiceOption {
+	return func(s *Service) {
+		s.nameTemplate = name
+		s.descriptionTemplate = description
+	}
+}
+
+// WithCommitment sets the commitment to wait for the mint transaction.
+func WithCommitment(commitment solana.Commitment) ServiceOption {
+	return func(s *Service) {
+		s.commitment = commitment
+	}
+}
+
+// MintReceipt mints the NFT receipt of the completed payment to the wallet which paid it.
+// The receipt minted before is returned as is. Returns nil if the payment isn't settled,
+// is a test-mode payment or its payer is unknown, e.g. the payment was sent by an exchange to the deposit address.
+func (s *Service) MintReceipt(ctx context.Context, payment *payments.Payment, attempts []*payments.PaymentAttempt) (*Receipt, error) {
+	if !payment.Settled() || !payment.Livemode {
+		return nil, nil
+	}
+
+	owner := payerWallet(attempts)
+	if owner == "" {
+		return nil, nil
+	}
+
+	mint, err := s.mintAccount(payment.ID)
+	if err != nil {
+		return nil, err
+	}
+	receipt := &Receipt{
+		PaymentID: payment.ID,
+		Mint:      mint.PublicKey.ToBase58(),
+		Owner:     owner,
+	}
+
+	ata, _, err := common.FindAssociatedTokenAddress(common.PublicKeyFromString(owner), mint.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find associated token address: %w", err)
+	}
+	if exists, err := s.sol.DoesTokenAccountExist(ctx, ata.ToBase58()); err == nil && exists {
+		return receipt, nil
+	}
+
+	data := s.templateData(ctx, payment)
+	name, err := render(s.name, data)
+	if err != nil {
+		return nil, err
+	}
+	description, err := render(s.description, data)
+	if err != nil {
+		return nil, err
+	}
+	name = truncate(name, maxNameLength)
+
+	md, err := metadata.NewNFTMetadataBuilder().
+		SetName(name).
+		SetSymbol(s.symbol).
+		SetDescription(description).
+		SetImage(s.imageURL).
+		SetExternalURL(s.externalURL).
+		SetAttribute("Order ID", data.OrderID).
+		SetAttribute("Amount", data.Amount).
+		SetAttribute("Currency", data.Currency).
+		SetAttribute("Merchant", data.MerchantName).
+		SetCategory(metadata.PropertyCategoryImage).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build receipt metadata: %w", err)
+	}
+	mdb, err := md.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal receipt metadata: %w", err)
+	}
+	if receipt.MetadataURI, err = s.uploader.Upload(mdb, "application/json", ".json"); err != nil {
+		return nil, fmt.Errorf("failed to upload receipt metadata: %w", err)
+	}
+
+	authority := s.authority.PublicKey().ToBase58()
+	builder := solana.NewTransactionBuilder(s.sol).
+		SetFeePayer(authority).
+		AddInstruction(solana.CreateNonFungibleToken(solana.CreateNonFungibleTokenParam{
+			Mint:        receipt.Mint,
+			Owner:       authority,
+			FeePayer:    authority,
+			Recipient:   owner,
+			Name:        name,
+			Symbol:      s.symbol,
+			MetadataURI: receipt.MetadataURI,
+		})).
+		AddSigner(mint).
+		AddExternalSigner(s.authority)
+
+	if receipt.Signature, err = solana.NewSender(s.sol).Send(ctx, builder.Build); err != nil {
+		return nil, fmt.Errorf("failed to send receipt mint transaction: %w", err)
+	}
+
+	status, err := s.sol.WaitForTransactionConfirmed(ctx, receipt.Signature, s.commitment, confirmationTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for receipt mint transaction %s: %w", receipt.Signature, err)
+	}
+	if status != solana.TransactionStatusSuccess {
+		return nil, fmt.Errorf("receipt mint transaction %s is %s", receipt.Signature, status)
+	}
+
+	return receipt, nil
+}
+
+// MintAddress returns the mint address of the payment receipt, whether it's minted or not.
+func (s *Service) MintAddress(paymentID uuid.UUID) (string, error) {
+	mint, err := s.mintAccount(paymentID)
+	if err != nil {
+		return "", err
+	}
+	return mint.PublicKey.ToBase58(), nil
+}
+
+// mintAccount derives the mint account of the payment receipt, the SLIP-0010 path m/<payment id>',
+// the uuid split into four 31-bit indices.
+func (s *Service) mintAccount(paymentID uuid.UUID) (types.Account, error) {
+	path := make([]uint32, 4)
+	for i := range path {
+		path[i] = binary.BigEndian.Uint32(paymentID[i*4:]) &^ hdkey.HardenedOffset
+	}
+
+	account, err := types.AccountFromSeed(s.mints.Derive(path...).PrivateKey().Seed())
+	if err != nil {
+		return types.Account{}, fmt.Errorf("failed to derive receipt mint account: %w", err)
+	}
+	return account, nil
+}
+
+// templateData returns the template data of the payment receipt.
+func (s *Service) templateData(ctx context.Context, payment *payments.Payment) TemplateData {
+	data := TemplateData{
+		OrderID:      payment.ExternalID,
+		PaymentID:    payment.ID.String(),
+		MerchantName: s.merchantName,
+	}
+	if data.OrderID == "" {
+		data.OrderID = data.PaymentID
+	}
+
+	mint := payments.MintAddress(payment.DestinationMint, payments.SOL)
+	if m, ok := knownMints[mint]; ok {
+		data.Amount, data.Currency = utils.AmountToString(payment.Amount, m.decimals), m.symbol
+	} else if decimals, err := s.sol.GetMintDecimals(ctx, mint); err == nil {
+		data.Amount, data.Currency = utils.AmountToString(payment.Amount, decimals), mint
+	} else {
+		data.Amount, data.Currency = fmt.Sprintf("%d", payment.Amount), mint
+	}
+
+	return data
+}
+
+// payerWallet returns the source wallet of the completed transaction of the payment.
+func payerWallet(attempts []*payments.PaymentAttempt) string {
+	for _, a := range attempts {
+		if a.Status == payments.TransactionStatusCompleted && a.SourceWallet != "" {
+			return a.SourceWallet
+		}
+	}
+	return ""
+}
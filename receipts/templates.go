@@ -0,0 +1,51 @@
+package receipts
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// Default templates of the receipt name and description.
+const (
+	DefaultNameTemplate        = "Receipt #{{.OrderID}}"
+	DefaultDescriptionTemplate = "Payment of {{.Amount}} {{.Currency}} to {{.MerchantName}}, order {{.OrderID}}."
+)
+
+// maxNameLength is the max length of the on-chain token name in bytes, set by the Metaplex metadata program.
+const maxNameLength = 32
+
+// parseTemplate parses the receipt template, the default one is used if the text is empty.
+func parseTemplate(name, text, fallback string) (*template.Template, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// render executes the template with the given data.
+func render(tmpl *template.Template, data TemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", tmpl.Name(), err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// truncate cuts the string to the given number of bytes, without splitting a multi-byte character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
@@ -0,0 +1,50 @@
+package receipts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplates(t *testing.T) {
+	data := TemplateData{
+		OrderID:      "order-42",
+		PaymentID:    "9b2d1c4e-4a7b-4f3e-8f1a-2c5d6e7f8a9b",
+		Amount:       "1.5",
+		Currency:     "USDC",
+		MerchantName: "Coffee Shop",
+	}
+
+	name, err := parseTemplate("name", "", DefaultNameTemplate)
+	require.NoError(t, err)
+	s, err := render(name, data)
+	require.NoError(t, err)
+	require.Equal(t, "Receipt #order-42", s)
+
+	description, err := parseTemplate("description", "", DefaultDescriptionTemplate)
+	require.NoError(t, err)
+	s, err = render(description, data)
+	require.NoError(t, err)
+	require.Equal(t, "Payment of 1.5 USDC to Coffee Shop, order order-42.", s)
+
+	custom, err := parseTemplate("name", "{{.MerchantName}} {{.Amount}} {{.Currency}}", DefaultNameTemplate)
+	require.NoError(t, err)
+	s, err = render(custom, data)
+	require.NoError(t, err)
+	require.Equal(t, "Coffee Shop 1.5 USDC", s)
+
+	_, err = parseTemplate("name", "{{.OrderID", DefaultNameTemplate)
+	require.Error(t, err)
+
+	unknown, err := parseTemplate("name", "{{.Unknown}}", DefaultNameTemplate)
+	require.NoError(t, err)
+	_, err = render(unknown, data)
+	require.Error(t, err)
+}
+
+func TestTruncate(t *testing.T) {
+	require.Equal(t, "short", truncate("short", maxNameLength))
+	require.Equal(t, "Receipt #9b2d1c4e-4a7b-4f3e-8f1a", truncate("Receipt #9b2d1c4e-4a7b-4f3e-8f1a-2c5d6e7f8a9b", maxNameLength))
+	// the multi-byte character is not split
+	require.Equal(t, "ab", truncate("abé", 3))
+}
@@ -0,0 +1,33 @@
+package receipts
+
+import "github.com/google/uuid"
+
+// Worker task types
+const (
+	TaskMintReceipt = "receipts:mint_receipt"
+)
+
+type (
+	// MintReceiptPayload is the payload for the receipts:mint_receipt task.
+	MintReceiptPayload struct {
+		PaymentID string `json:"payment_id"`
+	}
+
+	// Receipt is the NFT receipt of the payment.
+	Receipt struct {
+		PaymentID   uuid.UUID `json:"payment_id"`
+		Mint        string    `json:"mint"`
+		Owner       string    `json:"owner"`
+		MetadataURI string    `json:"metadata_uri,omitempty"` // empty if the receipt was minted before
+		Signature   string    `json:"signature,omitempty"`    // empty if the receipt was minted before
+	}
+
+	// TemplateData is the data passed to the receipt name and description templates.
+	TemplateData struct {
+		OrderID      string // the external ID of the payment, or the payment ID if it's not set
+		PaymentID    string
+		Amount       string // in the whole tokens, e.g. 1.5
+		Currency     string // the token symbol, or the mint address of the unknown tokens
+		MerchantName string
+	}
+)
@@ -0,0 +1,67 @@
+package receipts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+type (
+	// Worker is a task handler for the NFT receipts minting.
+	Worker struct {
+		svc        service
+		paymentSvc paymentService
+	}
+
+	service interface {
+		MintReceipt(ctx context.Context, payment *payments.Payment, attempts []*payments.PaymentAttempt) (*Receipt, error)
+	}
+
+	paymentService interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (*payments.Payment, error)
+		GetPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]*payments.PaymentAttempt, error)
+	}
+)
+
+// NewWorker creates a new receipts task handler.
+func NewWorker(svc service, paymentSvc paymentService) *Worker {
+	return &Worker{svc: svc, paymentSvc: paymentSvc}
+}
+
+// Register registers task handlers for the NFT receipts minting.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskMintReceipt, w.MintReceipt)
+}
+
+// MintReceipt mints the NFT receipt of the payment to the payer wallet.
+func (w *Worker) MintReceipt(ctx context.Context, t *asynq.Task) error {
+	var p MintReceiptPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	pid, err := uuid.Parse(p.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to parse payment id: %w", err)
+	}
+
+	payment, err := w.paymentSvc.GetPayment(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	attempts, err := w.paymentSvc.GetPaymentAttempts(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("failed to get payment attempts: %w", err)
+	}
+
+	if _, err := w.svc.MintReceipt(ctx, payment, attempts); err != nil {
+		return fmt.Errorf("failed to mint receipt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,205 @@
+package reports
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// Formats of the accounting export.
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// accountingColumns is the CSV header of the accounting export, in the AccountingRow order.
+var accountingColumns = []string{
+	"transaction_id", "payment_id", "external_id", "reference", "payer", "source_currency", "merchant", "currency",
+	"amount", "discount_amount", "voucher_amount", "total_amount", "tip_amount", "tip_destination",
+	"network_fee", "priority_fee", "slippage_fee", "fiat_currency", "exchange_rate",
+	"signature", "created_at", "settled_at",
+}
+
+// AccountingRow is the completed transaction of the accounting export.
+// The amounts and the fees are in the base units: the amounts of the currency mint, the fees in lamports.
+// The exchange rate is the price of the whole currency token in the fiat currency locked by the transaction,
+// both are empty if the payment is not fiat-priced.
+type AccountingRow struct {
+	TransactionID  uuid.UUID  `json:"transaction_id"`
+	PaymentID      uuid.UUID  `json:"payment_id"`
+	ExternalID     string     `json:"external_id,omitempty"`
+	Reference      string     `json:"reference"`
+	Payer          string     `json:"payer"`
+	SourceCurrency string     `json:"source_currency"`
+	Merchant       string     `json:"merchant"`
+	Currency       string     `json:"currency"`
+	Amount         uint64     `json:"amount"`
+	DiscountAmount uint64     `json:"discount_amount"`
+	VoucherAmount  uint64     `json:"voucher_amount"`
+	TotalAmount    uint64     `json:"total_amount"`
+	TipAmount      uint64     `json:"tip_amount"`
+	TipDestination string     `json:"tip_destination,omitempty"`
+	NetworkFee     uint64     `json:"network_fee"`
+	PriorityFee    uint64     `json:"priority_fee"`
+	SlippageFee    uint64     `json:"slippage_fee"`
+	FiatCurrency   string     `json:"fiat_currency,omitempty"`
+	ExchangeRate   string     `json:"exchange_rate,omitempty"`
+	Signature      string     `json:"signature"`
+	CreatedAt      time.Time  `json:"created_at"`
+	SettledAt      *time.Time `json:"settled_at,omitempty"`
+}
+
+// ExportAccounting streams the completed transactions within the period into w, in the CSV or JSON format,
+// for the bookkeeping. The JSON export is an array of AccountingRow.
+// The transactions are loaded and written page by page, so the export doesn't have to fit in memory.
+// Returns the number of the written rows.
+func (s *Service) ExportAccounting(ctx context.Context, w io.Writer, format string, from, to time.Time) (int64, error) {
+	bw := bufio.NewWriter(w)
+
+	var (
+		rows int64
+		err  error
+	)
+	switch format {
+	case ExportFormatCSV:
+		rows, err = s.exportAccountingCSV(ctx, bw, from, to)
+	case ExportFormatJSON:
+		rows, err = s.exportAccountingJSON(ctx, bw, from, to)
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidExportFormat, format)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := bw.Flush(); err != nil {
+		return 0, fmt.Errorf("failed to write accounting export: %w", err)
+	}
+
+	return rows, nil
+}
+
+// exportAccountingCSV writes the accounting export as CSV with the header row.
+func (s *Service) exportAccountingCSV(ctx context.Context, w io.Writer, from, to time.Time) (int64, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(accountingColumns); err != nil {
+		return 0, err
+	}
+
+	rows, err := s.eachSettledTransaction(ctx, from, to, func(r repository.GetSettlementExportRow) error {
+		return cw.Write(castToAccountingRow(r).record())
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return 0, fmt.Errorf("failed to write accounting export: %w", err)
+	}
+
+	return rows, nil
+}
+
+// exportAccountingJSON writes the accounting export as the JSON array, one row at a time.
+func (s *Service) exportAccountingJSON(ctx context.Context, w io.Writer, from, to time.Time) (int64, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return 0, err
+	}
+
+	var (
+		enc = json.NewEncoder(w)
+		sep bool
+	)
+	rows, err := s.eachSettledTransaction(ctx, from, to, func(r repository.GetSettlementExportRow) error {
+		if sep {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		sep = true
+		return enc.Encode(castToAccountingRow(r))
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := io.WriteString(w, "]\n"); err != nil {
+		return 0, err
+	}
+
+	return rows, nil
+}
+
+// record returns the CSV record of the row, in the accountingColumns order.
+func (r AccountingRow) record() []string {
+	settledAt := ""
+	if r.SettledAt != nil {
+		settledAt = r.SettledAt.UTC().Format(time.RFC3339)
+	}
+	return []string{
+		r.TransactionID.String(),
+		r.PaymentID.String(),
+		r.ExternalID,
+		r.Reference,
+		r.Payer,
+		r.SourceCurrency,
+		r.Merchant,
+		r.Currency,
+		strconv.FormatUint(r.Amount, 10),
+		strconv.FormatUint(r.DiscountAmount, 10),
+		strconv.FormatUint(r.VoucherAmount, 10),
+		strconv.FormatUint(r.TotalAmount, 10),
+		strconv.FormatUint(r.TipAmount, 10),
+		r.TipDestination,
+		strconv.FormatUint(r.NetworkFee, 10),
+		strconv.FormatUint(r.PriorityFee, 10),
+		strconv.FormatUint(r.SlippageFee, 10),
+		r.FiatCurrency,
+		r.ExchangeRate,
+		r.Signature,
+		r.CreatedAt.UTC().Format(time.RFC3339),
+		settledAt,
+	}
+}
+
+// castToAccountingRow converts the stored completed transaction.
+func castToAccountingRow(r repository.GetSettlementExportRow) AccountingRow {
+	result := AccountingRow{
+		TransactionID:  r.ID,
+		PaymentID:      r.PaymentID,
+		ExternalID:     r.ExternalID.String,
+		Reference:      r.Reference,
+		Payer:          r.SourceWallet,
+		SourceCurrency: r.SourceMint,
+		Merchant:       r.DestinationWallet,
+		Currency:       r.DestinationMint,
+		Amount:         uint64(r.Amount),
+		DiscountAmount: uint64(r.DiscountAmount),
+		VoucherAmount:  uint64(r.VoucherAmount),
+		TotalAmount:    uint64(r.TotalAmount),
+		TipAmount:      uint64(r.TipAmount),
+		TipDestination: r.TipDestination.String,
+		NetworkFee:     uint64(r.NetworkFee),
+		PriorityFee:    uint64(r.PriorityFee),
+		SlippageFee:    uint64(r.SlippageFee),
+		Signature:      r.TxSignature.String,
+		CreatedAt:      r.CreatedAt,
+	}
+	if r.ExchangeRate.Valid {
+		result.FiatCurrency = r.FiatCurrency.String
+		result.ExchangeRate = r.ExchangeRate.String
+	}
+	if r.UpdatedAt.Valid {
+		result.SettledAt = &r.UpdatedAt.Time
+	}
+	return result
+}
@@ -0,0 +1,184 @@
+package reports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+// Default report period, if it is not set in the request.
+const defaultReportPeriod = 30 * 24 * time.Hour
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		GetRevenue endpoint.Endpoint
+		GetBonus   endpoint.Endpoint
+
+		CreateJob   endpoint.Endpoint
+		GetJob      endpoint.Endpoint
+		DownloadJob endpoint.Endpoint
+	}
+
+	// PeriodRequest is the request type for the report methods.
+	PeriodRequest struct {
+		From time.Time
+		To   time.Time
+	}
+
+	// GetRevenueResponse is the response type for the GetRevenue method.
+	GetRevenueResponse struct {
+		From    time.Time    `json:"from"`
+		To      time.Time    `json:"to"`
+		Revenue []RevenueRow `json:"revenue"`
+	}
+
+	// GetBonusResponse is the response type for the GetBonus method.
+	GetBonusResponse struct {
+		From  time.Time  `json:"from"`
+		To    time.Time  `json:"to"`
+		Bonus []BonusRow `json:"bonus"`
+	}
+
+	// CreateJobRequest is the request type for the CreateJob method.
+	CreateJobRequest struct {
+		Kind string
+		PeriodRequest
+	}
+
+	// DownloadJobResponse is the response type for the DownloadJob method.
+	// The file is streamed to the client and closed by the transport.
+	DownloadJobResponse struct {
+		Job  *Job
+		File io.ReadCloser
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		GetRevenue: makeGetRevenueEndpoint(s),
+		GetBonus:   makeGetBonusEndpoint(s),
+
+		CreateJob:   makeCreateJobEndpoint(s),
+		GetJob:      makeGetJobEndpoint(s),
+		DownloadJob: makeDownloadJobEndpoint(s),
+	}
+}
+
+// makeGetRevenueEndpoint returns an endpoint function for the GetRevenue method.
+func makeGetRevenueEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, err := periodFrom(request)
+		if err != nil {
+			return nil, err
+		}
+
+		revenue, err := s.Revenue(ctx, req.From, req.To)
+		if err != nil {
+			return nil, err
+		}
+
+		return GetRevenueResponse{
+			From:    req.From,
+			To:      req.To,
+			Revenue: revenue,
+		}, nil
+	}
+}
+
+// makeGetBonusEndpoint returns an endpoint function for the GetBonus method.
+func makeGetBonusEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, err := periodFrom(request)
+		if err != nil {
+			return nil, err
+		}
+
+		bonus, err := s.Bonus(ctx, req.From, req.To)
+		if err != nil {
+			return nil, err
+		}
+
+		return GetBonusResponse{
+			From:  req.From,
+			To:    req.To,
+			Bonus: bonus,
+		}, nil
+	}
+}
+
+// makeCreateJobEndpoint returns an endpoint function for the CreateJob method.
+func makeCreateJobEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CreateJobRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		period, err := validPeriod(req.PeriodRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		return s.CreateJob(ctx, req.Kind, period.From, period.To)
+	}
+}
+
+// makeGetJobEndpoint returns an endpoint function for the GetJob method.
+func makeGetJobEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		return s.GetJob(ctx, id)
+	}
+}
+
+// makeDownloadJobEndpoint returns an endpoint function for the DownloadJob method.
+func makeDownloadJobEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		f, job, err := s.OpenJobFile(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return DownloadJobResponse{Job: job, File: f}, nil
+	}
+}
+
+// periodFrom validates the report period request and fills in the defaults.
+func periodFrom(request interface{}) (PeriodRequest, error) {
+	req, ok := request.(PeriodRequest)
+	if !ok {
+		return PeriodRequest{}, ErrInvalidRequest
+	}
+
+	return validPeriod(req)
+}
+
+// validPeriod validates the report period and fills in the defaults.
+func validPeriod(req PeriodRequest) (PeriodRequest, error) {
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+	if req.From.IsZero() {
+		req.From = req.To.Add(-defaultReportPeriod)
+	}
+	if !req.From.Before(req.To) {
+		return PeriodRequest{}, ErrInvalidPeriod
+	}
+
+	return req, nil
+}
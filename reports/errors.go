@@ -0,0 +1,21 @@
+package reports
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrInvalidPeriod  = errors.New("invalid report period")
+
+	ErrInvalidExportFormat = errors.New("invalid export format")
+)
+
+// Report job errors.
+var (
+	ErrReportJobsNotSupported = errors.New("report jobs are not configured")
+	ErrInvalidReportKind      = errors.New("invalid report kind")
+	ErrReportJobNotFound      = errors.New("report job not found")
+	ErrReportJobFinished      = errors.New("report job already finished")
+	ErrReportNotReady         = errors.New("report is not ready yet")
+	ErrReportFileNotFound     = errors.New("report file not found")
+)
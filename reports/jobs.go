@@ -0,0 +1,325 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// CreateJob creates the job exporting the report of the given kind within the given period
+// and enqueues its generation, so the large exports don't run inside the HTTP request.
+// The job status is polled with GetJob, the file is downloaded with OpenJobFile once the job is completed.
+func (s *Service) CreateJob(ctx context.Context, kind string, from, to time.Time) (*Job, error) {
+	if s.storage == nil || s.enqueuer == nil {
+		return nil, ErrReportJobsNotSupported
+	}
+	if kind != ReportKindPayments && kind != ReportKindSettlement {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidReportKind, kind)
+	}
+
+	job, err := s.repo.CreateReportJob(ctx, repository.CreateReportJobParams{
+		Kind:     kind,
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create report job: %w", err)
+	}
+
+	if err := s.enqueuer.GenerateReport(ctx, job.ID); err != nil {
+		// the job would stay pending forever otherwise
+		if _, ferr := s.FailJob(ctx, job.ID, err); ferr != nil {
+			return nil, fmt.Errorf("failed to enqueue report job: %w; %s", err, ferr.Error())
+		}
+		return nil, fmt.Errorf("failed to enqueue report job: %w", err)
+	}
+
+	return castFromRepositoryReportJob(job), nil
+}
+
+// GetJob returns the report job by its ID.
+func (s *Service) GetJob(ctx context.Context, id uuid.UUID) (*Job, error) {
+	job, err := s.repo.GetReportJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReportJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get report job: %w", err)
+	}
+
+	return castFromRepositoryReportJob(job), nil
+}
+
+// OpenJobFile opens the file of the completed report job.
+// The file must be closed by the caller.
+func (s *Service) OpenJobFile(ctx context.Context, id uuid.UUID) (io.ReadCloser, *Job, error) {
+	if s.storage == nil {
+		return nil, nil, ErrReportJobsNotSupported
+	}
+
+	job, err := s.repo.GetReportJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, ErrReportJobNotFound
+		}
+		return nil, nil, fmt.Errorf("failed to get report job: %w", err)
+	}
+	if job.Status != JobStatusCompleted || !job.FileKey.Valid {
+		return nil, nil, ErrReportNotReady
+	}
+
+	f, err := s.storage.Open(ctx, job.FileKey.String)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open report file: %w", err)
+	}
+
+	return f, castFromRepositoryReportJob(job), nil
+}
+
+// GenerateReport exports the report of the job into the CSV file and stores it in the report storage.
+// The retried generation starts over, the file of the previous attempt is overwritten.
+// Returns ErrReportJobFinished if the job is already completed or failed, e.g. the task is duplicated.
+func (s *Service) GenerateReport(ctx context.Context, id uuid.UUID) (*Job, error) {
+	if s.storage == nil {
+		return nil, ErrReportJobsNotSupported
+	}
+
+	started, err := s.repo.StartReportJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start report job: %w", err)
+	}
+	if started == 0 {
+		return nil, ErrReportJobFinished
+	}
+
+	job, err := s.repo.GetReportJob(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report job: %w", err)
+	}
+
+	// the report is written to the temporary file first, so the exports don't have to fit in memory
+	tmp, err := os.CreateTemp("", "report-*.csv")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary report file: %w", err)
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	rows, err := s.export(ctx, job, tmp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export %s report: %w", job.Kind, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind temporary report file: %w", err)
+	}
+
+	key := fmt.Sprintf("reports/%s.csv", job.ID)
+	if err := s.storage.Put(ctx, key, tmp); err != nil {
+		return nil, fmt.Errorf("failed to store report file: %w", err)
+	}
+
+	if _, err := s.repo.CompleteReportJob(ctx, repository.CompleteReportJobParams{
+		FileKey:  sql.NullString{String: key, Valid: true},
+		RowCount: rows,
+		ID:       job.ID,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to complete report job: %w", err)
+	}
+
+	return s.GetJob(ctx, job.ID)
+}
+
+// FailJob marks the report job as failed with the given cause.
+func (s *Service) FailJob(ctx context.Context, id uuid.UUID, cause error) (*Job, error) {
+	if _, err := s.repo.FailReportJob(ctx, repository.FailReportJobParams{
+		Error: sql.NullString{String: cause.Error(), Valid: true},
+		ID:    id,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to mark report job as failed: %w", err)
+	}
+
+	return s.GetJob(ctx, id)
+}
+
+// export writes the CSV report of the job and returns the number of the written rows.
+func (s *Service) export(ctx context.Context, job repository.ReportJob, w io.Writer) (int64, error) {
+	cw := csv.NewWriter(w)
+
+	var (
+		rows int64
+		err  error
+	)
+	switch job.Kind {
+	case ReportKindPayments:
+		rows, err = s.exportPaymentHistory(ctx, cw, job.FromDate, job.ToDate)
+	case ReportKindSettlement:
+		rows, err = s.exportSettlement(ctx, cw, job.FromDate, job.ToDate)
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidReportKind, job.Kind)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return 0, fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	return rows, nil
+}
+
+// exportPaymentHistory writes the payments created within the period, one row per transaction of the payment.
+// The payments without transactions are written with the empty transaction columns.
+func (s *Service) exportPaymentHistory(ctx context.Context, w *csv.Writer, from, to time.Time) (int64, error) {
+	if err := w.Write([]string{
+		"payment_id", "external_id", "payment_status", "merchant", "currency", "payment_amount", "payment_created_at",
+		"transaction_id", "reference", "transaction_status", "payer", "total_amount", "signature",
+	}); err != nil {
+		return 0, err
+	}
+
+	var rows int64
+	for offset := int32(0); ; offset += s.pageSize {
+		page, err := s.repo.GetPaymentHistoryExport(ctx, repository.GetPaymentHistoryExportParams{
+			FromDate: from,
+			ToDate:   to,
+			Limit:    s.pageSize,
+			Offset:   offset,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get payment history: %w", err)
+		}
+
+		for _, r := range page {
+			record := []string{
+				r.PaymentID.String(),
+				r.ExternalID.String,
+				string(r.PaymentStatus),
+				r.DestinationWallet,
+				r.DestinationMint,
+				strconv.FormatInt(r.PaymentAmount, 10),
+				r.PaymentCreatedAt.UTC().Format(time.RFC3339),
+				"", "", "", "", "", "",
+			}
+			if r.TransactionID.Valid {
+				record[7] = r.TransactionID.UUID.String()
+				record[8] = r.Reference.String
+				record[9] = string(r.TransactionStatus.TransactionStatus)
+				record[10] = r.SourceWallet.String
+				record[11] = strconv.FormatInt(r.TotalAmount.Int64, 10)
+				record[12] = r.TxSignature.String
+			}
+			if err := w.Write(record); err != nil {
+				return 0, err
+			}
+		}
+		rows += int64(len(page))
+
+		if len(page) < int(s.pageSize) {
+			return rows, nil
+		}
+	}
+}
+
+// exportSettlement writes the completed transactions within the period with the amounts, the tips,
+// the fees and the signatures, so the report can be reconciled with the on-chain transfers.
+func (s *Service) exportSettlement(ctx context.Context, w *csv.Writer, from, to time.Time) (int64, error) {
+	if err := w.Write([]string{
+		"transaction_id", "payment_id", "external_id", "reference", "payer", "source_currency", "merchant", "currency",
+		"amount", "discount_amount", "voucher_amount", "total_amount", "network_fee", "priority_fee", "slippage_fee",
+		"tip_amount", "tip_destination", "signature", "created_at", "settled_at",
+	}); err != nil {
+		return 0, err
+	}
+
+	return s.eachSettledTransaction(ctx, from, to, func(r repository.GetSettlementExportRow) error {
+		settledAt := ""
+		if r.UpdatedAt.Valid {
+			settledAt = r.UpdatedAt.Time.UTC().Format(time.RFC3339)
+		}
+		return w.Write([]string{
+			r.ID.String(),
+			r.PaymentID.String(),
+			r.ExternalID.String,
+			r.Reference,
+			r.SourceWallet,
+			r.SourceMint,
+			r.DestinationWallet,
+			r.DestinationMint,
+			strconv.FormatInt(r.Amount, 10),
+			strconv.FormatInt(r.DiscountAmount, 10),
+			strconv.FormatInt(r.VoucherAmount, 10),
+			strconv.FormatInt(r.TotalAmount, 10),
+			strconv.FormatInt(r.NetworkFee, 10),
+			strconv.FormatInt(r.PriorityFee, 10),
+			strconv.FormatInt(r.SlippageFee, 10),
+			strconv.FormatInt(r.TipAmount, 10),
+			r.TipDestination.String,
+			r.TxSignature.String,
+			r.CreatedAt.UTC().Format(time.RFC3339),
+			settledAt,
+		})
+	})
+}
+
+// eachSettledTransaction calls fn for every completed transaction within the period, in the creation order.
+// The transactions are loaded page by page, so the exports don't have to fit in memory.
+// Returns the number of the transactions.
+func (s *Service) eachSettledTransaction(ctx context.Context, from, to time.Time, fn func(repository.GetSettlementExportRow) error) (int64, error) {
+	var rows int64
+	for offset := int32(0); ; offset += s.pageSize {
+		page, err := s.repo.GetSettlementExport(ctx, repository.GetSettlementExportParams{
+			FromDate: from,
+			ToDate:   to,
+			Limit:    s.pageSize,
+			Offset:   offset,
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to get settled transactions: %w", err)
+		}
+
+		for _, r := range page {
+			if err := fn(r); err != nil {
+				return 0, err
+			}
+		}
+		rows += int64(len(page))
+
+		if len(page) < int(s.pageSize) {
+			return rows, nil
+		}
+	}
+}
+
+// castFromRepositoryReportJob converts the stored report job.
+func castFromRepositoryReportJob(j repository.ReportJob) *Job {
+	result := &Job{
+		ID:        j.ID,
+		Kind:      j.Kind,
+		Status:    j.Status,
+		From:      j.FromDate,
+		To:        j.ToDate,
+		Rows:      j.RowCount,
+		Error:     j.Error.String,
+		CreatedAt: j.CreatedAt,
+	}
+	if j.StartedAt.Valid {
+		result.StartedAt = &j.StartedAt.Time
+	}
+	if j.CompletedAt.Valid {
+		result.CompletedAt = &j.CompletedAt.Time
+	}
+	return result
+}
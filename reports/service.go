@@ -0,0 +1,112 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+type (
+	// Service builds the revenue reports.
+	// The aggregates are computed by the database, so the reports don't load the transactions.
+	// The full exports are generated by the report jobs, see CreateJob.
+	Service struct {
+		repo     reportsRepository
+		storage  Storage
+		enqueuer jobEnqueuer
+		pageSize int32
+	}
+
+	// ServiceOption is a function that configures the reports service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new reports service.
+// The report jobs are disabled unless both the storage and the enqueuer are set.
+func NewService(repo reportsRepository, opts ...ServiceOption) *Service {
+	s := &Service{repo: repo, pageSize: 1000}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithStorage sets the storage of the generated report files.
+func WithStorage(storage Storage) ServiceOption {
+	return func(s *Service) {
+		s.storage = storage
+	}
+}
+
+// WithEnqueuer sets the enqueuer of the report generation tasks.
+func WithEnqueuer(enq jobEnqueuer) ServiceOption {
+	return func(s *Service) {
+		s.enqueuer = enq
+	}
+}
+
+// WithExportPageSize sets the number of rows loaded from the database at once by the report jobs.
+// Default is 1000.
+func WithExportPageSize(n int) ServiceOption {
+	return func(s *Service) {
+		if n > 0 {
+			s.pageSize = int32(n)
+		}
+	}
+}
+
+// Revenue returns the completed transactions amounts within the given period,
+// grouped by day, currency and merchant wallet.
+func (s *Service) Revenue(ctx context.Context, from, to time.Time) ([]RevenueRow, error) {
+	rows, err := s.repo.GetRevenueReport(ctx, repository.GetRevenueReportParams{
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revenue report: %w", err)
+	}
+
+	result := make([]RevenueRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, RevenueRow{
+			Date:           row.Day.Format(dateFormat),
+			Currency:       row.DestinationMint,
+			Merchant:       row.DestinationWallet,
+			Transactions:   uint64(row.Transactions),
+			Amount:         uint64(row.Amount),
+			DiscountAmount: uint64(row.DiscountAmount),
+			VoucherAmount:  uint64(row.VoucherAmount),
+			TotalAmount:    uint64(row.TotalAmount),
+		})
+	}
+
+	return result, nil
+}
+
+// Bonus returns the bonus minted and redeemed by the completed transactions
+// within the given period, grouped by day and currency.
+func (s *Service) Bonus(ctx context.Context, from, to time.Time) ([]BonusRow, error) {
+	rows, err := s.repo.GetBonusReport(ctx, repository.GetBonusReportParams{
+		FromDate: from,
+		ToDate:   to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bonus report: %w", err)
+	}
+
+	result := make([]BonusRow, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, BonusRow{
+			Date:     row.Day.Format(dateFormat),
+			Currency: row.DestinationMint,
+			Minted:   uint64(row.Minted),
+			Redeemed: uint64(row.Redeemed),
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,263 @@
+package reports_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/reports"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	revenue    []repository.GetRevenueReportRow
+	bonus      []repository.GetBonusReportRow
+	settlement []repository.GetSettlementExportRow
+	jobs       map[uuid.UUID]repository.ReportJob
+}
+
+func (r *repoMock) GetRevenueReport(ctx context.Context, arg repository.GetRevenueReportParams) ([]repository.GetRevenueReportRow, error) {
+	return r.revenue, nil
+}
+
+func (r *repoMock) GetBonusReport(ctx context.Context, arg repository.GetBonusReportParams) ([]repository.GetBonusReportRow, error) {
+	return r.bonus, nil
+}
+
+func (r *repoMock) GetPaymentHistoryExport(ctx context.Context, arg repository.GetPaymentHistoryExportParams) ([]repository.GetPaymentHistoryExportRow, error) {
+	return nil, nil
+}
+
+func (r *repoMock) GetSettlementExport(ctx context.Context, arg repository.GetSettlementExportParams) ([]repository.GetSettlementExportRow, error) {
+	if int(arg.Offset) >= len(r.settlement) {
+		return nil, nil
+	}
+	end := int(arg.Offset + arg.Limit)
+	if end > len(r.settlement) {
+		end = len(r.settlement)
+	}
+	return r.settlement[arg.Offset:end], nil
+}
+
+func (r *repoMock) CreateReportJob(ctx context.Context, arg repository.CreateReportJobParams) (repository.ReportJob, error) {
+	job := repository.ReportJob{
+		ID:        uuid.New(),
+		Kind:      arg.Kind,
+		Status:    reports.JobStatusPending,
+		FromDate:  arg.FromDate,
+		ToDate:    arg.ToDate,
+		CreatedAt: time.Now(),
+	}
+	r.jobs[job.ID] = job
+	return job, nil
+}
+
+func (r *repoMock) GetReportJob(ctx context.Context, id uuid.UUID) (repository.ReportJob, error) {
+	job, ok := r.jobs[id]
+	if !ok {
+		return repository.ReportJob{}, sql.ErrNoRows
+	}
+	return job, nil
+}
+
+func (r *repoMock) StartReportJob(ctx context.Context, id uuid.UUID) (int64, error) {
+	job, ok := r.jobs[id]
+	if !ok || (job.Status != reports.JobStatusPending && job.Status != reports.JobStatusRunning) {
+		return 0, nil
+	}
+	job.Status = reports.JobStatusRunning
+	r.jobs[id] = job
+	return 1, nil
+}
+
+func (r *repoMock) CompleteReportJob(ctx context.Context, arg repository.CompleteReportJobParams) (int64, error) {
+	job, ok := r.jobs[arg.ID]
+	if !ok || job.Status != reports.JobStatusRunning {
+		return 0, nil
+	}
+	job.Status = reports.JobStatusCompleted
+	job.FileKey = arg.FileKey
+	job.RowCount = arg.RowCount
+	r.jobs[arg.ID] = job
+	return 1, nil
+}
+
+func (r *repoMock) FailReportJob(ctx context.Context, arg repository.FailReportJobParams) (int64, error) {
+	job, ok := r.jobs[arg.ID]
+	if !ok || (job.Status != reports.JobStatusPending && job.Status != reports.JobStatusRunning) {
+		return 0, nil
+	}
+	job.Status = reports.JobStatusFailed
+	job.Error = arg.Error
+	r.jobs[arg.ID] = job
+	return 1, nil
+}
+
+type enqueuerMock struct {
+	jobs []uuid.UUID
+}
+
+func (e *enqueuerMock) GenerateReport(ctx context.Context, jobID uuid.UUID) error {
+	e.jobs = append(e.jobs, jobID)
+	return nil
+}
+
+func TestServiceRevenue(t *testing.T) {
+	day := time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)
+	repo := &repoMock{revenue: []repository.GetRevenueReportRow{
+		{Day: day, DestinationMint: "SOL", DestinationWallet: "wallet1", Transactions: 2, Amount: 300, DiscountAmount: 50, TotalAmount: 250},
+		{Day: day.AddDate(0, 0, 1), DestinationMint: "USDC", DestinationWallet: "wallet2", Transactions: 1, Amount: 100, VoucherAmount: 100},
+	}}
+
+	report, err := reports.NewService(repo).Revenue(context.Background(), day, day.AddDate(0, 0, 2))
+	require.NoError(t, err)
+	require.Len(t, report, 2)
+	require.Equal(t, reports.RevenueRow{
+		Date:           "2023-03-14",
+		Currency:       "SOL",
+		Merchant:       "wallet1",
+		Transactions:   2,
+		Amount:         300,
+		DiscountAmount: 50,
+		TotalAmount:    250,
+	}, report[0])
+	require.Equal(t, "2023-03-15", report[1].Date)
+	require.EqualValues(t, 100, report[1].VoucherAmount)
+	require.Zero(t, report[1].TotalAmount)
+}
+
+func TestServiceBonus(t *testing.T) {
+	day := time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)
+	repo := &repoMock{bonus: []repository.GetBonusReportRow{
+		{Day: day, DestinationMint: "SOL", Minted: 1000, Redeemed: 20},
+	}}
+
+	report, err := reports.NewService(repo).Bonus(context.Background(), day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, []reports.BonusRow{
+		{Date: "2023-03-14", Currency: "SOL", Minted: 1000, Redeemed: 20},
+	}, report)
+}
+
+func TestServiceReportJobs(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)
+	repo := &repoMock{jobs: map[uuid.UUID]repository.ReportJob{}}
+	for i := 0; i < 3; i++ {
+		repo.settlement = append(repo.settlement, repository.GetSettlementExportRow{
+			ID:                uuid.New(),
+			PaymentID:         uuid.New(),
+			DestinationWallet: "wallet1",
+			DestinationMint:   "SOL",
+			Amount:            100,
+			TotalAmount:       100,
+			NetworkFee:        5000,
+			TxSignature:       sql.NullString{String: "sig", Valid: true},
+			CreatedAt:         day,
+		})
+	}
+
+	// the jobs are disabled without the storage
+	_, err := reports.NewService(repo).CreateJob(ctx, reports.ReportKindSettlement, day, day.AddDate(0, 0, 1))
+	require.ErrorIs(t, err, reports.ErrReportJobsNotSupported)
+
+	storage, err := reports.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	enq := &enqueuerMock{}
+	svc := reports.NewService(repo,
+		reports.WithStorage(storage),
+		reports.WithEnqueuer(enq),
+		reports.WithExportPageSize(2),
+	)
+
+	_, err = svc.CreateJob(ctx, "unknown", day, day.AddDate(0, 0, 1))
+	require.ErrorIs(t, err, reports.ErrInvalidReportKind)
+
+	job, err := svc.CreateJob(ctx, reports.ReportKindSettlement, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, reports.JobStatusPending, job.Status)
+	require.Equal(t, []uuid.UUID{job.ID}, enq.jobs)
+
+	// the file can't be downloaded until the job is completed
+	_, _, err = svc.OpenJobFile(ctx, job.ID)
+	require.ErrorIs(t, err, reports.ErrReportNotReady)
+
+	job, err = svc.GenerateReport(ctx, job.ID)
+	require.NoError(t, err)
+	require.Equal(t, reports.JobStatusCompleted, job.Status)
+	require.EqualValues(t, 3, job.Rows)
+
+	// the duplicated task doesn't generate the report twice
+	_, err = svc.GenerateReport(ctx, job.ID)
+	require.ErrorIs(t, err, reports.ErrReportJobFinished)
+
+	f, _, err := svc.OpenJobFile(ctx, job.ID)
+	require.NoError(t, err)
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 4) // header and all the pages
+	require.True(t, strings.HasPrefix(lines[0], "transaction_id,payment_id,"))
+	require.Contains(t, lines[1], ",5000,")
+
+	_, err = svc.GetJob(ctx, uuid.New())
+	require.ErrorIs(t, err, reports.ErrReportJobNotFound)
+}
+
+func TestServiceExportAccounting(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2023, 3, 14, 0, 0, 0, 0, time.UTC)
+	repo := &repoMock{}
+	for i := 0; i < 3; i++ {
+		repo.settlement = append(repo.settlement, repository.GetSettlementExportRow{
+			ID:                uuid.New(),
+			PaymentID:         uuid.New(),
+			DestinationWallet: "wallet1",
+			DestinationMint:   "USDC",
+			Amount:            2500000,
+			TotalAmount:       2500000,
+			NetworkFee:        5000,
+			ExchangeRate:      sql.NullString{String: "0.9998", Valid: true},
+			FiatCurrency:      sql.NullString{String: "EUR", Valid: true},
+			TxSignature:       sql.NullString{String: "sig", Valid: true},
+			CreatedAt:         day,
+		})
+	}
+	svc := reports.NewService(repo, reports.WithExportPageSize(2))
+
+	var buf strings.Builder
+	rows, err := svc.ExportAccounting(ctx, &buf, reports.ExportFormatCSV, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, rows)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4) // header and all the pages
+	require.True(t, strings.HasPrefix(lines[0], "transaction_id,payment_id,"))
+	require.Contains(t, lines[1], ",EUR,0.9998,sig,")
+
+	buf.Reset()
+	rows, err = svc.ExportAccounting(ctx, &buf, reports.ExportFormatJSON, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.EqualValues(t, 3, rows)
+	var result []reports.AccountingRow
+	require.NoError(t, json.Unmarshal([]byte(buf.String()), &result))
+	require.Len(t, result, 3)
+	require.Equal(t, repo.settlement[2].ID, result[2].TransactionID)
+	require.Equal(t, "0.9998", result[0].ExchangeRate)
+	require.EqualValues(t, 5000, result[0].NetworkFee)
+
+	// the empty export is still a valid JSON array
+	buf.Reset()
+	_, err = reports.NewService(&repoMock{}).ExportAccounting(ctx, &buf, reports.ExportFormatJSON, day, day.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	require.Equal(t, "[]\n", buf.String())
+
+	_, err = svc.ExportAccounting(ctx, &buf, "xml", day, day.AddDate(0, 0, 1))
+	require.ErrorIs(t, err, reports.ErrInvalidExportFormat)
+}
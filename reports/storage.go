@@ -0,0 +1,219 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/awssig"
+)
+
+type (
+	// Storage keeps the generated report files.
+	// The keys are slash-separated paths, e.g. reports/<job id>.csv.
+	Storage interface {
+		Put(ctx context.Context, key string, body io.Reader) error
+		Open(ctx context.Context, key string) (io.ReadCloser, error)
+	}
+
+	// LocalStorage keeps the report files in the local directory,
+	// e.g. a volume shared by the API and the worker instances.
+	LocalStorage struct {
+		dir string
+	}
+
+	// S3Storage keeps the report files in the S3 bucket or in the S3-compatible storage.
+	// The requests are signed with AWS Signature Version 4, so no SDK is needed.
+	S3Storage struct {
+		client   *http.Client
+		endpoint string
+		bucket   string
+		region   string
+		creds    awssig.Credentials
+	}
+
+	// S3StorageOption is a function that configures the S3 storage.
+	S3StorageOption func(*S3Storage)
+)
+
+// NewLocalStorage creates a new local report storage in the given directory.
+// The directory is created if it doesn't exist.
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("report storage directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create report storage directory: %w", err)
+	}
+
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Put writes the file with the given key.
+// The file is written to a temporary file first, so a partially written report is never opened.
+func (s *LocalStorage) Put(_ context.Context, key string, body io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".report-*")
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store report file: %w", err)
+	}
+
+	return nil
+}
+
+// Open opens the file with the given key.
+func (s *LocalStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrReportFileNotFound
+		}
+		return nil, fmt.Errorf("failed to open report file: %w", err)
+	}
+
+	return f, nil
+}
+
+// path returns the file path of the key, the key can't escape the storage directory.
+func (s *LocalStorage) path(key string) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash(key))
+	if rel == "." || filepath.IsAbs(rel) || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid report file key: %s", key)
+	}
+
+	return filepath.Join(s.dir, rel), nil
+}
+
+// NewS3Storage creates a new report storage in the S3 bucket.
+func NewS3Storage(bucket, region string, creds awssig.Credentials, opts ...S3StorageOption) (*S3Storage, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is required")
+	}
+	if region == "" {
+		return nil, fmt.Errorf("aws region is required")
+	}
+
+	s := &S3Storage{
+		client:   &http.Client{Timeout: 5 * time.Minute},
+		endpoint: fmt.Sprintf("https://s3.%s.amazonaws.com", region),
+		bucket:   bucket,
+		region:   region,
+		creds:    creds,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// WithS3Endpoint sets the endpoint of the S3-compatible storage, e.g. MinIO.
+// The bucket is addressed in the path style: <endpoint>/<bucket>/<key>.
+func WithS3Endpoint(endpoint string) S3StorageOption {
+	return func(s *S3Storage) {
+		if endpoint != "" {
+			s.endpoint = strings.TrimRight(endpoint, "/")
+		}
+	}
+}
+
+// Put uploads the file with the given key.
+// The request must be signed with the payload hash, so the file is read into memory.
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read report file: %w", err)
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return fmt.Errorf("s3: put object: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// Open downloads the file with the given key.
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		if errors.Is(err, ErrReportFileNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("s3: get object: %w", err)
+	}
+
+	return resp.Body, nil
+}
+
+// do makes a signed request to the object with the given key.
+// The response body must be closed by the caller.
+func (s *S3Storage) do(ctx context.Context, method, key string, body []byte) (*http.Response, error) {
+	url := fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, strings.TrimLeft(key, "/"))
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "text/csv")
+	}
+	sum := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+
+	if err := awssig.SignRequest(req, body, s.creds, s.region, "s3", time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrReportFileNotFound
+	case resp.StatusCode != http.StatusOK:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, string(msg))
+	}
+
+	return resp, nil
+}
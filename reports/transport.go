@@ -0,0 +1,169 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the revenue reporting API.
+// The report jobs are created with POST /jobs, polled with GET /jobs/{job_id}
+// and downloaded with GET /jobs/{job_id}/download once completed.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/revenue", httptransport.NewServer(
+		e.GetRevenue,
+		decodePeriodRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Get("/bonus", httptransport.NewServer(
+		e.GetBonus,
+		decodePeriodRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/jobs", httptransport.NewServer(
+		e.CreateJob,
+		decodeCreateJobRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Get("/jobs/{job_id}", httptransport.NewServer(
+		e.GetJob,
+		decodeJobIDRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Get("/jobs/{job_id}/download", httptransport.NewServer(
+		e.DownloadJob,
+		decodeJobIDRequest,
+		encodeDownloadJobResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, ErrInvalidRequest),
+		errors.Is(err, ErrInvalidPeriod),
+		errors.Is(err, ErrInvalidReportKind):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, ErrReportJobNotFound), errors.Is(err, ErrReportFileNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrReportNotReady):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, ErrReportJobsNotSupported):
+		return http.StatusNotImplemented, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodePeriodRequest is a transport/http.DecodeRequestFunc that decodes
+// the report period from the query string: ?from=2023-03-01&to=2023-04-01.
+// Both dates are optional and accept either YYYY-MM-DD or RFC3339 format.
+func decodePeriodRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var (
+		req PeriodRequest
+		err error
+	)
+
+	if req.From, err = utils.ParseDate(r.URL.Query().Get("from")); err != nil {
+		return nil, fmt.Errorf("%w: from: %s", ErrInvalidPeriod, err.Error())
+	}
+	if req.To, err = utils.ParseDate(r.URL.Query().Get("to")); err != nil {
+		return nil, fmt.Errorf("%w: to: %s", ErrInvalidPeriod, err.Error())
+	}
+
+	return req, nil
+}
+
+// decodeCreateJobRequest is a transport/http.DecodeRequestFunc that decodes
+// a JSON-encoded request from the HTTP request body: {"kind": "payments", "from": "2023-03-01", "to": "2023-04-01"}.
+// The period accepts the same formats as the report query string.
+func decodeCreateJobRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var body struct {
+		Kind string `json:"kind"`
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	var (
+		req = CreateJobRequest{Kind: body.Kind}
+		err error
+	)
+	if req.From, err = utils.ParseDate(body.From); err != nil {
+		return nil, fmt.Errorf("%w: from: %s", ErrInvalidPeriod, err.Error())
+	}
+	if req.To, err = utils.ParseDate(body.To); err != nil {
+		return nil, fmt.Errorf("%w: to: %s", ErrInvalidPeriod, err.Error())
+	}
+
+	return req, nil
+}
+
+// decodeJobIDRequest is a transport/http.DecodeRequestFunc that decodes
+// the report job ID from the URL path.
+func decodeJobIDRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "job_id"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid job id: %v", ErrInvalidRequest, err)
+	}
+	return id, nil
+}
+
+// encodeDownloadJobResponse streams the report file to the client as the CSV attachment.
+func encodeDownloadJobResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	resp, ok := response.(DownloadJobResponse)
+	if !ok {
+		return ErrInvalidRequest
+	}
+	defer resp.File.Close()
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(
+		`attachment; filename="%s-%s-%s.csv"`,
+		resp.Job.Kind, resp.Job.From.Format(dateFormat), resp.Job.To.Format(dateFormat),
+	))
+	_, err := io.Copy(w, resp.File)
+
+	return err
+}
@@ -0,0 +1,88 @@
+package reports
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// dateFormat is the format of the report days.
+const dateFormat = "2006-01-02"
+
+// Report kinds of the report jobs.
+const (
+	ReportKindPayments   = "payments"   // full payment history with the transactions of the payments
+	ReportKindSettlement = "settlement" // completed transactions with the fees and signatures, for the reconciliation
+)
+
+// Report job statuses.
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusCompleted = "completed"
+	JobStatusFailed    = "failed"
+)
+
+type (
+	// RevenueRow is the revenue of the merchant wallet in the currency for the day.
+	// All the amounts are in the base units of the currency mint.
+	RevenueRow struct {
+		Date           string `json:"date"`
+		Currency       string `json:"currency"`
+		Merchant       string `json:"merchant"`
+		Transactions   uint64 `json:"transactions"`
+		Amount         uint64 `json:"amount"`
+		DiscountAmount uint64 `json:"discount_amount"`
+		VoucherAmount  uint64 `json:"voucher_amount"`
+		TotalAmount    uint64 `json:"total_amount"`
+	}
+
+	// BonusRow is the bonus activity of the payments in the currency for the day.
+	// Minted is in the base units of the bonus mint, redeemed is the bonus discount
+	// in the base units of the currency mint.
+	BonusRow struct {
+		Date     string `json:"date"`
+		Currency string `json:"currency"`
+		Minted   uint64 `json:"minted"`
+		Redeemed uint64 `json:"redeemed"`
+	}
+
+	// Job is the asynchronous export of the report into the downloadable file.
+	Job struct {
+		ID          uuid.UUID  `json:"id"`
+		Kind        string     `json:"kind"`
+		Status      string     `json:"status"`
+		From        time.Time  `json:"from"`
+		To          time.Time  `json:"to"`
+		Rows        int64      `json:"rows"`
+		Error       string     `json:"error,omitempty"`
+		CreatedAt   time.Time  `json:"created_at"`
+		StartedAt   *time.Time `json:"started_at,omitempty"`
+		CompletedAt *time.Time `json:"completed_at,omitempty"`
+	}
+
+	// GenerateReportPayload is the payload for the reports:generate_report task.
+	GenerateReportPayload struct {
+		JobID uuid.UUID `json:"job_id"`
+	}
+
+	reportsRepository interface {
+		GetRevenueReport(ctx context.Context, arg repository.GetRevenueReportParams) ([]repository.GetRevenueReportRow, error)
+		GetBonusReport(ctx context.Context, arg repository.GetBonusReportParams) ([]repository.GetBonusReportRow, error)
+
+		GetPaymentHistoryExport(ctx context.Context, arg repository.GetPaymentHistoryExportParams) ([]repository.GetPaymentHistoryExportRow, error)
+		GetSettlementExport(ctx context.Context, arg repository.GetSettlementExportParams) ([]repository.GetSettlementExportRow, error)
+
+		CreateReportJob(ctx context.Context, arg repository.CreateReportJobParams) (repository.ReportJob, error)
+		GetReportJob(ctx context.Context, id uuid.UUID) (repository.ReportJob, error)
+		StartReportJob(ctx context.Context, id uuid.UUID) (int64, error)
+		CompleteReportJob(ctx context.Context, arg repository.CompleteReportJobParams) (int64, error)
+		FailReportJob(ctx context.Context, arg repository.FailReportJobParams) (int64, error)
+	}
+
+	jobEnqueuer interface {
+		GenerateReport(ctx context.Context, jobID uuid.UUID) error
+	}
+)
@@ -0,0 +1,88 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+// Task names.
+const (
+	TaskGenerateReport = "reports:generate_report"
+)
+
+type (
+	// Worker is a task handler for the report jobs.
+	Worker struct {
+		svc  service
+		emit func(events.EventName, interface{})
+	}
+
+	service interface {
+		GenerateReport(ctx context.Context, id uuid.UUID) (*Job, error)
+		FailJob(ctx context.Context, id uuid.UUID, cause error) (*Job, error)
+	}
+)
+
+// NewWorker creates a new report jobs task handler.
+// The emit function is used to fire the report.completed and report.failed events,
+// which are delivered to the merchant webhook.
+func NewWorker(svc service, emit func(events.EventName, interface{})) *Worker {
+	return &Worker{svc: svc, emit: emit}
+}
+
+// Register registers task handlers for the report jobs.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskGenerateReport, w.GenerateReport)
+}
+
+// GenerateReport generates the report file of the job and emits the report.completed event.
+// The job is marked as failed and the report.failed event is emitted once the task has exhausted its retries.
+func (w *Worker) GenerateReport(ctx context.Context, t *asynq.Task) error {
+	var p GenerateReportPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	job, err := w.svc.GenerateReport(ctx, p.JobID)
+	if err != nil {
+		if errors.Is(err, ErrReportJobFinished) {
+			return nil
+		}
+
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retried < maxRetry {
+			return fmt.Errorf("failed to generate report: %w", err)
+		}
+
+		job, ferr := w.svc.FailJob(ctx, p.JobID, err)
+		if ferr != nil {
+			return fmt.Errorf("failed to generate report: %w; %s", err, ferr.Error())
+		}
+		w.emit(events.ReportFailed, jobPayload(job))
+
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	w.emit(events.ReportCompleted, jobPayload(job))
+
+	return nil
+}
+
+// jobPayload returns the event payload of the report job.
+func jobPayload(job *Job) events.ReportJobPayload {
+	return events.ReportJobPayload{
+		Occurrence: events.OccurredNow(),
+		JobID:      job.ID.String(),
+		Kind:       job.Kind,
+		Status:     job.Status,
+		Rows:       job.Rows,
+		Error:      job.Error,
+	}
+}
@@ -24,30 +24,210 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.addAuditRecordStmt, err = db.PrepareContext(ctx, addAuditRecord); err != nil {
+		return nil, fmt.Errorf("error preparing query AddAuditRecord: %w", err)
+	}
+	if q.addOutboxEventStmt, err = db.PrepareContext(ctx, addOutboxEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query AddOutboxEvent: %w", err)
+	}
+	if q.addPaymentEventStmt, err = db.PrepareContext(ctx, addPaymentEvent); err != nil {
+		return nil, fmt.Errorf("error preparing query AddPaymentEvent: %w", err)
+	}
+	if q.addTransactionReferenceStmt, err = db.PrepareContext(ctx, addTransactionReference); err != nil {
+		return nil, fmt.Errorf("error preparing query AddTransactionReference: %w", err)
+	}
+	if q.advanceSubscriptionStmt, err = db.PrepareContext(ctx, advanceSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query AdvanceSubscription: %w", err)
+	}
+	if q.anyTransactionReferenceExistsStmt, err = db.PrepareContext(ctx, anyTransactionReferenceExists); err != nil {
+		return nil, fmt.Errorf("error preparing query AnyTransactionReferenceExists: %w", err)
+	}
+	if q.cancelSubscriptionStmt, err = db.PrepareContext(ctx, cancelSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query CancelSubscription: %w", err)
+	}
+	if q.completeReportJobStmt, err = db.PrepareContext(ctx, completeReportJob); err != nil {
+		return nil, fmt.Errorf("error preparing query CompleteReportJob: %w", err)
+	}
+	if q.createAPIKeyStmt, err = db.PrepareContext(ctx, createAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateAPIKey: %w", err)
+	}
+	if q.createDepositStmt, err = db.PrepareContext(ctx, createDeposit); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDeposit: %w", err)
+	}
+	if q.createDepositAddressStmt, err = db.PrepareContext(ctx, createDepositAddress); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateDepositAddress: %w", err)
+	}
+	if q.createLinkTokenStmt, err = db.PrepareContext(ctx, createLinkToken); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateLinkToken: %w", err)
+	}
+	if q.createMonthlyPartitionsStmt, err = db.PrepareContext(ctx, createMonthlyPartitions); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateMonthlyPartitions: %w", err)
+	}
 	if q.createPaymentStmt, err = db.PrepareContext(ctx, createPayment); err != nil {
 		return nil, fmt.Errorf("error preparing query CreatePayment: %w", err)
 	}
+	if q.createPaymentDisputeStmt, err = db.PrepareContext(ctx, createPaymentDispute); err != nil {
+		return nil, fmt.Errorf("error preparing query CreatePaymentDispute: %w", err)
+	}
+	if q.createReportJobStmt, err = db.PrepareContext(ctx, createReportJob); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateReportJob: %w", err)
+	}
+	if q.createSettlementStmt, err = db.PrepareContext(ctx, createSettlement); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSettlement: %w", err)
+	}
+	if q.createSubscriptionStmt, err = db.PrepareContext(ctx, createSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSubscription: %w", err)
+	}
+	if q.createSubscriptionInstallmentStmt, err = db.PrepareContext(ctx, createSubscriptionInstallment); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSubscriptionInstallment: %w", err)
+	}
+	if q.createSubscriptionPlanStmt, err = db.PrepareContext(ctx, createSubscriptionPlan); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateSubscriptionPlan: %w", err)
+	}
 	if q.createTransactionStmt, err = db.PrepareContext(ctx, createTransaction); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateTransaction: %w", err)
 	}
+	if q.createWebhookDestinationStmt, err = db.PrepareContext(ctx, createWebhookDestination); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateWebhookDestination: %w", err)
+	}
+	if q.deleteAuditRecordsCreatedBeforeStmt, err = db.PrepareContext(ctx, deleteAuditRecordsCreatedBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAuditRecordsCreatedBefore: %w", err)
+	}
+	if q.deleteCredentialRoleStmt, err = db.PrepareContext(ctx, deleteCredentialRole); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteCredentialRole: %w", err)
+	}
+	if q.deleteExpiredLinkTokensStmt, err = db.PrepareContext(ctx, deleteExpiredLinkTokens); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteExpiredLinkTokens: %w", err)
+	}
 	if q.deleteExpiredTokensStmt, err = db.PrepareContext(ctx, deleteExpiredTokens); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteExpiredTokens: %w", err)
 	}
+	if q.deleteOutboxEventsPublishedBeforeStmt, err = db.PrepareContext(ctx, deleteOutboxEventsPublishedBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteOutboxEventsPublishedBefore: %w", err)
+	}
 	if q.deleteTokenStmt, err = db.PrepareContext(ctx, deleteToken); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteToken: %w", err)
 	}
 	if q.deleteTokensByCredentialStmt, err = db.PrepareContext(ctx, deleteTokensByCredential); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteTokensByCredential: %w", err)
 	}
+	if q.deleteWebhookDestinationStmt, err = db.PrepareContext(ctx, deleteWebhookDestination); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteWebhookDestination: %w", err)
+	}
+	if q.depositExistsStmt, err = db.PrepareContext(ctx, depositExists); err != nil {
+		return nil, fmt.Errorf("error preparing query DepositExists: %w", err)
+	}
+	if q.expireOtherPendingTransactionsStmt, err = db.PrepareContext(ctx, expireOtherPendingTransactions); err != nil {
+		return nil, fmt.Errorf("error preparing query ExpireOtherPendingTransactions: %w", err)
+	}
+	if q.failReportJobStmt, err = db.PrepareContext(ctx, failReportJob); err != nil {
+		return nil, fmt.Errorf("error preparing query FailReportJob: %w", err)
+	}
+	if q.getAPIKeyStmt, err = db.PrepareContext(ctx, getAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAPIKey: %w", err)
+	}
+	if q.getAPIKeysStmt, err = db.PrepareContext(ctx, getAPIKeys); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAPIKeys: %w", err)
+	}
+	if q.getActiveAPIKeyByHashStmt, err = db.PrepareContext(ctx, getActiveAPIKeyByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveAPIKeyByHash: %w", err)
+	}
+	if q.getAuditRecordsStmt, err = db.PrepareContext(ctx, getAuditRecords); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAuditRecords: %w", err)
+	}
+	if q.getBonusReportStmt, err = db.PrepareContext(ctx, getBonusReport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetBonusReport: %w", err)
+	}
+	if q.getCredentialRoleStmt, err = db.PrepareContext(ctx, getCredentialRole); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCredentialRole: %w", err)
+	}
+	if q.getCredentialRolesStmt, err = db.PrepareContext(ctx, getCredentialRoles); err != nil {
+		return nil, fmt.Errorf("error preparing query GetCredentialRoles: %w", err)
+	}
+	if q.getDepositAddressByPaymentIDStmt, err = db.PrepareContext(ctx, getDepositAddressByPaymentID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDepositAddressByPaymentID: %w", err)
+	}
+	if q.getDepositAddressesToSweepStmt, err = db.PrepareContext(ctx, getDepositAddressesToSweep); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDepositAddressesToSweep: %w", err)
+	}
+	if q.getDueSubscriptionsStmt, err = db.PrepareContext(ctx, getDueSubscriptions); err != nil {
+		return nil, fmt.Errorf("error preparing query GetDueSubscriptions: %w", err)
+	}
+	if q.getFunnelReportStmt, err = db.PrepareContext(ctx, getFunnelReport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFunnelReport: %w", err)
+	}
+	if q.getLinkTokenStmt, err = db.PrepareContext(ctx, getLinkToken); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLinkToken: %w", err)
+	}
+	if q.getMerchantSettingsStmt, err = db.PrepareContext(ctx, getMerchantSettings); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMerchantSettings: %w", err)
+	}
+	if q.getMintDecimalsStmt, err = db.PrepareContext(ctx, getMintDecimals); err != nil {
+		return nil, fmt.Errorf("error preparing query GetMintDecimals: %w", err)
+	}
+	if q.getOpenPaymentDisputeStmt, err = db.PrepareContext(ctx, getOpenPaymentDispute); err != nil {
+		return nil, fmt.Errorf("error preparing query GetOpenPaymentDispute: %w", err)
+	}
 	if q.getPaymentStmt, err = db.PrepareContext(ctx, getPayment); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPayment: %w", err)
 	}
 	if q.getPaymentByExternalIDStmt, err = db.PrepareContext(ctx, getPaymentByExternalID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPaymentByExternalID: %w", err)
 	}
+	if q.getPaymentDisputesStmt, err = db.PrepareContext(ctx, getPaymentDisputes); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentDisputes: %w", err)
+	}
+	if q.getPaymentEventsStmt, err = db.PrepareContext(ctx, getPaymentEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentEvents: %w", err)
+	}
+	if q.getPaymentHistoryExportStmt, err = db.PrepareContext(ctx, getPaymentHistoryExport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentHistoryExport: %w", err)
+	}
+	if q.getPaymentStatusStmt, err = db.PrepareContext(ctx, getPaymentStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentStatus: %w", err)
+	}
+	if q.getPaymentsToReleaseStmt, err = db.PrepareContext(ctx, getPaymentsToRelease); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPaymentsToRelease: %w", err)
+	}
+	if q.getPendingOutboxEventsStmt, err = db.PrepareContext(ctx, getPendingOutboxEvents); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPendingOutboxEvents: %w", err)
+	}
+	if q.getPendingTransactionReferencesStmt, err = db.PrepareContext(ctx, getPendingTransactionReferences); err != nil {
+		return nil, fmt.Errorf("error preparing query GetPendingTransactionReferences: %w", err)
+	}
 	if q.getPendingTransactionsStmt, err = db.PrepareContext(ctx, getPendingTransactions); err != nil {
 		return nil, fmt.Errorf("error preparing query GetPendingTransactions: %w", err)
 	}
+	if q.getReportJobStmt, err = db.PrepareContext(ctx, getReportJob); err != nil {
+		return nil, fmt.Errorf("error preparing query GetReportJob: %w", err)
+	}
+	if q.getRevenueReportStmt, err = db.PrepareContext(ctx, getRevenueReport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetRevenueReport: %w", err)
+	}
+	if q.getSettlementByPaymentIDStmt, err = db.PrepareContext(ctx, getSettlementByPaymentID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSettlementByPaymentID: %w", err)
+	}
+	if q.getSettlementExportStmt, err = db.PrepareContext(ctx, getSettlementExport); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSettlementExport: %w", err)
+	}
+	if q.getSubscriptionStmt, err = db.PrepareContext(ctx, getSubscription); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubscription: %w", err)
+	}
+	if q.getSubscriptionInstallmentByDueAtStmt, err = db.PrepareContext(ctx, getSubscriptionInstallmentByDueAt); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubscriptionInstallmentByDueAt: %w", err)
+	}
+	if q.getSubscriptionInstallmentByPaymentIDStmt, err = db.PrepareContext(ctx, getSubscriptionInstallmentByPaymentID); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubscriptionInstallmentByPaymentID: %w", err)
+	}
+	if q.getSubscriptionInstallmentsStmt, err = db.PrepareContext(ctx, getSubscriptionInstallments); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubscriptionInstallments: %w", err)
+	}
+	if q.getSubscriptionPlanStmt, err = db.PrepareContext(ctx, getSubscriptionPlan); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubscriptionPlan: %w", err)
+	}
+	if q.getSubscriptionPlansStmt, err = db.PrepareContext(ctx, getSubscriptionPlans); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSubscriptionPlans: %w", err)
+	}
 	if q.getTokenStmt, err = db.PrepareContext(ctx, getToken); err != nil {
 		return nil, fmt.Errorf("error preparing query GetToken: %w", err)
 	}
@@ -60,44 +240,239 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getTransactionByReferenceStmt, err = db.PrepareContext(ctx, getTransactionByReference); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTransactionByReference: %w", err)
 	}
+	if q.getTransactionReferencesStmt, err = db.PrepareContext(ctx, getTransactionReferences); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTransactionReferences: %w", err)
+	}
 	if q.getTransactionsByPaymentIDStmt, err = db.PrepareContext(ctx, getTransactionsByPaymentID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTransactionsByPaymentID: %w", err)
 	}
+	if q.getTransactionsByStatusStmt, err = db.PrepareContext(ctx, getTransactionsByStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTransactionsByStatus: %w", err)
+	}
+	if q.getTransactionsByStatusCreatedBeforeStmt, err = db.PrepareContext(ctx, getTransactionsByStatusCreatedBefore); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTransactionsByStatusCreatedBefore: %w", err)
+	}
+	if q.getTransactionsByStatusCreatedBetweenStmt, err = db.PrepareContext(ctx, getTransactionsByStatusCreatedBetween); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTransactionsByStatusCreatedBetween: %w", err)
+	}
+	if q.getWebhookDestinationStmt, err = db.PrepareContext(ctx, getWebhookDestination); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhookDestination: %w", err)
+	}
+	if q.getWebhookDestinationsStmt, err = db.PrepareContext(ctx, getWebhookDestinations); err != nil {
+		return nil, fmt.Errorf("error preparing query GetWebhookDestinations: %w", err)
+	}
+	if q.holdPaymentStmt, err = db.PrepareContext(ctx, holdPayment); err != nil {
+		return nil, fmt.Errorf("error preparing query HoldPayment: %w", err)
+	}
+	if q.markDepositAddressSweptStmt, err = db.PrepareContext(ctx, markDepositAddressSwept); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkDepositAddressSwept: %w", err)
+	}
+	if q.markOutboxEventPublishedStmt, err = db.PrepareContext(ctx, markOutboxEventPublished); err != nil {
+		return nil, fmt.Errorf("error preparing query MarkOutboxEventPublished: %w", err)
+	}
 	if q.markPaymentsExpiredStmt, err = db.PrepareContext(ctx, markPaymentsExpired); err != nil {
 		return nil, fmt.Errorf("error preparing query MarkPaymentsExpired: %w", err)
 	}
 	if q.markTransactionsAsExpiredStmt, err = db.PrepareContext(ctx, markTransactionsAsExpired); err != nil {
 		return nil, fmt.Errorf("error preparing query MarkTransactionsAsExpired: %w", err)
 	}
+	if q.refreshPaymentAmountPaidStmt, err = db.PrepareContext(ctx, refreshPaymentAmountPaid); err != nil {
+		return nil, fmt.Errorf("error preparing query RefreshPaymentAmountPaid: %w", err)
+	}
+	if q.releasePaymentStmt, err = db.PrepareContext(ctx, releasePayment); err != nil {
+		return nil, fmt.Errorf("error preparing query ReleasePayment: %w", err)
+	}
+	if q.resolvePaymentDisputeStmt, err = db.PrepareContext(ctx, resolvePaymentDispute); err != nil {
+		return nil, fmt.Errorf("error preparing query ResolvePaymentDispute: %w", err)
+	}
+	if q.revokeAPIKeyStmt, err = db.PrepareContext(ctx, revokeAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query RevokeAPIKey: %w", err)
+	}
+	if q.rotateAPIKeyStmt, err = db.PrepareContext(ctx, rotateAPIKey); err != nil {
+		return nil, fmt.Errorf("error preparing query RotateAPIKey: %w", err)
+	}
+	if q.setSubscriptionInstallmentPaymentStmt, err = db.PrepareContext(ctx, setSubscriptionInstallmentPayment); err != nil {
+		return nil, fmt.Errorf("error preparing query SetSubscriptionInstallmentPayment: %w", err)
+	}
+	if q.startReportJobStmt, err = db.PrepareContext(ctx, startReportJob); err != nil {
+		return nil, fmt.Errorf("error preparing query StartReportJob: %w", err)
+	}
+	if q.storeCredentialRoleStmt, err = db.PrepareContext(ctx, storeCredentialRole); err != nil {
+		return nil, fmt.Errorf("error preparing query StoreCredentialRole: %w", err)
+	}
+	if q.storeMerchantSettingsStmt, err = db.PrepareContext(ctx, storeMerchantSettings); err != nil {
+		return nil, fmt.Errorf("error preparing query StoreMerchantSettings: %w", err)
+	}
+	if q.storeMintDecimalsStmt, err = db.PrepareContext(ctx, storeMintDecimals); err != nil {
+		return nil, fmt.Errorf("error preparing query StoreMintDecimals: %w", err)
+	}
 	if q.storeTokenStmt, err = db.PrepareContext(ctx, storeToken); err != nil {
 		return nil, fmt.Errorf("error preparing query StoreToken: %w", err)
 	}
+	if q.trackFunnelStageStmt, err = db.PrepareContext(ctx, trackFunnelStage); err != nil {
+		return nil, fmt.Errorf("error preparing query TrackFunnelStage: %w", err)
+	}
+	if q.updatePaymentAmountStmt, err = db.PrepareContext(ctx, updatePaymentAmount); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdatePaymentAmount: %w", err)
+	}
 	if q.updatePaymentStatusStmt, err = db.PrepareContext(ctx, updatePaymentStatus); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdatePaymentStatus: %w", err)
 	}
+	if q.updateSettlementStmt, err = db.PrepareContext(ctx, updateSettlement); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateSettlement: %w", err)
+	}
+	if q.updateSubscriptionInstallmentStatusStmt, err = db.PrepareContext(ctx, updateSubscriptionInstallmentStatus); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateSubscriptionInstallmentStatus: %w", err)
+	}
 	if q.updateTransactionByReferenceStmt, err = db.PrepareContext(ctx, updateTransactionByReference); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateTransactionByReference: %w", err)
 	}
+	if q.updateWebhookDestinationStmt, err = db.PrepareContext(ctx, updateWebhookDestination); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateWebhookDestination: %w", err)
+	}
+	if q.useLinkTokenStmt, err = db.PrepareContext(ctx, useLinkToken); err != nil {
+		return nil, fmt.Errorf("error preparing query UseLinkToken: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.addAuditRecordStmt != nil {
+		if cerr := q.addAuditRecordStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addAuditRecordStmt: %w", cerr)
+		}
+	}
+	if q.addOutboxEventStmt != nil {
+		if cerr := q.addOutboxEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addOutboxEventStmt: %w", cerr)
+		}
+	}
+	if q.addPaymentEventStmt != nil {
+		if cerr := q.addPaymentEventStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addPaymentEventStmt: %w", cerr)
+		}
+	}
+	if q.addTransactionReferenceStmt != nil {
+		if cerr := q.addTransactionReferenceStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing addTransactionReferenceStmt: %w", cerr)
+		}
+	}
+	if q.advanceSubscriptionStmt != nil {
+		if cerr := q.advanceSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing advanceSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.anyTransactionReferenceExistsStmt != nil {
+		if cerr := q.anyTransactionReferenceExistsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing anyTransactionReferenceExistsStmt: %w", cerr)
+		}
+	}
+	if q.cancelSubscriptionStmt != nil {
+		if cerr := q.cancelSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing cancelSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.completeReportJobStmt != nil {
+		if cerr := q.completeReportJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing completeReportJobStmt: %w", cerr)
+		}
+	}
+	if q.createAPIKeyStmt != nil {
+		if cerr := q.createAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.createDepositStmt != nil {
+		if cerr := q.createDepositStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDepositStmt: %w", cerr)
+		}
+	}
+	if q.createDepositAddressStmt != nil {
+		if cerr := q.createDepositAddressStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createDepositAddressStmt: %w", cerr)
+		}
+	}
+	if q.createLinkTokenStmt != nil {
+		if cerr := q.createLinkTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createLinkTokenStmt: %w", cerr)
+		}
+	}
+	if q.createMonthlyPartitionsStmt != nil {
+		if cerr := q.createMonthlyPartitionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createMonthlyPartitionsStmt: %w", cerr)
+		}
+	}
 	if q.createPaymentStmt != nil {
 		if cerr := q.createPaymentStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createPaymentStmt: %w", cerr)
 		}
 	}
+	if q.createPaymentDisputeStmt != nil {
+		if cerr := q.createPaymentDisputeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createPaymentDisputeStmt: %w", cerr)
+		}
+	}
+	if q.createReportJobStmt != nil {
+		if cerr := q.createReportJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createReportJobStmt: %w", cerr)
+		}
+	}
+	if q.createSettlementStmt != nil {
+		if cerr := q.createSettlementStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSettlementStmt: %w", cerr)
+		}
+	}
+	if q.createSubscriptionStmt != nil {
+		if cerr := q.createSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.createSubscriptionInstallmentStmt != nil {
+		if cerr := q.createSubscriptionInstallmentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSubscriptionInstallmentStmt: %w", cerr)
+		}
+	}
+	if q.createSubscriptionPlanStmt != nil {
+		if cerr := q.createSubscriptionPlanStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createSubscriptionPlanStmt: %w", cerr)
+		}
+	}
 	if q.createTransactionStmt != nil {
 		if cerr := q.createTransactionStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createTransactionStmt: %w", cerr)
 		}
 	}
+	if q.createWebhookDestinationStmt != nil {
+		if cerr := q.createWebhookDestinationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createWebhookDestinationStmt: %w", cerr)
+		}
+	}
+	if q.deleteAuditRecordsCreatedBeforeStmt != nil {
+		if cerr := q.deleteAuditRecordsCreatedBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAuditRecordsCreatedBeforeStmt: %w", cerr)
+		}
+	}
+	if q.deleteCredentialRoleStmt != nil {
+		if cerr := q.deleteCredentialRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteCredentialRoleStmt: %w", cerr)
+		}
+	}
+	if q.deleteExpiredLinkTokensStmt != nil {
+		if cerr := q.deleteExpiredLinkTokensStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteExpiredLinkTokensStmt: %w", cerr)
+		}
+	}
 	if q.deleteExpiredTokensStmt != nil {
 		if cerr := q.deleteExpiredTokensStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteExpiredTokensStmt: %w", cerr)
 		}
 	}
+	if q.deleteOutboxEventsPublishedBeforeStmt != nil {
+		if cerr := q.deleteOutboxEventsPublishedBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteOutboxEventsPublishedBeforeStmt: %w", cerr)
+		}
+	}
 	if q.deleteTokenStmt != nil {
 		if cerr := q.deleteTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteTokenStmt: %w", cerr)
@@ -108,6 +483,101 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing deleteTokensByCredentialStmt: %w", cerr)
 		}
 	}
+	if q.deleteWebhookDestinationStmt != nil {
+		if cerr := q.deleteWebhookDestinationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteWebhookDestinationStmt: %w", cerr)
+		}
+	}
+	if q.depositExistsStmt != nil {
+		if cerr := q.depositExistsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing depositExistsStmt: %w", cerr)
+		}
+	}
+	if q.expireOtherPendingTransactionsStmt != nil {
+		if cerr := q.expireOtherPendingTransactionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing expireOtherPendingTransactionsStmt: %w", cerr)
+		}
+	}
+	if q.failReportJobStmt != nil {
+		if cerr := q.failReportJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing failReportJobStmt: %w", cerr)
+		}
+	}
+	if q.getAPIKeyStmt != nil {
+		if cerr := q.getAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.getAPIKeysStmt != nil {
+		if cerr := q.getAPIKeysStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAPIKeysStmt: %w", cerr)
+		}
+	}
+	if q.getActiveAPIKeyByHashStmt != nil {
+		if cerr := q.getActiveAPIKeyByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveAPIKeyByHashStmt: %w", cerr)
+		}
+	}
+	if q.getAuditRecordsStmt != nil {
+		if cerr := q.getAuditRecordsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAuditRecordsStmt: %w", cerr)
+		}
+	}
+	if q.getBonusReportStmt != nil {
+		if cerr := q.getBonusReportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getBonusReportStmt: %w", cerr)
+		}
+	}
+	if q.getCredentialRoleStmt != nil {
+		if cerr := q.getCredentialRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCredentialRoleStmt: %w", cerr)
+		}
+	}
+	if q.getCredentialRolesStmt != nil {
+		if cerr := q.getCredentialRolesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getCredentialRolesStmt: %w", cerr)
+		}
+	}
+	if q.getDepositAddressByPaymentIDStmt != nil {
+		if cerr := q.getDepositAddressByPaymentIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDepositAddressByPaymentIDStmt: %w", cerr)
+		}
+	}
+	if q.getDepositAddressesToSweepStmt != nil {
+		if cerr := q.getDepositAddressesToSweepStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDepositAddressesToSweepStmt: %w", cerr)
+		}
+	}
+	if q.getDueSubscriptionsStmt != nil {
+		if cerr := q.getDueSubscriptionsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getDueSubscriptionsStmt: %w", cerr)
+		}
+	}
+	if q.getFunnelReportStmt != nil {
+		if cerr := q.getFunnelReportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFunnelReportStmt: %w", cerr)
+		}
+	}
+	if q.getLinkTokenStmt != nil {
+		if cerr := q.getLinkTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLinkTokenStmt: %w", cerr)
+		}
+	}
+	if q.getMerchantSettingsStmt != nil {
+		if cerr := q.getMerchantSettingsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMerchantSettingsStmt: %w", cerr)
+		}
+	}
+	if q.getMintDecimalsStmt != nil {
+		if cerr := q.getMintDecimalsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getMintDecimalsStmt: %w", cerr)
+		}
+	}
+	if q.getOpenPaymentDisputeStmt != nil {
+		if cerr := q.getOpenPaymentDisputeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getOpenPaymentDisputeStmt: %w", cerr)
+		}
+	}
 	if q.getPaymentStmt != nil {
 		if cerr := q.getPaymentStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPaymentStmt: %w", cerr)
@@ -118,11 +588,96 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getPaymentByExternalIDStmt: %w", cerr)
 		}
 	}
+	if q.getPaymentDisputesStmt != nil {
+		if cerr := q.getPaymentDisputesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentDisputesStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentEventsStmt != nil {
+		if cerr := q.getPaymentEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentEventsStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentHistoryExportStmt != nil {
+		if cerr := q.getPaymentHistoryExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentHistoryExportStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentStatusStmt != nil {
+		if cerr := q.getPaymentStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentStatusStmt: %w", cerr)
+		}
+	}
+	if q.getPaymentsToReleaseStmt != nil {
+		if cerr := q.getPaymentsToReleaseStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPaymentsToReleaseStmt: %w", cerr)
+		}
+	}
+	if q.getPendingOutboxEventsStmt != nil {
+		if cerr := q.getPendingOutboxEventsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPendingOutboxEventsStmt: %w", cerr)
+		}
+	}
+	if q.getPendingTransactionReferencesStmt != nil {
+		if cerr := q.getPendingTransactionReferencesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getPendingTransactionReferencesStmt: %w", cerr)
+		}
+	}
 	if q.getPendingTransactionsStmt != nil {
 		if cerr := q.getPendingTransactionsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getPendingTransactionsStmt: %w", cerr)
 		}
 	}
+	if q.getReportJobStmt != nil {
+		if cerr := q.getReportJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getReportJobStmt: %w", cerr)
+		}
+	}
+	if q.getRevenueReportStmt != nil {
+		if cerr := q.getRevenueReportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getRevenueReportStmt: %w", cerr)
+		}
+	}
+	if q.getSettlementByPaymentIDStmt != nil {
+		if cerr := q.getSettlementByPaymentIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSettlementByPaymentIDStmt: %w", cerr)
+		}
+	}
+	if q.getSettlementExportStmt != nil {
+		if cerr := q.getSettlementExportStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSettlementExportStmt: %w", cerr)
+		}
+	}
+	if q.getSubscriptionStmt != nil {
+		if cerr := q.getSubscriptionStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubscriptionStmt: %w", cerr)
+		}
+	}
+	if q.getSubscriptionInstallmentByDueAtStmt != nil {
+		if cerr := q.getSubscriptionInstallmentByDueAtStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubscriptionInstallmentByDueAtStmt: %w", cerr)
+		}
+	}
+	if q.getSubscriptionInstallmentByPaymentIDStmt != nil {
+		if cerr := q.getSubscriptionInstallmentByPaymentIDStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubscriptionInstallmentByPaymentIDStmt: %w", cerr)
+		}
+	}
+	if q.getSubscriptionInstallmentsStmt != nil {
+		if cerr := q.getSubscriptionInstallmentsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubscriptionInstallmentsStmt: %w", cerr)
+		}
+	}
+	if q.getSubscriptionPlanStmt != nil {
+		if cerr := q.getSubscriptionPlanStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubscriptionPlanStmt: %w", cerr)
+		}
+	}
+	if q.getSubscriptionPlansStmt != nil {
+		if cerr := q.getSubscriptionPlansStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSubscriptionPlansStmt: %w", cerr)
+		}
+	}
 	if q.getTokenStmt != nil {
 		if cerr := q.getTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTokenStmt: %w", cerr)
@@ -143,11 +698,56 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getTransactionByReferenceStmt: %w", cerr)
 		}
 	}
+	if q.getTransactionReferencesStmt != nil {
+		if cerr := q.getTransactionReferencesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTransactionReferencesStmt: %w", cerr)
+		}
+	}
 	if q.getTransactionsByPaymentIDStmt != nil {
 		if cerr := q.getTransactionsByPaymentIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getTransactionsByPaymentIDStmt: %w", cerr)
 		}
 	}
+	if q.getTransactionsByStatusStmt != nil {
+		if cerr := q.getTransactionsByStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTransactionsByStatusStmt: %w", cerr)
+		}
+	}
+	if q.getTransactionsByStatusCreatedBeforeStmt != nil {
+		if cerr := q.getTransactionsByStatusCreatedBeforeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTransactionsByStatusCreatedBeforeStmt: %w", cerr)
+		}
+	}
+	if q.getTransactionsByStatusCreatedBetweenStmt != nil {
+		if cerr := q.getTransactionsByStatusCreatedBetweenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTransactionsByStatusCreatedBetweenStmt: %w", cerr)
+		}
+	}
+	if q.getWebhookDestinationStmt != nil {
+		if cerr := q.getWebhookDestinationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhookDestinationStmt: %w", cerr)
+		}
+	}
+	if q.getWebhookDestinationsStmt != nil {
+		if cerr := q.getWebhookDestinationsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getWebhookDestinationsStmt: %w", cerr)
+		}
+	}
+	if q.holdPaymentStmt != nil {
+		if cerr := q.holdPaymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing holdPaymentStmt: %w", cerr)
+		}
+	}
+	if q.markDepositAddressSweptStmt != nil {
+		if cerr := q.markDepositAddressSweptStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markDepositAddressSweptStmt: %w", cerr)
+		}
+	}
+	if q.markOutboxEventPublishedStmt != nil {
+		if cerr := q.markOutboxEventPublishedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing markOutboxEventPublishedStmt: %w", cerr)
+		}
+	}
 	if q.markPaymentsExpiredStmt != nil {
 		if cerr := q.markPaymentsExpiredStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing markPaymentsExpiredStmt: %w", cerr)
@@ -158,21 +758,101 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing markTransactionsAsExpiredStmt: %w", cerr)
 		}
 	}
+	if q.refreshPaymentAmountPaidStmt != nil {
+		if cerr := q.refreshPaymentAmountPaidStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing refreshPaymentAmountPaidStmt: %w", cerr)
+		}
+	}
+	if q.releasePaymentStmt != nil {
+		if cerr := q.releasePaymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing releasePaymentStmt: %w", cerr)
+		}
+	}
+	if q.resolvePaymentDisputeStmt != nil {
+		if cerr := q.resolvePaymentDisputeStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing resolvePaymentDisputeStmt: %w", cerr)
+		}
+	}
+	if q.revokeAPIKeyStmt != nil {
+		if cerr := q.revokeAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing revokeAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.rotateAPIKeyStmt != nil {
+		if cerr := q.rotateAPIKeyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing rotateAPIKeyStmt: %w", cerr)
+		}
+	}
+	if q.setSubscriptionInstallmentPaymentStmt != nil {
+		if cerr := q.setSubscriptionInstallmentPaymentStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setSubscriptionInstallmentPaymentStmt: %w", cerr)
+		}
+	}
+	if q.startReportJobStmt != nil {
+		if cerr := q.startReportJobStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing startReportJobStmt: %w", cerr)
+		}
+	}
+	if q.storeCredentialRoleStmt != nil {
+		if cerr := q.storeCredentialRoleStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing storeCredentialRoleStmt: %w", cerr)
+		}
+	}
+	if q.storeMerchantSettingsStmt != nil {
+		if cerr := q.storeMerchantSettingsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing storeMerchantSettingsStmt: %w", cerr)
+		}
+	}
+	if q.storeMintDecimalsStmt != nil {
+		if cerr := q.storeMintDecimalsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing storeMintDecimalsStmt: %w", cerr)
+		}
+	}
 	if q.storeTokenStmt != nil {
 		if cerr := q.storeTokenStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing storeTokenStmt: %w", cerr)
 		}
 	}
+	if q.trackFunnelStageStmt != nil {
+		if cerr := q.trackFunnelStageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing trackFunnelStageStmt: %w", cerr)
+		}
+	}
+	if q.updatePaymentAmountStmt != nil {
+		if cerr := q.updatePaymentAmountStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updatePaymentAmountStmt: %w", cerr)
+		}
+	}
 	if q.updatePaymentStatusStmt != nil {
 		if cerr := q.updatePaymentStatusStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updatePaymentStatusStmt: %w", cerr)
 		}
 	}
+	if q.updateSettlementStmt != nil {
+		if cerr := q.updateSettlementStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateSettlementStmt: %w", cerr)
+		}
+	}
+	if q.updateSubscriptionInstallmentStatusStmt != nil {
+		if cerr := q.updateSubscriptionInstallmentStatusStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateSubscriptionInstallmentStatusStmt: %w", cerr)
+		}
+	}
 	if q.updateTransactionByReferenceStmt != nil {
 		if cerr := q.updateTransactionByReferenceStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateTransactionByReferenceStmt: %w", cerr)
 		}
 	}
+	if q.updateWebhookDestinationStmt != nil {
+		if cerr := q.updateWebhookDestinationStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateWebhookDestinationStmt: %w", cerr)
+		}
+	}
+	if q.useLinkTokenStmt != nil {
+		if cerr := q.useLinkTokenStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing useLinkTokenStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -212,47 +892,217 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                               DBTX
 	tx                                               *sql.Tx
+	addAuditRecordStmt                               *sql.Stmt
+	addOutboxEventStmt                               *sql.Stmt
+	addPaymentEventStmt                              *sql.Stmt
+	addTransactionReferenceStmt                      *sql.Stmt
+	advanceSubscriptionStmt                          *sql.Stmt
+	anyTransactionReferenceExistsStmt                *sql.Stmt
+	cancelSubscriptionStmt                           *sql.Stmt
+	completeReportJobStmt                            *sql.Stmt
+	createAPIKeyStmt                                 *sql.Stmt
+	createDepositStmt                                *sql.Stmt
+	createDepositAddressStmt                         *sql.Stmt
+	createLinkTokenStmt                              *sql.Stmt
+	createMonthlyPartitionsStmt                      *sql.Stmt
 	createPaymentStmt                                *sql.Stmt
+	createPaymentDisputeStmt                         *sql.Stmt
+	createReportJobStmt                              *sql.Stmt
+	createSettlementStmt                             *sql.Stmt
+	createSubscriptionStmt                           *sql.Stmt
+	createSubscriptionInstallmentStmt                *sql.Stmt
+	createSubscriptionPlanStmt                       *sql.Stmt
 	createTransactionStmt                            *sql.Stmt
+	createWebhookDestinationStmt                     *sql.Stmt
+	deleteAuditRecordsCreatedBeforeStmt              *sql.Stmt
+	deleteCredentialRoleStmt                         *sql.Stmt
+	deleteExpiredLinkTokensStmt                      *sql.Stmt
 	deleteExpiredTokensStmt                          *sql.Stmt
+	deleteOutboxEventsPublishedBeforeStmt            *sql.Stmt
 	deleteTokenStmt                                  *sql.Stmt
 	deleteTokensByCredentialStmt                     *sql.Stmt
+	deleteWebhookDestinationStmt                     *sql.Stmt
+	depositExistsStmt                                *sql.Stmt
+	expireOtherPendingTransactionsStmt               *sql.Stmt
+	failReportJobStmt                                *sql.Stmt
+	getAPIKeyStmt                                    *sql.Stmt
+	getAPIKeysStmt                                   *sql.Stmt
+	getActiveAPIKeyByHashStmt                        *sql.Stmt
+	getAuditRecordsStmt                              *sql.Stmt
+	getBonusReportStmt                               *sql.Stmt
+	getCredentialRoleStmt                            *sql.Stmt
+	getCredentialRolesStmt                           *sql.Stmt
+	getDepositAddressByPaymentIDStmt                 *sql.Stmt
+	getDepositAddressesToSweepStmt                   *sql.Stmt
+	getDueSubscriptionsStmt                          *sql.Stmt
+	getFunnelReportStmt                              *sql.Stmt
+	getLinkTokenStmt                                 *sql.Stmt
+	getMerchantSettingsStmt                          *sql.Stmt
+	getMintDecimalsStmt                              *sql.Stmt
+	getOpenPaymentDisputeStmt                        *sql.Stmt
 	getPaymentStmt                                   *sql.Stmt
 	getPaymentByExternalIDStmt                       *sql.Stmt
+	getPaymentDisputesStmt                           *sql.Stmt
+	getPaymentEventsStmt                             *sql.Stmt
+	getPaymentHistoryExportStmt                      *sql.Stmt
+	getPaymentStatusStmt                             *sql.Stmt
+	getPaymentsToReleaseStmt                         *sql.Stmt
+	getPendingOutboxEventsStmt                       *sql.Stmt
+	getPendingTransactionReferencesStmt              *sql.Stmt
 	getPendingTransactionsStmt                       *sql.Stmt
+	getReportJobStmt                                 *sql.Stmt
+	getRevenueReportStmt                             *sql.Stmt
+	getSettlementByPaymentIDStmt                     *sql.Stmt
+	getSettlementExportStmt                          *sql.Stmt
+	getSubscriptionStmt                              *sql.Stmt
+	getSubscriptionInstallmentByDueAtStmt            *sql.Stmt
+	getSubscriptionInstallmentByPaymentIDStmt        *sql.Stmt
+	getSubscriptionInstallmentsStmt                  *sql.Stmt
+	getSubscriptionPlanStmt                          *sql.Stmt
+	getSubscriptionPlansStmt                         *sql.Stmt
 	getTokenStmt                                     *sql.Stmt
 	getTransactionStmt                               *sql.Stmt
 	getTransactionByPaymentIDSourceWalletAndMintStmt *sql.Stmt
 	getTransactionByReferenceStmt                    *sql.Stmt
+	getTransactionReferencesStmt                     *sql.Stmt
 	getTransactionsByPaymentIDStmt                   *sql.Stmt
+	getTransactionsByStatusStmt                      *sql.Stmt
+	getTransactionsByStatusCreatedBeforeStmt         *sql.Stmt
+	getTransactionsByStatusCreatedBetweenStmt        *sql.Stmt
+	getWebhookDestinationStmt                        *sql.Stmt
+	getWebhookDestinationsStmt                       *sql.Stmt
+	holdPaymentStmt                                  *sql.Stmt
+	markDepositAddressSweptStmt                      *sql.Stmt
+	markOutboxEventPublishedStmt                     *sql.Stmt
 	markPaymentsExpiredStmt                          *sql.Stmt
 	markTransactionsAsExpiredStmt                    *sql.Stmt
+	refreshPaymentAmountPaidStmt                     *sql.Stmt
+	releasePaymentStmt                               *sql.Stmt
+	resolvePaymentDisputeStmt                        *sql.Stmt
+	revokeAPIKeyStmt                                 *sql.Stmt
+	rotateAPIKeyStmt                                 *sql.Stmt
+	setSubscriptionInstallmentPaymentStmt            *sql.Stmt
+	startReportJobStmt                               *sql.Stmt
+	storeCredentialRoleStmt                          *sql.Stmt
+	storeMerchantSettingsStmt                        *sql.Stmt
+	storeMintDecimalsStmt                            *sql.Stmt
 	storeTokenStmt                                   *sql.Stmt
+	trackFunnelStageStmt                             *sql.Stmt
+	updatePaymentAmountStmt                          *sql.Stmt
 	updatePaymentStatusStmt                          *sql.Stmt
+	updateSettlementStmt                             *sql.Stmt
+	updateSubscriptionInstallmentStatusStmt          *sql.Stmt
 	updateTransactionByReferenceStmt                 *sql.Stmt
+	updateWebhookDestinationStmt                     *sql.Stmt
+	useLinkTokenStmt                                 *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
-		db:                           tx,
-		tx:                           tx,
-		createPaymentStmt:            q.createPaymentStmt,
-		createTransactionStmt:        q.createTransactionStmt,
-		deleteExpiredTokensStmt:      q.deleteExpiredTokensStmt,
-		deleteTokenStmt:              q.deleteTokenStmt,
-		deleteTokensByCredentialStmt: q.deleteTokensByCredentialStmt,
-		getPaymentStmt:               q.getPaymentStmt,
-		getPaymentByExternalIDStmt:   q.getPaymentByExternalIDStmt,
-		getPendingTransactionsStmt:   q.getPendingTransactionsStmt,
-		getTokenStmt:                 q.getTokenStmt,
-		getTransactionStmt:           q.getTransactionStmt,
+		db:                                               tx,
+		tx:                                               tx,
+		addAuditRecordStmt:                               q.addAuditRecordStmt,
+		addOutboxEventStmt:                               q.addOutboxEventStmt,
+		addPaymentEventStmt:                              q.addPaymentEventStmt,
+		addTransactionReferenceStmt:                      q.addTransactionReferenceStmt,
+		advanceSubscriptionStmt:                          q.advanceSubscriptionStmt,
+		anyTransactionReferenceExistsStmt:                q.anyTransactionReferenceExistsStmt,
+		cancelSubscriptionStmt:                           q.cancelSubscriptionStmt,
+		completeReportJobStmt:                            q.completeReportJobStmt,
+		createAPIKeyStmt:                                 q.createAPIKeyStmt,
+		createDepositStmt:                                q.createDepositStmt,
+		createDepositAddressStmt:                         q.createDepositAddressStmt,
+		createLinkTokenStmt:                              q.createLinkTokenStmt,
+		createMonthlyPartitionsStmt:                      q.createMonthlyPartitionsStmt,
+		createPaymentStmt:                                q.createPaymentStmt,
+		createPaymentDisputeStmt:                         q.createPaymentDisputeStmt,
+		createReportJobStmt:                              q.createReportJobStmt,
+		createSettlementStmt:                             q.createSettlementStmt,
+		createSubscriptionStmt:                           q.createSubscriptionStmt,
+		createSubscriptionInstallmentStmt:                q.createSubscriptionInstallmentStmt,
+		createSubscriptionPlanStmt:                       q.createSubscriptionPlanStmt,
+		createTransactionStmt:                            q.createTransactionStmt,
+		createWebhookDestinationStmt:                     q.createWebhookDestinationStmt,
+		deleteAuditRecordsCreatedBeforeStmt:              q.deleteAuditRecordsCreatedBeforeStmt,
+		deleteCredentialRoleStmt:                         q.deleteCredentialRoleStmt,
+		deleteExpiredLinkTokensStmt:                      q.deleteExpiredLinkTokensStmt,
+		deleteExpiredTokensStmt:                          q.deleteExpiredTokensStmt,
+		deleteOutboxEventsPublishedBeforeStmt:            q.deleteOutboxEventsPublishedBeforeStmt,
+		deleteTokenStmt:                                  q.deleteTokenStmt,
+		deleteTokensByCredentialStmt:                     q.deleteTokensByCredentialStmt,
+		deleteWebhookDestinationStmt:                     q.deleteWebhookDestinationStmt,
+		depositExistsStmt:                                q.depositExistsStmt,
+		expireOtherPendingTransactionsStmt:               q.expireOtherPendingTransactionsStmt,
+		failReportJobStmt:                                q.failReportJobStmt,
+		getAPIKeyStmt:                                    q.getAPIKeyStmt,
+		getAPIKeysStmt:                                   q.getAPIKeysStmt,
+		getActiveAPIKeyByHashStmt:                        q.getActiveAPIKeyByHashStmt,
+		getAuditRecordsStmt:                              q.getAuditRecordsStmt,
+		getBonusReportStmt:                               q.getBonusReportStmt,
+		getCredentialRoleStmt:                            q.getCredentialRoleStmt,
+		getCredentialRolesStmt:                           q.getCredentialRolesStmt,
+		getDepositAddressByPaymentIDStmt:                 q.getDepositAddressByPaymentIDStmt,
+		getDepositAddressesToSweepStmt:                   q.getDepositAddressesToSweepStmt,
+		getDueSubscriptionsStmt:                          q.getDueSubscriptionsStmt,
+		getFunnelReportStmt:                              q.getFunnelReportStmt,
+		getLinkTokenStmt:                                 q.getLinkTokenStmt,
+		getMerchantSettingsStmt:                          q.getMerchantSettingsStmt,
+		getMintDecimalsStmt:                              q.getMintDecimalsStmt,
+		getOpenPaymentDisputeStmt:                        q.getOpenPaymentDisputeStmt,
+		getPaymentStmt:                                   q.getPaymentStmt,
+		getPaymentByExternalIDStmt:                       q.getPaymentByExternalIDStmt,
+		getPaymentDisputesStmt:                           q.getPaymentDisputesStmt,
+		getPaymentEventsStmt:                             q.getPaymentEventsStmt,
+		getPaymentHistoryExportStmt:                      q.getPaymentHistoryExportStmt,
+		getPaymentStatusStmt:                             q.getPaymentStatusStmt,
+		getPaymentsToReleaseStmt:                         q.getPaymentsToReleaseStmt,
+		getPendingOutboxEventsStmt:                       q.getPendingOutboxEventsStmt,
+		getPendingTransactionReferencesStmt:              q.getPendingTransactionReferencesStmt,
+		getPendingTransactionsStmt:                       q.getPendingTransactionsStmt,
+		getReportJobStmt:                                 q.getReportJobStmt,
+		getRevenueReportStmt:                             q.getRevenueReportStmt,
+		getSettlementByPaymentIDStmt:                     q.getSettlementByPaymentIDStmt,
+		getSettlementExportStmt:                          q.getSettlementExportStmt,
+		getSubscriptionStmt:                              q.getSubscriptionStmt,
+		getSubscriptionInstallmentByDueAtStmt:            q.getSubscriptionInstallmentByDueAtStmt,
+		getSubscriptionInstallmentByPaymentIDStmt:        q.getSubscriptionInstallmentByPaymentIDStmt,
+		getSubscriptionInstallmentsStmt:                  q.getSubscriptionInstallmentsStmt,
+		getSubscriptionPlanStmt:                          q.getSubscriptionPlanStmt,
+		getSubscriptionPlansStmt:                         q.getSubscriptionPlansStmt,
+		getTokenStmt:                                     q.getTokenStmt,
+		getTransactionStmt:                               q.getTransactionStmt,
 		getTransactionByPaymentIDSourceWalletAndMintStmt: q.getTransactionByPaymentIDSourceWalletAndMintStmt,
 		getTransactionByReferenceStmt:                    q.getTransactionByReferenceStmt,
+		getTransactionReferencesStmt:                     q.getTransactionReferencesStmt,
 		getTransactionsByPaymentIDStmt:                   q.getTransactionsByPaymentIDStmt,
+		getTransactionsByStatusStmt:                      q.getTransactionsByStatusStmt,
+		getTransactionsByStatusCreatedBeforeStmt:         q.getTransactionsByStatusCreatedBeforeStmt,
+		getTransactionsByStatusCreatedBetweenStmt:        q.getTransactionsByStatusCreatedBetweenStmt,
+		getWebhookDestinationStmt:                        q.getWebhookDestinationStmt,
+		getWebhookDestinationsStmt:                       q.getWebhookDestinationsStmt,
+		holdPaymentStmt:                                  q.holdPaymentStmt,
+		markDepositAddressSweptStmt:                      q.markDepositAddressSweptStmt,
+		markOutboxEventPublishedStmt:                     q.markOutboxEventPublishedStmt,
 		markPaymentsExpiredStmt:                          q.markPaymentsExpiredStmt,
 		markTransactionsAsExpiredStmt:                    q.markTransactionsAsExpiredStmt,
+		refreshPaymentAmountPaidStmt:                     q.refreshPaymentAmountPaidStmt,
+		releasePaymentStmt:                               q.releasePaymentStmt,
+		resolvePaymentDisputeStmt:                        q.resolvePaymentDisputeStmt,
+		revokeAPIKeyStmt:                                 q.revokeAPIKeyStmt,
+		rotateAPIKeyStmt:                                 q.rotateAPIKeyStmt,
+		setSubscriptionInstallmentPaymentStmt:            q.setSubscriptionInstallmentPaymentStmt,
+		startReportJobStmt:                               q.startReportJobStmt,
+		storeCredentialRoleStmt:                          q.storeCredentialRoleStmt,
+		storeMerchantSettingsStmt:                        q.storeMerchantSettingsStmt,
+		storeMintDecimalsStmt:                            q.storeMintDecimalsStmt,
 		storeTokenStmt:                                   q.storeTokenStmt,
+		trackFunnelStageStmt:                             q.trackFunnelStageStmt,
+		updatePaymentAmountStmt:                          q.updatePaymentAmountStmt,
 		updatePaymentStatusStmt:                          q.updatePaymentStatusStmt,
+		updateSettlementStmt:                             q.updateSettlementStmt,
+		updateSubscriptionInstallmentStatusStmt:          q.updateSubscriptionInstallmentStatusStmt,
 		updateTransactionByReferenceStmt:                 q.updateTransactionByReferenceStmt,
+		updateWebhookDestinationStmt:                     q.updateWebhookDestinationStmt,
+		useLinkTokenStmt:                                 q.useLinkTokenStmt,
 	}
 }
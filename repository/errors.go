@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// ErrUnknownBackend is returned by Open if the storage backend is not registered.
+var ErrUnknownBackend = errors.New("unknown storage backend")
+
+// PaymentsExternalIDIndex is the unique index of the payment external ids.
+const PaymentsExternalIDIndex = "payments_external_id"
+
+// uniqueViolation is the PostgreSQL error code of the unique constraint violations.
+const uniqueViolation = "23505"
+
+// UniqueViolationError is returned by the alternative storage backends
+// if the query violates a unique index or constraint.
+type UniqueViolationError struct {
+	Constraint string // name of the violated unique index or constraint
+	Err        error  // original driver error
+}
+
+// Error returns the error message with the constraint name.
+func (e *UniqueViolationError) Error() string {
+	return fmt.Sprintf("unique constraint %s violated: %v", e.Constraint, e.Err)
+}
+
+// Unwrap returns the original driver error.
+func (e *UniqueViolationError) Unwrap() error {
+	return e.Err
+}
+
+// IsUniqueViolation returns true if the error is a violation of the given unique index or constraint.
+// Any unique violation matches if the constraint name is empty.
+func IsUniqueViolation(err error, constraint string) bool {
+	var uvErr *UniqueViolationError
+	if errors.As(err, &uvErr) {
+		return constraint == "" || uvErr.Constraint == constraint
+	}
+
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != uniqueViolation {
+		return false
+	}
+	return constraint == "" || pqErr.Constraint == constraint
+}
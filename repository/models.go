@@ -7,6 +7,7 @@ package repository
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -22,6 +23,11 @@ const (
 	PaymentStatusFailed    PaymentStatus = "failed"
 	PaymentStatusCanceled  PaymentStatus = "canceled"
 	PaymentStatusExpired   PaymentStatus = "expired"
+	PaymentStatusHeld      PaymentStatus = "held"
+	PaymentStatusReleased  PaymentStatus = "released"
+	PaymentStatusDisputed  PaymentStatus = "disputed"
+
+	PaymentStatusPartiallyPaid PaymentStatus = "partially_paid"
 )
 
 func (e *PaymentStatus) Scan(src interface{}) error {
@@ -103,17 +109,204 @@ func (ns NullTransactionStatus) Value() (driver.Value, error) {
 	return ns.TransactionStatus, nil
 }
 
+type APIKey struct {
+	ID                   uuid.UUID      `json:"id"`
+	Name                 string         `json:"name"`
+	KeyPrefix            string         `json:"key_prefix"`
+	KeyHash              string         `json:"key_hash"`
+	PreviousKeyHash      sql.NullString `json:"previous_key_hash"`
+	PreviousKeyExpiresAt sql.NullTime   `json:"previous_key_expires_at"`
+	Role                 string         `json:"role"`
+	Livemode             bool           `json:"livemode"`
+	CreatedAt            time.Time      `json:"created_at"`
+	RotatedAt            sql.NullTime   `json:"rotated_at"`
+	RevokedAt            sql.NullTime   `json:"revoked_at"`
+	Scopes               string         `json:"scopes"`
+}
+
+type AuditRecord struct {
+	ID          int64     `json:"id"`
+	ClientID    string    `json:"client_id"`
+	Method      string    `json:"method"`
+	Route       string    `json:"route"`
+	Path        string    `json:"path"`
+	PayloadHash string    `json:"payload_hash"`
+	StatusCode  int32     `json:"status_code"`
+	LatencyUs   int64     `json:"latency_us"`
+	RequestID   string    `json:"request_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type CredentialRole struct {
+	Credential string    `json:"credential"`
+	Role       string    `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type Deposit struct {
+	ID           uuid.UUID      `json:"id"`
+	Wallet       string         `json:"wallet"`
+	Mint         string         `json:"mint"`
+	Amount       int64          `json:"amount"`
+	SourceWallet sql.NullString `json:"source_wallet"`
+	TxSignature  string         `json:"tx_signature"`
+	CreatedAt    time.Time      `json:"created_at"`
+}
+
+type EventOutbox struct {
+	ID          int64           `json:"id"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	PublishedAt sql.NullTime    `json:"published_at"`
+}
+
+type MerchantSetting struct {
+	ID                   int16     `json:"id"`
+	ApplyBonus           bool      `json:"apply_bonus"`
+	MaxApplyBonusAmount  int64     `json:"max_apply_bonus_amount"`
+	MaxApplyBonusPercent int32     `json:"max_apply_bonus_percent"`
+	AccrueBonusRate      int64     `json:"accrue_bonus_rate"`
+	DestinationMint      string    `json:"destination_mint"`
+	DestinationWallet    string    `json:"destination_wallet"`
+	EscrowReleaseAfter   int64     `json:"escrow_release_after"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+type Mint struct {
+	Address   string    `json:"address"`
+	Decimals  int16     `json:"decimals"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type Payment struct {
-	ID                uuid.UUID      `json:"id"`
-	ExternalID        sql.NullString `json:"external_id"`
-	DestinationWallet string         `json:"destination_wallet"`
-	DestinationMint   string         `json:"destination_mint"`
-	Amount            int64          `json:"amount"`
-	Status            PaymentStatus  `json:"status"`
-	Message           sql.NullString `json:"message"`
-	ExpiresAt         sql.NullTime   `json:"expires_at"`
-	CreatedAt         time.Time      `json:"created_at"`
-	UpdatedAt         sql.NullTime   `json:"updated_at"`
+	ID                uuid.UUID       `json:"id"`
+	ExternalID        sql.NullString  `json:"external_id"`
+	DestinationWallet string          `json:"destination_wallet"`
+	DestinationMint   string          `json:"destination_mint"`
+	Amount            int64           `json:"amount"`
+	Status            PaymentStatus   `json:"status"`
+	Message           sql.NullString  `json:"message"`
+	ExpiresAt         sql.NullTime    `json:"expires_at"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         sql.NullTime    `json:"updated_at"`
+	CustomerEmail     sql.NullString  `json:"customer_email"`
+	Translations      json.RawMessage `json:"translations"`
+	FeeOnTop          bool            `json:"fee_on_top"`
+	Escrow            bool            `json:"escrow"`
+	HeldUntil         sql.NullTime    `json:"held_until"`
+	Livemode          bool            `json:"livemode"`
+	CallbackUrl       sql.NullString  `json:"callback_url"`
+	AllowPartial      bool            `json:"allow_partial"`
+	AmountPaid        int64           `json:"amount_paid"`
+	FiatCurrency      sql.NullString  `json:"fiat_currency"`
+	FiatAmount        int64           `json:"fiat_amount"`
+	Settings          json.RawMessage `json:"settings"`
+}
+
+type PaymentDepositAddress struct {
+	PaymentID      uuid.UUID      `json:"payment_id"`
+	TransactionID  uuid.UUID      `json:"transaction_id"`
+	Wallet         string         `json:"wallet"`
+	TokenAccount   string         `json:"token_account"`
+	Mint           string         `json:"mint"`
+	Amount         int64          `json:"amount"`
+	SweepSignature sql.NullString `json:"sweep_signature"`
+	SweptAt        sql.NullTime   `json:"swept_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+type PaymentDispute struct {
+	ID             uuid.UUID       `json:"id"`
+	PaymentID      uuid.UUID       `json:"payment_id"`
+	Reason         string          `json:"reason"`
+	EvidenceUrls   json.RawMessage `json:"evidence_urls"`
+	PreviousStatus PaymentStatus   `json:"previous_status"`
+	Resolution     sql.NullString  `json:"resolution"`
+	ResolvedAt     sql.NullTime    `json:"resolved_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type PaymentEvent struct {
+	ID        int64           `json:"id"`
+	PaymentID uuid.UUID       `json:"payment_id"`
+	Event     string          `json:"event"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type PaymentFunnelEvent struct {
+	PaymentID uuid.UUID `json:"payment_id"`
+	Stage     string    `json:"stage"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PaymentLinkToken struct {
+	TokenHash string       `json:"token_hash"`
+	PaymentID uuid.UUID    `json:"payment_id"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	UsedAt    sql.NullTime `json:"used_at"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+type ReportJob struct {
+	ID          uuid.UUID      `json:"id"`
+	Kind        string         `json:"kind"`
+	Status      string         `json:"status"`
+	FromDate    time.Time      `json:"from_date"`
+	ToDate      time.Time      `json:"to_date"`
+	FileKey     sql.NullString `json:"file_key"`
+	RowCount    int64          `json:"row_count"`
+	Error       sql.NullString `json:"error"`
+	CreatedAt   time.Time      `json:"created_at"`
+	StartedAt   sql.NullTime   `json:"started_at"`
+	CompletedAt sql.NullTime   `json:"completed_at"`
+}
+
+type Settlement struct {
+	ID          uuid.UUID      `json:"id"`
+	PaymentID   uuid.UUID      `json:"payment_id"`
+	InputMint   string         `json:"input_mint"`
+	OutputMint  string         `json:"output_mint"`
+	InAmount    int64          `json:"in_amount"`
+	OutAmount   int64          `json:"out_amount"`
+	Status      string         `json:"status"`
+	TxSignature sql.NullString `json:"tx_signature"`
+	Error       sql.NullString `json:"error"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   sql.NullTime   `json:"updated_at"`
+}
+
+type Subscription struct {
+	ID            uuid.UUID      `json:"id"`
+	PlanID        uuid.UUID      `json:"plan_id"`
+	CustomerEmail sql.NullString `json:"customer_email"`
+	Status        string         `json:"status"`
+	Livemode      bool           `json:"livemode"`
+	NextBillingAt time.Time      `json:"next_billing_at"`
+	CreatedAt     time.Time      `json:"created_at"`
+	CanceledAt    sql.NullTime   `json:"canceled_at"`
+}
+
+type SubscriptionInstallment struct {
+	ID             uuid.UUID     `json:"id"`
+	SubscriptionID uuid.UUID     `json:"subscription_id"`
+	PaymentID      uuid.NullUUID `json:"payment_id"`
+	DueAt          time.Time     `json:"due_at"`
+	Status         string        `json:"status"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      sql.NullTime  `json:"updated_at"`
+}
+
+type SubscriptionPlan struct {
+	ID              uuid.UUID `json:"id"`
+	Name            string    `json:"name"`
+	Amount          int64     `json:"amount"`
+	Currency        string    `json:"currency"`
+	BillingInterval string    `json:"billing_interval"`
+	IntervalCount   int32     `json:"interval_count"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 type Token struct {
@@ -146,4 +339,27 @@ type Transaction struct {
 	Status             TransactionStatus `json:"status"`
 	CreatedAt          time.Time         `json:"created_at"`
 	UpdatedAt          sql.NullTime      `json:"updated_at"`
+	NetworkFee         int64             `json:"network_fee"`
+	PriorityFee        int64             `json:"priority_fee"`
+	SlippageFee        int64             `json:"slippage_fee"`
+	VoucherAmount      int64             `json:"voucher_amount"`
+	SwapRoute          json.RawMessage   `json:"swap_route"`
+	ExchangeRate       sql.NullString    `json:"exchange_rate"`
+	TipAmount          int64             `json:"tip_amount"`
+	TipDestination     sql.NullString    `json:"tip_destination"`
+}
+
+type TransactionReference struct {
+	Reference     string    `json:"reference"`
+	TransactionID uuid.UUID `json:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type WebhookDestination struct {
+	ID          uuid.UUID    `json:"id"`
+	URL         string       `json:"url"`
+	Events      string       `json:"events"`
+	Description string       `json:"description"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   sql.NullTime `json:"updated_at"`
 }
@@ -0,0 +1,125 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const apiKeyColumns = `id, name, key_prefix, key_hash, previous_key_hash, previous_key_expires_at, role, livemode, created_at, rotated_at, revoked_at, scopes`
+
+func scanAPIKey(row scanner) (repository.APIKey, error) {
+	var i repository.APIKey
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.KeyPrefix,
+		&i.KeyHash,
+		&i.PreviousKeyHash,
+		&i.PreviousKeyExpiresAt,
+		&i.Role,
+		&i.Livemode,
+		&i.CreatedAt,
+		&i.RotatedAt,
+		&i.RevokedAt,
+		&i.Scopes,
+	)
+	return i, err
+}
+
+const createAPIKey = `-- name: CreateAPIKey :exec
+INSERT INTO api_keys (id, name, key_prefix, key_hash, role, livemode, scopes)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg repository.CreateAPIKeyParams) (repository.APIKey, error) {
+	id := uuid.New()
+	if _, err := q.db.ExecContext(ctx, createAPIKey, id, arg.Name, arg.KeyPrefix, arg.KeyHash, arg.Role, arg.Livemode, arg.Scopes); err != nil {
+		return repository.APIKey{}, uniqueViolation(err)
+	}
+	return q.GetAPIKey(ctx, id)
+}
+
+const getAPIKey = `-- name: GetAPIKey :one
+SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = ?
+`
+
+func (q *Queries) GetAPIKey(ctx context.Context, id uuid.UUID) (repository.APIKey, error) {
+	return scanAPIKey(q.db.QueryRowContext(ctx, getAPIKey, id))
+}
+
+const getAPIKeys = `-- name: GetAPIKeys :many
+SELECT ` + apiKeyColumns + ` FROM api_keys ORDER BY created_at DESC
+`
+
+func (q *Queries) GetAPIKeys(ctx context.Context) ([]repository.APIKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.APIKey
+	for rows.Next() {
+		i, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveAPIKeyByHash = `-- name: GetActiveAPIKeyByHash :one
+SELECT ` + apiKeyColumns + ` FROM api_keys
+WHERE revoked_at IS NULL
+    AND (key_hash = ? OR (previous_key_hash = ? AND previous_key_expires_at > CURRENT_TIMESTAMP(6)))
+`
+
+func (q *Queries) GetActiveAPIKeyByHash(ctx context.Context, keyHash string) (repository.APIKey, error) {
+	// the hash is bound twice: for the current and for the previous key
+	return scanAPIKey(q.db.QueryRowContext(ctx, getActiveAPIKeyByHash, keyHash, keyHash))
+}
+
+const rotateAPIKey = `-- name: RotateAPIKey :execrows
+UPDATE api_keys SET
+    previous_key_hash = key_hash,
+    previous_key_expires_at = ?,
+    key_hash = ?,
+    key_prefix = ?,
+    rotated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND revoked_at IS NULL
+`
+
+func (q *Queries) RotateAPIKey(ctx context.Context, arg repository.RotateAPIKeyParams) (repository.APIKey, error) {
+	// the assignments are applied from left to right, so the previous hash is the one before the update
+	result, err := q.db.ExecContext(ctx, rotateAPIKey, arg.PreviousKeyExpiresAt, arg.KeyHash, arg.KeyPrefix, arg.ID)
+	if err != nil {
+		return repository.APIKey{}, uniqueViolation(err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return repository.APIKey{}, err
+	} else if n == 0 {
+		return repository.APIKey{}, sql.ErrNoRows
+	}
+	return q.GetAPIKey(ctx, arg.ID)
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP(6) WHERE id = ? AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeAPIKey, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
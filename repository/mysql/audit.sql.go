@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const addAuditRecord = `-- name: AddAuditRecord :exec
+INSERT INTO audit_records (client_id, method, route, path, payload_hash, status_code, latency_us, request_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+func (q *Queries) AddAuditRecord(ctx context.Context, arg repository.AddAuditRecordParams) error {
+	_, err := q.db.ExecContext(ctx, addAuditRecord,
+		arg.ClientID,
+		arg.Method,
+		arg.Route,
+		arg.Path,
+		arg.PayloadHash,
+		arg.StatusCode,
+		arg.LatencyUs,
+		arg.RequestID,
+	)
+	return err
+}
+
+const deleteAuditRecordsCreatedBefore = `-- name: DeleteAuditRecordsCreatedBefore :execrows
+DELETE FROM audit_records WHERE created_at < ?
+`
+
+func (q *Queries) DeleteAuditRecordsCreatedBefore(ctx context.Context, createdBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAuditRecordsCreatedBefore, createdBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAuditRecords = `-- name: GetAuditRecords :many
+SELECT id, client_id, method, route, path, payload_hash, status_code, latency_us, request_id, created_at FROM audit_records
+WHERE (? = '' OR client_id = ?)
+    AND created_at >= ? AND created_at < ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`
+
+func (q *Queries) GetAuditRecords(ctx context.Context, arg repository.GetAuditRecordsParams) ([]repository.AuditRecord, error) {
+	rows, err := q.db.QueryContext(ctx, getAuditRecords,
+		arg.ClientID,
+		arg.ClientID,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.AuditRecord
+	for rows.Next() {
+		var i repository.AuditRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.ClientID,
+			&i.Method,
+			&i.Route,
+			&i.Path,
+			&i.PayloadHash,
+			&i.StatusCode,
+			&i.LatencyUs,
+			&i.RequestID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
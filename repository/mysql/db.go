@@ -0,0 +1,65 @@
+// Package mysql implements the repository storage backend on MySQL 8.0.
+//
+// The schema and the queries are kept in sql/migrations and sql/query,
+// in the same layout as the PostgreSQL ones. The queries are mapped onto
+// the repository models by hand, since repository.Storage is defined in terms of them:
+// MySQL has no RETURNING clause, so the inserts and updates read the rows back by the key,
+// and the conditional updates lock the row in a transaction first.
+//
+// The backend is registered as "mysql" on import. The connection string must enable
+// the time parsing in UTC, e.g. "user:pass@tcp(127.0.0.1:3306)/checkout?parseTime=true&loc=UTC".
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/repository"
+	_ "github.com/go-sql-driver/mysql" // init mysql driver
+)
+
+// Backend is the name of the MySQL storage backend and its database/sql driver.
+const Backend = "mysql"
+
+func init() {
+	repository.RegisterBackend(Backend, Open)
+}
+
+type (
+	// Queries implements repository.Storage on MySQL.
+	Queries struct {
+		db repository.DBTX
+	}
+
+	// scanner is implemented by sql.Row and sql.Rows.
+	scanner interface {
+		Scan(dest ...interface{}) error
+	}
+)
+
+var _ repository.Storage = (*Queries)(nil)
+
+// New creates the queries on top of the given connection pool or transaction.
+func New(db repository.DBTX) *Queries {
+	return &Queries{db: db}
+}
+
+// Open opens the MySQL storage on top of the given connection pool.
+func Open(ctx context.Context, db *sql.DB) (repository.Storage, error) {
+	return New(db), nil
+}
+
+// Close implements repository.Storage; the queries aren't prepared, so there is nothing to release.
+func (q *Queries) Close() error {
+	return nil
+}
+
+// expandSlice replaces the slice placeholder of the query with the placeholders of its values.
+func expandSlice(query, name string, n int) string {
+	placeholders := "NULL" // IN (NULL) matches nothing
+	if n > 0 {
+		placeholders = strings.TrimSuffix(strings.Repeat("?,", n), ",")
+	}
+	return strings.ReplaceAll(query, "/*SLICE:"+name+"*/?", placeholders)
+}
@@ -0,0 +1,52 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+// WithTx returns the queries bound to the given transaction.
+func (q *Queries) WithTx(tx *sql.Tx) *Queries {
+	return &Queries{db: tx}
+}
+
+// RunInTx runs fn with the queries bound to a new transaction of the connection pool.
+// The transaction is committed if fn succeeds, and rolled back otherwise.
+func RunInTx(ctx context.Context, db *sql.DB, fn func(q *Queries) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(New(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w; rollback failed: %v", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// InTx implements repository.Storage.
+func (q *Queries) InTx(ctx context.Context, fn func(q repository.Querier) error) error {
+	return q.inTx(ctx, func(q *Queries) error {
+		return fn(q)
+	})
+}
+
+// inTx runs fn in a new transaction if the queries are bound to the connection pool,
+// or with the same queries if they're already bound to a transaction.
+func (q *Queries) inTx(ctx context.Context, fn func(q *Queries) error) error {
+	if db, ok := q.db.(*sql.DB); ok {
+		return RunInTx(ctx, db, fn)
+	}
+	return fn(q)
+}
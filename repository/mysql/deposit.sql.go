@@ -0,0 +1,59 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const depositColumns = `id, wallet, mint, amount, source_wallet, tx_signature, created_at`
+
+func scanDeposit(row scanner) (repository.Deposit, error) {
+	var i repository.Deposit
+	err := row.Scan(
+		&i.ID,
+		&i.Wallet,
+		&i.Mint,
+		&i.Amount,
+		&i.SourceWallet,
+		&i.TxSignature,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createDeposit = `-- name: CreateDeposit :exec
+INSERT INTO deposits (id, wallet, mint, amount, source_wallet, tx_signature)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+func (q *Queries) CreateDeposit(ctx context.Context, arg repository.CreateDepositParams) (repository.Deposit, error) {
+	id := uuid.New()
+	if _, err := q.db.ExecContext(ctx, createDeposit,
+		id,
+		arg.Wallet,
+		arg.Mint,
+		arg.Amount,
+		arg.SourceWallet,
+		arg.TxSignature,
+	); err != nil {
+		return repository.Deposit{}, uniqueViolation(err)
+	}
+	return scanDeposit(q.db.QueryRowContext(ctx, getDeposit, id))
+}
+
+const getDeposit = `-- name: GetDeposit :one
+SELECT ` + depositColumns + ` FROM deposits WHERE id = ?
+`
+
+const depositExists = `-- name: DepositExists :one
+SELECT EXISTS(SELECT 1 FROM deposits WHERE tx_signature = ? AND mint = ?)
+`
+
+func (q *Queries) DepositExists(ctx context.Context, arg repository.DepositExistsParams) (bool, error) {
+	row := q.db.QueryRowContext(ctx, depositExists, arg.TxSignature, arg.Mint)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
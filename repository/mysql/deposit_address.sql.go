@@ -0,0 +1,103 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const depositAddressColumns = `payment_id, transaction_id, wallet, token_account, mint, amount, sweep_signature, swept_at, created_at`
+
+func scanDepositAddress(row scanner) (repository.PaymentDepositAddress, error) {
+	var i repository.PaymentDepositAddress
+	err := row.Scan(
+		&i.PaymentID,
+		&i.TransactionID,
+		&i.Wallet,
+		&i.TokenAccount,
+		&i.Mint,
+		&i.Amount,
+		&i.SweepSignature,
+		&i.SweptAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createDepositAddress = `-- name: CreateDepositAddress :exec
+INSERT INTO payment_deposit_addresses (payment_id, transaction_id, wallet, token_account, mint, amount)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+func (q *Queries) CreateDepositAddress(ctx context.Context, arg repository.CreateDepositAddressParams) (repository.PaymentDepositAddress, error) {
+	if _, err := q.db.ExecContext(ctx, createDepositAddress,
+		arg.PaymentID,
+		arg.TransactionID,
+		arg.Wallet,
+		arg.TokenAccount,
+		arg.Mint,
+		arg.Amount,
+	); err != nil {
+		return repository.PaymentDepositAddress{}, uniqueViolation(err)
+	}
+	return q.GetDepositAddressByPaymentID(ctx, arg.PaymentID)
+}
+
+const getDepositAddressByPaymentID = `-- name: GetDepositAddressByPaymentID :one
+SELECT ` + depositAddressColumns + ` FROM payment_deposit_addresses WHERE payment_id = ?
+`
+
+func (q *Queries) GetDepositAddressByPaymentID(ctx context.Context, paymentID uuid.UUID) (repository.PaymentDepositAddress, error) {
+	return scanDepositAddress(q.db.QueryRowContext(ctx, getDepositAddressByPaymentID, paymentID))
+}
+
+const getDepositAddressesToSweep = `-- name: GetDepositAddressesToSweep :many
+SELECT d.payment_id, d.transaction_id, d.wallet, d.token_account, d.mint, d.amount, d.sweep_signature, d.swept_at, d.created_at FROM payment_deposit_addresses d
+JOIN transactions t ON t.id = d.transaction_id
+WHERE d.swept_at IS NULL AND t.status = 'completed'
+ORDER BY d.created_at
+`
+
+func (q *Queries) GetDepositAddressesToSweep(ctx context.Context) ([]repository.PaymentDepositAddress, error) {
+	rows, err := q.db.QueryContext(ctx, getDepositAddressesToSweep)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.PaymentDepositAddress
+	for rows.Next() {
+		i, err := scanDepositAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markDepositAddressSwept = `-- name: MarkDepositAddressSwept :execrows
+UPDATE payment_deposit_addresses SET sweep_signature = ?, swept_at = CURRENT_TIMESTAMP(6)
+WHERE payment_id = ? AND swept_at IS NULL
+`
+
+// MarkDepositAddressSwept stores the sweep signature. Returns sql.ErrNoRows if the address is already swept.
+func (q *Queries) MarkDepositAddressSwept(ctx context.Context, arg repository.MarkDepositAddressSweptParams) (repository.PaymentDepositAddress, error) {
+	result, err := q.db.ExecContext(ctx, markDepositAddressSwept, arg.SweepSignature, arg.PaymentID)
+	if err != nil {
+		return repository.PaymentDepositAddress{}, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return repository.PaymentDepositAddress{}, err
+	} else if n == 0 {
+		return repository.PaymentDepositAddress{}, sql.ErrNoRows
+	}
+	return q.GetDepositAddressByPaymentID(ctx, arg.PaymentID)
+}
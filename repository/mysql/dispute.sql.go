@@ -0,0 +1,150 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// openPaymentDisputeIndex is the name of the PostgreSQL partial index
+// which allows a single open dispute per payment.
+const openPaymentDisputeIndex = "payment_disputes_open_idx"
+
+const paymentDisputeColumns = `id, payment_id, reason, evidence_urls, previous_status, resolution, resolved_at, created_at`
+
+func scanPaymentDispute(row scanner) (repository.PaymentDispute, error) {
+	var i repository.PaymentDispute
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentID,
+		&i.Reason,
+		&i.EvidenceUrls,
+		&i.PreviousStatus,
+		&i.Resolution,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createPaymentDispute = `-- name: CreatePaymentDispute :exec
+INSERT INTO payment_disputes (id, payment_id, reason, evidence_urls, previous_status)
+VALUES (?, ?, ?, ?, ?)
+`
+
+const lockPayment = `-- name: LockPayment :one
+SELECT id FROM payments WHERE id = ? FOR UPDATE
+`
+
+// CreatePaymentDispute opens a dispute for the payment.
+// Returns repository.UniqueViolationError if the payment already has an open dispute.
+func (q *Queries) CreatePaymentDispute(ctx context.Context, arg repository.CreatePaymentDisputeParams) (repository.PaymentDispute, error) {
+	evidenceUrls := arg.EvidenceUrls
+	if len(evidenceUrls) == 0 {
+		evidenceUrls = []byte("[]")
+	}
+
+	var result repository.PaymentDispute
+	err := q.inTx(ctx, func(q *Queries) error {
+		var locked uuid.UUID
+		if err := q.db.QueryRowContext(ctx, lockPayment, arg.PaymentID).Scan(&locked); err != nil {
+			return err
+		}
+
+		open, err := q.GetOpenPaymentDispute(ctx, arg.PaymentID)
+		if err == nil {
+			return &repository.UniqueViolationError{
+				Constraint: openPaymentDisputeIndex,
+				Err:        fmt.Errorf("payment %s already has open dispute %s", arg.PaymentID, open.ID),
+			}
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		id := uuid.New()
+		if _, err := q.db.ExecContext(ctx, createPaymentDispute,
+			id,
+			arg.PaymentID,
+			arg.Reason,
+			evidenceUrls,
+			arg.PreviousStatus,
+		); err != nil {
+			return uniqueViolation(err)
+		}
+
+		result, err = scanPaymentDispute(q.db.QueryRowContext(ctx, getPaymentDispute, id))
+		return err
+	})
+	return result, err
+}
+
+const getPaymentDispute = `-- name: GetPaymentDispute :one
+SELECT ` + paymentDisputeColumns + ` FROM payment_disputes WHERE id = ?
+`
+
+const getOpenPaymentDispute = `-- name: GetOpenPaymentDispute :one
+SELECT ` + paymentDisputeColumns + ` FROM payment_disputes WHERE payment_id = ? AND resolved_at IS NULL
+`
+
+func (q *Queries) GetOpenPaymentDispute(ctx context.Context, paymentID uuid.UUID) (repository.PaymentDispute, error) {
+	return scanPaymentDispute(q.db.QueryRowContext(ctx, getOpenPaymentDispute, paymentID))
+}
+
+const getPaymentDisputes = `-- name: GetPaymentDisputes :many
+SELECT ` + paymentDisputeColumns + ` FROM payment_disputes WHERE payment_id = ? ORDER BY created_at DESC
+`
+
+func (q *Queries) GetPaymentDisputes(ctx context.Context, paymentID uuid.UUID) ([]repository.PaymentDispute, error) {
+	rows, err := q.db.QueryContext(ctx, getPaymentDisputes, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.PaymentDispute
+	for rows.Next() {
+		i, err := scanPaymentDispute(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockOpenPaymentDispute = `-- name: LockOpenPaymentDispute :one
+SELECT id FROM payment_disputes WHERE id = ? AND resolved_at IS NULL FOR UPDATE
+`
+
+const resolvePaymentDispute = `-- name: ResolvePaymentDispute :exec
+UPDATE payment_disputes SET resolution = ?, resolved_at = CURRENT_TIMESTAMP(6) WHERE id = ?
+`
+
+// ResolvePaymentDispute resolves the open dispute. Returns sql.ErrNoRows if the dispute is already resolved.
+func (q *Queries) ResolvePaymentDispute(ctx context.Context, arg repository.ResolvePaymentDisputeParams) (repository.PaymentDispute, error) {
+	var result repository.PaymentDispute
+	err := q.inTx(ctx, func(q *Queries) error {
+		var locked uuid.UUID
+		if err := q.db.QueryRowContext(ctx, lockOpenPaymentDispute, arg.ID).Scan(&locked); err != nil {
+			return err
+		}
+		if _, err := q.db.ExecContext(ctx, resolvePaymentDispute, arg.Resolution, arg.ID); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = scanPaymentDispute(q.db.QueryRowContext(ctx, getPaymentDispute, arg.ID))
+		return err
+	})
+	return result, err
+}
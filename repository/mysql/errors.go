@@ -0,0 +1,44 @@
+package mysql
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/repository"
+	driver "github.com/go-sql-driver/mysql"
+)
+
+// errDuplicateEntry is the MySQL error number of the unique index violations.
+const errDuplicateEntry = 1062
+
+// uniqueViolation converts the duplicate entry error of the driver to repository.UniqueViolationError,
+// so the violations are recognized by repository.IsUniqueViolation. Other errors are returned as is.
+func uniqueViolation(err error) error {
+	var myErr *driver.MySQLError
+	if !errors.As(err, &myErr) || myErr.Number != errDuplicateEntry {
+		return err
+	}
+
+	return &repository.UniqueViolationError{
+		Constraint: duplicateKey(myErr.Message),
+		Err:        err,
+	}
+}
+
+// duplicateKey returns the index name of the duplicate entry error message,
+// e.g. "Duplicate entry 'order-1' for key 'payments.payments_external_id'".
+// MySQL 8.0.19+ prefixes the index name with the table name.
+func duplicateKey(msg string) string {
+	const prefix = "for key '"
+	i := strings.LastIndex(msg, prefix)
+	if i < 0 {
+		return ""
+	}
+
+	key := strings.TrimSuffix(msg[i+len(prefix):], "'")
+	if j := strings.LastIndex(key, "."); j >= 0 {
+		key = key[j+1:]
+	}
+
+	return key
+}
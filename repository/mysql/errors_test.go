@@ -0,0 +1,46 @@
+package mysql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/repository"
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueViolation(t *testing.T) {
+	t.Run("duplicate entry with table prefix", func(t *testing.T) {
+		err := uniqueViolation(fmt.Errorf("insert: %w", &driver.MySQLError{
+			Number:  errDuplicateEntry,
+			Message: "Duplicate entry 'order-1' for key 'payments.payments_external_id'",
+		}))
+		require.True(t, repository.IsUniqueViolation(err, repository.PaymentsExternalIDIndex))
+	})
+
+	t.Run("duplicate entry without table prefix", func(t *testing.T) {
+		err := uniqueViolation(&driver.MySQLError{
+			Number:  errDuplicateEntry,
+			Message: "Duplicate entry 'order-1' for key 'payments_external_id'",
+		})
+		require.True(t, repository.IsUniqueViolation(err, repository.PaymentsExternalIDIndex))
+		require.False(t, repository.IsUniqueViolation(err, "transactions_reference"))
+	})
+
+	t.Run("other errors are returned as is", func(t *testing.T) {
+		myErr := &driver.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}
+		require.Equal(t, error(myErr), uniqueViolation(myErr))
+		require.False(t, repository.IsUniqueViolation(uniqueViolation(myErr), ""))
+
+		plainErr := errors.New("connection refused")
+		require.Equal(t, plainErr, uniqueViolation(plainErr))
+		require.NoError(t, uniqueViolation(nil))
+	})
+}
+
+func TestExpandSlice(t *testing.T) {
+	query := "SELECT 1 WHERE a IN (/*SLICE:refs*/?) OR b IN (/*SLICE:refs*/?)"
+	require.Equal(t, "SELECT 1 WHERE a IN (?,?,?) OR b IN (?,?,?)", expandSlice(query, "refs", 3))
+	require.Equal(t, "SELECT 1 WHERE a IN (NULL) OR b IN (NULL)", expandSlice(query, "refs", 0))
+}
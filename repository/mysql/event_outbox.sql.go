@@ -0,0 +1,79 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const addOutboxEvent = `-- name: AddOutboxEvent :exec
+INSERT INTO event_outbox (event, payload)
+VALUES (?, ?)
+`
+
+func (q *Queries) AddOutboxEvent(ctx context.Context, arg repository.AddOutboxEventParams) error {
+	payload := arg.Payload
+	if len(payload) == 0 {
+		payload = []byte("{}")
+	}
+	_, err := q.db.ExecContext(ctx, addOutboxEvent, arg.Event, payload)
+	return err
+}
+
+const getPendingOutboxEvents = `-- name: GetPendingOutboxEvents :many
+SELECT id, event, payload, created_at, published_at FROM event_outbox WHERE published_at IS NULL
+ORDER BY id
+LIMIT ?
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) GetPendingOutboxEvents(ctx context.Context, batchSize int32) ([]repository.EventOutbox, error) {
+	rows, err := q.db.QueryContext(ctx, getPendingOutboxEvents, batchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.EventOutbox
+	for rows.Next() {
+		var i repository.EventOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.Event,
+			&i.Payload,
+			&i.CreatedAt,
+			&i.PublishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markOutboxEventPublished = `-- name: MarkOutboxEventPublished :exec
+UPDATE event_outbox SET published_at = CURRENT_TIMESTAMP(6) WHERE id = ?
+`
+
+func (q *Queries) MarkOutboxEventPublished(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markOutboxEventPublished, id)
+	return err
+}
+
+const deleteOutboxEventsPublishedBefore = `-- name: DeleteOutboxEventsPublishedBefore :execrows
+DELETE FROM event_outbox WHERE published_at < ?
+`
+
+func (q *Queries) DeleteOutboxEventsPublishedBefore(ctx context.Context, publishedBefore time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOutboxEventsPublishedBefore, publishedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
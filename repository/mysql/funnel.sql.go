@@ -0,0 +1,60 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const getFunnelReport = `-- name: GetFunnelReport :many
+SELECT p.destination_wallet, p.destination_mint, f.stage, COUNT(*) AS total
+FROM payment_funnel_events f
+JOIN payments p ON p.id = f.payment_id AND p.livemode
+WHERE p.created_at >= ? AND p.created_at < ?
+GROUP BY p.destination_wallet, p.destination_mint, f.stage
+ORDER BY p.destination_wallet, p.destination_mint, f.stage
+`
+
+func (q *Queries) GetFunnelReport(ctx context.Context, arg repository.GetFunnelReportParams) ([]repository.GetFunnelReportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFunnelReport, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.GetFunnelReportRow
+	for rows.Next() {
+		var i repository.GetFunnelReportRow
+		if err := rows.Scan(
+			&i.DestinationWallet,
+			&i.DestinationMint,
+			&i.Stage,
+			&i.Total,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trackFunnelStage = `-- name: TrackFunnelStage :execrows
+INSERT INTO payment_funnel_events (payment_id, stage)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE payment_id = payment_id
+`
+
+// TrackFunnelStage returns 0 if the stage is already tracked for the payment,
+// since the no-op update isn't counted as an affected row.
+func (q *Queries) TrackFunnelStage(ctx context.Context, arg repository.TrackFunnelStageParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, trackFunnelStage, arg.PaymentID, arg.Stage)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
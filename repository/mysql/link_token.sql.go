@@ -0,0 +1,58 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const createLinkToken = `-- name: CreateLinkToken :exec
+INSERT INTO payment_link_tokens (token_hash, payment_id, expires_at)
+VALUES (?, ?, ?)
+`
+
+func (q *Queries) CreateLinkToken(ctx context.Context, arg repository.CreateLinkTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createLinkToken, arg.TokenHash, arg.PaymentID, arg.ExpiresAt)
+	return uniqueViolation(err)
+}
+
+const deleteExpiredLinkTokens = `-- name: DeleteExpiredLinkTokens :execrows
+DELETE FROM payment_link_tokens WHERE expires_at < CURRENT_TIMESTAMP(6)
+`
+
+func (q *Queries) DeleteExpiredLinkTokens(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredLinkTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getLinkToken = `-- name: GetLinkToken :one
+SELECT token_hash, payment_id, expires_at, used_at, created_at FROM payment_link_tokens WHERE token_hash = ?
+`
+
+func (q *Queries) GetLinkToken(ctx context.Context, tokenHash string) (repository.PaymentLinkToken, error) {
+	var i repository.PaymentLinkToken
+	err := q.db.QueryRowContext(ctx, getLinkToken, tokenHash).Scan(
+		&i.TokenHash,
+		&i.PaymentID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useLinkToken = `-- name: UseLinkToken :execrows
+UPDATE payment_link_tokens SET used_at = CURRENT_TIMESTAMP(6)
+WHERE token_hash = ? AND payment_id = ? AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP(6)
+`
+
+func (q *Queries) UseLinkToken(ctx context.Context, arg repository.UseLinkTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useLinkToken, arg.TokenHash, arg.PaymentID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,28 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const getMintDecimals = `-- name: GetMintDecimals :one
+SELECT decimals FROM mints WHERE address = ?
+`
+
+func (q *Queries) GetMintDecimals(ctx context.Context, address string) (int16, error) {
+	row := q.db.QueryRowContext(ctx, getMintDecimals, address)
+	var decimals int16
+	err := row.Scan(&decimals)
+	return decimals, err
+}
+
+const storeMintDecimals = `-- name: StoreMintDecimals :exec
+INSERT INTO mints (address, decimals) VALUES (?, ?)
+ON DUPLICATE KEY UPDATE decimals = ?
+`
+
+func (q *Queries) StoreMintDecimals(ctx context.Context, arg repository.StoreMintDecimalsParams) error {
+	_, err := q.db.ExecContext(ctx, storeMintDecimals, arg.Address, arg.Decimals, arg.Decimals)
+	return err
+}
@@ -0,0 +1,13 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+// CreateMonthlyPartitions is a no-op, since the MySQL schema isn't partitioned:
+// InnoDB doesn't support foreign keys on the partitioned tables.
+func (q *Queries) CreateMonthlyPartitions(ctx context.Context, arg repository.CreateMonthlyPartitionsParams) (int32, error) {
+	return 0, nil
+}
@@ -0,0 +1,244 @@
+package mysql
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const paymentColumns = `id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings`
+
+func scanPayment(row scanner) (repository.Payment, error) {
+	var i repository.Payment
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.DestinationWallet,
+		&i.DestinationMint,
+		&i.Amount,
+		&i.Status,
+		&i.Message,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
+	)
+	return i, err
+}
+
+const createPayment = `-- name: CreatePayment :exec
+INSERT INTO payments (
+    id,
+    external_id,
+    destination_wallet,
+    destination_mint,
+    amount,
+    status,
+    message,
+    expires_at,
+    customer_email,
+    translations,
+    fee_on_top,
+    escrow,
+    livemode,
+    callback_url,
+    allow_partial,
+    fiat_currency,
+    fiat_amount,
+    settings
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+func (q *Queries) CreatePayment(ctx context.Context, arg repository.CreatePaymentParams) (repository.Payment, error) {
+	id := uuid.New()
+	translations := arg.Translations
+	if len(translations) == 0 {
+		translations = []byte("{}")
+	}
+	if _, err := q.db.ExecContext(ctx, createPayment,
+		id,
+		arg.ExternalID,
+		arg.DestinationWallet,
+		arg.DestinationMint,
+		arg.Amount,
+		arg.Status,
+		arg.Message,
+		arg.ExpiresAt,
+		arg.CustomerEmail,
+		translations,
+		arg.FeeOnTop,
+		arg.Escrow,
+		arg.Livemode,
+		arg.CallbackUrl,
+		arg.AllowPartial,
+		arg.FiatCurrency,
+		arg.FiatAmount,
+		arg.Settings,
+	); err != nil {
+		return repository.Payment{}, uniqueViolation(err)
+	}
+	return q.GetPayment(ctx, id)
+}
+
+const getPayment = `-- name: GetPayment :one
+SELECT ` + paymentColumns + ` FROM payments WHERE id = ?
+`
+
+func (q *Queries) GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	return scanPayment(q.db.QueryRowContext(ctx, getPayment, id))
+}
+
+const getPaymentByExternalID = `-- name: GetPaymentByExternalID :one
+SELECT ` + paymentColumns + ` FROM payments WHERE external_id = ?
+`
+
+func (q *Queries) GetPaymentByExternalID(ctx context.Context, externalID string) (repository.Payment, error) {
+	return scanPayment(q.db.QueryRowContext(ctx, getPaymentByExternalID, externalID))
+}
+
+const getPaymentStatus = `-- name: GetPaymentStatus :one
+SELECT p.status, COALESCE(p.updated_at, p.created_at) AS updated_at,
+    COALESCE((
+        SELECT t.tx_signature FROM transactions t
+        WHERE t.payment_id = p.id AND t.tx_signature IS NOT NULL AND t.tx_signature <> ''
+        ORDER BY t.updated_at IS NULL, t.updated_at DESC, t.created_at DESC
+        LIMIT 1
+    ), '') AS tx_signature
+FROM payments p WHERE p.id = ?
+`
+
+func (q *Queries) GetPaymentStatus(ctx context.Context, id uuid.UUID) (repository.GetPaymentStatusRow, error) {
+	row := q.db.QueryRowContext(ctx, getPaymentStatus, id)
+	var i repository.GetPaymentStatusRow
+	err := row.Scan(
+		&i.Status,
+		&i.UpdatedAt,
+		&i.TxSignature,
+	)
+	return i, err
+}
+
+const updatePaymentStatus = `-- name: UpdatePaymentStatus :exec
+UPDATE payments SET status = ? WHERE id = ?
+`
+
+func (q *Queries) UpdatePaymentStatus(ctx context.Context, arg repository.UpdatePaymentStatusParams) (repository.Payment, error) {
+	if _, err := q.db.ExecContext(ctx, updatePaymentStatus, arg.Status, arg.ID); err != nil {
+		return repository.Payment{}, err
+	}
+	return q.GetPayment(ctx, arg.ID)
+}
+
+const markPaymentsExpired = `-- name: MarkPaymentsExpired :exec
+UPDATE payments SET status = 'expired' WHERE expires_at < ? AND status = 'new'
+`
+
+func (q *Queries) MarkPaymentsExpired(ctx context.Context, expiredBefore time.Time) error {
+	_, err := q.db.ExecContext(ctx, markPaymentsExpired, expiredBefore)
+	return err
+}
+
+const holdPayment = `-- name: HoldPayment :exec
+UPDATE payments SET status = 'held', held_until = ? WHERE id = ?
+`
+
+func (q *Queries) HoldPayment(ctx context.Context, arg repository.HoldPaymentParams) (repository.Payment, error) {
+	if _, err := q.db.ExecContext(ctx, holdPayment, arg.HeldUntil, arg.ID); err != nil {
+		return repository.Payment{}, err
+	}
+	return q.GetPayment(ctx, arg.ID)
+}
+
+const refreshPaymentAmountPaid = `-- name: RefreshPaymentAmountPaid :exec
+UPDATE payments SET amount_paid = (
+    SELECT COALESCE(SUM(t.amount), 0) FROM transactions t
+    WHERE t.payment_id = ? AND t.status = 'completed'
+)
+WHERE id = ?
+`
+
+func (q *Queries) RefreshPaymentAmountPaid(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	if _, err := q.db.ExecContext(ctx, refreshPaymentAmountPaid, id, id); err != nil {
+		return repository.Payment{}, err
+	}
+	return q.GetPayment(ctx, id)
+}
+
+const lockHeldPayment = `-- name: LockHeldPayment :one
+SELECT id FROM payments WHERE id = ? AND status = 'held' FOR UPDATE
+`
+
+const releasePayment = `-- name: ReleasePayment :exec
+UPDATE payments SET status = 'released' WHERE id = ?
+`
+
+// ReleasePayment releases the held payment. Returns sql.ErrNoRows if the payment is not held.
+func (q *Queries) ReleasePayment(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	var result repository.Payment
+	err := q.inTx(ctx, func(q *Queries) error {
+		var locked uuid.UUID
+		if err := q.db.QueryRowContext(ctx, lockHeldPayment, id).Scan(&locked); err != nil {
+			return err
+		}
+		if _, err := q.db.ExecContext(ctx, releasePayment, id); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = q.GetPayment(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+const getPaymentsToRelease = `-- name: GetPaymentsToRelease :many
+SELECT ` + paymentColumns + ` FROM payments WHERE status = 'held' AND held_until < CURRENT_TIMESTAMP(6) ORDER BY held_until
+`
+
+func (q *Queries) GetPaymentsToRelease(ctx context.Context) ([]repository.Payment, error) {
+	rows, err := q.db.QueryContext(ctx, getPaymentsToRelease)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.Payment
+	for rows.Next() {
+		i, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updatePaymentAmount = `-- name: UpdatePaymentAmount :exec
+UPDATE payments SET amount = ? WHERE id = ?
+`
+
+func (q *Queries) UpdatePaymentAmount(ctx context.Context, arg repository.UpdatePaymentAmountParams) (repository.Payment, error) {
+	if _, err := q.db.ExecContext(ctx, updatePaymentAmount, arg.Amount, arg.ID); err != nil {
+		return repository.Payment{}, err
+	}
+	return q.GetPayment(ctx, arg.ID)
+}
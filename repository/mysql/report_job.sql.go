@@ -0,0 +1,88 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const reportJobColumns = `id, kind, status, from_date, to_date, file_key, row_count, error, created_at, started_at, completed_at`
+
+func scanReportJob(row scanner) (repository.ReportJob, error) {
+	var i repository.ReportJob
+	err := row.Scan(
+		&i.ID,
+		&i.Kind,
+		&i.Status,
+		&i.FromDate,
+		&i.ToDate,
+		&i.FileKey,
+		&i.RowCount,
+		&i.Error,
+		&i.CreatedAt,
+		&i.StartedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const completeReportJob = `-- name: CompleteReportJob :execrows
+UPDATE report_jobs SET status = 'completed', file_key = ?, row_count = ?, completed_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND status = 'running'
+`
+
+func (q *Queries) CompleteReportJob(ctx context.Context, arg repository.CompleteReportJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, completeReportJob, arg.FileKey, arg.RowCount, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createReportJob = `-- name: CreateReportJob :exec
+INSERT INTO report_jobs (id, kind, from_date, to_date)
+VALUES (?, ?, ?, ?)
+`
+
+func (q *Queries) CreateReportJob(ctx context.Context, arg repository.CreateReportJobParams) (repository.ReportJob, error) {
+	id := uuid.New()
+	if _, err := q.db.ExecContext(ctx, createReportJob, id, arg.Kind, arg.FromDate, arg.ToDate); err != nil {
+		return repository.ReportJob{}, err
+	}
+	return q.GetReportJob(ctx, id)
+}
+
+const failReportJob = `-- name: FailReportJob :execrows
+UPDATE report_jobs SET status = 'failed', error = ?, completed_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND status IN ('pending', 'running')
+`
+
+func (q *Queries) FailReportJob(ctx context.Context, arg repository.FailReportJobParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, failReportJob, arg.Error, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getReportJob = `-- name: GetReportJob :one
+SELECT ` + reportJobColumns + ` FROM report_jobs WHERE id = ?
+`
+
+func (q *Queries) GetReportJob(ctx context.Context, id uuid.UUID) (repository.ReportJob, error) {
+	return scanReportJob(q.db.QueryRowContext(ctx, getReportJob, id))
+}
+
+const startReportJob = `-- name: StartReportJob :execrows
+UPDATE report_jobs SET status = 'running', started_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND status IN ('pending', 'running')
+`
+
+func (q *Queries) StartReportJob(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, startReportJob, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,242 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const getBonusReport = `-- name: GetBonusReport :many
+SELECT DATE(t.created_at) AS day,
+    t.destination_mint,
+    CAST(SUM(t.accrued_bonus_amount) AS SIGNED) AS minted,
+    CAST(SUM(CASE WHEN t.apply_bonus THEN t.discount_amount ELSE 0 END) AS SIGNED) AS redeemed
+FROM transactions t
+JOIN payments p ON p.id = t.payment_id AND p.livemode
+WHERE t.status = 'completed'
+    AND t.created_at >= ? AND t.created_at < ?
+GROUP BY day, t.destination_mint
+ORDER BY day, t.destination_mint
+`
+
+func (q *Queries) GetBonusReport(ctx context.Context, arg repository.GetBonusReportParams) ([]repository.GetBonusReportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getBonusReport, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.GetBonusReportRow
+	for rows.Next() {
+		var i repository.GetBonusReportRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.DestinationMint,
+			&i.Minted,
+			&i.Redeemed,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPaymentHistoryExport = `-- name: GetPaymentHistoryExport :many
+SELECT p.id AS payment_id,
+    p.external_id,
+    p.status AS payment_status,
+    p.destination_wallet,
+    p.destination_mint,
+    p.amount AS payment_amount,
+    p.created_at AS payment_created_at,
+    t.id AS transaction_id,
+    t.reference,
+    t.status AS transaction_status,
+    t.source_wallet,
+    t.total_amount,
+    t.tx_signature
+FROM payments p
+LEFT JOIN transactions t ON t.payment_id = p.id
+WHERE p.livemode AND p.created_at >= ? AND p.created_at < ?
+ORDER BY p.created_at, p.id, t.created_at, t.id
+LIMIT ? OFFSET ?
+`
+
+func (q *Queries) GetPaymentHistoryExport(ctx context.Context, arg repository.GetPaymentHistoryExportParams) ([]repository.GetPaymentHistoryExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPaymentHistoryExport,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.GetPaymentHistoryExportRow
+	for rows.Next() {
+		var i repository.GetPaymentHistoryExportRow
+		if err := rows.Scan(
+			&i.PaymentID,
+			&i.ExternalID,
+			&i.PaymentStatus,
+			&i.DestinationWallet,
+			&i.DestinationMint,
+			&i.PaymentAmount,
+			&i.PaymentCreatedAt,
+			&i.TransactionID,
+			&i.Reference,
+			&i.TransactionStatus,
+			&i.SourceWallet,
+			&i.TotalAmount,
+			&i.TxSignature,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRevenueReport = `-- name: GetRevenueReport :many
+SELECT DATE(t.created_at) AS day,
+    t.destination_mint,
+    t.destination_wallet,
+    COUNT(*) AS transactions,
+    CAST(SUM(t.amount) AS SIGNED) AS amount,
+    CAST(SUM(t.discount_amount) AS SIGNED) AS discount_amount,
+    CAST(SUM(t.voucher_amount) AS SIGNED) AS voucher_amount,
+    CAST(SUM(t.total_amount) AS SIGNED) AS total_amount
+FROM transactions t
+JOIN payments p ON p.id = t.payment_id AND p.livemode
+WHERE t.status = 'completed'
+    AND t.created_at >= ? AND t.created_at < ?
+GROUP BY day, t.destination_mint, t.destination_wallet
+ORDER BY day, t.destination_mint, t.destination_wallet
+`
+
+func (q *Queries) GetRevenueReport(ctx context.Context, arg repository.GetRevenueReportParams) ([]repository.GetRevenueReportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getRevenueReport, arg.FromDate, arg.ToDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.GetRevenueReportRow
+	for rows.Next() {
+		var i repository.GetRevenueReportRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.DestinationMint,
+			&i.DestinationWallet,
+			&i.Transactions,
+			&i.Amount,
+			&i.DiscountAmount,
+			&i.VoucherAmount,
+			&i.TotalAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSettlementExport = `-- name: GetSettlementExport :many
+SELECT t.id,
+    t.payment_id,
+    p.external_id,
+    t.reference,
+    t.source_wallet,
+    t.source_mint,
+    t.destination_wallet,
+    t.destination_mint,
+    t.amount,
+    t.discount_amount,
+    t.voucher_amount,
+    t.total_amount,
+    t.network_fee,
+    t.priority_fee,
+    t.slippage_fee,
+    t.tip_amount,
+    t.tip_destination,
+    t.exchange_rate,
+    p.fiat_currency,
+    t.tx_signature,
+    t.created_at,
+    t.updated_at
+FROM transactions t
+JOIN payments p ON p.id = t.payment_id AND p.livemode
+WHERE t.status = 'completed'
+    AND t.created_at >= ? AND t.created_at < ?
+ORDER BY t.created_at, t.id
+LIMIT ? OFFSET ?
+`
+
+func (q *Queries) GetSettlementExport(ctx context.Context, arg repository.GetSettlementExportParams) ([]repository.GetSettlementExportRow, error) {
+	rows, err := q.db.QueryContext(ctx, getSettlementExport,
+		arg.FromDate,
+		arg.ToDate,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.GetSettlementExportRow
+	for rows.Next() {
+		var i repository.GetSettlementExportRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentID,
+			&i.ExternalID,
+			&i.Reference,
+			&i.SourceWallet,
+			&i.SourceMint,
+			&i.DestinationWallet,
+			&i.DestinationMint,
+			&i.Amount,
+			&i.DiscountAmount,
+			&i.VoucherAmount,
+			&i.TotalAmount,
+			&i.NetworkFee,
+			&i.PriorityFee,
+			&i.SlippageFee,
+			&i.TipAmount,
+			&i.TipDestination,
+			&i.ExchangeRate,
+			&i.FiatCurrency,
+			&i.TxSignature,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
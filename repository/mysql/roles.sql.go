@@ -0,0 +1,83 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const deleteCredentialRole = `-- name: DeleteCredentialRole :execrows
+DELETE FROM credential_roles WHERE credential = ?
+`
+
+func (q *Queries) DeleteCredentialRole(ctx context.Context, credential string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCredentialRole, credential)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCredentialRole = `-- name: GetCredentialRole :one
+SELECT credential, role, created_at, updated_at FROM credential_roles WHERE credential = ?
+`
+
+func (q *Queries) GetCredentialRole(ctx context.Context, credential string) (repository.CredentialRole, error) {
+	row := q.db.QueryRowContext(ctx, getCredentialRole, credential)
+	var i repository.CredentialRole
+	err := row.Scan(
+		&i.Credential,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCredentialRoles = `-- name: GetCredentialRoles :many
+SELECT credential, role, created_at, updated_at FROM credential_roles ORDER BY credential
+`
+
+func (q *Queries) GetCredentialRoles(ctx context.Context) ([]repository.CredentialRole, error) {
+	rows, err := q.db.QueryContext(ctx, getCredentialRoles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.CredentialRole
+	for rows.Next() {
+		var i repository.CredentialRole
+		if err := rows.Scan(
+			&i.Credential,
+			&i.Role,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const storeCredentialRole = `-- name: StoreCredentialRole :exec
+INSERT INTO credential_roles (credential, role)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE
+    role = ?,
+    updated_at = CURRENT_TIMESTAMP(6)
+`
+
+func (q *Queries) StoreCredentialRole(ctx context.Context, arg repository.StoreCredentialRoleParams) (repository.CredentialRole, error) {
+	// the role is bound twice: for the insert and for the update on the duplicate key
+	if _, err := q.db.ExecContext(ctx, storeCredentialRole, arg.Credential, arg.Role, arg.Role); err != nil {
+		return repository.CredentialRole{}, err
+	}
+	return q.GetCredentialRole(ctx, arg.Credential)
+}
@@ -0,0 +1,67 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const getMerchantSettings = `-- name: GetMerchantSettings :one
+SELECT id, apply_bonus, max_apply_bonus_amount, max_apply_bonus_percent, accrue_bonus_rate, destination_mint, destination_wallet, escrow_release_after, updated_at FROM merchant_settings WHERE id = 1
+`
+
+func (q *Queries) GetMerchantSettings(ctx context.Context) (repository.MerchantSetting, error) {
+	row := q.db.QueryRowContext(ctx, getMerchantSettings)
+	var i repository.MerchantSetting
+	err := row.Scan(
+		&i.ID,
+		&i.ApplyBonus,
+		&i.MaxApplyBonusAmount,
+		&i.MaxApplyBonusPercent,
+		&i.AccrueBonusRate,
+		&i.DestinationMint,
+		&i.DestinationWallet,
+		&i.EscrowReleaseAfter,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const storeMerchantSettings = `-- name: StoreMerchantSettings :exec
+INSERT INTO merchant_settings (
+    id,
+    apply_bonus,
+    max_apply_bonus_amount,
+    max_apply_bonus_percent,
+    accrue_bonus_rate,
+    destination_mint,
+    destination_wallet,
+    escrow_release_after
+) VALUES (1, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    apply_bonus = ?,
+    max_apply_bonus_amount = ?,
+    max_apply_bonus_percent = ?,
+    accrue_bonus_rate = ?,
+    destination_mint = ?,
+    destination_wallet = ?,
+    escrow_release_after = ?,
+    updated_at = CURRENT_TIMESTAMP(6)
+`
+
+func (q *Queries) StoreMerchantSettings(ctx context.Context, arg repository.StoreMerchantSettingsParams) (repository.MerchantSetting, error) {
+	values := []interface{}{
+		arg.ApplyBonus,
+		arg.MaxApplyBonusAmount,
+		arg.MaxApplyBonusPercent,
+		arg.AccrueBonusRate,
+		arg.DestinationMint,
+		arg.DestinationWallet,
+		arg.EscrowReleaseAfter,
+	}
+	// the values are bound twice: for the insert and for the update on the duplicate key
+	if _, err := q.db.ExecContext(ctx, storeMerchantSettings, append(values, values...)...); err != nil {
+		return repository.MerchantSetting{}, err
+	}
+	return q.GetMerchantSettings(ctx)
+}
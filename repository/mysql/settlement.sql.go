@@ -0,0 +1,72 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const settlementColumns = `id, payment_id, input_mint, output_mint, in_amount, out_amount, status, tx_signature, error, created_at, updated_at`
+
+func scanSettlement(row scanner) (repository.Settlement, error) {
+	var i repository.Settlement
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentID,
+		&i.InputMint,
+		&i.OutputMint,
+		&i.InAmount,
+		&i.OutAmount,
+		&i.Status,
+		&i.TxSignature,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createSettlement = `-- name: CreateSettlement :exec
+INSERT INTO settlements (id, payment_id, input_mint, output_mint, in_amount)
+VALUES (?, ?, ?, ?, ?)
+`
+
+func (q *Queries) CreateSettlement(ctx context.Context, arg repository.CreateSettlementParams) (repository.Settlement, error) {
+	id := uuid.New()
+	if _, err := q.db.ExecContext(ctx, createSettlement, id, arg.PaymentID, arg.InputMint, arg.OutputMint, arg.InAmount); err != nil {
+		return repository.Settlement{}, uniqueViolation(err)
+	}
+	return scanSettlement(q.db.QueryRowContext(ctx, getSettlement, id))
+}
+
+const getSettlement = `-- name: GetSettlement :one
+SELECT ` + settlementColumns + ` FROM settlements WHERE id = ?
+`
+
+const getSettlementByPaymentID = `-- name: GetSettlementByPaymentID :one
+SELECT ` + settlementColumns + ` FROM settlements WHERE payment_id = ?
+`
+
+func (q *Queries) GetSettlementByPaymentID(ctx context.Context, paymentID uuid.UUID) (repository.Settlement, error) {
+	return scanSettlement(q.db.QueryRowContext(ctx, getSettlementByPaymentID, paymentID))
+}
+
+const updateSettlement = `-- name: UpdateSettlement :execrows
+UPDATE settlements SET status = ?, out_amount = ?, tx_signature = ?, error = ?, updated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ?
+`
+
+func (q *Queries) UpdateSettlement(ctx context.Context, arg repository.UpdateSettlementParams) (repository.Settlement, error) {
+	result, err := q.db.ExecContext(ctx, updateSettlement, arg.Status, arg.OutAmount, arg.TxSignature, arg.Error, arg.ID)
+	if err != nil {
+		return repository.Settlement{}, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return repository.Settlement{}, err
+	} else if n == 0 {
+		return repository.Settlement{}, sql.ErrNoRows
+	}
+	return scanSettlement(q.db.QueryRowContext(ctx, getSettlement, arg.ID))
+}
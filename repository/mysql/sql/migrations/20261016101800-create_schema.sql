@@ -0,0 +1,154 @@
+-- +migrate Up
+-- the statements are split by the semicolons, since the mysql driver runs one statement at a time;
+-- the MySQL 8.0 counterpart of the PostgreSQL schema up to 20261016101700-add_swap_route_to_transactions;
+-- the base58 addresses and signatures are case-sensitive, hence the binary collation
+CREATE TABLE IF NOT EXISTS payments (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    external_id VARCHAR(255) DEFAULT NULL,
+    destination_wallet VARCHAR(64) NOT NULL,
+    destination_mint VARCHAR(64) NOT NULL,
+    amount BIGINT NOT NULL,
+    status ENUM('new', 'pending', 'completed', 'failed', 'canceled', 'expired', 'held', 'released', 'disputed') NOT NULL DEFAULT 'new',
+    message VARCHAR(255) DEFAULT NULL,
+    expires_at DATETIME(6) DEFAULT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP(6),
+    customer_email VARCHAR(255) DEFAULT NULL,
+    translations JSON NOT NULL DEFAULT ('{}'),
+    fee_on_top BOOLEAN NOT NULL DEFAULT FALSE,
+    escrow BOOLEAN NOT NULL DEFAULT FALSE,
+    held_until DATETIME(6) DEFAULT NULL,
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    callback_url VARCHAR(2048) DEFAULT NULL,
+    UNIQUE KEY payments_external_id (external_id),
+    KEY payments_status_held_until (status, held_until)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    payment_id CHAR(36) NOT NULL,
+    reference VARCHAR(64) NOT NULL,
+    source_wallet VARCHAR(64) NOT NULL,
+    source_mint VARCHAR(64) NOT NULL,
+    destination_wallet VARCHAR(64) NOT NULL,
+    destination_mint VARCHAR(64) NOT NULL,
+    amount BIGINT NOT NULL,
+    discount_amount BIGINT NOT NULL DEFAULT 0,
+    total_amount BIGINT NOT NULL,
+    accrued_bonus_amount BIGINT NOT NULL DEFAULT 0,
+    message VARCHAR(255) DEFAULT NULL,
+    memo VARCHAR(255) DEFAULT NULL,
+    apply_bonus BOOLEAN DEFAULT NULL,
+    tx_signature VARCHAR(128) DEFAULT NULL,
+    status ENUM('pending', 'completed', 'failed', 'expired') NOT NULL DEFAULT 'pending',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP(6),
+    network_fee BIGINT NOT NULL DEFAULT 0,
+    priority_fee BIGINT NOT NULL DEFAULT 0,
+    slippage_fee BIGINT NOT NULL DEFAULT 0,
+    voucher_amount BIGINT NOT NULL DEFAULT 0,
+    swap_route JSON NOT NULL DEFAULT ('{}'),
+    UNIQUE KEY transactions_reference (reference),
+    KEY transactions_payment_source (payment_id, source_wallet, source_mint),
+    KEY transactions_status_created_at (status, created_at, id),
+    CONSTRAINT transactions_payment_id_fk FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- additional reference keys of the transactions; the primary one is stored in transactions.reference
+CREATE TABLE IF NOT EXISTS transaction_references (
+    reference VARCHAR(64) NOT NULL PRIMARY KEY,
+    transaction_id CHAR(36) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY transaction_references_transaction_id_idx (transaction_id),
+    CONSTRAINT transaction_references_transaction_id_fk FOREIGN KEY (transaction_id) REFERENCES transactions (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS tokens (
+    token_type VARCHAR(32) NOT NULL,
+    credential VARCHAR(255) NOT NULL,
+    access_token_id CHAR(36) NOT NULL,
+    refresh_token_id CHAR(36) NOT NULL,
+    access_expires_at DATETIME(6) NOT NULL,
+    refresh_expires_at DATETIME(6) NOT NULL,
+    updated_at DATETIME(6) NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP(6),
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (token_type, credential),
+    UNIQUE KEY tokens_access_token_id (access_token_id),
+    UNIQUE KEY tokens_refresh_token_id (refresh_token_id),
+    KEY tokens_refresh_expires_at (refresh_expires_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS mints (
+    address VARCHAR(64) NOT NULL PRIMARY KEY,
+    decimals SMALLINT NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS deposits (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    wallet VARCHAR(64) NOT NULL,
+    mint VARCHAR(64) NOT NULL,
+    amount BIGINT NOT NULL,
+    source_wallet VARCHAR(64) DEFAULT NULL,
+    tx_signature VARCHAR(128) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    UNIQUE KEY deposits_tx_signature_mint (tx_signature, mint)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS payment_funnel_events (
+    payment_id CHAR(36) NOT NULL,
+    stage VARCHAR(64) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    PRIMARY KEY (payment_id, stage),
+    CONSTRAINT payment_funnel_events_payment_id_fk FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- MySQL has no partial indexes, so a single open dispute per payment
+-- is guarded by CreatePaymentDispute, which locks the payment row
+CREATE TABLE IF NOT EXISTS payment_disputes (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    payment_id CHAR(36) NOT NULL,
+    reason VARCHAR(1024) NOT NULL,
+    evidence_urls JSON NOT NULL DEFAULT ('[]'),
+    previous_status ENUM('new', 'pending', 'completed', 'failed', 'canceled', 'expired', 'held', 'released', 'disputed') NOT NULL,
+    resolution VARCHAR(1024) DEFAULT NULL,
+    resolved_at DATETIME(6) DEFAULT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY payment_disputes_payment_id_idx (payment_id, resolved_at),
+    CONSTRAINT payment_disputes_payment_id_fk FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS payment_events (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    payment_id CHAR(36) NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    data JSON NOT NULL DEFAULT ('{}'),
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY payment_events_payment_id_idx (payment_id, id),
+    CONSTRAINT payment_events_payment_id_fk FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- the runtime merchant settings; a single row, the env config is used until it's stored
+CREATE TABLE IF NOT EXISTS merchant_settings (
+    id SMALLINT NOT NULL DEFAULT 1 PRIMARY KEY CHECK (id = 1),
+    apply_bonus BOOLEAN NOT NULL,
+    max_apply_bonus_amount BIGINT NOT NULL,
+    max_apply_bonus_percent INTEGER NOT NULL,
+    accrue_bonus_rate BIGINT NOT NULL,
+    destination_mint VARCHAR(64) NOT NULL,
+    destination_wallet VARCHAR(64) NOT NULL,
+    escrow_release_after BIGINT NOT NULL DEFAULT 0,
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS merchant_settings;
+DROP TABLE IF EXISTS payment_events;
+DROP TABLE IF EXISTS payment_disputes;
+DROP TABLE IF EXISTS payment_funnel_events;
+DROP TABLE IF EXISTS deposits;
+DROP TABLE IF EXISTS mints;
+DROP TABLE IF EXISTS tokens;
+DROP TABLE IF EXISTS transaction_references;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS payments;
@@ -0,0 +1,19 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016101900-create_audit_records_table
+CREATE TABLE IF NOT EXISTS audit_records (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    client_id VARCHAR(255) NOT NULL,
+    method VARCHAR(16) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path VARCHAR(2048) NOT NULL,
+    payload_hash VARCHAR(64) NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL,
+    latency_us BIGINT NOT NULL,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY audit_records_created_at_idx (created_at),
+    KEY audit_records_client_id_idx (client_id, created_at)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS audit_records;
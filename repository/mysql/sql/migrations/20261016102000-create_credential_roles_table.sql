@@ -0,0 +1,11 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102000-create_credential_roles_table
+CREATE TABLE IF NOT EXISTS credential_roles (
+    credential VARCHAR(255) NOT NULL PRIMARY KEY,
+    role VARCHAR(32) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS credential_roles;
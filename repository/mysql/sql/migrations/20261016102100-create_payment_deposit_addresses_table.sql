@@ -0,0 +1,18 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102100-create_payment_deposit_addresses_table
+CREATE TABLE IF NOT EXISTS payment_deposit_addresses (
+    payment_id CHAR(36) NOT NULL PRIMARY KEY,
+    transaction_id CHAR(36) NOT NULL,
+    wallet VARCHAR(64) NOT NULL,
+    token_account VARCHAR(64) NOT NULL,
+    mint VARCHAR(64) NOT NULL,
+    amount BIGINT NOT NULL,
+    sweep_signature VARCHAR(128) DEFAULT NULL,
+    swept_at DATETIME(6) DEFAULT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY payment_deposit_addresses_transaction_id_idx (transaction_id),
+    CONSTRAINT payment_deposit_addresses_payment_id_fk FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS payment_deposit_addresses;
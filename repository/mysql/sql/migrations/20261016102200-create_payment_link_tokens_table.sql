@@ -0,0 +1,14 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102200-create_payment_link_tokens_table
+CREATE TABLE IF NOT EXISTS payment_link_tokens (
+    token_hash VARCHAR(64) NOT NULL PRIMARY KEY,
+    payment_id CHAR(36) NOT NULL,
+    expires_at DATETIME(6) NOT NULL,
+    used_at DATETIME(6) DEFAULT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    KEY payment_link_tokens_expires_at_idx (expires_at),
+    CONSTRAINT payment_link_tokens_payment_id_fk FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS payment_link_tokens;
@@ -0,0 +1,18 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102300-create_report_jobs_table
+CREATE TABLE IF NOT EXISTS report_jobs (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    kind VARCHAR(32) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    from_date DATETIME(6) NOT NULL,
+    to_date DATETIME(6) NOT NULL,
+    file_key VARCHAR(255) DEFAULT NULL,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    error TEXT DEFAULT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    started_at DATETIME(6) DEFAULT NULL,
+    completed_at DATETIME(6) DEFAULT NULL
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS report_jobs;
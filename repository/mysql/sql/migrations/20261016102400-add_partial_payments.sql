@@ -0,0 +1,14 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102400-add_partial_payments
+ALTER TABLE payments
+    MODIFY status ENUM('new', 'pending', 'completed', 'failed', 'canceled', 'expired', 'held', 'released', 'disputed', 'partially_paid') NOT NULL DEFAULT 'new',
+    ADD COLUMN allow_partial BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN amount_paid BIGINT NOT NULL DEFAULT 0;
+UPDATE payments SET amount_paid = amount WHERE status IN ('completed', 'held', 'released', 'disputed');
+
+-- +migrate Down
+UPDATE payments SET status = 'pending' WHERE status = 'partially_paid';
+ALTER TABLE payments
+    DROP COLUMN amount_paid,
+    DROP COLUMN allow_partial,
+    MODIFY status ENUM('new', 'pending', 'completed', 'failed', 'canceled', 'expired', 'held', 'released', 'disputed') NOT NULL DEFAULT 'new';
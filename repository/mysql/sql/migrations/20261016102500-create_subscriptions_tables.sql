@@ -0,0 +1,43 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102500-create_subscriptions_tables
+CREATE TABLE IF NOT EXISTS subscription_plans (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR(64) NOT NULL,
+    billing_interval VARCHAR(16) NOT NULL,
+    interval_count INT NOT NULL DEFAULT 1,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    plan_id CHAR(36) NOT NULL,
+    customer_email VARCHAR(255) DEFAULT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'active',
+    livemode BOOLEAN NOT NULL DEFAULT TRUE,
+    next_billing_at DATETIME(6) NOT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    canceled_at DATETIME(6) DEFAULT NULL,
+    KEY subscriptions_status_next_billing_at_idx (status, next_billing_at),
+    CONSTRAINT subscriptions_plan_id_fk FOREIGN KEY (plan_id) REFERENCES subscription_plans (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+CREATE TABLE IF NOT EXISTS subscription_installments (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    subscription_id CHAR(36) NOT NULL,
+    payment_id CHAR(36) DEFAULT NULL,
+    due_at DATETIME(6) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'due',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT NULL,
+    UNIQUE KEY subscription_installments_subscription_id_due_at_idx (subscription_id, due_at),
+    KEY subscription_installments_payment_id_idx (payment_id),
+    CONSTRAINT subscription_installments_subscription_id_fk FOREIGN KEY (subscription_id) REFERENCES subscriptions (id) ON DELETE CASCADE,
+    CONSTRAINT subscription_installments_payment_id_fk FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE SET NULL
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS subscription_installments;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS subscription_plans;
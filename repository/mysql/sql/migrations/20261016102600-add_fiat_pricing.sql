@@ -0,0 +1,12 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102600-add_fiat_pricing
+ALTER TABLE payments
+    ADD COLUMN fiat_currency VARCHAR(3) DEFAULT NULL,
+    ADD COLUMN fiat_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN exchange_rate DECIMAL(36, 18) DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE transactions DROP COLUMN exchange_rate;
+ALTER TABLE payments
+    DROP COLUMN fiat_amount,
+    DROP COLUMN fiat_currency;
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102700-add_transaction_tips
+ALTER TABLE transactions
+    ADD COLUMN tip_amount BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN tip_destination VARCHAR(64) DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE transactions
+    DROP COLUMN tip_destination,
+    DROP COLUMN tip_amount;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102800-add_payment_settings
+ALTER TABLE payments ADD COLUMN settings JSON DEFAULT NULL;
+
+-- +migrate Down
+ALTER TABLE payments DROP COLUMN settings;
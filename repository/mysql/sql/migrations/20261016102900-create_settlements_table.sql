@@ -0,0 +1,20 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016102900-create_settlements_table
+CREATE TABLE IF NOT EXISTS settlements (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    payment_id CHAR(36) NOT NULL,
+    input_mint VARCHAR(64) NOT NULL,
+    output_mint VARCHAR(64) NOT NULL,
+    in_amount BIGINT NOT NULL,
+    out_amount BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    tx_signature VARCHAR(128) DEFAULT NULL,
+    error TEXT DEFAULT NULL,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT NULL,
+    UNIQUE KEY settlements_payment_id_idx (payment_id),
+    CONSTRAINT settlements_payment_id_fk FOREIGN KEY (payment_id) REFERENCES payments (id) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS settlements;
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016103000-create_api_keys_table
+CREATE TABLE IF NOT EXISTS api_keys (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    key_prefix VARCHAR(32) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    previous_key_hash CHAR(64) DEFAULT NULL,
+    previous_key_expires_at DATETIME(6) DEFAULT NULL,
+    role VARCHAR(32) NOT NULL,
+    livemode BOOLEAN NOT NULL DEFAULT true,
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    rotated_at DATETIME(6) DEFAULT NULL,
+    revoked_at DATETIME(6) DEFAULT NULL,
+    UNIQUE KEY api_keys_key_hash_idx (key_hash),
+    KEY api_keys_previous_key_hash_idx (previous_key_hash)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS api_keys;
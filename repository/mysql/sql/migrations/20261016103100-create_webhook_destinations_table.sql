@@ -0,0 +1,13 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016103100-create_webhook_destinations_table
+CREATE TABLE IF NOT EXISTS webhook_destinations (
+    id CHAR(36) NOT NULL PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    events TEXT NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    updated_at DATETIME(6) DEFAULT NULL
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS webhook_destinations;
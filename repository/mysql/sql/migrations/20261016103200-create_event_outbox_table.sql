@@ -0,0 +1,13 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016103200-create_event_outbox_table
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGINT NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    event VARCHAR(64) NOT NULL,
+    payload JSON NOT NULL DEFAULT ('{}'),
+    created_at DATETIME(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+    published_at DATETIME(6) DEFAULT NULL,
+    KEY event_outbox_published_at_idx (published_at, id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin;
+
+-- +migrate Down
+DROP TABLE IF EXISTS event_outbox;
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- the MySQL counterpart of 20261016103300-add_api_key_scopes
+ALTER TABLE api_keys ADD COLUMN scopes VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE api_keys DROP COLUMN scopes;
@@ -0,0 +1,26 @@
+-- name: CreateAPIKey :exec
+INSERT INTO api_keys (id, name, key_prefix, key_hash, role, livemode, scopes)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetAPIKey :one
+SELECT * FROM api_keys WHERE id = ?;
+
+-- name: GetAPIKeys :many
+SELECT * FROM api_keys ORDER BY created_at DESC;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE revoked_at IS NULL
+    AND (key_hash = ? OR (previous_key_hash = ? AND previous_key_expires_at > CURRENT_TIMESTAMP(6)));
+
+-- name: RotateAPIKey :execrows
+UPDATE api_keys SET
+    previous_key_hash = key_hash,
+    previous_key_expires_at = ?,
+    key_hash = ?,
+    key_prefix = ?,
+    rotated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND revoked_at IS NULL;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = CURRENT_TIMESTAMP(6) WHERE id = ? AND revoked_at IS NULL;
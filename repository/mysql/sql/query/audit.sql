@@ -0,0 +1,13 @@
+-- name: AddAuditRecord :exec
+INSERT INTO audit_records (client_id, method, route, path, payload_hash, status_code, latency_us, request_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetAuditRecords :many
+SELECT * FROM audit_records
+WHERE (? = '' OR client_id = ?)
+    AND created_at >= ? AND created_at < ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?;
+
+-- name: DeleteAuditRecordsCreatedBefore :execrows
+DELETE FROM audit_records WHERE created_at < ?;
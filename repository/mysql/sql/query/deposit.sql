@@ -0,0 +1,9 @@
+-- name: CreateDeposit :exec
+INSERT INTO deposits (id, wallet, mint, amount, source_wallet, tx_signature)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetDeposit :one
+SELECT * FROM deposits WHERE id = ?;
+
+-- name: DepositExists :one
+SELECT EXISTS(SELECT 1 FROM deposits WHERE tx_signature = ? AND mint = ?);
@@ -0,0 +1,16 @@
+-- name: CreateDepositAddress :exec
+INSERT INTO payment_deposit_addresses (payment_id, transaction_id, wallet, token_account, mint, amount)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetDepositAddressByPaymentID :one
+SELECT * FROM payment_deposit_addresses WHERE payment_id = ?;
+
+-- name: GetDepositAddressesToSweep :many
+SELECT d.* FROM payment_deposit_addresses d
+JOIN transactions t ON t.id = d.transaction_id
+WHERE d.swept_at IS NULL AND t.status = 'completed'
+ORDER BY d.created_at;
+
+-- name: MarkDepositAddressSwept :execrows
+UPDATE payment_deposit_addresses SET sweep_signature = ?, swept_at = CURRENT_TIMESTAMP(6)
+WHERE payment_id = ? AND swept_at IS NULL;
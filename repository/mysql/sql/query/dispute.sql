@@ -0,0 +1,21 @@
+-- name: CreatePaymentDispute :exec
+INSERT INTO payment_disputes (id, payment_id, reason, evidence_urls, previous_status)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: LockPayment :one
+SELECT id FROM payments WHERE id = ? FOR UPDATE;
+
+-- name: GetPaymentDispute :one
+SELECT * FROM payment_disputes WHERE id = ?;
+
+-- name: GetOpenPaymentDispute :one
+SELECT * FROM payment_disputes WHERE payment_id = ? AND resolved_at IS NULL;
+
+-- name: GetPaymentDisputes :many
+SELECT * FROM payment_disputes WHERE payment_id = ? ORDER BY created_at DESC;
+
+-- name: LockOpenPaymentDispute :one
+SELECT id FROM payment_disputes WHERE id = ? AND resolved_at IS NULL FOR UPDATE;
+
+-- name: ResolvePaymentDispute :exec
+UPDATE payment_disputes SET resolution = ?, resolved_at = CURRENT_TIMESTAMP(6) WHERE id = ?;
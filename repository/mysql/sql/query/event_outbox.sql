@@ -0,0 +1,15 @@
+-- name: AddOutboxEvent :exec
+INSERT INTO event_outbox (event, payload)
+VALUES (?, ?);
+
+-- name: GetPendingOutboxEvents :many
+SELECT id, event, payload, created_at, published_at FROM event_outbox WHERE published_at IS NULL
+ORDER BY id
+LIMIT ?
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE event_outbox SET published_at = CURRENT_TIMESTAMP(6) WHERE id = ?;
+
+-- name: DeleteOutboxEventsPublishedBefore :execrows
+DELETE FROM event_outbox WHERE published_at < ?;
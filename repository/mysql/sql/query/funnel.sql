@@ -0,0 +1,12 @@
+-- name: TrackFunnelStage :execrows
+INSERT INTO payment_funnel_events (payment_id, stage)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE payment_id = payment_id;
+
+-- name: GetFunnelReport :many
+SELECT p.destination_wallet, p.destination_mint, f.stage, COUNT(*) AS total
+FROM payment_funnel_events f
+JOIN payments p ON p.id = f.payment_id AND p.livemode
+WHERE p.created_at >= ? AND p.created_at < ?
+GROUP BY p.destination_wallet, p.destination_mint, f.stage
+ORDER BY p.destination_wallet, p.destination_mint, f.stage;
@@ -0,0 +1,13 @@
+-- name: CreateLinkToken :exec
+INSERT INTO payment_link_tokens (token_hash, payment_id, expires_at)
+VALUES (?, ?, ?);
+
+-- name: GetLinkToken :one
+SELECT * FROM payment_link_tokens WHERE token_hash = ?;
+
+-- name: UseLinkToken :execrows
+UPDATE payment_link_tokens SET used_at = CURRENT_TIMESTAMP(6)
+WHERE token_hash = ? AND payment_id = ? AND used_at IS NULL AND expires_at > CURRENT_TIMESTAMP(6);
+
+-- name: DeleteExpiredLinkTokens :execrows
+DELETE FROM payment_link_tokens WHERE expires_at < CURRENT_TIMESTAMP(6);
@@ -0,0 +1,6 @@
+-- name: GetMintDecimals :one
+SELECT decimals FROM mints WHERE address = ?;
+
+-- name: StoreMintDecimals :exec
+INSERT INTO mints (address, decimals) VALUES (?, ?)
+ON DUPLICATE KEY UPDATE decimals = ?;
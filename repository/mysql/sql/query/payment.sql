@@ -0,0 +1,66 @@
+-- name: CreatePayment :exec
+INSERT INTO payments (
+    id,
+    external_id,
+    destination_wallet,
+    destination_mint,
+    amount,
+    status,
+    message,
+    expires_at,
+    customer_email,
+    translations,
+    fee_on_top,
+    escrow,
+    livemode,
+    callback_url,
+    allow_partial,
+    fiat_currency,
+    fiat_amount,
+    settings
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetPayment :one
+SELECT * FROM payments WHERE id = ?;
+
+-- name: GetPaymentByExternalID :one
+SELECT * FROM payments WHERE external_id = ?;
+
+-- name: GetPaymentStatus :one
+SELECT p.status, COALESCE(p.updated_at, p.created_at) AS updated_at,
+    COALESCE((
+        SELECT t.tx_signature FROM transactions t
+        WHERE t.payment_id = p.id AND t.tx_signature IS NOT NULL AND t.tx_signature <> ''
+        ORDER BY t.updated_at IS NULL, t.updated_at DESC, t.created_at DESC
+        LIMIT 1
+    ), '') AS tx_signature
+FROM payments p WHERE p.id = ?;
+
+-- name: UpdatePaymentStatus :exec
+UPDATE payments SET status = ? WHERE id = ?;
+
+-- name: MarkPaymentsExpired :exec
+UPDATE payments SET status = 'expired' WHERE expires_at < ? AND status = 'new';
+
+-- name: HoldPayment :exec
+UPDATE payments SET status = 'held', held_until = ? WHERE id = ?;
+
+-- name: LockHeldPayment :one
+SELECT id FROM payments WHERE id = ? AND status = 'held' FOR UPDATE;
+
+-- name: ReleasePayment :exec
+UPDATE payments SET status = 'released' WHERE id = ?;
+
+-- name: GetPaymentsToRelease :many
+SELECT * FROM payments WHERE status = 'held' AND held_until < CURRENT_TIMESTAMP(6) ORDER BY held_until;
+
+-- name: RefreshPaymentAmountPaid :exec
+UPDATE payments SET amount_paid = (
+    SELECT COALESCE(SUM(t.amount), 0) FROM transactions t
+    WHERE t.payment_id = ? AND t.status = 'completed'
+)
+WHERE id = ?;
+
+-- name: UpdatePaymentAmount :exec
+UPDATE payments SET amount = ? WHERE id = ?;
@@ -0,0 +1,27 @@
+-- name: GetRevenueReport :many
+SELECT DATE(t.created_at) AS day,
+    t.destination_mint,
+    t.destination_wallet,
+    COUNT(*) AS transactions,
+    CAST(SUM(t.amount) AS SIGNED) AS amount,
+    CAST(SUM(t.discount_amount) AS SIGNED) AS discount_amount,
+    CAST(SUM(t.voucher_amount) AS SIGNED) AS voucher_amount,
+    CAST(SUM(t.total_amount) AS SIGNED) AS total_amount
+FROM transactions t
+JOIN payments p ON p.id = t.payment_id AND p.livemode
+WHERE t.status = 'completed'
+    AND t.created_at >= ? AND t.created_at < ?
+GROUP BY day, t.destination_mint, t.destination_wallet
+ORDER BY day, t.destination_mint, t.destination_wallet;
+
+-- name: GetBonusReport :many
+SELECT DATE(t.created_at) AS day,
+    t.destination_mint,
+    CAST(SUM(t.accrued_bonus_amount) AS SIGNED) AS minted,
+    CAST(SUM(CASE WHEN t.apply_bonus THEN t.discount_amount ELSE 0 END) AS SIGNED) AS redeemed
+FROM transactions t
+JOIN payments p ON p.id = t.payment_id AND p.livemode
+WHERE t.status = 'completed'
+    AND t.created_at >= ? AND t.created_at < ?
+GROUP BY day, t.destination_mint
+ORDER BY day, t.destination_mint;
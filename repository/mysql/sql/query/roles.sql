@@ -0,0 +1,15 @@
+-- name: GetCredentialRole :one
+SELECT * FROM credential_roles WHERE credential = ?;
+
+-- name: GetCredentialRoles :many
+SELECT * FROM credential_roles ORDER BY credential;
+
+-- name: StoreCredentialRole :exec
+INSERT INTO credential_roles (credential, role)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE
+    role = ?,
+    updated_at = CURRENT_TIMESTAMP(6);
+
+-- name: DeleteCredentialRole :execrows
+DELETE FROM credential_roles WHERE credential = ?;
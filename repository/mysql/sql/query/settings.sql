@@ -0,0 +1,23 @@
+-- name: GetMerchantSettings :one
+SELECT * FROM merchant_settings WHERE id = 1;
+
+-- name: StoreMerchantSettings :exec
+INSERT INTO merchant_settings (
+    id,
+    apply_bonus,
+    max_apply_bonus_amount,
+    max_apply_bonus_percent,
+    accrue_bonus_rate,
+    destination_mint,
+    destination_wallet,
+    escrow_release_after
+) VALUES (1, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    apply_bonus = ?,
+    max_apply_bonus_amount = ?,
+    max_apply_bonus_percent = ?,
+    accrue_bonus_rate = ?,
+    destination_mint = ?,
+    destination_wallet = ?,
+    escrow_release_after = ?,
+    updated_at = CURRENT_TIMESTAMP(6);
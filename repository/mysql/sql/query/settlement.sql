@@ -0,0 +1,10 @@
+-- name: CreateSettlement :exec
+INSERT INTO settlements (id, payment_id, input_mint, output_mint, in_amount)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetSettlementByPaymentID :one
+SELECT * FROM settlements WHERE payment_id = ?;
+
+-- name: UpdateSettlement :execrows
+UPDATE settlements SET status = ?, out_amount = ?, tx_signature = ?, error = ?, updated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ?;
@@ -0,0 +1,48 @@
+-- name: CreateSubscriptionPlan :exec
+INSERT INTO subscription_plans (id, name, amount, currency, billing_interval, interval_count)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetSubscriptionPlan :one
+SELECT * FROM subscription_plans WHERE id = ?;
+
+-- name: GetSubscriptionPlans :many
+SELECT * FROM subscription_plans ORDER BY created_at DESC;
+
+-- name: CreateSubscription :exec
+INSERT INTO subscriptions (id, plan_id, customer_email, livemode, next_billing_at)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetSubscription :one
+SELECT * FROM subscriptions WHERE id = ?;
+
+-- name: CancelSubscription :execrows
+UPDATE subscriptions SET status = 'canceled', canceled_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND status = 'active';
+
+-- name: GetDueSubscriptions :many
+SELECT * FROM subscriptions WHERE status = 'active' AND next_billing_at <= ? ORDER BY next_billing_at;
+
+-- name: AdvanceSubscription :execrows
+UPDATE subscriptions SET next_billing_at = ?
+WHERE id = ? AND next_billing_at = ?;
+
+-- name: CreateSubscriptionInstallment :exec
+INSERT INTO subscription_installments (id, subscription_id, due_at)
+VALUES (?, ?, ?);
+
+-- name: GetSubscriptionInstallmentByDueAt :one
+SELECT * FROM subscription_installments WHERE subscription_id = ? AND due_at = ?;
+
+-- name: GetSubscriptionInstallmentByPaymentID :one
+SELECT * FROM subscription_installments WHERE payment_id = ?;
+
+-- name: GetSubscriptionInstallments :many
+SELECT * FROM subscription_installments WHERE subscription_id = ? ORDER BY due_at DESC;
+
+-- name: SetSubscriptionInstallmentPayment :exec
+UPDATE subscription_installments SET payment_id = ?, updated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ?;
+
+-- name: UpdateSubscriptionInstallmentStatus :execrows
+UPDATE subscription_installments SET status = ?, updated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND status = 'due';
@@ -0,0 +1,6 @@
+-- name: AddPaymentEvent :exec
+INSERT INTO payment_events (payment_id, event, data)
+VALUES (?, ?, ?);
+
+-- name: GetPaymentEvents :many
+SELECT * FROM payment_events WHERE payment_id = ? ORDER BY id;
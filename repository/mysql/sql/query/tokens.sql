@@ -0,0 +1,33 @@
+-- name: StoreToken :exec
+INSERT INTO tokens (
+    token_type,
+    credential,
+    access_token_id,
+    refresh_token_id,
+    access_expires_at,
+    refresh_expires_at
+) VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    access_token_id = ?,
+    refresh_token_id = ?,
+    access_expires_at = ?,
+    refresh_expires_at = ?;
+
+-- name: GetTokenByCredential :one
+SELECT * FROM tokens WHERE token_type = ? AND credential = ?;
+
+-- name: GetToken :one
+SELECT * FROM tokens
+WHERE token_type = ?
+AND credential = ?
+AND access_token_id = ?
+AND refresh_token_id = ?;
+
+-- name: DeleteToken :exec
+DELETE FROM tokens WHERE token_type = ? AND credential = ?;
+
+-- name: DeleteExpiredTokens :execrows
+DELETE FROM tokens WHERE refresh_expires_at < CURRENT_TIMESTAMP(6);
+
+-- name: DeleteTokensByCredential :exec
+DELETE FROM tokens WHERE credential = ?;
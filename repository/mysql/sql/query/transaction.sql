@@ -0,0 +1,106 @@
+-- name: CreateTransaction :exec
+INSERT INTO transactions (
+    id,
+    payment_id,
+    reference,
+    source_wallet,
+    source_mint,
+    destination_wallet,
+    destination_mint,
+    amount,
+    discount_amount,
+    total_amount,
+    accrued_bonus_amount,
+    message,
+    memo,
+    apply_bonus,
+    status,
+    network_fee,
+    priority_fee,
+    slippage_fee,
+    voucher_amount,
+    swap_route,
+    exchange_rate,
+    tip_amount,
+    tip_destination
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetTransaction :one
+SELECT * FROM transactions WHERE id = ?;
+
+-- name: GetTransactionByReference :one
+SELECT * FROM transactions
+WHERE reference = sqlc.arg(reference)
+    OR id = (SELECT r.transaction_id FROM transaction_references r WHERE r.reference = sqlc.arg(reference));
+
+-- name: GetTransactionsByPaymentID :many
+SELECT * FROM transactions WHERE payment_id = ? ORDER BY created_at DESC;
+
+-- name: GetTransactionsByStatus :many
+SELECT * FROM transactions
+WHERE status = ?
+ORDER BY created_at, id
+LIMIT ? OFFSET ?;
+
+-- name: GetTransactionsByStatusCreatedBefore :many
+SELECT * FROM transactions
+WHERE status = ? AND created_at < ?
+ORDER BY created_at, id
+LIMIT ? OFFSET ?;
+
+-- name: GetTransactionsByStatusCreatedBetween :many
+SELECT * FROM transactions
+WHERE status = ? AND created_at >= ? AND created_at < ?
+ORDER BY created_at, id
+LIMIT ? OFFSET ?;
+
+-- name: LockTransactionByReference :one
+SELECT id FROM transactions
+WHERE reference = sqlc.arg(reference)
+    OR id = (SELECT r.transaction_id FROM transaction_references r WHERE r.reference = sqlc.arg(reference))
+LIMIT 1
+FOR UPDATE;
+
+-- name: UpdateTransaction :exec
+UPDATE transactions SET tx_signature = ?, status = ? WHERE id = ?;
+
+-- name: GetTransactionByPaymentIDSourceWalletAndMint :one
+SELECT * FROM transactions
+WHERE payment_id = ?
+    AND source_wallet = ?
+    AND source_mint = ?
+    AND status = 'pending'
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetPendingTransactions :many
+SELECT * FROM transactions WHERE status = 'pending';
+
+-- name: GetPendingTransactionReferences :many
+SELECT t.reference FROM transactions t WHERE t.status = 'pending'
+UNION
+SELECT tr.reference FROM transaction_references tr
+JOIN transactions t ON t.id = tr.transaction_id
+WHERE t.status = 'pending';
+
+-- name: MarkTransactionsAsExpired :exec
+UPDATE transactions SET status = 'expired'
+WHERE status = 'pending' AND payment_id IN (
+    SELECT id FROM payments WHERE status = 'expired'
+);
+
+-- name: ExpireOtherPendingTransactions :execrows
+UPDATE transactions SET status = 'expired'
+WHERE payment_id = ? AND id <> ? AND status = 'pending';
+
+-- name: AnyTransactionReferenceExists :one
+SELECT EXISTS(SELECT 1 FROM transactions WHERE reference IN (sqlc.slice('references')))
+    OR EXISTS(SELECT 1 FROM transaction_references WHERE reference IN (sqlc.slice('references')));
+
+-- name: AddTransactionReference :exec
+INSERT INTO transaction_references (transaction_id, reference)
+VALUES (?, ?);
+
+-- name: GetTransactionReferences :many
+SELECT reference FROM transaction_references WHERE transaction_id = ? ORDER BY created_at, reference;
@@ -0,0 +1,16 @@
+-- name: CreateWebhookDestination :exec
+INSERT INTO webhook_destinations (id, url, events, description)
+VALUES (?, ?, ?, ?);
+
+-- name: GetWebhookDestination :one
+SELECT * FROM webhook_destinations WHERE id = ?;
+
+-- name: GetWebhookDestinations :many
+SELECT * FROM webhook_destinations ORDER BY created_at;
+
+-- name: UpdateWebhookDestination :execrows
+UPDATE webhook_destinations SET url = ?, events = ?, description = ?, updated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ?;
+
+-- name: DeleteWebhookDestination :execrows
+DELETE FROM webhook_destinations WHERE id = ?;
@@ -0,0 +1,271 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const (
+	subscriptionPlanColumns        = `id, name, amount, currency, billing_interval, interval_count, created_at`
+	subscriptionColumns            = `id, plan_id, customer_email, status, livemode, next_billing_at, created_at, canceled_at`
+	subscriptionInstallmentColumns = `id, subscription_id, payment_id, due_at, status, created_at, updated_at`
+)
+
+func scanSubscriptionPlan(row scanner) (repository.SubscriptionPlan, error) {
+	var i repository.SubscriptionPlan
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Amount,
+		&i.Currency,
+		&i.BillingInterval,
+		&i.IntervalCount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+func scanSubscription(row scanner) (repository.Subscription, error) {
+	var i repository.Subscription
+	err := row.Scan(
+		&i.ID,
+		&i.PlanID,
+		&i.CustomerEmail,
+		&i.Status,
+		&i.Livemode,
+		&i.NextBillingAt,
+		&i.CreatedAt,
+		&i.CanceledAt,
+	)
+	return i, err
+}
+
+func scanSubscriptionInstallment(row scanner) (repository.SubscriptionInstallment, error) {
+	var i repository.SubscriptionInstallment
+	err := row.Scan(
+		&i.ID,
+		&i.SubscriptionID,
+		&i.PaymentID,
+		&i.DueAt,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const advanceSubscription = `-- name: AdvanceSubscription :execrows
+UPDATE subscriptions SET next_billing_at = ?
+WHERE id = ? AND next_billing_at = ?
+`
+
+func (q *Queries) AdvanceSubscription(ctx context.Context, arg repository.AdvanceSubscriptionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, advanceSubscription, arg.NextBillingAt, arg.ID, arg.DueAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const cancelSubscription = `-- name: CancelSubscription :execrows
+UPDATE subscriptions SET status = 'canceled', canceled_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND status = 'active'
+`
+
+func (q *Queries) CancelSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, error) {
+	result, err := q.db.ExecContext(ctx, cancelSubscription, id)
+	if err != nil {
+		return repository.Subscription{}, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return repository.Subscription{}, err
+	} else if n == 0 {
+		return repository.Subscription{}, sql.ErrNoRows
+	}
+	return q.GetSubscription(ctx, id)
+}
+
+const createSubscription = `-- name: CreateSubscription :exec
+INSERT INTO subscriptions (id, plan_id, customer_email, livemode, next_billing_at)
+VALUES (?, ?, ?, ?, ?)
+`
+
+func (q *Queries) CreateSubscription(ctx context.Context, arg repository.CreateSubscriptionParams) (repository.Subscription, error) {
+	id := uuid.New()
+	if _, err := q.db.ExecContext(ctx, createSubscription, id, arg.PlanID, arg.CustomerEmail, arg.Livemode, arg.NextBillingAt); err != nil {
+		return repository.Subscription{}, err
+	}
+	return q.GetSubscription(ctx, id)
+}
+
+const createSubscriptionInstallment = `-- name: CreateSubscriptionInstallment :exec
+INSERT INTO subscription_installments (id, subscription_id, due_at)
+VALUES (?, ?, ?)
+`
+
+func (q *Queries) CreateSubscriptionInstallment(ctx context.Context, arg repository.CreateSubscriptionInstallmentParams) (repository.SubscriptionInstallment, error) {
+	id := uuid.New()
+	if _, err := q.db.ExecContext(ctx, createSubscriptionInstallment, id, arg.SubscriptionID, arg.DueAt); err != nil {
+		return repository.SubscriptionInstallment{}, uniqueViolation(err)
+	}
+	return scanSubscriptionInstallment(q.db.QueryRowContext(ctx, getSubscriptionInstallment, id))
+}
+
+const createSubscriptionPlan = `-- name: CreateSubscriptionPlan :exec
+INSERT INTO subscription_plans (id, name, amount, currency, billing_interval, interval_count)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+func (q *Queries) CreateSubscriptionPlan(ctx context.Context, arg repository.CreateSubscriptionPlanParams) (repository.SubscriptionPlan, error) {
+	id := uuid.New()
+	if _, err := q.db.ExecContext(ctx, createSubscriptionPlan, id, arg.Name, arg.Amount, arg.Currency, arg.BillingInterval, arg.IntervalCount); err != nil {
+		return repository.SubscriptionPlan{}, err
+	}
+	return q.GetSubscriptionPlan(ctx, id)
+}
+
+const getDueSubscriptions = `-- name: GetDueSubscriptions :many
+SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE status = 'active' AND next_billing_at <= ? ORDER BY next_billing_at
+`
+
+func (q *Queries) GetDueSubscriptions(ctx context.Context, now time.Time) ([]repository.Subscription, error) {
+	rows, err := q.db.QueryContext(ctx, getDueSubscriptions, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.Subscription
+	for rows.Next() {
+		i, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubscription = `-- name: GetSubscription :one
+SELECT ` + subscriptionColumns + ` FROM subscriptions WHERE id = ?
+`
+
+func (q *Queries) GetSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, error) {
+	return scanSubscription(q.db.QueryRowContext(ctx, getSubscription, id))
+}
+
+const getSubscriptionInstallment = `-- name: GetSubscriptionInstallment :one
+SELECT ` + subscriptionInstallmentColumns + ` FROM subscription_installments WHERE id = ?
+`
+
+const getSubscriptionInstallmentByDueAt = `-- name: GetSubscriptionInstallmentByDueAt :one
+SELECT ` + subscriptionInstallmentColumns + ` FROM subscription_installments WHERE subscription_id = ? AND due_at = ?
+`
+
+func (q *Queries) GetSubscriptionInstallmentByDueAt(ctx context.Context, arg repository.GetSubscriptionInstallmentByDueAtParams) (repository.SubscriptionInstallment, error) {
+	return scanSubscriptionInstallment(q.db.QueryRowContext(ctx, getSubscriptionInstallmentByDueAt, arg.SubscriptionID, arg.DueAt))
+}
+
+const getSubscriptionInstallmentByPaymentID = `-- name: GetSubscriptionInstallmentByPaymentID :one
+SELECT ` + subscriptionInstallmentColumns + ` FROM subscription_installments WHERE payment_id = ?
+`
+
+func (q *Queries) GetSubscriptionInstallmentByPaymentID(ctx context.Context, paymentID uuid.NullUUID) (repository.SubscriptionInstallment, error) {
+	return scanSubscriptionInstallment(q.db.QueryRowContext(ctx, getSubscriptionInstallmentByPaymentID, paymentID))
+}
+
+const getSubscriptionInstallments = `-- name: GetSubscriptionInstallments :many
+SELECT ` + subscriptionInstallmentColumns + ` FROM subscription_installments WHERE subscription_id = ? ORDER BY due_at DESC
+`
+
+func (q *Queries) GetSubscriptionInstallments(ctx context.Context, subscriptionID uuid.UUID) ([]repository.SubscriptionInstallment, error) {
+	rows, err := q.db.QueryContext(ctx, getSubscriptionInstallments, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.SubscriptionInstallment
+	for rows.Next() {
+		i, err := scanSubscriptionInstallment(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSubscriptionPlan = `-- name: GetSubscriptionPlan :one
+SELECT ` + subscriptionPlanColumns + ` FROM subscription_plans WHERE id = ?
+`
+
+func (q *Queries) GetSubscriptionPlan(ctx context.Context, id uuid.UUID) (repository.SubscriptionPlan, error) {
+	return scanSubscriptionPlan(q.db.QueryRowContext(ctx, getSubscriptionPlan, id))
+}
+
+const getSubscriptionPlans = `-- name: GetSubscriptionPlans :many
+SELECT ` + subscriptionPlanColumns + ` FROM subscription_plans ORDER BY created_at DESC
+`
+
+func (q *Queries) GetSubscriptionPlans(ctx context.Context) ([]repository.SubscriptionPlan, error) {
+	rows, err := q.db.QueryContext(ctx, getSubscriptionPlans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.SubscriptionPlan
+	for rows.Next() {
+		i, err := scanSubscriptionPlan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setSubscriptionInstallmentPayment = `-- name: SetSubscriptionInstallmentPayment :exec
+UPDATE subscription_installments SET payment_id = ?, updated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ?
+`
+
+func (q *Queries) SetSubscriptionInstallmentPayment(ctx context.Context, arg repository.SetSubscriptionInstallmentPaymentParams) (repository.SubscriptionInstallment, error) {
+	if _, err := q.db.ExecContext(ctx, setSubscriptionInstallmentPayment, arg.PaymentID, arg.ID); err != nil {
+		return repository.SubscriptionInstallment{}, err
+	}
+	return scanSubscriptionInstallment(q.db.QueryRowContext(ctx, getSubscriptionInstallment, arg.ID))
+}
+
+const updateSubscriptionInstallmentStatus = `-- name: UpdateSubscriptionInstallmentStatus :execrows
+UPDATE subscription_installments SET status = ?, updated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ? AND status = 'due'
+`
+
+func (q *Queries) UpdateSubscriptionInstallmentStatus(ctx context.Context, arg repository.UpdateSubscriptionInstallmentStatusParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateSubscriptionInstallmentStatus, arg.Status, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,55 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const addPaymentEvent = `-- name: AddPaymentEvent :exec
+INSERT INTO payment_events (payment_id, event, data)
+VALUES (?, ?, ?)
+`
+
+func (q *Queries) AddPaymentEvent(ctx context.Context, arg repository.AddPaymentEventParams) error {
+	data := arg.Data
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+	_, err := q.db.ExecContext(ctx, addPaymentEvent, arg.PaymentID, arg.Event, data)
+	return err
+}
+
+const getPaymentEvents = `-- name: GetPaymentEvents :many
+SELECT id, payment_id, event, data, created_at FROM payment_events WHERE payment_id = ? ORDER BY id
+`
+
+func (q *Queries) GetPaymentEvents(ctx context.Context, paymentID uuid.UUID) ([]repository.PaymentEvent, error) {
+	rows, err := q.db.QueryContext(ctx, getPaymentEvents, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.PaymentEvent
+	for rows.Next() {
+		var i repository.PaymentEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentID,
+			&i.Event,
+			&i.Data,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
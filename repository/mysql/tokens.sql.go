@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+const tokenColumns = `token_type, credential, access_token_id, refresh_token_id, access_expires_at, refresh_expires_at, updated_at, created_at`
+
+func scanToken(row scanner) (repository.Token, error) {
+	var i repository.Token
+	err := row.Scan(
+		&i.TokenType,
+		&i.Credential,
+		&i.AccessTokenID,
+		&i.RefreshTokenID,
+		&i.AccessExpiresAt,
+		&i.RefreshExpiresAt,
+		&i.UpdatedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const storeToken = `-- name: StoreToken :exec
+INSERT INTO tokens (
+    token_type,
+    credential,
+    access_token_id,
+    refresh_token_id,
+    access_expires_at,
+    refresh_expires_at
+) VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    access_token_id = ?,
+    refresh_token_id = ?,
+    access_expires_at = ?,
+    refresh_expires_at = ?
+`
+
+func (q *Queries) StoreToken(ctx context.Context, arg repository.StoreTokenParams) (repository.Token, error) {
+	if _, err := q.db.ExecContext(ctx, storeToken,
+		arg.TokenType,
+		arg.Credential,
+		arg.AccessTokenID,
+		arg.RefreshTokenID,
+		arg.AccessExpiresAt,
+		arg.RefreshExpiresAt,
+		arg.AccessTokenID,
+		arg.RefreshTokenID,
+		arg.AccessExpiresAt,
+		arg.RefreshExpiresAt,
+	); err != nil {
+		return repository.Token{}, uniqueViolation(err)
+	}
+	return scanToken(q.db.QueryRowContext(ctx, getTokenByCredential, arg.TokenType, arg.Credential))
+}
+
+const getTokenByCredential = `-- name: GetTokenByCredential :one
+SELECT ` + tokenColumns + ` FROM tokens WHERE token_type = ? AND credential = ?
+`
+
+const getToken = `-- name: GetToken :one
+SELECT ` + tokenColumns + ` FROM tokens
+WHERE token_type = ?
+AND credential = ?
+AND access_token_id = ?
+AND refresh_token_id = ?
+`
+
+func (q *Queries) GetToken(ctx context.Context, arg repository.GetTokenParams) (repository.Token, error) {
+	return scanToken(q.db.QueryRowContext(ctx, getToken,
+		arg.TokenType,
+		arg.Credential,
+		arg.AccessTokenID,
+		arg.RefreshTokenID,
+	))
+}
+
+const deleteToken = `-- name: DeleteToken :exec
+DELETE FROM tokens WHERE token_type = ? AND credential = ?
+`
+
+func (q *Queries) DeleteToken(ctx context.Context, arg repository.DeleteTokenParams) error {
+	_, err := q.db.ExecContext(ctx, deleteToken, arg.TokenType, arg.Credential)
+	return err
+}
+
+const deleteExpiredTokens = `-- name: DeleteExpiredTokens :execrows
+DELETE FROM tokens WHERE refresh_expires_at < CURRENT_TIMESTAMP(6)
+`
+
+func (q *Queries) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteTokensByCredential = `-- name: DeleteTokensByCredential :exec
+DELETE FROM tokens WHERE credential = ?
+`
+
+func (q *Queries) DeleteTokensByCredential(ctx context.Context, credential string) error {
+	_, err := q.db.ExecContext(ctx, deleteTokensByCredential, credential)
+	return err
+}
@@ -0,0 +1,361 @@
+package mysql
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const transactionColumns = `id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination`
+
+func scanTransaction(row scanner) (repository.Transaction, error) {
+	var i repository.Transaction
+	err := row.Scan(
+		&i.ID,
+		&i.PaymentID,
+		&i.Reference,
+		&i.SourceWallet,
+		&i.SourceMint,
+		&i.DestinationWallet,
+		&i.DestinationMint,
+		&i.Amount,
+		&i.DiscountAmount,
+		&i.TotalAmount,
+		&i.AccruedBonusAmount,
+		&i.Message,
+		&i.Memo,
+		&i.ApplyBonus,
+		&i.TxSignature,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.NetworkFee,
+		&i.PriorityFee,
+		&i.SlippageFee,
+		&i.VoucherAmount,
+		&i.SwapRoute,
+		&i.ExchangeRate,
+		&i.TipAmount,
+		&i.TipDestination,
+	)
+	return i, err
+}
+
+func (q *Queries) queryTransactions(ctx context.Context, query string, args ...interface{}) ([]repository.Transaction, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.Transaction
+	for rows.Next() {
+		i, err := scanTransaction(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (q *Queries) queryReferences(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var reference string
+		if err := rows.Scan(&reference); err != nil {
+			return nil, err
+		}
+		items = append(items, reference)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createTransaction = `-- name: CreateTransaction :exec
+INSERT INTO transactions (
+    id,
+    payment_id,
+    reference,
+    source_wallet,
+    source_mint,
+    destination_wallet,
+    destination_mint,
+    amount,
+    discount_amount,
+    total_amount,
+    accrued_bonus_amount,
+    message,
+    memo,
+    apply_bonus,
+    status,
+    network_fee,
+    priority_fee,
+    slippage_fee,
+    voucher_amount,
+    swap_route,
+    exchange_rate,
+    tip_amount,
+    tip_destination
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+func (q *Queries) CreateTransaction(ctx context.Context, arg repository.CreateTransactionParams) (repository.Transaction, error) {
+	id := uuid.New()
+	if arg.ID.Valid {
+		id = arg.ID.UUID
+	}
+	swapRoute := arg.SwapRoute
+	if len(swapRoute) == 0 {
+		swapRoute = []byte("{}")
+	}
+	if _, err := q.db.ExecContext(ctx, createTransaction,
+		id,
+		arg.PaymentID,
+		arg.Reference,
+		arg.SourceWallet,
+		arg.SourceMint,
+		arg.DestinationWallet,
+		arg.DestinationMint,
+		arg.Amount,
+		arg.DiscountAmount,
+		arg.TotalAmount,
+		arg.AccruedBonusAmount,
+		arg.Message,
+		arg.Memo,
+		arg.ApplyBonus,
+		arg.Status,
+		arg.NetworkFee,
+		arg.PriorityFee,
+		arg.SlippageFee,
+		arg.VoucherAmount,
+		swapRoute,
+		arg.ExchangeRate,
+		arg.TipAmount,
+		arg.TipDestination,
+	); err != nil {
+		return repository.Transaction{}, uniqueViolation(err)
+	}
+	return q.GetTransaction(ctx, id)
+}
+
+const getTransaction = `-- name: GetTransaction :one
+SELECT ` + transactionColumns + ` FROM transactions WHERE id = ?
+`
+
+func (q *Queries) GetTransaction(ctx context.Context, id uuid.UUID) (repository.Transaction, error) {
+	return scanTransaction(q.db.QueryRowContext(ctx, getTransaction, id))
+}
+
+const getTransactionByReference = `-- name: GetTransactionByReference :one
+SELECT ` + transactionColumns + ` FROM transactions
+WHERE reference = ?
+    OR id = (SELECT r.transaction_id FROM transaction_references r WHERE r.reference = ?)
+`
+
+func (q *Queries) GetTransactionByReference(ctx context.Context, reference string) (repository.Transaction, error) {
+	return scanTransaction(q.db.QueryRowContext(ctx, getTransactionByReference, reference, reference))
+}
+
+const getTransactionsByPaymentID = `-- name: GetTransactionsByPaymentID :many
+SELECT ` + transactionColumns + ` FROM transactions WHERE payment_id = ? ORDER BY created_at DESC
+`
+
+func (q *Queries) GetTransactionsByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]repository.Transaction, error) {
+	return q.queryTransactions(ctx, getTransactionsByPaymentID, paymentID)
+}
+
+const getTransactionsByStatus = `-- name: GetTransactionsByStatus :many
+SELECT ` + transactionColumns + ` FROM transactions
+WHERE status = ?
+ORDER BY created_at, id
+LIMIT ? OFFSET ?
+`
+
+func (q *Queries) GetTransactionsByStatus(ctx context.Context, arg repository.GetTransactionsByStatusParams) ([]repository.Transaction, error) {
+	return q.queryTransactions(ctx, getTransactionsByStatus, arg.Status, arg.Limit, arg.Offset)
+}
+
+const getTransactionsByStatusCreatedBefore = `-- name: GetTransactionsByStatusCreatedBefore :many
+SELECT ` + transactionColumns + ` FROM transactions
+WHERE status = ? AND created_at < ?
+ORDER BY created_at, id
+LIMIT ? OFFSET ?
+`
+
+func (q *Queries) GetTransactionsByStatusCreatedBefore(ctx context.Context, arg repository.GetTransactionsByStatusCreatedBeforeParams) ([]repository.Transaction, error) {
+	return q.queryTransactions(ctx, getTransactionsByStatusCreatedBefore,
+		arg.Status,
+		arg.CreatedBefore,
+		arg.Limit,
+		arg.Offset,
+	)
+}
+
+const getTransactionsByStatusCreatedBetween = `-- name: GetTransactionsByStatusCreatedBetween :many
+SELECT ` + transactionColumns + ` FROM transactions
+WHERE status = ? AND created_at >= ? AND created_at < ?
+ORDER BY created_at, id
+LIMIT ? OFFSET ?
+`
+
+func (q *Queries) GetTransactionsByStatusCreatedBetween(ctx context.Context, arg repository.GetTransactionsByStatusCreatedBetweenParams) ([]repository.Transaction, error) {
+	return q.queryTransactions(ctx, getTransactionsByStatusCreatedBetween,
+		arg.Status,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.Limit,
+		arg.Offset,
+	)
+}
+
+const lockTransactionByReference = `-- name: LockTransactionByReference :one
+SELECT id FROM transactions
+WHERE reference = ?
+    OR id = (SELECT r.transaction_id FROM transaction_references r WHERE r.reference = ?)
+LIMIT 1
+FOR UPDATE
+`
+
+const updateTransaction = `-- name: UpdateTransaction :exec
+UPDATE transactions SET tx_signature = ?, status = ? WHERE id = ?
+`
+
+// UpdateTransactionByReference updates the transaction found by any of its references.
+// Returns sql.ErrNoRows if there is no such transaction.
+func (q *Queries) UpdateTransactionByReference(ctx context.Context, arg repository.UpdateTransactionByReferenceParams) (repository.Transaction, error) {
+	var result repository.Transaction
+	err := q.inTx(ctx, func(q *Queries) error {
+		var id uuid.UUID
+		if err := q.db.QueryRowContext(ctx, lockTransactionByReference, arg.Reference, arg.Reference).Scan(&id); err != nil {
+			return err
+		}
+		if _, err := q.db.ExecContext(ctx, updateTransaction, arg.TxSignature, arg.Status, id); err != nil {
+			return err
+		}
+
+		var err error
+		result, err = q.GetTransaction(ctx, id)
+		return err
+	})
+	return result, err
+}
+
+const getTransactionByPaymentIDSourceWalletAndMint = `-- name: GetTransactionByPaymentIDSourceWalletAndMint :one
+SELECT ` + transactionColumns + ` FROM transactions
+WHERE payment_id = ?
+    AND source_wallet = ?
+    AND source_mint = ?
+    AND status = 'pending'
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetTransactionByPaymentIDSourceWalletAndMint(ctx context.Context, arg repository.GetTransactionByPaymentIDSourceWalletAndMintParams) (repository.Transaction, error) {
+	return scanTransaction(q.db.QueryRowContext(ctx, getTransactionByPaymentIDSourceWalletAndMint,
+		arg.PaymentID,
+		arg.SourceWallet,
+		arg.SourceMint,
+	))
+}
+
+const getPendingTransactions = `-- name: GetPendingTransactions :many
+SELECT ` + transactionColumns + ` FROM transactions WHERE status = 'pending'
+`
+
+func (q *Queries) GetPendingTransactions(ctx context.Context) ([]repository.Transaction, error) {
+	return q.queryTransactions(ctx, getPendingTransactions)
+}
+
+const getPendingTransactionReferences = `-- name: GetPendingTransactionReferences :many
+SELECT t.reference FROM transactions t WHERE t.status = 'pending'
+UNION
+SELECT tr.reference FROM transaction_references tr
+JOIN transactions t ON t.id = tr.transaction_id
+WHERE t.status = 'pending'
+`
+
+func (q *Queries) GetPendingTransactionReferences(ctx context.Context) ([]string, error) {
+	return q.queryReferences(ctx, getPendingTransactionReferences)
+}
+
+const markTransactionsAsExpired = `-- name: MarkTransactionsAsExpired :exec
+UPDATE transactions SET status = 'expired'
+WHERE status = 'pending' AND payment_id IN (
+    SELECT id FROM payments WHERE status = 'expired'
+)
+`
+
+func (q *Queries) MarkTransactionsAsExpired(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, markTransactionsAsExpired)
+	return err
+}
+
+const expireOtherPendingTransactions = `-- name: ExpireOtherPendingTransactions :execrows
+UPDATE transactions SET status = 'expired'
+WHERE payment_id = ? AND id <> ? AND status = 'pending'
+`
+
+func (q *Queries) ExpireOtherPendingTransactions(ctx context.Context, arg repository.ExpireOtherPendingTransactionsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, expireOtherPendingTransactions, arg.PaymentID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const anyTransactionReferenceExists = `-- name: AnyTransactionReferenceExists :one
+SELECT EXISTS(SELECT 1 FROM transactions WHERE reference IN (/*SLICE:references*/?))
+    OR EXISTS(SELECT 1 FROM transaction_references WHERE reference IN (/*SLICE:references*/?))
+`
+
+func (q *Queries) AnyTransactionReferenceExists(ctx context.Context, references []string) (bool, error) {
+	// the references are bound twice: for the transactions and for the additional references
+	args := make([]interface{}, 0, 2*len(references))
+	for i := 0; i < 2; i++ {
+		for _, reference := range references {
+			args = append(args, reference)
+		}
+	}
+
+	query := expandSlice(anyTransactionReferenceExists, "references", len(references))
+	row := q.db.QueryRowContext(ctx, query, args...)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const addTransactionReference = `-- name: AddTransactionReference :exec
+INSERT INTO transaction_references (transaction_id, reference)
+VALUES (?, ?)
+`
+
+func (q *Queries) AddTransactionReference(ctx context.Context, arg repository.AddTransactionReferenceParams) error {
+	_, err := q.db.ExecContext(ctx, addTransactionReference, arg.TransactionID, arg.Reference)
+	return uniqueViolation(err)
+}
+
+const getTransactionReferences = `-- name: GetTransactionReferences :many
+SELECT reference FROM transaction_references WHERE transaction_id = ? ORDER BY created_at, reference
+`
+
+func (q *Queries) GetTransactionReferences(ctx context.Context, transactionID uuid.UUID) ([]string, error) {
+	return q.queryReferences(ctx, getTransactionReferences, transactionID)
+}
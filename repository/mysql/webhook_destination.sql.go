@@ -0,0 +1,102 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+const webhookDestinationColumns = `id, url, events, description, created_at, updated_at`
+
+func scanWebhookDestination(row scanner) (repository.WebhookDestination, error) {
+	var i repository.WebhookDestination
+	err := row.Scan(
+		&i.ID,
+		&i.URL,
+		&i.Events,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createWebhookDestination = `-- name: CreateWebhookDestination :exec
+INSERT INTO webhook_destinations (id, url, events, description)
+VALUES (?, ?, ?, ?)
+`
+
+func (q *Queries) CreateWebhookDestination(ctx context.Context, arg repository.CreateWebhookDestinationParams) (repository.WebhookDestination, error) {
+	id := uuid.New()
+	if _, err := q.db.ExecContext(ctx, createWebhookDestination, id, arg.URL, arg.Events, arg.Description); err != nil {
+		return repository.WebhookDestination{}, err
+	}
+	return q.GetWebhookDestination(ctx, id)
+}
+
+const getWebhookDestination = `-- name: GetWebhookDestination :one
+SELECT ` + webhookDestinationColumns + ` FROM webhook_destinations WHERE id = ?
+`
+
+func (q *Queries) GetWebhookDestination(ctx context.Context, id uuid.UUID) (repository.WebhookDestination, error) {
+	return scanWebhookDestination(q.db.QueryRowContext(ctx, getWebhookDestination, id))
+}
+
+const getWebhookDestinations = `-- name: GetWebhookDestinations :many
+SELECT ` + webhookDestinationColumns + ` FROM webhook_destinations ORDER BY created_at
+`
+
+func (q *Queries) GetWebhookDestinations(ctx context.Context) ([]repository.WebhookDestination, error) {
+	rows, err := q.db.QueryContext(ctx, getWebhookDestinations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []repository.WebhookDestination
+	for rows.Next() {
+		i, err := scanWebhookDestination(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateWebhookDestination = `-- name: UpdateWebhookDestination :execrows
+UPDATE webhook_destinations SET url = ?, events = ?, description = ?, updated_at = CURRENT_TIMESTAMP(6)
+WHERE id = ?
+`
+
+func (q *Queries) UpdateWebhookDestination(ctx context.Context, arg repository.UpdateWebhookDestinationParams) (repository.WebhookDestination, error) {
+	result, err := q.db.ExecContext(ctx, updateWebhookDestination, arg.URL, arg.Events, arg.Description, arg.ID)
+	if err != nil {
+		return repository.WebhookDestination{}, err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return repository.WebhookDestination{}, err
+	} else if n == 0 {
+		return repository.WebhookDestination{}, sql.ErrNoRows
+	}
+	return q.GetWebhookDestination(ctx, arg.ID)
+}
+
+const deleteWebhookDestination = `-- name: DeleteWebhookDestination :execrows
+DELETE FROM webhook_destinations WHERE id = ?
+`
+
+func (q *Queries) DeleteWebhookDestination(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWebhookDestination, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
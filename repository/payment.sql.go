@@ -8,6 +8,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -20,7 +22,17 @@ INSERT INTO payments (
     amount, 
     status, 
     message, 
-    expires_at
+    expires_at,
+    customer_email,
+    translations,
+    fee_on_top,
+    escrow,
+    livemode,
+    callback_url,
+    allow_partial,
+    fiat_currency,
+    fiat_amount,
+    settings
 ) 
 VALUES (
     $1, 
@@ -29,19 +41,39 @@ VALUES (
     $4, 
     $5, 
     $6, 
-    $7
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14,
+    $15,
+    $16,
+    $17
 )
-RETURNING id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at
+RETURNING id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings
 `
 
 type CreatePaymentParams struct {
-	ExternalID        sql.NullString `json:"external_id"`
-	DestinationWallet string         `json:"destination_wallet"`
-	DestinationMint   string         `json:"destination_mint"`
-	Amount            int64          `json:"amount"`
-	Status            PaymentStatus  `json:"status"`
-	Message           sql.NullString `json:"message"`
-	ExpiresAt         sql.NullTime   `json:"expires_at"`
+	ExternalID        sql.NullString  `json:"external_id"`
+	DestinationWallet string          `json:"destination_wallet"`
+	DestinationMint   string          `json:"destination_mint"`
+	Amount            int64           `json:"amount"`
+	Status            PaymentStatus   `json:"status"`
+	Message           sql.NullString  `json:"message"`
+	ExpiresAt         sql.NullTime    `json:"expires_at"`
+	CustomerEmail     sql.NullString  `json:"customer_email"`
+	Translations      json.RawMessage `json:"translations"`
+	FeeOnTop          bool            `json:"fee_on_top"`
+	Escrow            bool            `json:"escrow"`
+	Livemode          bool            `json:"livemode"`
+	CallbackUrl       sql.NullString  `json:"callback_url"`
+	AllowPartial      bool            `json:"allow_partial"`
+	FiatCurrency      sql.NullString  `json:"fiat_currency"`
+	FiatAmount        int64           `json:"fiat_amount"`
+	Settings          json.RawMessage `json:"settings"`
 }
 
 func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
@@ -53,6 +85,16 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		arg.Status,
 		arg.Message,
 		arg.ExpiresAt,
+		arg.CustomerEmail,
+		arg.Translations,
+		arg.FeeOnTop,
+		arg.Escrow,
+		arg.Livemode,
+		arg.CallbackUrl,
+		arg.AllowPartial,
+		arg.FiatCurrency,
+		arg.FiatAmount,
+		arg.Settings,
 	)
 	var i Payment
 	err := row.Scan(
@@ -66,12 +108,24 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
 	)
 	return i, err
 }
 
 const getPayment = `-- name: GetPayment :one
-SELECT id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at FROM payments WHERE id = $1
+SELECT id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings FROM payments WHERE id = $1
 `
 
 func (q *Queries) GetPayment(ctx context.Context, id uuid.UUID) (Payment, error) {
@@ -88,12 +142,24 @@ func (q *Queries) GetPayment(ctx context.Context, id uuid.UUID) (Payment, error)
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
 	)
 	return i, err
 }
 
 const getPaymentByExternalID = `-- name: GetPaymentByExternalID :one
-SELECT id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at FROM payments WHERE external_id = $1::VARCHAR
+SELECT id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings FROM payments WHERE external_id = $1::VARCHAR
 `
 
 func (q *Queries) GetPaymentByExternalID(ctx context.Context, externalID string) (Payment, error) {
@@ -110,21 +176,261 @@ func (q *Queries) GetPaymentByExternalID(ctx context.Context, externalID string)
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
+	)
+	return i, err
+}
+
+const getPaymentStatus = `-- name: GetPaymentStatus :one
+SELECT p.status, COALESCE(p.updated_at, p.created_at)::TIMESTAMP AS updated_at,
+    COALESCE((
+        SELECT t.tx_signature FROM transactions t
+        WHERE t.payment_id = p.id AND t.tx_signature IS NOT NULL AND t.tx_signature <> ''
+        ORDER BY t.updated_at DESC NULLS LAST, t.created_at DESC
+        LIMIT 1
+    ), '')::VARCHAR AS tx_signature
+FROM payments p WHERE p.id = $1
+`
+
+type GetPaymentStatusRow struct {
+	Status      PaymentStatus `json:"status"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+	TxSignature string        `json:"tx_signature"`
+}
+
+func (q *Queries) GetPaymentStatus(ctx context.Context, id uuid.UUID) (GetPaymentStatusRow, error) {
+	row := q.queryRow(ctx, q.getPaymentStatusStmt, getPaymentStatus, id)
+	var i GetPaymentStatusRow
+	err := row.Scan(
+		&i.Status,
+		&i.UpdatedAt,
+		&i.TxSignature,
+	)
+	return i, err
+}
+
+const getPaymentsToRelease = `-- name: GetPaymentsToRelease :many
+SELECT id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings FROM payments WHERE status = 'held'::payment_status AND held_until < NOW() ORDER BY held_until
+`
+
+func (q *Queries) GetPaymentsToRelease(ctx context.Context) ([]Payment, error) {
+	rows, err := q.query(ctx, q.getPaymentsToReleaseStmt, getPaymentsToRelease)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.ExternalID,
+			&i.DestinationWallet,
+			&i.DestinationMint,
+			&i.Amount,
+			&i.Status,
+			&i.Message,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CustomerEmail,
+			&i.Translations,
+			&i.FeeOnTop,
+			&i.Escrow,
+			&i.HeldUntil,
+			&i.Livemode,
+			&i.CallbackUrl,
+			&i.AllowPartial,
+			&i.AmountPaid,
+			&i.FiatCurrency,
+			&i.FiatAmount,
+			&i.Settings,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const holdPayment = `-- name: HoldPayment :one
+UPDATE payments SET status = 'held'::payment_status, held_until = $1 WHERE id = $2 RETURNING id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings
+`
+
+type HoldPaymentParams struct {
+	HeldUntil sql.NullTime `json:"held_until"`
+	ID        uuid.UUID    `json:"id"`
+}
+
+func (q *Queries) HoldPayment(ctx context.Context, arg HoldPaymentParams) (Payment, error) {
+	row := q.queryRow(ctx, q.holdPaymentStmt, holdPayment, arg.HeldUntil, arg.ID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.DestinationWallet,
+		&i.DestinationMint,
+		&i.Amount,
+		&i.Status,
+		&i.Message,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
 	)
 	return i, err
 }
 
 const markPaymentsExpired = `-- name: MarkPaymentsExpired :exec
-UPDATE payments SET status = 'expired'::payment_status WHERE expires_at < NOW() AND status = 'new'::payment_status
+UPDATE payments SET status = 'expired'::payment_status WHERE expires_at < $1 AND status = 'new'::payment_status
 `
 
-func (q *Queries) MarkPaymentsExpired(ctx context.Context) error {
-	_, err := q.exec(ctx, q.markPaymentsExpiredStmt, markPaymentsExpired)
+func (q *Queries) MarkPaymentsExpired(ctx context.Context, expiredBefore time.Time) error {
+	_, err := q.exec(ctx, q.markPaymentsExpiredStmt, markPaymentsExpired, expiredBefore)
 	return err
 }
 
+const refreshPaymentAmountPaid = `-- name: RefreshPaymentAmountPaid :one
+UPDATE payments SET amount_paid = (
+    SELECT COALESCE(SUM(t.amount), 0)::BIGINT FROM transactions t
+    WHERE t.payment_id = $1 AND t.status = 'completed'::transaction_status
+)
+WHERE id = $1 RETURNING id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings
+`
+
+func (q *Queries) RefreshPaymentAmountPaid(ctx context.Context, id uuid.UUID) (Payment, error) {
+	row := q.queryRow(ctx, q.refreshPaymentAmountPaidStmt, refreshPaymentAmountPaid, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.DestinationWallet,
+		&i.DestinationMint,
+		&i.Amount,
+		&i.Status,
+		&i.Message,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
+	)
+	return i, err
+}
+
+const releasePayment = `-- name: ReleasePayment :one
+UPDATE payments SET status = 'released'::payment_status WHERE id = $1 AND status = 'held'::payment_status RETURNING id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings
+`
+
+func (q *Queries) ReleasePayment(ctx context.Context, id uuid.UUID) (Payment, error) {
+	row := q.queryRow(ctx, q.releasePaymentStmt, releasePayment, id)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.DestinationWallet,
+		&i.DestinationMint,
+		&i.Amount,
+		&i.Status,
+		&i.Message,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
+	)
+	return i, err
+}
+
+const updatePaymentAmount = `-- name: UpdatePaymentAmount :one
+UPDATE payments SET amount = $1 WHERE id = $2 RETURNING id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings
+`
+
+type UpdatePaymentAmountParams struct {
+	Amount int64     `json:"amount"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) UpdatePaymentAmount(ctx context.Context, arg UpdatePaymentAmountParams) (Payment, error) {
+	row := q.queryRow(ctx, q.updatePaymentAmountStmt, updatePaymentAmount, arg.Amount, arg.ID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.ExternalID,
+		&i.DestinationWallet,
+		&i.DestinationMint,
+		&i.Amount,
+		&i.Status,
+		&i.Message,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
+	)
+	return i, err
+}
+
 const updatePaymentStatus = `-- name: UpdatePaymentStatus :one
-UPDATE payments SET status = $1 WHERE id = $2 RETURNING id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at
+UPDATE payments SET status = $1 WHERE id = $2 RETURNING id, external_id, destination_wallet, destination_mint, amount, status, message, expires_at, created_at, updated_at, customer_email, translations, fee_on_top, escrow, held_until, livemode, callback_url, allow_partial, amount_paid, fiat_currency, fiat_amount, settings
 `
 
 type UpdatePaymentStatusParams struct {
@@ -146,6 +452,18 @@ func (q *Queries) UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStat
 		&i.ExpiresAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerEmail,
+		&i.Translations,
+		&i.FeeOnTop,
+		&i.Escrow,
+		&i.HeldUntil,
+		&i.Livemode,
+		&i.CallbackUrl,
+		&i.AllowPartial,
+		&i.AmountPaid,
+		&i.FiatCurrency,
+		&i.FiatAmount,
+		&i.Settings,
 	)
 	return i, err
 }
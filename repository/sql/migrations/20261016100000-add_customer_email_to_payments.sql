@@ -0,0 +1,10 @@
+
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS customer_email VARCHAR DEFAULT NULL;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE payments DROP COLUMN IF EXISTS customer_email;
+-- +migrate StatementEnd
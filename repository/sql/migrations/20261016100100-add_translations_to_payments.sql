@@ -0,0 +1,10 @@
+
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS translations JSONB NOT NULL DEFAULT '{}'::JSONB;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE payments DROP COLUMN IF EXISTS translations;
+-- +migrate StatementEnd
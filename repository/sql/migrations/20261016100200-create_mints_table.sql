@@ -0,0 +1,14 @@
+
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS mints (
+    address VARCHAR PRIMARY KEY,
+    decimals SMALLINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS mints;
+-- +migrate StatementEnd
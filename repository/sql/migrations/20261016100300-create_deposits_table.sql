@@ -0,0 +1,19 @@
+
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS deposits (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    wallet VARCHAR NOT NULL,
+    mint VARCHAR NOT NULL,
+    amount BIGINT NOT NULL,
+    source_wallet VARCHAR DEFAULT NULL,
+    tx_signature VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX deposits_tx_signature_mint ON deposits USING BTREE (tx_signature, mint);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS deposits;
+-- +migrate StatementEnd
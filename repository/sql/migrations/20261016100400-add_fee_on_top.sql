@@ -0,0 +1,16 @@
+
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS fee_on_top BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS network_fee BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS priority_fee BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS slippage_fee BIGINT NOT NULL DEFAULT 0;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE transactions DROP COLUMN IF EXISTS slippage_fee;
+ALTER TABLE transactions DROP COLUMN IF EXISTS priority_fee;
+ALTER TABLE transactions DROP COLUMN IF EXISTS network_fee;
+ALTER TABLE payments DROP COLUMN IF EXISTS fee_on_top;
+-- +migrate StatementEnd
@@ -0,0 +1,15 @@
+
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS payment_funnel_events (
+    payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    stage VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    PRIMARY KEY (payment_id, stage)
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS payment_funnel_events;
+-- +migrate StatementEnd
@@ -0,0 +1,10 @@
+
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS voucher_amount BIGINT NOT NULL DEFAULT 0;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE transactions DROP COLUMN IF EXISTS voucher_amount;
+-- +migrate StatementEnd
@@ -0,0 +1,20 @@
+
+-- +migrate Up notransaction
+-- +migrate StatementBegin
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'held';
+-- +migrate StatementEnd
+-- +migrate StatementBegin
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'released';
+-- +migrate StatementEnd
+-- +migrate StatementBegin
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS escrow BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS held_until TIMESTAMP DEFAULT NULL;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+-- enum values can't be dropped, so escrowed payments fall back to the completed status
+UPDATE payments SET status = 'completed'::payment_status WHERE status IN ('held', 'released');
+ALTER TABLE payments DROP COLUMN IF EXISTS held_until;
+ALTER TABLE payments DROP COLUMN IF EXISTS escrow;
+-- +migrate StatementEnd
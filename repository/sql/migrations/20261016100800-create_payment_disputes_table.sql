@@ -0,0 +1,26 @@
+-- +migrate Up notransaction
+-- +migrate StatementBegin
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'disputed';
+-- +migrate StatementEnd
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS payment_disputes (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    reason VARCHAR NOT NULL,
+    evidence_urls JSONB NOT NULL DEFAULT '[]',
+    previous_status payment_status NOT NULL,
+    resolution VARCHAR DEFAULT NULL,
+    resolved_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE UNIQUE INDEX IF NOT EXISTS payment_disputes_open_idx ON payment_disputes (payment_id) WHERE resolved_at IS NULL;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+-- enum values can't be dropped, so disputed payments fall back to the status they had before the dispute
+UPDATE payments p SET status = d.previous_status
+FROM payment_disputes d
+WHERE d.payment_id = p.id AND d.resolved_at IS NULL AND p.status = 'disputed';
+DROP TABLE IF EXISTS payment_disputes;
+-- +migrate StatementEnd
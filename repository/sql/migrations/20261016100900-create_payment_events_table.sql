@@ -0,0 +1,16 @@
+-- +migrate Up
+-- +migrate StatementBegin
+CREATE TABLE IF NOT EXISTS payment_events (
+    id BIGSERIAL PRIMARY KEY,
+    payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    event VARCHAR NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS payment_events_payment_id_idx ON payment_events (payment_id, id);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS payment_events;
+-- +migrate StatementEnd
@@ -0,0 +1,15 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- additional reference keys of the transactions; the primary one is stored in transactions.reference
+CREATE TABLE IF NOT EXISTS transaction_references (
+    reference VARCHAR PRIMARY KEY,
+    transaction_id uuid NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS transaction_references_transaction_id_idx ON transaction_references (transaction_id);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS transaction_references;
+-- +migrate StatementEnd
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- lists the transactions by status and age for the reconciliation, cleanup and admin tooling
+CREATE INDEX IF NOT EXISTS transactions_status_created_at ON transactions USING BTREE (status, created_at, id);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP INDEX IF EXISTS transactions_status_created_at;
+-- +migrate StatementEnd
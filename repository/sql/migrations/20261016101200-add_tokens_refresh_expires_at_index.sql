@@ -0,0 +1,10 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the expired tokens are purged periodically
+CREATE INDEX IF NOT EXISTS tokens_refresh_expires_at ON tokens USING BTREE (refresh_expires_at);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP INDEX IF EXISTS tokens_refresh_expires_at;
+-- +migrate StatementEnd
@@ -0,0 +1,35 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- creates the monthly range partitions of the parent table covering the given period;
+-- does nothing if the table is not partitioned, so it's safe to call on any deployment
+CREATE OR REPLACE FUNCTION create_monthly_partitions(parent TEXT, from_date TIMESTAMP, to_date TIMESTAMP) RETURNS INTEGER AS $$
+DECLARE
+    month_start TIMESTAMP := date_trunc('month', from_date);
+    partition_name TEXT;
+    created INTEGER := 0;
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_partitioned_table pt JOIN pg_class c ON c.oid = pt.partrelid WHERE c.relname = parent) THEN
+        RETURN 0;
+    END IF;
+
+    WHILE month_start <= to_date LOOP
+        partition_name := parent || '_p' || to_char(month_start, 'YYYYMM');
+        IF to_regclass(partition_name) IS NULL THEN
+            EXECUTE format(
+                'CREATE TABLE %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+                partition_name, parent, month_start, month_start + INTERVAL '1 month'
+            );
+            created := created + 1;
+        END IF;
+        month_start := month_start + INTERVAL '1 month';
+    END LOOP;
+
+    RETURN created;
+END;
+$$ LANGUAGE 'plpgsql';
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP FUNCTION IF EXISTS create_monthly_partitions(TEXT, TIMESTAMP, TIMESTAMP);
+-- +migrate StatementEnd
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the runtime merchant settings; a single row, the env config is used until it's stored
+CREATE TABLE IF NOT EXISTS merchant_settings (
+    id SMALLINT PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    apply_bonus BOOLEAN NOT NULL,
+    max_apply_bonus_amount BIGINT NOT NULL,
+    max_apply_bonus_percent INTEGER NOT NULL,
+    accrue_bonus_rate BIGINT NOT NULL,
+    destination_mint VARCHAR NOT NULL,
+    destination_wallet VARCHAR NOT NULL,
+    escrow_release_after BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS merchant_settings;
+-- +migrate StatementEnd
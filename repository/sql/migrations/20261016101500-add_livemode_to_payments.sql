@@ -0,0 +1,10 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- test-mode payments are created with the test OAuth client and excluded from the reports
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS livemode BOOLEAN NOT NULL DEFAULT true;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE payments DROP COLUMN IF EXISTS livemode;
+-- +migrate StatementEnd
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- per-payment webhook endpoint, it receives the payment events in addition to the merchant webhook
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS callback_url VARCHAR;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE payments DROP COLUMN IF EXISTS callback_url;
+-- +migrate StatementEnd
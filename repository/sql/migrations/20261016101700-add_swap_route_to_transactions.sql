@@ -0,0 +1,10 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- summary of the jupiter swap route the transaction was built with, empty if no swap is needed
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS swap_route JSONB NOT NULL DEFAULT '{}'::JSONB;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE transactions DROP COLUMN IF EXISTS swap_route;
+-- +migrate StatementEnd
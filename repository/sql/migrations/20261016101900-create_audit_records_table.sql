@@ -0,0 +1,23 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the authenticated API calls; the payload itself is not stored, only its hash
+CREATE TABLE IF NOT EXISTS audit_records (
+    id BIGSERIAL PRIMARY KEY,
+    client_id VARCHAR NOT NULL,
+    method VARCHAR NOT NULL,
+    route VARCHAR NOT NULL,
+    path VARCHAR NOT NULL,
+    payload_hash VARCHAR NOT NULL DEFAULT '',
+    status_code INTEGER NOT NULL,
+    latency_us BIGINT NOT NULL,
+    request_id VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS audit_records_created_at_idx ON audit_records (created_at);
+CREATE INDEX IF NOT EXISTS audit_records_client_id_idx ON audit_records (client_id, created_at);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS audit_records;
+-- +migrate StatementEnd
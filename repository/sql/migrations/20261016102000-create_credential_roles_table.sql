@@ -0,0 +1,15 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the roles of the API credentials, e.g. the OAuth2 client ids; the credentials without a role get the default one
+CREATE TABLE IF NOT EXISTS credential_roles (
+    credential VARCHAR PRIMARY KEY,
+    role VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS credential_roles;
+-- +migrate StatementEnd
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the dedicated deposit addresses of the payments, e.g. for the exchange withdrawals which strip the reference accounts;
+-- the transaction_id has no foreign key, so the transactions table can be partitioned
+CREATE TABLE IF NOT EXISTS payment_deposit_addresses (
+    payment_id uuid PRIMARY KEY REFERENCES payments(id) ON DELETE CASCADE,
+    transaction_id uuid NOT NULL,
+    wallet VARCHAR NOT NULL,
+    token_account VARCHAR NOT NULL,
+    mint VARCHAR NOT NULL,
+    amount BIGINT NOT NULL,
+    sweep_signature VARCHAR DEFAULT NULL,
+    swept_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS payment_deposit_addresses_unswept_idx ON payment_deposit_addresses (transaction_id) WHERE swept_at IS NULL;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS payment_deposit_addresses;
+-- +migrate StatementEnd
@@ -0,0 +1,17 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the single-use access tokens of the public checkout links, only the sha256 hash of the token is stored
+CREATE TABLE IF NOT EXISTS payment_link_tokens (
+    token_hash VARCHAR PRIMARY KEY,
+    payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS payment_link_tokens_expires_at_idx ON payment_link_tokens (expires_at);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS payment_link_tokens;
+-- +migrate StatementEnd
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the asynchronous report exports, the file is written to the report storage by the worker
+CREATE TABLE IF NOT EXISTS report_jobs (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    from_date TIMESTAMP NOT NULL,
+    to_date TIMESTAMP NOT NULL,
+    file_key VARCHAR DEFAULT NULL,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    error VARCHAR DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    started_at TIMESTAMP DEFAULT NULL,
+    completed_at TIMESTAMP DEFAULT NULL
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS report_jobs;
+-- +migrate StatementEnd
@@ -0,0 +1,19 @@
+-- +migrate Up notransaction
+-- +migrate StatementBegin
+ALTER TYPE payment_status ADD VALUE IF NOT EXISTS 'partially_paid';
+-- +migrate StatementEnd
+-- +migrate StatementBegin
+-- the partial payments accept several transactions until the amount is paid,
+-- amount_paid is the sum of the completed transactions of the payment
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS allow_partial BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS amount_paid BIGINT NOT NULL DEFAULT 0;
+UPDATE payments SET amount_paid = amount WHERE status IN ('completed', 'held', 'released', 'disputed');
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+-- enum values can't be dropped, so the partially paid payments fall back to pending
+UPDATE payments SET status = 'pending' WHERE status = 'partially_paid';
+ALTER TABLE payments DROP COLUMN IF EXISTS amount_paid;
+ALTER TABLE payments DROP COLUMN IF EXISTS allow_partial;
+-- +migrate StatementEnd
@@ -0,0 +1,43 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the recurring billing plans, the subscriptions bill the plan amount every interval
+CREATE TABLE IF NOT EXISTS subscription_plans (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR NOT NULL,
+    amount BIGINT NOT NULL,
+    currency VARCHAR NOT NULL,
+    billing_interval VARCHAR NOT NULL,
+    interval_count INT NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    plan_id uuid NOT NULL REFERENCES subscription_plans(id),
+    customer_email VARCHAR DEFAULT NULL,
+    status VARCHAR NOT NULL DEFAULT 'active',
+    livemode BOOLEAN NOT NULL DEFAULT true,
+    next_billing_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    canceled_at TIMESTAMP DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS subscriptions_status_next_billing_at_idx ON subscriptions (status, next_billing_at);
+-- the installments of the subscriptions, each one is paid with its own payment
+CREATE TABLE IF NOT EXISTS subscription_installments (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id uuid NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    payment_id uuid DEFAULT NULL REFERENCES payments(id) ON DELETE SET NULL,
+    due_at TIMESTAMP NOT NULL,
+    status VARCHAR NOT NULL DEFAULT 'due',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS subscription_installments_subscription_id_due_at_idx ON subscription_installments (subscription_id, due_at);
+CREATE INDEX IF NOT EXISTS subscription_installments_payment_id_idx ON subscription_installments (payment_id);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS subscription_installments;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS subscription_plans;
+-- +migrate StatementEnd
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the fiat-priced payments are converted to the destination mint when the transaction is built,
+-- fiat_amount is in the minor units of the fiat currency, e.g. cents
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS fiat_currency VARCHAR(3) DEFAULT NULL;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS fiat_amount BIGINT NOT NULL DEFAULT 0;
+-- the price of the whole token in the fiat currency the transaction amount was converted at
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS exchange_rate NUMERIC(36, 18) DEFAULT NULL;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE transactions DROP COLUMN IF EXISTS exchange_rate;
+ALTER TABLE payments DROP COLUMN IF EXISTS fiat_amount;
+ALTER TABLE payments DROP COLUMN IF EXISTS fiat_currency;
+-- +migrate StatementEnd
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the optional tip paid on top of the total amount, in the destination mint,
+-- transferred to the tip destination wallet by a separate instruction
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tip_amount BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tip_destination VARCHAR DEFAULT NULL;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE transactions DROP COLUMN IF EXISTS tip_destination;
+ALTER TABLE transactions DROP COLUMN IF EXISTS tip_amount;
+-- +migrate StatementEnd
@@ -0,0 +1,10 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the merchant settings overridden for the payment only, NULL if the payment uses the merchant settings
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS settings JSONB DEFAULT NULL;
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE payments DROP COLUMN IF EXISTS settings;
+-- +migrate StatementEnd
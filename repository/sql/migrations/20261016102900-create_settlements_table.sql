@@ -0,0 +1,23 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the swaps of the payment funds received in another mint into the settlement mint
+CREATE TABLE IF NOT EXISTS settlements (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    payment_id uuid NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    input_mint VARCHAR NOT NULL,
+    output_mint VARCHAR NOT NULL,
+    in_amount BIGINT NOT NULL,
+    out_amount BIGINT NOT NULL DEFAULT 0,
+    status VARCHAR NOT NULL DEFAULT 'pending',
+    tx_signature VARCHAR DEFAULT NULL,
+    error TEXT DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS settlements_payment_id_idx ON settlements (payment_id);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS settlements;
+-- +migrate StatementEnd
@@ -0,0 +1,25 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the static API keys, an alternative to the OAuth2 client credentials; only the SHA-256 hashes of the keys are stored.
+-- The previous key stays valid until previous_key_expires_at after the rotation.
+CREATE TABLE IF NOT EXISTS api_keys (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR NOT NULL,
+    key_prefix VARCHAR NOT NULL,
+    key_hash VARCHAR NOT NULL,
+    previous_key_hash VARCHAR DEFAULT NULL,
+    previous_key_expires_at TIMESTAMP DEFAULT NULL,
+    role VARCHAR NOT NULL,
+    livemode BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    rotated_at TIMESTAMP DEFAULT NULL,
+    revoked_at TIMESTAMP DEFAULT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_key_hash_idx ON api_keys (key_hash);
+CREATE INDEX IF NOT EXISTS api_keys_previous_key_hash_idx ON api_keys (previous_key_hash);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS api_keys;
+-- +migrate StatementEnd
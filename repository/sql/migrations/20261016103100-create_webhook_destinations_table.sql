@@ -0,0 +1,18 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the webhook destinations registered in addition to WEBHOOK_URI, each one receives the subscribed events only;
+-- the events are the comma-separated event names or patterns, e.g. payment.succeeded,transaction.*
+CREATE TABLE IF NOT EXISTS webhook_destinations (
+    id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+    url VARCHAR NOT NULL,
+    events VARCHAR NOT NULL,
+    description VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT NULL
+);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS webhook_destinations;
+-- +migrate StatementEnd
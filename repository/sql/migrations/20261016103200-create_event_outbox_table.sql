@@ -0,0 +1,19 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the events recorded in the same transaction as the payment updates they're about,
+-- published to the event listeners by the outbox poller and kept for a while after that
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event VARCHAR NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT now(),
+    published_at TIMESTAMP DEFAULT NULL
+);
+CREATE INDEX IF NOT EXISTS event_outbox_pending_idx ON event_outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS event_outbox_published_at_idx ON event_outbox (published_at);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TABLE IF EXISTS event_outbox;
+-- +migrate StatementEnd
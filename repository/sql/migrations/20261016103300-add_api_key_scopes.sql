@@ -0,0 +1,11 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the space-delimited scopes of the API key, e.g. "payments:read reports:read";
+-- the empty scopes keep the key limited by its role only, as the keys issued before the scopes
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes VARCHAR NOT NULL DEFAULT '';
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE api_keys DROP COLUMN IF EXISTS scopes;
+-- +migrate StatementEnd
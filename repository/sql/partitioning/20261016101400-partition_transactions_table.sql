@@ -0,0 +1,51 @@
+-- +migrate Up
+-- +migrate StatementBegin
+-- the partition key must be a part of the primary key and the unique indexes,
+-- so the references (random public keys) are not unique-indexed anymore and
+-- the transaction references can't have a foreign key to the transactions anymore
+ALTER TABLE transaction_references DROP CONSTRAINT IF EXISTS transaction_references_transaction_id_fkey;
+DROP TRIGGER IF EXISTS update_transactions_modtime ON transactions;
+ALTER TABLE transactions RENAME TO transactions_unpartitioned;
+
+CREATE TABLE transactions (LIKE transactions_unpartitioned INCLUDING DEFAULTS) PARTITION BY RANGE (created_at);
+CREATE TABLE transactions_default PARTITION OF transactions DEFAULT;
+SELECT create_monthly_partitions(
+    'transactions',
+    COALESCE((SELECT MIN(created_at) FROM transactions_unpartitioned), now()::TIMESTAMP),
+    (now() + INTERVAL '3 months')::TIMESTAMP
+);
+
+INSERT INTO transactions SELECT * FROM transactions_unpartitioned;
+DROP TABLE transactions_unpartitioned;
+
+ALTER TABLE transactions ADD PRIMARY KEY (id, created_at);
+ALTER TABLE transactions ADD CONSTRAINT transactions_payment_id_fkey FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE;
+CREATE INDEX transactions_id ON transactions USING BTREE (id);
+CREATE INDEX transactions_reference ON transactions USING BTREE (reference);
+CREATE INDEX transactions_payment_source ON transactions USING BTREE (payment_id, source_wallet, source_mint) WHERE status = 'pending'::transaction_status;
+CREATE INDEX transactions_status_created_at ON transactions USING BTREE (status, created_at, id);
+CREATE TRIGGER update_transactions_modtime BEFORE
+UPDATE ON transactions FOR EACH ROW EXECUTE PROCEDURE transactions_update_updated_at_column();
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+DROP TRIGGER IF EXISTS update_transactions_modtime ON transactions;
+ALTER TABLE transactions RENAME TO transactions_partitioned;
+
+CREATE TABLE transactions (LIKE transactions_partitioned INCLUDING DEFAULTS);
+INSERT INTO transactions SELECT * FROM transactions_partitioned;
+DROP TABLE transactions_partitioned;
+
+ALTER TABLE transactions ADD PRIMARY KEY (id);
+ALTER TABLE transactions ADD CONSTRAINT transactions_payment_id_fkey FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX transactions_reference ON transactions USING BTREE (reference);
+CREATE INDEX transactions_payment_source ON transactions USING BTREE (payment_id, source_wallet, source_mint) WHERE status = 'pending'::transaction_status;
+CREATE INDEX transactions_status_created_at ON transactions USING BTREE (status, created_at, id);
+CREATE TRIGGER update_transactions_modtime BEFORE
+UPDATE ON transactions FOR EACH ROW EXECUTE PROCEDURE transactions_update_updated_at_column();
+
+DELETE FROM transaction_references r WHERE NOT EXISTS (SELECT 1 FROM transactions t WHERE t.id = r.transaction_id);
+ALTER TABLE transaction_references ADD CONSTRAINT transaction_references_transaction_id_fkey
+    FOREIGN KEY (transaction_id) REFERENCES transactions(id) ON DELETE CASCADE;
+-- +migrate StatementEnd
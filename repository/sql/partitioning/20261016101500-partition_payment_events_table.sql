@@ -0,0 +1,34 @@
+-- +migrate Up
+-- +migrate StatementBegin
+ALTER TABLE payment_events RENAME TO payment_events_unpartitioned;
+
+CREATE TABLE payment_events (LIKE payment_events_unpartitioned INCLUDING DEFAULTS) PARTITION BY RANGE (created_at);
+ALTER SEQUENCE payment_events_id_seq OWNED BY payment_events.id;
+CREATE TABLE payment_events_default PARTITION OF payment_events DEFAULT;
+SELECT create_monthly_partitions(
+    'payment_events',
+    COALESCE((SELECT MIN(created_at) FROM payment_events_unpartitioned), now()::TIMESTAMP),
+    (now() + INTERVAL '3 months')::TIMESTAMP
+);
+
+INSERT INTO payment_events SELECT * FROM payment_events_unpartitioned;
+DROP TABLE payment_events_unpartitioned;
+
+ALTER TABLE payment_events ADD PRIMARY KEY (id, created_at);
+ALTER TABLE payment_events ADD CONSTRAINT payment_events_payment_id_fkey FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE;
+CREATE INDEX payment_events_payment_id_idx ON payment_events (payment_id, id);
+-- +migrate StatementEnd
+
+-- +migrate Down
+-- +migrate StatementBegin
+ALTER TABLE payment_events RENAME TO payment_events_partitioned;
+
+CREATE TABLE payment_events (LIKE payment_events_partitioned INCLUDING DEFAULTS);
+ALTER SEQUENCE payment_events_id_seq OWNED BY payment_events.id;
+INSERT INTO payment_events SELECT * FROM payment_events_partitioned;
+DROP TABLE payment_events_partitioned;
+
+ALTER TABLE payment_events ADD PRIMARY KEY (id);
+ALTER TABLE payment_events ADD CONSTRAINT payment_events_payment_id_fkey FOREIGN KEY (payment_id) REFERENCES payments(id) ON DELETE CASCADE;
+CREATE INDEX payment_events_payment_id_idx ON payment_events (payment_id, id);
+-- +migrate StatementEnd
@@ -0,0 +1,28 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (name, key_prefix, key_hash, role, livemode, scopes)
+VALUES (@name, @key_prefix, @key_hash, @role, @livemode, @scopes)
+RETURNING *;
+
+-- name: GetAPIKey :one
+SELECT * FROM api_keys WHERE id = @id;
+
+-- name: GetAPIKeys :many
+SELECT * FROM api_keys ORDER BY created_at DESC;
+
+-- name: GetActiveAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE revoked_at IS NULL
+    AND (key_hash = @key_hash OR (previous_key_hash = @key_hash AND previous_key_expires_at > now()));
+
+-- name: RotateAPIKey :one
+UPDATE api_keys SET
+    previous_key_hash = key_hash,
+    previous_key_expires_at = @previous_key_expires_at,
+    key_hash = @key_hash,
+    key_prefix = @key_prefix,
+    rotated_at = now()
+WHERE id = @id AND revoked_at IS NULL
+RETURNING *;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys SET revoked_at = now() WHERE id = @id AND revoked_at IS NULL;
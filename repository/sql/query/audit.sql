@@ -0,0 +1,13 @@
+-- name: AddAuditRecord :exec
+INSERT INTO audit_records (client_id, method, route, path, payload_hash, status_code, latency_us, request_id)
+VALUES (@client_id, @method, @route, @path, @payload_hash, @status_code, @latency_us, @request_id);
+
+-- name: GetAuditRecords :many
+SELECT * FROM audit_records
+WHERE (@client_id::VARCHAR = '' OR client_id = @client_id)
+    AND created_at >= @created_from AND created_at < @created_to
+ORDER BY created_at DESC, id DESC
+LIMIT @limit OFFSET @offset;
+
+-- name: DeleteAuditRecordsCreatedBefore :execrows
+DELETE FROM audit_records WHERE created_at < @created_before;
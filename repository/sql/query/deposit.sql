@@ -0,0 +1,7 @@
+-- name: CreateDeposit :one
+INSERT INTO deposits (wallet, mint, amount, source_wallet, tx_signature)
+VALUES (@wallet, @mint, @amount, @source_wallet, @tx_signature)
+RETURNING *;
+
+-- name: DepositExists :one
+SELECT EXISTS(SELECT 1 FROM deposits WHERE tx_signature = @tx_signature AND mint = @mint);
@@ -0,0 +1,18 @@
+-- name: CreateDepositAddress :one
+INSERT INTO payment_deposit_addresses (payment_id, transaction_id, wallet, token_account, mint, amount)
+VALUES (@payment_id, @transaction_id, @wallet, @token_account, @mint, @amount)
+RETURNING *;
+
+-- name: GetDepositAddressByPaymentID :one
+SELECT * FROM payment_deposit_addresses WHERE payment_id = @payment_id;
+
+-- name: GetDepositAddressesToSweep :many
+SELECT d.* FROM payment_deposit_addresses d
+JOIN transactions t ON t.id = d.transaction_id
+WHERE d.swept_at IS NULL AND t.status = 'completed'::transaction_status
+ORDER BY d.created_at;
+
+-- name: MarkDepositAddressSwept :one
+UPDATE payment_deposit_addresses SET sweep_signature = @sweep_signature, swept_at = now()
+WHERE payment_id = @payment_id AND swept_at IS NULL
+RETURNING *;
@@ -0,0 +1,15 @@
+-- name: CreatePaymentDispute :one
+INSERT INTO payment_disputes (payment_id, reason, evidence_urls, previous_status)
+VALUES (@payment_id, @reason, @evidence_urls, @previous_status)
+RETURNING *;
+
+-- name: GetOpenPaymentDispute :one
+SELECT * FROM payment_disputes WHERE payment_id = @payment_id AND resolved_at IS NULL;
+
+-- name: GetPaymentDisputes :many
+SELECT * FROM payment_disputes WHERE payment_id = @payment_id ORDER BY created_at DESC;
+
+-- name: ResolvePaymentDispute :one
+UPDATE payment_disputes SET resolution = @resolution, resolved_at = NOW()
+WHERE id = @id AND resolved_at IS NULL
+RETURNING *;
@@ -0,0 +1,15 @@
+-- name: AddOutboxEvent :exec
+INSERT INTO event_outbox (event, payload)
+VALUES (@event, @payload);
+
+-- name: GetPendingOutboxEvents :many
+SELECT * FROM event_outbox WHERE published_at IS NULL
+ORDER BY id
+LIMIT @batch_size
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkOutboxEventPublished :exec
+UPDATE event_outbox SET published_at = now() WHERE id = @id;
+
+-- name: DeleteOutboxEventsPublishedBefore :execrows
+DELETE FROM event_outbox WHERE published_at < @published_before;
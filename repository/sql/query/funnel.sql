@@ -0,0 +1,12 @@
+-- name: TrackFunnelStage :execrows
+INSERT INTO payment_funnel_events (payment_id, stage)
+VALUES (@payment_id, @stage)
+ON CONFLICT (payment_id, stage) DO NOTHING;
+
+-- name: GetFunnelReport :many
+SELECT p.destination_wallet, p.destination_mint, f.stage, COUNT(*)::BIGINT AS total
+FROM payment_funnel_events f
+JOIN payments p ON p.id = f.payment_id AND p.livemode
+WHERE p.created_at >= @from_date::TIMESTAMP AND p.created_at < @to_date::TIMESTAMP
+GROUP BY p.destination_wallet, p.destination_mint, f.stage
+ORDER BY p.destination_wallet, p.destination_mint, f.stage;
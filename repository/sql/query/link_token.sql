@@ -0,0 +1,13 @@
+-- name: CreateLinkToken :exec
+INSERT INTO payment_link_tokens (token_hash, payment_id, expires_at)
+VALUES (@token_hash, @payment_id, @expires_at);
+
+-- name: GetLinkToken :one
+SELECT * FROM payment_link_tokens WHERE token_hash = @token_hash;
+
+-- name: UseLinkToken :execrows
+UPDATE payment_link_tokens SET used_at = now()
+WHERE token_hash = @token_hash AND payment_id = @payment_id AND used_at IS NULL AND expires_at > now();
+
+-- name: DeleteExpiredLinkTokens :execrows
+DELETE FROM payment_link_tokens WHERE expires_at < now();
@@ -0,0 +1,6 @@
+-- name: GetMintDecimals :one
+SELECT decimals FROM mints WHERE address = @address;
+
+-- name: StoreMintDecimals :exec
+INSERT INTO mints (address, decimals) VALUES (@address, @decimals)
+ON CONFLICT (address) DO UPDATE SET decimals = @decimals;
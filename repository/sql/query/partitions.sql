@@ -0,0 +1,2 @@
+-- name: CreateMonthlyPartitions :one
+SELECT create_monthly_partitions(@parent_table::TEXT, @from_date::TIMESTAMP, @to_date::TIMESTAMP)::INTEGER AS created;
@@ -6,7 +6,17 @@ INSERT INTO payments (
     amount, 
     status, 
     message, 
-    expires_at
+    expires_at,
+    customer_email,
+    translations,
+    fee_on_top,
+    escrow,
+    livemode,
+    callback_url,
+    allow_partial,
+    fiat_currency,
+    fiat_amount,
+    settings
 ) 
 VALUES (
     @external_id, 
@@ -15,7 +25,17 @@ VALUES (
     @amount, 
     @status, 
     @message, 
-    @expires_at
+    @expires_at,
+    @customer_email,
+    @translations,
+    @fee_on_top,
+    @escrow,
+    @livemode,
+    @callback_url,
+    @allow_partial,
+    @fiat_currency,
+    @fiat_amount,
+    @settings
 )
 RETURNING *;
 
@@ -25,8 +45,37 @@ SELECT * FROM payments WHERE id = @id;
 -- name: GetPaymentByExternalID :one
 SELECT * FROM payments WHERE external_id = @external_id::VARCHAR;
 
+-- name: GetPaymentStatus :one
+SELECT p.status, COALESCE(p.updated_at, p.created_at)::TIMESTAMP AS updated_at,
+    COALESCE((
+        SELECT t.tx_signature FROM transactions t
+        WHERE t.payment_id = p.id AND t.tx_signature IS NOT NULL AND t.tx_signature <> ''
+        ORDER BY t.updated_at DESC NULLS LAST, t.created_at DESC
+        LIMIT 1
+    ), '')::VARCHAR AS tx_signature
+FROM payments p WHERE p.id = @id;
+
 -- name: UpdatePaymentStatus :one
 UPDATE payments SET status = @status WHERE id = @id RETURNING *;
 
 -- name: MarkPaymentsExpired :exec
-UPDATE payments SET status = 'expired'::payment_status WHERE expires_at < NOW() AND status = 'new'::payment_status;
\ No newline at end of file
+UPDATE payments SET status = 'expired'::payment_status WHERE expires_at < @expired_before AND status = 'new'::payment_status;
+
+-- name: HoldPayment :one
+UPDATE payments SET status = 'held'::payment_status, held_until = @held_until WHERE id = @id RETURNING *;
+
+-- name: ReleasePayment :one
+UPDATE payments SET status = 'released'::payment_status WHERE id = @id AND status = 'held'::payment_status RETURNING *;
+
+-- name: GetPaymentsToRelease :many
+SELECT * FROM payments WHERE status = 'held'::payment_status AND held_until < NOW() ORDER BY held_until;
+
+-- name: RefreshPaymentAmountPaid :one
+UPDATE payments SET amount_paid = (
+    SELECT COALESCE(SUM(t.amount), 0)::BIGINT FROM transactions t
+    WHERE t.payment_id = @id AND t.status = 'completed'::transaction_status
+)
+WHERE id = @id RETURNING *;
+
+-- name: UpdatePaymentAmount :one
+UPDATE payments SET amount = @amount WHERE id = @id RETURNING *;
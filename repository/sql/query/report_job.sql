@@ -0,0 +1,19 @@
+-- name: CreateReportJob :one
+INSERT INTO report_jobs (kind, from_date, to_date)
+VALUES (@kind, @from_date, @to_date)
+RETURNING *;
+
+-- name: GetReportJob :one
+SELECT * FROM report_jobs WHERE id = @id;
+
+-- name: StartReportJob :execrows
+UPDATE report_jobs SET status = 'running', started_at = now()
+WHERE id = @id AND status IN ('pending', 'running');
+
+-- name: CompleteReportJob :execrows
+UPDATE report_jobs SET status = 'completed', file_key = @file_key, row_count = @row_count, completed_at = now()
+WHERE id = @id AND status = 'running';
+
+-- name: FailReportJob :execrows
+UPDATE report_jobs SET status = 'failed', error = @error, completed_at = now()
+WHERE id = @id AND status IN ('pending', 'running');
@@ -0,0 +1,77 @@
+-- name: GetRevenueReport :many
+SELECT DATE(t.created_at)::DATE AS day,
+    t.destination_mint,
+    t.destination_wallet,
+    COUNT(*)::BIGINT AS transactions,
+    SUM(t.amount)::BIGINT AS amount,
+    SUM(t.discount_amount)::BIGINT AS discount_amount,
+    SUM(t.voucher_amount)::BIGINT AS voucher_amount,
+    SUM(t.total_amount)::BIGINT AS total_amount
+FROM transactions t
+JOIN payments p ON p.id = t.payment_id AND p.livemode
+WHERE t.status = 'completed'::transaction_status
+    AND t.created_at >= @from_date::TIMESTAMP AND t.created_at < @to_date::TIMESTAMP
+GROUP BY day, t.destination_mint, t.destination_wallet
+ORDER BY day, t.destination_mint, t.destination_wallet;
+
+-- name: GetBonusReport :many
+SELECT DATE(t.created_at)::DATE AS day,
+    t.destination_mint,
+    SUM(t.accrued_bonus_amount)::BIGINT AS minted,
+    SUM(CASE WHEN t.apply_bonus THEN t.discount_amount ELSE 0 END)::BIGINT AS redeemed
+FROM transactions t
+JOIN payments p ON p.id = t.payment_id AND p.livemode
+WHERE t.status = 'completed'::transaction_status
+    AND t.created_at >= @from_date::TIMESTAMP AND t.created_at < @to_date::TIMESTAMP
+GROUP BY day, t.destination_mint
+ORDER BY day, t.destination_mint;
+
+-- name: GetPaymentHistoryExport :many
+SELECT p.id AS payment_id,
+    p.external_id,
+    p.status AS payment_status,
+    p.destination_wallet,
+    p.destination_mint,
+    p.amount AS payment_amount,
+    p.created_at AS payment_created_at,
+    t.id AS transaction_id,
+    t.reference,
+    t.status AS transaction_status,
+    t.source_wallet,
+    t.total_amount,
+    t.tx_signature
+FROM payments p
+LEFT JOIN transactions t ON t.payment_id = p.id
+WHERE p.livemode AND p.created_at >= @from_date::TIMESTAMP AND p.created_at < @to_date::TIMESTAMP
+ORDER BY p.created_at, p.id, t.created_at, t.id
+LIMIT @limit OFFSET @offset;
+
+-- name: GetSettlementExport :many
+SELECT t.id,
+    t.payment_id,
+    p.external_id,
+    t.reference,
+    t.source_wallet,
+    t.source_mint,
+    t.destination_wallet,
+    t.destination_mint,
+    t.amount,
+    t.discount_amount,
+    t.voucher_amount,
+    t.total_amount,
+    t.network_fee,
+    t.priority_fee,
+    t.slippage_fee,
+    t.tip_amount,
+    t.tip_destination,
+    t.exchange_rate,
+    p.fiat_currency,
+    t.tx_signature,
+    t.created_at,
+    t.updated_at
+FROM transactions t
+JOIN payments p ON p.id = t.payment_id AND p.livemode
+WHERE t.status = 'completed'::transaction_status
+    AND t.created_at >= @from_date::TIMESTAMP AND t.created_at < @to_date::TIMESTAMP
+ORDER BY t.created_at, t.id
+LIMIT @limit OFFSET @offset;
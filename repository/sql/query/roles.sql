@@ -0,0 +1,16 @@
+-- name: GetCredentialRole :one
+SELECT * FROM credential_roles WHERE credential = @credential;
+
+-- name: GetCredentialRoles :many
+SELECT * FROM credential_roles ORDER BY credential;
+
+-- name: StoreCredentialRole :one
+INSERT INTO credential_roles (credential, role)
+VALUES (@credential, @role)
+ON CONFLICT (credential) DO UPDATE SET
+    role = @role,
+    updated_at = now()
+RETURNING *;
+
+-- name: DeleteCredentialRole :execrows
+DELETE FROM credential_roles WHERE credential = @credential;
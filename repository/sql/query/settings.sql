@@ -0,0 +1,32 @@
+-- name: GetMerchantSettings :one
+SELECT * FROM merchant_settings WHERE id = 1;
+
+-- name: StoreMerchantSettings :one
+INSERT INTO merchant_settings (
+    id,
+    apply_bonus,
+    max_apply_bonus_amount,
+    max_apply_bonus_percent,
+    accrue_bonus_rate,
+    destination_mint,
+    destination_wallet,
+    escrow_release_after
+) VALUES (
+    1,
+    @apply_bonus,
+    @max_apply_bonus_amount,
+    @max_apply_bonus_percent,
+    @accrue_bonus_rate,
+    @destination_mint,
+    @destination_wallet,
+    @escrow_release_after
+) ON CONFLICT (id) DO UPDATE SET
+    apply_bonus = @apply_bonus,
+    max_apply_bonus_amount = @max_apply_bonus_amount,
+    max_apply_bonus_percent = @max_apply_bonus_percent,
+    accrue_bonus_rate = @accrue_bonus_rate,
+    destination_mint = @destination_mint,
+    destination_wallet = @destination_wallet,
+    escrow_release_after = @escrow_release_after,
+    updated_at = now()
+RETURNING *;
@@ -0,0 +1,12 @@
+-- name: CreateSettlement :one
+INSERT INTO settlements (payment_id, input_mint, output_mint, in_amount)
+VALUES (@payment_id, @input_mint, @output_mint, @in_amount)
+RETURNING *;
+
+-- name: GetSettlementByPaymentID :one
+SELECT * FROM settlements WHERE payment_id = @payment_id;
+
+-- name: UpdateSettlement :one
+UPDATE settlements SET status = @status, out_amount = @out_amount, tx_signature = @tx_signature, error = @error, updated_at = now()
+WHERE id = @id
+RETURNING *;
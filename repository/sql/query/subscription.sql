@@ -0,0 +1,53 @@
+-- name: CreateSubscriptionPlan :one
+INSERT INTO subscription_plans (name, amount, currency, billing_interval, interval_count)
+VALUES (@name, @amount, @currency, @billing_interval, @interval_count)
+RETURNING *;
+
+-- name: GetSubscriptionPlan :one
+SELECT * FROM subscription_plans WHERE id = @id;
+
+-- name: GetSubscriptionPlans :many
+SELECT * FROM subscription_plans ORDER BY created_at DESC;
+
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (plan_id, customer_email, livemode, next_billing_at)
+VALUES (@plan_id, @customer_email, @livemode, @next_billing_at)
+RETURNING *;
+
+-- name: GetSubscription :one
+SELECT * FROM subscriptions WHERE id = @id;
+
+-- name: CancelSubscription :one
+UPDATE subscriptions SET status = 'canceled', canceled_at = now()
+WHERE id = @id AND status = 'active'
+RETURNING *;
+
+-- name: GetDueSubscriptions :many
+SELECT * FROM subscriptions WHERE status = 'active' AND next_billing_at <= @now ORDER BY next_billing_at;
+
+-- name: AdvanceSubscription :execrows
+UPDATE subscriptions SET next_billing_at = @next_billing_at
+WHERE id = @id AND next_billing_at = @due_at;
+
+-- name: CreateSubscriptionInstallment :one
+INSERT INTO subscription_installments (subscription_id, due_at)
+VALUES (@subscription_id, @due_at)
+RETURNING *;
+
+-- name: GetSubscriptionInstallmentByDueAt :one
+SELECT * FROM subscription_installments WHERE subscription_id = @subscription_id AND due_at = @due_at;
+
+-- name: GetSubscriptionInstallmentByPaymentID :one
+SELECT * FROM subscription_installments WHERE payment_id = @payment_id;
+
+-- name: GetSubscriptionInstallments :many
+SELECT * FROM subscription_installments WHERE subscription_id = @subscription_id ORDER BY due_at DESC;
+
+-- name: SetSubscriptionInstallmentPayment :one
+UPDATE subscription_installments SET payment_id = @payment_id, updated_at = now()
+WHERE id = @id
+RETURNING *;
+
+-- name: UpdateSubscriptionInstallmentStatus :execrows
+UPDATE subscription_installments SET status = @status, updated_at = now()
+WHERE id = @id AND status = 'due';
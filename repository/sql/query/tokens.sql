@@ -30,7 +30,7 @@ AND refresh_token_id = @refresh_token_id;
 -- name: DeleteToken :exec
 DELETE FROM tokens WHERE token_type = @token_type AND credential = @credential;
 
--- name: DeleteExpiredTokens :exec
+-- name: DeleteExpiredTokens :execrows
 DELETE FROM tokens WHERE refresh_expires_at < NOW();
 
 -- name: DeleteTokensByCredential :exec
@@ -13,7 +13,16 @@ INSERT INTO transactions (
     message,
     memo,
     apply_bonus,
-    status
+    status,
+    network_fee,
+    priority_fee,
+    slippage_fee,
+    voucher_amount,
+    swap_route,
+    exchange_rate,
+    tip_amount,
+    tip_destination,
+    id
 ) 
 VALUES (
     @payment_id, 
@@ -29,7 +38,16 @@ VALUES (
     @message,
     @memo,
     @apply_bonus,
-    @status
+    @status,
+    @network_fee,
+    @priority_fee,
+    @slippage_fee,
+    @voucher_amount,
+    @swap_route,
+    @exchange_rate,
+    @tip_amount,
+    @tip_destination,
+    COALESCE(sqlc.narg(id), uuid_generate_v4())
 )
 RETURNING *;
 
@@ -37,13 +55,36 @@ RETURNING *;
 SELECT * FROM transactions WHERE id = @id;
 
 -- name: GetTransactionByReference :one
-SELECT * FROM transactions WHERE reference = @reference;
+SELECT * FROM transactions
+WHERE reference = @reference
+    OR id = (SELECT r.transaction_id FROM transaction_references r WHERE r.reference = @reference);
 
 -- name: GetTransactionsByPaymentID :many
 SELECT * FROM transactions WHERE payment_id = @payment_id ORDER BY created_at DESC;
 
+-- name: GetTransactionsByStatus :many
+SELECT * FROM transactions
+WHERE status = @status
+ORDER BY created_at, id
+LIMIT @limit OFFSET @offset;
+
+-- name: GetTransactionsByStatusCreatedBefore :many
+SELECT * FROM transactions
+WHERE status = @status AND created_at < @created_before
+ORDER BY created_at, id
+LIMIT @limit OFFSET @offset;
+
+-- name: GetTransactionsByStatusCreatedBetween :many
+SELECT * FROM transactions
+WHERE status = @status AND created_at >= @created_from AND created_at < @created_to
+ORDER BY created_at, id
+LIMIT @limit OFFSET @offset;
+
 -- name: UpdateTransactionByReference :one
-UPDATE transactions SET tx_signature = @tx_signature, status = @status WHERE reference = @reference RETURNING *;
+UPDATE transactions SET tx_signature = @tx_signature, status = @status
+WHERE reference = @reference
+    OR id = (SELECT r.transaction_id FROM transaction_references r WHERE r.reference = @reference)
+RETURNING *;
 
 -- name: GetTransactionByPaymentIDSourceWalletAndMint :one
 SELECT * FROM transactions 
@@ -57,8 +98,30 @@ LIMIT 1;
 -- name: GetPendingTransactions :many
 SELECT * FROM transactions WHERE status = 'pending'::transaction_status;
 
+-- name: GetPendingTransactionReferences :many
+SELECT t.reference FROM transactions t WHERE t.status = 'pending'::transaction_status
+UNION
+SELECT tr.reference FROM transaction_references tr
+JOIN transactions t ON t.id = tr.transaction_id
+WHERE t.status = 'pending'::transaction_status;
+
 -- name: MarkTransactionsAsExpired :exec
 UPDATE transactions SET status = 'expired'::transaction_status 
 WHERE status = 'pending'::transaction_status AND payment_id IN (
     SELECT id FROM payments WHERE status = 'expired'::payment_status
-);
\ No newline at end of file
+);
+
+-- name: ExpireOtherPendingTransactions :execrows
+UPDATE transactions SET status = 'expired'::transaction_status
+WHERE payment_id = @payment_id AND id <> @id AND status = 'pending'::transaction_status;
+
+-- name: AnyTransactionReferenceExists :one
+SELECT EXISTS(SELECT 1 FROM transactions WHERE reference = ANY(@references::VARCHAR[]))
+    OR EXISTS(SELECT 1 FROM transaction_references WHERE reference = ANY(@references::VARCHAR[]));
+
+-- name: AddTransactionReference :exec
+INSERT INTO transaction_references (transaction_id, reference)
+VALUES (@transaction_id, @reference);
+
+-- name: GetTransactionReferences :many
+SELECT reference FROM transaction_references WHERE transaction_id = @transaction_id ORDER BY created_at, reference;
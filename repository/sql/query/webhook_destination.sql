@@ -0,0 +1,18 @@
+-- name: CreateWebhookDestination :one
+INSERT INTO webhook_destinations (url, events, description)
+VALUES (@url, @events, @description)
+RETURNING *;
+
+-- name: GetWebhookDestination :one
+SELECT * FROM webhook_destinations WHERE id = @id;
+
+-- name: GetWebhookDestinations :many
+SELECT * FROM webhook_destinations ORDER BY created_at;
+
+-- name: UpdateWebhookDestination :one
+UPDATE webhook_destinations SET url = @url, events = @events, description = @description, updated_at = now()
+WHERE id = @id
+RETURNING *;
+
+-- name: DeleteWebhookDestination :execrows
+DELETE FROM webhook_destinations WHERE id = @id;
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultBackend is the name of the sqlc-generated PostgreSQL backend.
+const DefaultBackend = "postgres"
+
+type (
+	// Storage is the storage backend consumed by the payment service, the auth verifier
+	// and the other services. Alternative backends implement it on top of their own schema
+	// and queries, so the sqlc-generated PostgreSQL code doesn't have to be forked.
+	// The backends must follow the contract of the PostgreSQL one:
+	// the single row queries return sql.ErrNoRows if nothing is found,
+	// the violations of the unique indexes are recognized by IsUniqueViolation.
+	Storage interface {
+		Querier
+		// InTx runs fn with the queries bound to a new transaction, which is committed if fn succeeds
+		// and rolled back otherwise. If the storage is already bound to a transaction, fn joins it.
+		InTx(ctx context.Context, fn func(q Querier) error) error
+		// Close releases the prepared statements, the connection pool is closed by the caller.
+		Close() error
+	}
+
+	// Backend opens the storage on top of the given connection pool.
+	Backend func(ctx context.Context, db *sql.DB) (Storage, error)
+)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = map[string]Backend{
+		DefaultBackend: func(ctx context.Context, db *sql.DB) (Storage, error) {
+			return Prepare(ctx, db)
+		},
+	}
+)
+
+// RegisterBackend makes the storage backend available by the given name,
+// which is the name of its database/sql driver, e.g. "mysql".
+// It panics if the backend is registered twice.
+func RegisterBackend(name string, backend Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if backend == nil {
+		panic("repository: backend is nil")
+	}
+	if _, ok := backends[name]; ok {
+		panic("repository: backend is registered twice: " + name)
+	}
+	backends[name] = backend
+}
+
+// Backends returns the sorted names of the registered backends.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	result := make([]string, 0, len(backends))
+	for name := range backends {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// Open opens the storage of the given backend on top of the connection pool.
+func Open(ctx context.Context, name string, db *sql.DB) (Storage, error) {
+	backendsMu.RLock()
+	backend, ok := backends[name]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, name)
+	}
+
+	return backend(ctx, db)
+}
@@ -12,13 +12,16 @@ import (
 	"github.com/google/uuid"
 )
 
-const deleteExpiredTokens = `-- name: DeleteExpiredTokens :exec
+const deleteExpiredTokens = `-- name: DeleteExpiredTokens :execrows
 DELETE FROM tokens WHERE refresh_expires_at < NOW()
 `
 
-func (q *Queries) DeleteExpiredTokens(ctx context.Context) error {
-	_, err := q.exec(ctx, q.deleteExpiredTokensStmt, deleteExpiredTokens)
-	return err
+func (q *Queries) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	result, err := q.exec(ctx, q.deleteExpiredTokensStmt, deleteExpiredTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const deleteToken = `-- name: DeleteToken :exec
@@ -8,10 +8,28 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const addTransactionReference = `-- name: AddTransactionReference :exec
+INSERT INTO transaction_references (transaction_id, reference)
+VALUES ($1, $2)
+`
+
+type AddTransactionReferenceParams struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Reference     string    `json:"reference"`
+}
+
+func (q *Queries) AddTransactionReference(ctx context.Context, arg AddTransactionReferenceParams) error {
+	_, err := q.exec(ctx, q.addTransactionReferenceStmt, addTransactionReference, arg.TransactionID, arg.Reference)
+	return err
+}
+
 const createTransaction = `-- name: CreateTransaction :one
 INSERT INTO transactions (
     payment_id, 
@@ -27,7 +45,16 @@ INSERT INTO transactions (
     message,
     memo,
     apply_bonus,
-    status
+    status,
+    network_fee,
+    priority_fee,
+    slippage_fee,
+    voucher_amount,
+    swap_route,
+    exchange_rate,
+    tip_amount,
+    tip_destination,
+    id
 ) 
 VALUES (
     $1, 
@@ -43,9 +70,18 @@ VALUES (
     $11,
     $12,
     $13,
-    $14
+    $14,
+    $15,
+    $16,
+    $17,
+    $18,
+    $19,
+    $20,
+    $21,
+    $22,
+    COALESCE($23, uuid_generate_v4())
 )
-RETURNING id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at
+RETURNING id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination
 `
 
 type CreateTransactionParams struct {
@@ -63,6 +99,15 @@ type CreateTransactionParams struct {
 	Memo               sql.NullString    `json:"memo"`
 	ApplyBonus         sql.NullBool      `json:"apply_bonus"`
 	Status             TransactionStatus `json:"status"`
+	NetworkFee         int64             `json:"network_fee"`
+	PriorityFee        int64             `json:"priority_fee"`
+	SlippageFee        int64             `json:"slippage_fee"`
+	VoucherAmount      int64             `json:"voucher_amount"`
+	SwapRoute          json.RawMessage   `json:"swap_route"`
+	ExchangeRate       sql.NullString    `json:"exchange_rate"`
+	TipAmount          int64             `json:"tip_amount"`
+	TipDestination     sql.NullString    `json:"tip_destination"`
+	ID                 uuid.NullUUID     `json:"id"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (Transaction, error) {
@@ -81,6 +126,15 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.Memo,
 		arg.ApplyBonus,
 		arg.Status,
+		arg.NetworkFee,
+		arg.PriorityFee,
+		arg.SlippageFee,
+		arg.VoucherAmount,
+		arg.SwapRoute,
+		arg.ExchangeRate,
+		arg.TipAmount,
+		arg.TipDestination,
+		arg.ID,
 	)
 	var i Transaction
 	err := row.Scan(
@@ -102,12 +156,51 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.NetworkFee,
+		&i.PriorityFee,
+		&i.SlippageFee,
+		&i.VoucherAmount,
+		&i.SwapRoute,
+		&i.ExchangeRate,
+		&i.TipAmount,
+		&i.TipDestination,
 	)
 	return i, err
 }
 
+const getPendingTransactionReferences = `-- name: GetPendingTransactionReferences :many
+SELECT t.reference FROM transactions t WHERE t.status = 'pending'::transaction_status
+UNION
+SELECT tr.reference FROM transaction_references tr
+JOIN transactions t ON t.id = tr.transaction_id
+WHERE t.status = 'pending'::transaction_status
+`
+
+func (q *Queries) GetPendingTransactionReferences(ctx context.Context) ([]string, error) {
+	rows, err := q.query(ctx, q.getPendingTransactionReferencesStmt, getPendingTransactionReferences)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var reference string
+		if err := rows.Scan(&reference); err != nil {
+			return nil, err
+		}
+		items = append(items, reference)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPendingTransactions = `-- name: GetPendingTransactions :many
-SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at FROM transactions WHERE status = 'pending'::transaction_status
+SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination FROM transactions WHERE status = 'pending'::transaction_status
 `
 
 func (q *Queries) GetPendingTransactions(ctx context.Context) ([]Transaction, error) {
@@ -138,6 +231,14 @@ func (q *Queries) GetPendingTransactions(ctx context.Context) ([]Transaction, er
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.NetworkFee,
+			&i.PriorityFee,
+			&i.SlippageFee,
+			&i.VoucherAmount,
+			&i.SwapRoute,
+			&i.ExchangeRate,
+			&i.TipAmount,
+			&i.TipDestination,
 		); err != nil {
 			return nil, err
 		}
@@ -153,7 +254,7 @@ func (q *Queries) GetPendingTransactions(ctx context.Context) ([]Transaction, er
 }
 
 const getTransaction = `-- name: GetTransaction :one
-SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at FROM transactions WHERE id = $1
+SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination FROM transactions WHERE id = $1
 `
 
 func (q *Queries) GetTransaction(ctx context.Context, id uuid.UUID) (Transaction, error) {
@@ -178,12 +279,20 @@ func (q *Queries) GetTransaction(ctx context.Context, id uuid.UUID) (Transaction
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.NetworkFee,
+		&i.PriorityFee,
+		&i.SlippageFee,
+		&i.VoucherAmount,
+		&i.SwapRoute,
+		&i.ExchangeRate,
+		&i.TipAmount,
+		&i.TipDestination,
 	)
 	return i, err
 }
 
 const getTransactionByPaymentIDSourceWalletAndMint = `-- name: GetTransactionByPaymentIDSourceWalletAndMint :one
-SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at FROM transactions 
+SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination FROM transactions 
 WHERE payment_id = $1 
     AND source_wallet = $2 
     AND source_mint = $3
@@ -220,12 +329,22 @@ func (q *Queries) GetTransactionByPaymentIDSourceWalletAndMint(ctx context.Conte
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.NetworkFee,
+		&i.PriorityFee,
+		&i.SlippageFee,
+		&i.VoucherAmount,
+		&i.SwapRoute,
+		&i.ExchangeRate,
+		&i.TipAmount,
+		&i.TipDestination,
 	)
 	return i, err
 }
 
 const getTransactionByReference = `-- name: GetTransactionByReference :one
-SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at FROM transactions WHERE reference = $1
+SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination FROM transactions
+WHERE reference = $1
+    OR id = (SELECT r.transaction_id FROM transaction_references r WHERE r.reference = $1)
 `
 
 func (q *Queries) GetTransactionByReference(ctx context.Context, reference string) (Transaction, error) {
@@ -250,12 +369,47 @@ func (q *Queries) GetTransactionByReference(ctx context.Context, reference strin
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.NetworkFee,
+		&i.PriorityFee,
+		&i.SlippageFee,
+		&i.VoucherAmount,
+		&i.SwapRoute,
+		&i.ExchangeRate,
+		&i.TipAmount,
+		&i.TipDestination,
 	)
 	return i, err
 }
 
+const getTransactionReferences = `-- name: GetTransactionReferences :many
+SELECT reference FROM transaction_references WHERE transaction_id = $1 ORDER BY created_at, reference
+`
+
+func (q *Queries) GetTransactionReferences(ctx context.Context, transactionID uuid.UUID) ([]string, error) {
+	rows, err := q.query(ctx, q.getTransactionReferencesStmt, getTransactionReferences, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var reference string
+		if err := rows.Scan(&reference); err != nil {
+			return nil, err
+		}
+		items = append(items, reference)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTransactionsByPaymentID = `-- name: GetTransactionsByPaymentID :many
-SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at FROM transactions WHERE payment_id = $1 ORDER BY created_at DESC
+SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination FROM transactions WHERE payment_id = $1 ORDER BY created_at DESC
 `
 
 func (q *Queries) GetTransactionsByPaymentID(ctx context.Context, paymentID uuid.UUID) ([]Transaction, error) {
@@ -286,6 +440,206 @@ func (q *Queries) GetTransactionsByPaymentID(ctx context.Context, paymentID uuid
 			&i.Status,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.NetworkFee,
+			&i.PriorityFee,
+			&i.SlippageFee,
+			&i.VoucherAmount,
+			&i.SwapRoute,
+			&i.ExchangeRate,
+			&i.TipAmount,
+			&i.TipDestination,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTransactionsByStatus = `-- name: GetTransactionsByStatus :many
+SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination FROM transactions
+WHERE status = $1
+ORDER BY created_at, id
+LIMIT $2 OFFSET $3
+`
+
+type GetTransactionsByStatusParams struct {
+	Status TransactionStatus `json:"status"`
+	Limit  int32             `json:"limit"`
+	Offset int32             `json:"offset"`
+}
+
+func (q *Queries) GetTransactionsByStatus(ctx context.Context, arg GetTransactionsByStatusParams) ([]Transaction, error) {
+	rows, err := q.query(ctx, q.getTransactionsByStatusStmt, getTransactionsByStatus, arg.Status, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentID,
+			&i.Reference,
+			&i.SourceWallet,
+			&i.SourceMint,
+			&i.DestinationWallet,
+			&i.DestinationMint,
+			&i.Amount,
+			&i.DiscountAmount,
+			&i.TotalAmount,
+			&i.AccruedBonusAmount,
+			&i.Message,
+			&i.Memo,
+			&i.ApplyBonus,
+			&i.TxSignature,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.NetworkFee,
+			&i.PriorityFee,
+			&i.SlippageFee,
+			&i.VoucherAmount,
+			&i.SwapRoute,
+			&i.ExchangeRate,
+			&i.TipAmount,
+			&i.TipDestination,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTransactionsByStatusCreatedBefore = `-- name: GetTransactionsByStatusCreatedBefore :many
+SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination FROM transactions
+WHERE status = $1 AND created_at < $2
+ORDER BY created_at, id
+LIMIT $3 OFFSET $4
+`
+
+type GetTransactionsByStatusCreatedBeforeParams struct {
+	Status        TransactionStatus `json:"status"`
+	CreatedBefore time.Time         `json:"created_before"`
+	Limit         int32             `json:"limit"`
+	Offset        int32             `json:"offset"`
+}
+
+func (q *Queries) GetTransactionsByStatusCreatedBefore(ctx context.Context, arg GetTransactionsByStatusCreatedBeforeParams) ([]Transaction, error) {
+	rows, err := q.query(ctx, q.getTransactionsByStatusCreatedBeforeStmt, getTransactionsByStatusCreatedBefore, arg.Status, arg.CreatedBefore, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentID,
+			&i.Reference,
+			&i.SourceWallet,
+			&i.SourceMint,
+			&i.DestinationWallet,
+			&i.DestinationMint,
+			&i.Amount,
+			&i.DiscountAmount,
+			&i.TotalAmount,
+			&i.AccruedBonusAmount,
+			&i.Message,
+			&i.Memo,
+			&i.ApplyBonus,
+			&i.TxSignature,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.NetworkFee,
+			&i.PriorityFee,
+			&i.SlippageFee,
+			&i.VoucherAmount,
+			&i.SwapRoute,
+			&i.ExchangeRate,
+			&i.TipAmount,
+			&i.TipDestination,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTransactionsByStatusCreatedBetween = `-- name: GetTransactionsByStatusCreatedBetween :many
+SELECT id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination FROM transactions
+WHERE status = $1 AND created_at >= $2 AND created_at < $3
+ORDER BY created_at, id
+LIMIT $4 OFFSET $5
+`
+
+type GetTransactionsByStatusCreatedBetweenParams struct {
+	Status      TransactionStatus `json:"status"`
+	CreatedFrom time.Time         `json:"created_from"`
+	CreatedTo   time.Time         `json:"created_to"`
+	Limit       int32             `json:"limit"`
+	Offset      int32             `json:"offset"`
+}
+
+func (q *Queries) GetTransactionsByStatusCreatedBetween(ctx context.Context, arg GetTransactionsByStatusCreatedBetweenParams) ([]Transaction, error) {
+	rows, err := q.query(ctx, q.getTransactionsByStatusCreatedBetweenStmt, getTransactionsByStatusCreatedBetween, arg.Status, arg.CreatedFrom, arg.CreatedTo, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Transaction
+	for rows.Next() {
+		var i Transaction
+		if err := rows.Scan(
+			&i.ID,
+			&i.PaymentID,
+			&i.Reference,
+			&i.SourceWallet,
+			&i.SourceMint,
+			&i.DestinationWallet,
+			&i.DestinationMint,
+			&i.Amount,
+			&i.DiscountAmount,
+			&i.TotalAmount,
+			&i.AccruedBonusAmount,
+			&i.Message,
+			&i.Memo,
+			&i.ApplyBonus,
+			&i.TxSignature,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.NetworkFee,
+			&i.PriorityFee,
+			&i.SlippageFee,
+			&i.VoucherAmount,
+			&i.SwapRoute,
+			&i.ExchangeRate,
+			&i.TipAmount,
+			&i.TipDestination,
 		); err != nil {
 			return nil, err
 		}
@@ -312,8 +666,29 @@ func (q *Queries) MarkTransactionsAsExpired(ctx context.Context) error {
 	return err
 }
 
+const expireOtherPendingTransactions = `-- name: ExpireOtherPendingTransactions :execrows
+UPDATE transactions SET status = 'expired'::transaction_status
+WHERE payment_id = $1 AND id <> $2 AND status = 'pending'::transaction_status
+`
+
+type ExpireOtherPendingTransactionsParams struct {
+	PaymentID uuid.UUID `json:"payment_id"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (q *Queries) ExpireOtherPendingTransactions(ctx context.Context, arg ExpireOtherPendingTransactionsParams) (int64, error) {
+	result, err := q.exec(ctx, q.expireOtherPendingTransactionsStmt, expireOtherPendingTransactions, arg.PaymentID, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateTransactionByReference = `-- name: UpdateTransactionByReference :one
-UPDATE transactions SET tx_signature = $1, status = $2 WHERE reference = $3 RETURNING id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at
+UPDATE transactions SET tx_signature = $1, status = $2
+WHERE reference = $3
+    OR id = (SELECT r.transaction_id FROM transaction_references r WHERE r.reference = $3)
+RETURNING id, payment_id, reference, source_wallet, source_mint, destination_wallet, destination_mint, amount, discount_amount, total_amount, accrued_bonus_amount, message, memo, apply_bonus, tx_signature, status, created_at, updated_at, network_fee, priority_fee, slippage_fee, voucher_amount, swap_route, exchange_rate, tip_amount, tip_destination
 `
 
 type UpdateTransactionByReferenceParams struct {
@@ -344,6 +719,26 @@ func (q *Queries) UpdateTransactionByReference(ctx context.Context, arg UpdateTr
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.NetworkFee,
+		&i.PriorityFee,
+		&i.SlippageFee,
+		&i.VoucherAmount,
+		&i.SwapRoute,
+		&i.ExchangeRate,
+		&i.TipAmount,
+		&i.TipDestination,
 	)
 	return i, err
 }
+
+const anyTransactionReferenceExists = `-- name: AnyTransactionReferenceExists :one
+SELECT EXISTS(SELECT 1 FROM transactions WHERE reference = ANY($1::VARCHAR[]))
+    OR EXISTS(SELECT 1 FROM transaction_references WHERE reference = ANY($1::VARCHAR[]))
+`
+
+func (q *Queries) AnyTransactionReferenceExists(ctx context.Context, references []string) (bool, error) {
+	row := q.queryRow(ctx, q.anyTransactionReferenceExistsStmt, anyTransactionReferenceExists, pq.Array(references))
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// txContextKey is the context key of the queries bound to the transaction, see ContextWithTx.
+type txContextKey struct{}
+
+// InTx implements Storage. The prepared statements are bound to the transaction, see WithTx.
+func (q *Queries) InTx(ctx context.Context, fn func(q Querier) error) error {
+	if q.tx != nil {
+		return fn(q)
+	}
+
+	db, ok := q.db.(*sql.DB)
+	if !ok {
+		return errors.New("queries are not bound to the connection pool")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w; rollback failed: %v", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ContextWithTx returns the context carrying the queries bound to the transaction,
+// so the services called with it run their queries in the same transaction, see TxFromContext.
+func ContextWithTx(ctx context.Context, q Querier) context.Context {
+	return context.WithValue(ctx, txContextKey{}, q)
+}
+
+// TxFromContext returns the queries bound to the transaction of the context, if any.
+func TxFromContext(ctx context.Context) (Querier, bool) {
+	q, ok := ctx.Value(txContextKey{}).(Querier)
+	return q, ok
+}
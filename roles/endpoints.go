@@ -0,0 +1,92 @@
+package roles
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		List   endpoint.Endpoint
+		Assign endpoint.Endpoint
+		Remove endpoint.Endpoint
+	}
+
+	// AssignRequest is the request type for the Assign method.
+	AssignRequest struct {
+		Credential string `json:"-"`
+		Role       string `json:"role"`
+	}
+
+	// RemoveRequest is the request type for the Remove method.
+	RemoveRequest struct {
+		Credential string
+	}
+
+	// ListResponse is the response type for the List method.
+	ListResponse struct {
+		Roles []CredentialRole `json:"roles"`
+	}
+
+	// RoleResponse is the response type for the Assign method.
+	RoleResponse struct {
+		Role *CredentialRole `json:"role"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		List:   makeListEndpoint(s),
+		Assign: makeAssignEndpoint(s),
+		Remove: makeRemoveEndpoint(s),
+	}
+}
+
+// makeListEndpoint returns an endpoint function for the List method.
+func makeListEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		roles, err := s.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return ListResponse{Roles: roles}, nil
+	}
+}
+
+// makeAssignEndpoint returns an endpoint function for the Assign method.
+func makeAssignEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(AssignRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		role, err := s.Assign(ctx, req.Credential, req.Role)
+		if err != nil {
+			return nil, err
+		}
+
+		return RoleResponse{Role: role}, nil
+	}
+}
+
+// makeRemoveEndpoint returns an endpoint function for the Remove method.
+func makeRemoveEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(RemoveRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		if err := s.Remove(ctx, req.Credential); err != nil {
+			return nil, err
+		}
+
+		return true, nil
+	}
+}
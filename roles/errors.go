@@ -0,0 +1,9 @@
+package roles
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest = errors.New("invalid_request")
+	ErrRoleNotFound   = errors.New("role_not_found")
+)
@@ -0,0 +1,72 @@
+package roles
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+// Service manages the roles of the API credentials.
+// The role is added to the access token claims on issuance, so the changes apply
+// to the credential on the next token issuance or refresh.
+type Service struct {
+	repo roleRepository
+}
+
+// NewService creates a new roles service.
+func NewService(repo roleRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// List returns the credentials with the assigned roles.
+// The credentials which are not listed have the auth.DefaultRole.
+func (s *Service) List(ctx context.Context) ([]CredentialRole, error) {
+	items, err := s.repo.GetCredentialRoles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get credential roles: %w", err)
+	}
+
+	result := make([]CredentialRole, 0, len(items))
+	for _, r := range items {
+		result = append(result, castFromRepositoryRole(r))
+	}
+
+	return result, nil
+}
+
+// Assign sets the role of the credential, replacing the previous one.
+func (s *Service) Assign(ctx context.Context, credential, role string) (*CredentialRole, error) {
+	if credential == "" {
+		return nil, fmt.Errorf("%w: credential is required", ErrInvalidRequest)
+	}
+	r, err := auth.ParseRole(role)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+
+	stored, err := s.repo.StoreCredentialRole(ctx, repository.StoreCredentialRoleParams{
+		Credential: credential,
+		Role:       string(r),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store credential role: %w", err)
+	}
+
+	result := castFromRepositoryRole(stored)
+	return &result, nil
+}
+
+// Remove deletes the role of the credential, so it falls back to the auth.DefaultRole.
+func (s *Service) Remove(ctx context.Context, credential string) error {
+	n, err := s.repo.DeleteCredentialRole(ctx, credential)
+	if err != nil {
+		return fmt.Errorf("failed to delete credential role: %w", err)
+	}
+	if n == 0 {
+		return ErrRoleNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package roles_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/roles"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	roles map[string]repository.CredentialRole
+}
+
+func (r *repoMock) GetCredentialRoles(ctx context.Context) ([]repository.CredentialRole, error) {
+	result := make([]repository.CredentialRole, 0, len(r.roles))
+	for _, cr := range r.roles {
+		result = append(result, cr)
+	}
+	return result, nil
+}
+
+func (r *repoMock) StoreCredentialRole(ctx context.Context, arg repository.StoreCredentialRoleParams) (repository.CredentialRole, error) {
+	cr := repository.CredentialRole{
+		Credential: arg.Credential,
+		Role:       arg.Role,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+	r.roles[arg.Credential] = cr
+	return cr, nil
+}
+
+func (r *repoMock) DeleteCredentialRole(ctx context.Context, credential string) (int64, error) {
+	if _, ok := r.roles[credential]; !ok {
+		return 0, nil
+	}
+	delete(r.roles, credential)
+	return 1, nil
+}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	s := roles.NewService(&repoMock{roles: make(map[string]repository.CredentialRole)})
+
+	_, err := s.Assign(ctx, "client", "admin")
+	require.ErrorIs(t, err, roles.ErrInvalidRequest)
+
+	_, err = s.Assign(ctx, "", "operator")
+	require.ErrorIs(t, err, roles.ErrInvalidRequest)
+
+	cr, err := s.Assign(ctx, "client", "read-only")
+	require.NoError(t, err)
+	require.Equal(t, "client", cr.Credential)
+	require.Equal(t, "read-only", cr.Role)
+
+	cr, err = s.Assign(ctx, "client", "operator")
+	require.NoError(t, err)
+	require.Equal(t, "operator", cr.Role)
+
+	list, err := s.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	require.Equal(t, "operator", list[0].Role)
+
+	require.NoError(t, s.Remove(ctx, "client"))
+	require.ErrorIs(t, s.Remove(ctx, "client"), roles.ErrRoleNotFound)
+}
@@ -0,0 +1,88 @@
+package roles
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the role management API.
+// All the endpoints require authorization, the authMdw must allow the owners only.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/", httptransport.NewServer(
+		e.List,
+		httptransport.NopRequestDecoder,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{credential}", httptransport.NewServer(
+		e.Assign,
+		decodeAssignRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Delete("/{credential}", httptransport.NewServer(
+		e.Remove,
+		decodeRemoveRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, ErrRoleNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeAssignRequest is a transport/http.DecodeRequestFunc that decodes
+// the role from the request body and the credential from the URL path.
+func decodeAssignRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req AssignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+	req.Credential = chi.URLParam(r, "credential")
+
+	return req, nil
+}
+
+// decodeRemoveRequest is a transport/http.DecodeRequestFunc that decodes
+// the credential from the URL path.
+func decodeRemoveRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return RemoveRequest{Credential: chi.URLParam(r, "credential")}, nil
+}
@@ -0,0 +1,32 @@
+package roles
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+type (
+	// CredentialRole is the role assigned to the API credential, e.g. the OAuth2 client ID.
+	CredentialRole struct {
+		Credential string    `json:"credential"`
+		Role       string    `json:"role"`
+		UpdatedAt  time.Time `json:"updated_at"`
+	}
+
+	roleRepository interface {
+		GetCredentialRoles(ctx context.Context) ([]repository.CredentialRole, error)
+		StoreCredentialRole(ctx context.Context, arg repository.StoreCredentialRoleParams) (repository.CredentialRole, error)
+		DeleteCredentialRole(ctx context.Context, credential string) (int64, error)
+	}
+)
+
+// castFromRepositoryRole converts a repository credential role to a credential role.
+func castFromRepositoryRole(r repository.CredentialRole) CredentialRole {
+	return CredentialRole{
+		Credential: r.Credential,
+		Role:       r.Role,
+		UpdatedAt:  r.UpdatedAt,
+	}
+}
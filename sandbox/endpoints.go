@@ -0,0 +1,64 @@
+package sandbox
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		Airdrop endpoint.Endpoint
+	}
+
+	// AirdropRequest is the request type for the Airdrop method.
+	AirdropRequest struct {
+		Wallet      string   `json:"wallet" validate:"required|solanaWallet"`
+		Currencies  []string `json:"currencies,omitempty"` // test mint symbols, e.g. USDC; all the test mints if empty
+		Livemode    bool     `json:"-"`
+		RequestedBy string   `json:"-"`
+	}
+
+	// AirdropResponse is the response type for the Airdrop method.
+	AirdropResponse struct {
+		Airdrop *Airdrop `json:"airdrop"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		Airdrop: makeAirdropEndpoint(s),
+	}
+}
+
+// makeAirdropEndpoint returns an endpoint function for the Airdrop method.
+// Only the test-mode clients can request the airdrop.
+func makeAirdropEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(AirdropRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if req.Livemode {
+			return nil, ErrLivemodeClient
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		airdrop, err := s.Airdrop(ctx, AirdropParams{
+			Wallet:      req.Wallet,
+			Currencies:  req.Currencies,
+			RequestedBy: req.RequestedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return AirdropResponse{Airdrop: airdrop}, nil
+	}
+}
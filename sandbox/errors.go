@@ -0,0 +1,12 @@
+package sandbox
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest  = errors.New("invalid_request")
+	ErrLivemodeClient  = errors.New("livemode_client")
+	ErrAirdropFailed   = errors.New("airdrop_failed")
+	ErrFaucetDisabled  = errors.New("faucet_disabled")
+	ErrUnknownCurrency = errors.New("unknown_currency")
+)
@@ -0,0 +1,194 @@
+package sandbox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// Default airdrop amounts.
+const (
+	DefaultSOLAmount   uint64 = 1000000000 // 1 SOL, the devnet faucet limit per request
+	DefaultTokenAmount uint64 = 100        // in the token units, e.g. 100 USDC
+)
+
+type (
+	// Service funds the wallets on the sandbox cluster, so the integrators can
+	// pay the test-mode payments: SOL is airdropped by the cluster faucet,
+	// the test tokens are minted by the faucet mint authority.
+	Service struct {
+		sol         solanaClient
+		mints       map[string]string // currency symbol => test mint address
+		faucet      solana.Signer
+		solAmount   uint64
+		tokenAmount uint64
+		log         Logger
+	}
+
+	// ServiceOption is a function that configures the sandbox service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new sandbox service for the given sandbox cluster client and test mints,
+// e.g. {"USDC": "<devnet USDC mint address>"}.
+func NewService(sol solanaClient, mints map[string]string, log Logger, opts ...ServiceOption) *Service {
+	if log == nil {
+		panic("logger is required")
+	}
+
+	s := &Service{
+		sol:         sol,
+		mints:       make(map[string]string, len(mints)),
+		solAmount:   DefaultSOLAmount,
+		tokenAmount: DefaultTokenAmount,
+		log:         log,
+	}
+	for symbol, mint := range mints {
+		s.mints[strings.ToUpper(symbol)] = mint
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithFaucet sets the mint authority of the test mints.
+// If it's not set, only SOL is airdropped.
+func WithFaucet(signer solana.Signer) ServiceOption {
+	return func(s *Service) {
+		s.faucet = signer
+	}
+}
+
+// WithSOLAmount sets the amount of lamports airdropped per request.
+func WithSOLAmount(lamports uint64) ServiceOption {
+	return func(s *Service) {
+		if lamports > 0 {
+			s.solAmount = lamports
+		}
+	}
+}
+
+// WithTokenAmount sets the amount of each test token minted per request, in the token units.
+func WithTokenAmount(amount uint64) ServiceOption {
+	return func(s *Service) {
+		if amount > 0 {
+			s.tokenAmount = amount
+		}
+	}
+}
+
+// Airdrop funds the wallet with SOL and the test tokens.
+func (s *Service) Airdrop(ctx context.Context, params AirdropParams) (*Airdrop, error) {
+	s.log.Infof("sandbox: airdrop to %s requested by %s", params.Wallet, params.RequestedBy)
+
+	result, err := s.airdrop(ctx, params)
+	if err != nil {
+		s.log.Errorf("sandbox: airdrop to %s requested by %s failed: %v", params.Wallet, params.RequestedBy, err)
+		return nil, err
+	}
+
+	s.log.Infof("sandbox: airdrop to %s requested by %s sent: %s", params.Wallet, params.RequestedBy, result.SOLSignature)
+
+	return result, nil
+}
+
+func (s *Service) airdrop(ctx context.Context, params AirdropParams) (*Airdrop, error) {
+	currencies, err := s.currencies(params.Currencies)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Airdrop{
+		Wallet:    params.Wallet,
+		SOLAmount: s.solAmount,
+	}
+
+	result.SOLSignature, err = s.sol.RequestAirdrop(ctx, params.Wallet, s.solAmount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAirdropFailed, err.Error())
+	}
+
+	if len(currencies) == 0 {
+		return result, nil
+	}
+
+	faucet := s.faucet.PublicKey().ToBase58()
+	builder := solana.NewTransactionBuilder(s.sol).SetFeePayer(faucet)
+	for _, currency := range currencies {
+		mint := s.mints[currency]
+		decimals, err := s.sol.GetMintDecimals(ctx, mint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s mint decimals: %w", currency, err)
+		}
+
+		token := &TokenAirdrop{
+			Currency: currency,
+			Mint:     mint,
+			Amount:   utils.ConvertAmount(s.tokenAmount, 0, decimals),
+		}
+		result.Tokens = append(result.Tokens, token)
+
+		builder = builder.AddInstruction(solana.MintFungibleToken(solana.MintFungibleTokenParams{
+			Funder:    faucet,
+			Mint:      mint,
+			MintOwner: faucet,
+			MintTo:    params.Wallet,
+			Amount:    token.Amount,
+		}))
+	}
+
+	tx, err := builder.AddExternalSigner(s.faucet).Build(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build faucet transaction: %w", err)
+	}
+
+	signature, err := s.sol.SendTransaction(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAirdropFailed, err.Error())
+	}
+	for _, token := range result.Tokens {
+		token.Signature = signature
+	}
+
+	return result, nil
+}
+
+// currencies returns the sorted currency symbols of the test mints to airdrop.
+// All the test mints are airdropped if the requested list is empty.
+func (s *Service) currencies(requested []string) ([]string, error) {
+	if s.faucet == nil {
+		if len(requested) > 0 {
+			return nil, ErrFaucetDisabled
+		}
+		return nil, nil
+	}
+
+	if len(requested) == 0 {
+		for symbol := range s.mints {
+			requested = append(requested, symbol)
+		}
+	}
+
+	result := make([]string, 0, len(requested))
+	seen := make(map[string]bool, len(requested))
+	for _, symbol := range requested {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if _, ok := s.mints[symbol]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, symbol)
+		}
+		if !seen[symbol] {
+			seen[symbol] = true
+			result = append(result, symbol)
+		}
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the sandbox API.
+// All the endpoints require authorization of the test-mode client.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Post("/airdrop", httptransport.NewServer(
+		e.Airdrop,
+		decodeAirdropRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		return http.StatusPreconditionFailed, err
+	case errors.Is(err, ErrLivemodeClient):
+		return http.StatusForbidden, err.Error()
+	case errors.Is(err, ErrAirdropFailed):
+		return http.StatusBadGateway, err.Error()
+	case errors.Is(err, ErrInvalidRequest),
+		errors.Is(err, ErrFaucetDisabled),
+		errors.Is(err, ErrUnknownCurrency):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeAirdropRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeAirdropRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req AirdropRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	// OAuth2 client credentials and mode, set by the auth middleware
+	req.RequestedBy, _ = ctx.Value(oauth.CredentialContext).(string)
+	req.Livemode = auth.Livemode(ctx)
+
+	return req, nil
+}
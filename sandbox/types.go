@@ -0,0 +1,45 @@
+package sandbox
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// AirdropParams defines the parameters of a sandbox airdrop.
+	AirdropParams struct {
+		Wallet      string   // base58 encoded wallet address to fund.
+		Currencies  []string // currency symbols of the test mints to fund, e.g. USDC; all the test mints if empty.
+		RequestedBy string   // client which requested the airdrop, for the log.
+	}
+
+	// Airdrop is the result of a sandbox airdrop.
+	Airdrop struct {
+		Wallet       string          `json:"wallet"`
+		SOLAmount    uint64          `json:"sol_amount"`              // in lamports.
+		SOLSignature string          `json:"sol_signature,omitempty"` // signature of the SOL airdrop transaction.
+		Tokens       []*TokenAirdrop `json:"tokens,omitempty"`
+	}
+
+	// TokenAirdrop is a test token minted to the wallet by the faucet.
+	TokenAirdrop struct {
+		Currency  string `json:"currency"`
+		Mint      string `json:"mint"`
+		Amount    uint64 `json:"amount"`    // in minimal units of the mint.
+		Signature string `json:"signature"` // signature of the mint transaction.
+	}
+
+	solanaClient interface {
+		solana.SolanaClient
+		RequestAirdrop(ctx context.Context, base58Addr string, amount uint64) (string, error)
+		GetMintDecimals(ctx context.Context, base58MintAddr string) (uint8, error)
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+	}
+
+	// Logger is used for the airdrops log.
+	Logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
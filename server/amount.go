@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// Amount is an amount with the currency formatting metadata,
+	// so clients don't need to know decimals of every mint.
+	Amount struct {
+		solana.Balance
+		Mint   string `json:"mint"`
+		Symbol string `json:"symbol,omitempty"`
+	}
+
+	tokenMetadataProvider interface {
+		GetFungibleTokenMetadata(ctx context.Context, base58MintAddr string) (*solana.FungibleTokenMetadata, error)
+	}
+)
+
+// newAmount returns the amount with the formatting metadata of the given mint.
+// Returns nil if the mint metadata is not available, since the metadata is optional.
+func newAmount(ctx context.Context, tm tokenMetadataProvider, amount uint64, mint string) *Amount {
+	if tm == nil {
+		return nil
+	}
+
+	mint = payments.MintAddress(mint, payments.SOL)
+	md, err := tm.GetFungibleTokenMetadata(ctx, mint)
+	if err != nil || md == nil {
+		return nil
+	}
+
+	return &Amount{
+		Balance: solana.NewBalance(amount, md.Decimals),
+		Mint:    mint,
+		Symbol:  md.Symbol,
+	}
+}
@@ -0,0 +1,305 @@
+// Hosted checkout page: pays with the browser wallets supporting the Wallet Standard
+// and follows the payment status with the events stream, or polls it if the stream is unavailable.
+(function () {
+  'use strict';
+
+  var configEl = document.getElementById('checkout-config');
+  var config = configEl ? JSON.parse(configEl.textContent) : null;
+  var statusEl = document.getElementById('status');
+  if (!config || !config.payment_id || !statusEl) {
+    return;
+  }
+
+  var STATUS_TEXT = {
+    new: '',
+    pending: 'Confirming the transaction…',
+    partially_paid: 'Paid in part, waiting for the rest of the payment',
+    completed: 'Payment completed',
+    held: 'Payment completed',
+    released: 'Payment completed',
+    disputed: 'Payment disputed',
+    failed: 'Payment failed',
+    canceled: 'Payment canceled',
+    expired: 'Payment expired'
+  };
+  var SUCCESS = ['completed', 'held', 'released'];
+  var FINAL = SUCCESS.concat(['disputed', 'failed', 'canceled', 'expired']);
+  var POLL_INTERVAL = 3000;
+
+  var payEl = document.getElementById('pay');
+  var walletsEl = document.getElementById('wallets');
+  var errorEl = document.getElementById('error');
+
+  var events = null;
+  var pollTimer = null;
+
+  // Status
+
+  function setStatus(status) {
+    if (!status || !(status in STATUS_TEXT)) {
+      return;
+    }
+    config.status = status;
+    statusEl.textContent = STATUS_TEXT[status];
+
+    var final = FINAL.indexOf(status) >= 0;
+    statusEl.classList.toggle('final', final);
+    statusEl.classList.toggle('success', SUCCESS.indexOf(status) >= 0);
+    statusEl.classList.toggle('error', final && SUCCESS.indexOf(status) < 0);
+    if (final) {
+      if (payEl) {
+        payEl.hidden = true;
+      }
+      stopFollowing();
+    }
+  }
+
+  // statusFromEvent returns the payment status of the events stream message, if any.
+  function statusFromEvent(data) {
+    var payload = data.payload || {};
+    switch (data.name) {
+      case 'payment.released':
+        return 'released';
+      case 'payment.disputed':
+        return 'disputed';
+      case 'transaction.updated':
+        return null;
+      default:
+        return data.name && data.name.indexOf('payment.') === 0 ? payload.status : null;
+    }
+  }
+
+  function follow() {
+    if (FINAL.indexOf(config.status) >= 0) {
+      return;
+    }
+    if (!window.EventSource) {
+      poll();
+      return;
+    }
+
+    events = new EventSource(config.events_url);
+    events.onmessage = function (e) {
+      try {
+        setStatus(statusFromEvent(JSON.parse(e.data)));
+      } catch (err) {
+        // ignore the malformed messages
+      }
+    };
+    events.onerror = function () {
+      // the browser reconnects unless the stream is refused, e.g. rate limited
+      if (events.readyState === EventSource.CLOSED) {
+        events = null;
+        poll();
+      }
+    };
+  }
+
+  function poll() {
+    fetch(config.status_url, { headers: { Accept: 'application/json' } })
+      .then(function (res) {
+        return res.ok ? res.json() : null;
+      })
+      .then(function (body) {
+        if (body) {
+          setStatus(body.status);
+        }
+      })
+      .catch(function () {})
+      .then(function () {
+        if (FINAL.indexOf(config.status) < 0) {
+          pollTimer = setTimeout(poll, POLL_INTERVAL);
+        }
+      });
+  }
+
+  function stopFollowing() {
+    if (events) {
+      events.close();
+      events = null;
+    }
+    if (pollTimer) {
+      clearTimeout(pollTimer);
+      pollTimer = null;
+    }
+  }
+
+  // Wallets, see https://github.com/wallet-standard/wallet-standard
+
+  var CONNECT = 'standard:connect';
+  var SIGN_AND_SEND = 'solana:signAndSendTransaction';
+  var wallets = {};
+
+  function registerWallets() {
+    Array.prototype.slice.call(arguments).forEach(addWallet);
+    return function () {};
+  }
+
+  function addWallet(wallet) {
+    if (!walletsEl || !config.transaction_url || !wallet || wallets[wallet.name]) {
+      return;
+    }
+    var features = wallet.features || {};
+    var chains = wallet.chains || [];
+    if (!features[CONNECT] || !features[SIGN_AND_SEND] || chains.indexOf(config.chain) < 0) {
+      return;
+    }
+    wallets[wallet.name] = wallet;
+
+    var button = document.createElement('button');
+    button.type = 'button';
+    button.className = 'button';
+    if (wallet.icon) {
+      var icon = document.createElement('img');
+      icon.src = wallet.icon;
+      icon.alt = '';
+      button.appendChild(icon);
+    }
+    button.appendChild(document.createTextNode('Pay with ' + wallet.name));
+    button.addEventListener('click', function () {
+      pay(wallet);
+    });
+
+    walletsEl.appendChild(button);
+    walletsEl.hidden = false;
+  }
+
+  function setBusy(busy) {
+    walletsEl.querySelectorAll('button').forEach(function (b) {
+      b.disabled = busy;
+    });
+  }
+
+  function showError(message) {
+    errorEl.textContent = message;
+    errorEl.hidden = !message;
+  }
+
+  // pay connects the wallet, requests the payment transaction for its account
+  // from the Solana Pay transaction request endpoint and lets the wallet sign and send it.
+  function pay(wallet) {
+    var feature = wallet.features[SIGN_AND_SEND];
+    var versions = feature.supportedTransactionVersions || ['legacy'];
+    var account;
+
+    showError('');
+    setBusy(true);
+
+    wallet.features[CONNECT].connect()
+      .then(function (result) {
+        account = (result.accounts || []).filter(function (a) {
+          return (a.chains || []).indexOf(config.chain) >= 0;
+        })[0] || (result.accounts || [])[0];
+        if (!account) {
+          throw new Error('The wallet has no account to pay with');
+        }
+
+        return proofOfWork();
+      })
+      .then(function (pow) {
+        var headers = {
+          'Content-Type': 'application/json',
+          'X-Supported-Transaction-Versions': versions.join(',')
+        };
+        if (pow) {
+          headers[config.pow_header] = pow;
+        }
+
+        return fetch(config.transaction_url, {
+          method: 'POST',
+          headers: headers,
+          body: JSON.stringify({ account: account.address })
+        });
+      })
+      .then(function (res) {
+        return res.json().then(function (body) {
+          if (!res.ok) {
+            throw new Error(body.message || body.error || 'Could not create the payment transaction');
+          }
+          return body;
+        });
+      })
+      .then(function (body) {
+        return feature.signAndSendTransaction({
+          account: account,
+          chain: config.chain,
+          transaction: base64ToBytes(body.transaction)
+        });
+      })
+      .then(function () {
+        if (FINAL.indexOf(config.status) < 0) {
+          setStatus('pending');
+        }
+      })
+      .catch(function (err) {
+        showError((err && err.message) || 'The payment failed, please try again');
+      })
+      .then(function () {
+        setBusy(false);
+      });
+  }
+
+  // proofOfWork solves the proof of work of the checkout routes, if required:
+  // a nonce such that sha256("<payment_id>:<timestamp>:<nonce>") has pow_difficulty leading zero bits.
+  // It resolves to the "<timestamp>:<nonce>" header value, or to an empty string if not required.
+  function proofOfWork() {
+    if (!config.pow_header || !config.pow_difficulty) {
+      return Promise.resolve('');
+    }
+    if (!window.crypto || !window.crypto.subtle) {
+      return Promise.reject(new Error('The browser can not verify the payment, please use a wallet app'));
+    }
+
+    var ts = String(Math.floor(Date.now() / 1000));
+    var prefix = config.payment_id + ':' + ts + ':';
+    var encoder = new TextEncoder();
+    var nonce = 0;
+
+    function attempt() {
+      return window.crypto.subtle.digest('SHA-256', encoder.encode(prefix + nonce)).then(function (hash) {
+        if (leadingZeroBits(new Uint8Array(hash)) >= config.pow_difficulty) {
+          return ts + ':' + nonce;
+        }
+        nonce++;
+        return attempt();
+      });
+    }
+
+    return attempt();
+  }
+
+  function leadingZeroBits(bytes) {
+    var n = 0;
+    for (var i = 0; i < bytes.length; i++) {
+      if (bytes[i] !== 0) {
+        return n + Math.clz32(bytes[i]) - 24;
+      }
+      n += 8;
+    }
+    return n;
+  }
+
+  function base64ToBytes(s) {
+    var bin = atob(s);
+    var bytes = new Uint8Array(bin.length);
+    for (var i = 0; i < bin.length; i++) {
+      bytes[i] = bin.charCodeAt(i);
+    }
+    return bytes;
+  }
+
+  // the wallets loaded before the page register on the app-ready event,
+  // the ones loaded after it register with their own event
+  var api = Object.freeze({ register: registerWallets });
+  window.addEventListener('wallet-standard:register-wallet', function (e) {
+    e.detail(api);
+  });
+  try {
+    window.dispatchEvent(new CustomEvent('wallet-standard:app-ready', { detail: api }));
+  } catch (err) {
+    // the wallets register with their own event only
+  }
+
+  setStatus(config.status);
+  follow();
+})();
@@ -0,0 +1,317 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/qrcode"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Defaults of the hosted checkout page.
+const (
+	DefaultCheckoutPageColor   = "#512da8"
+	DefaultCheckoutPageAPIPath = "/payment"
+)
+
+// checkoutFiles is the hosted checkout page: the page template and its static assets.
+//
+//go:embed checkout
+var checkoutFiles embed.FS
+
+var checkoutPageTemplate = template.Must(template.ParseFS(checkoutFiles, "checkout/page.html"))
+
+type (
+	// CheckoutPage is the branding of the hosted checkout page and the public URL of the checkout page.
+	// The hosted page shows the AppName and the AppIconURI of the Config.
+	CheckoutPage struct {
+		PrimaryColor string // CSS color of the buttons and the accents, DefaultCheckoutPageColor if empty
+		SupportURL   string // merchant support link shown on the page, omitted if empty
+		Cluster      string // Solana cluster of the wallet transactions, solana.ClusterMainnet if empty
+		APIPath      string // path the payment API is mounted at, DefaultCheckoutPageAPIPath if empty
+		// ProofOfWorkDifficulty is the difficulty of the ProofOfWorkChallenge of the checkout routes, if any:
+		// the page script solves it before the transaction request of the browser wallet.
+		ProofOfWorkDifficulty int
+		// URL is the public URL of the checkout page with the {payment_id} placeholder, e.g. the hosted one
+		// https://api.example.com/checkout/{payment_id}, or the page of the merchant. The payment ID is appended
+		// if there is no placeholder. The wallet deep links of the payment links are omitted if it's empty.
+		URL string
+	}
+
+	checkoutPageService interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (*payments.Payment, error)
+		CheckoutLink(ctx context.Context, paymentID uuid.UUID, token string) (string, error)
+	}
+
+	// checkoutPageData is the data of the checkout page template.
+	checkoutPageData struct {
+		Title        string
+		IconURI      string
+		PrimaryColor string
+		SupportURL   string
+		Locale       string
+		Label        string
+		Message      string
+		Amount       string
+		Error        string
+		Link         template.URL // the solana: link, trusted since it's generated by the payments service
+		QRCode       template.HTML
+		Config       checkoutPageConfig
+	}
+
+	// checkoutPageConfig is passed to the page script as JSON.
+	checkoutPageConfig struct {
+		PaymentID      string `json:"payment_id"`
+		Status         string `json:"status"`
+		TransactionURL string `json:"transaction_url,omitempty"` // Solana Pay transaction request URL, empty if not payable
+		EventsURL      string `json:"events_url"`
+		StatusURL      string `json:"status_url"`
+		Chain          string `json:"chain"`                    // Wallet Standard chain, e.g. solana:mainnet
+		PoWHeader      string `json:"pow_header,omitempty"`     // ProofOfWorkHeader, empty if the proof of work is disabled
+		PoWDifficulty  int    `json:"pow_difficulty,omitempty"` // leading zero bits of the proof of work
+	}
+)
+
+// MakeCheckoutPageHandler returns the hosted checkout page, e.g. mounted at /checkout:
+// GET /{payment_id} is the page of the payment, GET /assets/{name} are its script and styles.
+// The page shows the Solana Pay QR code, connects the browser wallets supporting the Wallet Standard,
+// and follows the payment status with the events stream of the payment API.
+// The page of the links with the access tokens, see payments.Config.LinkTokenTTL, requires the token of the link
+// it's opened with, see CheckoutPage.PaymentURL; the page reuses the token, so it issues none and fires no events.
+// The checkout middlewares are applied to the page, e.g. CheckoutProtection.PageMiddleware:
+// the page can't pass the checkout challenge, its script passes the proof of work on the transaction request.
+func MakeCheckoutPageHandler(ps checkoutPageService, tm tokenMetadataProvider, cfg Config, log logger, checkoutMdw ...middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	assets, err := fs.Sub(checkoutFiles, "checkout/assets")
+	if err != nil {
+		panic(err)
+	}
+	r.Get("/assets/{name}", serveCheckoutAsset(assets))
+
+	r.Group(func(r chi.Router) {
+		for _, mdw := range checkoutMdw {
+			r.Use(mdw)
+		}
+
+		r.Get("/{payment_id}", func(w http.ResponseWriter, r *http.Request) {
+			status, data := checkoutPage(r, ps, tm, cfg, log)
+
+			var buf bytes.Buffer
+			if err := checkoutPageTemplate.Execute(&buf, data); err != nil {
+				log.Log("msg", "checkout page: failed to render page", "err", err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			// the payment link may carry the single-use access token, so the page is never cached
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("X-Frame-Options", "DENY")
+			w.WriteHeader(status)
+			w.Write(buf.Bytes())
+		})
+	})
+
+	return r
+}
+
+// PaymentURL returns the public URL of the checkout page of the payment with the access token
+// of the payment link, if any, empty if the URL is not set.
+func (p CheckoutPage) PaymentURL(paymentID uuid.UUID, locale, token string) string {
+	if p.URL == "" {
+		return ""
+	}
+
+	result := strings.ReplaceAll(p.URL, "{payment_id}", paymentID.String())
+	if result == p.URL {
+		result = strings.TrimRight(p.URL, "/") + "/" + paymentID.String()
+	}
+	if locale != "" {
+		sep := "?"
+		if strings.Contains(result, "?") {
+			sep = "&"
+		}
+		result += sep + "locale=" + url.QueryEscape(payments.NormalizeLocale(locale))
+	}
+	if token != "" {
+		sep := "?"
+		if strings.Contains(result, "?") {
+			sep = "&"
+		}
+		result += sep + payments.LinkTokenParam + "=" + url.QueryEscape(token)
+	}
+
+	return result
+}
+
+// linkToken returns the access token of the Solana Pay link, see payments.LinkTokenParam, empty if it has none.
+func linkToken(link string) string {
+	uri := strings.TrimPrefix(link, "solana:")
+	if decoded, err := url.QueryUnescape(uri); err == nil {
+		uri = decoded
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get(payments.LinkTokenParam)
+}
+
+// checkoutPage returns the response status and the page data of the payment with the {payment_id} URL parameter.
+// The page of the payment which can't be paid anymore shows its status only.
+func checkoutPage(r *http.Request, ps checkoutPageService, tm tokenMetadataProvider, cfg Config, log logger) (int, checkoutPageData) {
+	ctx := r.Context()
+	branding := cfg.CheckoutPage
+
+	data := checkoutPageData{
+		Title:        cfg.AppName,
+		IconURI:      cfg.AppIconURI,
+		PrimaryColor: branding.PrimaryColor,
+		SupportURL:   branding.SupportURL,
+		Locale:       localeFromRequest(r),
+		Label:        cfg.AppName,
+	}
+	if data.PrimaryColor == "" {
+		data.PrimaryColor = DefaultCheckoutPageColor
+	}
+
+	paymentID, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+	if err != nil {
+		data.Error = "Payment not found"
+		return http.StatusNotFound, data
+	}
+	payment, err := ps.GetPayment(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			data.Error = "Payment not found"
+			return http.StatusNotFound, data
+		}
+		log.Log("msg", "checkout page: failed to get payment", "payment_id", paymentID, "err", err)
+		data.Error = "The payment is temporarily unavailable, please try again later"
+		return http.StatusInternalServerError, data
+	}
+
+	t := payment.Translate(data.Locale)
+	if t.Label != "" {
+		data.Label = t.Label
+	}
+	data.Message = t.Message
+	data.Amount = checkoutPageAmount(ctx, tm, payment)
+
+	apiPath := strings.TrimRight(branding.APIPath, "/")
+	if apiPath == "" {
+		apiPath = DefaultCheckoutPageAPIPath
+	}
+	cluster := branding.Cluster
+	if cluster == "" {
+		cluster = solana.ClusterMainnet
+	}
+	data.Config = checkoutPageConfig{
+		PaymentID: paymentID.String(),
+		Status:    string(payment.Status),
+		EventsURL: apiPath + "/checkout/" + paymentID.String() + "/events",
+		StatusURL: apiPath + "/pid/" + paymentID.String() + "/status",
+		Chain:     "solana:" + strings.TrimSuffix(cluster, "-beta"),
+	}
+	if branding.ProofOfWorkDifficulty > 0 {
+		data.Config.PoWHeader = ProofOfWorkHeader
+		data.Config.PoWDifficulty = branding.ProofOfWorkDifficulty
+	}
+
+	if !payment.Payable() || payment.Expired() {
+		return http.StatusOK, data
+	}
+
+	link, err := ps.CheckoutLink(ctx, paymentID, r.URL.Query().Get(payments.LinkTokenParam))
+	if err != nil {
+		switch {
+		case errors.Is(err, payments.ErrPaymentExpired):
+			data.Config.Status = string(payments.PaymentStatusExpired)
+			return http.StatusOK, data
+		case errors.Is(err, payments.ErrInvalidLinkToken):
+			data.Error = "The payment link is invalid, please request a new one"
+			return http.StatusForbidden, data
+		case errors.Is(err, payments.ErrLinkTokenUsed), errors.Is(err, payments.ErrLinkExpired):
+			data.Error = "The payment link is no longer valid, please request a new one"
+			return http.StatusGone, data
+		}
+		log.Log("msg", "checkout page: failed to get payment link", "payment_id", paymentID, "err", err)
+		data.Error = "The payment is temporarily unavailable, please try again later"
+		return http.StatusInternalServerError, data
+	}
+	if data.Locale != "" {
+		link = withLocale(link, data.Locale)
+	}
+	data.Link = template.URL(link)
+
+	// the browser wallets post to the transaction request URL directly
+	txURL := strings.TrimPrefix(link, "solana:")
+	if decoded, err := url.QueryUnescape(txURL); err == nil {
+		txURL = decoded
+	}
+	data.Config.TransactionURL = txURL
+
+	qr, err := qrcode.Encode([]byte(link))
+	if err != nil {
+		log.Log("msg", "checkout page: failed to encode qr code", "payment_id", paymentID, "err", err)
+	} else {
+		data.QRCode = template.HTML(qr.SVG(4))
+	}
+
+	return http.StatusOK, data
+}
+
+// checkoutPageAmount returns the formatted amount due of the payment, e.g. "12.5 USDC" or "10.00 USD",
+// or an empty string if the mint metadata is not available.
+func checkoutPageAmount(ctx context.Context, tm tokenMetadataProvider, payment *payments.Payment) string {
+	if payment.FiatCurrency != "" && payment.AmountPaid == 0 {
+		return fmt.Sprintf("%d.%02d %s", payment.FiatAmount/100, payment.FiatAmount%100, payment.FiatCurrency)
+	}
+
+	amount := newAmount(ctx, tm, payment.AmountDue(), payment.DestinationMint)
+	if amount == nil || amount.Symbol == "" {
+		return ""
+	}
+	return amount.UIAmountString + " " + amount.Symbol
+}
+
+// serveCheckoutAsset serves the static asset of the checkout page with the {name} URL parameter.
+func serveCheckoutAsset(assets fs.FS) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "name")
+		f, err := assets.Open(name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+
+		rs, ok := f.(io.ReadSeeker)
+		if stat, err := f.Stat(); err != nil || stat.IsDir() || !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		// unlike the API responses, the assets are cacheable
+		for _, h := range []string{"Expires", "Pragma", "X-Accel-Expires"} {
+			w.Header().Del(h)
+		}
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+		http.ServeContent(w, r, name, time.Time{}, rs)
+	}
+}
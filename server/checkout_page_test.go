@@ -0,0 +1,130 @@
+package server_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/checkouttest"
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/server"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/stretchr/testify/require"
+)
+
+// linkTokenCounter counts the issued access tokens of the payment links.
+type linkTokenCounter struct {
+	*checkouttest.PaymentRepository
+	issued int32
+}
+
+func (r *linkTokenCounter) CreateLinkToken(ctx context.Context, arg repository.CreateLinkTokenParams) error {
+	atomic.AddInt32(&r.issued, 1)
+	return r.PaymentRepository.CreateLinkToken(ctx, arg)
+}
+
+type noTokenMetadata struct{}
+
+func (noTokenMetadata) GetFungibleTokenMetadata(context.Context, string) (*solana.FungibleTokenMetadata, error) {
+	return nil, errors.New("not available")
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Log(...interface{}) error { return nil }
+
+func TestCheckoutPageReusesLinkToken(t *testing.T) {
+	ctx := context.Background()
+	payment := checkouttest.NewPayment(checkouttest.WithAmount(1000))
+	repo := &linkTokenCounter{PaymentRepository: checkouttest.NewPaymentRepository(checkouttest.RepositoryPayment(payment))}
+
+	var fired int32
+	svc := payments.NewServiceEvents(
+		payments.NewService(repo, checkouttest.NewSolanaClient(), checkouttest.NewJupiterClient(1), payments.Config{
+			DestinationMint:   "SOL",
+			DestinationWallet: checkouttest.MerchantWallet,
+			SolPayBaseURL:     "https://api.example.com/payment/checkout",
+			LinkTokenTTL:      time.Hour,
+		}),
+		func(events.EventName, interface{}) { atomic.AddInt32(&fired, 1) },
+	)
+
+	link, err := svc.GeneratePaymentLink(ctx, payment.ID, "", false)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, atomic.LoadInt32(&repo.issued))
+	require.EqualValues(t, 1, atomic.LoadInt32(&fired))
+
+	pageURL := server.CheckoutPage{URL: "https://api.example.com/checkout/{payment_id}"}.
+		PaymentURL(payment.ID, "", tokenOf(t, link))
+	require.Contains(t, pageURL, payments.LinkTokenParam+"=")
+
+	handler := server.MakeCheckoutPageHandler(svc, noTokenMetadata{}, server.Config{AppName: "Shop"}, nopLogger{})
+	serve := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	// the page is loaded twice with the token of the link
+	for i := 0; i < 2; i++ {
+		w := serve(strings.TrimPrefix(pageURL, "https://api.example.com/checkout"))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), tokenOf(t, link))
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&repo.issued), "the page issues no tokens")
+	require.EqualValues(t, 1, atomic.LoadInt32(&fired), "the page fires no events")
+
+	// the page requires the token of the link
+	require.Equal(t, http.StatusForbidden, serve("/"+payment.ID.String()).Code)
+	require.Equal(t, http.StatusForbidden, serve("/"+payment.ID.String()+"?token=unknown").Code)
+}
+
+// tokenOf returns the access token of the Solana Pay link.
+func tokenOf(t *testing.T, link string) string {
+	t.Helper()
+	i := strings.Index(link, payments.LinkTokenParam+"%3D")
+	require.True(t, i >= 0, link)
+	token := link[i+len(payments.LinkTokenParam+"%3D"):]
+	if j := strings.Index(token, "%26"); j >= 0 {
+		token = token[:j]
+	}
+	return token
+}
+
+func TestCheckoutPageProofOfWork(t *testing.T) {
+	ctx := context.Background()
+	payment := checkouttest.NewPayment(checkouttest.WithAmount(1000))
+	svc := payments.NewService(
+		checkouttest.NewPaymentRepository(checkouttest.RepositoryPayment(payment)),
+		checkouttest.NewSolanaClient(),
+		checkouttest.NewJupiterClient(1),
+		payments.Config{
+			DestinationMint:   "SOL",
+			DestinationWallet: checkouttest.MerchantWallet,
+			SolPayBaseURL:     "https://api.example.com/payment/checkout",
+		},
+	)
+	link, err := svc.GeneratePaymentLink(ctx, payment.ID, "", false)
+	require.NoError(t, err)
+	require.NotEmpty(t, link)
+
+	protection := server.NewCheckoutProtection(nopLogger{}, server.WithChallenge(server.ProofOfWorkChallenge(8)))
+	handler := server.MakeCheckoutPageHandler(svc, noTokenMetadata{}, server.Config{
+		AppName:      "Shop",
+		CheckoutPage: server.CheckoutPage{ProofOfWorkDifficulty: 8},
+	}, nopLogger{}, protection.PageMiddleware)
+
+	// the page is served without the challenge and passes its parameters to the script
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+payment.ID.String(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Contains(t, w.Body.String(), `"pow_header":"`+server.ProofOfWorkHeader+`"`)
+	require.Contains(t, w.Body.String(), `"pow_difficulty":8`)
+}
@@ -0,0 +1,82 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckoutTokenParam is the query parameter of the checkout token on the WebSocket handshake,
+// the browsers can't set the headers of the WebSocket requests.
+const CheckoutTokenParam = "token"
+
+// DefaultCheckoutTokenTTL is the lifetime of the checkout tokens if the ttl is 0.
+const DefaultCheckoutTokenTTL = 5 * time.Minute
+
+type (
+	// CheckoutTokens issues and verifies the short-lived checkout tokens, the HMAC-signed
+	// payment ID with the expiration time. The merchant backend issues the token of the payment
+	// for its checkout page, which opens the checkout WebSocket with it.
+	// The token is checked on the handshake only, the open connection outlives it.
+	CheckoutTokens struct {
+		key []byte
+		ttl time.Duration
+	}
+
+	// CheckoutToken is the issued checkout token.
+	CheckoutToken struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+)
+
+// NewCheckoutTokens creates a new checkout tokens signer, DefaultCheckoutTokenTTL is used if the ttl is 0.
+func NewCheckoutTokens(key []byte, ttl time.Duration) *CheckoutTokens {
+	if ttl <= 0 {
+		ttl = DefaultCheckoutTokenTTL
+	}
+	return &CheckoutTokens{key: key, ttl: ttl}
+}
+
+// Issue returns a new checkout token of the payment: "<expiration unix timestamp>.<signature>".
+func (t *CheckoutTokens) Issue(paymentID uuid.UUID) CheckoutToken {
+	exp := time.Now().Add(t.ttl).Truncate(time.Second)
+	return CheckoutToken{
+		Token:     strconv.FormatInt(exp.Unix(), 10) + "." + t.signature(paymentID, exp.Unix()),
+		ExpiresAt: exp,
+	}
+}
+
+// Verify checks the checkout token of the payment.
+// It returns ErrInvalidCheckoutToken if the token is malformed, forged or issued for another payment,
+// ErrCheckoutTokenExpired if it's expired.
+func (t *CheckoutTokens) Verify(paymentID uuid.UUID, token string) error {
+	expStr, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return fmt.Errorf("%w: malformed token", ErrInvalidCheckoutToken)
+	}
+	exp, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed expiration time", ErrInvalidCheckoutToken)
+	}
+	if !hmac.Equal([]byte(sig), []byte(t.signature(paymentID, exp))) {
+		return ErrInvalidCheckoutToken
+	}
+	if time.Now().Unix() > exp {
+		return ErrCheckoutTokenExpired
+	}
+	return nil
+}
+
+// signature returns the base64url encoded HMAC-SHA256 of the payment ID and the expiration time.
+func (t *CheckoutTokens) signature(paymentID uuid.UUID, exp int64) string {
+	h := hmac.New(sha256.New, t.key)
+	h.Write([]byte("checkout:v1:" + paymentID.String() + ":" + strconv.FormatInt(exp, 10)))
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
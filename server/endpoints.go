@@ -3,13 +3,17 @@ package server
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"time"
 
+	"github.com/easypmnt/checkout-api/auth"
 	"github.com/easypmnt/checkout-api/internal/utils"
 	"github.com/easypmnt/checkout-api/internal/validator"
 	"github.com/easypmnt/checkout-api/jupiter"
 	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/easypmnt/checkout-api/timeline"
 	"github.com/go-kit/kit/endpoint"
 	"github.com/google/uuid"
 )
@@ -20,16 +24,37 @@ type (
 		GetAppInfo                 endpoint.Endpoint
 		CreatePayment              endpoint.Endpoint
 		CancelPayment              endpoint.Endpoint
+		ReleasePayment             endpoint.Endpoint
+		CreateDepositAddress       endpoint.Endpoint
 		GetPayment                 endpoint.Endpoint
 		GetPaymentByExternalID     endpoint.Endpoint
+		GetPaymentStatus           endpoint.Endpoint
+		GetPaymentTimeline         endpoint.Endpoint
+		ExportPayments             endpoint.Endpoint
+		IssueCheckoutToken         endpoint.Endpoint
 		GeneratePaymentLink        endpoint.Endpoint
 		GeneratePaymentTransaction endpoint.Endpoint
+		QuotePaymentTransaction    endpoint.Endpoint
+		GetWalletTokens            endpoint.Endpoint
 		GetExchangeRate            endpoint.Endpoint
+		RecheckTransaction         endpoint.Endpoint
 	}
 
 	Config struct {
 		AppName    string // AppName is the name of the application to be displayed in the payment page and wallet.
 		AppIconURI string // AppIconURI is the URI of the application icon to be displayed in the payment page and wallet.
+		// PaymentStatusCacheTTL is the time the payment status is cached for on the server and the clients.
+		// DefaultPaymentStatusCacheTTL is used if it's zero.
+		PaymentStatusCacheTTL time.Duration
+		// Explorer builds the block explorer URLs of the transaction signatures in the responses.
+		// The URLs are omitted if it's nil.
+		Explorer *solana.Explorer
+		// CheckoutTokens issues the tokens of the checkout WebSocket, see CheckoutWebSocket.
+		// The tokens are disabled if it's nil.
+		CheckoutTokens *CheckoutTokens
+		// CheckoutPage is the branding of the hosted checkout page, see MakeCheckoutPageHandler,
+		// and the checkout page URL of the wallet deep links.
+		CheckoutPage CheckoutPage
 	}
 
 	paymentService interface {
@@ -39,38 +64,81 @@ type (
 		GetPayment(ctx context.Context, id uuid.UUID) (*payments.Payment, error)
 		// GetPaymentByExternalID returns the payment with the given external ID.
 		GetPaymentByExternalID(ctx context.Context, externalID string) (*payments.Payment, error)
+		// GetPaymentStatus returns only the status of the payment with the given ID.
+		GetPaymentStatus(ctx context.Context, id uuid.UUID) (*payments.PaymentStatusInfo, error)
 		// GeneratePaymentLink generates a new payment link for the given payment.
 		GeneratePaymentLink(ctx context.Context, paymentID uuid.UUID, mint string, applyBonus bool) (string, error)
+		// GenerateTransferRequest generates the Solana Pay transfer-request link of the payment.
+		GenerateTransferRequest(ctx context.Context, paymentID uuid.UUID, locale string) (*payments.TransferRequest, error)
 		// CancelPayment cancels the payment with the given ID.
 		CancelPayment(ctx context.Context, id uuid.UUID) error
 		// CancelPaymentByExternalID cancels the payment with the given external ID.
 		CancelPaymentByExternalID(ctx context.Context, externalID string) error
+		// ReleasePayment transfers the held payment funds from the escrow wallet to the merchant wallet.
+		ReleasePayment(ctx context.Context, id uuid.UUID) (*payments.EscrowRelease, error)
+		// CreateDepositAddress returns the dedicated deposit address of the payment, creating it on the first call.
+		CreateDepositAddress(ctx context.Context, paymentID uuid.UUID) (*payments.DepositAddress, error)
 		// BuildTransaction builds a new transaction for the given payment.
 		BuildTransaction(ctx context.Context, tx *payments.Transaction) (*payments.Transaction, error)
+		// QuoteTransaction estimates the checkout total for the given payment, customer wallet and currency.
+		QuoteTransaction(ctx context.Context, tx *payments.Transaction) (*payments.Quote, error)
+		// GetWalletTokens returns the tokens held by the given wallet that can be used to pay for the payment.
+		GetWalletTokens(ctx context.Context, paymentID uuid.UUID, wallet string) ([]*payments.WalletToken, error)
 		// GetTransactionByReference returns the transaction with the given reference.
 		GetTransactionByReference(ctx context.Context, reference string) (*payments.Transaction, error)
+		// GetLatestTransaction returns the latest transaction generated for the payment with the given ID.
+		GetLatestTransaction(ctx context.Context, paymentID uuid.UUID) (*payments.Transaction, error)
+		// GetPaymentAttempts returns all the transactions generated for the payment, the latest first.
+		GetPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]*payments.PaymentAttempt, error)
+		// RecheckTransaction re-runs the on-chain validation of the transaction and fixes the stored status.
+		RecheckTransaction(ctx context.Context, reference string) (*payments.TransactionRecheck, error)
+	}
+
+	timelineService interface {
+		// Timeline returns the events of the payment in the order they happened.
+		Timeline(ctx context.Context, paymentID uuid.UUID) ([]timeline.Entry, error)
+	}
+
+	accountingExporter interface {
+		// ExportAccounting streams the completed transactions within the period into w in the given format.
+		ExportAccounting(ctx context.Context, w io.Writer, format string, from, to time.Time) (int64, error)
 	}
 
 	jupiterClient interface {
-		ExchangeRate(params jupiter.ExchangeRateParams) (jupiter.Rate, error)
+		ExchangeRate(ctx context.Context, params jupiter.ExchangeRateParams) (jupiter.Rate, error)
 	}
 )
 
 // MakeEndpoints returns an Endpoints struct where each field is an endpoint
 // that comprises the server.
-func MakeEndpoints(ps paymentService, jup jupiterClient, cfg Config) Endpoints {
+func MakeEndpoints(ps paymentService, jup jupiterClient, tm tokenMetadataProvider, tl timelineService, ex accountingExporter, cfg Config) Endpoints {
 	return Endpoints{
-		GetAppInfo:                 makeGetAppInfoEndpoint(cfg),
-		CreatePayment:              makeCreatePaymentEndpoint(ps),
+		GetAppInfo:                 makeGetAppInfoEndpoint(ps, cfg),
+		CreatePayment:              makeCreatePaymentEndpoint(ps, tm),
 		CancelPayment:              makeCancelPaymentEndpoint(ps),
-		GetPayment:                 makeGetPaymentEndpoint(ps),
-		GetPaymentByExternalID:     makeGetPaymentByExternalIDEndpoint(ps),
-		GeneratePaymentLink:        makeGeneratePaymentLinkEndpoint(ps),
+		ReleasePayment:             makeReleasePaymentEndpoint(ps, cfg.Explorer),
+		CreateDepositAddress:       makeCreateDepositAddressEndpoint(ps),
+		GetPayment:                 makeGetPaymentEndpoint(ps, tm, cfg.Explorer),
+		GetPaymentByExternalID:     makeGetPaymentByExternalIDEndpoint(ps, tm, cfg.Explorer),
+		GetPaymentStatus:           makeGetPaymentStatusEndpoint(ps, cfg.PaymentStatusCacheTTL, cfg.Explorer),
+		GetPaymentTimeline:         makeGetPaymentTimelineEndpoint(tl),
+		ExportPayments:             makeExportPaymentsEndpoint(ex),
+		IssueCheckoutToken:         makeIssueCheckoutTokenEndpoint(ps, cfg.CheckoutTokens),
+		GeneratePaymentLink:        makeGeneratePaymentLinkEndpoint(ps, cfg.CheckoutPage),
 		GeneratePaymentTransaction: makeGeneratePaymentTransactionEndpoint(ps),
+		QuotePaymentTransaction:    makeQuotePaymentTransactionEndpoint(ps),
+		GetWalletTokens:            makeGetWalletTokensEndpoint(ps, tm),
 		GetExchangeRate:            makeGetExchangeRateEndpoint(jup),
+		RecheckTransaction:         makeRecheckTransactionEndpoint(ps, cfg.Explorer),
 	}
 }
 
+// GetAppInfoRequest is the request type for the GetAppInfo method.
+type GetAppInfoRequest struct {
+	PaymentID string
+	Locale    string
+}
+
 // GetAppInfoResponse is the response type for the GetAppInfo method.
 type GetAppInfoResponse struct {
 	Label string `json:"label"`
@@ -78,12 +146,33 @@ type GetAppInfoResponse struct {
 }
 
 // makeGetAppInfoEndpoint returns an endpoint function for the GetAppInfo method.
-func makeGetAppInfoEndpoint(cfg Config) endpoint.Endpoint {
+// If a locale is requested, the label is taken from the payment translations.
+func makeGetAppInfoEndpoint(ps paymentService, cfg Config) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
-		return GetAppInfoResponse{
+		resp := GetAppInfoResponse{
 			Label: cfg.AppName,
 			Icon:  cfg.AppIconURI,
-		}, nil
+		}
+
+		req, ok := request.(GetAppInfoRequest)
+		if !ok || req.Locale == "" {
+			return resp, nil
+		}
+
+		paymentID, err := uuid.Parse(req.PaymentID)
+		if err != nil {
+			return resp, nil
+		}
+
+		// The app info must be returned even if the payment cannot be loaded,
+		// so the wallet is able to show the default label.
+		if payment, err := ps.GetPayment(ctx, paymentID); err == nil {
+			if t := payment.Translate(req.Locale); t.Label != "" {
+				resp.Label = t.Label
+			}
+		}
+
+		return resp, nil
 	}
 }
 
@@ -91,18 +180,41 @@ func makeGetAppInfoEndpoint(cfg Config) endpoint.Endpoint {
 // For more information about the fields, see the struct definition in payment/payment.go.CreatePaymentParams
 type CreatePaymentRequest struct {
 	ExternalID string `json:"external_id,omitempty" validate:"min_len:1|max_len:50"`
-	Amount     uint64 `json:"amount,omitempty" validate:"required|gt:0"`
+	Amount     uint64 `json:"amount,omitempty" validate:"required_without:FiatCurrency|gt:0"`
 	Message    string `json:"message,omitempty" validate:"min_len:2|max_len:100"`
-	TTL        int64  `json:"ttl,omitempty" validate:"min:0|max:86400"`
+	// FiatCurrency prices the payment in the fiat currency, e.g. "USD" or "EUR", instead of the amount.
+	// The fiat amount is converted to the destination mint at the current rate when the transaction is built.
+	FiatCurrency string `json:"fiat_currency,omitempty" validate:"len:3"`
+	// FiatAmount is the price in the minor units of the fiat currency, e.g. cents.
+	FiatAmount uint64 `json:"fiat_amount,omitempty" validate:"required_with:FiatCurrency|gt:0"`
+	TTL        int64  `json:"ttl,omitempty" validate:"min:0"` // seconds, within the configured bounds
+	// CustomerEmail is an optional email address of the customer to send the payment notifications to.
+	CustomerEmail string `json:"customer_email,omitempty" validate:"email"`
+	// Translations are optional localized label and message, keyed by locale, e.g. "es" or "pt-BR".
+	Translations map[string]payments.Translation `json:"translations,omitempty" validate:"-"`
+	// FeeOnTop grosses up the amount, so the customer covers the swap slippage.
+	// The network and priority fees are always paid by the customer as the transaction fee payer.
+	FeeOnTop bool `json:"fee_on_top,omitempty" validate:"bool"`
+	// Escrow holds the paid funds in the escrow wallet until the payment is released to the merchant.
+	Escrow bool `json:"escrow,omitempty" validate:"bool"`
+	// CallbackURL is an optional https URL, which receives the payment webhooks
+	// in addition to the merchant webhook. Its host must be allowlisted.
+	CallbackURL string `json:"callback_url,omitempty" validate:"fullUrl|max_len:2048"`
+	// AllowPartial allows to pay the payment with several transactions, e.g. an installment or a top-up.
+	AllowPartial bool `json:"allow_partial,omitempty" validate:"bool"`
+	// Settings optionally override the merchant settings for this payment only:
+	// the destination mint, the bonus policy and the Solana Pay base URI.
+	Settings *payments.PaymentSettings `json:"settings,omitempty" validate:"-"`
 }
 
 // CreatePaymentResponse is the response type for the CreatePayment method.
 type CreatePaymentResponse struct {
 	Payment *payments.Payment `json:"payment"`
+	Amount  *Amount           `json:"amount,omitempty"`
 }
 
 // makeCreatePaymentEndpoint returns an endpoint function for the CreatePayment method.
-func makeCreatePaymentEndpoint(ps paymentService) endpoint.Endpoint {
+func makeCreatePaymentEndpoint(ps paymentService, tm tokenMetadataProvider) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req, ok := request.(CreatePaymentRequest)
 		if !ok {
@@ -111,21 +223,37 @@ func makeCreatePaymentEndpoint(ps paymentService) endpoint.Endpoint {
 		if v := validator.ValidateStruct(req); len(v) > 0 {
 			return nil, validator.NewValidationError(v)
 		}
+		if err := validateTranslations(req.Translations); err != nil {
+			return nil, err
+		}
 
 		payment := &payments.Payment{
-			ExternalID: req.ExternalID,
-			Amount:     req.Amount,
-			Message:    req.Message,
+			ExternalID:    req.ExternalID,
+			Amount:        req.Amount,
+			Message:       req.Message,
+			CustomerEmail: req.CustomerEmail,
+			Translations:  req.Translations,
+			FeeOnTop:      req.FeeOnTop,
+			Escrow:        req.Escrow,
+			CallbackURL:   req.CallbackURL,
+			AllowPartial:  req.AllowPartial,
+			FiatCurrency:  req.FiatCurrency,
+			FiatAmount:    req.FiatAmount,
+			Settings:      req.Settings,
 		}
 		if req.TTL > 0 {
 			payment.ExpiresAt = utils.Pointer(time.Now().Add(time.Duration(req.TTL) * time.Second))
 		}
-		payment, err := ps.CreatePayment(ctx, payment)
+		// the payments of the test client run in test mode
+		payment, err := ps.CreatePayment(payments.WithLivemode(ctx, auth.Livemode(ctx)), payment)
 		if err != nil {
 			return nil, err
 		}
 
-		return CreatePaymentResponse{Payment: payment}, nil
+		return CreatePaymentResponse{
+			Payment: payment,
+			Amount:  newAmount(ctx, tm, payment.Amount, payment.DestinationMint),
+		}, nil
 	}
 }
 
@@ -145,14 +273,89 @@ func makeCancelPaymentEndpoint(ps paymentService) endpoint.Endpoint {
 	}
 }
 
+// ReleasePaymentResponse is the response type for the ReleasePayment method.
+type ReleasePaymentResponse struct {
+	Release     *payments.EscrowRelease `json:"release"`
+	ExplorerURL string                  `json:"explorer_url,omitempty"` // block explorer URL of the release transaction
+}
+
+// makeReleasePaymentEndpoint returns an endpoint function for the ReleasePayment method.
+func makeReleasePaymentEndpoint(ps paymentService, explorer *solana.Explorer) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		paymentID, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		release, err := ps.ReleasePayment(ctx, paymentID)
+		if err != nil {
+			return nil, err
+		}
+
+		return ReleasePaymentResponse{
+			Release:     release,
+			ExplorerURL: explorer.TransactionURL(release.Signature),
+		}, nil
+	}
+}
+
+// CreateDepositAddressResponse is the response type for the CreateDepositAddress method.
+type CreateDepositAddressResponse struct {
+	DepositAddress *payments.DepositAddress `json:"deposit_address"`
+}
+
+// makeCreateDepositAddressEndpoint returns an endpoint function for the CreateDepositAddress method.
+func makeCreateDepositAddressEndpoint(ps paymentService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		paymentID, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		deposit, err := ps.CreateDepositAddress(ctx, paymentID)
+		if err != nil {
+			return nil, err
+		}
+
+		return CreateDepositAddressResponse{DepositAddress: deposit}, nil
+	}
+}
+
 // GetPaymentResponse is the response type for the GetPayment method.
 type GetPaymentResponse struct {
 	Payment     *payments.Payment     `json:"payment"`
+	Amount      *Amount               `json:"amount,omitempty"`
 	Transaction *payments.Transaction `json:"transaction,omitempty"`
+	Signature   string                `json:"signature,omitempty"`    // signature of the latest payment transaction, if any
+	ExplorerURL string                `json:"explorer_url,omitempty"` // block explorer URL of the latest payment transaction
+	// Attempts are the transactions of all the payers, e.g. several wallets paying the same payment.
+	Attempts []*payments.PaymentAttempt `json:"attempts,omitempty"`
+}
+
+// newGetPaymentResponse returns the payment response with the latest generated transaction,
+// the signature of the latest submitted one and the status of every attempt.
+// All of them are best effort: the payment is returned without them if the lookups fail.
+func newGetPaymentResponse(ctx context.Context, ps paymentService, tm tokenMetadataProvider, explorer *solana.Explorer, payment *payments.Payment) GetPaymentResponse {
+	resp := GetPaymentResponse{
+		Payment: payment,
+		Amount:  newAmount(ctx, tm, payment.Amount, payment.DestinationMint),
+	}
+	if status, err := ps.GetPaymentStatus(ctx, payment.ID); err == nil {
+		resp.Signature = status.Signature
+		resp.ExplorerURL = explorer.TransactionURL(status.Signature)
+	}
+	if tx, err := ps.GetLatestTransaction(ctx, payment.ID); err == nil {
+		resp.Transaction = tx
+	}
+	if attempts, err := ps.GetPaymentAttempts(ctx, payment.ID); err == nil {
+		resp.Attempts = attempts
+	}
+
+	return resp
 }
 
 // makeGetPaymentEndpoint returns an endpoint function for the GetPayment method.
-func makeGetPaymentEndpoint(ps paymentService) endpoint.Endpoint {
+func makeGetPaymentEndpoint(ps paymentService, tm tokenMetadataProvider, explorer *solana.Explorer) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		paymentID, ok := request.(uuid.UUID)
 		if !ok {
@@ -164,12 +367,144 @@ func makeGetPaymentEndpoint(ps paymentService) endpoint.Endpoint {
 			return nil, err
 		}
 
-		return GetPaymentResponse{Payment: payment}, nil
+		return newGetPaymentResponse(ctx, ps, tm, explorer, payment), nil
+	}
+}
+
+// GetPaymentStatusResponse is the response type for the GetPaymentStatus method.
+type GetPaymentStatusResponse struct {
+	*payments.PaymentStatusInfo
+	ExplorerURL string `json:"explorer_url,omitempty"` // block explorer URL of the latest payment transaction
+	ttl         time.Duration
+}
+
+// maxAge returns the time the response can be cached for by the clients.
+func (r GetPaymentStatusResponse) maxAge() time.Duration {
+	return r.ttl
+}
+
+// makeGetPaymentStatusEndpoint returns an endpoint function for the GetPaymentStatus method.
+// The status is cached for ttl, since the checkout pages poll it at a high frequency.
+func makeGetPaymentStatusEndpoint(ps paymentService, ttl time.Duration, explorer *solana.Explorer) endpoint.Endpoint {
+	cache := newPaymentStatusCache(ps, ttl)
+
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		paymentID, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		status, err := cache.GetPaymentStatus(ctx, paymentID)
+		if err != nil {
+			return nil, err
+		}
+
+		return GetPaymentStatusResponse{
+			PaymentStatusInfo: status,
+			ExplorerURL:       explorer.TransactionURL(status.Signature),
+			ttl:               cache.ttl,
+		}, nil
+	}
+}
+
+// GetPaymentTimelineResponse is the response type for the GetPaymentTimeline method.
+type GetPaymentTimelineResponse struct {
+	PaymentID uuid.UUID        `json:"payment_id"`
+	Timeline  []timeline.Entry `json:"timeline"`
+}
+
+// makeGetPaymentTimelineEndpoint returns an endpoint function for the GetPaymentTimeline method.
+func makeGetPaymentTimelineEndpoint(tl timelineService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		paymentID, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		entries, err := tl.Timeline(ctx, paymentID)
+		if err != nil {
+			return nil, err
+		}
+
+		return GetPaymentTimelineResponse{
+			PaymentID: paymentID,
+			Timeline:  entries,
+		}, nil
+	}
+}
+
+// makeIssueCheckoutTokenEndpoint returns an endpoint function for the IssueCheckoutToken method.
+// The merchant backend passes the token to its checkout page, which opens the checkout WebSocket with it.
+func makeIssueCheckoutTokenEndpoint(ps paymentService, tokens *CheckoutTokens) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		paymentID, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if tokens == nil {
+			return nil, ErrCheckoutTokensDisabled
+		}
+
+		if _, err := ps.GetPaymentStatus(ctx, paymentID); err != nil {
+			return nil, err
+		}
+
+		return tokens.Issue(paymentID), nil
+	}
+}
+
+// Default period of the accounting export, if it is not set in the request.
+const defaultExportPeriod = 30 * 24 * time.Hour
+
+// ExportPaymentsRequest is the request type for the ExportPayments method.
+type ExportPaymentsRequest struct {
+	Format string
+	From   time.Time
+	To     time.Time
+}
+
+// ExportPaymentsResponse is the response type for the ExportPayments method.
+// The export is streamed to the client by the transport.
+type ExportPaymentsResponse struct {
+	Format string
+	From   time.Time
+	To     time.Time
+	Export func(w io.Writer) error
+}
+
+// makeExportPaymentsEndpoint returns an endpoint function for the ExportPayments method.
+// The period defaults to the last 30 days.
+func makeExportPaymentsEndpoint(ex accountingExporter) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(ExportPaymentsRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		if req.To.IsZero() {
+			req.To = time.Now()
+		}
+		if req.From.IsZero() {
+			req.From = req.To.Add(-defaultExportPeriod)
+		}
+		if !req.From.Before(req.To) {
+			return nil, fmt.Errorf("%w: from must be before to", ErrInvalidParameter)
+		}
+
+		return ExportPaymentsResponse{
+			Format: req.Format,
+			From:   req.From,
+			To:     req.To,
+			Export: func(w io.Writer) error {
+				_, err := ex.ExportAccounting(ctx, w, req.Format, req.From, req.To)
+				return err
+			},
+		}, nil
 	}
 }
 
 // makeGetPaymentByExternalIDEndpoint returns an endpoint function for the GetPaymentByExternalID method.
-func makeGetPaymentByExternalIDEndpoint(ps paymentService) endpoint.Endpoint {
+func makeGetPaymentByExternalIDEndpoint(ps paymentService, tm tokenMetadataProvider, explorer *solana.Explorer) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		externalID, ok := request.(string)
 		if !ok {
@@ -181,7 +516,7 @@ func makeGetPaymentByExternalIDEndpoint(ps paymentService) endpoint.Endpoint {
 			return nil, err
 		}
 
-		return GetPaymentResponse{Payment: payment}, nil
+		return newGetPaymentResponse(ctx, ps, tm, explorer, payment), nil
 	}
 }
 
@@ -190,15 +525,21 @@ type GeneratePaymentLinkRequest struct {
 	PaymentID  uuid.UUID `json:"-" validate:"-" label:"Payment ID"`
 	Mint       string    `json:"mint,omitempty" validate:"-" label:"Selected Mint"`
 	ApplyBonus bool      `json:"apply_bonus,omitempty" validate:"bool" label:"Apply Bonus"`
+	Locale     string    `json:"locale,omitempty" validate:"-" label:"Locale"`
+	// Transfer requests the Solana Pay transfer-request link instead of the transaction-request one,
+	// for the wallets which don't support the transaction requests. The mint and the bonus are ignored.
+	Transfer bool `json:"transfer,omitempty" validate:"bool" label:"Transfer Request"`
 }
 
 // GeneratePaymentLinkResponse is the response type for the GeneratePaymentLink method.
 type GeneratePaymentLinkResponse struct {
 	Link string `json:"link"`
+	// DeepLinks open the payment in the mobile wallet apps, see Config.CheckoutPage.URL.
+	DeepLinks payments.WalletDeepLinks `json:"deep_links"`
 }
 
 // makeGeneratePaymentLinkEndpoint returns an endpoint function for the GeneratePaymentLink method.
-func makeGeneratePaymentLinkEndpoint(ps paymentService) endpoint.Endpoint {
+func makeGeneratePaymentLinkEndpoint(ps paymentService, page CheckoutPage) endpoint.Endpoint {
 	return func(ctx context.Context, request interface{}) (interface{}, error) {
 		req, ok := request.(GeneratePaymentLinkRequest)
 		if !ok {
@@ -208,12 +549,34 @@ func makeGeneratePaymentLinkEndpoint(ps paymentService) endpoint.Endpoint {
 			return nil, validator.NewValidationError(v)
 		}
 
+		if req.Locale != "" && !payments.ValidLocale(req.Locale) {
+			return nil, fmt.Errorf("%w: invalid locale: %s", ErrInvalidParameter, req.Locale)
+		}
+
+		// the transfer request is translated by the server, the wallet doesn't call it back
+		if req.Transfer {
+			tr, err := ps.GenerateTransferRequest(ctx, req.PaymentID, req.Locale)
+			if err != nil {
+				return nil, err
+			}
+			return GeneratePaymentLinkResponse{
+				Link:      tr.Link,
+				DeepLinks: payments.NewWalletDeepLinks(tr.Link, page.PaymentURL(req.PaymentID, req.Locale, linkToken(tr.Link))),
+			}, nil
+		}
+
 		link, err := ps.GeneratePaymentLink(ctx, req.PaymentID, req.Mint, req.ApplyBonus)
 		if err != nil {
 			return nil, err
 		}
+		if req.Locale != "" {
+			link = withLocale(link, req.Locale)
+		}
 
-		return GeneratePaymentLinkResponse{Link: link}, nil
+		return GeneratePaymentLinkResponse{
+			Link:      link,
+			DeepLinks: payments.NewWalletDeepLinks(link, page.PaymentURL(req.PaymentID, req.Locale, linkToken(link))),
+		}, nil
 	}
 }
 
@@ -223,12 +586,39 @@ type GeneratePaymentTransactionRequest struct {
 	SourceWallet string `json:"account" validate:"required" label:"Account public key"`
 	Mint         string `json:"-" validate:"-"`
 	ApplyBonus   string `json:"-" validate:"bool"`
+	ApplyVoucher string `json:"-" validate:"bool"`
+	Locale       string `json:"-" validate:"-"`
+
+	// AcceptPriceImpact confirms the swap with the price impact above the merchant limit.
+	AcceptPriceImpact string `json:"-" validate:"bool"`
+	// TxVersion is the serialization format of the transaction: legacy or 0.
+	TxVersion string `json:"-" validate:"-"`
+	// LinkToken is the single-use access token of the payment link, used up by the transaction.
+	LinkToken string `json:"-" validate:"-"`
+	// MultiTx allows to split the payment into a set of transactions if it doesn't fit in one.
+	MultiTx string `json:"-" validate:"bool"`
+	// Amount is the part of the payment amount to pay, if the payment allows partial payments.
+	// Empty means the whole amount due.
+	Amount string `json:"-" validate:"-"`
+	// TipAmount is the optional tip on top of the payment amount, in the destination mint base units.
+	TipAmount uint64 `json:"tip_amount,omitempty" validate:"-" label:"Tip amount"`
+	// TipDestination is the wallet receiving the tip, the payment destination wallet if empty.
+	TipDestination string `json:"tip_destination,omitempty" validate:"solanaWallet" label:"Tip destination"`
 }
 
 // GeneratePaymentTransactionResponse is the response type for the GeneratePaymentTransaction method.
 type GeneratePaymentTransactionResponse struct {
-	Transaction string `json:"transaction"`
-	Message     string `json:"message,omitempty"`
+	Transaction   string              `json:"transaction"`
+	Message       string              `json:"message,omitempty"`
+	Surcharge     *payments.Surcharge `json:"surcharge,omitempty"`
+	VoucherAmount uint64              `json:"voucher_amount,omitempty"`
+	TipAmount     uint64              `json:"tip_amount,omitempty"`
+	Route         *payments.SwapRoute `json:"route,omitempty"` // nil if no swap is needed
+	Version       string              `json:"version"`         // legacy or 0
+
+	// Transactions is the ordered set of the transactions to sign and send one after another,
+	// if the payment is split; Transaction is the last one then.
+	Transactions []string `json:"transactions,omitempty"`
 }
 
 // makeGeneratePaymentTransactionEndpoint returns an endpoint function for the GeneratePaymentTransaction method.
@@ -248,11 +638,34 @@ func makeGeneratePaymentTransactionEndpoint(ps paymentService) endpoint.Endpoint
 		}
 
 		applyBonus, _ := strconv.ParseBool(req.ApplyBonus)
+		applyVoucher, _ := strconv.ParseBool(req.ApplyVoucher)
+		acceptPriceImpact, _ := strconv.ParseBool(req.AcceptPriceImpact)
+		multiTx, _ := strconv.ParseBool(req.MultiTx)
+		version, err := solana.ParseTransactionVersion(req.TxVersion)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+		var partialAmount uint64
+		if req.Amount != "" {
+			partialAmount, err = strconv.ParseUint(req.Amount, 10, 64)
+			if err != nil || partialAmount == 0 {
+				return nil, fmt.Errorf("%w: invalid amount: %s", ErrInvalidParameter, req.Amount)
+			}
+		}
 		tx := &payments.Transaction{
-			PaymentID:    paymentID,
-			SourceWallet: req.SourceWallet,
-			SourceMint:   req.Mint,
-			ApplyBonus:   applyBonus,
+			PaymentID:         paymentID,
+			SourceWallet:      req.SourceWallet,
+			SourceMint:        req.Mint,
+			ApplyBonus:        applyBonus,
+			ApplyVoucher:      applyVoucher,
+			AcceptPriceImpact: acceptPriceImpact,
+			Locale:            req.Locale,
+			Version:           version,
+			LinkToken:         req.LinkToken,
+			AllowSplit:        multiTx,
+			PartialAmount:     partialAmount,
+			TipAmount:         req.TipAmount,
+			TipDestination:    req.TipDestination,
 		}
 
 		result, err := ps.BuildTransaction(ctx, tx)
@@ -261,12 +674,109 @@ func makeGeneratePaymentTransactionEndpoint(ps paymentService) endpoint.Endpoint
 		}
 
 		return GeneratePaymentTransactionResponse{
-			Transaction: result.Transaction,
-			Message:     result.Message,
+			Transaction:   result.Transaction,
+			Message:       result.Message,
+			Surcharge:     result.Surcharge,
+			VoucherAmount: result.VoucherAmount,
+			TipAmount:     result.TipAmount,
+			Route:         result.Route,
+			Version:       string(version),
+			Transactions:  result.Transactions,
 		}, nil
 	}
 }
 
+// QuotePaymentTransactionRequest is the request type for the QuotePaymentTransaction method.
+type QuotePaymentTransactionRequest struct {
+	PaymentID    uuid.UUID `json:"-" validate:"-" label:"Payment ID"`
+	SourceWallet string    `json:"account" validate:"required|solanaWallet" label:"Account public key"`
+	Mint         string    `json:"mint,omitempty" validate:"-" label:"Selected Mint"`
+	ApplyBonus   bool      `json:"apply_bonus,omitempty" validate:"bool" label:"Apply Bonus"`
+	ApplyVoucher bool      `json:"apply_voucher,omitempty" validate:"bool" label:"Apply Voucher"`
+}
+
+// QuotePaymentTransactionResponse is the response type for the QuotePaymentTransaction method.
+type QuotePaymentTransactionResponse struct {
+	Quote *payments.Quote `json:"quote"`
+}
+
+// makeQuotePaymentTransactionEndpoint returns an endpoint function for the QuotePaymentTransaction method.
+func makeQuotePaymentTransactionEndpoint(ps paymentService) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(QuotePaymentTransactionRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		quote, err := ps.QuoteTransaction(ctx, &payments.Transaction{
+			PaymentID:    req.PaymentID,
+			SourceWallet: req.SourceWallet,
+			SourceMint:   req.Mint,
+			ApplyBonus:   req.ApplyBonus,
+			ApplyVoucher: req.ApplyVoucher,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return QuotePaymentTransactionResponse{Quote: quote}, nil
+	}
+}
+
+// GetWalletTokensRequest is the request type for the GetWalletTokens method.
+type GetWalletTokensRequest struct {
+	PaymentID string `json:"payment_id" validate:"required|uuid" label:"Payment ID"`
+	Wallet    string `json:"wallet" validate:"required|solanaWallet" label:"Wallet public key"`
+}
+
+// GetWalletTokensResponse is the response type for the GetWalletTokens method.
+type GetWalletTokensResponse struct {
+	Tokens []WalletToken `json:"tokens"`
+}
+
+// WalletToken is a wallet token acceptable for the payment, with the token symbol if it's known.
+type WalletToken struct {
+	*payments.WalletToken
+	Symbol string `json:"symbol,omitempty"`
+}
+
+// makeGetWalletTokensEndpoint returns an endpoint function for the GetWalletTokens method.
+func makeGetWalletTokensEndpoint(ps paymentService, tm tokenMetadataProvider) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(GetWalletTokensRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		paymentID, err := uuid.Parse(req.PaymentID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid payment ID: %v", ErrInvalidParameter, err)
+		}
+
+		tokens, err := ps.GetWalletTokens(ctx, paymentID, req.Wallet)
+		if err != nil {
+			return nil, err
+		}
+
+		result := make([]WalletToken, 0, len(tokens))
+		for _, t := range tokens {
+			token := WalletToken{WalletToken: t}
+			if amount := newAmount(ctx, tm, t.Balance.Amount, t.Mint); amount != nil {
+				token.Symbol = amount.Symbol
+			}
+			result = append(result, token)
+		}
+
+		return GetWalletTokensResponse{Tokens: result}, nil
+	}
+}
+
 // GetExchangeRateRequest is the request type for the GetExchangeRate method.
 type GetExchangeRateRequest struct {
 	InCurrency  string `json:"in_currency" validate:"required" label:"In Currency"`
@@ -291,7 +801,7 @@ func makeGetExchangeRateEndpoint(jup jupiterClient) endpoint.Endpoint {
 			return nil, validator.NewValidationError(v)
 		}
 
-		rate, err := jup.ExchangeRate(jupiter.ExchangeRateParams{
+		rate, err := jup.ExchangeRate(ctx, jupiter.ExchangeRateParams{
 			InputMint:  currency.InCurrency,
 			OutputMint: currency.OutCurrency,
 			Amount:     currency.Amount,
@@ -306,3 +816,31 @@ func makeGetExchangeRateEndpoint(jup jupiterClient) endpoint.Endpoint {
 		}, nil
 	}
 }
+
+// RecheckTransactionResponse is the response type for the RecheckTransaction method.
+type RecheckTransactionResponse struct {
+	Recheck     *payments.TransactionRecheck `json:"recheck"`
+	ExplorerURL string                       `json:"explorer_url,omitempty"` // block explorer URL of the matched transaction
+}
+
+// makeRecheckTransactionEndpoint returns an endpoint function for the RecheckTransaction method.
+func makeRecheckTransactionEndpoint(ps paymentService, explorer *solana.Explorer) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		reference, ok := request.(string)
+		if !ok || reference == "" {
+			return nil, ErrInvalidRequest
+		}
+
+		recheck, err := ps.RecheckTransaction(ctx, reference)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := RecheckTransactionResponse{Recheck: recheck}
+		if recheck.Match != nil {
+			resp.ExplorerURL = explorer.TransactionURL(recheck.Match.Signature)
+		}
+
+		return resp, nil
+	}
+}
@@ -4,7 +4,10 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/easypmnt/checkout-api/internal/deadline"
 	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/solana"
 )
 
 // Predefined errors.
@@ -13,6 +16,13 @@ var (
 	ErrInvalidParameter = errors.New("invalid_parameter")
 	ErrForbidden        = errors.New("forbidden")
 	ErrNotFound         = errors.New("not_found")
+
+	ErrTooManyRequests   = errors.New("too_many_requests")
+	ErrChallengeRequired = errors.New("challenge_required")
+
+	ErrCheckoutTokensDisabled = errors.New("checkout_tokens_disabled")
+	ErrInvalidCheckoutToken   = errors.New("invalid_checkout_token")
+	ErrCheckoutTokenExpired   = errors.New("checkout_token_expired")
 )
 
 // Error codes map
@@ -21,6 +31,46 @@ var ErrorCodes = map[error]int{
 	ErrInvalidParameter: http.StatusBadRequest,
 	ErrForbidden:        http.StatusForbidden,
 	ErrNotFound:         http.StatusNotFound,
+
+	ErrTooManyRequests:   http.StatusTooManyRequests,
+	ErrChallengeRequired: http.StatusForbidden,
+
+	ErrCheckoutTokensDisabled: http.StatusNotImplemented,
+	ErrInvalidCheckoutToken:   http.StatusForbidden,
+	ErrCheckoutTokenExpired:   http.StatusUnauthorized,
+
+	payments.ErrInvalidMint:         http.StatusBadRequest,
+	payments.ErrVoucherNotSupported: http.StatusBadRequest,
+	payments.ErrEscrowNotSupported:  http.StatusBadRequest,
+	payments.ErrTestModeDisabled:    http.StatusBadRequest,
+	payments.ErrInvalidTTL:          http.StatusBadRequest,
+	payments.ErrCallbackURLDenied:   http.StatusBadRequest,
+	payments.ErrInvalidDestination:  http.StatusBadRequest,
+	payments.ErrPriceImpactTooHigh:  http.StatusUnprocessableEntity,
+	payments.ErrPaymentExpired:      http.StatusGone,
+	payments.ErrPaymentNotHeld:      http.StatusConflict,
+	payments.ErrPaymentExists:       http.StatusConflict,
+	solana.ErrBelowRentExemption:    http.StatusBadRequest,
+	solana.ErrChainUnavailable:      http.StatusServiceUnavailable,
+	solana.ErrTransactionTooLarge:   http.StatusUnprocessableEntity,
+	deadline.ErrDeadlineExceeded:    http.StatusGatewayTimeout,
+
+	payments.ErrInvalidLinkSignature: http.StatusForbidden,
+	payments.ErrLinkExpired:          http.StatusGone,
+
+	payments.ErrInvalidLinkToken: http.StatusForbidden,
+	payments.ErrLinkTokenUsed:    http.StatusGone,
+
+	payments.ErrDepositAddressNotSupported: http.StatusBadRequest,
+
+	payments.ErrPartialPaymentNotAllowed: http.StatusBadRequest,
+
+	payments.ErrInvalidSettings: http.StatusBadRequest,
+
+	payments.ErrFiatPricingNotSupported: http.StatusBadRequest,
+	payments.ErrUnsupportedFiatCurrency: http.StatusBadRequest,
+	payments.ErrFiatPricedPayment:       http.StatusBadRequest,
+	payments.ErrExchangeRateUnavailable: http.StatusServiceUnavailable,
 }
 
 // Error messages
@@ -29,6 +79,13 @@ var ErrorMessages = map[error]string{
 	ErrInvalidParameter: "Some parameters are invalid",
 	ErrForbidden:        "Forbidden. You don't have permission to access this account",
 	ErrNotFound:         "Not found",
+
+	ErrTooManyRequests: "Too many requests, please try again later",
+
+	solana.ErrChainUnavailable:   "Solana network is temporarily unavailable, please try again later",
+	deadline.ErrDeadlineExceeded: deadline.Message,
+
+	payments.ErrExchangeRateUnavailable: "Exchange rate is temporarily unavailable, please try again later",
 }
 
 // NewError creates a new error
@@ -52,11 +109,19 @@ func NewError(err error) *httpencoder.ErrorResponse {
 		msg = err.Error()
 	}
 
-	return &httpencoder.ErrorResponse{
+	resp := &httpencoder.ErrorResponse{
 		Code:    code,
 		Error:   errStr,
 		Message: msg,
 	}
+
+	// the client can fetch the existing payment instead of creating a new one
+	var existsErr *payments.PaymentExistsError
+	if errors.As(err, &existsErr) {
+		resp.Details = map[string]string{"payment_id": existsErr.PaymentID.String()}
+	}
+
+	return resp
 }
 
 func findError(err error) error {
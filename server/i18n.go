@@ -0,0 +1,78 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/payments"
+)
+
+// Max length of the translated texts, the same as for the default payment message.
+const (
+	maxTranslationLabelLen   = 100
+	maxTranslationMessageLen = 100
+)
+
+// validateTranslations checks the locales and the length of the translated texts.
+func validateTranslations(translations map[string]payments.Translation) error {
+	for locale, t := range translations {
+		if !payments.ValidLocale(locale) {
+			return fmt.Errorf("%w: invalid translation locale: %s", ErrInvalidParameter, locale)
+		}
+		if len([]rune(t.Label)) > maxTranslationLabelLen || len([]rune(t.Message)) > maxTranslationMessageLen {
+			return fmt.Errorf("%w: translation %s is too long", ErrInvalidParameter, locale)
+		}
+	}
+	return nil
+}
+
+// withLocale adds the locale query parameter to the Solana Pay transaction request link.
+// The link URL must be URL-encoded since it contains a query string.
+// The link may already have a query, e.g. the signature or the access token, then it's decoded first.
+func withLocale(link, locale string) string {
+	uri := strings.TrimPrefix(link, "solana:")
+	sep := "?"
+	if decoded, err := url.QueryUnescape(uri); err == nil && strings.Contains(decoded, "?") {
+		uri, sep = decoded, "&"
+	}
+	return "solana:" + url.QueryEscape(uri+sep+"locale="+url.QueryEscape(payments.NormalizeLocale(locale)))
+}
+
+// localeFromRequest returns the requested locale from the "locale" query parameter
+// or the most preferred language of the Accept-Language header.
+func localeFromRequest(r *http.Request) string {
+	if locale := r.URL.Query().Get("locale"); locale != "" && payments.ValidLocale(locale) {
+		return payments.NormalizeLocale(locale)
+	}
+
+	type lang struct {
+		tag string
+		q   float64
+	}
+	var langs []lang
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" || !payments.ValidLocale(tag) {
+			continue
+		}
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			if f, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64); err == nil {
+				q = f
+			}
+		}
+		if q > 0 {
+			langs = append(langs, lang{tag: tag, q: q})
+		}
+	}
+	if len(langs) == 0 {
+		return ""
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].q > langs[j].q })
+
+	return payments.NormalizeLocale(langs[0].tag)
+}
@@ -0,0 +1,290 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"math"
+	"math/bits"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/ratelimit"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/go-chi/chi/v5"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+)
+
+// ProofOfWorkHeader is the request header with the proof of work solution: "<unix timestamp>:<nonce>".
+const ProofOfWorkHeader = "X-Checkout-PoW"
+
+// proofOfWorkMaxAge is the maximum age of the proof of work timestamp.
+const proofOfWorkMaxAge = 5 * time.Minute
+
+type (
+	// Challenge verifies the abuse protection challenge of the request,
+	// e.g. a captcha token or a proof of work. It returns an error if the challenge is not passed.
+	Challenge func(r *http.Request) error
+
+	// CheckoutProtection protects the public checkout endpoints, which trigger expensive RPC
+	// and Jupiter calls, with stricter rate limits per client IP and per payment ID
+	// and an optional challenge.
+	CheckoutProtection struct {
+		mu        sync.RWMutex // guards the limiters, see SetRateLimits
+		byIP      *ratelimit.Limiter
+		byPayment *ratelimit.Limiter
+
+		challenge   Challenge
+		linkSigner  *payments.LinkSigner
+		linkTokens  LinkTokenVerifier
+		byToken     *ratelimit.Limiter
+		encodeError httptransport.ErrorEncoder
+	}
+
+	// LinkTokenVerifier checks the single-use access token of the payment link, see payments.Service.VerifyLinkToken.
+	LinkTokenVerifier interface {
+		VerifyLinkToken(ctx context.Context, paymentID uuid.UUID, token string) error
+	}
+
+	// CheckoutProtectionOption is a function that configures the CheckoutProtection.
+	CheckoutProtectionOption func(*CheckoutProtection)
+)
+
+// NewCheckoutProtection creates a new checkout endpoints protection.
+// Without options, it allows all requests.
+func NewCheckoutProtection(log logger, opts ...CheckoutProtectionOption) *CheckoutProtection {
+	p := &CheckoutProtection{
+		encodeError: httpencoder.EncodeError(log, codeAndMessageFrom),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithIPRateLimit limits the number of checkout requests per client IP.
+// Zero limit disables it.
+func WithIPRateLimit(limit int, period time.Duration) CheckoutProtectionOption {
+	return func(p *CheckoutProtection) {
+		if limit > 0 {
+			p.byIP = ratelimit.New(limit, period)
+		}
+	}
+}
+
+// WithPaymentRateLimit limits the number of checkout requests per payment ID from all clients.
+// Zero limit disables it.
+func WithPaymentRateLimit(limit int, period time.Duration) CheckoutProtectionOption {
+	return func(p *CheckoutProtection) {
+		if limit > 0 {
+			p.byPayment = ratelimit.New(limit, period)
+		}
+	}
+}
+
+// WithChallenge requires every checkout request to pass the given challenge.
+// Note that wallets call the Solana Pay transaction request endpoints directly,
+// so the challenge can only be used if the checkout page proxies those requests,
+// e.g. the hosted one solves the ProofOfWorkChallenge, see CheckoutPage.ProofOfWorkDifficulty.
+func WithChallenge(challenge Challenge) CheckoutProtectionOption {
+	return func(p *CheckoutProtection) {
+		p.challenge = challenge
+	}
+}
+
+// WithLinkSigner requires the signed payment links on the checkout routes, see payments.Config.LinkSigner.
+// The links without the signature, e.g. generated before the signing was enabled, are rejected.
+func WithLinkSigner(signer *payments.LinkSigner) CheckoutProtectionOption {
+	return func(p *CheckoutProtection) {
+		p.linkSigner = signer
+	}
+}
+
+// WithLinkTokens requires the single-use access token on the payment link routes, see payments.Config.LinkTokenTTL.
+// The requests with the missing, used or expired tokens are rejected before any RPC call,
+// and the requests per token are limited to the given number, zero means no limit.
+func WithLinkTokens(verifier LinkTokenVerifier, limit int, period time.Duration) CheckoutProtectionOption {
+	return func(p *CheckoutProtection) {
+		p.linkTokens = verifier
+		if limit > 0 {
+			p.byToken = ratelimit.New(limit, period)
+		}
+	}
+}
+
+// SetRateLimits replaces the limits per client IP and per payment ID, e.g. on the config reload.
+// Zero limit disables the corresponding limiter.
+func (p *CheckoutProtection) SetRateLimits(perIP, perPayment int, period time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.byIP = updateLimiter(p.byIP, perIP, period)
+	p.byPayment = updateLimiter(p.byPayment, perPayment, period)
+}
+
+// updateLimiter returns the limiter with the given limit, keeping the counters of the existing one.
+func updateLimiter(l *ratelimit.Limiter, limit int, period time.Duration) *ratelimit.Limiter {
+	switch {
+	case limit <= 0:
+		return nil
+	case l == nil:
+		return ratelimit.New(limit, period)
+	default:
+		l.SetLimit(limit, period)
+		return l
+	}
+}
+
+// limiters returns the current limiters per client IP and per payment ID.
+func (p *CheckoutProtection) limiters() (byIP, byPayment *ratelimit.Limiter) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.byIP, p.byPayment
+}
+
+// Middleware is a chi middleware that applies the protection to the routes with a payment_id or address parameter.
+// The link signature and the link token are verified on the payment link routes, the ones with a mint parameter.
+func (p *CheckoutProtection) Middleware(next http.Handler) http.Handler {
+	return p.protect(next, true)
+}
+
+// PageMiddleware is Middleware without the challenge, e.g. for the hosted checkout page:
+// the page is opened by the browser navigation, which can't pass the challenge,
+// and the page script passes it on the transaction request instead, see CheckoutPage.ProofOfWorkDifficulty.
+func (p *CheckoutProtection) PageMiddleware(next http.Handler) http.Handler {
+	return p.protect(next, false)
+}
+
+// protect applies the rate limits, the link checks and, optionally, the challenge to the request.
+func (p *CheckoutProtection) protect(next http.Handler, challenge bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		byIP, byPayment := p.limiters()
+
+		if byIP != nil {
+			if ok, retryAfter := byIP.Allow(clientIP(r)); !ok {
+				p.tooManyRequests(w, r, retryAfter)
+				return
+			}
+		}
+
+		if pid := chi.URLParam(r, "payment_id"); pid != "" && byPayment != nil {
+			if ok, retryAfter := byPayment.Allow(pid); !ok {
+				p.tooManyRequests(w, r, retryAfter)
+				return
+			}
+		}
+
+		if p.linkSigner != nil && chi.URLParam(r, "mint") != "" {
+			if err := p.linkSigner.Verify(payments.LinkParams{
+				PaymentID:  chi.URLParam(r, "payment_id"),
+				Mint:       chi.URLParam(r, "mint"),
+				ApplyBonus: chi.URLParam(r, "apply_bonus"),
+			}, r.URL.Query()); err != nil {
+				p.encodeError(r.Context(), err, w)
+				return
+			}
+		}
+
+		if p.linkTokens != nil && chi.URLParam(r, "mint") != "" {
+			token := r.URL.Query().Get(payments.LinkTokenParam)
+			if p.byToken != nil && token != "" {
+				if ok, retryAfter := p.byToken.Allow(token); !ok {
+					p.tooManyRequests(w, r, retryAfter)
+					return
+				}
+			}
+			paymentID, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+			if err != nil {
+				p.encodeError(r.Context(), fmt.Errorf("%w: invalid payment ID: %v", ErrInvalidParameter, err), w)
+				return
+			}
+			if err := p.linkTokens.VerifyLinkToken(r.Context(), paymentID, token); err != nil {
+				p.encodeError(r.Context(), err, w)
+				return
+			}
+		}
+
+		if challenge && p.challenge != nil {
+			if err := p.challenge(r); err != nil {
+				p.encodeError(r.Context(), fmt.Errorf("%w: %v", ErrChallengeRequired, err), w)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tooManyRequests responds with 429 status code and the Retry-After header.
+func (p *CheckoutProtection) tooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	p.encodeError(r.Context(), ErrTooManyRequests, w)
+}
+
+// ProofOfWorkChallenge returns a hashcash-like challenge: the request must have the ProofOfWorkHeader
+// with a recent unix timestamp and a nonce, such that sha256("<payment_id>:<timestamp>:<nonce>")
+// has at least the given number of leading zero bits.
+// Every extra bit doubles the average work of the client.
+// Every solution is accepted once: the spent ones are kept for the whole period their timestamp is valid,
+// so a solved header can't be replayed. The spent solutions are kept in memory, per API instance.
+func ProofOfWorkChallenge(difficulty int) Challenge {
+	spent := ratelimit.New(1, 2*proofOfWorkMaxAge)
+
+	return func(r *http.Request) error {
+		parts := strings.SplitN(r.Header.Get(ProofOfWorkHeader), ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return fmt.Errorf("missing proof of work")
+		}
+
+		ts, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid proof of work timestamp")
+		}
+		if age := time.Since(time.Unix(ts, 0)); age > proofOfWorkMaxAge || age < -proofOfWorkMaxAge {
+			return fmt.Errorf("proof of work is expired")
+		}
+
+		resource := chi.URLParam(r, "payment_id")
+		if resource == "" {
+			resource = chi.URLParam(r, "address")
+		}
+
+		solution := resource + ":" + parts[0] + ":" + parts[1]
+		if leadingZeroBits(sha256.Sum256([]byte(solution))) < difficulty {
+			return fmt.Errorf("insufficient proof of work")
+		}
+		if ok, _ := spent.Allow(solution); !ok {
+			return fmt.Errorf("proof of work is already used")
+		}
+
+		return nil
+	}
+}
+
+// leadingZeroBits returns the number of leading zero bits of the hash.
+func leadingZeroBits(hash [sha256.Size]byte) int {
+	n := 0
+	for _, b := range hash {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
+
+// clientIP returns the client IP address.
+// The real client IP is set to RemoteAddr by the realip middleware for the requests from the trusted proxies only,
+// so the clients can't evade the limit by the forwarding headers.
+func clientIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
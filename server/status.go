@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/google/uuid"
+)
+
+// DefaultPaymentStatusCacheTTL is the default time the payment status is cached for.
+// Checkout pages poll the status every second or two, so a short TTL
+// cuts the database load without delaying the status updates noticeably.
+const DefaultPaymentStatusCacheTTL = 2 * time.Second
+
+type (
+	// paymentStatusCache is an in-memory cache of the payment statuses,
+	// so many checkout pages polling the same payment hit the database once per ttl.
+	paymentStatusCache struct {
+		ps  paymentStatusGetter
+		ttl time.Duration
+
+		mu        sync.RWMutex
+		items     map[uuid.UUID]cachedPaymentStatus
+		lastSweep time.Time
+	}
+
+	paymentStatusGetter interface {
+		GetPaymentStatus(ctx context.Context, id uuid.UUID) (*payments.PaymentStatusInfo, error)
+	}
+
+	// cacheable is implemented by the responses which can be cached by the clients.
+	cacheable interface {
+		maxAge() time.Duration
+	}
+
+	cachedPaymentStatus struct {
+		status    payments.PaymentStatusInfo
+		expiresAt time.Time
+	}
+)
+
+// newPaymentStatusCache creates a new payment status cache.
+// DefaultPaymentStatusCacheTTL is used if ttl is zero.
+func newPaymentStatusCache(ps paymentStatusGetter, ttl time.Duration) *paymentStatusCache {
+	if ttl <= 0 {
+		ttl = DefaultPaymentStatusCacheTTL
+	}
+
+	return &paymentStatusCache{
+		ps:    ps,
+		ttl:   ttl,
+		items: make(map[uuid.UUID]cachedPaymentStatus),
+	}
+}
+
+// GetPaymentStatus returns the payment status from the cache
+// or fetches it with the underlying service and caches the result.
+func (c *paymentStatusCache) GetPaymentStatus(ctx context.Context, id uuid.UUID) (*payments.PaymentStatusInfo, error) {
+	c.mu.RLock()
+	item, ok := c.items[id]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(item.expiresAt) {
+		status := item.status
+		return &status, nil
+	}
+
+	status, err := c.ps.GetPaymentStatus(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	c.sweep(now)
+	c.items[id] = cachedPaymentStatus{
+		status:    *status,
+		expiresAt: now.Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	result := *status
+	return &result, nil
+}
+
+// sweep removes the expired items once per ttl to keep the memory usage bounded.
+// It must be called with the lock held.
+func (c *paymentStatusCache) sweep(now time.Time) {
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+
+	for id, item := range c.items {
+		if now.After(item.expiresAt) {
+			delete(c.items, id)
+		}
+	}
+}
+
+// encodeCacheableResponse is a response encoder which allows the clients and proxies
+// to cache the cacheable responses, overriding the global no-cache headers.
+func encodeCacheableResponse(ctx context.Context, w http.ResponseWriter, response interface{}) error {
+	if c, ok := response.(cacheable); ok {
+		for _, h := range []string{"Expires", "Pragma", "X-Accel-Expires"} {
+			w.Header().Del(h)
+		}
+		w.Header().Set("Cache-Control", "public, max-age="+strconv.Itoa(int(c.maxAge().Seconds())))
+	}
+
+	return httpencoder.EncodeResponseAsIs(ctx, w, response)
+}
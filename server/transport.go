@@ -7,27 +7,46 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
+	"github.com/easypmnt/checkout-api/internal/deadline"
 	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/utils"
 	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/reports"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-kit/kit/transport"
 	httptransport "github.com/go-kit/kit/transport/http"
 	"github.com/google/uuid"
 )
 
+// dateFormat is the format of the export period days.
+const dateFormat = "2006-01-02"
+
 type (
 	logger interface {
 		Log(keyvals ...interface{}) error
 	}
 
 	middlewareFunc func(http.Handler) http.Handler
+
+	// Timeouts are the deadlines of the chain-heavy routes, tighter than the request timeout of the API.
+	// The deadlines are propagated to the Solana and Jupiter calls. The zero values disable them.
+	Timeouts struct {
+		Transaction time.Duration // transaction generation
+		Estimate    time.Duration // quotes, exchange rates and wallet tokens
+	}
 )
 
 // MakeHTTPHandler returns an http.Handler that can be used to serve the API.
-func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+// The checkout middlewares are applied to the public checkout routes only, e.g. CheckoutProtection.Middleware.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc, timeouts Timeouts, checkoutMdw ...middlewareFunc) http.Handler {
 	r := chi.NewRouter()
 
+	txDeadline := deadline.Middleware(timeouts.Transaction)
+	estimateDeadline := deadline.Middleware(timeouts.Estimate)
+
 	options := []httptransport.ServerOption{
 		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
 		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
@@ -35,6 +54,10 @@ func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handl
 
 	// Without auth
 	r.Group(func(r chi.Router) {
+		for _, mdw := range checkoutMdw {
+			r.Use(mdw)
+		}
+
 		r.Get("/checkout/{payment_id}/{mint}/{apply_bonus}", httptransport.NewServer(
 			e.GetAppInfo,
 			decodeGetAppInfoRequest,
@@ -42,14 +65,30 @@ func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handl
 			options...,
 		).ServeHTTP)
 
-		r.Post("/checkout/{payment_id}/{mint}/{apply_bonus}", httptransport.NewServer(
+		r.With(txDeadline).Post("/checkout/{payment_id}/{mint}/{apply_bonus}", httptransport.NewServer(
 			e.GeneratePaymentTransaction,
 			decodeGeneratePaymentTransactionRequest,
 			httpencoder.EncodeResponseAsIs,
 			options...,
 		).ServeHTTP)
+
+		r.With(estimateDeadline).Get("/wallet/{address}/tokens", httptransport.NewServer(
+			e.GetWalletTokens,
+			decodeGetWalletTokensRequest,
+			httpencoder.EncodeResponse,
+			options...,
+		).ServeHTTP)
 	})
 
+	// Without auth and checkout protection: the status is cheap and cached,
+	// so the checkout pages can poll it without hitting the checkout rate limits.
+	r.Get("/pid/{payment_id}/status", httptransport.NewServer(
+		e.GetPaymentStatus,
+		decodeGetPaymentStatusRequest,
+		encodeCacheableResponse,
+		options...,
+	).ServeHTTP)
+
 	// With auth
 	r.Group(func(r chi.Router) {
 		r.Use(authMdw)
@@ -68,6 +107,20 @@ func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handl
 			options...,
 		).ServeHTTP)
 
+		r.Get("/pid/{payment_id}/timeline", httptransport.NewServer(
+			e.GetPaymentTimeline,
+			decodeGetPaymentTimelineRequest,
+			httpencoder.EncodeResponse,
+			options...,
+		).ServeHTTP)
+
+		r.Get("/export", httptransport.NewServer(
+			e.ExportPayments,
+			decodeExportPaymentsRequest,
+			encodeExportPaymentsResponse,
+			options...,
+		).ServeHTTP)
+
 		r.Get("/ext/{external_id}", httptransport.NewServer(
 			e.GetPaymentByExternalID,
 			decodeGetPaymentByExternalIDRequest,
@@ -75,6 +128,13 @@ func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handl
 			options...,
 		).ServeHTTP)
 
+		r.Post("/pid/{payment_id}/checkout-token", httptransport.NewServer(
+			e.IssueCheckoutToken,
+			decodeGetPaymentRequest,
+			httpencoder.EncodeResponse,
+			options...,
+		).ServeHTTP)
+
 		r.Post("/pid/{payment_id}/cancel", httptransport.NewServer(
 			e.CancelPayment,
 			decodeCancelPaymentRequest,
@@ -82,6 +142,20 @@ func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handl
 			options...,
 		).ServeHTTP)
 
+		r.Post("/pid/{payment_id}/release", httptransport.NewServer(
+			e.ReleasePayment,
+			decodeReleasePaymentRequest,
+			httpencoder.EncodeResponse,
+			options...,
+		).ServeHTTP)
+
+		r.Post("/pid/{payment_id}/deposit-address", httptransport.NewServer(
+			e.CreateDepositAddress,
+			decodeCreateDepositAddressRequest,
+			httpencoder.EncodeResponse,
+			options...,
+		).ServeHTTP)
+
 		r.Post("/pid/{payment_id}/link", httptransport.NewServer(
 			e.GeneratePaymentLink,
 			decodeGeneratePaymentLinkRequest,
@@ -89,14 +163,21 @@ func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handl
 			options...,
 		).ServeHTTP)
 
-		r.Post("/pid/{payment_id}/transaction", httptransport.NewServer(
+		r.With(txDeadline).Post("/pid/{payment_id}/transaction", httptransport.NewServer(
 			e.GeneratePaymentTransaction,
 			decodeGeneratePaymentTransactionRequest,
 			httpencoder.EncodeResponse,
 			options...,
 		).ServeHTTP)
 
-		r.Post("/exchange", httptransport.NewServer(
+		r.With(estimateDeadline).Post("/pid/{payment_id}/quote", httptransport.NewServer(
+			e.QuotePaymentTransaction,
+			decodeQuotePaymentTransactionRequest,
+			httpencoder.EncodeResponse,
+			options...,
+		).ServeHTTP)
+
+		r.With(estimateDeadline).Post("/exchange", httptransport.NewServer(
 			e.GetExchangeRate,
 			decodeGetExchangeRateRequest,
 			httpencoder.EncodeResponse,
@@ -107,8 +188,31 @@ func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handl
 	return r
 }
 
+// MakeAdminHTTPHandler returns an http.Handler that serves the operator API.
+// All the endpoints require authorization.
+func MakeAdminHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Post("/transactions/{reference}/recheck", httptransport.NewServer(
+		e.RecheckTransaction,
+		decodeRecheckTransactionRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
 // returns http error code by error type
 func codeAndMessageFrom(err error) (int, interface{}) {
+	err = deadline.Error(err)
 	if errors.Is(err, validator.ErrValidation) {
 		return http.StatusPreconditionFailed, err
 	}
@@ -124,8 +228,11 @@ func codeAndMessageFrom(err error) (int, interface{}) {
 
 // DecodeGetAppInfoRequest is a transport/http.DecodeRequestFunc that decodes a
 // JSON-encoded request from the HTTP request body.
-func decodeGetAppInfoRequest(_ context.Context, _ *http.Request) (interface{}, error) {
-	return nil, nil
+func decodeGetAppInfoRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return GetAppInfoRequest{
+		PaymentID: chi.URLParam(r, "payment_id"),
+		Locale:    localeFromRequest(r),
+	}, nil
 }
 
 // decodeGeneratePaymentTransactionRequest is a transport/http.DecodeRequestFunc that decodes a
@@ -139,6 +246,13 @@ func decodeGeneratePaymentTransactionRequest(ctx context.Context, r *http.Reques
 	req.PaymentID = chi.URLParam(r, "payment_id")
 	req.Mint = chi.URLParam(r, "mint")
 	req.ApplyBonus = chi.URLParam(r, "apply_bonus")
+	req.ApplyVoucher = r.URL.Query().Get("apply_voucher")
+	req.AcceptPriceImpact = r.URL.Query().Get("accept_price_impact")
+	req.Locale = localeFromRequest(r)
+	req.TxVersion = txVersionFromRequest(r)
+	req.LinkToken = r.URL.Query().Get(payments.LinkTokenParam)
+	req.MultiTx = r.URL.Query().Get("multi_tx")
+	req.Amount = r.URL.Query().Get(payments.LinkAmountParam)
 
 	return req, nil
 }
@@ -165,6 +279,28 @@ func decodeGetPaymentRequest(ctx context.Context, r *http.Request) (interface{},
 	return pid, nil
 }
 
+// decodeGetPaymentStatusRequest is a transport/http.DecodeRequestFunc that decodes the
+// payment ID from the URL path.
+func decodeGetPaymentStatusRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	pid, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+	if err != nil {
+		return nil, ErrInvalidRequest
+	}
+
+	return pid, nil
+}
+
+// decodeGetPaymentTimelineRequest is a transport/http.DecodeRequestFunc that decodes the
+// payment ID from the URL path.
+func decodeGetPaymentTimelineRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	pid, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+	if err != nil {
+		return nil, ErrInvalidRequest
+	}
+
+	return pid, nil
+}
+
 // decodeGetPaymentByExternalIDRequest is a transport/http.DecodeRequestFunc that decodes a
 // JSON-encoded request from the HTTP request body.
 func decodeGetPaymentByExternalIDRequest(ctx context.Context, r *http.Request) (interface{}, error) {
@@ -182,6 +318,28 @@ func decodeCancelPaymentRequest(ctx context.Context, r *http.Request) (interface
 	return pid, nil
 }
 
+// decodeReleasePaymentRequest is a transport/http.DecodeRequestFunc that decodes the
+// payment ID from the URL path.
+func decodeReleasePaymentRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	pid, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+	if err != nil {
+		return nil, ErrInvalidRequest
+	}
+
+	return pid, nil
+}
+
+// decodeCreateDepositAddressRequest is a transport/http.DecodeRequestFunc that decodes the
+// payment ID from the URL path.
+func decodeCreateDepositAddressRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	pid, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+	if err != nil {
+		return nil, ErrInvalidRequest
+	}
+
+	return pid, nil
+}
+
 // decodeGeneratePaymentLinkRequest is a transport/http.DecodeRequestFunc that decodes a
 // JSON-encoded request from the HTTP request body.
 func decodeGeneratePaymentLinkRequest(ctx context.Context, r *http.Request) (interface{}, error) {
@@ -199,6 +357,32 @@ func decodeGeneratePaymentLinkRequest(ctx context.Context, r *http.Request) (int
 	return req, nil
 }
 
+// decodeQuotePaymentTransactionRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeQuotePaymentTransactionRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req QuotePaymentTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("invalid request body: %w", err)
+	}
+
+	pid, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+	if err != nil {
+		return nil, ErrInvalidRequest
+	}
+	req.PaymentID = pid
+
+	return req, nil
+}
+
+// decodeGetWalletTokensRequest is a transport/http.DecodeRequestFunc that decodes
+// the wallet address from the URL path and the payment ID from the query string.
+func decodeGetWalletTokensRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	return GetWalletTokensRequest{
+		PaymentID: r.URL.Query().Get("payment_id"),
+		Wallet:    chi.URLParam(r, "address"),
+	}, nil
+}
+
 // decodeGetExchangeRateRequest is a transport/http.DecodeRequestFunc that decodes a
 // JSON-encoded request from the HTTP request body.
 func decodeGetExchangeRateRequest(ctx context.Context, r *http.Request) (interface{}, error) {
@@ -209,3 +393,57 @@ func decodeGetExchangeRateRequest(ctx context.Context, r *http.Request) (interfa
 
 	return req, nil
 }
+
+// decodeRecheckTransactionRequest is a transport/http.DecodeRequestFunc that decodes
+// the transaction reference from the URL path.
+func decodeRecheckTransactionRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return chi.URLParam(r, "reference"), nil
+}
+
+// decodeExportPaymentsRequest is a transport/http.DecodeRequestFunc that decodes
+// the export format and period from the query string: ?from=2023-03-01&to=2023-04-01&format=csv.
+// The format is csv or json, csv by default. Both dates are optional and accept either YYYY-MM-DD or RFC3339 format.
+func decodeExportPaymentsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	q := r.URL.Query()
+
+	req := ExportPaymentsRequest{Format: q.Get("format")}
+	switch req.Format {
+	case "":
+		req.Format = reports.ExportFormatCSV
+	case reports.ExportFormatCSV, reports.ExportFormatJSON:
+	default:
+		return nil, fmt.Errorf("%w: format must be csv or json", ErrInvalidParameter)
+	}
+
+	var err error
+	if req.From, err = utils.ParseDate(q.Get("from")); err != nil {
+		return nil, fmt.Errorf("%w: from: %s", ErrInvalidParameter, err.Error())
+	}
+	if req.To, err = utils.ParseDate(q.Get("to")); err != nil {
+		return nil, fmt.Errorf("%w: to: %s", ErrInvalidParameter, err.Error())
+	}
+
+	return req, nil
+}
+
+// encodeExportPaymentsResponse streams the accounting export to the client as the attachment.
+// The status is sent before the export is written, so the errors in the middle of the export
+// are only logged and the client gets the truncated file.
+func encodeExportPaymentsResponse(_ context.Context, w http.ResponseWriter, response interface{}) error {
+	resp, ok := response.(ExportPaymentsResponse)
+	if !ok {
+		return ErrInvalidRequest
+	}
+
+	contentType := "text/csv"
+	if resp.Format == reports.ExportFormatJSON {
+		contentType = "application/json"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(
+		`attachment; filename="payments-%s-%s.%s"`,
+		resp.From.Format(dateFormat), resp.To.Format(dateFormat), resp.Format,
+	))
+
+	return resp.Export(w)
+}
@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+// SupportedTransactionVersionsHeader is the request header with the comma-separated transaction versions
+// the wallet can deserialize, e.g. "legacy,0", as reported by the wallet adapter.
+const SupportedTransactionVersionsHeader = "X-Supported-Transaction-Versions"
+
+// txVersionFromRequest returns the requested version of the payment transaction:
+// the tx_version query parameter or the preferred version of the wallet capability header.
+// It's empty if neither is set, so the transaction is legacy.
+func txVersionFromRequest(r *http.Request) string {
+	if v := r.URL.Query().Get("tx_version"); v != "" {
+		return v
+	}
+	if h := r.Header.Get(SupportedTransactionVersionsHeader); h != "" {
+		return string(solana.PreferredTransactionVersion(strings.Split(h, ",")...))
+	}
+	return ""
+}
@@ -0,0 +1,274 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/go-chi/chi/v5"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// Checkout WebSocket connection settings.
+const (
+	checkoutWSWriteTimeout = 10 * time.Second
+	checkoutWSPingPeriod   = 30 * time.Second
+	checkoutWSPongTimeout  = checkoutWSPingPeriod + 10*time.Second
+	checkoutWSSendBuffer   = 16
+)
+
+// CheckoutEvents are the payment and transaction events pushed to the checkout WebSocket clients.
+var CheckoutEvents = []events.EventName{
+	events.PaymentProcessing,
+	events.PaymentPartiallyPaid,
+	events.PaymentSucceeded,
+	events.PaymentFailed,
+	events.PaymentExpired,
+	events.PaymentCancelled,
+	events.PaymentHeld,
+	events.PaymentReleased,
+	events.PaymentDisputed,
+	events.PaymentDisputeResolved,
+	events.TransactionUpdated,
+}
+
+// CheckoutStatusEvent is the event of the current payment status sent on connect.
+const CheckoutStatusEvent = "payment.status"
+
+type (
+	// CheckoutWebSocket pushes the status transitions of the payment and its transactions
+	// to the checkout pages over WebSocket, e.g. /ws/checkout/{payment_id}?token=<checkout token>.
+	// The messages carry the statuses only, not the event payloads, since the endpoint is public.
+	CheckoutWebSocket struct {
+		ps          paymentStatusGetter
+		tokens      *CheckoutTokens
+		upgrader    websocket.Upgrader
+		encodeError httptransport.ErrorEncoder
+		log         logger
+
+		mu      sync.RWMutex
+		clients map[string]map[*checkoutClient]struct{}
+	}
+
+	// CheckoutMessage is the message pushed to the checkout WebSocket clients.
+	CheckoutMessage struct {
+		Event     string `json:"event"`
+		PaymentID string `json:"payment_id"`
+		Status    string `json:"status,omitempty"`
+		Reference string `json:"reference,omitempty"` // transaction events only
+		Signature string `json:"signature,omitempty"`
+	}
+
+	// checkoutClient is the connection of the checkout page.
+	// The messages are written by its own goroutine, the connection doesn't support concurrent writers.
+	checkoutClient struct {
+		conn *websocket.Conn
+		send chan CheckoutMessage
+		done chan struct{} // closed by the read pump once the connection is closed
+	}
+)
+
+// NewCheckoutWebSocket creates a new checkout WebSocket and subscribes it to the CheckoutEvents of the emitter.
+// With the events fanout, the emitter must be the fanout, so the clients get the events of all the API instances.
+// The connections are authenticated by the checkout tokens of the payments.
+func NewCheckoutWebSocket(emitter events.Emitter, ps paymentStatusGetter, tokens *CheckoutTokens, log logger) *CheckoutWebSocket {
+	ws := &CheckoutWebSocket{
+		ps:     ps,
+		tokens: tokens,
+		upgrader: websocket.Upgrader{
+			// the token authenticates the page, so it may be served from any origin
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		encodeError: httpencoder.EncodeError(log, codeAndMessageFrom),
+		log:         log,
+		clients:     make(map[string]map[*checkoutClient]struct{}),
+	}
+
+	emitter.ListenEvents(ws.Push, CheckoutEvents...)
+
+	return ws
+}
+
+// Push is the events.Listener sending the event to the clients of its payment.
+// A client which doesn't keep up with the events is disconnected, so it reconnects and gets the current status.
+func (ws *CheckoutWebSocket) Push(event events.EventName, payload interface{}) error {
+	msg, ok := checkoutMessage(event, payload)
+	if !ok {
+		return nil
+	}
+
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	for c := range ws.clients[msg.PaymentID] {
+		select {
+		case c.send <- msg:
+		default:
+			c.conn.Close()
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP upgrades the request to the WebSocket connection with the payment events.
+// The payment ID is the {payment_id} URL parameter, the checkout token is the token query parameter.
+func (ws *CheckoutWebSocket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	paymentID, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+	if err != nil {
+		ws.encodeError(ctx, fmt.Errorf("%w: invalid payment ID: %v", ErrInvalidParameter, err), w)
+		return
+	}
+	if err := ws.tokens.Verify(paymentID, r.URL.Query().Get(CheckoutTokenParam)); err != nil {
+		ws.encodeError(ctx, err, w)
+		return
+	}
+
+	conn, err := ws.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already responded with the error
+		ws.log.Log("msg", "checkout websocket: failed to upgrade connection", "err", err)
+		return
+	}
+
+	// subscribe before getting the status, so the events in between are not lost
+	c := &checkoutClient{
+		conn: conn,
+		send: make(chan CheckoutMessage, checkoutWSSendBuffer),
+		done: make(chan struct{}),
+	}
+	ws.add(paymentID.String(), c)
+	defer ws.remove(paymentID.String(), c)
+
+	status, err := ws.ps.GetPaymentStatus(ctx, paymentID)
+	if err != nil {
+		reason := "failed to get payment status"
+		if errors.Is(err, sql.ErrNoRows) {
+			reason = "payment not found"
+		}
+		c.close(websocket.ClosePolicyViolation, reason)
+		return
+	}
+	// the write pump isn't started yet, so the status is written directly
+	c.conn.SetWriteDeadline(time.Now().Add(checkoutWSWriteTimeout))
+	if err := c.conn.WriteJSON(CheckoutMessage{
+		Event:     CheckoutStatusEvent,
+		PaymentID: paymentID.String(),
+		Status:    string(status.Status),
+		Signature: status.Signature,
+	}); err != nil {
+		return
+	}
+
+	go c.readPump()
+	c.writePump(ctx)
+}
+
+// add registers the client of the payment.
+func (ws *CheckoutWebSocket) add(paymentID string, c *checkoutClient) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	if ws.clients[paymentID] == nil {
+		ws.clients[paymentID] = make(map[*checkoutClient]struct{})
+	}
+	ws.clients[paymentID][c] = struct{}{}
+}
+
+// remove unregisters the client of the payment and closes its connection.
+func (ws *CheckoutWebSocket) remove(paymentID string, c *checkoutClient) {
+	ws.mu.Lock()
+	delete(ws.clients[paymentID], c)
+	if len(ws.clients[paymentID]) == 0 {
+		delete(ws.clients, paymentID)
+	}
+	ws.mu.Unlock()
+
+	c.conn.Close()
+}
+
+// readPump reads the connection until it's closed, so the pongs and the close frames are handled.
+// The client doesn't send any messages, they are discarded.
+func (c *checkoutClient) readPump() {
+	defer close(c.done)
+
+	c.conn.SetReadLimit(512)
+	c.conn.SetReadDeadline(time.Now().Add(checkoutWSPongTimeout))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(checkoutWSPongTimeout))
+	})
+
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// writePump writes the messages and the pings to the connection until it's closed or the context is done.
+// The hijacked connection doesn't cancel the request context, so the closing is signaled by the read pump.
+func (c *checkoutClient) writePump(ctx context.Context) {
+	ticker := time.NewTicker(checkoutWSPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.close(websocket.CloseGoingAway, "")
+			return
+		case <-c.done:
+			return
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(checkoutWSWriteTimeout))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(checkoutWSWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// close sends the close frame with the given code and reason.
+func (c *checkoutClient) close(code int, reason string) {
+	c.conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(checkoutWSWriteTimeout),
+	)
+}
+
+// checkoutMessage returns the message of the payment or transaction event.
+func checkoutMessage(event events.EventName, payload interface{}) (CheckoutMessage, bool) {
+	msg := CheckoutMessage{Event: string(event)}
+
+	switch p := payload.(type) {
+	case events.PaymentStatusUpdatedPayload:
+		msg.PaymentID, msg.Status = p.PaymentID.PaymentID, p.Status
+	case events.PaymentReleasedPayload:
+		msg.PaymentID, msg.Status, msg.Signature = p.PaymentID.PaymentID, string(payments.PaymentStatusReleased), p.Signature
+	case events.PaymentDisputedPayload:
+		msg.PaymentID, msg.Status = p.PaymentID.PaymentID, string(payments.PaymentStatusDisputed)
+	case events.PaymentDisputeResolvedPayload:
+		msg.PaymentID, msg.Status = p.PaymentID.PaymentID, p.Status
+	case events.TransactionUpdatedPayload:
+		msg.PaymentID, msg.Status, msg.Reference, msg.Signature = p.PaymentID.PaymentID, p.Status, p.Reference, p.Signature
+	default:
+		return msg, false
+	}
+
+	return msg, msg.PaymentID != ""
+}
@@ -0,0 +1,65 @@
+package settings
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		GetSettings    endpoint.Endpoint
+		UpdateSettings endpoint.Endpoint
+	}
+
+	// UpdateSettingsRequest is the request type for the UpdateSettings method.
+	// All the settings are replaced, so the request must contain the full settings object.
+	UpdateSettingsRequest struct {
+		payments.Settings
+	}
+
+	// SettingsResponse is the response type for the settings methods.
+	SettingsResponse struct {
+		Settings payments.Settings `json:"settings"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		GetSettings:    makeGetSettingsEndpoint(s),
+		UpdateSettings: makeUpdateSettingsEndpoint(s),
+	}
+}
+
+// makeGetSettingsEndpoint returns an endpoint function for the GetSettings method.
+func makeGetSettingsEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		settings, err := s.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return SettingsResponse{Settings: settings}, nil
+	}
+}
+
+// makeUpdateSettingsEndpoint returns an endpoint function for the UpdateSettings method.
+func makeUpdateSettingsEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(UpdateSettingsRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		settings, err := s.Update(ctx, req.Settings)
+		if err != nil {
+			return nil, err
+		}
+
+		return SettingsResponse{Settings: settings}, nil
+	}
+}
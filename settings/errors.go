@@ -0,0 +1,8 @@
+package settings
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest = errors.New("invalid request")
+)
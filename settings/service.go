@@ -0,0 +1,182 @@
+package settings
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+// DefaultReloadInterval is the default interval of the settings reload from the database,
+// so the settings updated on another API instance are applied on this one.
+const DefaultReloadInterval = 30 * time.Second
+
+// Service stores the merchant settings in the database and applies them to the payment service
+// without restarting the API. Until the settings are stored, the defaults from the env config are used.
+type Service struct {
+	repo     settingsRepository
+	app      settingsApplier
+	defaults payments.Settings
+	interval time.Duration
+	log      logger
+
+	mu        sync.Mutex // guards defaults and updatedAt
+	updatedAt time.Time  // of the applied settings; zero if the defaults are applied
+}
+
+// NewService creates a new settings service.
+// DefaultReloadInterval is used if interval is zero.
+func NewService(repo settingsRepository, app settingsApplier, defaults payments.Settings, interval time.Duration, log logger) *Service {
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+
+	return &Service{
+		repo:     repo,
+		app:      app,
+		defaults: defaults,
+		interval: interval,
+		log:      log,
+	}
+}
+
+// Get returns the current merchant settings.
+func (s *Service) Get(ctx context.Context) (payments.Settings, error) {
+	row, err := s.repo.GetMerchantSettings(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.Defaults(), nil
+		}
+		return payments.Settings{}, fmt.Errorf("failed to get merchant settings: %w", err)
+	}
+
+	return castFromRepositorySettings(row), nil
+}
+
+// Defaults returns the settings from the env config, used until the settings are stored.
+func (s *Service) Defaults() payments.Settings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.defaults
+}
+
+// SetDefaults replaces the settings from the env config, e.g. on the config reload.
+// They're applied to the payment service at once, unless the stored settings are applied.
+func (s *Service) SetDefaults(defaults payments.Settings) error {
+	if err := s.app.CheckSettings(defaults); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.updatedAt.IsZero() {
+		if err := s.app.ApplySettings(defaults); err != nil {
+			return fmt.Errorf("failed to apply merchant settings: %w", err)
+		}
+	}
+	s.defaults = defaults
+
+	return nil
+}
+
+// Update validates and stores the merchant settings and applies them to the payment service.
+func (s *Service) Update(ctx context.Context, settings payments.Settings) (payments.Settings, error) {
+	if err := s.app.CheckSettings(settings); err != nil {
+		return payments.Settings{}, err
+	}
+
+	row, err := s.repo.StoreMerchantSettings(ctx, repository.StoreMerchantSettingsParams{
+		ApplyBonus:           settings.ApplyBonus,
+		MaxApplyBonusAmount:  int64(settings.MaxApplyBonusAmount),
+		MaxApplyBonusPercent: int32(settings.MaxApplyBonusPercent),
+		AccrueBonusRate:      int64(settings.AccrueBonusRate),
+		DestinationMint:      settings.DestinationMint,
+		DestinationWallet:    settings.DestinationWallet,
+		EscrowReleaseAfter:   settings.EscrowReleaseAfter,
+	})
+	if err != nil {
+		return payments.Settings{}, fmt.Errorf("failed to store merchant settings: %w", err)
+	}
+
+	result := castFromRepositorySettings(row)
+	if err := s.apply(result, row.UpdatedAt); err != nil {
+		return payments.Settings{}, err
+	}
+
+	return result, nil
+}
+
+// Reload applies the stored settings, if they were changed since the last reload.
+func (s *Service) Reload(ctx context.Context) error {
+	row, err := s.repo.GetMerchantSettings(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get merchant settings: %w", err)
+	}
+
+	s.mu.Lock()
+	changed := !row.UpdatedAt.Equal(s.updatedAt)
+	s.mu.Unlock()
+	if !changed {
+		return nil
+	}
+
+	if err := s.apply(castFromRepositorySettings(row), row.UpdatedAt); err != nil {
+		return err
+	}
+	s.log.Infof("settings: merchant settings updated at %s are applied", row.UpdatedAt.Format(time.RFC3339))
+
+	return nil
+}
+
+// Run reloads the settings periodically until the context is canceled.
+func (s *Service) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := s.Reload(ctx); err != nil {
+			s.log.Errorf("settings: %s", err.Error())
+		}
+	}
+}
+
+// apply applies the settings to the payment service and records their version.
+func (s *Service) apply(settings payments.Settings, updatedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.app.ApplySettings(settings); err != nil {
+		return fmt.Errorf("failed to apply merchant settings: %w", err)
+	}
+	s.updatedAt = updatedAt
+
+	return nil
+}
+
+// castFromRepositorySettings converts the stored settings to the payment service settings.
+func castFromRepositorySettings(row repository.MerchantSetting) payments.Settings {
+	return payments.Settings{
+		ApplyBonus:           row.ApplyBonus,
+		MaxApplyBonusAmount:  uint64(row.MaxApplyBonusAmount),
+		MaxApplyBonusPercent: uint16(row.MaxApplyBonusPercent),
+		AccrueBonusRate:      uint64(row.AccrueBonusRate),
+		DestinationMint:      row.DestinationMint,
+		DestinationWallet:    row.DestinationWallet,
+		EscrowReleaseAfter:   row.EscrowReleaseAfter,
+	}
+}
@@ -0,0 +1,122 @@
+package settings_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/settings"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	row *repository.MerchantSetting
+}
+
+func (r *repoMock) GetMerchantSettings(ctx context.Context) (repository.MerchantSetting, error) {
+	if r.row == nil {
+		return repository.MerchantSetting{}, sql.ErrNoRows
+	}
+	return *r.row, nil
+}
+
+func (r *repoMock) StoreMerchantSettings(ctx context.Context, arg repository.StoreMerchantSettingsParams) (repository.MerchantSetting, error) {
+	r.row = &repository.MerchantSetting{
+		ID:                   1,
+		ApplyBonus:           arg.ApplyBonus,
+		MaxApplyBonusAmount:  arg.MaxApplyBonusAmount,
+		MaxApplyBonusPercent: arg.MaxApplyBonusPercent,
+		AccrueBonusRate:      arg.AccrueBonusRate,
+		DestinationMint:      arg.DestinationMint,
+		DestinationWallet:    arg.DestinationWallet,
+		EscrowReleaseAfter:   arg.EscrowReleaseAfter,
+		UpdatedAt:            time.Now(),
+	}
+	return *r.row, nil
+}
+
+type applierMock struct {
+	applied []payments.Settings
+}
+
+func (a *applierMock) CheckSettings(s payments.Settings) error {
+	if s.DestinationWallet == "" {
+		return payments.ErrInvalidSettings
+	}
+	return nil
+}
+
+func (a *applierMock) ApplySettings(s payments.Settings) error {
+	a.applied = append(a.applied, s)
+	return nil
+}
+
+type logMock struct{}
+
+func (logMock) Infof(format string, args ...interface{})  {}
+func (logMock) Errorf(format string, args ...interface{}) {}
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoMock{}
+	app := &applierMock{}
+	defaults := payments.Settings{DestinationMint: "SOL", DestinationWallet: "wallet", AccrueBonusRate: 100}
+	svc := settings.NewService(repo, app, defaults, 0, logMock{})
+
+	// nothing is stored yet, the env config is used
+	require.NoError(t, svc.Reload(ctx))
+	require.Empty(t, app.applied)
+	got, err := svc.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, defaults, got)
+
+	_, err = svc.Update(ctx, payments.Settings{DestinationMint: "SOL"})
+	require.ErrorIs(t, err, payments.ErrInvalidSettings)
+	require.Nil(t, repo.row)
+
+	updated := payments.Settings{DestinationMint: "USDC", DestinationWallet: "wallet2", ApplyBonus: true, MaxApplyBonusPercent: 1000}
+	got, err = svc.Update(ctx, updated)
+	require.NoError(t, err)
+	require.Equal(t, updated, got)
+	require.Equal(t, []payments.Settings{updated}, app.applied)
+
+	// the settings are not changed since the update
+	require.NoError(t, svc.Reload(ctx))
+	require.Len(t, app.applied, 1)
+
+	// updated on another instance
+	repo.row.EscrowReleaseAfter = 3600
+	repo.row.UpdatedAt = repo.row.UpdatedAt.Add(time.Second)
+	require.NoError(t, svc.Reload(ctx))
+	require.Len(t, app.applied, 2)
+	require.EqualValues(t, 3600, app.applied[1].EscrowReleaseAfter)
+}
+
+func TestServiceSetDefaults(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoMock{}
+	app := &applierMock{}
+	svc := settings.NewService(repo, app, payments.Settings{DestinationMint: "SOL", DestinationWallet: "wallet"}, 0, logMock{})
+
+	require.ErrorIs(t, svc.SetDefaults(payments.Settings{DestinationMint: "SOL"}), payments.ErrInvalidSettings)
+	require.Empty(t, app.applied)
+
+	// nothing is stored yet, the reloaded env config is applied at once
+	defaults := payments.Settings{DestinationMint: "SOL", DestinationWallet: "wallet", ApplyBonus: true, AccrueBonusRate: 200}
+	require.NoError(t, svc.SetDefaults(defaults))
+	require.Equal(t, []payments.Settings{defaults}, app.applied)
+	got, err := svc.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, defaults, got)
+
+	// the stored settings override the env config
+	stored := payments.Settings{DestinationMint: "USDC", DestinationWallet: "wallet2"}
+	_, err = svc.Update(ctx, stored)
+	require.NoError(t, err)
+	require.NoError(t, svc.SetDefaults(payments.Settings{DestinationMint: "SOL", DestinationWallet: "wallet"}))
+	require.Equal(t, []payments.Settings{defaults, stored}, app.applied)
+	require.Equal(t, "wallet", svc.Defaults().DestinationWallet)
+}
@@ -0,0 +1,72 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	middlewareFunc func(http.Handler) http.Handler
+
+	kitLogger interface {
+		Log(keyvals ...interface{}) error
+	}
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the merchant settings API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log kitLogger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/", httptransport.NewServer(
+		e.GetSettings,
+		httptransport.NopRequestDecoder,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/", httptransport.NewServer(
+		e.UpdateSettings,
+		decodeUpdateSettingsRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	if errors.Is(err, ErrInvalidRequest) || errors.Is(err, payments.ErrInvalidSettings) {
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeUpdateSettingsRequest is a transport/http.DecodeRequestFunc that decodes
+// the settings object from the request body.
+func decodeUpdateSettingsRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req UpdateSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	return req, nil
+}
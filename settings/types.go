@@ -0,0 +1,26 @@
+package settings
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+)
+
+type (
+	settingsRepository interface {
+		GetMerchantSettings(ctx context.Context) (repository.MerchantSetting, error)
+		StoreMerchantSettings(ctx context.Context, arg repository.StoreMerchantSettingsParams) (repository.MerchantSetting, error)
+	}
+
+	// settingsApplier applies the settings to the running service, e.g. *payments.Service.
+	settingsApplier interface {
+		CheckSettings(settings payments.Settings) error
+		ApplySettings(settings payments.Settings) error
+	}
+
+	logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
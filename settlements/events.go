@@ -0,0 +1,28 @@
+package settlements
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/events"
+)
+
+type settlementEnqueuer interface {
+	SettlePayment(ctx context.Context, paymentID string) error
+}
+
+// PaymentSettledListener enqueues the settlement swap of the payment funds
+// for the payment.succeeded and payment.released events, once the funds are in the merchant wallet.
+func PaymentSettledListener(enq settlementEnqueuer) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		if payload == nil || (event != events.PaymentSucceeded && event != events.PaymentReleased) {
+			return nil
+		}
+
+		p, ok := payload.(events.PaymentIDGetter)
+		if !ok {
+			return nil
+		}
+
+		return enq.SettlePayment(context.Background(), p.GetPaymentID())
+	}
+}
@@ -0,0 +1,294 @@
+package settlements
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/utils"
+	"github.com/easypmnt/checkout-api/jupiter"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/google/uuid"
+)
+
+const (
+	// confirmationTimeout is the max time to wait for the swap transaction to be confirmed.
+	confirmationTimeout = time.Minute
+	// swapExpiry is the time after which the sent swap, which is still unknown to the network, is dropped:
+	// its blockhash is expired, so it never lands and the swap can be sent again.
+	swapExpiry = 2 * time.Minute
+)
+
+type (
+	// Service swaps the funds of the completed payments, received in the mints other than
+	// the settlement mint, into the settlement mint via Jupiter, e.g. SOL and BONK into USDC.
+	//
+	// The swap is signed by the settlement key, so only the payments to its wallet are settled.
+	// Every swap is recorded in the settlements table; its signature is recorded before it's sent,
+	// so the retried task checks the sent swap instead of swapping the funds twice.
+	Service struct {
+		repo        settlementRepository
+		sol         solanaClient
+		jup         jupiterClient
+		signer      solana.Signer
+		wallet      string
+		mint        string
+		slippageBps uint64
+		commitment  solana.Commitment
+	}
+
+	// ServiceOption is a function that configures the settlements service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new settlements service.
+// The signer is the settlement key, the owner of the wallet receiving the payments, which pays the swap fees.
+// The mint is the settlement mint address.
+func NewService(repo settlementRepository, sol solanaClient, jup jupiterClient, signer solana.Signer, mint string, opts ...ServiceOption) *Service {
+	if repo == nil {
+		panic("repository is nil")
+	}
+	if sol == nil {
+		panic("solana client is nil")
+	}
+	if jup == nil {
+		panic("jupiter client is nil")
+	}
+	if signer == nil {
+		panic("settlement signer is nil")
+	}
+	if mint == "" {
+		panic("settlement mint is required")
+	}
+
+	s := &Service{
+		repo:        repo,
+		sol:         sol,
+		jup:         jup,
+		signer:      signer,
+		wallet:      signer.PublicKey().ToBase58(),
+		mint:        mint,
+		slippageBps: 50,
+		commitment:  solana.CommitmentConfirmed,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithSlippage sets the max slippage of the swaps in basis points, 100 = 1%. Default is 50.
+func WithSlippage(bps uint64) ServiceOption {
+	return func(s *Service) {
+		s.slippageBps = bps
+	}
+}
+
+// WithCommitment sets the commitment to wait for the swap transaction.
+func WithCommitment(commitment solana.Commitment) ServiceOption {
+	return func(s *Service) {
+		s.commitment = commitment
+	}
+}
+
+// Wallet returns the settlement wallet address, the wallet of the settlement key.
+func (s *Service) Wallet() string {
+	return s.wallet
+}
+
+// Settle swaps the funds received by the completed payment into the settlement mint.
+// The completed settlement is returned as is. Returns nil if there is nothing to settle:
+// the payment isn't completed or released, is a test-mode payment, is paid to another wallet
+// or in the settlement mint. The tips are kept in the mint they were paid in.
+func (s *Service) Settle(ctx context.Context, payment *payments.Payment, attempts []*payments.PaymentAttempt) (*Settlement, error) {
+	if payment.Status != payments.PaymentStatusCompleted && payment.Status != payments.PaymentStatusReleased {
+		return nil, nil
+	}
+	if !payment.Livemode || payment.DestinationWallet != s.wallet || payment.DestinationMint == s.mint {
+		return nil, nil
+	}
+
+	amount := receivedAmount(attempts)
+	if amount == 0 {
+		return nil, nil
+	}
+
+	row, err := s.repo.GetSettlementByPaymentID(ctx, payment.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		row, err = s.repo.CreateSettlement(ctx, repository.CreateSettlementParams{
+			PaymentID:  payment.ID,
+			InputMint:  payment.DestinationMint,
+			OutputMint: s.mint,
+			InAmount:   int64(amount),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create settlement: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to get settlement: %w", err)
+	case Status(row.Status) == StatusCompleted:
+		return castFromRepositorySettlement(row), nil
+	case row.TxSignature.Valid:
+		// the swap sent by the previous attempt may have landed
+		done, err := s.checkSent(ctx, row)
+		if err != nil || done != nil {
+			return done, err
+		}
+	}
+
+	var outAmount uint64
+	signature, err := solana.NewSender(s.sol).Send(ctx, func(ctx context.Context) (string, error) {
+		tx, out, err := s.buildSwap(ctx, row.InputMint, uint64(row.InAmount))
+		if err != nil {
+			return "", err
+		}
+		sig, err := solana.TransactionSignature(tx)
+		if err != nil {
+			return "", err
+		}
+		if row, err = s.update(ctx, row.ID, StatusPending, out, sig, nil); err != nil {
+			return "", err
+		}
+		outAmount = out
+		return tx, nil
+	})
+	if err != nil {
+		// the signature of the last built swap is kept, so the next attempt checks it first
+		if _, updErr := s.update(ctx, row.ID, StatusFailed, uint64(row.OutAmount), row.TxSignature.String, err); updErr != nil {
+			return nil, fmt.Errorf("failed to send settlement swap: %v; %w", err, updErr)
+		}
+		return nil, fmt.Errorf("failed to send settlement swap: %w", err)
+	}
+
+	status, err := s.sol.WaitForTransactionConfirmed(ctx, signature, s.commitment, confirmationTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for settlement swap %s: %w", signature, err)
+	}
+	switch status {
+	case solana.TransactionStatusSuccess:
+		row, err = s.update(ctx, row.ID, StatusCompleted, outAmount, signature, nil)
+		if err != nil {
+			return nil, err
+		}
+		return castFromRepositorySettlement(row), nil
+	case solana.TransactionStatusFailure:
+		cause := fmt.Errorf("settlement swap %s failed", signature)
+		if _, err := s.update(ctx, row.ID, StatusFailed, outAmount, signature, cause); err != nil {
+			return nil, err
+		}
+		return nil, cause
+	}
+
+	return nil, fmt.Errorf("settlement swap %s is %s", signature, status)
+}
+
+// checkSent checks the swap sent by the previous attempt of the settlement.
+// Returns the completed settlement if the swap has landed, an error if it may still land,
+// or nil if the swap failed or was dropped and must be sent again.
+func (s *Service) checkSent(ctx context.Context, row repository.Settlement) (*Settlement, error) {
+	signature := row.TxSignature.String
+	status, err := s.sol.GetTransactionStatus(ctx, signature, s.commitment)
+	switch {
+	case status == solana.TransactionStatusSuccess:
+		row, err = s.update(ctx, row.ID, StatusCompleted, uint64(row.OutAmount), signature, nil)
+		if err != nil {
+			return nil, err
+		}
+		return castFromRepositorySettlement(row), nil
+	case status == solana.TransactionStatusFailure:
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to get settlement swap %s status: %w", signature, err)
+	case status == solana.TransactionStatusUnknown && time.Since(lastUpdate(row)) > swapExpiry:
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("settlement swap %s is %s", signature, status)
+}
+
+// buildSwap returns the signed swap transaction of the amount of the input mint into the settlement mint,
+// and the quoted amount of the settlement mint.
+func (s *Service) buildSwap(ctx context.Context, inputMint string, amount uint64) (string, uint64, error) {
+	quote, err := s.jup.Quote(ctx, jupiter.QuoteParams{
+		InputMint:           inputMint,
+		OutputMint:          s.mint,
+		Amount:              amount,
+		SwapMode:            jupiter.SwapModeExactIn,
+		SlippageBps:         s.slippageBps,
+		AsLegacyTransaction: true,
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to quote settlement swap: %w", err)
+	}
+	route, err := quote.GetBestRoute()
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to quote settlement swap: %w", err)
+	}
+	outAmount, err := strconv.ParseUint(route.OutAmount, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to parse settlement swap out amount: %w", err)
+	}
+
+	swapTx, err := s.jup.Swap(ctx, jupiter.SwapParams{
+		Route:               route,
+		UserPublicKey:       s.wallet,
+		WrapUnwrapSol:       utils.Pointer(true),
+		AsLegacyTransaction: utils.Pointer(true),
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get settlement swap transaction: %w", err)
+	}
+
+	signed, err := solana.SignTransactionWithSigner(ctx, swapTx, s.signer)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return signed, outAmount, nil
+}
+
+// update stores the status of the settlement.
+func (s *Service) update(ctx context.Context, id uuid.UUID, status Status, outAmount uint64, signature string, cause error) (repository.Settlement, error) {
+	params := repository.UpdateSettlementParams{
+		ID:          id,
+		Status:      string(status),
+		OutAmount:   int64(outAmount),
+		TxSignature: sql.NullString{String: signature, Valid: signature != ""},
+	}
+	if cause != nil {
+		params.Error = sql.NullString{String: cause.Error(), Valid: true}
+	}
+
+	row, err := s.repo.UpdateSettlement(ctx, params)
+	if err != nil {
+		return repository.Settlement{}, fmt.Errorf("failed to update settlement: %w", err)
+	}
+	return row, nil
+}
+
+// receivedAmount returns the amount received by the completed transactions of the payment.
+func receivedAmount(attempts []*payments.PaymentAttempt) uint64 {
+	var result uint64
+	for _, a := range attempts {
+		if a.Status == payments.TransactionStatusCompleted {
+			result += a.TotalAmount
+		}
+	}
+	return result
+}
+
+// lastUpdate returns the time the settlement was updated at, e.g. the last swap was built.
+func lastUpdate(row repository.Settlement) time.Time {
+	if row.UpdatedAt.Valid {
+		return row.UpdatedAt.Time
+	}
+	return row.CreatedAt
+}
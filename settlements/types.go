@@ -0,0 +1,85 @@
+package settlements
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/jupiter"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/google/uuid"
+)
+
+// Worker task types
+const (
+	TaskSettlePayment = "settlements:settle_payment"
+)
+
+// Status is the status of the settlement swap.
+type Status string
+
+// Predefined settlement statuses.
+const (
+	StatusPending   Status = "pending" // the swap is created or sent, waiting for the confirmation
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed" // the swap is retried by the next attempt of the task
+)
+
+type (
+	// SettlePaymentPayload is the payload for the settlements:settle_payment task.
+	SettlePaymentPayload struct {
+		PaymentID string `json:"payment_id"`
+	}
+
+	// Settlement is the swap of the payment funds received in another mint into the settlement mint.
+	Settlement struct {
+		ID         uuid.UUID  `json:"id"`
+		PaymentID  uuid.UUID  `json:"payment_id"`
+		InputMint  string     `json:"input_mint"`
+		OutputMint string     `json:"output_mint"`
+		InAmount   uint64     `json:"in_amount"`
+		OutAmount  uint64     `json:"out_amount"` // quoted until the swap is completed
+		Status     Status     `json:"status"`
+		Signature  string     `json:"signature,omitempty"`
+		Error      string     `json:"error,omitempty"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	}
+
+	settlementRepository interface {
+		CreateSettlement(ctx context.Context, arg repository.CreateSettlementParams) (repository.Settlement, error)
+		GetSettlementByPaymentID(ctx context.Context, paymentID uuid.UUID) (repository.Settlement, error)
+		UpdateSettlement(ctx context.Context, arg repository.UpdateSettlementParams) (repository.Settlement, error)
+	}
+
+	solanaClient interface {
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+		GetTransactionStatus(ctx context.Context, txhash string, commitment solana.Commitment) (solana.TransactionStatus, error)
+		WaitForTransactionConfirmed(ctx context.Context, txhash string, commitment solana.Commitment, maxDuration time.Duration) (solana.TransactionStatus, error)
+	}
+
+	jupiterClient interface {
+		Quote(ctx context.Context, params jupiter.QuoteParams) (jupiter.QuoteResponse, error)
+		Swap(ctx context.Context, params jupiter.SwapParams) (string, error)
+	}
+)
+
+// castFromRepositorySettlement converts a repository settlement to a settlement.
+func castFromRepositorySettlement(s repository.Settlement) *Settlement {
+	result := &Settlement{
+		ID:         s.ID,
+		PaymentID:  s.PaymentID,
+		InputMint:  s.InputMint,
+		OutputMint: s.OutputMint,
+		InAmount:   uint64(s.InAmount),
+		OutAmount:  uint64(s.OutAmount),
+		Status:     Status(s.Status),
+		Signature:  s.TxSignature.String,
+		Error:      s.Error.String,
+		CreatedAt:  s.CreatedAt,
+	}
+	if s.UpdatedAt.Valid {
+		result.UpdatedAt = &s.UpdatedAt.Time
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package settlements
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+type (
+	// Worker is a task handler for the settlement swaps.
+	Worker struct {
+		svc        service
+		paymentSvc paymentService
+	}
+
+	service interface {
+		Settle(ctx context.Context, payment *payments.Payment, attempts []*payments.PaymentAttempt) (*Settlement, error)
+	}
+
+	paymentService interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (*payments.Payment, error)
+		GetPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]*payments.PaymentAttempt, error)
+	}
+)
+
+// NewWorker creates a new settlements task handler.
+func NewWorker(svc service, paymentSvc paymentService) *Worker {
+	return &Worker{svc: svc, paymentSvc: paymentSvc}
+}
+
+// Register registers task handlers for the settlement swaps.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskSettlePayment, w.SettlePayment)
+}
+
+// SettlePayment swaps the funds of the payment into the settlement mint.
+func (w *Worker) SettlePayment(ctx context.Context, t *asynq.Task) error {
+	var p SettlePaymentPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+
+	pid, err := uuid.Parse(p.PaymentID)
+	if err != nil {
+		return fmt.Errorf("failed to parse payment id: %w", err)
+	}
+
+	payment, err := w.paymentSvc.GetPayment(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	attempts, err := w.paymentSvc.GetPaymentAttempts(ctx, pid)
+	if err != nil {
+		return fmt.Errorf("failed to get payment attempts: %w", err)
+	}
+
+	if _, err := w.svc.Settle(ctx, payment, attempts); err != nil {
+		return fmt.Errorf("failed to settle payment: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/metrics"
+)
+
+// Circuit breaker states.
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half_open"
+)
+
+// Circuit breaker metrics.
+var (
+	breakerState = metrics.NewGaugeVec(
+		"solana_rpc_circuit_open",
+		"Whether the circuit breaker of the Solana RPC endpoint is open (1) or not (0).",
+		"endpoint",
+	)
+	breakerRejectedTotal = metrics.NewCounterVec(
+		"solana_rpc_circuit_rejected_total",
+		"Total number of Solana RPC calls rejected by the open circuit breaker.",
+		"endpoint",
+	)
+)
+
+// CircuitBreaker stops calling the RPC endpoint after a number of consecutive failures,
+// so sustained RPC outages fail fast with ErrChainUnavailable instead of piling up slow failing calls.
+// After the cooldown, a single probe call is let through: the breaker closes if it succeeds
+// and opens again for another cooldown if it fails.
+// Only transport failures and timeouts are counted; RPC errors returned by a healthy node,
+// e.g. "account not found", are not.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	endpoint  string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	state    string
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// NewCircuitBreaker creates a new circuit breaker for the given RPC endpoint.
+// threshold is the number of consecutive failures to open the breaker,
+// cooldown is the time to wait before the probe call.
+func NewCircuitBreaker(endpoint string, threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &CircuitBreaker{
+		endpoint:  endpointLabel(endpoint),
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		state:     breakerClosed,
+	}
+}
+
+// Allow returns ErrChainUnavailable if the call must not be made.
+// Every allowed call must be followed by Record with the call result.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			breakerRejectedTotal.WithLabelValues(b.endpoint).Inc()
+			return ErrChainUnavailable
+		}
+		b.state = breakerHalfOpen
+		b.probing = true
+		return nil
+	case breakerHalfOpen:
+		if b.probing {
+			breakerRejectedTotal.WithLabelValues(b.endpoint).Inc()
+			return ErrChainUnavailable
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record records the result of the allowed call.
+func (b *CircuitBreaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// the caller gave up, the call says nothing about the endpoint health
+	if errors.Is(err, context.Canceled) {
+		b.probing = false
+		return
+	}
+
+	if !isRPCFailure(err) {
+		b.state = breakerClosed
+		b.failures = 0
+		b.probing = false
+		breakerState.WithLabelValues(b.endpoint).Set(0)
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+		b.probing = false
+		breakerState.WithLabelValues(b.endpoint).Set(1)
+	}
+}
+
+// Available returns false if the breaker is open and the cooldown has not passed yet,
+// i.e. the calls would be rejected. It's used to pause the workers during RPC outages.
+func (b *CircuitBreaker) Available() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state != breakerOpen || b.now().Sub(b.openedAt) >= b.cooldown
+}
+
+// isRPCFailure returns true if the error means the RPC endpoint is unhealthy:
+// a network error, a timeout or an unexpected HTTP status code, e.g. 429 or 5xx.
+func isRPCFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var netErr net.Error
+	var urlErr *url.Error
+	if errors.As(err, &netErr) || errors.As(err, &urlErr) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	return strings.Contains(err.Error(), "status code")
+}
+
+// endpointLabel returns the host of the RPC endpoint to keep the label cardinality low
+// and to not expose API keys from the path or query.
+func endpointLabel(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
@@ -0,0 +1,62 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker("https://api.mainnet-beta.solana.com/?api-key=secret", 2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	rpcDown := &url.Error{Op: "Post", URL: "https://api.mainnet-beta.solana.com", Err: errors.New("connection refused")}
+
+	// rpc errors of a healthy node don't open the breaker
+	for i := 0; i < 3; i++ {
+		require.NoError(t, b.Allow())
+		b.Record(errors.New("account not found"))
+	}
+	require.True(t, b.Available())
+
+	// consecutive transport failures open the breaker
+	require.NoError(t, b.Allow())
+	b.Record(rpcDown)
+	require.NoError(t, b.Allow())
+	b.Record(context.DeadlineExceeded)
+	require.False(t, b.Available())
+	require.ErrorIs(t, b.Allow(), ErrChainUnavailable)
+
+	// a single probe call is allowed after the cooldown
+	now = now.Add(time.Minute)
+	require.True(t, b.Available())
+	require.NoError(t, b.Allow())
+	require.ErrorIs(t, b.Allow(), ErrChainUnavailable)
+
+	// the failed probe opens the breaker again
+	b.Record(rpcDown)
+	require.ErrorIs(t, b.Allow(), ErrChainUnavailable)
+
+	// the successful probe closes the breaker
+	now = now.Add(time.Minute)
+	require.NoError(t, b.Allow())
+	b.Record(nil)
+	require.True(t, b.Available())
+	require.NoError(t, b.Allow())
+	b.Record(nil)
+
+	// a single failure doesn't open the closed breaker
+	require.NoError(t, b.Allow())
+	b.Record(rpcDown)
+	require.NoError(t, b.Allow())
+}
+
+func TestEndpointLabel(t *testing.T) {
+	require.Equal(t, "api.mainnet-beta.solana.com", endpointLabel("https://api.mainnet-beta.solana.com/?api-key=secret"))
+	require.Equal(t, "unknown", endpointLabel(""))
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/easypmnt/checkout-api/solana/metadata"
@@ -13,6 +14,7 @@ import (
 	"github.com/portto/solana-go-sdk/client"
 	"github.com/portto/solana-go-sdk/common"
 	"github.com/portto/solana-go-sdk/program/metaplex/token_metadata"
+	"github.com/portto/solana-go-sdk/program/token"
 	"github.com/portto/solana-go-sdk/rpc"
 )
 
@@ -21,8 +23,23 @@ type (
 	// It implements the SolanaClient interface.
 	Client struct {
 		rpcClient     *client.Client
+		rpcEndpoint   string
 		wsClient      *client.Client
 		tokenListPath string
+
+		breaker          *CircuitBreaker
+		breakerThreshold int
+		breakerCooldown  time.Duration
+
+		mintDecimals      sync.Map // mint address -> uint8
+		mintDecimalsStore MintDecimalsStore
+	}
+
+	// MintDecimalsStore is a persistent storage of the mint decimals.
+	// Decimals of a mint can't be changed, so they can be stored forever.
+	MintDecimalsStore interface {
+		GetMintDecimals(ctx context.Context, base58MintAddr string) (uint8, error)
+		StoreMintDecimals(ctx context.Context, base58MintAddr string, decimals uint8) error
 	}
 
 	// ClientOption is a function that configures the Client.
@@ -40,6 +57,9 @@ func NewClient(opts ...ClientOption) *Client {
 	if c.rpcClient == nil {
 		panic("rpc client is nil")
 	}
+	if c.breakerThreshold > 0 {
+		c.breaker = NewCircuitBreaker(c.rpcEndpoint, c.breakerThreshold, c.breakerCooldown)
+	}
 	return c
 }
 
@@ -54,6 +74,18 @@ func WithRPCClient(rpcClient *client.Client) ClientOption {
 func WithRPCEndpoint(endpoint string) ClientOption {
 	return func(c *Client) {
 		c.rpcClient = client.NewClient(endpoint)
+		c.rpcEndpoint = endpoint
+	}
+}
+
+// WithCircuitBreaker enables the circuit breaker of the rpc endpoint.
+// The breaker opens after the given number of consecutive failed rpc calls
+// and rejects all calls with ErrChainUnavailable until the cooldown passes.
+// Zero threshold disables the breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) ClientOption {
+	return func(c *Client) {
+		c.breakerThreshold = threshold
+		c.breakerCooldown = cooldown
 	}
 }
 
@@ -64,6 +96,13 @@ func WithTokenListPath(path string) ClientOption {
 	}
 }
 
+// WithMintDecimalsStore sets the persistent storage of the mint decimals.
+func WithMintDecimalsStore(store MintDecimalsStore) ClientOption {
+	return func(c *Client) {
+		c.mintDecimalsStore = store
+	}
+}
+
 // WithWSClient sets the ws client.
 func WithWSClient(wsClient *client.Client) ClientOption {
 	return func(c *Client) {
@@ -78,9 +117,88 @@ func WithWSEndpoint(endpoint string) ClientOption {
 	}
 }
 
+// Available returns false while the circuit breaker of the rpc endpoint is open,
+// i.e. the rpc calls fail fast with ErrChainUnavailable.
+func (c *Client) Available() bool {
+	return c.breaker == nil || c.breaker.Available()
+}
+
+// GetSlot returns the latest slot processed by the rpc node.
+func (c *Client) GetSlot(ctx context.Context) (uint64, error) {
+	if err := c.allow(); err != nil {
+		return 0, err
+	}
+	slot, err := c.rpcClient.GetSlotWithConfig(ctx, rpc.GetSlotConfig{Commitment: rpc.CommitmentProcessed})
+	c.record(err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get slot: %w", err)
+	}
+
+	return slot, nil
+}
+
+// rpcMethodNotFound is the JSON-RPC error code of the unsupported method.
+const rpcMethodNotFound = -32601
+
+// GetSlotLag returns the number of slots the rpc node is behind the cluster, as reported by its getHealth method.
+// It returns an error if the node is unhealthy for other reasons.
+func (c *Client) GetSlotLag(ctx context.Context) (uint64, error) {
+	if err := c.allow(); err != nil {
+		return 0, err
+	}
+	// the unhealthy node may respond with a non-2xx status and the error in the body
+	body, err := c.rpcClient.RpcClient.Call(ctx, "getHealth")
+	if len(body) == 0 {
+		c.record(err)
+		return 0, fmt.Errorf("failed to get rpc node health: %w", err)
+	}
+	c.record(nil)
+
+	var resp struct {
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Data    struct {
+				NumSlotsBehind *uint64 `json:"numSlotsBehind"`
+			} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return 0, fmt.Errorf("failed to decode rpc node health: %w", err)
+	}
+	// the lag is unknown if the rpc provider doesn't support the method
+	if resp.Error == nil || resp.Error.Code == rpcMethodNotFound {
+		return 0, nil
+	}
+	if resp.Error.Data.NumSlotsBehind != nil {
+		return *resp.Error.Data.NumSlotsBehind, nil
+	}
+
+	return 0, fmt.Errorf("rpc node is unhealthy: %s", resp.Error.Message)
+}
+
+// allow checks the circuit breaker of the rpc endpoint, if it's enabled.
+func (c *Client) allow() error {
+	if c.breaker == nil {
+		return nil
+	}
+	return c.breaker.Allow()
+}
+
+// record records the rpc call result in the circuit breaker, if it's enabled.
+func (c *Client) record(err error) {
+	if c.breaker != nil {
+		c.breaker.Record(err)
+	}
+}
+
 // GetLatestBlockhash returns the latest blockhash.
 func (c *Client) GetLatestBlockhash(ctx context.Context) (string, error) {
+	if err := c.allow(); err != nil {
+		return "", err
+	}
 	blockhash, err := c.rpcClient.GetLatestBlockhash(ctx)
+	c.record(err)
 	if err != nil {
 		return "", ErrGetLatestBlockhash
 	}
@@ -91,7 +209,11 @@ func (c *Client) GetLatestBlockhash(ctx context.Context) (string, error) {
 // DoesTokenAccountExist returns true if the token account exists.
 // Otherwise, it returns false.
 func (c *Client) DoesTokenAccountExist(ctx context.Context, base58AtaAddr string) (bool, error) {
+	if err := c.allow(); err != nil {
+		return false, err
+	}
 	ata, err := c.rpcClient.GetTokenAccount(ctx, base58AtaAddr)
+	c.record(err)
 	if err != nil {
 		return false, ErrTokenAccountDoesNotExist
 	}
@@ -99,10 +221,88 @@ func (c *Client) DoesTokenAccountExist(ctx context.Context, base58AtaAddr string
 	return ata.Mint.Bytes() != nil, nil
 }
 
+// IsTokenAccountFrozen returns true if the associated token account
+// of the given wallet and mint is frozen by the mint freeze authority.
+// Returns ErrTokenAccountDoesNotExist if the account can't be loaded.
+func (c *Client) IsTokenAccountFrozen(ctx context.Context, base58Addr, base58MintAddr string) (bool, error) {
+	ata, err := AssociatedTokenAddress(base58Addr, base58MintAddr)
+	if err != nil {
+		return false, err
+	}
+	if err := c.allow(); err != nil {
+		return false, err
+	}
+	account, err := c.rpcClient.GetTokenAccount(ctx, ata)
+	c.record(err)
+	if err != nil {
+		return false, ErrTokenAccountDoesNotExist
+	}
+
+	return account.State == token.TokenAccountFrozen, nil
+}
+
+// GetTokenAllowance returns the delegation of the associated token account
+// of the given wallet and mint.
+// Returns ErrTokenAccountDoesNotExist if the account can't be loaded.
+func (c *Client) GetTokenAllowance(ctx context.Context, base58Addr, base58MintAddr string) (TokenAllowance, error) {
+	ata, err := AssociatedTokenAddress(base58Addr, base58MintAddr)
+	if err != nil {
+		return TokenAllowance{}, err
+	}
+	if err := c.allow(); err != nil {
+		return TokenAllowance{}, err
+	}
+	account, err := c.rpcClient.GetTokenAccount(ctx, ata)
+	c.record(err)
+	if err != nil {
+		return TokenAllowance{}, ErrTokenAccountDoesNotExist
+	}
+
+	result := TokenAllowance{Balance: account.Amount}
+	if account.Delegate != nil {
+		result.Delegate = account.Delegate.ToBase58()
+		result.Amount = account.DelegatedAmount
+	}
+
+	return result, nil
+}
+
+// ValidateDestination checks that the given account can own the associated token accounts,
+// so it can be used as the payment destination or the withdrawal recipient.
+// New and system owned accounts (wallets and PDA vaults, e.g. Squads vaults) and SPL multisig accounts are allowed;
+// SPL token accounts and mints are rejected with ErrInvalidDestination, since the tokens sent to their ATAs would be lost.
+func (c *Client) ValidateDestination(ctx context.Context, base58Addr string) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	info, err := c.rpcClient.GetAccountInfo(ctx, base58Addr)
+	c.record(err)
+	if err != nil {
+		return errors.Wrap(err, "failed to get destination account info")
+	}
+
+	switch info.Owner {
+	case common.PublicKey{}, common.SystemProgramID:
+		return nil
+	case common.TokenProgramID:
+		if len(info.Data) == token.MultisigAccountSize {
+			return nil
+		}
+		return ErrInvalidDestination
+	}
+
+	// accounts owned by other programs are handled by those programs, e.g. the program derived vaults
+	return nil
+}
+
 // RequestAirdrop sends a request to the solana network to airdrop SOL to the given account.
 // Returns the transaction signature or an error.
 func (c *Client) RequestAirdrop(ctx context.Context, base58Addr string, amount uint64) (string, error) {
+	if err := c.allow(); err != nil {
+		return "", err
+	}
 	txSig, err := c.rpcClient.RequestAirdrop(ctx, base58Addr, amount)
+	c.record(err)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to request airdrop")
 	}
@@ -113,7 +313,11 @@ func (c *Client) RequestAirdrop(ctx context.Context, base58Addr string, amount u
 // GetSOLBalance returns the SOL balance in lamports of the given base58 encoded account address.
 // Returns the balance or an error.
 func (c *Client) GetSOLBalance(ctx context.Context, base58Addr string) (Balance, error) {
+	if err := c.allow(); err != nil {
+		return Balance{}, err
+	}
 	balance, err := c.rpcClient.GetBalance(ctx, base58Addr)
+	c.record(err)
 	if err != nil {
 		return Balance{}, errors.Wrap(err, "failed to get balance")
 	}
@@ -125,7 +329,11 @@ func (c *Client) GetSOLBalance(ctx context.Context, base58Addr string) (Balance,
 // base58Addr is the base58 encoded associated token account address.
 // Returns the balance in lamports and token decimals, or an error.
 func (c *Client) GetAtaBalance(ctx context.Context, base58Addr string) (Balance, error) {
+	if err := c.allow(); err != nil {
+		return Balance{}, err
+	}
 	balance, decimals, err := c.rpcClient.GetTokenAccountBalance(ctx, base58Addr)
+	c.record(err)
 	if err != nil {
 		return Balance{}, errors.Wrap(err, "failed to get token account balance")
 	}
@@ -138,21 +346,57 @@ func (c *Client) GetAtaBalance(ctx context.Context, base58Addr string) (Balance,
 // base58MintAddr is the base58 encoded SPL token mint address.
 // Returns the Balance object, or an error.
 func (c *Client) GetTokenBalance(ctx context.Context, base58Addr, base58MintAddr string) (Balance, error) {
-	ata, _, err := common.FindAssociatedTokenAddress(
-		common.PublicKeyFromString(base58Addr),
-		common.PublicKeyFromString(base58MintAddr),
-	)
+	ata, err := AssociatedTokenAddress(base58Addr, base58MintAddr)
 	if err != nil {
-		return Balance{}, errors.Wrap(err, "failed to find associated token address")
+		return Balance{}, err
 	}
 
-	return c.GetAtaBalance(ctx, ata.String())
+	return c.GetAtaBalance(ctx, ata)
+}
+
+// GetTokenAccountsByOwner returns the non-zero SPL token balances of the given base58 encoded wallet address.
+// Balances of several token accounts of the same mint are summed up.
+// Wrapped SOL accounts are skipped, use GetSOLBalance to get the native SOL balance.
+// Returns a map of the base58 encoded mint address to the balance, or an error.
+func (c *Client) GetTokenAccountsByOwner(ctx context.Context, base58Addr string) (map[string]Balance, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	accounts, err := c.rpcClient.GetTokenAccountsByOwner(ctx, base58Addr)
+	c.record(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token accounts by owner: %w", err)
+	}
+
+	amounts := make(map[string]uint64, len(accounts))
+	for _, account := range accounts {
+		mint := account.Mint.ToBase58()
+		if account.Amount == 0 || mint == NativeMint {
+			continue
+		}
+		amounts[mint] += account.Amount
+	}
+
+	result := make(map[string]Balance, len(amounts))
+	for mint, amount := range amounts {
+		decimals, err := c.GetMintDecimals(ctx, mint)
+		if err != nil {
+			return nil, err
+		}
+		result[mint] = NewBalance(amount, decimals)
+	}
+
+	return result, nil
 }
 
 // GetMinimumBalanceForRentExemption gets the minimum balance for rent exemption.
 // Returns the minimum balance in lamports or an error.
 func (c *Client) GetMinimumBalanceForRentExemption(ctx context.Context, size uint64) (uint64, error) {
+	if err := c.allow(); err != nil {
+		return 0, err
+	}
 	mintAccountRent, err := c.rpcClient.GetMinimumBalanceForRentExemption(ctx, size)
+	c.record(err)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get minimum balance for rent exemption: %w", err)
 	}
@@ -160,10 +404,16 @@ func (c *Client) GetMinimumBalanceForRentExemption(ctx context.Context, size uin
 	return mintAccountRent, nil
 }
 
-// GetTransactionStatus gets the transaction status.
+// GetTransactionStatus gets the transaction status against the required commitment level:
+// success once the transaction has reached it, in progress before.
+// Empty commitment means finalized.
 // Returns the transaction status or an error.
-func (c *Client) GetTransactionStatus(ctx context.Context, txhash string) (TransactionStatus, error) {
+func (c *Client) GetTransactionStatus(ctx context.Context, txhash string, commitment Commitment) (TransactionStatus, error) {
+	if err := c.allow(); err != nil {
+		return TransactionStatusUnknown, err
+	}
 	status, err := c.rpcClient.GetSignatureStatus(ctx, txhash)
+	c.record(err)
 	if err != nil {
 		return TransactionStatusUnknown, fmt.Errorf("failed to get transaction status: %v", err)
 	}
@@ -179,7 +429,7 @@ func (c *Client) GetTransactionStatus(ctx context.Context, txhash string) (Trans
 		result = TransactionStatusInProgress
 	}
 	if status.ConfirmationStatus != nil {
-		result = ParseTransactionStatus(*status.ConfirmationStatus)
+		result = TransactionStatusAt(*status.ConfirmationStatus, commitment)
 	}
 
 	return result, nil
@@ -193,7 +443,11 @@ func (c *Client) SendTransaction(ctx context.Context, txSource string) (string,
 		return "", fmt.Errorf("failed to send transaction: base64 to bytes: %w", err)
 	}
 
+	if err := c.allow(); err != nil {
+		return "", err
+	}
 	txSig, err := c.rpcClient.SendTransaction(ctx, tx)
+	c.record(err)
 	if err != nil {
 		return "", fmt.Errorf("failed to send transaction: %w", err)
 	}
@@ -201,11 +455,11 @@ func (c *Client) SendTransaction(ctx context.Context, txSource string) (string,
 	return txSig, nil
 }
 
-// WaitForTransactionConfirmed waits for a transaction to be confirmed.
+// WaitForTransactionConfirmed waits for a transaction to reach the given commitment level.
 // Returns the transaction status or an error.
 // If maxDuration is 0, it will wait for 5 minutes.
 // Can be useful for testing, but not recommended for production because it may block requests for a long time.
-func (c *Client) WaitForTransactionConfirmed(ctx context.Context, txhash string, maxDuration time.Duration) (TransactionStatus, error) {
+func (c *Client) WaitForTransactionConfirmed(ctx context.Context, txhash string, commitment Commitment, maxDuration time.Duration) (TransactionStatus, error) {
 	tick := time.NewTicker(5 * time.Second)
 	defer tick.Stop()
 
@@ -223,7 +477,7 @@ func (c *Client) WaitForTransactionConfirmed(ctx context.Context, txhash string,
 				txhash, maxDuration.String(),
 			)
 		case <-tick.C:
-			status, err := c.GetTransactionStatus(ctx, txhash)
+			status, err := c.GetTransactionStatus(ctx, txhash, commitment)
 			if err != nil {
 				return TransactionStatusUnknown, fmt.Errorf("failed to get transaction status: %w", err)
 			}
@@ -237,19 +491,25 @@ func (c *Client) WaitForTransactionConfirmed(ctx context.Context, txhash string,
 	}
 }
 
-// GetOldestTransactionForWallet returns the oldest transaction by the given base58 encoded public key.
+// GetOldestTransactionForWallet returns the oldest transaction by the given base58 encoded public key,
+// which has reached the given commitment level. Empty commitment means finalized.
 // Returns the transaction or an error.
 func (c *Client) GetOldestTransactionForWallet(
 	ctx context.Context,
 	base58Addr string,
 	offsetTxSignature string,
+	commitment Commitment,
 ) (string, *client.GetTransactionResponse, error) {
 	limit := 1000
+	if err := c.allow(); err != nil {
+		return "", nil, err
+	}
 	result, err := c.rpcClient.GetSignaturesForAddressWithConfig(ctx, base58Addr, rpc.GetSignaturesForAddressConfig{
 		Limit:      limit,
 		Before:     offsetTxSignature,
-		Commitment: rpc.CommitmentFinalized,
+		Commitment: lookupCommitment(commitment),
 	})
+	c.record(err)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get signatures for address: %s: %w", base58Addr, err)
 	}
@@ -259,7 +519,7 @@ func (c *Client) GetOldestTransactionForWallet(
 	} else if l < limit {
 		tx := result[l-1]
 		if tx.Err != nil {
-			return "", nil, fmt.Errorf("transaction failed: %v", tx.Err)
+			return "", nil, fmt.Errorf("%w: %v", ErrTransactionFailed, tx.Err)
 		}
 		if tx.Signature == "" {
 			return "", nil, ErrNoTransactionsFound
@@ -268,7 +528,7 @@ func (c *Client) GetOldestTransactionForWallet(
 			return "", nil, ErrTransactionNotConfirmed
 		}
 
-		resp, err := c.GetTransaction(ctx, tx.Signature)
+		resp, err := c.getTransaction(ctx, tx.Signature, commitment)
 		if err != nil {
 			return "", nil, fmt.Errorf("failed to get oldest transaction for wallet: %s: %w", base58Addr, err)
 		}
@@ -276,13 +536,51 @@ func (c *Client) GetOldestTransactionForWallet(
 		return tx.Signature, resp, nil
 	}
 
-	return c.GetOldestTransactionForWallet(ctx, base58Addr, result[limit-1].Signature)
+	return c.GetOldestTransactionForWallet(ctx, base58Addr, result[limit-1].Signature, commitment)
+}
+
+// GetSignaturesForAddress returns signatures of the latest successful finalized transactions
+// of the given base58 encoded account address, newest first.
+func (c *Client) GetSignaturesForAddress(ctx context.Context, base58Addr string, limit int) ([]string, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	result, err := c.rpcClient.GetSignaturesForAddressWithConfig(ctx, base58Addr, rpc.GetSignaturesForAddressConfig{
+		Limit:      limit,
+		Commitment: rpc.CommitmentFinalized,
+	})
+	c.record(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signatures for address: %s: %w", base58Addr, err)
+	}
+
+	signatures := make([]string, 0, len(result))
+	for _, tx := range result {
+		if tx.Err != nil || tx.Signature == "" {
+			continue
+		}
+		signatures = append(signatures, tx.Signature)
+	}
+
+	return signatures, nil
 }
 
-// GetTransaction returns the transaction by the given base58 encoded transaction signature.
+// GetTransaction returns the finalized transaction by the given base58 encoded transaction signature.
 // Returns the transaction or an error.
 func (c *Client) GetTransaction(ctx context.Context, txSignature string) (*client.GetTransactionResponse, error) {
-	tx, err := c.rpcClient.GetTransaction(ctx, txSignature)
+	return c.getTransaction(ctx, txSignature, CommitmentFinalized)
+}
+
+// getTransaction returns the transaction by the given base58 encoded transaction signature,
+// which has reached the given commitment level.
+func (c *Client) getTransaction(ctx context.Context, txSignature string, commitment Commitment) (*client.GetTransactionResponse, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	tx, err := c.rpcClient.GetTransactionWithConfig(ctx, txSignature, rpc.GetTransactionConfig{
+		Commitment: lookupCommitment(commitment),
+	})
+	c.record(err)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get transaction: %w", err)
 	}
@@ -296,12 +594,36 @@ func (c *Client) GetTransaction(ctx context.Context, txSignature string) (*clien
 	return tx, nil
 }
 
+// GetInboundTransfer returns the amount of the given mint credited to the base58 encoded wallet address
+// in the transaction with the given signature. Empty mint means SOL.
+func (c *Client) GetInboundTransfer(ctx context.Context, txSignature, base58Addr, base58MintAddr string) (*InboundTransfer, error) {
+	tx, err := c.GetTransaction(ctx, txSignature)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InboundTransfer{
+		Signature: txSignature,
+		Amount:    inboundAmount(tx.Meta, tx.Transaction, base58MintAddr, base58Addr),
+		Accounts:  transactionAccounts(tx.Transaction),
+	}
+	if len(result.Accounts) > 0 {
+		result.Sender = result.Accounts[0]
+	}
+
+	return result, nil
+}
+
 // GetTokenSupply returns the token supply for a given mint address.
 // This is a wrapper around the GetTokenSupply function from the solana-go-sdk.
 // base58MintAddr is the base58 encoded address of the token mint.
 // The function returns the token supply and decimals or an error.
 func (c *Client) GetTokenSupply(ctx context.Context, base58MintAddr string) (Balance, error) {
+	if err := c.allow(); err != nil {
+		return Balance{}, err
+	}
 	amount, decimals, err := c.rpcClient.GetTokenSupply(ctx, base58MintAddr)
+	c.record(err)
 	if err != nil {
 		return Balance{}, fmt.Errorf("failed to get token supply: %w", err)
 	}
@@ -309,6 +631,74 @@ func (c *Client) GetTokenSupply(ctx context.Context, base58MintAddr string) (Bal
 	return NewBalance(amount, decimals), nil
 }
 
+// GetMintDecimals returns the number of decimals of the given base58 encoded mint address.
+// The result is cached in memory and in the persistent store, if it's set.
+// "SOL" and the native mint address return 9.
+func (c *Client) GetMintDecimals(ctx context.Context, base58MintAddr string) (uint8, error) {
+	if base58MintAddr == "SOL" || base58MintAddr == NativeMint {
+		return NativeMintDecimals, nil
+	}
+
+	if d, ok := c.mintDecimals.Load(base58MintAddr); ok {
+		return d.(uint8), nil
+	}
+
+	if c.mintDecimalsStore != nil {
+		if d, err := c.mintDecimalsStore.GetMintDecimals(ctx, base58MintAddr); err == nil {
+			c.mintDecimals.Store(base58MintAddr, d)
+			return d, nil
+		}
+	}
+
+	supply, err := c.GetTokenSupply(ctx, base58MintAddr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get mint decimals: %w", err)
+	}
+
+	c.cacheMintDecimals(ctx, base58MintAddr, supply.Decimals)
+
+	return supply.Decimals, nil
+}
+
+// ValidateMint checks on-chain that the given base58 encoded address is an initialized SPL token mint
+// and returns its decimals. The decimals are cached the same way as in GetMintDecimals.
+// Returns ErrInvalidMint if the account does not exist or is not a mint.
+func (c *Client) ValidateMint(ctx context.Context, base58MintAddr string) (uint8, error) {
+	if base58MintAddr == "SOL" || base58MintAddr == NativeMint {
+		return NativeMintDecimals, nil
+	}
+
+	if err := c.allow(); err != nil {
+		return 0, err
+	}
+	accountInfo, err := c.rpcClient.GetAccountInfo(ctx, base58MintAddr)
+	c.record(err)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account info: %w", err)
+	}
+	if accountInfo.Owner != common.TokenProgramID || len(accountInfo.Data) != token.MintAccountSize {
+		return 0, ErrInvalidMint
+	}
+
+	mint, err := token.MintAccountFromData(accountInfo.Data)
+	if err != nil || !mint.IsInitialized {
+		return 0, ErrInvalidMint
+	}
+
+	c.cacheMintDecimals(ctx, base58MintAddr, mint.Decimals)
+
+	return mint.Decimals, nil
+}
+
+// cacheMintDecimals stores the mint decimals in memory and in the persistent store, if it's set.
+func (c *Client) cacheMintDecimals(ctx context.Context, base58MintAddr string, decimals uint8) {
+	c.mintDecimals.Store(base58MintAddr, decimals)
+	if c.mintDecimalsStore != nil {
+		// the store is just a cache, so the error is not critical
+		_ = c.mintDecimalsStore.StoreMintDecimals(ctx, base58MintAddr, decimals)
+	}
+}
+
 // GetFungibleTokenMetadata returns the on-chain SPL token metadata by the given base58 encoded SPL token mint address.
 // Returns the token metadata or an error.
 func (c *Client) GetFungibleTokenMetadata(ctx context.Context, base58MintAddr string) (result *FungibleTokenMetadata, err error) {
@@ -344,7 +734,11 @@ func (c *Client) GetFungibleTokenMetadata(ctx context.Context, base58MintAddr st
 		return result, fmt.Errorf("failed to get token metadata account: %w", err)
 	}
 
+	if err := c.allow(); err != nil {
+		return result, err
+	}
 	accountInfo, err := c.rpcClient.GetAccountInfo(ctx, metadataAccount.ToBase58())
+	c.record(err)
 	if err != nil {
 		return result, fmt.Errorf("failed to get account info: %w", err)
 	}
@@ -432,15 +826,31 @@ func (c *Client) getDeprecatedTokenMetadata(_ context.Context, base58MintAddr st
 	return &result, nil
 }
 
-// ValidateTransactionByReference returns the transaction by the given reference.
+// ValidateTokenBurn checks that the owner burned at least the given amount of the mint
+// in the transaction with the given base58 encoded signature.
+func (c *Client) ValidateTokenBurn(ctx context.Context, txSignature, owner, mint string, amount uint64) error {
+	tx, err := c.GetTransaction(ctx, txSignature)
+	if err != nil {
+		return fmt.Errorf("failed to validate token burn in transaction %s: %w", txSignature, err)
+	}
+
+	if err := CheckTokenBurnTransaction(tx.Meta, mint, owner, amount); err != nil {
+		return fmt.Errorf("failed to validate token burn in transaction %s: %w", txSignature, err)
+	}
+
+	return nil
+}
+
+// ValidateTransactionByReference returns the transaction by the given reference,
+// which has reached the given commitment level. Empty commitment means finalized.
 // Returns transaction signature or an error if the transaction is not found or the transaction failed.
-func (c *Client) ValidateTransactionByReference(ctx context.Context, reference, destination string, amount uint64, mint string) (string, error) {
-	txSign, tx, err := c.GetOldestTransactionForWallet(ctx, reference, "")
+func (c *Client) ValidateTransactionByReference(ctx context.Context, reference, destination string, amount uint64, mint string, commitment Commitment) (string, error) {
+	txSign, tx, err := c.GetOldestTransactionForWallet(ctx, reference, "", commitment)
 	if err != nil {
 		return "", fmt.Errorf("failed to validate transaction for reference %s: %w", reference, err)
 	}
 
-	if mint == "" || mint == "SOL" || mint == "So11111111111111111111111111111111111111112" {
+	if mint == "" || mint == "SOL" || mint == NativeMint {
 		if err := CheckSolTransferTransaction(tx.Meta, tx.Transaction, destination, amount); err != nil {
 			return "", fmt.Errorf("failed to validate transaction for reference %s: %w", reference, err)
 		}
@@ -453,3 +863,38 @@ func (c *Client) ValidateTransactionByReference(ctx context.Context, reference,
 
 	return txSign, nil
 }
+
+// MatchTransactionByReference matches the oldest transaction of the reference account
+// against the expected transfer of the amount of the mint to the destination wallet.
+// Unlike ValidateTransactionByReference, it returns the details of the match
+// if the transaction is not found, not confirmed, failed or the amount is different.
+// The transaction is confirmed once it has reached the given commitment level, empty means finalized.
+func (c *Client) MatchTransactionByReference(ctx context.Context, reference, destination string, amount uint64, mint string, commitment Commitment) (*ReferenceMatch, error) {
+	result := &ReferenceMatch{ExpectedAmount: amount}
+
+	txSign, tx, err := c.GetOldestTransactionForWallet(ctx, reference, "", commitment)
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrNoTransactionsFound):
+		return result, nil
+	case errors.Is(err, ErrTransactionNotConfirmed):
+		result.Found = true
+		return result, nil
+	case errors.Is(err, ErrTransactionFailed):
+		result.Found, result.Confirmed, result.Failed = true, true, true
+		result.Error = err.Error()
+		return result, nil
+	default:
+		return nil, fmt.Errorf("failed to match transaction for reference %s: %w", reference, err)
+	}
+
+	result.Signature = txSign
+	result.Found, result.Confirmed = true, true
+	result.ReceivedAmount = inboundAmount(tx.Meta, tx.Transaction, mint, destination)
+	result.Matched = result.ReceivedAmount == amount
+	if !result.Matched {
+		result.Error = fmt.Sprintf("amount is not equal to the amount in the transaction: %d != %d", amount, result.ReceivedAmount)
+	}
+
+	return result, nil
+}
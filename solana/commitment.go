@@ -0,0 +1,80 @@
+package solana
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/portto/solana-go-sdk/rpc"
+)
+
+// Commitment is the level of the cluster confirmation a transaction must reach
+// to be treated as confirmed.
+type Commitment = rpc.Commitment
+
+// Commitment levels, from the lowest to the highest.
+const (
+	CommitmentProcessed Commitment = rpc.CommitmentProcessed
+	CommitmentConfirmed Commitment = rpc.CommitmentConfirmed
+	CommitmentFinalized Commitment = rpc.CommitmentFinalized
+)
+
+// commitmentRanks orders the commitment levels.
+var commitmentRanks = map[Commitment]int{
+	CommitmentProcessed: 1,
+	CommitmentConfirmed: 2,
+	CommitmentFinalized: 3,
+}
+
+// ParseCommitment parses the commitment level from the given string.
+// Empty string means finalized.
+func ParseCommitment(s string) (Commitment, error) {
+	if s == "" {
+		return CommitmentFinalized, nil
+	}
+	c := Commitment(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := commitmentRanks[c]; !ok {
+		return "", fmt.Errorf("invalid commitment level: %s", s)
+	}
+	return c, nil
+}
+
+// CommitmentReached reports whether the given confirmation status
+// has reached the required commitment level.
+// Empty required commitment means finalized.
+func CommitmentReached(status, required Commitment) bool {
+	rank, ok := commitmentRanks[status]
+	if !ok {
+		return false
+	}
+	return rank >= commitmentRanks[requiredCommitment(required)]
+}
+
+// TransactionStatusAt returns the transaction status for the given confirmation status
+// against the required commitment level: success once the level is reached, in progress before.
+func TransactionStatusAt(status, required Commitment) TransactionStatus {
+	if _, ok := commitmentRanks[status]; !ok {
+		return TransactionStatusUnknown
+	}
+	if CommitmentReached(status, required) {
+		return TransactionStatusSuccess
+	}
+	return TransactionStatusInProgress
+}
+
+// requiredCommitment returns the given commitment or finalized if it's empty.
+func requiredCommitment(c Commitment) Commitment {
+	if _, ok := commitmentRanks[c]; !ok {
+		return CommitmentFinalized
+	}
+	return c
+}
+
+// lookupCommitment returns the commitment to look up the transactions with.
+// The transaction history methods don't support processed, so it's raised to confirmed.
+func lookupCommitment(c Commitment) Commitment {
+	c = requiredCommitment(c)
+	if c == CommitmentProcessed {
+		return CommitmentConfirmed
+	}
+	return c
+}
@@ -0,0 +1,35 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitment(t *testing.T) {
+	c, err := ParseCommitment("")
+	require.NoError(t, err)
+	require.Equal(t, CommitmentFinalized, c)
+
+	c, err = ParseCommitment(" Confirmed ")
+	require.NoError(t, err)
+	require.Equal(t, CommitmentConfirmed, c)
+
+	_, err = ParseCommitment("max")
+	require.Error(t, err)
+
+	// finalized is required by default
+	require.Equal(t, TransactionStatusInProgress, TransactionStatusAt(CommitmentConfirmed, ""))
+	require.Equal(t, TransactionStatusSuccess, TransactionStatusAt(CommitmentFinalized, ""))
+	require.Equal(t, TransactionStatusInProgress, ParseTransactionStatus(CommitmentProcessed))
+
+	// a higher level satisfies a lower requirement
+	require.Equal(t, TransactionStatusInProgress, TransactionStatusAt(CommitmentProcessed, CommitmentConfirmed))
+	require.Equal(t, TransactionStatusSuccess, TransactionStatusAt(CommitmentConfirmed, CommitmentConfirmed))
+	require.Equal(t, TransactionStatusSuccess, TransactionStatusAt(CommitmentFinalized, CommitmentProcessed))
+	require.Equal(t, TransactionStatusUnknown, TransactionStatusAt("", CommitmentProcessed))
+
+	// the transaction history is not available at processed
+	require.Equal(t, CommitmentConfirmed, lookupCommitment(CommitmentProcessed))
+	require.Equal(t, CommitmentFinalized, lookupCommitment(""))
+}
@@ -0,0 +1,159 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/portto/solana-go-sdk/common"
+	"github.com/portto/solana-go-sdk/program/compute_budget"
+	"github.com/portto/solana-go-sdk/rpc"
+	"github.com/portto/solana-go-sdk/types"
+)
+
+// Compute budget program instruction discriminators, the first byte of the instruction data.
+const (
+	computeBudgetSetComputeUnitLimit = 2
+	computeBudgetSetComputeUnitPrice = 3
+)
+
+// DefaultPriorityFeePercentile is the percentile of the recent prioritization fees
+// used as the compute unit price if the percentile is not set, see TransactionBuilder.SetPriorityFeeEstimator.
+const DefaultPriorityFeePercentile = 75
+
+type (
+	// PrioritizationFee is the minimum compute unit price, in micro-lamports,
+	// paid by a transaction which landed in the slot.
+	PrioritizationFee struct {
+		Slot              uint64 `json:"slot"`
+		PrioritizationFee uint64 `json:"prioritizationFee"`
+	}
+
+	// PriorityFeeEstimator returns the recent prioritization fees of the transactions
+	// which locked the given writable accounts, see Client.GetRecentPrioritizationFees.
+	PriorityFeeEstimator interface {
+		GetRecentPrioritizationFees(ctx context.Context, base58Accounts ...string) ([]PrioritizationFee, error)
+	}
+)
+
+// GetRecentPrioritizationFees returns the prioritization fees of the recent slots,
+// paid by the transactions which locked the given writable accounts, or by any transaction if none are given.
+func (c *Client) GetRecentPrioritizationFees(ctx context.Context, base58Accounts ...string) ([]PrioritizationFee, error) {
+	if err := c.allow(); err != nil {
+		return nil, err
+	}
+	if base58Accounts == nil {
+		base58Accounts = []string{}
+	}
+	body, err := c.rpcClient.RpcClient.Call(ctx, "getRecentPrioritizationFees", base58Accounts)
+	c.record(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent prioritization fees: %w", err)
+	}
+
+	var resp rpc.JsonRpcResponse[[]PrioritizationFee]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode recent prioritization fees: %w", err)
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("failed to get recent prioritization fees: %w", resp.Error)
+	}
+
+	return resp.Result, nil
+}
+
+// EstimateComputeUnitPrice returns the given percentile of the prioritization fees,
+// e.g. 75 is the price paid by 3/4 of the recent slots. Zero if there are no fees.
+func EstimateComputeUnitPrice(fees []PrioritizationFee, percentile int) uint64 {
+	if len(fees) == 0 {
+		return 0
+	}
+	if percentile <= 0 || percentile > 100 {
+		percentile = DefaultPriorityFeePercentile
+	}
+
+	prices := make([]uint64, 0, len(fees))
+	for _, f := range fees {
+		prices = append(prices, f.PrioritizationFee)
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
+
+	// nearest-rank percentile
+	rank := (percentile*len(prices) + 99) / 100
+	return prices[rank-1]
+}
+
+// PriorityFee returns the priority fee in lamports of the transaction with the given compute unit limit and price.
+func PriorityFee(computeUnitLimit uint32, computeUnitPrice uint64) uint64 {
+	return (uint64(computeUnitLimit)*computeUnitPrice + 999_999) / 1_000_000
+}
+
+// computeBudgetInstructions returns the compute budget instructions of the builder settings,
+// except the ones already set by the given instructions, e.g. by the Jupiter swap.
+// A transaction with the duplicate compute budget instructions is rejected.
+func (b *TransactionBuilder) computeBudgetInstructions(ctx context.Context, instructions []types.Instruction) []types.Instruction {
+	var hasLimit, hasPrice bool
+	for _, ins := range instructions {
+		if ins.ProgramID != common.ComputeBudgetProgramID || len(ins.Data) == 0 {
+			continue
+		}
+		switch ins.Data[0] {
+		case computeBudgetSetComputeUnitLimit:
+			hasLimit = true
+		case computeBudgetSetComputeUnitPrice:
+			hasPrice = true
+		}
+	}
+
+	result := make([]types.Instruction, 0, 2)
+	if b.computeUnitLimit > 0 && !hasLimit {
+		result = append(result, compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{
+			Units: b.computeUnitLimit,
+		}))
+	}
+	if !hasPrice {
+		if price := b.computeUnitPriceFor(ctx, instructions); price > 0 {
+			result = append(result, compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{
+				MicroLamports: price,
+			}))
+		}
+	}
+
+	return result
+}
+
+// computeUnitPriceFor returns the compute unit price of the transaction: estimated from the recent
+// prioritization fees of its writable accounts, if the estimator is set, and capped by the max price.
+// The fixed price is the floor of the estimate and the fallback if the estimation fails,
+// so the transaction is still built while the rpc node doesn't support the method.
+func (b *TransactionBuilder) computeUnitPriceFor(ctx context.Context, instructions []types.Instruction) uint64 {
+	price := b.computeUnitPrice
+	if b.priorityFeeEstimator == nil {
+		return price
+	}
+
+	seen := make(map[common.PublicKey]bool)
+	accounts := make([]string, 0, len(instructions))
+	for _, ins := range instructions {
+		for _, acc := range ins.Accounts {
+			if acc.IsWritable && !seen[acc.PubKey] {
+				seen[acc.PubKey] = true
+				accounts = append(accounts, acc.PubKey.ToBase58())
+			}
+		}
+	}
+
+	fees, err := b.priorityFeeEstimator.GetRecentPrioritizationFees(ctx, accounts...)
+	if err != nil {
+		return price
+	}
+	if estimated := EstimateComputeUnitPrice(fees, b.priorityFeePercentile); estimated > price {
+		price = estimated
+	}
+	if b.maxComputeUnitPrice > 0 && price > b.maxComputeUnitPrice {
+		price = b.maxComputeUnitPrice
+	}
+
+	return price
+}
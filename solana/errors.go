@@ -18,4 +18,19 @@ var (
 	ErrNoTransactionsFound       = errors.New("no transactions found")
 	ErrTransactionNotConfirmed   = errors.New("transaction not confirmed")
 	ErrTransactionNotFound       = errors.New("transaction not found")
+	ErrTransactionFailed         = errors.New("transaction failed")
+	ErrInvalidSignature          = errors.New("invalid signature")
+	ErrUnsupportedKeyType        = errors.New("unsupported key type, ed25519 key is required")
+	ErrInvalidMint               = errors.New("account is not an initialized spl token mint")
+	ErrBelowRentExemption        = errors.New("recipient balance would be below the minimum balance for rent exemption")
+	ErrChainUnavailable          = errors.New("chain temporarily unavailable")
+
+	ErrDelegateAndMultisigAreExclusive = errors.New("delegate and multisig signers can't be set both")
+	ErrTransactionMismatch             = errors.New("transactions have different messages")
+	ErrInvalidDestination              = errors.New("destination account can't own token accounts")
+	ErrBlockhashExpired                = errors.New("transaction blockhash expired")
+
+	ErrUnsupportedTransactionVersion = errors.New("unsupported transaction version")
+	ErrLookupTablesRequireV0         = errors.New("address lookup tables require a versioned transaction")
+	ErrTransactionTooLarge           = errors.New("transaction exceeds the maximum transaction size")
 )
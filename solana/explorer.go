@@ -0,0 +1,70 @@
+package solana
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Supported block explorers.
+const (
+	ExplorerSolscan = "solscan"
+	ExplorerSolana  = "solana"
+)
+
+// Solana clusters.
+const (
+	ClusterMainnet = "mainnet-beta"
+	ClusterDevnet  = "devnet"
+	ClusterTestnet = "testnet"
+)
+
+// explorerTemplates are the transaction URL templates of the supported block explorers.
+var explorerTemplates = map[string]string{
+	ExplorerSolscan: "https://solscan.io/tx/{signature}",
+	ExplorerSolana:  "https://explorer.solana.com/tx/{signature}",
+}
+
+// Explorer builds the block explorer URLs of the transactions for the configured cluster.
+type Explorer struct {
+	template string
+	cluster  string
+}
+
+// NewExplorer creates a new block explorer URL builder.
+// The explorer is either one of the supported explorers, e.g. ExplorerSolscan,
+// or a custom URL template with {signature} and optional {cluster} placeholders.
+// The cluster query parameter is added to the supported explorers URLs for non-mainnet clusters.
+func NewExplorer(explorer, cluster string) (*Explorer, error) {
+	if cluster == "" {
+		cluster = ClusterMainnet
+	}
+
+	template, ok := explorerTemplates[strings.ToLower(explorer)]
+	if ok {
+		if cluster != ClusterMainnet {
+			template += "?cluster=" + url.QueryEscape(cluster)
+		}
+	} else {
+		if !strings.Contains(explorer, "{signature}") {
+			return nil, fmt.Errorf("unsupported block explorer %q: must be %s, %s or a URL template with {signature} placeholder",
+				explorer, ExplorerSolscan, ExplorerSolana)
+		}
+		template = explorer
+	}
+
+	return &Explorer{template: template, cluster: cluster}, nil
+}
+
+// TransactionURL returns the block explorer URL of the transaction with the given signature.
+// Returns an empty string if the signature is empty or the explorer is not configured.
+func (e *Explorer) TransactionURL(signature string) string {
+	if e == nil || signature == "" {
+		return ""
+	}
+
+	return strings.NewReplacer(
+		"{signature}", url.PathEscape(signature),
+		"{cluster}", url.QueryEscape(e.cluster),
+	).Replace(e.template)
+}
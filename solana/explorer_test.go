@@ -0,0 +1,31 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplorer(t *testing.T) {
+	sig := "5VERv8NMvzbJMEkV8xnrLkEaWRtSz9CosKDYjCJjBRnbJLgp8uirBgmQpjKhoR4tjF3ZpRzrFmBV6UjKdiSZkQUW"
+
+	solscan, err := NewExplorer(ExplorerSolscan, ClusterMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "https://solscan.io/tx/"+sig, solscan.TransactionURL(sig))
+
+	devnet, err := NewExplorer(ExplorerSolana, ClusterDevnet)
+	require.NoError(t, err)
+	require.Equal(t, "https://explorer.solana.com/tx/"+sig+"?cluster=devnet", devnet.TransactionURL(sig))
+	require.Empty(t, devnet.TransactionURL(""))
+
+	custom, err := NewExplorer("https://explorer.example.com/{cluster}/tx/{signature}", ClusterTestnet)
+	require.NoError(t, err)
+	require.Equal(t, "https://explorer.example.com/testnet/tx/"+sig, custom.TransactionURL(sig))
+
+	_, err = NewExplorer("unknown", ClusterMainnet)
+	require.Error(t, err)
+
+	// not configured
+	var none *Explorer
+	require.Empty(t, none.TransactionURL(sig))
+}
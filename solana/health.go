@@ -0,0 +1,135 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/metrics"
+)
+
+// RPC health metrics.
+var rpcSlotLag = metrics.NewGaugeVec(
+	"solana_rpc_slot_lag",
+	"Number of slots the Solana RPC node is behind the cluster.",
+	"endpoint",
+)
+
+type (
+	// RPCHealth periodically checks that the RPC node is reachable, keeps up with the cluster
+	// and its processed slot advances. It's meant for the readiness probe,
+	// so the traffic shifts to the replicas with a healthy chain connection.
+	RPCHealth struct {
+		rpc        slotGetter
+		endpoint   string
+		interval   time.Duration
+		maxSlotLag uint64
+		maxStall   time.Duration
+		now        func() time.Time
+
+		mu         sync.RWMutex
+		err        error
+		lastSlot   uint64
+		lastSlotAt time.Time
+	}
+
+	slotGetter interface {
+		GetSlot(ctx context.Context) (uint64, error)
+		GetSlotLag(ctx context.Context) (uint64, error)
+	}
+)
+
+// NewRPCHealth creates a new health check of the RPC endpoint.
+// The node is unhealthy if it's more than maxSlotLag slots behind the cluster
+// or its slot hasn't advanced for maxStall. Zero values disable the corresponding check.
+func NewRPCHealth(rpc slotGetter, endpoint string, interval time.Duration, maxSlotLag uint64, maxStall time.Duration) *RPCHealth {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	return &RPCHealth{
+		rpc:        rpc,
+		endpoint:   endpointLabel(endpoint),
+		interval:   interval,
+		maxSlotLag: maxSlotLag,
+		maxStall:   maxStall,
+		now:        time.Now,
+	}
+}
+
+// Run checks the RPC node health periodically until the context is canceled.
+func (h *RPCHealth) Run(ctx context.Context) error {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		h.check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check returns the result of the latest health check.
+func (h *RPCHealth) Check() error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.err
+}
+
+// check runs a single health check and stores its result.
+func (h *RPCHealth) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, h.interval)
+	defer cancel()
+
+	err := h.checkSlot(ctx)
+	if err == nil {
+		err = h.checkSlotLag(ctx)
+	}
+
+	h.mu.Lock()
+	h.err = err
+	h.mu.Unlock()
+}
+
+// checkSlot returns an error if the processed slot hasn't advanced for maxStall.
+func (h *RPCHealth) checkSlot(ctx context.Context) error {
+	slot, err := h.rpc.GetSlot(ctx)
+	if err != nil {
+		return fmt.Errorf("solana rpc: %w", err)
+	}
+
+	now := h.now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if slot > h.lastSlot || h.lastSlotAt.IsZero() {
+		h.lastSlot, h.lastSlotAt = slot, now
+		return nil
+	}
+	if h.maxStall > 0 && now.Sub(h.lastSlotAt) > h.maxStall {
+		return fmt.Errorf("solana rpc: slot %d hasn't advanced for %s", slot, now.Sub(h.lastSlotAt).Round(time.Second))
+	}
+
+	return nil
+}
+
+// checkSlotLag returns an error if the node is more than maxSlotLag slots behind the cluster.
+func (h *RPCHealth) checkSlotLag(ctx context.Context) error {
+	lag, err := h.rpc.GetSlotLag(ctx)
+	if err != nil {
+		return fmt.Errorf("solana rpc: %w", err)
+	}
+	rpcSlotLag.WithLabelValues(h.endpoint).Set(float64(lag))
+
+	if h.maxSlotLag > 0 && lag > h.maxSlotLag {
+		return fmt.Errorf("solana rpc: node is %d slots behind", lag)
+	}
+
+	return nil
+}
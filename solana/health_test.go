@@ -0,0 +1,59 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type slotGetterMock struct {
+	slot uint64
+	lag  uint64
+	err  error
+}
+
+func (m *slotGetterMock) GetSlot(ctx context.Context) (uint64, error) {
+	return m.slot, m.err
+}
+
+func (m *slotGetterMock) GetSlotLag(ctx context.Context) (uint64, error) {
+	return m.lag, m.err
+}
+
+func TestRPCHealth(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	rpc := &slotGetterMock{slot: 100}
+	h := NewRPCHealth(rpc, "https://api.mainnet-beta.solana.com", time.Second, 50, time.Minute)
+	h.now = func() time.Time { return now }
+
+	h.check(ctx)
+	require.NoError(t, h.Check())
+
+	// the node falls behind the cluster
+	rpc.slot, rpc.lag = 101, 51
+	h.check(ctx)
+	require.EqualError(t, h.Check(), "solana rpc: node is 51 slots behind")
+
+	// the slot doesn't advance
+	rpc.lag = 0
+	now = now.Add(30 * time.Second)
+	h.check(ctx)
+	require.NoError(t, h.Check())
+	now = now.Add(31 * time.Second)
+	h.check(ctx)
+	require.EqualError(t, h.Check(), "solana rpc: slot 101 hasn't advanced for 1m1s")
+
+	// the node is unreachable
+	rpc.err = errors.New("connection refused")
+	h.check(ctx)
+	require.EqualError(t, h.Check(), "solana rpc: connection refused")
+
+	// recovered
+	rpc.slot, rpc.err = 200, nil
+	h.check(ctx)
+	require.NoError(t, h.Check())
+}
@@ -84,7 +84,7 @@ type TransferSOLParams struct {
 	Sender    string // required; base58 encoded public key of the sender. Must be a signer.
 	Recipient string // required; base58 encoded public key of the recipient.
 	Reference string // optional; base58 encoded public key to use as a reference for the transaction.
-	Amount    uint64 // required; the amount of SOL to send (in lamports). Must be greater than minimum account rent exemption (~0.0025 SOL).
+	Amount    uint64 // required; the amount of SOL to send (in lamports). Recipient must remain rent-exempt after the transfer (~0.00089 SOL).
 }
 
 // Validate validates the parameters.
@@ -107,12 +107,16 @@ func (p TransferSOLParams) Validate() error {
 // TransferSOL transfers SOL from one wallet to another.
 // Note: This function does not check if the sender has enough SOL to send. It is the responsibility
 // of the caller to check this.
-// Amount must be greater than minimum account rent exemption (~0.0025 SOL).
+// The recipient balance after the transfer must be at least the minimum balance for rent exemption,
+// so a new account must receive at least this amount. Otherwise ErrBelowRentExemption is returned.
 func TransferSOL(params TransferSOLParams) InstructionFunc {
-	return func(ctx context.Context, _ SolanaClient) ([]types.Instruction, error) {
+	return func(ctx context.Context, c SolanaClient) ([]types.Instruction, error) {
 		if err := params.Validate(); err != nil {
 			return nil, errors.Wrap(err, "invalid parameters for TransferSOL instruction")
 		}
+		if err := checkRentExemption(ctx, c, params.Recipient, params.Amount); err != nil {
+			return nil, errors.Wrap(err, "TransferSOL instruction")
+		}
 
 		var (
 			senderPubKey    = common.PublicKeyFromString(params.Sender)
@@ -137,6 +141,28 @@ func TransferSOL(params TransferSOLParams) InstructionFunc {
 	}
 }
 
+// checkRentExemption checks that the recipient system account remains rent-exempt after receiving the amount.
+func checkRentExemption(ctx context.Context, c SolanaClient, recipient string, amount uint64) error {
+	minBalance, err := c.GetMinimumBalanceForRentExemption(ctx, 0)
+	if err != nil {
+		return err
+	}
+	if amount >= minBalance {
+		return nil
+	}
+
+	balance, err := c.GetSOLBalance(ctx, recipient)
+	if err != nil {
+		return err
+	}
+	if balance.Amount+amount < minBalance {
+		return fmt.Errorf("%w: at least %d lamports required, got %d",
+			ErrBelowRentExemption, minBalance-balance.Amount, amount)
+	}
+
+	return nil
+}
+
 // TransferTokenParam defines the parameters for transferring tokens.
 type TransferTokenParam struct {
 	Sender    string // required; base58 encoded public key of the sender. Must be a signer.
@@ -144,6 +170,11 @@ type TransferTokenParam struct {
 	Mint      string // required; base58 encoded public key of the mint of the token to send.
 	Reference string // optional; base58 encoded public key to use as a reference for the transaction.
 	Amount    uint64 // required; the amount of tokens to send (in token minimal units), e.g. 1 USDT = 1000000 (10^6) lamports.
+	Delegate  string // optional; base58 encoded public key of the delegate approved to spend the sender tokens. Must be a signer if set, instead of the sender.
+
+	// MultisigSigners are the base58 encoded public keys of the signers of the SPL multisig sender account.
+	// Optional; if set, the sender is the multisig account and the signers must sign instead of the sender.
+	MultisigSigners []string
 }
 
 // Validate validates the parameters.
@@ -163,6 +194,9 @@ func (p TransferTokenParam) Validate() error {
 	if p.Amount <= 0 {
 		return ErrMustBeGreaterThanZero
 	}
+	if p.Delegate != "" && len(p.MultisigSigners) > 0 {
+		return ErrDelegateAndMultisigAreExclusive
+	}
 	return nil
 }
 
@@ -170,6 +204,9 @@ func (p TransferTokenParam) Validate() error {
 // Note: This function does not check if the sender has enough tokens to send. It is the responsibility
 // of the caller to check this.
 // FeePayer must be provided if Sender is not set.
+// If Delegate is set, it signs the transfer instead of the sender and funds the recipient token account.
+// If MultisigSigners are set, they sign the transfer instead of the sender
+// and the first of them funds the recipient token account.
 func TransferToken(params TransferTokenParam) InstructionFunc {
 	return func(ctx context.Context, c SolanaClient) ([]types.Instruction, error) {
 		if err := params.Validate(); err != nil {
@@ -180,7 +217,20 @@ func TransferToken(params TransferTokenParam) InstructionFunc {
 			senderPubKey    = common.PublicKeyFromString(params.Sender)
 			recipientPubKey = common.PublicKeyFromString(params.Recipient)
 			mintPubKey      = common.PublicKeyFromString(params.Mint)
+			authPubKey      = senderPubKey
+			funderPubKey    = senderPubKey
+			signers         []common.PublicKey
 		)
+		if params.Delegate != "" {
+			authPubKey = common.PublicKeyFromString(params.Delegate)
+			funderPubKey = authPubKey
+		}
+		for _, signer := range params.MultisigSigners {
+			signers = append(signers, common.PublicKeyFromString(signer))
+		}
+		if len(signers) > 0 {
+			funderPubKey = signers[0]
+		}
 		senderAta, _, err := common.FindAssociatedTokenAddress(senderPubKey, mintPubKey)
 		if err != nil {
 			return nil, fmt.Errorf("failed to find associated token address for sender wallet: %w", err)
@@ -196,7 +246,7 @@ func TransferToken(params TransferTokenParam) InstructionFunc {
 			instructions = append(instructions,
 				associated_token_account.CreateAssociatedTokenAccount(
 					associated_token_account.CreateAssociatedTokenAccountParam{
-						Funder:                 senderPubKey,
+						Funder:                 funderPubKey,
 						Owner:                  recipientPubKey,
 						Mint:                   mintPubKey,
 						AssociatedTokenAccount: recipientAta,
@@ -206,10 +256,11 @@ func TransferToken(params TransferTokenParam) InstructionFunc {
 		}
 
 		instruction := token.Transfer(token.TransferParam{
-			From:   senderAta,
-			To:     recipientAta,
-			Auth:   senderPubKey,
-			Amount: params.Amount,
+			From:    senderAta,
+			To:      recipientAta,
+			Auth:    authPubKey,
+			Signers: signers,
+			Amount:  params.Amount,
 		})
 
 		if params.Reference != "" {
@@ -226,16 +277,111 @@ func TransferToken(params TransferTokenParam) InstructionFunc {
 	}
 }
 
+// ApproveTokenDelegateParams are the parameters for the ApproveTokenDelegate instruction.
+type ApproveTokenDelegateParams struct {
+	Owner    string // base58 encoded public key of the token account owner. Must be a signer.
+	Mint     string // base58 encoded public key of the mint
+	Delegate string // base58 encoded public key of the delegate allowed to spend the owner tokens
+	Amount   uint64 // allowance in the token minimal units
+}
+
+// Validate checks that the required fields of the params are set.
+func (p ApproveTokenDelegateParams) Validate() error {
+	if p.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if p.Mint == "" {
+		return ErrMintIsRequired
+	}
+	if p.Delegate == "" {
+		return fmt.Errorf("delegate is required")
+	}
+	if p.Owner == p.Delegate {
+		return fmt.Errorf("owner and delegate must be different")
+	}
+	if p.Amount == 0 {
+		return ErrMustBeGreaterThanZero
+	}
+	return nil
+}
+
+// ApproveTokenDelegate allows the delegate to transfer up to the amount of tokens
+// from the associated token account of the owner. It replaces the previous allowance, if any.
+func ApproveTokenDelegate(params ApproveTokenDelegateParams) InstructionFunc {
+	return func(ctx context.Context, c SolanaClient) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to validate params: %w", err)
+		}
+
+		ownerPubKey := common.PublicKeyFromString(params.Owner)
+		ata, _, err := common.FindAssociatedTokenAddress(ownerPubKey, common.PublicKeyFromString(params.Mint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find associated token address: %w", err)
+		}
+
+		return []types.Instruction{
+			token.Approve(token.ApproveParam{
+				From:    ata,
+				To:      common.PublicKeyFromString(params.Delegate),
+				Auth:    ownerPubKey,
+				Signers: []common.PublicKey{},
+				Amount:  params.Amount,
+			}),
+		}, nil
+	}
+}
+
+// RevokeTokenDelegateParams are the parameters for the RevokeTokenDelegate instruction.
+type RevokeTokenDelegateParams struct {
+	Owner string // base58 encoded public key of the token account owner. Must be a signer.
+	Mint  string // base58 encoded public key of the mint
+}
+
+// Validate checks that the required fields of the params are set.
+func (p RevokeTokenDelegateParams) Validate() error {
+	if p.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if p.Mint == "" {
+		return ErrMintIsRequired
+	}
+	return nil
+}
+
+// RevokeTokenDelegate revokes the allowance of the delegate of the owner associated token account.
+func RevokeTokenDelegate(params RevokeTokenDelegateParams) InstructionFunc {
+	return func(ctx context.Context, c SolanaClient) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to validate params: %w", err)
+		}
+
+		ownerPubKey := common.PublicKeyFromString(params.Owner)
+		ata, _, err := common.FindAssociatedTokenAddress(ownerPubKey, common.PublicKeyFromString(params.Mint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find associated token address: %w", err)
+		}
+
+		return []types.Instruction{
+			token.Revoke(token.RevokeParam{
+				From:    ata,
+				Auth:    ownerPubKey,
+				Signers: []common.PublicKey{},
+			}),
+		}, nil
+	}
+}
+
 // CreateFungibleTokenParam defines the parameters for the CreateFungibleToken instruction.
 type CreateFungibleTokenParam struct {
 	Mint     string // required; The token mint public key.
 	Owner    string // required; The owner of the token.
 	FeePayer string // required; The wallet to pay the fees from.
 
-	Decimals    uint8  // optional; The number of decimals the token has. Default is 0.
-	MetadataURI string // optional; URI of the token metadata; can be set later
-	TokenName   string // optional; Name of the token; used for the token metadata if MetadataURI is not set.
-	TokenSymbol string // optional; Symbol of the token; used for the token metadata if MetadataURI is not set.
+	Decimals        uint8  // optional; The number of decimals the token has. Default is 0.
+	MetadataURI     string // optional; URI of the token metadata; can be set later
+	TokenName       string // optional; Name of the token; used for the token metadata if MetadataURI is not set.
+	TokenSymbol     string // optional; Symbol of the token; used for the token metadata if MetadataURI is not set.
+	FreezeAuthority string // optional; The freeze authority of the token. Default is the owner.
 }
 
 // Validate checks that the required fields of the params are set.
@@ -278,7 +424,11 @@ func CreateFungibleToken(params CreateFungibleTokenParam) InstructionFunc {
 			mintPubKey  = common.PublicKeyFromString(params.Mint)
 			ownerPubKey = common.PublicKeyFromString(params.Owner)
 			feePayer    = common.PublicKeyFromString(params.FeePayer)
+			freezeAuth  = ownerPubKey
 		)
+		if params.FreezeAuthority != "" {
+			freezeAuth = common.PublicKeyFromString(params.FreezeAuthority)
+		}
 
 		metaPubkey, err := token_metadata.GetTokenMetaPubkey(mintPubKey)
 		if err != nil {
@@ -328,7 +478,7 @@ func CreateFungibleToken(params CreateFungibleTokenParam) InstructionFunc {
 				Decimals:   params.Decimals,
 				Mint:       mintPubKey,
 				MintAuth:   ownerPubKey,
-				FreezeAuth: utils.Pointer(ownerPubKey),
+				FreezeAuth: utils.Pointer(freezeAuth),
 			}),
 			token_metadata.CreateMetadataAccountV2(token_metadata.CreateMetadataAccountV2Param{
 				Metadata:                metaPubkey,
@@ -407,6 +557,136 @@ func UpdateFungibleMetadata(params UpdateFungibleMetadataParams) InstructionFunc
 	}
 }
 
+// CreateNonFungibleTokenParam defines the parameters for the CreateNonFungibleToken instruction.
+type CreateNonFungibleTokenParam struct {
+	Mint        string // required; The new token mint public key, must be a signer.
+	Owner       string // required; The mint and update authority of the token, must be a signer.
+	FeePayer    string // required; The wallet to pay the fees from.
+	Recipient   string // required; The wallet to mint the token to.
+	Name        string // required; Name of the token, up to 32 characters.
+	Symbol      string // required; Symbol of the token, up to 10 characters.
+	MetadataURI string // required; URI of the token metadata json.
+}
+
+// Validate checks that the required fields of the params are set.
+func (p CreateNonFungibleTokenParam) Validate() error {
+	if p.Mint == "" {
+		return fmt.Errorf("mint address is required")
+	}
+	if p.Owner == "" {
+		return fmt.Errorf("owner public key is required")
+	}
+	if p.FeePayer == "" {
+		return fmt.Errorf("invalid fee payer public key")
+	}
+	if p.Recipient == "" {
+		return fmt.Errorf("recipient public key is required")
+	}
+	if p.Name == "" || len(p.Name) > 32 {
+		return fmt.Errorf("token name must be between 1 and 32 characters")
+	}
+	if p.Symbol == "" || len(p.Symbol) > 10 {
+		return fmt.Errorf("token symbol must be between 1 and 10 characters")
+	}
+	if !strings.HasPrefix(p.MetadataURI, "http://") && !strings.HasPrefix(p.MetadataURI, "https://") {
+		return fmt.Errorf("field MetadataURI must be a valid URI")
+	}
+	return nil
+}
+
+// CreateNonFungibleToken creates instructions for minting a Metaplex NFT to the recipient:
+// the mint account with 0 decimals, the metadata account, the single token and the master edition
+// with zero max supply, so no more tokens or prints can be minted.
+// Unlike CreateFungibleToken, the metadata is not fetched from the URI, so the just uploaded metadata can be used.
+func CreateNonFungibleToken(params CreateNonFungibleTokenParam) InstructionFunc {
+	return func(ctx context.Context, c SolanaClient) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid params: %w", err)
+		}
+
+		var (
+			mintPubKey      = common.PublicKeyFromString(params.Mint)
+			ownerPubKey     = common.PublicKeyFromString(params.Owner)
+			feePayer        = common.PublicKeyFromString(params.FeePayer)
+			recipientPubKey = common.PublicKeyFromString(params.Recipient)
+		)
+
+		metaPubkey, err := token_metadata.GetTokenMetaPubkey(mintPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get token metadata pubkey: %w", err)
+		}
+		editionPubkey, err := token_metadata.GetMasterEdition(mintPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get master edition pubkey: %w", err)
+		}
+		recipientAta, _, err := common.FindAssociatedTokenAddress(recipientPubKey, mintPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find associated token address: %w", err)
+		}
+
+		rentExemption, err := c.GetMinimumBalanceForRentExemption(ctx, token.MintAccountSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get minimum balance for rent exemption: %w", err)
+		}
+
+		instructions := []types.Instruction{
+			system.CreateAccount(system.CreateAccountParam{
+				From:     feePayer,
+				New:      mintPubKey,
+				Owner:    common.TokenProgramID,
+				Lamports: rentExemption,
+				Space:    token.MintAccountSize,
+			}),
+			token.InitializeMint2(token.InitializeMint2Param{
+				Decimals:   0,
+				Mint:       mintPubKey,
+				MintAuth:   ownerPubKey,
+				FreezeAuth: utils.Pointer(ownerPubKey),
+			}),
+			token_metadata.CreateMetadataAccountV2(token_metadata.CreateMetadataAccountV2Param{
+				Metadata:                metaPubkey,
+				Mint:                    mintPubKey,
+				MintAuthority:           ownerPubKey,
+				Payer:                   feePayer,
+				UpdateAuthority:         ownerPubKey,
+				UpdateAuthorityIsSigner: true,
+				IsMutable:               true,
+				Data: token_metadata.DataV2{
+					Name:   params.Name,
+					Symbol: params.Symbol,
+					Uri:    params.MetadataURI,
+				},
+			}),
+			associated_token_account.CreateAssociatedTokenAccount(
+				associated_token_account.CreateAssociatedTokenAccountParam{
+					Funder:                 feePayer,
+					Owner:                  recipientPubKey,
+					Mint:                   mintPubKey,
+					AssociatedTokenAccount: recipientAta,
+				},
+			),
+			token.MintTo(token.MintToParam{
+				Mint:    mintPubKey,
+				To:      recipientAta,
+				Auth:    ownerPubKey,
+				Signers: []common.PublicKey{},
+				Amount:  1,
+			}),
+			token_metadata.CreateMasterEditionV3(token_metadata.CreateMasterEditionParam{
+				Edition:         editionPubkey,
+				Mint:            mintPubKey,
+				UpdateAuthority: ownerPubKey,
+				MintAuthority:   ownerPubKey,
+				Metadata:        metaPubkey,
+				Payer:           feePayer,
+				MaxSupply:       utils.Pointer(uint64(0)),
+			}),
+		}
+
+		return instructions, nil
+	}
+}
+
 // MintFungibleTokenParams is the params for MintFungibleToken
 type MintFungibleTokenParams struct {
 	Funder    string // base58 encoded public key of the account that will fund the associated token account. Must be a signer.
@@ -488,6 +768,7 @@ func MintFungibleToken(params MintFungibleTokenParams) InstructionFunc {
 type BurnTokenParams struct {
 	Mint              string // base58 encoded public key of the mint
 	TokenAccountOwner string // base58 encoded public key of the token account owner
+	Reference         string // optional; base58 encoded public key to use as a reference for the transaction.
 	Amount            uint64
 }
 
@@ -522,15 +803,63 @@ func BurnToken(params BurnTokenParams) InstructionFunc {
 			return nil, fmt.Errorf("failed to find associated token address: %w", err)
 		}
 
-		return []types.Instruction{
-			token.Burn(token.BurnParam{
-				Account: ata,
-				Mint:    mintPubKey,
-				Auth:    ataOwnerPubKey,
-				Amount:  params.Amount,
-			}),
-		}, nil
+		instruction := token.Burn(token.BurnParam{
+			Account: ata,
+			Mint:    mintPubKey,
+			Auth:    ataOwnerPubKey,
+			Amount:  params.Amount,
+		})
+
+		return []types.Instruction{withReference(instruction, params.Reference)}, nil
+	}
+}
+
+// BurnTokenCheckedParams are the parameters for the BurnTokenChecked instruction.
+type BurnTokenCheckedParams struct {
+	BurnTokenParams
+	Decimals uint8 // decimals of the mint; the burn fails if they don't match the mint.
+}
+
+// BurnTokenChecked burns the specified token, asserting the mint decimals,
+// so the amount in the wrong units can't be burned by mistake.
+func BurnTokenChecked(params BurnTokenCheckedParams) InstructionFunc {
+	return func(ctx context.Context, c SolanaClient) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to validate params: %w", err)
+		}
+
+		var (
+			mintPubKey     = common.PublicKeyFromString(params.Mint)
+			ataOwnerPubKey = common.PublicKeyFromString(params.TokenAccountOwner)
+		)
+
+		ata, _, err := common.FindAssociatedTokenAddress(ataOwnerPubKey, mintPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find associated token address: %w", err)
+		}
+
+		instruction := token.BurnChecked(token.BurnCheckedParam{
+			Account:  ata,
+			Mint:     mintPubKey,
+			Auth:     ataOwnerPubKey,
+			Amount:   params.Amount,
+			Decimals: params.Decimals,
+		})
+
+		return []types.Instruction{withReference(instruction, params.Reference)}, nil
+	}
+}
+
+// withReference appends the reference account to the instruction accounts, if it's set.
+func withReference(instruction types.Instruction, reference string) types.Instruction {
+	if reference != "" {
+		instruction.Accounts = append(instruction.Accounts, types.AccountMeta{
+			PubKey:     common.PublicKeyFromString(reference),
+			IsSigner:   false,
+			IsWritable: false,
+		})
 	}
+	return instruction
 }
 
 // CloseTokenAccountParams are the parameters for the CloseTokenAccount instruction.
@@ -590,3 +919,81 @@ func CloseTokenAccount(params CloseTokenAccountParams) InstructionFunc {
 		}, nil
 	}
 }
+
+// FreezeTokenAccountParams are the parameters for the FreezeTokenAccount and ThawTokenAccount instructions.
+type FreezeTokenAccountParams struct {
+	Mint      string // base58 encoded public key of the mint
+	Owner     string // base58 encoded public key of the owner of the associated token account
+	Authority string // base58 encoded public key of the mint freeze authority. Must be a signer.
+}
+
+// Validate checks that the required fields of the params are set.
+func (p FreezeTokenAccountParams) Validate() error {
+	if p.Mint == "" {
+		return fmt.Errorf("mint is required")
+	}
+	if p.Owner == "" {
+		return fmt.Errorf("owner is required")
+	}
+	if p.Authority == "" {
+		return fmt.Errorf("freeze authority is required")
+	}
+	return nil
+}
+
+// FreezeTokenAccount freezes the associated token account of the owner for the given mint.
+// A frozen account can't send or receive tokens until it's thawed.
+func FreezeTokenAccount(params FreezeTokenAccountParams) InstructionFunc {
+	return func(ctx context.Context, c SolanaClient) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to validate params: %w", err)
+		}
+
+		ata, mintPubKey, err := freezableTokenAccount(params)
+		if err != nil {
+			return nil, err
+		}
+
+		return []types.Instruction{
+			token.FreezeAccount(token.FreezeAccountParam{
+				Account: ata,
+				Mint:    mintPubKey,
+				Auth:    common.PublicKeyFromString(params.Authority),
+				Signers: []common.PublicKey{},
+			}),
+		}, nil
+	}
+}
+
+// ThawTokenAccount thaws the frozen associated token account of the owner for the given mint.
+func ThawTokenAccount(params FreezeTokenAccountParams) InstructionFunc {
+	return func(ctx context.Context, c SolanaClient) ([]types.Instruction, error) {
+		if err := params.Validate(); err != nil {
+			return nil, fmt.Errorf("failed to validate params: %w", err)
+		}
+
+		ata, mintPubKey, err := freezableTokenAccount(params)
+		if err != nil {
+			return nil, err
+		}
+
+		return []types.Instruction{
+			token.ThawAccount(token.ThawAccountParam{
+				Account: ata,
+				Mint:    mintPubKey,
+				Auth:    common.PublicKeyFromString(params.Authority),
+				Signers: []common.PublicKey{},
+			}),
+		}, nil
+	}
+}
+
+// freezableTokenAccount returns the associated token account and the mint public keys for the given params.
+func freezableTokenAccount(params FreezeTokenAccountParams) (common.PublicKey, common.PublicKey, error) {
+	mintPubKey := common.PublicKeyFromString(params.Mint)
+	ata, _, err := common.FindAssociatedTokenAddress(common.PublicKeyFromString(params.Owner), mintPubKey)
+	if err != nil {
+		return common.PublicKey{}, common.PublicKey{}, fmt.Errorf("failed to find associated token address: %w", err)
+	}
+	return ata, mintPubKey, nil
+}
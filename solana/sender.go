@@ -0,0 +1,199 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/utils"
+)
+
+// Default sender settings.
+const (
+	DefaultSendAttempts   = 5
+	DefaultSendRetryDelay = 2 * time.Second
+)
+
+// RPC errors of the transaction submission, matched by the message,
+// since the SDK returns them as the plain JSON-RPC errors.
+var (
+	blockhashExpiredErrors = []string{
+		"blockhash not found",
+		"blockhashnotfound",
+		"block height exceeded",
+	}
+	nodeBehindErrors = []string{
+		"node is behind",
+		"node is unhealthy",
+		"nodeunhealthy",
+		"minimum context slot has not been reached",
+	}
+	alreadyProcessedErrors = []string{
+		"already been processed",
+		"alreadyprocessed",
+	}
+)
+
+type (
+	// Sender submits the server-signed transactions, e.g. refunds, sweeps and escrow releases.
+	// It retries the submission while the RPC node is behind or unavailable, and rebuilds
+	// the transaction with a fresh blockhash if the blockhash is expired. The transaction is
+	// never rebuilt if any of its previous signatures has landed, so the funds are not moved twice.
+	Sender struct {
+		client      transactionSender
+		maxAttempts int
+		retryDelay  time.Duration
+	}
+
+	// SenderOption is a function that configures the sender.
+	SenderOption func(*Sender)
+
+	// BuildFunc builds a signed base64 encoded transaction with the latest blockhash,
+	// e.g. TransactionBuilder.Build.
+	BuildFunc func(ctx context.Context) (string, error)
+
+	transactionSender interface {
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+		GetTransactionStatus(ctx context.Context, txhash string, commitment Commitment) (TransactionStatus, error)
+	}
+)
+
+// NewSender creates a new transaction sender.
+func NewSender(client transactionSender, opts ...SenderOption) *Sender {
+	s := &Sender{
+		client:      client,
+		maxAttempts: DefaultSendAttempts,
+		retryDelay:  DefaultSendRetryDelay,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithSendAttempts sets the max number of the submission attempts, including the rebuilt transactions.
+func WithSendAttempts(n int) SenderOption {
+	return func(s *Sender) {
+		if n > 0 {
+			s.maxAttempts = n
+		}
+	}
+}
+
+// WithSendRetryDelay sets the delay before the second attempt, it grows linearly with the attempts.
+func WithSendRetryDelay(d time.Duration) SenderOption {
+	return func(s *Sender) {
+		if d >= 0 {
+			s.retryDelay = d
+		}
+	}
+}
+
+// Send builds and submits the transaction. Returns the signature of the submitted transaction.
+func (s *Sender) Send(ctx context.Context, build BuildFunc) (string, error) {
+	var (
+		tx   string
+		sent []string // signatures of the submitted transactions, the latest last
+	)
+
+	for attempt := 1; ; attempt++ {
+		if tx == "" {
+			// the previous transaction may have landed despite the error,
+			// rebuilding it would move the funds twice
+			if signature := s.landed(ctx, sent); signature != "" {
+				return signature, nil
+			}
+
+			var err error
+			if tx, err = build(ctx); err != nil {
+				return "", fmt.Errorf("failed to build transaction: %w", err)
+			}
+		}
+
+		signature, err := s.client.SendTransaction(ctx, tx)
+		if err == nil {
+			return signature, nil
+		}
+
+		if signature, sigErr := TransactionSignature(tx); sigErr == nil {
+			if matchError(err, alreadyProcessedErrors) {
+				return signature, nil
+			}
+			if len(sent) == 0 || sent[len(sent)-1] != signature {
+				sent = append(sent, signature)
+			}
+		}
+
+		if attempt >= s.maxAttempts {
+			return "", err
+		}
+
+		switch {
+		case matchError(err, blockhashExpiredErrors):
+			tx = "" // rebuild with a fresh blockhash
+		case matchError(err, nodeBehindErrors), errors.Is(err, ErrChainUnavailable):
+			// resend the same transaction, the network deduplicates it by the signature
+		default:
+			return "", err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-time.After(s.retryDelay * time.Duration(attempt)):
+		}
+	}
+}
+
+// SendSigned submits the transaction signed outside of the service, e.g. by the multisig signers.
+// It can't be rebuilt, so ErrBlockhashExpired is returned once its blockhash is expired.
+func (s *Sender) SendSigned(ctx context.Context, tx string) (string, error) {
+	built := false
+	return s.Send(ctx, func(context.Context) (string, error) {
+		if built {
+			return "", ErrBlockhashExpired
+		}
+		built = true
+		return tx, nil
+	})
+}
+
+// landed returns the signature of the submitted transaction which is processed by the network, if any.
+func (s *Sender) landed(ctx context.Context, signatures []string) string {
+	for _, signature := range signatures {
+		status, err := s.client.GetTransactionStatus(ctx, signature, CommitmentProcessed)
+		if err == nil && (status == TransactionStatusSuccess || status == TransactionStatusInProgress) {
+			return signature
+		}
+	}
+	return ""
+}
+
+// TransactionSignature returns the base58 encoded signature of the fee payer,
+// which identifies the base64 encoded transaction on the network.
+func TransactionSignature(base64Tx string) (string, error) {
+	tx, err := DecodeTransaction(base64Tx)
+	if err != nil {
+		return "", err
+	}
+	if len(tx.Signatures) == 0 || isEmptySignature(tx.Signatures[0]) {
+		return "", ErrInvalidSignature
+	}
+
+	return utils.BytesToBase58(tx.Signatures[0]), nil
+}
+
+// matchError returns true if the error message contains any of the given lowercase substrings.
+func matchError(err error, substrings []string) bool {
+	msg := strings.ToLower(err.Error())
+	for _, s := range substrings {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
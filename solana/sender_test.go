@@ -0,0 +1,169 @@
+package solana_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/portto/solana-go-sdk/program/system"
+	"github.com/portto/solana-go-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSender returns the scripted errors of the SendTransaction calls in order,
+// then the signatures of the sent transactions.
+type fakeSender struct {
+	errs     []error
+	landed   map[string]bool
+	sent     []string
+	statuses []string
+}
+
+func (f *fakeSender) SendTransaction(ctx context.Context, txSource string) (string, error) {
+	f.sent = append(f.sent, txSource)
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return "", err
+	}
+	return solana.TransactionSignature(txSource)
+}
+
+func (f *fakeSender) GetTransactionStatus(ctx context.Context, txhash string, commitment solana.Commitment) (solana.TransactionStatus, error) {
+	f.statuses = append(f.statuses, txhash)
+	if f.landed[txhash] {
+		return solana.TransactionStatusInProgress, nil
+	}
+	return solana.TransactionStatusUnknown, nil
+}
+
+// newTransferBuilder returns a build function which signs a new transfer with a distinct blockhash on each call.
+func newTransferBuilder(t *testing.T, feePayer types.Account) (solana.BuildFunc, *[]string) {
+	t.Helper()
+
+	var built []string
+	return func(ctx context.Context) (string, error) {
+		tx, err := types.NewTransaction(types.NewTransactionParam{
+			Message: types.NewMessage(types.NewMessageParam{
+				FeePayer:        feePayer.PublicKey,
+				RecentBlockhash: types.NewAccount().PublicKey.ToBase58(),
+				Instructions: []types.Instruction{
+					system.Transfer(system.TransferParam{
+						From:   feePayer.PublicKey,
+						To:     types.NewAccount().PublicKey,
+						Amount: 1000,
+					}),
+				},
+			}),
+			Signers: []types.Account{feePayer},
+		})
+		require.NoError(t, err)
+
+		result, err := solana.EncodeTransaction(tx)
+		require.NoError(t, err)
+
+		built = append(built, result)
+		return result, nil
+	}, &built
+}
+
+func TestSender(t *testing.T) {
+	ctx := context.Background()
+	feePayer := types.NewAccount()
+	blockhashExpired := errors.New("rpc response error: {\"code\":-32002,\"message\":\"Transaction simulation failed: Blockhash not found\"}")
+	nodeBehind := errors.New("rpc response error: {\"code\":-32005,\"message\":\"Node is behind by 42 slots\"}")
+
+	t.Run("resend the same transaction while the node is behind", func(t *testing.T) {
+		client := &fakeSender{errs: []error{nodeBehind, solana.ErrChainUnavailable}}
+		build, built := newTransferBuilder(t, feePayer)
+
+		signature, err := solana.NewSender(client, solana.WithSendRetryDelay(0)).Send(ctx, build)
+		require.NoError(t, err)
+		require.Len(t, *built, 1)
+		require.Equal(t, []string{(*built)[0], (*built)[0], (*built)[0]}, client.sent)
+
+		expected, err := solana.TransactionSignature((*built)[0])
+		require.NoError(t, err)
+		require.Equal(t, expected, signature)
+	})
+
+	t.Run("rebuild the transaction with a fresh blockhash", func(t *testing.T) {
+		client := &fakeSender{errs: []error{blockhashExpired}}
+		build, built := newTransferBuilder(t, feePayer)
+
+		signature, err := solana.NewSender(client, solana.WithSendRetryDelay(0)).Send(ctx, build)
+		require.NoError(t, err)
+		require.Len(t, *built, 2)
+		require.Len(t, client.statuses, 1)
+
+		expected, err := solana.TransactionSignature((*built)[1])
+		require.NoError(t, err)
+		require.Equal(t, expected, signature)
+	})
+
+	t.Run("don't rebuild the landed transaction", func(t *testing.T) {
+		client := &fakeSender{errs: []error{nodeBehind, blockhashExpired}, landed: make(map[string]bool)}
+		build, built := newTransferBuilder(t, feePayer)
+		build = func(build solana.BuildFunc) solana.BuildFunc {
+			return func(ctx context.Context) (string, error) {
+				tx, err := build(ctx)
+				if err == nil {
+					signature, _ := solana.TransactionSignature(tx)
+					client.landed[signature] = true
+				}
+				return tx, err
+			}
+		}(build)
+
+		signature, err := solana.NewSender(client, solana.WithSendRetryDelay(0)).Send(ctx, build)
+		require.NoError(t, err)
+		require.Len(t, *built, 1)
+
+		expected, err := solana.TransactionSignature((*built)[0])
+		require.NoError(t, err)
+		require.Equal(t, expected, signature)
+	})
+
+	t.Run("already processed transaction", func(t *testing.T) {
+		client := &fakeSender{errs: []error{nodeBehind, errors.New("Transaction simulation failed: This transaction has already been processed")}}
+		build, built := newTransferBuilder(t, feePayer)
+
+		signature, err := solana.NewSender(client, solana.WithSendRetryDelay(0)).Send(ctx, build)
+		require.NoError(t, err)
+
+		expected, err := solana.TransactionSignature((*built)[0])
+		require.NoError(t, err)
+		require.Equal(t, expected, signature)
+	})
+
+	t.Run("give up after max attempts", func(t *testing.T) {
+		client := &fakeSender{errs: []error{nodeBehind, nodeBehind, nodeBehind}}
+		build, _ := newTransferBuilder(t, feePayer)
+
+		_, err := solana.NewSender(client, solana.WithSendAttempts(2), solana.WithSendRetryDelay(0)).Send(ctx, build)
+		require.ErrorIs(t, err, nodeBehind)
+		require.Len(t, client.sent, 2)
+	})
+
+	t.Run("don't retry other errors", func(t *testing.T) {
+		insufficientFunds := errors.New("Transaction simulation failed: insufficient funds for fee")
+		client := &fakeSender{errs: []error{insufficientFunds}}
+		build, _ := newTransferBuilder(t, feePayer)
+
+		_, err := solana.NewSender(client, solana.WithSendRetryDelay(0)).Send(ctx, build)
+		require.ErrorIs(t, err, insufficientFunds)
+		require.Len(t, client.sent, 1)
+	})
+
+	t.Run("signed transaction is not rebuilt", func(t *testing.T) {
+		client := &fakeSender{errs: []error{blockhashExpired}}
+		build, _ := newTransferBuilder(t, feePayer)
+		tx, err := build(ctx)
+		require.NoError(t, err)
+
+		_, err = solana.NewSender(client, solana.WithSendRetryDelay(0)).SendSigned(ctx, tx)
+		require.ErrorIs(t, err, solana.ErrBlockhashExpired)
+		require.Len(t, client.sent, 1)
+	})
+}
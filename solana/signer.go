@@ -0,0 +1,51 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/portto/solana-go-sdk/common"
+	"github.com/portto/solana-go-sdk/types"
+)
+
+type (
+	// Signer is an interface for any account that can sign a transaction message,
+	// without exposing its private key, e.g. a local key, AWS KMS or GCP KMS key.
+	Signer interface {
+		// PublicKey returns the public key of the signer.
+		PublicKey() common.PublicKey
+		// Sign signs the given serialized transaction message.
+		// Returns the ed25519 signature or an error.
+		Sign(ctx context.Context, message []byte) ([]byte, error)
+	}
+
+	// LocalSigner is a signer that keeps the private key in memory.
+	LocalSigner struct {
+		account types.Account
+	}
+)
+
+// NewLocalSigner creates a new signer from the given account.
+func NewLocalSigner(account types.Account) *LocalSigner {
+	return &LocalSigner{account: account}
+}
+
+// NewLocalSignerFromBase58 creates a new signer from the given base58 encoded private key.
+func NewLocalSignerFromBase58(base58PrivateKey string) (*LocalSigner, error) {
+	account, err := types.AccountFromBase58(base58PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	return NewLocalSigner(account), nil
+}
+
+// PublicKey returns the public key of the signer.
+func (s *LocalSigner) PublicKey() common.PublicKey {
+	return s.account.PublicKey
+}
+
+// Sign signs the given message.
+func (s *LocalSigner) Sign(_ context.Context, message []byte) ([]byte, error) {
+	return s.account.Sign(message), nil
+}
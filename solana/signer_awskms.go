@@ -0,0 +1,183 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/awssig"
+	"github.com/portto/solana-go-sdk/common"
+)
+
+type (
+	// AWSKMSSigner is a signer backed by an AWS KMS asymmetric key with the
+	// ECC_NIST_EDWARDS25519 key spec. The private key never leaves KMS.
+	AWSKMSSigner struct {
+		client    *http.Client
+		endpoint  string
+		region    string
+		keyID     string
+		creds     awssig.Credentials
+		publicKey common.PublicKey
+	}
+
+	// AWSKMSSignerOption is a function that configures the AWSKMSSigner.
+	AWSKMSSignerOption func(*AWSKMSSigner)
+)
+
+// NewAWSKMSSigner creates a new AWS KMS signer for the given key ID or ARN.
+// It fetches the public key of the KMS key, so the key must be accessible at startup.
+func NewAWSKMSSigner(ctx context.Context, keyID, region string, creds awssig.Credentials, opts ...AWSKMSSignerOption) (*AWSKMSSigner, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("aws kms key id is required")
+	}
+	if region == "" {
+		return nil, fmt.Errorf("aws region is required")
+	}
+
+	s := &AWSKMSSigner{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: fmt.Sprintf("https://kms.%s.amazonaws.com/", region),
+		region:   region,
+		keyID:    keyID,
+		creds:    creds,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.loadPublicKey(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// WithAWSKMSEndpoint sets a custom AWS KMS endpoint, e.g. for VPC endpoints or local testing.
+func WithAWSKMSEndpoint(endpoint string) AWSKMSSignerOption {
+	return func(s *AWSKMSSigner) {
+		s.endpoint = endpoint
+	}
+}
+
+// WithAWSKMSHTTPClient sets a custom HTTP client.
+func WithAWSKMSHTTPClient(client *http.Client) AWSKMSSignerOption {
+	return func(s *AWSKMSSigner) {
+		s.client = client
+	}
+}
+
+// PublicKey returns the public key of the KMS key.
+func (s *AWSKMSSigner) PublicKey() common.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the given message with the KMS key.
+func (s *AWSKMSSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	var resp struct {
+		Signature string `json:"Signature"`
+	}
+	if err := s.call(ctx, "TrentService.Sign", map[string]interface{}{
+		"KeyId":            s.keyID,
+		"Message":          base64.StdEncoding.EncodeToString(message),
+		"MessageType":      "RAW",
+		"SigningAlgorithm": "ED25519_SHA_512",
+	}, &resp); err != nil {
+		return nil, fmt.Errorf("aws kms: sign: %w", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("aws kms: sign: decode signature: %w", err)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("aws kms: sign: %w", ErrInvalidSignature)
+	}
+
+	return sig, nil
+}
+
+// loadPublicKey fetches the public key of the KMS key.
+func (s *AWSKMSSigner) loadPublicKey(ctx context.Context) error {
+	var resp struct {
+		PublicKey string `json:"PublicKey"`
+		KeySpec   string `json:"KeySpec"`
+	}
+	if err := s.call(ctx, "TrentService.GetPublicKey", map[string]interface{}{
+		"KeyId": s.keyID,
+	}, &resp); err != nil {
+		return fmt.Errorf("aws kms: get public key: %w", err)
+	}
+
+	der, err := base64.StdEncoding.DecodeString(resp.PublicKey)
+	if err != nil {
+		return fmt.Errorf("aws kms: get public key: decode: %w", err)
+	}
+
+	pubKey, err := parseEd25519PublicKey(der)
+	if err != nil {
+		return fmt.Errorf("aws kms: get public key: %w", err)
+	}
+	s.publicKey = pubKey
+
+	return nil
+}
+
+// call makes a signed request to the AWS KMS JSON API.
+func (s *AWSKMSSigner) call(ctx context.Context, target string, payload, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+
+	if err := awssig.SignRequest(req, body, s.creds, s.region, "kms", time.Now()); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, string(msg))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// parseEd25519PublicKey parses DER encoded SubjectPublicKeyInfo with an ed25519 key.
+func parseEd25519PublicKey(der []byte) (common.PublicKey, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return common.PublicKey{}, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return common.PublicKey{}, ErrUnsupportedKeyType
+	}
+
+	return common.PublicKeyFromBytes(edKey), nil
+}
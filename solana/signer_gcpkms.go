@@ -0,0 +1,232 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/portto/solana-go-sdk/common"
+)
+
+// GCP metadata server endpoint to get an access token of the default service account.
+const gcpMetadataTokenURI = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+type (
+	// GCPKMSSigner is a signer backed by a GCP Cloud KMS asymmetric key version
+	// with the EC_SIGN_ED25519 algorithm. The private key never leaves KMS.
+	GCPKMSSigner struct {
+		client      *http.Client
+		endpoint    string
+		keyName     string
+		tokenSource GCPTokenSource
+		publicKey   common.PublicKey
+	}
+
+	// GCPKMSSignerOption is a function that configures the GCPKMSSigner.
+	GCPKMSSignerOption func(*GCPKMSSigner)
+
+	// GCPTokenSource returns an OAuth2 access token to call the GCP Cloud KMS API.
+	GCPTokenSource func(ctx context.Context) (string, error)
+)
+
+// NewGCPKMSSigner creates a new GCP KMS signer for the given key version resource name:
+// projects/{project}/locations/{location}/keyRings/{ring}/cryptoKeys/{key}/cryptoKeyVersions/{version}.
+// By default, it uses the GCP metadata server to get an access token, so it works
+// out of the box on GCE, GKE and Cloud Run.
+// It fetches the public key of the KMS key, so the key must be accessible at startup.
+func NewGCPKMSSigner(ctx context.Context, keyName string, opts ...GCPKMSSignerOption) (*GCPKMSSigner, error) {
+	if keyName == "" {
+		return nil, fmt.Errorf("gcp kms key name is required")
+	}
+
+	s := &GCPKMSSigner{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: "https://cloudkms.googleapis.com/v1/",
+		keyName:  strings.Trim(keyName, "/"),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.tokenSource == nil {
+		s.tokenSource = newGCPMetadataTokenSource(s.client)
+	}
+
+	if err := s.loadPublicKey(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// WithGCPKMSEndpoint sets a custom GCP KMS endpoint, e.g. for private service connect or local testing.
+func WithGCPKMSEndpoint(endpoint string) GCPKMSSignerOption {
+	return func(s *GCPKMSSigner) {
+		s.endpoint = strings.TrimRight(endpoint, "/") + "/"
+	}
+}
+
+// WithGCPKMSHTTPClient sets a custom HTTP client.
+func WithGCPKMSHTTPClient(client *http.Client) GCPKMSSignerOption {
+	return func(s *GCPKMSSigner) {
+		s.client = client
+	}
+}
+
+// WithGCPKMSTokenSource sets a custom access token source.
+func WithGCPKMSTokenSource(ts GCPTokenSource) GCPKMSSignerOption {
+	return func(s *GCPKMSSigner) {
+		s.tokenSource = ts
+	}
+}
+
+// PublicKey returns the public key of the KMS key.
+func (s *GCPKMSSigner) PublicKey() common.PublicKey {
+	return s.publicKey
+}
+
+// Sign signs the given message with the KMS key.
+func (s *GCPKMSSigner) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	if err := s.call(ctx, http.MethodPost, s.keyName+":asymmetricSign", map[string]interface{}{
+		"data": base64.StdEncoding.EncodeToString(message),
+	}, &resp); err != nil {
+		return nil, fmt.Errorf("gcp kms: sign: %w", err)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("gcp kms: sign: decode signature: %w", err)
+	}
+	if len(sig) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("gcp kms: sign: %w", ErrInvalidSignature)
+	}
+
+	return sig, nil
+}
+
+// loadPublicKey fetches the public key of the KMS key.
+func (s *GCPKMSSigner) loadPublicKey(ctx context.Context) error {
+	var resp struct {
+		Pem       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := s.call(ctx, http.MethodGet, s.keyName+"/publicKey", nil, &resp); err != nil {
+		return fmt.Errorf("gcp kms: get public key: %w", err)
+	}
+
+	block, _ := pem.Decode([]byte(resp.Pem))
+	if block == nil {
+		return fmt.Errorf("gcp kms: get public key: failed to decode pem")
+	}
+
+	pubKey, err := parseEd25519PublicKey(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("gcp kms: get public key: %w", err)
+	}
+	s.publicKey = pubKey
+
+	return nil
+}
+
+// call makes an authorized request to the GCP Cloud KMS REST API.
+func (s *GCPKMSSigner) call(ctx context.Context, method, path string, payload, result interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	token, err := s.tokenSource(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, string(msg))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// newGCPMetadataTokenSource returns a token source that gets access tokens
+// from the GCP metadata server and caches them until they expire.
+func newGCPMetadataTokenSource(client *http.Client) GCPTokenSource {
+	var (
+		mu      sync.Mutex
+		token   string
+		expires time.Time
+	)
+
+	return func(ctx context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if token != "" && time.Now().Before(expires) {
+			return token, nil
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, gcpMetadataTokenURI, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to make request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		}
+
+		var result struct {
+			AccessToken string `json:"access_token"`
+			ExpiresIn   int64  `json:"expires_in"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return "", fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		token = result.AccessToken
+		// refresh the token a minute before it expires
+		expires = time.Now().Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+
+		return token, nil
+	}
+}
@@ -0,0 +1,96 @@
+package solana
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Well-known token mints with static metadata, so they never hit the RPC.
+var knownTokens = map[string]FungibleTokenMetadata{
+	NativeMint: {
+		Mint:     NativeMint,
+		Name:     "Wrapped SOL",
+		Symbol:   "SOL",
+		Decimals: NativeMintDecimals,
+	},
+	"EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v": {
+		Mint:     "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v",
+		Name:     "USD Coin",
+		Symbol:   "USDC",
+		Decimals: 6,
+	},
+	"Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB": {
+		Mint:     "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB",
+		Name:     "USDT",
+		Symbol:   "USDT",
+		Decimals: 6,
+	},
+}
+
+type (
+	// TokenMetadataCache is an in-memory cache of the fungible token metadata.
+	TokenMetadataCache struct {
+		getter tokenMetadataGetter
+		ttl    time.Duration
+
+		mu    sync.RWMutex
+		items map[string]cachedTokenMetadata
+	}
+
+	tokenMetadataGetter interface {
+		GetFungibleTokenMetadata(ctx context.Context, base58MintAddr string) (*FungibleTokenMetadata, error)
+	}
+
+	cachedTokenMetadata struct {
+		metadata  FungibleTokenMetadata
+		expiresAt time.Time
+	}
+)
+
+// NewTokenMetadataCache creates a new token metadata cache.
+// The token metadata rarely changes, so the ttl can be quite long, e.g. 24 hours.
+func NewTokenMetadataCache(getter tokenMetadataGetter, ttl time.Duration) *TokenMetadataCache {
+	return &TokenMetadataCache{
+		getter: getter,
+		ttl:    ttl,
+		items:  make(map[string]cachedTokenMetadata),
+	}
+}
+
+// GetFungibleTokenMetadata returns the token metadata from the cache
+// or fetches it with the underlying getter and caches the result.
+// "SOL" is accepted as an alias of the native mint.
+func (c *TokenMetadataCache) GetFungibleTokenMetadata(ctx context.Context, base58MintAddr string) (*FungibleTokenMetadata, error) {
+	if strings.EqualFold(base58MintAddr, "SOL") {
+		base58MintAddr = NativeMint
+	}
+
+	if md, ok := knownTokens[base58MintAddr]; ok {
+		return &md, nil
+	}
+
+	c.mu.RLock()
+	item, ok := c.items[base58MintAddr]
+	c.mu.RUnlock()
+	if ok && time.Now().Before(item.expiresAt) {
+		md := item.metadata
+		return &md, nil
+	}
+
+	md, err := c.getter.GetFungibleTokenMetadata(ctx, base58MintAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.items[base58MintAddr] = cachedTokenMetadata{
+		metadata:  *md,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+	c.mu.Unlock()
+
+	result := *md
+	return &result, nil
+}
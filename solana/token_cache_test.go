@@ -0,0 +1,47 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type tokenMetadataGetterFunc func(ctx context.Context, mint string) (*FungibleTokenMetadata, error)
+
+func (f tokenMetadataGetterFunc) GetFungibleTokenMetadata(ctx context.Context, mint string) (*FungibleTokenMetadata, error) {
+	return f(ctx, mint)
+}
+
+func TestTokenMetadataCache(t *testing.T) {
+	calls := 0
+	cache := NewTokenMetadataCache(tokenMetadataGetterFunc(func(ctx context.Context, mint string) (*FungibleTokenMetadata, error) {
+		calls++
+		if mint == "unknown" {
+			return nil, errors.New("not found")
+		}
+		return &FungibleTokenMetadata{Mint: mint, Symbol: "TKN", Decimals: 3}, nil
+	}), time.Hour)
+
+	md, err := cache.GetFungibleTokenMetadata(context.Background(), "SOL")
+	require.NoError(t, err)
+	require.Equal(t, uint8(9), md.Decimals)
+	require.Equal(t, 0, calls)
+
+	for i := 0; i < 3; i++ {
+		md, err = cache.GetFungibleTokenMetadata(context.Background(), "mint")
+		require.NoError(t, err)
+		require.Equal(t, "TKN", md.Symbol)
+		require.Equal(t, uint8(3), md.Decimals)
+	}
+	require.Equal(t, 1, calls)
+
+	// errors are not cached
+	_, err = cache.GetFungibleTokenMetadata(context.Background(), "unknown")
+	require.Error(t, err)
+	_, err = cache.GetFungibleTokenMetadata(context.Background(), "unknown")
+	require.Error(t, err)
+	require.Equal(t, 3, calls)
+}
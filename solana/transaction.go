@@ -1,12 +1,16 @@
 package solana
 
 import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
 	"fmt"
 	"strconv"
 
 	"github.com/easypmnt/checkout-api/internal/utils"
 	"github.com/pkg/errors"
 	"github.com/portto/solana-go-sdk/client"
+	"github.com/portto/solana-go-sdk/common"
 	"github.com/portto/solana-go-sdk/types"
 )
 
@@ -59,18 +63,116 @@ func SignTransaction(txSource string, signer types.Account) (string, error) {
 	return result, nil
 }
 
+// SignTransactionWithSigner signs a transaction with the signer, e.g. a KMS key,
+// and returns a base64 encoded transaction. The signer must be one of the transaction signers.
+func SignTransactionWithSigner(ctx context.Context, txSource string, signer Signer) (string, error) {
+	tx, err := DecodeTransaction(txSource)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: base64 to bytes: %w", err)
+	}
+
+	msg, err := tx.Message.Serialize()
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: serialize message: %w", err)
+	}
+
+	sig, err := signer.Sign(ctx, msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: signer: %w", err)
+	}
+	if err := tx.AddSignature(sig); err != nil {
+		return "", fmt.Errorf("failed to sign transaction: add signature: %w", err)
+	}
+
+	result, err := EncodeTransaction(tx)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign transaction: encode transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// MergeTransactionSignatures merges the signatures of the partially signed copies of the same transaction,
+// e.g. signed separately by the owners of a multisig account.
+// Every signature is verified against the transaction message.
+// Returns the base64 encoded merged transaction and the base58 encoded public keys
+// of the required signers whose signatures are still missing.
+func MergeTransactionSignatures(base64Txs ...string) (string, []string, error) {
+	if len(base64Txs) == 0 {
+		return "", nil, ErrNoTransactionsFound
+	}
+
+	var (
+		result types.Transaction
+		msg    []byte
+	)
+	for i, base64Tx := range base64Txs {
+		tx, err := DecodeTransaction(base64Tx)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to merge signatures: transaction #%d: %w", i, err)
+		}
+		txMsg, err := tx.Message.Serialize()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to merge signatures: transaction #%d: serialize message: %w", i, err)
+		}
+
+		if i == 0 {
+			result, msg = tx, txMsg
+			result.Signatures = make([]types.Signature, tx.Message.Header.NumRequireSignatures)
+		} else if !bytes.Equal(msg, txMsg) {
+			return "", nil, fmt.Errorf("failed to merge signatures: transaction #%d: %w", i, ErrTransactionMismatch)
+		}
+
+		for j, sig := range tx.Signatures {
+			if j >= len(result.Signatures) || isEmptySignature(sig) {
+				continue
+			}
+			if !ed25519.Verify(tx.Message.Accounts[j].Bytes(), msg, sig) {
+				return "", nil, fmt.Errorf("failed to merge signatures: transaction #%d: %w: %s",
+					i, ErrInvalidSignature, tx.Message.Accounts[j].ToBase58())
+			}
+			result.Signatures[j] = sig
+		}
+	}
+
+	var missing []string
+	for i, sig := range result.Signatures {
+		if isEmptySignature(sig) {
+			result.Signatures[i] = make(types.Signature, 64)
+			missing = append(missing, result.Message.Accounts[i].ToBase58())
+		}
+	}
+
+	merged, err := EncodeTransaction(result)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to merge signatures: %w", err)
+	}
+
+	return merged, missing, nil
+}
+
+// isEmptySignature returns true if the signature is not set,
+// i.e. it's empty or zero-filled placeholder of the unsigned transaction.
+func isEmptySignature(sig types.Signature) bool {
+	for _, b := range sig {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // CheckSolTransferTransaction checks if a transaction is a SOL transfer transaction.
 // Verifies that destination account has been credited with the correct amount.
 func CheckSolTransferTransaction(meta *client.TransactionMeta, tx types.Transaction, destination string, amount uint64) error {
-	var destIdx int
+	var txAmount int64
 	for i, acc := range tx.Message.Accounts {
 		if acc.ToBase58() == destination {
-			destIdx = i
+			txAmount = meta.PostBalances[i] - meta.PreBalances[i]
 			break
 		}
 	}
 
-	txAmount := meta.PostBalances[destIdx] - meta.PreBalances[destIdx]
 	if txAmount != int64(amount) {
 		return fmt.Errorf("amount is not equal to the amount in the transaction: %d != %d", amount, txAmount)
 	}
@@ -112,3 +214,86 @@ func CheckTokenTransferTransaction(meta *client.TransactionMeta, tx types.Transa
 
 	return nil
 }
+
+// CheckTokenBurnTransaction checks if the owner burned at least the given amount of the mint in the transaction.
+// Verifies that the owner token balance has been debited with the amount.
+func CheckTokenBurnTransaction(meta *client.TransactionMeta, mint, owner string, amount uint64) error {
+	var preBalance, postBalance uint64
+	for _, balance := range meta.PreTokenBalances {
+		if balance.Mint == mint && balance.Owner == owner {
+			preBalance, _ = strconv.ParseUint(balance.UITokenAmount.Amount, 10, 64)
+			break
+		}
+	}
+	for _, balance := range meta.PostTokenBalances {
+		if balance.Mint == mint && balance.Owner == owner {
+			postBalance, _ = strconv.ParseUint(balance.UITokenAmount.Amount, 10, 64)
+			break
+		}
+	}
+
+	if preBalance < postBalance || preBalance-postBalance < amount {
+		return fmt.Errorf("burned amount is less than expected: %d < %d", preBalance-postBalance, amount)
+	}
+
+	return nil
+}
+
+// inboundAmount returns the amount the given owner wallet has been credited with in the transaction.
+// If mint is empty or the native mint, the SOL amount in lamports is returned.
+// Returns 0 if the owner balance did not increase.
+func inboundAmount(meta *client.TransactionMeta, tx types.Transaction, mint, owner string) uint64 {
+	if mint == "" || mint == "SOL" || mint == NativeMint {
+		for i, acc := range tx.Message.Accounts {
+			if acc.ToBase58() == owner && i < len(meta.PreBalances) && i < len(meta.PostBalances) {
+				if diff := meta.PostBalances[i] - meta.PreBalances[i]; diff > 0 {
+					return uint64(diff)
+				}
+				return 0
+			}
+		}
+		return 0
+	}
+
+	var preBalance, postBalance uint64
+	for _, balance := range meta.PreTokenBalances {
+		if balance.Mint == mint && balance.Owner == owner {
+			preBalance, _ = strconv.ParseUint(balance.UITokenAmount.Amount, 10, 64)
+			break
+		}
+	}
+	for _, balance := range meta.PostTokenBalances {
+		if balance.Mint == mint && balance.Owner == owner {
+			postBalance, _ = strconv.ParseUint(balance.UITokenAmount.Amount, 10, 64)
+			break
+		}
+	}
+	if postBalance > preBalance {
+		return postBalance - preBalance
+	}
+
+	return 0
+}
+
+// transactionAccounts returns base58 encoded addresses of all accounts used in the transaction.
+func transactionAccounts(tx types.Transaction) []string {
+	result := make([]string, 0, len(tx.Message.Accounts))
+	for _, acc := range tx.Message.Accounts {
+		result = append(result, acc.ToBase58())
+	}
+	return result
+}
+
+// AssociatedTokenAddress returns the base58 encoded associated token account address
+// of the given wallet and SPL token mint.
+func AssociatedTokenAddress(base58Addr, base58MintAddr string) (string, error) {
+	ata, _, err := common.FindAssociatedTokenAddress(
+		common.PublicKeyFromString(base58Addr),
+		common.PublicKeyFromString(base58MintAddr),
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to find associated token address")
+	}
+
+	return ata.ToBase58(), nil
+}
@@ -6,9 +6,14 @@ import (
 	"github.com/easypmnt/checkout-api/internal/utils"
 	"github.com/pkg/errors"
 	"github.com/portto/solana-go-sdk/common"
+	"github.com/portto/solana-go-sdk/pkg/bincode"
 	"github.com/portto/solana-go-sdk/types"
 )
 
+// MaxTransactionSize is the maximum size of the serialized transaction in bytes,
+// the size of the network packet data.
+const MaxTransactionSize = 1232
+
 type (
 	// TransactionBuilder is a builder for Transaction.
 	TransactionBuilder struct {
@@ -17,8 +22,16 @@ type (
 		rawInstructionsBefore []types.Instruction
 		rawInstructionsAfter  []types.Instruction
 		signers               []types.Account
+		externalSigners       []Signer
 		feePayer              *common.PublicKey // transaction fee payer
 		addressLookup         []types.AddressLookupTableAccount
+		version               TransactionVersion
+
+		computeUnitLimit      uint32 // 0 = the runtime default
+		computeUnitPrice      uint64 // micro-lamports per compute unit; 0 = no priority fee
+		priorityFeeEstimator  PriorityFeeEstimator
+		priorityFeePercentile int
+		maxComputeUnitPrice   uint64
 	}
 )
 
@@ -30,6 +43,7 @@ func NewTransactionBuilder(client SolanaClient) *TransactionBuilder {
 		rawInstructionsBefore: []types.Instruction{},
 		rawInstructionsAfter:  []types.Instruction{},
 		signers:               []types.Account{},
+		externalSigners:       []Signer{},
 		addressLookup:         []types.AddressLookupTableAccount{},
 	}
 }
@@ -65,14 +79,68 @@ func (b *TransactionBuilder) AddSigner(signer types.Account) *TransactionBuilder
 	return b
 }
 
+// AddExternalSigner adds a signer which private key is kept outside of the process, e.g. in KMS.
+// The transaction message is signed by the external signer after the transaction is built.
+func (b *TransactionBuilder) AddExternalSigner(signer Signer) *TransactionBuilder {
+	b.externalSigners = append(b.externalSigners, signer)
+	return b
+}
+
 // SetAddressLookupTableAccount adds a new address lookup table account to the transaction.
 func (b *TransactionBuilder) SetAddressLookupTableAccount(account types.AddressLookupTableAccount) *TransactionBuilder {
 	b.addressLookup = append(b.addressLookup, account)
 	return b
 }
 
+// SetComputeUnitLimit sets the compute unit limit of the transaction, so the priority fee
+// is paid for the units the transaction needs rather than for the runtime default.
+func (b *TransactionBuilder) SetComputeUnitLimit(units uint32) *TransactionBuilder {
+	b.computeUnitLimit = units
+	return b
+}
+
+// SetComputeUnitPrice sets the compute unit price of the transaction in micro-lamports,
+// the priority fee is the price times the compute unit limit.
+// With the priority fee estimator, it's the minimum price, see SetPriorityFeeEstimator.
+func (b *TransactionBuilder) SetComputeUnitPrice(microLamports uint64) *TransactionBuilder {
+	b.computeUnitPrice = microLamports
+	return b
+}
+
+// SetPriorityFeeEstimator enables the automatic compute unit price: the given percentile
+// of the recent prioritization fees of the transaction writable accounts, DefaultPriorityFeePercentile if 0,
+// at most the max price, if it's set.
+// The compute budget instructions already added, e.g. by the Jupiter swap, are kept as is.
+func (b *TransactionBuilder) SetPriorityFeeEstimator(estimator PriorityFeeEstimator, percentile int, maxPrice uint64) *TransactionBuilder {
+	b.priorityFeeEstimator = estimator
+	b.priorityFeePercentile = percentile
+	b.maxComputeUnitPrice = maxPrice
+	return b
+}
+
+// Clone returns a copy of the builder, e.g. to build the same instructions with and without the extra ones.
+func (b *TransactionBuilder) Clone() *TransactionBuilder {
+	c := *b
+	c.instructions = append([]InstructionFunc{}, b.instructions...)
+	c.rawInstructionsBefore = append([]types.Instruction{}, b.rawInstructionsBefore...)
+	c.rawInstructionsAfter = append([]types.Instruction{}, b.rawInstructionsAfter...)
+	c.signers = append([]types.Account{}, b.signers...)
+	c.externalSigners = append([]Signer{}, b.externalSigners...)
+	c.addressLookup = append([]types.AddressLookupTableAccount{}, b.addressLookup...)
+	return &c
+}
+
+// SetVersion sets the serialization format of the transaction.
+// The same instructions are built either way; if it's not set, the transaction is v0
+// if it uses the address lookup tables and legacy otherwise.
+func (b *TransactionBuilder) SetVersion(version TransactionVersion) *TransactionBuilder {
+	b.version = version
+	return b
+}
+
 // Build builds a new transaction with the given instructions.
-// It returns base64 encoded transaction or an error.
+// It returns base64 encoded transaction or an error,
+// ErrTransactionTooLarge if the instructions don't fit in one transaction.
 func (b *TransactionBuilder) Build(ctx context.Context) (string, error) {
 	// Validate the builder inputs before building the transaction.
 	if err := b.Validate(); err != nil {
@@ -85,24 +153,53 @@ func (b *TransactionBuilder) Build(ctx context.Context) (string, error) {
 		return "", errors.Wrap(err, "failed to build transaction: prepare instructions")
 	}
 
+	// the compute budget instructions go first, where the wallets expect them
+	instructions = append(b.computeBudgetInstructions(ctx, instructions), instructions...)
+
 	latestBlockhash, err := b.client.GetLatestBlockhash(ctx)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to build transaction: get latest blockhash")
 	}
 
+	message := types.NewMessage(types.NewMessageParam{
+		FeePayer:                   *b.feePayer,
+		RecentBlockhash:            latestBlockhash,
+		Instructions:               instructions,
+		AddressLookupTableAccounts: b.addressLookup,
+	})
+	message.Version, err = messageVersion(b.version, message.Version, len(b.addressLookup))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to build transaction: message version")
+	}
+
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Message: types.NewMessage(types.NewMessageParam{
-			FeePayer:                   *b.feePayer,
-			RecentBlockhash:            latestBlockhash,
-			Instructions:               instructions,
-			AddressLookupTableAccounts: b.addressLookup,
-		}),
+		Message: message,
 		Signers: b.signers,
 	})
 	if err != nil {
 		return "", errors.Wrap(err, "failed to build transaction: new transaction")
 	}
 
+	if len(b.externalSigners) > 0 {
+		msg, err := tx.Message.Serialize()
+		if err != nil {
+			return "", errors.Wrap(err, "failed to build transaction: serialize message")
+		}
+		for _, signer := range b.externalSigners {
+			sig, err := signer.Sign(ctx, msg)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to build transaction: external signer")
+			}
+			if err := tx.AddSignature(sig); err != nil {
+				return "", errors.Wrap(err, "failed to build transaction: add signature")
+			}
+		}
+	}
+
+	if size := transactionSize(tx); size > MaxTransactionSize {
+		return "", errors.Wrapf(ErrTransactionTooLarge, "failed to build transaction: %d bytes", size)
+	}
+
 	base64Tx, err := EncodeTransaction(tx)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to build transaction: encode transaction")
@@ -119,7 +216,7 @@ func (b *TransactionBuilder) Validate() error {
 	if b.feePayer == nil || *b.feePayer == (common.PublicKey{}) {
 		return ErrFeePayerNotSet
 	}
-	if len(b.instructions) == 0 {
+	if len(b.instructions) == 0 && len(b.rawInstructionsBefore) == 0 && len(b.rawInstructionsAfter) == 0 {
 		return ErrNoInstruction
 	}
 	return nil
@@ -144,3 +241,13 @@ func (b *TransactionBuilder) PrepareInstructions(ctx context.Context) ([]types.I
 	}
 	return instructions, nil
 }
+
+// transactionSize returns the size of the serialized transaction:
+// the signatures with their compact-u16 count and the message.
+func transactionSize(tx types.Transaction) int {
+	msg, err := tx.Message.Serialize()
+	if err != nil {
+		return 0 // reported by the encoding
+	}
+	return len(bincode.UintToVarLenBytes(uint64(len(tx.Signatures)))) + len(tx.Signatures)*64 + len(msg)
+}
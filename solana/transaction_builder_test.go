@@ -0,0 +1,115 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/portto/solana-go-sdk/program/compute_budget"
+	"github.com/portto/solana-go-sdk/program/system"
+	"github.com/portto/solana-go-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionSize(t *testing.T) {
+	feePayer := types.NewAccount()
+	transfers := func(n int) []types.Instruction {
+		instructions := make([]types.Instruction, 0, n)
+		for i := 0; i < n; i++ {
+			instructions = append(instructions, system.Transfer(system.TransferParam{
+				From:   feePayer.PublicKey,
+				To:     types.NewAccount().PublicKey,
+				Amount: 1000,
+			}))
+		}
+		return instructions
+	}
+
+	for _, n := range []int{1, 40} {
+		tx, err := types.NewTransaction(types.NewTransactionParam{
+			Message: types.NewMessage(types.NewMessageParam{
+				FeePayer:        feePayer.PublicKey,
+				RecentBlockhash: "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N",
+				Instructions:    transfers(n),
+			}),
+			Signers: []types.Account{feePayer},
+		})
+		require.NoError(t, err)
+
+		raw, err := tx.Serialize()
+		require.NoError(t, err)
+		require.Equal(t, len(raw), transactionSize(tx))
+	}
+
+	// the copy doesn't share the instructions with the original
+	b := NewTransactionBuilder(nil).AddRawInstructionsToEnd(transfers(1)...)
+	c := b.Clone().AddRawInstructionsToBeginning(transfers(2)...)
+	require.Len(t, b.rawInstructionsBefore, 0)
+	require.Len(t, c.rawInstructionsBefore, 2)
+	require.Len(t, c.rawInstructionsAfter, 1)
+}
+
+type priorityFeeEstimatorFunc func(ctx context.Context, base58Accounts ...string) ([]PrioritizationFee, error)
+
+func (f priorityFeeEstimatorFunc) GetRecentPrioritizationFees(ctx context.Context, base58Accounts ...string) ([]PrioritizationFee, error) {
+	return f(ctx, base58Accounts...)
+}
+
+func TestComputeBudgetInstructions(t *testing.T) {
+	ctx := context.Background()
+	payer, recipient := types.NewAccount().PublicKey, types.NewAccount().PublicKey
+	transfer := system.Transfer(system.TransferParam{From: payer, To: recipient, Amount: 1000})
+
+	// nothing to add by default
+	require.Empty(t, NewTransactionBuilder(nil).computeBudgetInstructions(ctx, []types.Instruction{transfer}))
+
+	b := NewTransactionBuilder(nil).SetComputeUnitLimit(200_000).SetComputeUnitPrice(1000)
+	ins := b.computeBudgetInstructions(ctx, []types.Instruction{transfer})
+	require.Equal(t, []types.Instruction{
+		compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{Units: 200_000}),
+		compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{MicroLamports: 1000}),
+	}, ins)
+
+	// the instructions already set by the swap are kept
+	swapLimit := compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{Units: 1_400_000})
+	ins = b.computeBudgetInstructions(ctx, []types.Instruction{swapLimit, transfer})
+	require.Equal(t, []types.Instruction{
+		compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{MicroLamports: 1000}),
+	}, ins)
+
+	// the estimate of the writable accounts, the fixed price is the floor, the max price is the cap
+	var accounts []string
+	estimator := priorityFeeEstimatorFunc(func(ctx context.Context, base58Accounts ...string) ([]PrioritizationFee, error) {
+		accounts = base58Accounts
+		return []PrioritizationFee{{Slot: 1, PrioritizationFee: 500}, {Slot: 2, PrioritizationFee: 5000}}, nil
+	})
+	b.SetPriorityFeeEstimator(estimator, 100, 0)
+	require.Equal(t, uint64(5000), b.computeUnitPriceFor(ctx, []types.Instruction{transfer}))
+	require.ElementsMatch(t, []string{payer.ToBase58(), recipient.ToBase58()}, accounts)
+	b.SetPriorityFeeEstimator(estimator, 50, 0)
+	require.Equal(t, uint64(1000), b.computeUnitPriceFor(ctx, []types.Instruction{transfer}))
+	b.SetPriorityFeeEstimator(estimator, 100, 2000)
+	require.Equal(t, uint64(2000), b.computeUnitPriceFor(ctx, []types.Instruction{transfer}))
+
+	// the fixed price is the fallback
+	b.SetPriorityFeeEstimator(priorityFeeEstimatorFunc(func(ctx context.Context, base58Accounts ...string) ([]PrioritizationFee, error) {
+		return nil, errors.New("method not found")
+	}), 0, 0)
+	require.Equal(t, uint64(1000), b.computeUnitPriceFor(ctx, []types.Instruction{transfer}))
+}
+
+func TestEstimateComputeUnitPrice(t *testing.T) {
+	fees := make([]PrioritizationFee, 0, 20)
+	for i := 20; i > 0; i-- {
+		fees = append(fees, PrioritizationFee{Slot: uint64(i), PrioritizationFee: uint64(i * 100)})
+	}
+
+	require.Zero(t, EstimateComputeUnitPrice(nil, 75))
+	require.Equal(t, uint64(1500), EstimateComputeUnitPrice(fees, 75))
+	require.Equal(t, uint64(1500), EstimateComputeUnitPrice(fees, 0)) // DefaultPriorityFeePercentile
+	require.Equal(t, uint64(2000), EstimateComputeUnitPrice(fees, 100))
+	require.Equal(t, uint64(100), EstimateComputeUnitPrice(fees, 1))
+
+	require.Equal(t, uint64(200), PriorityFee(200_000, 1000))
+	require.Equal(t, uint64(1), PriorityFee(200_000, 1))
+}
@@ -0,0 +1,69 @@
+package solana_test
+
+import (
+	"testing"
+
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/portto/solana-go-sdk/program/system"
+	"github.com/portto/solana-go-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+// newMultisignerTransaction returns a base64 encoded unsigned transaction
+// which requires the signatures of both the fee payer and the sender.
+func newMultisignerTransaction(t *testing.T, feePayer, sender types.Account, blockhash string) string {
+	t.Helper()
+
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer:        feePayer.PublicKey,
+			RecentBlockhash: blockhash,
+			Instructions: []types.Instruction{
+				system.Transfer(system.TransferParam{
+					From:   sender.PublicKey,
+					To:     types.NewAccount().PublicKey,
+					Amount: 1000,
+				}),
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	result, err := solana.EncodeTransaction(tx)
+	require.NoError(t, err)
+
+	return result
+}
+
+func TestMergeTransactionSignatures(t *testing.T) {
+	feePayer, sender := types.NewAccount(), types.NewAccount()
+	unsigned := newMultisignerTransaction(t, feePayer, sender, "EkSnNWid2cvwEVnVx9aBqawnmiCNiDgp3gUdkDPTKN1N")
+
+	signedByFeePayer, err := solana.SignTransaction(unsigned, feePayer)
+	require.NoError(t, err)
+	signedBySender, err := solana.SignTransaction(unsigned, sender)
+	require.NoError(t, err)
+
+	_, missing, err := solana.MergeTransactionSignatures(unsigned)
+	require.NoError(t, err)
+	require.Equal(t, []string{feePayer.PublicKey.ToBase58(), sender.PublicKey.ToBase58()}, missing)
+
+	_, missing, err = solana.MergeTransactionSignatures(signedByFeePayer, unsigned)
+	require.NoError(t, err)
+	require.Equal(t, []string{sender.PublicKey.ToBase58()}, missing)
+
+	merged, missing, err := solana.MergeTransactionSignatures(signedByFeePayer, signedBySender)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	tx, err := solana.DecodeTransaction(merged)
+	require.NoError(t, err)
+	require.Len(t, tx.Signatures, 2)
+
+	other := newMultisignerTransaction(t, feePayer, sender, "9sHcv6xwn9YkB8nxTUGKDwPwNnmqVp5oAXxU8Fdkm4J6")
+	_, _, err = solana.MergeTransactionSignatures(signedByFeePayer, other)
+	require.ErrorIs(t, err, solana.ErrTransactionMismatch)
+
+	_, _, err = solana.MergeTransactionSignatures()
+	require.ErrorIs(t, err, solana.ErrNoTransactionsFound)
+}
@@ -8,12 +8,19 @@ import (
 	"github.com/portto/solana-go-sdk/types"
 )
 
+// Native SOL mint (wrapped SOL).
+const (
+	NativeMint         = "So11111111111111111111111111111111111111112"
+	NativeMintDecimals = 9
+)
+
 type (
 	// SolanaClient is an RPC client for Solana.
 	SolanaClient interface {
 		GetLatestBlockhash(ctx context.Context) (string, error)
 		DoesTokenAccountExist(ctx context.Context, base58AtaAddr string) (bool, error)
 		GetMinimumBalanceForRentExemption(ctx context.Context, size uint64) (uint64, error)
+		GetSOLBalance(ctx context.Context, base58Addr string) (Balance, error)
 	}
 
 	// InstructionFunc is a function that returns a list of prepared instructions.
@@ -28,6 +35,34 @@ type (
 	}
 )
 
+// InboundTransfer represents an amount credited to a wallet in a transaction.
+type InboundTransfer struct {
+	Signature string   // Transaction signature.
+	Sender    string   // Fee payer of the transaction, usually the sender.
+	Amount    uint64   // Credited amount in minimal units, 0 if the wallet balance did not increase.
+	Accounts  []string // All accounts used in the transaction, including references.
+}
+
+// TokenAllowance is the delegation of an associated token account, see ApproveTokenDelegate.
+type TokenAllowance struct {
+	Delegate string `json:"delegate,omitempty"` // base58 encoded public key of the delegate; empty if there is no delegate.
+	Amount   uint64 `json:"amount"`             // remaining allowance in the token minimal units.
+	Balance  uint64 `json:"balance"`            // token account balance in the token minimal units.
+}
+
+// ReferenceMatch is the result of matching the oldest transaction of a reference account
+// against the expected transfer to the destination wallet.
+type ReferenceMatch struct {
+	Signature      string `json:"signature,omitempty"`
+	Found          bool   `json:"found"`     // a transaction with the reference exists
+	Confirmed      bool   `json:"confirmed"` // the transaction has reached the required commitment
+	Failed         bool   `json:"failed"`    // the transaction is confirmed with an error
+	ExpectedAmount uint64 `json:"expected_amount"`
+	ReceivedAmount uint64 `json:"received_amount"` // amount the destination has been credited with
+	Matched        bool   `json:"matched"`         // the transaction is confirmed and the amounts are equal
+	Error          string `json:"error,omitempty"`
+}
+
 // NewBalance returns a new Balance instance.
 func NewBalance(amount uint64, decimals uint8) Balance {
 	return Balance{
@@ -63,15 +98,9 @@ func (s TransactionStatus) String() string {
 }
 
 // ParseTransactionStatus parses the transaction status from the given string.
+// Only finalized transactions are successful, see TransactionStatusAt for the other levels.
 func ParseTransactionStatus(s rpc.Commitment) TransactionStatus {
-	switch s {
-	case rpc.CommitmentFinalized:
-		return TransactionStatusSuccess
-	case rpc.CommitmentConfirmed, rpc.CommitmentProcessed:
-		return TransactionStatusInProgress
-	default:
-		return TransactionStatusUnknown
-	}
+	return TransactionStatusAt(s, CommitmentFinalized)
 }
 
 // FungibleTokenMetadata represents the metadata of a fungible token.
@@ -0,0 +1,61 @@
+package solana
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/portto/solana-go-sdk/types"
+)
+
+// TransactionVersion is the serialization format of the built transaction.
+// The older wallets can't deserialize the versioned transactions, so the legacy format is the default.
+type TransactionVersion string
+
+// Transaction versions, the values the wallets report in the supported transaction versions.
+const (
+	TransactionVersionLegacy TransactionVersion = "legacy"
+	TransactionVersionV0     TransactionVersion = "0"
+)
+
+// ParseTransactionVersion parses the transaction version from the given string: legacy, 0 or v0.
+// Empty string means legacy.
+func ParseTransactionVersion(s string) (TransactionVersion, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", string(TransactionVersionLegacy):
+		return TransactionVersionLegacy, nil
+	case string(TransactionVersionV0), "v0":
+		return TransactionVersionV0, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedTransactionVersion, s)
+	}
+}
+
+// PreferredTransactionVersion returns the transaction version to build for the wallet
+// which supports the given versions: v0 if it's supported, legacy otherwise.
+// The unknown versions are ignored.
+func PreferredTransactionVersion(supported ...string) TransactionVersion {
+	for _, s := range supported {
+		if v, err := ParseTransactionVersion(s); err == nil && v == TransactionVersionV0 {
+			return TransactionVersionV0
+		}
+	}
+	return TransactionVersionLegacy
+}
+
+// messageVersion returns the message version of the transaction version.
+// Empty version keeps the version of the compiled message: v0 with the address lookup tables, legacy otherwise.
+func messageVersion(v TransactionVersion, compiled types.MessageVersion, lookupTables int) (types.MessageVersion, error) {
+	switch v {
+	case "":
+		return compiled, nil
+	case TransactionVersionV0:
+		return types.MessageVersionV0, nil
+	case TransactionVersionLegacy:
+		if lookupTables > 0 {
+			return "", ErrLookupTablesRequireV0
+		}
+		return types.MessageVersionLegacy, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedTransactionVersion, v)
+	}
+}
@@ -0,0 +1,39 @@
+package solana
+
+import (
+	"testing"
+
+	"github.com/portto/solana-go-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionVersion(t *testing.T) {
+	v, err := ParseTransactionVersion("")
+	require.NoError(t, err)
+	require.Equal(t, TransactionVersionLegacy, v)
+
+	v, err = ParseTransactionVersion(" V0 ")
+	require.NoError(t, err)
+	require.Equal(t, TransactionVersionV0, v)
+
+	_, err = ParseTransactionVersion("1")
+	require.ErrorIs(t, err, ErrUnsupportedTransactionVersion)
+
+	// v0 is preferred if the wallet supports it
+	require.Equal(t, TransactionVersionV0, PreferredTransactionVersion("legacy", "0"))
+	require.Equal(t, TransactionVersionLegacy, PreferredTransactionVersion("legacy", "1"))
+	require.Equal(t, TransactionVersionLegacy, PreferredTransactionVersion())
+
+	// the compiled version is kept by default
+	mv, err := messageVersion("", types.MessageVersionV0, 1)
+	require.NoError(t, err)
+	require.EqualValues(t, types.MessageVersionV0, mv)
+
+	// the lookup tables can't be used by the legacy transactions
+	_, err = messageVersion(TransactionVersionLegacy, types.MessageVersionV0, 1)
+	require.ErrorIs(t, err, ErrLookupTablesRequireV0)
+
+	mv, err = messageVersion(TransactionVersionV0, types.MessageVersionLegacy, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, types.MessageVersionV0, mv)
+}
@@ -0,0 +1,302 @@
+// Package solanatest provides a local Solana validator harness for integration tests.
+// It spins up solana-test-validator, or connects to the localnet configured
+// with the SOLANA_TEST_RPC_ENDPOINT environment variable, funds throwaway wallets
+// and provides helpers to assert balances and references.
+// Tests are skipped if neither the localnet nor the validator binary is available.
+package solanatest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/solana"
+	"github.com/portto/solana-go-sdk/client"
+	"github.com/portto/solana-go-sdk/types"
+	"github.com/stretchr/testify/require"
+)
+
+// Environment variables to connect to an already running localnet.
+const (
+	EnvRPCEndpoint = "SOLANA_TEST_RPC_ENDPOINT"
+	EnvWSEndpoint  = "SOLANA_TEST_WSS_ENDPOINT"
+)
+
+// ValidatorBin is the name of the local validator binary.
+const ValidatorBin = "solana-test-validator"
+
+type (
+	// Validator is a running local validator.
+	Validator struct {
+		RPCEndpoint string
+		WSEndpoint  string
+		Client      *solana.Client
+
+		confirmTimeout time.Duration
+	}
+
+	// Option is a function that configures the validator.
+	Option func(*config)
+
+	config struct {
+		args           []string
+		startTimeout   time.Duration
+		confirmTimeout time.Duration
+	}
+)
+
+// WithValidatorArgs passes extra arguments to solana-test-validator,
+// e.g. to clone programs or accounts from a cluster.
+// It has no effect if the localnet is configured with the environment variables.
+func WithValidatorArgs(args ...string) Option {
+	return func(c *config) {
+		c.args = append(c.args, args...)
+	}
+}
+
+// WithStartTimeout sets the maximum time to wait for the validator to start.
+// Default: 1 minute.
+func WithStartTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.startTimeout = d
+	}
+}
+
+// WithConfirmTimeout sets the maximum time to wait for a transaction to be confirmed.
+// Default: 1 minute.
+func WithConfirmTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.confirmTimeout = d
+	}
+}
+
+// Start returns a local validator for the test.
+// It connects to the localnet from the environment variables if they are set,
+// otherwise it starts solana-test-validator with a temporary ledger,
+// which is stopped when the test and all its subtests complete.
+func Start(t testing.TB, opts ...Option) *Validator {
+	t.Helper()
+
+	cfg := &config{
+		startTimeout:   time.Minute,
+		confirmTimeout: time.Minute,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if endpoint := os.Getenv(EnvRPCEndpoint); endpoint != "" {
+		v := newValidator(endpoint, os.Getenv(EnvWSEndpoint), cfg)
+		v.waitReady(t, cfg.startTimeout, nil)
+		return v
+	}
+
+	bin, err := exec.LookPath(ValidatorBin)
+	if err != nil {
+		t.Skipf("solanatest: %s is not found and %s is not set", ValidatorBin, EnvRPCEndpoint)
+	}
+
+	rpcPort := freePort(t)
+	args := append([]string{
+		"--reset",
+		"--quiet",
+		"--ledger", t.TempDir(),
+		"--rpc-port", strconv.Itoa(rpcPort),
+		"--faucet-port", strconv.Itoa(freePort(t)),
+		"--gossip-port", strconv.Itoa(freePort(t)),
+	}, cfg.args...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cmd := exec.CommandContext(ctx, bin, args...)
+	output := &syncBuffer{}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	require.NoError(t, cmd.Start(), "solanatest: failed to start %s", ValidatorBin)
+
+	exited := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(exited)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-exited
+	})
+
+	v := newValidator(
+		fmt.Sprintf("http://127.0.0.1:%d", rpcPort),
+		fmt.Sprintf("ws://127.0.0.1:%d", rpcPort+1), // the validator serves websockets on the next port
+		cfg,
+	)
+	v.waitReady(t, cfg.startTimeout, func() (string, bool) {
+		select {
+		case <-exited:
+			return output.String(), true
+		default:
+			return "", false
+		}
+	})
+
+	return v
+}
+
+func newValidator(rpcEndpoint, wsEndpoint string, cfg *config) *Validator {
+	opts := []solana.ClientOption{solana.WithRPCEndpoint(rpcEndpoint)}
+	if wsEndpoint != "" {
+		opts = append(opts, solana.WithWSEndpoint(wsEndpoint))
+	}
+
+	return &Validator{
+		RPCEndpoint:    rpcEndpoint,
+		WSEndpoint:     wsEndpoint,
+		Client:         solana.NewClient(opts...),
+		confirmTimeout: cfg.confirmTimeout,
+	}
+}
+
+// waitReady waits until the validator RPC responds.
+// exited reports whether the validator process has exited with its output, so the test fails fast.
+func (v *Validator) waitReady(t testing.TB, timeout time.Duration, exited func() (string, bool)) {
+	t.Helper()
+
+	deadline := time.Now().Add(timeout)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		_, err := v.Client.GetLatestBlockhash(ctx)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if exited != nil {
+			if out, ok := exited(); ok {
+				t.Fatalf("solanatest: %s exited: %s", ValidatorBin, out)
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("solanatest: validator at %s is not ready: %v", v.RPCEndpoint, err)
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+// NewWallet creates a throwaway wallet funded with the given amount of lamports.
+func (v *Validator) NewWallet(t testing.TB, lamports uint64) types.Account {
+	t.Helper()
+
+	wallet := types.NewAccount()
+	if lamports > 0 {
+		v.Airdrop(t, wallet.PublicKey.ToBase58(), lamports)
+	}
+
+	return wallet
+}
+
+// Airdrop funds the wallet with the given amount of lamports and waits for the confirmation.
+func (v *Validator) Airdrop(t testing.TB, base58Addr string, lamports uint64) {
+	t.Helper()
+
+	ctx := context.Background()
+	txSig, err := v.Client.RequestAirdrop(ctx, base58Addr, lamports)
+	require.NoError(t, err, "solanatest: failed to request airdrop")
+	v.requireConfirmed(t, txSig)
+}
+
+// SendAndConfirm signs the base64 encoded transaction by the given signers,
+// sends it and waits for the confirmation. Returns the transaction signature.
+func (v *Validator) SendAndConfirm(t testing.TB, tx string, signers ...types.Account) string {
+	t.Helper()
+
+	var err error
+	for _, signer := range signers {
+		tx, err = solana.SignTransaction(tx, signer)
+		require.NoError(t, err, "solanatest: failed to sign transaction")
+	}
+
+	txSig, err := v.Client.SendTransaction(context.Background(), tx)
+	require.NoError(t, err, "solanatest: failed to send transaction")
+	v.requireConfirmed(t, txSig)
+
+	return txSig
+}
+
+// RequireSOLBalance asserts the SOL balance of the wallet.
+func (v *Validator) RequireSOLBalance(t testing.TB, base58Addr string, expected uint64) {
+	t.Helper()
+
+	balance, err := v.Client.GetSOLBalance(context.Background(), base58Addr)
+	require.NoError(t, err)
+	require.EqualValues(t, expected, balance.Amount, "solanatest: unexpected SOL balance of %s", base58Addr)
+}
+
+// RequireTokenBalance asserts the token balance of the wallet.
+func (v *Validator) RequireTokenBalance(t testing.TB, base58Addr, base58MintAddr string, expected uint64) {
+	t.Helper()
+
+	balance, err := v.Client.GetTokenBalance(context.Background(), base58Addr, base58MintAddr)
+	require.NoError(t, err)
+	require.EqualValues(t, expected, balance.Amount, "solanatest: unexpected %s balance of %s", base58MintAddr, base58Addr)
+}
+
+// RequireReference waits until the transaction with the given reference account is finalized,
+// and returns its signature and the transaction itself.
+func (v *Validator) RequireReference(t testing.TB, reference string) (string, *client.GetTransactionResponse) {
+	t.Helper()
+
+	deadline := time.Now().Add(v.confirmTimeout)
+	for {
+		txSig, tx, err := v.Client.GetOldestTransactionForWallet(context.Background(), reference, "", solana.CommitmentFinalized)
+		if err == nil {
+			return txSig, tx
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("solanatest: no finalized transaction for reference %s: %v", reference, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// requireConfirmed waits for the transaction to be confirmed successfully.
+func (v *Validator) requireConfirmed(t testing.TB, txSig string) {
+	t.Helper()
+
+	status, err := v.Client.WaitForTransactionConfirmed(context.Background(), txSig, solana.CommitmentFinalized, v.confirmTimeout)
+	require.NoError(t, err, "solanatest: transaction %s is not confirmed", txSig)
+	require.EqualValues(t, solana.TransactionStatusSuccess, status, "solanatest: transaction %s failed", txSig)
+}
+
+// freePort returns a free local TCP port.
+func freePort(t testing.TB) int {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err, "solanatest: failed to get a free port")
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
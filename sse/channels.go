@@ -2,38 +2,72 @@ package sse
 
 import (
 	"strings"
+	"sync"
 
 	"github.com/dustin/go-broadcast"
 )
 
-var channels = make(map[string]broadcast.Broadcaster)
+// hub is the broadcaster of the channel with the number of its listeners.
+type hub struct {
+	broadcast.Broadcaster
+	listeners int
+}
+
+var (
+	channelsMu sync.Mutex
+	channels   = make(map[string]*hub)
+)
 
+// openListener registers a new listener of the channel, the channel is created on the first listener.
 func openListener(channelID string) chan interface{} {
+	channelsMu.Lock()
+	defer channelsMu.Unlock()
+
+	channelID = strings.ToLower(channelID)
+	h, ok := channels[channelID]
+	if !ok {
+		h = &hub{Broadcaster: broadcast.NewBroadcaster(10)}
+		channels[channelID] = h
+	}
+	h.listeners++
+
 	listener := make(chan interface{})
-	channel(channelID).Register(listener)
+	h.Register(listener)
 	return listener
 }
 
+// closeListener unregisters the listener of the channel, the channel is closed with its last listener.
 func closeListener(channelID string, listener chan interface{}) {
-	channel(channelID).Unregister(listener)
-	close(listener)
-}
+	channelsMu.Lock()
+	defer channelsMu.Unlock()
 
-func deleteBroadcast(channelID string) {
 	channelID = strings.ToLower(channelID)
-	b, ok := channels[channelID]
-	if ok {
-		b.Close()
+	h, ok := channels[channelID]
+	if !ok {
+		return
+	}
+	// the broadcaster may be blocked on sending to this listener, so it's drained until unregistered
+	go func() {
+		for range listener {
+		}
+	}()
+	h.Unregister(listener)
+	close(listener)
+
+	if h.listeners--; h.listeners <= 0 {
+		h.Close()
 		delete(channels, channelID)
 	}
 }
 
-func channel(channelID string) broadcast.Broadcaster {
-	channelID = strings.ToLower(channelID)
-	b, ok := channels[channelID]
-	if !ok {
-		b = broadcast.NewBroadcaster(10)
-		channels[channelID] = b
+// submit sends the event to the listeners of the channel, if any.
+// The channels without listeners are not created, so publishing to every payment doesn't leak the broadcasters.
+func submit(channelID string, event interface{}) {
+	channelsMu.Lock()
+	h, ok := channels[strings.ToLower(channelID)]
+	channelsMu.Unlock()
+
+	if ok {
+		h.Submit(event)
 	}
-	return b
 }
@@ -3,15 +3,37 @@ package sse
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/easypmnt/checkout-api/events"
 )
 
+// PaymentStatusEvents are the events changing the payment status, streamed to the checkout pages.
+var PaymentStatusEvents = []events.EventName{
+	events.PaymentProcessing,
+	events.PaymentPartiallyPaid,
+	events.PaymentSucceeded,
+	events.PaymentFailed,
+	events.PaymentExpired,
+	events.PaymentCancelled,
+	events.PaymentHeld,
+	events.PaymentReleased,
+	events.PaymentDisputed,
+	events.PaymentDisputeResolved,
+}
+
+// PaymentStatusEvent is the name of the current payment status sent on connect,
+// with the events.PaymentStatusUpdatedPayload payload.
+const PaymentStatusEvent = "payment.status"
+
+// EventTTL is the time the published events are replayed to the reconnected clients.
+const EventTTL = 5 * time.Minute
+
 type sseService interface {
 	PubEvent(channelID string, data EventData, ttl int64) error
 }
 
-// TranslateEventsToSSEChannel translates the events from the events package to the webhook events.
+// TranslateEventsToSSEChannel publishes the events from the events package to the SSE channels of their payments.
 func TranslateEventsToSSEChannel(sse sseService) events.Listener {
 	return func(event events.EventName, payload interface{}) error {
 		if payload == nil {
@@ -26,7 +48,7 @@ func TranslateEventsToSSEChannel(sse sseService) events.Listener {
 		return sse.PubEvent(pid, EventData{
 			Name:    string(event),
 			Payload: payload,
-		}, 300)
+		}, int64(EventTTL.Seconds()))
 	}
 }
 
@@ -1,6 +1,7 @@
 package sse
 
 import (
+	"context"
 	"sort"
 	"sync"
 	"time"
@@ -58,11 +59,7 @@ func (s *MemStorage) Add(channelID string, event Event) error {
 	s.Lock()
 	defer s.Unlock()
 
-	events, ok := s.events[channelID]
-	if !ok {
-		events = make([]Event, 0, defaultChannelLength)
-	}
-	s.events[channelID] = sortEvents(append(events, event))
+	s.events[channelID] = sortEvents(append(s.events[channelID], event))
 
 	return nil
 }
@@ -86,33 +83,19 @@ func (s *MemStorage) Delete(channelID string, event Event) error {
 	return nil
 }
 
-// GC - garbage collector
-func (s *MemStorage) GC(eventMaxAge, gcPeriod string, wg *sync.WaitGroup) error {
-	if eventMaxAge == "" {
-		eventMaxAge = "1h"
-	}
-	maxAge, err := time.ParseDuration(eventMaxAge)
-	if err != nil {
-		return err
-	}
-
-	if gcPeriod == "" {
-		gcPeriod = "1h"
-	}
-	period, err := time.ParseDuration(gcPeriod)
-	if err != nil {
-		return err
-	}
-
+// GC deletes the events older than maxAge every period, until the context is done.
+func (s *MemStorage) GC(ctx context.Context, maxAge, period time.Duration) error {
 	ticker := time.NewTicker(period)
 	defer ticker.Stop()
 
 	for {
 		select {
+		case <-ctx.Done():
+			return nil
 		case <-ticker.C:
-			wg.Add(1)
-			s.gc(maxAge)
-			wg.Done()
+			if err := s.gc(maxAge); err != nil {
+				return err
+			}
 		}
 	}
 }
@@ -147,13 +130,11 @@ func (s *MemStorage) deleteBefore(channelID string, t int64) error {
 	}
 
 	i := positionGt(events, t)
-	if i >= 0 {
-		l := len(events[i:])
-		c := defaultChannelLength
-		if l > c {
-			c = l
-		}
-		truncated := make([]Event, l, c)
+	switch {
+	case i >= len(events):
+		delete(s.events, channelID)
+	case i >= 0:
+		truncated := make([]Event, len(events[i:]))
 		copy(truncated, events[i:])
 		s.events[channelID] = truncated
 	}
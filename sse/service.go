@@ -3,22 +3,41 @@ package sse
 import (
 	"errors"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/easypmnt/checkout-api/internal/logging"
 )
 
 type (
 	// SSE service struct
 	Service struct {
 		storage Storage
+		log     logger
 	}
+
+	// ServiceOption is a function that configures the SSE service.
+	ServiceOption func(*Service)
 )
 
 // NewService factory func returns new SSE service
-func NewService(storage Storage) *Service {
-	return &Service{storage: storage}
+func NewService(storage Storage, opts ...ServiceOption) *Service {
+	s := &Service{storage: storage}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.log == nil {
+		s.log = logging.Default().Module("sse")
+	}
+	return s
+}
+
+// WithLogger sets the logger of the SSE service.
+func WithLogger(l logger) ServiceOption {
+	return func(s *Service) {
+		s.log = l
+	}
 }
 
 // PubEvent func publishes data to channel with given ttl in seconds,
@@ -31,7 +50,7 @@ func (s *Service) PubEvent(channelID string, data EventData, ttl int64) error {
 		TTL:       ttl,
 		Timestamp: t,
 	}
-	channel(channelID).Submit(event)
+	submit(channelID, event)
 	return s.storeEvent(channelID, event)
 }
 
@@ -44,9 +63,10 @@ func (s *Service) SubscribeToChannel(channelID, lastEventID string) (chan interf
 	if lastEventID != "" {
 		history, err = s.getEventsByLastID(channelID, lastEventID)
 		if err != nil {
+			closeListener(channelID, listener)
 			return nil, nil, err
 		}
-		log.Printf("\nchannel: %s;\nlast event id: %s;\nhistory: %+v\n", channelID, lastEventID, history)
+		s.log.Debugf("sse: channel: %s; last event id: %s; history: %d events", channelID, lastEventID, len(history))
 	}
 	return listener, history, err
 }
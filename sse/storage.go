@@ -1,7 +1,8 @@
 package sse
 
 import (
-	"sync"
+	"context"
+	"time"
 )
 
 type (
@@ -15,7 +16,7 @@ type (
 		Add(channelID string, event Event) error
 		// Delete event from storage
 		Delete(channelID string, event Event) error
-		// Deletes event which is older then given time from channel
-		GC(eventMaxAge, gcPeriod string, wg *sync.WaitGroup) error
+		// Deletes the events older than maxAge every period, until the context is done
+		GC(ctx context.Context, maxAge, period time.Duration) error
 	}
 )
@@ -1,10 +1,18 @@
 package sse
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
 	"net/http"
+	"time"
 
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/payments"
 	"github.com/gin-contrib/sse"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 )
 
 type (
@@ -19,6 +27,10 @@ type (
 		SubscribeToChannel(channelID, lastEventID string) (chan interface{}, []Event, error)
 		Unsubscribe(channelID string, listener chan interface{}) error
 	}
+
+	paymentStatusGetter interface {
+		GetPaymentStatus(ctx context.Context, id uuid.UUID) (*payments.PaymentStatusInfo, error)
+	}
 )
 
 // MakeHTTPHandler returns a handler that makes a set of endpoints available on
@@ -77,23 +89,133 @@ func subscribeToChannel(s sseServer, log logger) http.HandlerFunc {
 			}
 		}
 
-		for {
-			select {
-			case <-r.Context().Done():
-				log.Debugf("[client_disconnected] client closed connection: %s", channelID)
+		streamEvents(w, r, flusher, channelID, listener, 0, log)
+	}
+}
+
+// MakePaymentEventsHandler returns a handler streaming the status updates of the payment
+// with the {payment_id} URL parameter, e.g. GET /payment/checkout/{payment_id}/events.
+// The current status is sent on connect, so the client doesn't miss the updates happened before it.
+// The reconnected client with the Last-Event-ID gets the missed events instead, if they are still kept.
+// The heartbeat comment is sent every heartbeat interval, so the proxies don't close the idle connection.
+func MakePaymentEventsHandler(s sseServer, ps paymentStatusGetter, heartbeat time.Duration, log logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		paymentID, err := uuid.Parse(chi.URLParam(r, "payment_id"))
+		if err != nil {
+			http.Error(w, "Invalid payment id!", http.StatusBadRequest)
+			return
+		}
+		channelID := paymentID.String()
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			log.Warnf("streaming unsupported: channel %s", channelID)
+			http.Error(w, "Streaming unsupported!", http.StatusNotImplemented)
+			return
+		}
+
+		// subscribe before getting the status, so the updates in between are not lost
+		listener, history, err := s.SubscribeToChannel(channelID, getLastEventID(r))
+		if err != nil {
+			log.Debugf("subscribe to channel %s with last event id %s: %s", channelID, getLastEventID(r), err.Error())
+			history = nil
+			listener, _, err = s.SubscribeToChannel(channelID, "")
+			if err != nil {
+				log.Errorf("subscribe to channel %s: %s", channelID, err.Error())
+				http.Error(w, "Could not subscribe to events channel", http.StatusInternalServerError)
+				return
+			}
+		}
+		defer s.Unsubscribe(channelID, listener)
+
+		missed := make([]Event, 0, len(history))
+		for _, event := range history {
+			if !event.IsExpired() {
+				missed = append(missed, event)
+			}
+		}
+
+		var current *payments.PaymentStatusInfo
+		if len(missed) == 0 {
+			if current, err = ps.GetPaymentStatus(r.Context(), paymentID); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					http.Error(w, "Payment not found", http.StatusNotFound)
+					return
+				}
+				log.Errorf("get payment %s status: %s", channelID, err.Error())
+				http.Error(w, "Could not get payment status", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		if err := openHTTPConnection(w, r); err != nil {
+			log.Errorf("open http connection: %s", err.Error())
+			return
+		}
+		flusher.Flush()
+
+		// the missed events of the reconnected client, or the current status;
+		// the status has no id, so it doesn't reset the last event id of the client
+		for _, event := range missed {
+			if err := sse.Encode(w, event.MapToSseEvent()); err != nil {
+				log.Errorf("sse encoding: %s (channel id: %s, event: %#v)", err.Error(), channelID, event)
+				return
+			}
+		}
+		if current != nil {
+			if err := sse.Encode(w, sse.Event{
+				Event: "message",
+				Data: EventData{
+					Name: PaymentStatusEvent,
+					Payload: events.PaymentStatusUpdatedPayload{
+						PaymentID:  events.PaymentID{PaymentID: channelID},
+						Occurrence: events.OccurredNow(),
+						Status:     string(current.Status),
+					},
+				},
+			}); err != nil {
+				log.Errorf("sse encoding: %s (channel id: %s)", err.Error(), channelID)
+				return
+			}
+		}
+		flusher.Flush()
+
+		streamEvents(w, r, flusher, channelID, listener, heartbeat, log)
+	}
+}
+
+// streamEvents writes the events of the channel to the client until it disconnects.
+// The heartbeat comment is written every heartbeat interval, if it's positive.
+func streamEvents(w http.ResponseWriter, r *http.Request, flusher http.Flusher, channelID string, listener chan interface{}, heartbeat time.Duration, log logger) {
+	var tick <-chan time.Time
+	if heartbeat > 0 {
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			log.Debugf("[client_disconnected] client closed connection: %s", channelID)
+			return
+		case <-tick:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				log.Debugf("sse heartbeat: %s (channel id: %s)", err.Error(), channelID)
 				return
-			case event := <-listener:
-				if e, ok := event.(Event); ok {
-					err := sse.Encode(w, e.MapToSseEvent())
-					if err != nil {
-						log.Errorf("sse encoding: %s (channel id: %s, event: %#v)", err.Error(), channelID, event)
-						return
-					}
-					flusher.Flush()
-					log.Debugf("[channel_received_event] channel %s: received event: %+v", channelID, event)
-				} else {
-					log.Errorf("event is not Event type: %#v", event)
+			}
+			flusher.Flush()
+		case event := <-listener:
+			if e, ok := event.(Event); ok {
+				err := sse.Encode(w, e.MapToSseEvent())
+				if err != nil {
+					log.Errorf("sse encoding: %s (channel id: %s, event: %#v)", err.Error(), channelID, event)
+					return
 				}
+				flusher.Flush()
+				log.Debugf("[channel_received_event] channel %s: received event: %+v", channelID, event)
+			} else {
+				log.Errorf("event is not Event type: %#v", event)
 			}
 		}
 	}
@@ -0,0 +1,194 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		CreatePlan endpoint.Endpoint
+		GetPlan    endpoint.Endpoint
+		ListPlans  endpoint.Endpoint
+		Subscribe  endpoint.Endpoint
+		Get        endpoint.Endpoint
+		Cancel     endpoint.Endpoint
+	}
+
+	// CreatePlanRequest is the request type for the CreatePlan method.
+	CreatePlanRequest struct {
+		Name          string `json:"name" validate:"required|max_len:255"`
+		Amount        uint64 `json:"amount" validate:"required|gt:0"`
+		Currency      string `json:"currency,omitempty" validate:"-"` // merchant default mint if empty
+		Interval      string `json:"interval" validate:"required|in:day,week,month,year"`
+		IntervalCount int    `json:"interval_count,omitempty" validate:"gte:0"` // 1 if empty
+	}
+
+	// SubscribeRequest is the request type for the Subscribe method.
+	SubscribeRequest struct {
+		PlanID        string     `json:"plan_id" validate:"required|uuid" label:"Plan ID"`
+		CustomerEmail string     `json:"customer_email,omitempty" validate:"email"`
+		StartAt       *time.Time `json:"start_at,omitempty" validate:"-"` // the first installment is due at once if empty
+		Livemode      bool       `json:"-"`
+	}
+
+	// PlanResponse is the response type for the CreatePlan and GetPlan methods.
+	PlanResponse struct {
+		Plan *Plan `json:"plan"`
+	}
+
+	// ListPlansResponse is the response type for the ListPlans method.
+	ListPlansResponse struct {
+		Plans []Plan `json:"plans"`
+	}
+
+	// SubscriptionResponse is the response type for the Subscribe, Get and Cancel methods.
+	SubscriptionResponse struct {
+		Subscription *Subscription `json:"subscription"`
+		Installments []Installment `json:"installments,omitempty"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		CreatePlan: makeCreatePlanEndpoint(s),
+		GetPlan:    makeGetPlanEndpoint(s),
+		ListPlans:  makeListPlansEndpoint(s),
+		Subscribe:  makeSubscribeEndpoint(s),
+		Get:        makeGetEndpoint(s),
+		Cancel:     makeCancelEndpoint(s),
+	}
+}
+
+// makeCreatePlanEndpoint returns an endpoint function for the CreatePlan method.
+func makeCreatePlanEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CreatePlanRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		plan, err := s.CreatePlan(ctx, CreatePlanParams{
+			Name:          req.Name,
+			Amount:        req.Amount,
+			Currency:      req.Currency,
+			Interval:      req.Interval,
+			IntervalCount: req.IntervalCount,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return PlanResponse{Plan: plan}, nil
+	}
+}
+
+// makeGetPlanEndpoint returns an endpoint function for the GetPlan method.
+func makeGetPlanEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		plan, err := s.GetPlan(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return PlanResponse{Plan: plan}, nil
+	}
+}
+
+// makeListPlansEndpoint returns an endpoint function for the ListPlans method.
+func makeListPlansEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		plans, err := s.ListPlans(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return ListPlansResponse{Plans: plans}, nil
+	}
+}
+
+// makeSubscribeEndpoint returns an endpoint function for the Subscribe method.
+func makeSubscribeEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(SubscribeRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		planID, err := uuid.Parse(req.PlanID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid plan ID: %v", ErrInvalidRequest, err)
+		}
+
+		sub, err := s.Subscribe(ctx, SubscribeParams{
+			PlanID:        planID,
+			CustomerEmail: req.CustomerEmail,
+			StartAt:       req.StartAt,
+			Livemode:      req.Livemode,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return SubscriptionResponse{Subscription: sub}, nil
+	}
+}
+
+// makeGetEndpoint returns an endpoint function for the Get method.
+// The response includes the installments of the subscription.
+func makeGetEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		sub, err := s.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		installments, err := s.ListInstallments(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return SubscriptionResponse{Subscription: sub, Installments: installments}, nil
+	}
+}
+
+// makeCancelEndpoint returns an endpoint function for the Cancel method.
+func makeCancelEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		id, ok := request.(uuid.UUID)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		sub, err := s.Cancel(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		return SubscriptionResponse{Subscription: sub}, nil
+	}
+}
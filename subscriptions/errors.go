@@ -0,0 +1,12 @@
+package subscriptions
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest        = errors.New("invalid_request")
+	ErrInvalidPlan           = errors.New("invalid_plan")
+	ErrPlanNotFound          = errors.New("plan_not_found")
+	ErrSubscriptionNotFound  = errors.New("subscription_not_found")
+	ErrSubscriptionNotActive = errors.New("subscription_not_active")
+)
@@ -0,0 +1,53 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/google/uuid"
+)
+
+// eventStatuses maps the payment events to the installment statuses.
+var eventStatuses = map[events.EventName]string{
+	events.PaymentSucceeded: InstallmentStatusPaid,
+	events.PaymentHeld:      InstallmentStatusPaid,
+	events.PaymentExpired:   InstallmentStatusMissed,
+	events.PaymentFailed:    InstallmentStatusMissed,
+	events.PaymentCancelled: InstallmentStatusMissed,
+}
+
+// Events returns the list of events the installments listener is subscribed to.
+func Events() []events.EventName {
+	result := make([]events.EventName, 0, len(eventStatuses))
+	for name := range eventStatuses {
+		result = append(result, name)
+	}
+	return result
+}
+
+// Listener marks the installments as paid or missed from the events of their payments.
+func Listener(s *Service) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		status, ok := eventStatuses[event]
+		if !ok || payload == nil {
+			return nil
+		}
+
+		p, ok := payload.(events.PaymentIDGetter)
+		if !ok {
+			return nil
+		}
+
+		pid, err := uuid.Parse(p.GetPaymentID())
+		if err != nil {
+			return fmt.Errorf("failed to parse payment id: %s", err.Error())
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return s.UpdateInstallmentStatus(ctx, pid, status)
+	}
+}
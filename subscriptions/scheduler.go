@@ -0,0 +1,16 @@
+package subscriptions
+
+import "github.com/hibiken/asynq"
+
+// Scheduler is a task scheduler for subscriptions service.
+type Scheduler struct{}
+
+// NewScheduler creates a new task scheduler for subscriptions service.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Schedule tasks for subscriptions service.
+func (s *Scheduler) Schedule(scheduler *asynq.Scheduler) {
+	scheduler.Register("@every 1m", asynq.NewTask(TaskCreateDueInstallments, nil))
+}
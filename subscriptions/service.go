@@ -0,0 +1,317 @@
+package subscriptions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// Service manages the recurring billing plans and the subscriptions to them.
+// The installments of the active subscriptions are created by the scheduled task,
+// each one with its own payment, see CreateDueInstallments.
+type Service struct {
+	repo     subscriptionRepository
+	payments paymentCreator
+	emit     func(events.EventName, interface{})
+}
+
+// NewService creates a new subscriptions service.
+// The installment payments are created with the given payment service, so they fire the payment events as usual.
+// The emit function is used to fire the subscription.installment_* events.
+func NewService(repo subscriptionRepository, ps paymentCreator, emit func(events.EventName, interface{})) *Service {
+	return &Service{repo: repo, payments: ps, emit: emit}
+}
+
+// CreatePlan creates a new recurring billing plan.
+func (s *Service) CreatePlan(ctx context.Context, params CreatePlanParams) (*Plan, error) {
+	if params.IntervalCount == 0 {
+		params.IntervalCount = 1
+	}
+	if params.Amount == 0 {
+		return nil, fmt.Errorf("%w: amount must be greater than 0", ErrInvalidPlan)
+	}
+	if !validInterval(params.Interval) {
+		return nil, fmt.Errorf("%w: unsupported interval %q", ErrInvalidPlan, params.Interval)
+	}
+	if params.IntervalCount < 0 {
+		return nil, fmt.Errorf("%w: interval count must be positive", ErrInvalidPlan)
+	}
+
+	p, err := s.repo.CreateSubscriptionPlan(ctx, repository.CreateSubscriptionPlanParams{
+		Name:            params.Name,
+		Amount:          int64(params.Amount),
+		Currency:        params.Currency,
+		BillingInterval: params.Interval,
+		IntervalCount:   int32(params.IntervalCount),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription plan: %w", err)
+	}
+
+	result := castFromRepositoryPlan(p)
+	return &result, nil
+}
+
+// GetPlan returns the plan by its ID.
+func (s *Service) GetPlan(ctx context.Context, id uuid.UUID) (*Plan, error) {
+	p, err := s.repo.GetSubscriptionPlan(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPlanNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription plan: %w", err)
+	}
+
+	result := castFromRepositoryPlan(p)
+	return &result, nil
+}
+
+// ListPlans returns all the plans, the latest first.
+func (s *Service) ListPlans(ctx context.Context) ([]Plan, error) {
+	items, err := s.repo.GetSubscriptionPlans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription plans: %w", err)
+	}
+
+	result := make([]Plan, 0, len(items))
+	for _, p := range items {
+		result = append(result, castFromRepositoryPlan(p))
+	}
+
+	return result, nil
+}
+
+// Subscribe creates a new subscription to the plan.
+// The first installment is due at the start time, the later ones every plan interval after it.
+func (s *Service) Subscribe(ctx context.Context, params SubscribeParams) (*Subscription, error) {
+	if _, err := s.GetPlan(ctx, params.PlanID); err != nil {
+		return nil, err
+	}
+
+	startAt := time.Now()
+	if params.StartAt != nil && params.StartAt.After(startAt) {
+		startAt = *params.StartAt
+	}
+
+	sub, err := s.repo.CreateSubscription(ctx, repository.CreateSubscriptionParams{
+		PlanID:        params.PlanID,
+		CustomerEmail: sql.NullString{String: params.CustomerEmail, Valid: params.CustomerEmail != ""},
+		Livemode:      params.Livemode,
+		NextBillingAt: startAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	result := castFromRepositorySubscription(sub)
+	return &result, nil
+}
+
+// Get returns the subscription by its ID.
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	result := castFromRepositorySubscription(sub)
+	return &result, nil
+}
+
+// Cancel cancels the active subscription, no more installments are created for it.
+// The payment of the current installment, if any, can still be paid.
+func (s *Service) Cancel(ctx context.Context, id uuid.UUID) (*Subscription, error) {
+	sub, err := s.repo.CancelSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, err := s.Get(ctx, id); err != nil {
+				return nil, err
+			}
+			return nil, ErrSubscriptionNotActive
+		}
+		return nil, fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+
+	result := castFromRepositorySubscription(sub)
+	return &result, nil
+}
+
+// ListInstallments returns the installments of the subscription, the latest first.
+func (s *Service) ListInstallments(ctx context.Context, subscriptionID uuid.UUID) ([]Installment, error) {
+	items, err := s.repo.GetSubscriptionInstallments(ctx, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription installments: %w", err)
+	}
+
+	result := make([]Installment, 0, len(items))
+	for _, i := range items {
+		result = append(result, castFromRepositoryInstallment(i))
+	}
+
+	return result, nil
+}
+
+// CreateDueInstallments creates the installment payments of the active subscriptions which are due
+// and fires the subscription.installment_due events.
+// The subscriptions are processed independently, the last error is returned.
+func (s *Service) CreateDueInstallments(ctx context.Context) error {
+	now := time.Now()
+	subs, err := s.repo.GetDueSubscriptions(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to get due subscriptions: %w", err)
+	}
+
+	plans := make(map[uuid.UUID]repository.SubscriptionPlan)
+	var lastErr error
+	for _, sub := range subs {
+		plan, ok := plans[sub.PlanID]
+		if !ok {
+			plan, err = s.repo.GetSubscriptionPlan(ctx, sub.PlanID)
+			if err != nil {
+				lastErr = fmt.Errorf("failed to get plan of subscription %s: %w", sub.ID, err)
+				continue
+			}
+			plans[sub.PlanID] = plan
+		}
+
+		if err := s.createInstallment(ctx, sub, plan, now); err != nil {
+			lastErr = fmt.Errorf("failed to create installment of subscription %s: %w", sub.ID, err)
+		}
+	}
+
+	return lastErr
+}
+
+// createInstallment creates the installment of the subscription due at its next billing time
+// and moves the next billing time to the next period.
+// A retried call picks up the installment and the payment of the failed one,
+// the periods missed while the scheduler wasn't running are skipped.
+func (s *Service) createInstallment(ctx context.Context, sub repository.Subscription, plan repository.SubscriptionPlan, now time.Time) error {
+	inst, err := s.repo.CreateSubscriptionInstallment(ctx, repository.CreateSubscriptionInstallmentParams{
+		SubscriptionID: sub.ID,
+		DueAt:          sub.NextBillingAt,
+	})
+	if err != nil {
+		if !repository.IsUniqueViolation(err, "") {
+			return fmt.Errorf("failed to create installment: %w", err)
+		}
+		inst, err = s.repo.GetSubscriptionInstallmentByDueAt(ctx, repository.GetSubscriptionInstallmentByDueAtParams{
+			SubscriptionID: sub.ID,
+			DueAt:          sub.NextBillingAt,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get installment: %w", err)
+		}
+	}
+
+	if !inst.PaymentID.Valid {
+		paymentID, err := s.createPayment(ctx, sub, plan, inst)
+		if err != nil {
+			return err
+		}
+		inst, err = s.repo.SetSubscriptionInstallmentPayment(ctx, repository.SetSubscriptionInstallmentPaymentParams{
+			PaymentID: uuid.NullUUID{UUID: paymentID, Valid: true},
+			ID:        inst.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to store installment payment: %w", err)
+		}
+	}
+
+	next := nextBillingAt(sub.NextBillingAt, plan)
+	for !next.After(now) {
+		next = nextBillingAt(next, plan)
+	}
+	advanced, err := s.repo.AdvanceSubscription(ctx, repository.AdvanceSubscriptionParams{
+		NextBillingAt: next,
+		ID:            sub.ID,
+		DueAt:         sub.NextBillingAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to advance subscription: %w", err)
+	}
+
+	// the concurrent call has advanced the subscription and fired the event
+	if advanced > 0 {
+		s.emit(events.SubscriptionInstallmentDue, installmentPayload(inst))
+	}
+
+	return nil
+}
+
+// createPayment creates the payment of the installment.
+// The installment ID is the external ID of the payment, so the retried call gets the same payment.
+func (s *Service) createPayment(ctx context.Context, sub repository.Subscription, plan repository.SubscriptionPlan, inst repository.SubscriptionInstallment) (uuid.UUID, error) {
+	payment, err := s.payments.CreatePayment(payments.WithLivemode(ctx, sub.Livemode), &payments.Payment{
+		ExternalID:      inst.ID.String(),
+		DestinationMint: plan.Currency,
+		Amount:          uint64(plan.Amount),
+		Message:         plan.Name,
+		CustomerEmail:   sub.CustomerEmail.String,
+	})
+	if err != nil {
+		var exists *payments.PaymentExistsError
+		if errors.As(err, &exists) {
+			return exists.PaymentID, nil
+		}
+		return uuid.Nil, fmt.Errorf("failed to create installment payment: %w", err)
+	}
+
+	return payment.ID, nil
+}
+
+// UpdateInstallmentStatus marks the due installment paid with the given payment as paid or missed
+// and fires the subscription.installment_paid or subscription.installment_missed event.
+// The payments which aren't installments and the installments which are already paid or missed are ignored.
+func (s *Service) UpdateInstallmentStatus(ctx context.Context, paymentID uuid.UUID, status string) error {
+	inst, err := s.repo.GetSubscriptionInstallmentByPaymentID(ctx, uuid.NullUUID{UUID: paymentID, Valid: true})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to get installment: %w", err)
+	}
+
+	updated, err := s.repo.UpdateSubscriptionInstallmentStatus(ctx, repository.UpdateSubscriptionInstallmentStatusParams{
+		Status: status,
+		ID:     inst.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update installment status: %w", err)
+	}
+	if updated == 0 {
+		return nil
+	}
+
+	inst.Status = status
+	switch status {
+	case InstallmentStatusPaid:
+		s.emit(events.SubscriptionInstallmentPaid, installmentPayload(inst))
+	case InstallmentStatusMissed:
+		s.emit(events.SubscriptionInstallmentMissed, installmentPayload(inst))
+	}
+
+	return nil
+}
+
+// installmentPayload returns the event payload of the installment.
+func installmentPayload(inst repository.SubscriptionInstallment) events.SubscriptionInstallmentPayload {
+	return events.SubscriptionInstallmentPayload{
+		PaymentID:      events.PaymentID{PaymentID: inst.PaymentID.UUID.String()},
+		Occurrence:     events.OccurredNow(),
+		SubscriptionID: inst.SubscriptionID.String(),
+		InstallmentID:  inst.ID.String(),
+		Status:         inst.Status,
+		DueAt:          inst.DueAt.Unix(),
+	}
+}
@@ -0,0 +1,288 @@
+package subscriptions_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/subscriptions"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	plans        []repository.SubscriptionPlan
+	subs         []repository.Subscription
+	installments []repository.SubscriptionInstallment
+}
+
+func (r *repoMock) CreateSubscriptionPlan(ctx context.Context, arg repository.CreateSubscriptionPlanParams) (repository.SubscriptionPlan, error) {
+	p := repository.SubscriptionPlan{
+		ID:              uuid.New(),
+		Name:            arg.Name,
+		Amount:          arg.Amount,
+		Currency:        arg.Currency,
+		BillingInterval: arg.BillingInterval,
+		IntervalCount:   arg.IntervalCount,
+		CreatedAt:       time.Now(),
+	}
+	r.plans = append(r.plans, p)
+	return p, nil
+}
+
+func (r *repoMock) GetSubscriptionPlan(ctx context.Context, id uuid.UUID) (repository.SubscriptionPlan, error) {
+	for _, p := range r.plans {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return repository.SubscriptionPlan{}, sql.ErrNoRows
+}
+
+func (r *repoMock) GetSubscriptionPlans(ctx context.Context) ([]repository.SubscriptionPlan, error) {
+	return r.plans, nil
+}
+
+func (r *repoMock) CreateSubscription(ctx context.Context, arg repository.CreateSubscriptionParams) (repository.Subscription, error) {
+	s := repository.Subscription{
+		ID:            uuid.New(),
+		PlanID:        arg.PlanID,
+		CustomerEmail: arg.CustomerEmail,
+		Status:        subscriptions.StatusActive,
+		Livemode:      arg.Livemode,
+		NextBillingAt: arg.NextBillingAt,
+		CreatedAt:     time.Now(),
+	}
+	r.subs = append(r.subs, s)
+	return s, nil
+}
+
+func (r *repoMock) GetSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, error) {
+	for _, s := range r.subs {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return repository.Subscription{}, sql.ErrNoRows
+}
+
+func (r *repoMock) CancelSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, error) {
+	for i, s := range r.subs {
+		if s.ID == id && s.Status == subscriptions.StatusActive {
+			s.Status = subscriptions.StatusCanceled
+			s.CanceledAt = sql.NullTime{Time: time.Now(), Valid: true}
+			r.subs[i] = s
+			return s, nil
+		}
+	}
+	return repository.Subscription{}, sql.ErrNoRows
+}
+
+func (r *repoMock) GetDueSubscriptions(ctx context.Context, now time.Time) ([]repository.Subscription, error) {
+	var result []repository.Subscription
+	for _, s := range r.subs {
+		if s.Status == subscriptions.StatusActive && !s.NextBillingAt.After(now) {
+			result = append(result, s)
+		}
+	}
+	return result, nil
+}
+
+func (r *repoMock) AdvanceSubscription(ctx context.Context, arg repository.AdvanceSubscriptionParams) (int64, error) {
+	for i, s := range r.subs {
+		if s.ID == arg.ID && s.NextBillingAt.Equal(arg.DueAt) {
+			r.subs[i].NextBillingAt = arg.NextBillingAt
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (r *repoMock) CreateSubscriptionInstallment(ctx context.Context, arg repository.CreateSubscriptionInstallmentParams) (repository.SubscriptionInstallment, error) {
+	if _, err := r.GetSubscriptionInstallmentByDueAt(ctx, repository.GetSubscriptionInstallmentByDueAtParams(arg)); err == nil {
+		return repository.SubscriptionInstallment{}, &repository.UniqueViolationError{}
+	}
+	i := repository.SubscriptionInstallment{
+		ID:             uuid.New(),
+		SubscriptionID: arg.SubscriptionID,
+		DueAt:          arg.DueAt,
+		Status:         subscriptions.InstallmentStatusDue,
+		CreatedAt:      time.Now(),
+	}
+	r.installments = append(r.installments, i)
+	return i, nil
+}
+
+func (r *repoMock) GetSubscriptionInstallmentByDueAt(ctx context.Context, arg repository.GetSubscriptionInstallmentByDueAtParams) (repository.SubscriptionInstallment, error) {
+	for _, i := range r.installments {
+		if i.SubscriptionID == arg.SubscriptionID && i.DueAt.Equal(arg.DueAt) {
+			return i, nil
+		}
+	}
+	return repository.SubscriptionInstallment{}, sql.ErrNoRows
+}
+
+func (r *repoMock) GetSubscriptionInstallmentByPaymentID(ctx context.Context, paymentID uuid.NullUUID) (repository.SubscriptionInstallment, error) {
+	for _, i := range r.installments {
+		if i.PaymentID == paymentID {
+			return i, nil
+		}
+	}
+	return repository.SubscriptionInstallment{}, sql.ErrNoRows
+}
+
+func (r *repoMock) GetSubscriptionInstallments(ctx context.Context, subscriptionID uuid.UUID) ([]repository.SubscriptionInstallment, error) {
+	var result []repository.SubscriptionInstallment
+	for _, i := range r.installments {
+		if i.SubscriptionID == subscriptionID {
+			result = append(result, i)
+		}
+	}
+	return result, nil
+}
+
+func (r *repoMock) SetSubscriptionInstallmentPayment(ctx context.Context, arg repository.SetSubscriptionInstallmentPaymentParams) (repository.SubscriptionInstallment, error) {
+	for i, inst := range r.installments {
+		if inst.ID == arg.ID {
+			r.installments[i].PaymentID = arg.PaymentID
+			return r.installments[i], nil
+		}
+	}
+	return repository.SubscriptionInstallment{}, sql.ErrNoRows
+}
+
+func (r *repoMock) UpdateSubscriptionInstallmentStatus(ctx context.Context, arg repository.UpdateSubscriptionInstallmentStatusParams) (int64, error) {
+	for i, inst := range r.installments {
+		if inst.ID == arg.ID && inst.Status == subscriptions.InstallmentStatusDue {
+			r.installments[i].Status = arg.Status
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+type paymentsMock struct {
+	created []*payments.Payment
+}
+
+func (p *paymentsMock) CreatePayment(ctx context.Context, payment *payments.Payment) (*payments.Payment, error) {
+	for _, c := range p.created {
+		if c.ExternalID == payment.ExternalID {
+			return nil, &payments.PaymentExistsError{PaymentID: c.ID}
+		}
+	}
+	payment.ID = uuid.New()
+	p.created = append(p.created, payment)
+	return payment, nil
+}
+
+func TestSubscriptionInstallments(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoMock{}
+	ps := &paymentsMock{}
+
+	var fired []events.EventName
+	svc := subscriptions.NewService(repo, ps, func(name events.EventName, payload interface{}) {
+		fired = append(fired, name)
+	})
+
+	plan, err := svc.CreatePlan(ctx, subscriptions.CreatePlanParams{
+		Name:     "Pro",
+		Amount:   1000,
+		Interval: subscriptions.IntervalMonth,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, plan.IntervalCount)
+
+	_, err = svc.CreatePlan(ctx, subscriptions.CreatePlanParams{Name: "Bad", Amount: 1000, Interval: "fortnight"})
+	require.ErrorIs(t, err, subscriptions.ErrInvalidPlan)
+
+	// the first installment is due at once
+	sub, err := svc.Subscribe(ctx, subscriptions.SubscribeParams{PlanID: plan.ID, CustomerEmail: "john@example.com"})
+	require.NoError(t, err)
+	firstDueAt := sub.NextBillingAt
+
+	require.NoError(t, svc.CreateDueInstallments(ctx))
+	require.Len(t, ps.created, 1)
+	require.EqualValues(t, 1000, ps.created[0].Amount)
+	require.Equal(t, "john@example.com", ps.created[0].CustomerEmail)
+	require.Equal(t, []events.EventName{events.SubscriptionInstallmentDue}, fired)
+
+	sub, err = svc.Get(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Equal(t, firstDueAt.AddDate(0, 1, 0), sub.NextBillingAt)
+
+	// nothing is due until the next billing time
+	require.NoError(t, svc.CreateDueInstallments(ctx))
+	require.Len(t, ps.created, 1)
+
+	installments, err := svc.ListInstallments(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Len(t, installments, 1)
+	require.Equal(t, ps.created[0].ID, *installments[0].PaymentID)
+
+	// the payment events update the installment once
+	listener := subscriptions.Listener(svc)
+	payload := events.PaymentStatusUpdatedPayload{PaymentID: events.PaymentID{PaymentID: ps.created[0].ID.String()}}
+	require.NoError(t, listener(events.PaymentSucceeded, payload))
+	require.NoError(t, listener(events.PaymentExpired, payload))
+	require.Equal(t, []events.EventName{events.SubscriptionInstallmentDue, events.SubscriptionInstallmentPaid}, fired)
+
+	installments, err = svc.ListInstallments(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Equal(t, subscriptions.InstallmentStatusPaid, installments[0].Status)
+
+	// the payments which aren't installments are ignored
+	require.NoError(t, listener(events.PaymentFailed, events.PaymentStatusUpdatedPayload{
+		PaymentID: events.PaymentID{PaymentID: uuid.NewString()},
+	}))
+
+	_, err = svc.Cancel(ctx, sub.ID)
+	require.NoError(t, err)
+	_, err = svc.Cancel(ctx, sub.ID)
+	require.ErrorIs(t, err, subscriptions.ErrSubscriptionNotActive)
+	_, err = svc.Cancel(ctx, uuid.New())
+	require.ErrorIs(t, err, subscriptions.ErrSubscriptionNotFound)
+}
+
+func TestCreateDueInstallmentsRetry(t *testing.T) {
+	ctx := context.Background()
+	repo := &repoMock{}
+	ps := &paymentsMock{}
+	svc := subscriptions.NewService(repo, ps, func(events.EventName, interface{}) {})
+
+	plan, err := svc.CreatePlan(ctx, subscriptions.CreatePlanParams{
+		Name:          "Weekly",
+		Amount:        500,
+		Interval:      subscriptions.IntervalWeek,
+		IntervalCount: 2,
+	})
+	require.NoError(t, err)
+
+	sub, err := svc.Subscribe(ctx, subscriptions.SubscribeParams{PlanID: plan.ID})
+	require.NoError(t, err)
+
+	// the installment and its payment exist, but the subscription was not advanced
+	dueAt := time.Now().AddDate(0, 0, -45)
+	repo.subs[0].NextBillingAt = dueAt
+	inst, err := repo.CreateSubscriptionInstallment(ctx, repository.CreateSubscriptionInstallmentParams{
+		SubscriptionID: sub.ID,
+		DueAt:          dueAt,
+	})
+	require.NoError(t, err)
+	ps.created = append(ps.created, &payments.Payment{ID: uuid.New(), ExternalID: inst.ID.String()})
+
+	require.NoError(t, svc.CreateDueInstallments(ctx))
+	require.Len(t, ps.created, 1)
+	require.Len(t, repo.installments, 1)
+	require.Equal(t, ps.created[0].ID, repo.installments[0].PaymentID.UUID)
+
+	// the missed periods are skipped
+	sub, err = svc.Get(ctx, sub.ID)
+	require.NoError(t, err)
+	require.Equal(t, dueAt.AddDate(0, 0, 56), sub.NextBillingAt)
+}
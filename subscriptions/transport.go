@@ -0,0 +1,145 @@
+package subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/auth"
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the subscriptions API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Post("/plans", httptransport.NewServer(
+		e.CreatePlan,
+		decodeCreatePlanRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Get("/plans", httptransport.NewServer(
+		e.ListPlans,
+		httptransport.NopRequestDecoder,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Get("/plans/{plan_id}", httptransport.NewServer(
+		e.GetPlan,
+		decodePlanIDRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/", httptransport.NewServer(
+		e.Subscribe,
+		decodeSubscribeRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Get("/{subscription_id}", httptransport.NewServer(
+		e.Get,
+		decodeSubscriptionIDRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/{subscription_id}/cancel", httptransport.NewServer(
+		e.Cancel,
+		decodeSubscriptionIDRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		return http.StatusPreconditionFailed, err
+	case errors.Is(err, ErrPlanNotFound),
+		errors.Is(err, ErrSubscriptionNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrSubscriptionNotActive):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, ErrInvalidRequest),
+		errors.Is(err, ErrInvalidPlan):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeCreatePlanRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeCreatePlanRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req CreatePlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	return req, nil
+}
+
+// decodeSubscribeRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+// The subscription mode is the mode of the authorized client.
+func decodeSubscribeRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req SubscribeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+	req.Livemode = auth.Livemode(ctx)
+
+	return req, nil
+}
+
+// decodePlanIDRequest is a transport/http.DecodeRequestFunc that decodes
+// the plan ID from the URL path.
+func decodePlanIDRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return parseID(r, "plan_id")
+}
+
+// decodeSubscriptionIDRequest is a transport/http.DecodeRequestFunc that decodes
+// the subscription ID from the URL path.
+func decodeSubscriptionIDRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	return parseID(r, "subscription_id")
+}
+
+// parseID parses the ID from the URL path parameter.
+func parseID(r *http.Request, param string) (uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParam(r, param))
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: invalid %s: %v", ErrInvalidRequest, param, err)
+	}
+	return id, nil
+}
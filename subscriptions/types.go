@@ -0,0 +1,174 @@
+package subscriptions
+
+import (
+	"context"
+	"time"
+
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// Billing intervals of the plans.
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+	IntervalYear  = "year"
+)
+
+// Subscription statuses.
+const (
+	StatusActive   = "active"
+	StatusCanceled = "canceled"
+)
+
+// Installment statuses.
+const (
+	InstallmentStatusDue    = "due"    // the payment is created, waiting for the customer
+	InstallmentStatusPaid   = "paid"   // the payment is completed
+	InstallmentStatusMissed = "missed" // the payment is expired, failed or canceled
+)
+
+type (
+	// Plan is the recurring billing plan: the amount billed every interval.
+	Plan struct {
+		ID            uuid.UUID `json:"id"`
+		Name          string    `json:"name"`
+		Amount        uint64    `json:"amount"`
+		Currency      string    `json:"currency"`       // destination mint of the installment payments
+		Interval      string    `json:"interval"`       // day, week, month or year
+		IntervalCount int       `json:"interval_count"` // e.g. 3 months
+		CreatedAt     time.Time `json:"created_at"`
+	}
+
+	// Subscription bills the plan to the customer every plan interval,
+	// each installment is paid with its own payment.
+	Subscription struct {
+		ID            uuid.UUID  `json:"id"`
+		PlanID        uuid.UUID  `json:"plan_id"`
+		CustomerEmail string     `json:"customer_email,omitempty"`
+		Status        string     `json:"status"`
+		Livemode      bool       `json:"livemode"`
+		NextBillingAt time.Time  `json:"next_billing_at"`
+		CreatedAt     time.Time  `json:"created_at"`
+		CanceledAt    *time.Time `json:"canceled_at,omitempty"`
+	}
+
+	// Installment is the billing period of the subscription and its payment.
+	Installment struct {
+		ID             uuid.UUID  `json:"id"`
+		SubscriptionID uuid.UUID  `json:"subscription_id"`
+		PaymentID      *uuid.UUID `json:"payment_id,omitempty"` // nil until the payment is created
+		DueAt          time.Time  `json:"due_at"`
+		Status         string     `json:"status"`
+		CreatedAt      time.Time  `json:"created_at"`
+	}
+
+	// CreatePlanParams defines the parameters of a new plan.
+	CreatePlanParams struct {
+		Name          string
+		Amount        uint64
+		Currency      string // merchant default mint if empty
+		Interval      string
+		IntervalCount int // 1 if zero
+	}
+
+	// SubscribeParams defines the parameters of a new subscription.
+	SubscribeParams struct {
+		PlanID        uuid.UUID
+		CustomerEmail string
+		StartAt       *time.Time // the first installment is due at once if nil
+		Livemode      bool
+	}
+
+	subscriptionRepository interface {
+		CreateSubscriptionPlan(ctx context.Context, arg repository.CreateSubscriptionPlanParams) (repository.SubscriptionPlan, error)
+		GetSubscriptionPlan(ctx context.Context, id uuid.UUID) (repository.SubscriptionPlan, error)
+		GetSubscriptionPlans(ctx context.Context) ([]repository.SubscriptionPlan, error)
+		CreateSubscription(ctx context.Context, arg repository.CreateSubscriptionParams) (repository.Subscription, error)
+		GetSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, error)
+		CancelSubscription(ctx context.Context, id uuid.UUID) (repository.Subscription, error)
+		GetDueSubscriptions(ctx context.Context, now time.Time) ([]repository.Subscription, error)
+		AdvanceSubscription(ctx context.Context, arg repository.AdvanceSubscriptionParams) (int64, error)
+		CreateSubscriptionInstallment(ctx context.Context, arg repository.CreateSubscriptionInstallmentParams) (repository.SubscriptionInstallment, error)
+		GetSubscriptionInstallmentByDueAt(ctx context.Context, arg repository.GetSubscriptionInstallmentByDueAtParams) (repository.SubscriptionInstallment, error)
+		GetSubscriptionInstallmentByPaymentID(ctx context.Context, paymentID uuid.NullUUID) (repository.SubscriptionInstallment, error)
+		GetSubscriptionInstallments(ctx context.Context, subscriptionID uuid.UUID) ([]repository.SubscriptionInstallment, error)
+		SetSubscriptionInstallmentPayment(ctx context.Context, arg repository.SetSubscriptionInstallmentPaymentParams) (repository.SubscriptionInstallment, error)
+		UpdateSubscriptionInstallmentStatus(ctx context.Context, arg repository.UpdateSubscriptionInstallmentStatusParams) (int64, error)
+	}
+
+	paymentCreator interface {
+		CreatePayment(ctx context.Context, payment *payments.Payment) (*payments.Payment, error)
+	}
+)
+
+// nextBillingAt returns the time the installment after the given one is due.
+func nextBillingAt(dueAt time.Time, plan repository.SubscriptionPlan) time.Time {
+	n := int(plan.IntervalCount)
+	switch plan.BillingInterval {
+	case IntervalDay:
+		return dueAt.AddDate(0, 0, n)
+	case IntervalWeek:
+		return dueAt.AddDate(0, 0, 7*n)
+	case IntervalMonth:
+		return dueAt.AddDate(0, n, 0)
+	default:
+		return dueAt.AddDate(n, 0, 0)
+	}
+}
+
+// validInterval reports whether the billing interval is supported.
+func validInterval(interval string) bool {
+	switch interval {
+	case IntervalDay, IntervalWeek, IntervalMonth, IntervalYear:
+		return true
+	}
+	return false
+}
+
+// castFromRepositoryPlan converts a repository subscription plan to a plan.
+func castFromRepositoryPlan(p repository.SubscriptionPlan) Plan {
+	return Plan{
+		ID:            p.ID,
+		Name:          p.Name,
+		Amount:        uint64(p.Amount),
+		Currency:      p.Currency,
+		Interval:      p.BillingInterval,
+		IntervalCount: int(p.IntervalCount),
+		CreatedAt:     p.CreatedAt,
+	}
+}
+
+// castFromRepositorySubscription converts a repository subscription to a subscription.
+func castFromRepositorySubscription(s repository.Subscription) Subscription {
+	result := Subscription{
+		ID:            s.ID,
+		PlanID:        s.PlanID,
+		CustomerEmail: s.CustomerEmail.String,
+		Status:        s.Status,
+		Livemode:      s.Livemode,
+		NextBillingAt: s.NextBillingAt,
+		CreatedAt:     s.CreatedAt,
+	}
+	if s.CanceledAt.Valid {
+		result.CanceledAt = &s.CanceledAt.Time
+	}
+	return result
+}
+
+// castFromRepositoryInstallment converts a repository subscription installment to an installment.
+func castFromRepositoryInstallment(i repository.SubscriptionInstallment) Installment {
+	result := Installment{
+		ID:             i.ID,
+		SubscriptionID: i.SubscriptionID,
+		DueAt:          i.DueAt,
+		Status:         i.Status,
+		CreatedAt:      i.CreatedAt,
+	}
+	if i.PaymentID.Valid {
+		result.PaymentID = &i.PaymentID.UUID
+	}
+	return result
+}
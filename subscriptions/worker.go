@@ -0,0 +1,37 @@
+package subscriptions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+)
+
+// Task names.
+const (
+	TaskCreateDueInstallments = "subscriptions:create_due_installments"
+)
+
+// Worker is a task handler for the subscription installments.
+type Worker struct {
+	svc *Service
+}
+
+// NewWorker creates a new subscriptions task handler.
+func NewWorker(svc *Service) *Worker {
+	return &Worker{svc: svc}
+}
+
+// Register registers task handlers for the subscription installments.
+func (w *Worker) Register(mux *asynq.ServeMux) {
+	mux.HandleFunc(TaskCreateDueInstallments, w.CreateDueInstallments)
+}
+
+// CreateDueInstallments creates the payments of the due installments.
+func (w *Worker) CreateDueInstallments(ctx context.Context, t *asynq.Task) error {
+	if err := w.svc.CreateDueInstallments(ctx); err != nil {
+		return fmt.Errorf("worker: %w", err)
+	}
+
+	return nil
+}
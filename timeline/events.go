@@ -0,0 +1,43 @@
+package timeline
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/google/uuid"
+)
+
+// Events returns the list of events the timeline listener is subscribed to:
+// all the payment events, the webhook deliveries and the reconciliation discrepancies.
+func Events() []events.EventName {
+	result := make([]events.EventName, 0, len(events.AllEvents)+2)
+	result = append(result, events.AllEvents...)
+	return append(result, events.WebhookDelivered, events.ReconciliationDiscrepancy)
+}
+
+// Listener records the payment events to the payment timeline.
+// Events without a payment ID are ignored.
+func Listener(s *Service) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		if payload == nil {
+			return nil
+		}
+
+		p, ok := payload.(events.PaymentIDGetter)
+		if !ok {
+			return nil
+		}
+
+		pid, err := uuid.Parse(p.GetPaymentID())
+		if err != nil {
+			return fmt.Errorf("failed to parse payment id: %s", err.Error())
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		return s.Record(ctx, pid, string(event), payload)
+	}
+}
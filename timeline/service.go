@@ -0,0 +1,64 @@
+// Package timeline records everything that happened to a payment,
+// so the support team can see the whole story of the payment in one place.
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// Service stores and returns the payment timelines.
+type Service struct {
+	repo timelineRepository
+}
+
+// NewService creates a new timeline service.
+func NewService(repo timelineRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Record appends the event with the given payload to the payment timeline.
+func (s *Service) Record(ctx context.Context, paymentID uuid.UUID, event string, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s payload: %w", event, err)
+	}
+
+	if err := s.repo.AddPaymentEvent(ctx, repository.AddPaymentEventParams{
+		PaymentID: paymentID,
+		Event:     event,
+		Data:      data,
+	}); err != nil {
+		return fmt.Errorf("failed to record %s event: %w", event, err)
+	}
+
+	return nil
+}
+
+// Timeline returns the events of the payment in the order they happened.
+func (s *Service) Timeline(ctx context.Context, paymentID uuid.UUID) ([]Entry, error) {
+	// distinguish a missing payment from a payment without recorded events
+	if _, err := s.repo.GetPayment(ctx, paymentID); err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	rows, err := s.repo.GetPaymentEvents(ctx, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment events: %w", err)
+	}
+
+	result := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Entry{
+			Event:     row.Event,
+			Data:      row.Data,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	return result, nil
+}
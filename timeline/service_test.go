@@ -0,0 +1,87 @@
+package timeline_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/easypmnt/checkout-api/timeline"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type repoMock struct {
+	payments map[uuid.UUID]bool
+	events   []repository.PaymentEvent
+}
+
+func (r *repoMock) GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error) {
+	if !r.payments[id] {
+		return repository.Payment{}, sql.ErrNoRows
+	}
+	return repository.Payment{ID: id}, nil
+}
+
+func (r *repoMock) AddPaymentEvent(ctx context.Context, arg repository.AddPaymentEventParams) error {
+	r.events = append(r.events, repository.PaymentEvent{
+		ID:        int64(len(r.events) + 1),
+		PaymentID: arg.PaymentID,
+		Event:     arg.Event,
+		Data:      arg.Data,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+func (r *repoMock) GetPaymentEvents(ctx context.Context, paymentID uuid.UUID) ([]repository.PaymentEvent, error) {
+	var result []repository.PaymentEvent
+	for _, e := range r.events {
+		if e.PaymentID == paymentID {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
+
+func TestTimeline(t *testing.T) {
+	pid := uuid.New()
+	repo := &repoMock{payments: map[uuid.UUID]bool{pid: true}}
+	svc := timeline.NewService(repo)
+	listener := timeline.Listener(svc)
+
+	require.NoError(t, listener(events.PaymentCreated, events.PaymentCreatedPayload{
+		PaymentID: events.PaymentID{PaymentID: pid.String()},
+	}))
+	require.NoError(t, listener(events.PaymentLinkGenerated, events.PaymentLinkGeneratedPayload{
+		PaymentID: events.PaymentID{PaymentID: pid.String()},
+		Link:      "solana:https://example.com",
+	}))
+	require.NoError(t, listener(events.WebhookDelivered, events.WebhookDeliveredPayload{
+		PaymentID: events.PaymentID{PaymentID: pid.String()},
+		Event:     string(events.PaymentCreated),
+	}))
+
+	// events without a payment ID are ignored
+	require.NoError(t, listener(events.TransactionReferenceNotification, events.ReferencePayload{Reference: "ref"}))
+	require.Error(t, listener(events.PaymentCreated, events.PaymentCreatedPayload{
+		PaymentID: events.PaymentID{PaymentID: "invalid"},
+	}))
+
+	entries, err := svc.Timeline(context.Background(), pid)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, string(events.PaymentCreated), entries[0].Event)
+	require.Equal(t, string(events.PaymentLinkGenerated), entries[1].Event)
+	require.Equal(t, string(events.WebhookDelivered), entries[2].Event)
+
+	var link events.PaymentLinkGeneratedPayload
+	require.NoError(t, json.Unmarshal(entries[1].Data, &link))
+	require.Equal(t, "solana:https://example.com", link.Link)
+
+	_, err = svc.Timeline(context.Background(), uuid.New())
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
@@ -0,0 +1,25 @@
+package timeline
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+type (
+	// Entry is a single event in the payment timeline.
+	Entry struct {
+		Event     string          `json:"event"`
+		Data      json.RawMessage `json:"data,omitempty"`
+		CreatedAt time.Time       `json:"created_at"`
+	}
+
+	timelineRepository interface {
+		GetPayment(ctx context.Context, id uuid.UUID) (repository.Payment, error)
+		AddPaymentEvent(ctx context.Context, arg repository.AddPaymentEventParams) error
+		GetPaymentEvents(ctx context.Context, paymentID uuid.UUID) ([]repository.PaymentEvent, error)
+	}
+)
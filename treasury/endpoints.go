@@ -0,0 +1,101 @@
+package treasury
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/easypmnt/checkout-api/payments"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		Withdraw          endpoint.Endpoint
+		CollectSignatures endpoint.Endpoint
+	}
+
+	// WithdrawRequest is the request type for the Withdraw method.
+	WithdrawRequest struct {
+		Destination string `json:"destination" validate:"required|solanaWallet"`
+		Mint        string `json:"mint" validate:"required"` // mint address or symbol, e.g. SOL or USDC
+		Amount      uint64 `json:"amount" validate:"required|gt:0"`
+		RequestedBy string `json:"-"`
+	}
+
+	// WithdrawResponse is the response type for the Withdraw method.
+	WithdrawResponse struct {
+		Withdrawal *Withdrawal `json:"withdrawal"`
+	}
+
+	// CollectSignaturesRequest is the request type for the CollectSignatures method.
+	CollectSignaturesRequest struct {
+		Transactions []string `json:"transactions"` // base64 encoded partially signed copies of the same transaction
+		RequestedBy  string   `json:"-"`
+	}
+
+	// CollectSignaturesResponse is the response type for the CollectSignatures method.
+	CollectSignaturesResponse struct {
+		Signatures *Signatures `json:"signatures"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		Withdraw:          makeWithdrawEndpoint(s),
+		CollectSignatures: makeCollectSignaturesEndpoint(s),
+	}
+}
+
+// makeWithdrawEndpoint returns an endpoint function for the Withdraw method.
+func makeWithdrawEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(WithdrawRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		// unknown symbols are resolved to SOL by payments.MintAddress, so reject them explicitly
+		mint := payments.MintAddress(req.Mint, payments.SOL)
+		if mint == payments.SOL && !payments.IsSOL(req.Mint) {
+			return nil, ErrMintNotAllowed
+		}
+
+		withdrawal, err := s.Withdraw(ctx, WithdrawParams{
+			Destination: req.Destination,
+			Mint:        mint,
+			Amount:      req.Amount,
+			RequestedBy: req.RequestedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return WithdrawResponse{Withdrawal: withdrawal}, nil
+	}
+}
+
+// makeCollectSignaturesEndpoint returns an endpoint function for the CollectSignatures method.
+func makeCollectSignaturesEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(CollectSignaturesRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		signatures, err := s.CollectSignatures(ctx, CollectSignaturesParams{
+			Transactions: req.Transactions,
+			RequestedBy:  req.RequestedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return CollectSignaturesResponse{Signatures: signatures}, nil
+	}
+}
@@ -0,0 +1,14 @@
+package treasury
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest         = errors.New("invalid_request")
+	ErrInvalidAmount          = errors.New("invalid_amount")
+	ErrDestinationNotAllowed  = errors.New("destination_not_allowed")
+	ErrMintNotAllowed         = errors.New("mint_not_allowed")
+	ErrAmountExceedsLimit     = errors.New("amount_exceeds_limit")
+	ErrSignerWalletMismatched = errors.New("signer_wallet_mismatched")
+	ErrInvalidTransaction     = errors.New("invalid_transaction")
+)
@@ -0,0 +1,266 @@
+package treasury
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// Service builds withdrawals of funds from the settlement wallet to whitelisted addresses.
+	Service struct {
+		sol       solanaClient
+		wallet    string
+		signer    solana.Signer
+		multisig  []string
+		whitelist map[string]struct{}
+		limits    map[string]uint64
+		log       Logger
+	}
+
+	// ServiceOption is a function that configures the treasury service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new treasury service for the given settlement wallet.
+// Withdrawals are allowed only to the whitelisted addresses and only of the mints with a configured limit.
+func NewService(sol solanaClient, wallet string, log Logger, opts ...ServiceOption) *Service {
+	if wallet == "" {
+		panic("settlement wallet address is required")
+	}
+	if log == nil {
+		panic("logger is required")
+	}
+
+	s := &Service{
+		sol:       sol,
+		wallet:    wallet,
+		whitelist: make(map[string]struct{}),
+		limits:    make(map[string]uint64),
+		log:       log,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.signer != nil && !s.isSigner(s.signer.PublicKey().ToBase58()) {
+		panic(ErrSignerWalletMismatched)
+	}
+
+	return s
+}
+
+// WithSigner sets the hot wallet signer of the settlement wallet.
+// If it's set, the withdrawal transactions are signed and sent.
+func WithSigner(signer solana.Signer) ServiceOption {
+	return func(s *Service) {
+		s.signer = signer
+	}
+}
+
+// WithMultisig sets the signers of the settlement wallet, which is an SPL multisig account.
+// The withdrawals are paid by the first signer and returned to be signed by the required number of signers.
+// If the hot wallet signer is set, it must be one of the multisig signers and co-signs the withdrawals.
+func WithMultisig(signers ...string) ServiceOption {
+	return func(s *Service) {
+		s.multisig = append(s.multisig, signers...)
+	}
+}
+
+// WithWhitelist sets the addresses allowed to withdraw funds to.
+func WithWhitelist(addrs ...string) ServiceOption {
+	return func(s *Service) {
+		for _, addr := range addrs {
+			s.whitelist[addr] = struct{}{}
+		}
+	}
+}
+
+// WithAmountLimit sets the max amount of a single withdrawal of the given mint.
+func WithAmountLimit(mint string, max uint64) ServiceOption {
+	return func(s *Service) {
+		s.limits[mint] = max
+	}
+}
+
+// Enabled returns true if there is at least one whitelisted address.
+func (s *Service) Enabled() bool {
+	return len(s.whitelist) > 0
+}
+
+// Withdraw builds a transfer from the settlement wallet to the whitelisted destination.
+// The transaction is signed and sent if the hot wallet signer is configured.
+func (s *Service) Withdraw(ctx context.Context, params WithdrawParams) (*Withdrawal, error) {
+	s.log.Infof("treasury: withdrawal of %d %s to %s requested by %s",
+		params.Amount, params.Mint, params.Destination, params.RequestedBy)
+
+	result, err := s.withdraw(ctx, params)
+	if err != nil {
+		s.log.Errorf("treasury: withdrawal of %d %s to %s requested by %s failed: %v",
+			params.Amount, params.Mint, params.Destination, params.RequestedBy, err)
+		return nil, err
+	}
+
+	if result.Signature != "" {
+		s.log.Infof("treasury: withdrawal of %d %s to %s requested by %s sent: %s",
+			params.Amount, params.Mint, params.Destination, params.RequestedBy, result.Signature)
+	} else {
+		s.log.Infof("treasury: withdrawal of %d %s to %s requested by %s built, waiting for signature",
+			params.Amount, params.Mint, params.Destination, params.RequestedBy)
+	}
+
+	return result, nil
+}
+
+func (s *Service) withdraw(ctx context.Context, params WithdrawParams) (*Withdrawal, error) {
+	if err := s.validate(params); err != nil {
+		return nil, err
+	}
+
+	feePayer := s.wallet
+	if len(s.multisig) > 0 {
+		feePayer = s.multisig[0]
+	}
+
+	builder := solana.NewTransactionBuilder(s.sol).SetFeePayer(feePayer)
+	if params.Mint == solana.NativeMint {
+		builder = builder.AddInstruction(solana.TransferSOL(solana.TransferSOLParams{
+			Sender:    s.wallet,
+			Recipient: params.Destination,
+			Amount:    params.Amount,
+		}))
+	} else {
+		builder = builder.AddInstruction(solana.TransferToken(solana.TransferTokenParam{
+			Sender:          s.wallet,
+			Recipient:       params.Destination,
+			Mint:            params.Mint,
+			Amount:          params.Amount,
+			MultisigSigners: s.multisig,
+		}))
+	}
+	if s.signer != nil {
+		builder = builder.AddExternalSigner(s.signer)
+	}
+
+	result := &Withdrawal{
+		Source:      s.wallet,
+		Destination: params.Destination,
+		Mint:        params.Mint,
+		Amount:      params.Amount,
+	}
+
+	if s.signer == nil || len(s.multisig) > 0 {
+		tx, err := builder.Build(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build withdrawal transaction: %w", err)
+		}
+		result.Transaction, result.MissingSigners, err = solana.MergeTransactionSignatures(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get missing signers: %w", err)
+		}
+		return result, nil
+	}
+
+	signature, err := solana.NewSender(s.sol).Send(ctx, builder.Build)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send withdrawal transaction: %w", err)
+	}
+	result.Signature = signature
+
+	return result, nil
+}
+
+// CollectSignatures merges the signatures of the partially signed copies of a withdrawal transaction,
+// e.g. signed separately by the multisig signers, and sends the transaction once all the signatures are collected.
+func (s *Service) CollectSignatures(ctx context.Context, params CollectSignaturesParams) (*Signatures, error) {
+	result, err := s.collectSignatures(ctx, params)
+	if err != nil {
+		s.log.Errorf("treasury: signatures of %d transaction copies submitted by %s rejected: %v",
+			len(params.Transactions), params.RequestedBy, err)
+		return nil, err
+	}
+
+	if result.Signature != "" {
+		s.log.Infof("treasury: signed transaction submitted by %s sent: %s", params.RequestedBy, result.Signature)
+	} else {
+		s.log.Infof("treasury: signatures submitted by %s merged, waiting for %s",
+			params.RequestedBy, strings.Join(result.MissingSigners, ", "))
+	}
+
+	return result, nil
+}
+
+func (s *Service) collectSignatures(ctx context.Context, params CollectSignaturesParams) (*Signatures, error) {
+	if len(params.Transactions) == 0 {
+		return nil, ErrInvalidTransaction
+	}
+
+	merged, missing, err := solana.MergeTransactionSignatures(params.Transactions...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTransaction, err.Error())
+	}
+
+	// only the settlement wallet transactions are accepted
+	tx, err := solana.DecodeTransaction(merged)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTransaction, err.Error())
+	}
+	if feePayer := tx.Message.Accounts[0].ToBase58(); feePayer != s.wallet && !s.isSigner(feePayer) {
+		return nil, fmt.Errorf("%w: unexpected fee payer %s", ErrInvalidTransaction, feePayer)
+	}
+
+	result := &Signatures{
+		Transaction:    merged,
+		MissingSigners: missing,
+	}
+	if len(missing) > 0 {
+		return result, nil
+	}
+
+	result.Signature, err = solana.NewSender(s.sol).SendSigned(ctx, merged)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send withdrawal transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// isSigner returns true if the given address can sign the settlement wallet transactions:
+// it's one of the multisig signers, or the settlement wallet itself if it's not a multisig.
+func (s *Service) isSigner(addr string) bool {
+	if len(s.multisig) == 0 {
+		return addr == s.wallet
+	}
+	for _, signer := range s.multisig {
+		if signer == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks the withdrawal against the whitelist and amount limits.
+func (s *Service) validate(params WithdrawParams) error {
+	if params.Amount == 0 {
+		return ErrInvalidAmount
+	}
+	if params.Mint == solana.NativeMint && len(s.multisig) > 0 {
+		return fmt.Errorf("%w: SOL can't be withdrawn from the multisig wallet", ErrMintNotAllowed)
+	}
+	if _, ok := s.whitelist[params.Destination]; !ok {
+		return ErrDestinationNotAllowed
+	}
+
+	limit, ok := s.limits[params.Mint]
+	if !ok {
+		return ErrMintNotAllowed
+	}
+	if params.Amount > limit {
+		return fmt.Errorf("%w: max %d", ErrAmountExceedsLimit, limit)
+	}
+
+	return nil
+}
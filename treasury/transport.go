@@ -0,0 +1,99 @@
+package treasury
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/oauth"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the treasury API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Post("/withdraw", httptransport.NewServer(
+		e.Withdraw,
+		decodeWithdrawRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/signatures", httptransport.NewServer(
+		e.CollectSignatures,
+		decodeCollectSignaturesRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, validator.ErrValidation):
+		return http.StatusPreconditionFailed, err
+	case errors.Is(err, ErrDestinationNotAllowed),
+		errors.Is(err, ErrMintNotAllowed),
+		errors.Is(err, ErrAmountExceedsLimit):
+		return http.StatusForbidden, err.Error()
+	case errors.Is(err, ErrInvalidRequest),
+		errors.Is(err, ErrInvalidAmount),
+		errors.Is(err, ErrInvalidTransaction):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeWithdrawRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeWithdrawRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req WithdrawRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	// OAuth2 client credentials, set by the auth middleware
+	req.RequestedBy, _ = ctx.Value(oauth.CredentialContext).(string)
+
+	return req, nil
+}
+
+// decodeCollectSignaturesRequest is a transport/http.DecodeRequestFunc that decodes a
+// JSON-encoded request from the HTTP request body.
+func decodeCollectSignaturesRequest(ctx context.Context, r *http.Request) (interface{}, error) {
+	var req CollectSignaturesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	// OAuth2 client credentials, set by the auth middleware
+	req.RequestedBy, _ = ctx.Value(oauth.CredentialContext).(string)
+
+	return req, nil
+}
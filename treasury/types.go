@@ -0,0 +1,58 @@
+package treasury
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// WithdrawParams defines the parameters of a withdrawal from the settlement wallet.
+	WithdrawParams struct {
+		Destination string // base58 encoded whitelisted wallet address.
+		Mint        string // base58 encoded mint address, or SOL.
+		Amount      uint64 // amount in minimal units of the mint.
+		RequestedBy string // client which requested the withdrawal, for the audit log.
+	}
+
+	// Withdrawal is a built withdrawal transaction.
+	// If the hot wallet signer is configured, the transaction is signed and sent,
+	// otherwise the unsigned transaction is returned to be signed by the wallet owner.
+	// The multisig wallet transactions are returned co-signed by the hot wallet, if any,
+	// to be signed by the rest of the multisig signers.
+	Withdrawal struct {
+		Source         string   `json:"source"`
+		Destination    string   `json:"destination"`
+		Mint           string   `json:"mint"`
+		Amount         uint64   `json:"amount"`
+		Transaction    string   `json:"transaction,omitempty"`     // base64 encoded unsigned or partially signed transaction.
+		MissingSigners []string `json:"missing_signers,omitempty"` // base58 encoded public keys of the required signers.
+		Signature      string   `json:"signature,omitempty"`       // signature of the sent transaction.
+	}
+
+	// CollectSignaturesParams defines the partially signed copies of a withdrawal transaction.
+	CollectSignaturesParams struct {
+		Transactions []string // base64 encoded copies of the same transaction signed by different signers.
+		RequestedBy  string   // client which submitted the signatures, for the audit log.
+	}
+
+	// Signatures is a withdrawal transaction with the merged signatures.
+	// It's sent once all the required signatures are collected.
+	Signatures struct {
+		Transaction    string   `json:"transaction"`               // base64 encoded merged transaction.
+		MissingSigners []string `json:"missing_signers,omitempty"` // base58 encoded public keys of the required signers.
+		Signature      string   `json:"signature,omitempty"`       // signature of the sent transaction.
+	}
+
+	solanaClient interface {
+		solana.SolanaClient
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+		GetTransactionStatus(ctx context.Context, txhash string, commitment solana.Commitment) (solana.TransactionStatus, error)
+	}
+
+	// Logger is used for the withdrawals audit log.
+	Logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
@@ -0,0 +1,181 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type (
+	// Client is a minimal HashiCorp Vault HTTP API client,
+	// which supports KV v2 secrets, dynamic secrets and lease renewal.
+	Client struct {
+		client    *http.Client
+		addr      string
+		token     string
+		namespace string
+		kvMount   string
+	}
+
+	// ClientOption is a function that can be used to configure a Vault client.
+	ClientOption func(*Client)
+
+	// Lease represents a dynamic secret lease, e.g. database credentials.
+	Lease struct {
+		ID        string                 `json:"lease_id"`
+		Duration  time.Duration          `json:"-"`
+		Renewable bool                   `json:"renewable"`
+		Data      map[string]interface{} `json:"data"`
+	}
+
+	// response is a generic Vault API response.
+	response struct {
+		LeaseID       string                 `json:"lease_id"`
+		LeaseDuration int64                  `json:"lease_duration"`
+		Renewable     bool                   `json:"renewable"`
+		Data          map[string]interface{} `json:"data"`
+		Auth          *struct {
+			LeaseDuration int64 `json:"lease_duration"`
+			Renewable     bool  `json:"renewable"`
+		} `json:"auth"`
+		Errors []string `json:"errors"`
+	}
+)
+
+// NewClient returns a new Vault client for the given address and token.
+func NewClient(addr, token string, opts ...ClientOption) *Client {
+	if addr == "" {
+		panic("vault address is required")
+	}
+	if token == "" {
+		panic("vault token is required")
+	}
+
+	c := &Client{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		addr:    strings.TrimRight(addr, "/"),
+		token:   token,
+		kvMount: "secret",
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// GetKV returns the latest version of the KV v2 secret at the given path.
+func (c *Client) GetKV(ctx context.Context, path string) (map[string]string, error) {
+	resp, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/v1/%s/data/%s", c.kvMount, strings.Trim(path, "/")), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s: %w", path, err)
+	}
+
+	data, ok := resp.Data["data"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to get secret %s: %w", path, ErrSecretNotFound)
+	}
+
+	result := make(map[string]string, len(data))
+	for k, v := range data {
+		result[k] = fmt.Sprint(v)
+	}
+
+	return result, nil
+}
+
+// GetDynamicSecret reads a dynamic secret, e.g. database/creds/{role}.
+// Returns the lease with the secret data.
+func (c *Client) GetDynamicSecret(ctx context.Context, path string) (*Lease, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/v1/"+strings.Trim(path, "/"), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dynamic secret %s: %w", path, err)
+	}
+
+	return &Lease{
+		ID:        resp.LeaseID,
+		Duration:  time.Duration(resp.LeaseDuration) * time.Second,
+		Renewable: resp.Renewable,
+		Data:      resp.Data,
+	}, nil
+}
+
+// RenewLease renews the lease with the given ID.
+// Returns the new lease duration.
+func (c *Client) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (time.Duration, error) {
+	resp, err := c.do(ctx, http.MethodPut, "/v1/sys/leases/renew", map[string]interface{}{
+		"lease_id":  leaseID,
+		"increment": int64(increment.Seconds()),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to renew lease: %w", err)
+	}
+
+	return time.Duration(resp.LeaseDuration) * time.Second, nil
+}
+
+// RenewToken renews the client token.
+// Returns the new token TTL.
+func (c *Client) RenewToken(ctx context.Context) (time.Duration, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/v1/auth/token/renew-self", map[string]interface{}{})
+	if err != nil {
+		return 0, fmt.Errorf("failed to renew token: %w", err)
+	}
+	if resp.Auth == nil || !resp.Auth.Renewable {
+		return 0, ErrLeaseNotRenew
+	}
+
+	return time.Duration(resp.Auth.LeaseDuration) * time.Second, nil
+}
+
+// do makes a request to the Vault API and decodes the response.
+func (c *Client) do(ctx context.Context, method, path string, payload interface{}) (*response, error) {
+	var body io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	req.Header.Set("Content-Type", "application/json")
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result response
+	if resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return &result, nil
+	case http.StatusNotFound:
+		return nil, ErrSecretNotFound
+	case http.StatusForbidden:
+		return nil, ErrPermissionDenied
+	}
+
+	return nil, fmt.Errorf("unexpected status code: %d: %s", resp.StatusCode, strings.Join(result.Errors, "; "))
+}
@@ -0,0 +1,28 @@
+package vault
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WithHTTPClient returns a ClientOption that configures the HTTP client used by the Vault client.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(c *Client) {
+		c.client = client
+	}
+}
+
+// WithNamespace returns a ClientOption that configures the Vault Enterprise namespace.
+func WithNamespace(namespace string) ClientOption {
+	return func(c *Client) {
+		c.namespace = strings.Trim(namespace, "/")
+	}
+}
+
+// WithKVMount returns a ClientOption that configures the mount path of the KV v2 secrets engine.
+// Default: "secret".
+func WithKVMount(mount string) ClientOption {
+	return func(c *Client) {
+		c.kvMount = strings.Trim(mount, "/")
+	}
+}
@@ -0,0 +1,10 @@
+package vault
+
+import "errors"
+
+// Predefined package errors.
+var (
+	ErrSecretNotFound   = errors.New("secret not found")
+	ErrLeaseNotRenew    = errors.New("lease is not renewable")
+	ErrPermissionDenied = errors.New("permission denied")
+)
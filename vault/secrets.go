@@ -0,0 +1,187 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Secret keys expected in the KV secret.
+const (
+	KeyDatabaseURL            = "database_url"
+	KeyOAuthSigningKey        = "oauth_signing_key"
+	KeyClientSecret           = "client_secret"
+	KeyWebhookSignatureSecret = "webhook_signature_secret"
+	KeyBonusMintAuthority     = "bonus_mint_authority"
+)
+
+type (
+	// Provider fetches application secrets from Vault and keeps
+	// dynamic database credentials and the client token alive.
+	Provider struct {
+		client      *Client
+		secretPath  string
+		dbCredsPath string
+		log         logger
+
+		mu      sync.RWMutex
+		dbLease *Lease
+	}
+
+	// Secrets is a set of application secrets.
+	// Empty fields mean that the secret is not stored in Vault.
+	Secrets struct {
+		DatabaseURL            string
+		OAuthSigningKey        string
+		ClientSecret           string
+		WebhookSignatureSecret string
+		BonusMintAuthority     string
+	}
+
+	logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
+
+// NewProvider creates a new secrets provider.
+// secretPath is a path of the KV v2 secret with static application secrets.
+// dbCredsPath is an optional path of the database secrets engine role, e.g. "database/creds/checkout-api".
+func NewProvider(client *Client, secretPath, dbCredsPath string, log logger) *Provider {
+	return &Provider{
+		client:      client,
+		secretPath:  secretPath,
+		dbCredsPath: dbCredsPath,
+		log:         log,
+	}
+}
+
+// Load fetches the secrets from Vault.
+// Must be called before DatabaseCredentials and Run.
+func (p *Provider) Load(ctx context.Context) (Secrets, error) {
+	var result Secrets
+
+	if p.secretPath != "" {
+		kv, err := p.client.GetKV(ctx, p.secretPath)
+		if err != nil {
+			return result, fmt.Errorf("vault: %w", err)
+		}
+		result = Secrets{
+			DatabaseURL:            kv[KeyDatabaseURL],
+			OAuthSigningKey:        kv[KeyOAuthSigningKey],
+			ClientSecret:           kv[KeyClientSecret],
+			WebhookSignatureSecret: kv[KeyWebhookSignatureSecret],
+			BonusMintAuthority:     kv[KeyBonusMintAuthority],
+		}
+	}
+
+	if p.dbCredsPath != "" {
+		if err := p.refreshDatabaseCredentials(ctx); err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// DatabaseCredentials returns the current dynamic database credentials.
+// Returns false if dynamic database credentials are not configured.
+func (p *Provider) DatabaseCredentials() (username, password string, ok bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.dbLease == nil {
+		return "", "", false
+	}
+
+	username, _ = p.dbLease.Data["username"].(string)
+	password, _ = p.dbLease.Data["password"].(string)
+
+	return username, password, true
+}
+
+// Run renews the client token and the database credentials lease
+// until the context is canceled. If the lease can't be renewed anymore
+// (e.g. max TTL is reached), new credentials are requested.
+func (p *Provider) Run(ctx context.Context) error {
+	for {
+		interval := p.renewInterval()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+
+		if _, err := p.client.RenewToken(ctx); err != nil && !errors.Is(err, ErrLeaseNotRenew) {
+			p.log.Errorf("vault: %s", err.Error())
+		}
+
+		if p.dbCredsPath == "" {
+			continue
+		}
+
+		if err := p.renewDatabaseCredentials(ctx); err != nil {
+			p.log.Errorf("vault: %s", err.Error())
+		}
+	}
+}
+
+// renewDatabaseCredentials renews the database credentials lease,
+// or requests new credentials if the lease is not renewable anymore.
+func (p *Provider) renewDatabaseCredentials(ctx context.Context) error {
+	p.mu.RLock()
+	lease := p.dbLease
+	p.mu.RUnlock()
+
+	if lease != nil && lease.Renewable {
+		ttl, err := p.client.RenewLease(ctx, lease.ID, lease.Duration)
+		if err == nil && ttl >= lease.Duration/2 {
+			p.mu.Lock()
+			p.dbLease.Duration = ttl
+			p.mu.Unlock()
+			return nil
+		}
+	}
+
+	p.log.Infof("vault: database credentials lease is expiring, requesting new credentials")
+
+	return p.refreshDatabaseCredentials(ctx)
+}
+
+// refreshDatabaseCredentials requests new database credentials.
+func (p *Provider) refreshDatabaseCredentials(ctx context.Context) error {
+	lease, err := p.client.GetDynamicSecret(ctx, p.dbCredsPath)
+	if err != nil {
+		return fmt.Errorf("vault: %w", err)
+	}
+
+	p.mu.Lock()
+	p.dbLease = lease
+	p.mu.Unlock()
+
+	return nil
+}
+
+// renewInterval returns the interval to renew leases: 2/3 of the lease duration,
+// but not more often than every 10 seconds and not less often than every hour.
+func (p *Provider) renewInterval() time.Duration {
+	interval := time.Hour
+
+	p.mu.RLock()
+	if p.dbLease != nil && p.dbLease.Duration > 0 {
+		interval = p.dbLease.Duration * 2 / 3
+	}
+	p.mu.RUnlock()
+
+	if interval < 10*time.Second {
+		interval = 10 * time.Second
+	}
+	if interval > time.Hour {
+		interval = time.Hour
+	}
+
+	return interval
+}
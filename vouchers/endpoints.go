@@ -0,0 +1,61 @@
+package vouchers
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/internal/validator"
+	"github.com/go-kit/kit/endpoint"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints that comprise a server.
+	Endpoints struct {
+		Issue endpoint.Endpoint
+	}
+
+	// IssueRequest is the request type for the Issue method.
+	IssueRequest struct {
+		Merchant    string `json:"merchant,omitempty" validate:"solanaWallet"` // default merchant wallet if empty
+		Recipient   string `json:"recipient" validate:"required|solanaWallet"`
+		Amount      uint64 `json:"amount" validate:"required|gt:0"`
+		RequestedBy string `json:"-"`
+	}
+
+	// IssueResponse is the response type for the Issue method.
+	IssueResponse struct {
+		Voucher *Voucher `json:"voucher"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided service.
+func MakeEndpoints(s *Service) Endpoints {
+	return Endpoints{
+		Issue: makeIssueEndpoint(s),
+	}
+}
+
+// makeIssueEndpoint returns an endpoint function for the Issue method.
+func makeIssueEndpoint(s *Service) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(IssueRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+		if v := validator.ValidateStruct(req); len(v) > 0 {
+			return nil, validator.NewValidationError(v)
+		}
+
+		voucher, err := s.Issue(ctx, IssueParams{
+			Merchant:    req.Merchant,
+			Recipient:   req.Recipient,
+			Amount:      req.Amount,
+			RequestedBy: req.RequestedBy,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return IssueResponse{Voucher: voucher}, nil
+	}
+}
@@ -0,0 +1,10 @@
+package vouchers
+
+import "errors"
+
+// Predefined errors.
+var (
+	ErrInvalidRequest       = errors.New("invalid_request")
+	ErrInvalidAmount        = errors.New("invalid_amount")
+	ErrMerchantNotSupported = errors.New("merchant_has_no_voucher_mint")
+)
@@ -0,0 +1,132 @@
+package vouchers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// Service issues prepaid voucher SPL tokens, one voucher mint per merchant wallet.
+	// The mint authority of all the voucher mints is the same signer, it also pays the transaction fees.
+	Service struct {
+		sol       solanaClient
+		authority solana.Signer
+		merchant  string
+		mints     map[string]string // merchant wallet => voucher mint
+		log       Logger
+	}
+
+	// ServiceOption is a function that configures the voucher service.
+	ServiceOption func(*Service)
+)
+
+// NewService creates a new voucher service.
+// The merchant is the default merchant wallet, used if the issuance merchant is not set.
+func NewService(sol solanaClient, authority solana.Signer, merchant string, log Logger, opts ...ServiceOption) *Service {
+	if log == nil {
+		panic("logger is required")
+	}
+
+	s := &Service{
+		sol:       sol,
+		authority: authority,
+		merchant:  merchant,
+		mints:     make(map[string]string),
+		log:       log,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if len(s.mints) > 0 && s.authority == nil {
+		panic("voucher mint authority is required")
+	}
+
+	return s
+}
+
+// WithVoucherMint sets the voucher mint of the given merchant wallet.
+func WithVoucherMint(merchant, mint string) ServiceOption {
+	return func(s *Service) {
+		s.mints[merchant] = mint
+	}
+}
+
+// Enabled returns true if there is at least one voucher mint.
+func (s *Service) Enabled() bool {
+	return len(s.mints) > 0
+}
+
+// Mints returns the voucher mints indexed by the merchant wallet.
+func (s *Service) Mints() map[string]string {
+	result := make(map[string]string, len(s.mints))
+	for merchant, mint := range s.mints {
+		result[merchant] = mint
+	}
+	return result
+}
+
+// Issue mints voucher tokens of the merchant to the recipient wallet.
+// The recipient token account is created if it doesn't exist, at the expense of the mint authority.
+func (s *Service) Issue(ctx context.Context, params IssueParams) (*Voucher, error) {
+	if params.Merchant == "" {
+		params.Merchant = s.merchant
+	}
+
+	s.log.Infof("vouchers: issuance of %d to %s for merchant %s requested by %s",
+		params.Amount, params.Recipient, params.Merchant, params.RequestedBy)
+
+	result, err := s.issue(ctx, params)
+	if err != nil {
+		s.log.Errorf("vouchers: issuance of %d to %s for merchant %s requested by %s failed: %v",
+			params.Amount, params.Recipient, params.Merchant, params.RequestedBy, err)
+		return nil, err
+	}
+
+	s.log.Infof("vouchers: issuance of %d to %s for merchant %s requested by %s sent: %s",
+		params.Amount, params.Recipient, params.Merchant, params.RequestedBy, result.Signature)
+
+	return result, nil
+}
+
+func (s *Service) issue(ctx context.Context, params IssueParams) (*Voucher, error) {
+	if params.Amount == 0 {
+		return nil, ErrInvalidAmount
+	}
+	mint, ok := s.mints[params.Merchant]
+	if !ok {
+		return nil, ErrMerchantNotSupported
+	}
+
+	authority := s.authority.PublicKey().ToBase58()
+	tx, err := solana.NewTransactionBuilder(s.sol).
+		SetFeePayer(authority).
+		AddInstruction(solana.MintFungibleToken(solana.MintFungibleTokenParams{
+			Funder:    authority,
+			Mint:      mint,
+			MintOwner: authority,
+			MintTo:    params.Recipient,
+			Amount:    params.Amount,
+		})).
+		AddExternalSigner(s.authority).
+		Build(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build voucher issuance transaction: %w", err)
+	}
+
+	signature, err := s.sol.SendTransaction(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send voucher issuance transaction: %w", err)
+	}
+
+	return &Voucher{
+		Merchant:  params.Merchant,
+		Mint:      mint,
+		Recipient: params.Recipient,
+		Amount:    params.Amount,
+		Signature: signature,
+	}, nil
+}
@@ -0,0 +1,38 @@
+package vouchers
+
+import (
+	"context"
+
+	"github.com/easypmnt/checkout-api/solana"
+)
+
+type (
+	// IssueParams defines the parameters of a voucher issuance.
+	IssueParams struct {
+		Merchant    string // base58 encoded merchant wallet address, the default merchant if empty.
+		Recipient   string // base58 encoded wallet address of the voucher holder.
+		Amount      uint64 // amount in minimal units of the voucher mint.
+		RequestedBy string // client which requested the issuance, for the audit log.
+	}
+
+	// Voucher is an issued prepaid voucher.
+	// Voucher tokens are redeemable 1:1 for the merchant payment currency at checkout.
+	Voucher struct {
+		Merchant  string `json:"merchant"`
+		Mint      string `json:"mint"`
+		Recipient string `json:"recipient"`
+		Amount    uint64 `json:"amount"`
+		Signature string `json:"signature"` // signature of the mint transaction.
+	}
+
+	solanaClient interface {
+		solana.SolanaClient
+		SendTransaction(ctx context.Context, txSource string) (string, error)
+	}
+
+	// Logger is used for the voucher issuance audit log.
+	Logger interface {
+		Infof(format string, args ...interface{})
+		Errorf(format string, args ...interface{})
+	}
+)
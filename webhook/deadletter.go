@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/hibiken/asynq"
+)
+
+// DeadLetterHandler returns an asynq error handler that emits the webhook.dead_lettered event
+// when a webhook task has exhausted all its retries.
+// Errors of other task types are ignored.
+func DeadLetterHandler(emit func(events.EventName, interface{})) asynq.ErrorHandler {
+	return asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
+		if task.Type() != TaskFireEvent {
+			return
+		}
+
+		retried, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retried < maxRetry {
+			return
+		}
+
+		var p FireEventPayload
+		_ = json.Unmarshal(task.Payload(), &p)
+		taskID, _ := asynq.GetTaskID(ctx)
+		deadLettersTotal.WithLabelValues(p.Event).Inc()
+
+		emit(events.WebhookDeadLettered, events.WebhookDeadLetteredPayload{
+			TaskID:  taskID,
+			Event:   p.Event,
+			Retried: retried,
+			Error:   err.Error(),
+		})
+	})
+}
@@ -0,0 +1,259 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+)
+
+// DefaultDestinationsCacheTTL is how long the destinations are cached by the enqueuer,
+// so the changes made by another instance apply within it.
+const DefaultDestinationsCacheTTL = 30 * time.Second
+
+// Events are the names of the events delivered to the webhooks.
+var Events = append(append([]events.EventName{}, events.AllEvents...),
+	events.DepositReceived,
+	events.ReportCompleted,
+	events.ReportFailed,
+	events.SubscriptionInstallmentDue,
+	events.SubscriptionInstallmentPaid,
+	events.SubscriptionInstallmentMissed,
+)
+
+type (
+	// Destination is the webhook registered in addition to the merchant webhook, WEBHOOK_URI.
+	// It receives only the events it's subscribed to.
+	Destination struct {
+		ID  uuid.UUID `json:"id"`
+		URL string    `json:"url"`
+		// Events are the event names or patterns: "payment.succeeded", "payment.*" or "*" for all the events.
+		Events      []string   `json:"events"`
+		Description string     `json:"description,omitempty"`
+		CreatedAt   time.Time  `json:"created_at"`
+		UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	}
+
+	// Destinations manages the webhook destinations and their event subscriptions.
+	Destinations struct {
+		repo     destinationRepository
+		checkURL func(url string) error
+		cacheTTL time.Duration
+
+		mu       sync.Mutex // guards the cache
+		cached   []Destination
+		cachedAt time.Time
+	}
+
+	// DestinationsOption is a function that configures the destinations.
+	DestinationsOption func(*Destinations)
+
+	destinationRepository interface {
+		CreateWebhookDestination(ctx context.Context, arg repository.CreateWebhookDestinationParams) (repository.WebhookDestination, error)
+		GetWebhookDestinations(ctx context.Context) ([]repository.WebhookDestination, error)
+		UpdateWebhookDestination(ctx context.Context, arg repository.UpdateWebhookDestinationParams) (repository.WebhookDestination, error)
+		DeleteWebhookDestination(ctx context.Context, id uuid.UUID) (int64, error)
+	}
+)
+
+// NewDestinations creates a new webhook destinations service.
+func NewDestinations(repo destinationRepository, opts ...DestinationsOption) *Destinations {
+	d := &Destinations{
+		repo:     repo,
+		cacheTTL: DefaultDestinationsCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// WithURLCheck configures the destinations to validate the URLs, e.g. with Service.CheckURL,
+// so the destinations not allowed by the egress policy are rejected on registration.
+func WithURLCheck(check func(url string) error) DestinationsOption {
+	return func(d *Destinations) {
+		d.checkURL = check
+	}
+}
+
+// WithDestinationsCacheTTL configures how long the destinations are cached by Subscribed.
+func WithDestinationsCacheTTL(ttl time.Duration) DestinationsOption {
+	return func(d *Destinations) {
+		d.cacheTTL = ttl
+	}
+}
+
+// List returns all the webhook destinations.
+func (d *Destinations) List(ctx context.Context) ([]Destination, error) {
+	items, err := d.repo.GetWebhookDestinations(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook destinations: %w", err)
+	}
+
+	result := make([]Destination, 0, len(items))
+	for _, item := range items {
+		result = append(result, castFromRepositoryDestination(item))
+	}
+
+	return result, nil
+}
+
+// Create registers a new webhook destination subscribed to the given events.
+func (d *Destinations) Create(ctx context.Context, url string, eventNames []string, description string) (*Destination, error) {
+	if err := d.validate(url, eventNames); err != nil {
+		return nil, err
+	}
+
+	stored, err := d.repo.CreateWebhookDestination(ctx, repository.CreateWebhookDestinationParams{
+		URL:         url,
+		Events:      strings.Join(eventNames, ","),
+		Description: description,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store webhook destination: %w", err)
+	}
+	d.resetCache()
+
+	result := castFromRepositoryDestination(stored)
+	return &result, nil
+}
+
+// Update replaces the URL, the events and the description of the webhook destination.
+func (d *Destinations) Update(ctx context.Context, id uuid.UUID, url string, eventNames []string, description string) (*Destination, error) {
+	if err := d.validate(url, eventNames); err != nil {
+		return nil, err
+	}
+
+	stored, err := d.repo.UpdateWebhookDestination(ctx, repository.UpdateWebhookDestinationParams{
+		URL:         url,
+		Events:      strings.Join(eventNames, ","),
+		Description: description,
+		ID:          id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDestinationNotFound
+		}
+		return nil, fmt.Errorf("failed to update webhook destination: %w", err)
+	}
+	d.resetCache()
+
+	result := castFromRepositoryDestination(stored)
+	return &result, nil
+}
+
+// Delete removes the webhook destination. The events which are already queued are still delivered to it.
+func (d *Destinations) Delete(ctx context.Context, id uuid.UUID) error {
+	n, err := d.repo.DeleteWebhookDestination(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook destination: %w", err)
+	}
+	if n == 0 {
+		return ErrDestinationNotFound
+	}
+	d.resetCache()
+
+	return nil
+}
+
+// Subscribed returns the URLs of the destinations subscribed to the event.
+// The destinations are cached, see WithDestinationsCacheTTL.
+func (d *Destinations) Subscribed(ctx context.Context, event string) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cached == nil || time.Since(d.cachedAt) > d.cacheTTL {
+		items, err := d.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		d.cached, d.cachedAt = items, time.Now()
+	}
+
+	var result []string
+	for _, dst := range d.cached {
+		if MatchEvent(dst.Events, event) {
+			result = append(result, dst.URL)
+		}
+	}
+
+	return result, nil
+}
+
+// validate returns ErrInvalidRequest if the destination URL or its events are not valid.
+func (d *Destinations) validate(url string, eventNames []string) error {
+	if url == "" {
+		return fmt.Errorf("%w: url is required", ErrInvalidRequest)
+	}
+	if d.checkURL != nil {
+		if err := d.checkURL(url); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+	}
+	if len(eventNames) == 0 {
+		return fmt.Errorf("%w: at least one event is required", ErrInvalidRequest)
+	}
+	if err := ValidateEvents(eventNames); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	return nil
+}
+
+func (d *Destinations) resetCache() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cached = nil
+}
+
+// MatchEvent reports whether the event matches any of the patterns:
+// the event name itself, the prefix followed by the wildcard, e.g. "payment.*", or "*".
+func MatchEvent(patterns []string, event string) bool {
+	for _, p := range patterns {
+		switch {
+		case p == "*", p == event:
+			return true
+		case strings.HasSuffix(p, ".*") && strings.HasPrefix(event, strings.TrimSuffix(p, "*")):
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateEvents returns an error if any of the patterns matches none of the webhook Events.
+func ValidateEvents(patterns []string) error {
+	for _, p := range patterns {
+		known := false
+		for _, e := range Events {
+			if MatchEvent([]string{p}, string(e)) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown event: %s", p)
+		}
+	}
+	return nil
+}
+
+// castFromRepositoryDestination converts a repository webhook destination to a destination.
+func castFromRepositoryDestination(d repository.WebhookDestination) Destination {
+	result := Destination{
+		ID:          d.ID,
+		URL:         d.URL,
+		Events:      strings.Split(d.Events, ","),
+		Description: d.Description,
+		CreatedAt:   d.CreatedAt,
+	}
+	if d.UpdatedAt.Valid {
+		result.UpdatedAt = &d.UpdatedAt.Time
+	}
+	return result
+}
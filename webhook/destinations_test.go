@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/easypmnt/checkout-api/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type destinationRepoMock struct {
+	items []repository.WebhookDestination
+	calls int
+}
+
+func (m *destinationRepoMock) CreateWebhookDestination(_ context.Context, arg repository.CreateWebhookDestinationParams) (repository.WebhookDestination, error) {
+	d := repository.WebhookDestination{
+		ID:          uuid.New(),
+		URL:         arg.URL,
+		Events:      arg.Events,
+		Description: arg.Description,
+		CreatedAt:   time.Now(),
+	}
+	m.items = append(m.items, d)
+	return d, nil
+}
+
+func (m *destinationRepoMock) GetWebhookDestinations(context.Context) ([]repository.WebhookDestination, error) {
+	m.calls++
+	return m.items, nil
+}
+
+func (m *destinationRepoMock) UpdateWebhookDestination(context.Context, repository.UpdateWebhookDestinationParams) (repository.WebhookDestination, error) {
+	return repository.WebhookDestination{}, nil
+}
+
+func (m *destinationRepoMock) DeleteWebhookDestination(context.Context, uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func TestMatchEvent(t *testing.T) {
+	require.True(t, MatchEvent([]string{"*"}, "payment.succeeded"))
+	require.True(t, MatchEvent([]string{"transaction.updated", "payment.succeeded"}, "payment.succeeded"))
+	require.True(t, MatchEvent([]string{"payment.*"}, "payment.link.generated"))
+	require.False(t, MatchEvent([]string{"payment.*"}, "payments.created"))
+	require.False(t, MatchEvent([]string{"payment.succeeded"}, "payment.failed"))
+	require.False(t, MatchEvent(nil, "payment.failed"))
+
+	require.NoError(t, ValidateEvents([]string{"payment.succeeded", "transaction.*", "report.completed", "*"}))
+	require.Error(t, ValidateEvents([]string{"payment.paid"}))
+	require.Error(t, ValidateEvents([]string{"webhook.*"}))
+}
+
+func TestDestinationsSubscribed(t *testing.T) {
+	ctx := context.Background()
+	repo := &destinationRepoMock{}
+	d := NewDestinations(repo)
+
+	_, err := d.Create(ctx, "https://a.example.com/hook", nil, "")
+	require.ErrorIs(t, err, ErrInvalidRequest)
+	_, err = d.Create(ctx, "https://a.example.com/hook", []string{"payment.paid"}, "")
+	require.ErrorIs(t, err, ErrInvalidRequest)
+
+	_, err = d.Create(ctx, "https://a.example.com/hook", []string{"payment.succeeded"}, "orders")
+	require.NoError(t, err)
+	_, err = d.Create(ctx, "https://b.example.com/hook", []string{"payment.*", "transaction.updated"}, "")
+	require.NoError(t, err)
+
+	urls, err := d.Subscribed(ctx, "payment.succeeded")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://a.example.com/hook", "https://b.example.com/hook"}, urls)
+
+	urls, err = d.Subscribed(ctx, "transaction.updated")
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://b.example.com/hook"}, urls)
+
+	urls, err = d.Subscribed(ctx, "report.completed")
+	require.NoError(t, err)
+	require.Empty(t, urls)
+
+	// the destinations are loaded once within the cache TTL
+	require.Equal(t, 1, repo.calls)
+}
@@ -0,0 +1,202 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpclient"
+)
+
+// ErrEgressDenied is returned if the webhook URL or its address is not allowed by the egress policy.
+var ErrEgressDenied = errors.New("webhook egress denied")
+
+// reservedNetworks are the non-public networks which are not covered by the net.IP methods.
+var reservedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this" network
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"64:ff9b::/96",  // NAT64, may translate to the private IPv4 addresses
+)
+
+// EgressPolicy restricts the webhook deliveries, so the webhook and callback URLs
+// can't be used to reach the internal services (SSRF).
+type EgressPolicy struct {
+	AllowedSchemes  []string // e.g. https; any scheme if empty
+	AllowedPorts    []int    // any port if empty
+	BlockPrivateIPs bool     // deny the loopback, private, link-local and other non-public addresses
+	Proxy           *url.URL // optional outbound proxy, e.g. the one with the static egress IPs
+	EgressIPs       []string // public IPs the deliveries are sent from, documented to the merchants for allowlisting
+}
+
+// CheckURL returns ErrEgressDenied if the scheme or the port of the URL is not allowed,
+// or if the host is an IP address which is not allowed.
+// The host names are checked when they're resolved, see Client.
+func (p EgressPolicy) CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: invalid url: %s", ErrEgressDenied, err.Error())
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: url has no host", ErrEgressDenied)
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if len(p.AllowedSchemes) > 0 && !containsFold(p.AllowedSchemes, scheme) {
+		return fmt.Errorf("%w: scheme %s is not allowed", ErrEgressDenied, scheme)
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch scheme {
+		case "http":
+			port = "80"
+		case "https":
+			port = "443"
+		}
+	}
+	if len(p.AllowedPorts) > 0 {
+		n, err := strconv.Atoi(port)
+		if err != nil || !containsInt(p.AllowedPorts, n) {
+			return fmt.Errorf("%w: port %s is not allowed", ErrEgressDenied, port)
+		}
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		return p.checkIP(ip)
+	}
+
+	return nil
+}
+
+// checkIP returns ErrEgressDenied if the address is not public and the private addresses are blocked.
+func (p EgressPolicy) checkIP(ip net.IP) error {
+	if p.BlockPrivateIPs && !isPublicIP(ip) {
+		return fmt.Errorf("%w: address %s is not public", ErrEgressDenied, ip)
+	}
+	return nil
+}
+
+// Client returns the HTTP client which enforces the policy.
+// Without the proxy the resolved addresses are checked right before connecting,
+// so the host names resolving to the private addresses, including the DNS rebinding, are denied.
+// With the proxy the client connects to the proxy only, so the host names are resolved and checked
+// before the request, and the proxy is expected to enforce the same restrictions.
+func (p EgressPolicy) Client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+	}
+	transport := httpclient.NewTransport()
+	transport.DialContext = dialer.DialContext
+
+	if p.Proxy != nil {
+		transport.Proxy = http.ProxyURL(p.Proxy)
+	} else {
+		transport.Proxy = nil // the environment proxy would bypass the address checks
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: invalid address %s", ErrEgressDenied, address)
+			}
+			return p.checkIP(net.ParseIP(host))
+		}
+	}
+
+	return httpclient.New("webhook",
+		httpclient.WithTimeout(timeout),
+		httpclient.WithTransport(transport),
+		// the redirects are checked as well, so the allowed host can't redirect to the internal one
+		httpclient.WithCheckRedirect(func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return p.check(req.Context(), req.URL.String())
+		}),
+	)
+}
+
+// check checks the URL before the request. With the proxy, the host name is resolved
+// and its addresses are checked as well, since the proxy connects to the host instead of the client.
+func (p EgressPolicy) check(ctx context.Context, rawURL string) error {
+	if err := p.CheckURL(rawURL); err != nil {
+		return err
+	}
+	if p.Proxy == nil || !p.BlockPrivateIPs {
+		return nil
+	}
+
+	u, _ := url.Parse(rawURL) // parsed by CheckURL
+	if net.ParseIP(u.Hostname()) != nil {
+		return nil
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook host: %w", err)
+	}
+	for _, addr := range addrs {
+		if err := p.checkIP(addr.IP); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isPublicIP reports whether the address is a public unicast one.
+func isPublicIP(ip net.IP) bool {
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() {
+		return false
+	}
+	for _, n := range reservedNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	result := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		result = append(result, n)
+	}
+	return result
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsInt(list []int, n int) bool {
+	for _, item := range list {
+		if item == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressPolicyCheckURL(t *testing.T) {
+	p := EgressPolicy{
+		AllowedSchemes:  []string{"https"},
+		AllowedPorts:    []int{443, 8443},
+		BlockPrivateIPs: true,
+	}
+
+	require.NoError(t, p.CheckURL("https://hooks.example.com/webhook"))
+	require.NoError(t, p.CheckURL("https://hooks.example.com:8443/webhook"))
+	require.NoError(t, p.CheckURL("https://93.184.216.34/webhook"))
+
+	for _, u := range []string{
+		"http://hooks.example.com/webhook",
+		"https://hooks.example.com:22/webhook",
+		"https://127.0.0.1/webhook",
+		"https://10.0.0.1/webhook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://[::1]/webhook",
+		"https://[::ffff:192.168.0.1]/webhook",
+		"https://100.64.0.1/webhook",
+		"/webhook",
+	} {
+		require.ErrorIs(t, p.CheckURL(u), ErrEgressDenied, u)
+	}
+
+	// no restrictions
+	require.NoError(t, EgressPolicy{}.CheckURL("http://127.0.0.1:3000/webhook"))
+}
+
+func TestEgressPolicyClient(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// the host name resolves to the loopback address
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	_, port, err := net.SplitHostPort(u.Host)
+	require.NoError(t, err)
+	target := "http://localhost:" + port + "/webhook"
+
+	_, err = EgressPolicy{BlockPrivateIPs: true}.Client(time.Second).Post(target, ContentTypeJSON, nil)
+	require.True(t, errors.Is(err, ErrEgressDenied), err)
+	require.Zero(t, requests)
+
+	resp, err := EgressPolicy{}.Client(time.Second).Post(target, ContentTypeJSON, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, 1, requests)
+}
+
+func TestEgressPolicyRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "ftp://internal/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	_, err := EgressPolicy{AllowedSchemes: []string{"http"}}.Client(time.Second).Get(srv.URL)
+	require.ErrorIs(t, err, ErrEgressDenied)
+}
+
+func TestSendEgressDenied(t *testing.T) {
+	s := NewService(WithEgressPolicy(EgressPolicy{AllowedSchemes: []string{"https"}}))
+
+	_, err := s.Send("evt", "http://hooks.example.com/webhook", map[string]string{"event": "ping"})
+	require.ErrorIs(t, err, ErrEgressDenied)
+	require.ErrorIs(t, s.CheckURL("http://hooks.example.com/webhook"), ErrEgressDenied)
+}
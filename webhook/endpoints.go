@@ -0,0 +1,114 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/go-kit/kit/endpoint"
+	"github.com/google/uuid"
+)
+
+type (
+	// Endpoints is a collection of all the endpoints of the webhook destinations API.
+	Endpoints struct {
+		ListDestinations  endpoint.Endpoint
+		CreateDestination endpoint.Endpoint
+		UpdateDestination endpoint.Endpoint
+		DeleteDestination endpoint.Endpoint
+	}
+
+	// DestinationRequest is the request type for the CreateDestination and UpdateDestination methods.
+	DestinationRequest struct {
+		ID          uuid.UUID `json:"-"`
+		URL         string    `json:"url"`
+		Events      []string  `json:"events"`
+		Description string    `json:"description,omitempty"`
+	}
+
+	// DeleteDestinationRequest is the request type for the DeleteDestination method.
+	DeleteDestinationRequest struct {
+		ID uuid.UUID
+	}
+
+	// ListDestinationsResponse is the response type for the ListDestinations method.
+	ListDestinationsResponse struct {
+		Destinations []Destination `json:"destinations"`
+	}
+
+	// DestinationResponse is the response type for the CreateDestination and UpdateDestination methods.
+	DestinationResponse struct {
+		Destination *Destination `json:"destination"`
+	}
+)
+
+// MakeEndpoints returns an Endpoints struct where each endpoint invokes
+// the corresponding method on the provided destinations service.
+func MakeEndpoints(d *Destinations) Endpoints {
+	return Endpoints{
+		ListDestinations:  makeListDestinationsEndpoint(d),
+		CreateDestination: makeCreateDestinationEndpoint(d),
+		UpdateDestination: makeUpdateDestinationEndpoint(d),
+		DeleteDestination: makeDeleteDestinationEndpoint(d),
+	}
+}
+
+// makeListDestinationsEndpoint returns an endpoint function for the List method.
+func makeListDestinationsEndpoint(d *Destinations) endpoint.Endpoint {
+	return func(ctx context.Context, _ interface{}) (interface{}, error) {
+		result, err := d.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return ListDestinationsResponse{Destinations: result}, nil
+	}
+}
+
+// makeCreateDestinationEndpoint returns an endpoint function for the Create method.
+func makeCreateDestinationEndpoint(d *Destinations) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DestinationRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		result, err := d.Create(ctx, req.URL, req.Events, req.Description)
+		if err != nil {
+			return nil, err
+		}
+
+		return DestinationResponse{Destination: result}, nil
+	}
+}
+
+// makeUpdateDestinationEndpoint returns an endpoint function for the Update method.
+func makeUpdateDestinationEndpoint(d *Destinations) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DestinationRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		result, err := d.Update(ctx, req.ID, req.URL, req.Events, req.Description)
+		if err != nil {
+			return nil, err
+		}
+
+		return DestinationResponse{Destination: result}, nil
+	}
+}
+
+// makeDeleteDestinationEndpoint returns an endpoint function for the Delete method.
+func makeDeleteDestinationEndpoint(d *Destinations) endpoint.Endpoint {
+	return func(ctx context.Context, request interface{}) (interface{}, error) {
+		req, ok := request.(DeleteDestinationRequest)
+		if !ok {
+			return nil, ErrInvalidRequest
+		}
+
+		if err := d.Delete(ctx, req.ID); err != nil {
+			return nil, err
+		}
+
+		return true, nil
+	}
+}
@@ -2,6 +2,8 @@ package webhook
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -16,10 +18,17 @@ type (
 		queueName    string
 		taskDeadline time.Duration
 		maxRetry     int
+		events       []string // events of the merchant webhook, all if empty
+		destinations destinationSource
 	}
 
 	// EnqueuerOption is a function that configures an enqueuer.
 	EnqueuerOption func(*Enqueuer)
+
+	// destinationSource returns the URLs of the webhook destinations subscribed to the event, e.g. Destinations.
+	destinationSource interface {
+		Subscribed(ctx context.Context, event string) ([]string, error)
+	}
 )
 
 // NewEnqueuer creates a new email enqueuer.
@@ -69,6 +78,22 @@ func WithMaxRetry(n int) EnqueuerOption {
 	}
 }
 
+// WithWebhookEvents configures the events fired to the merchant webhook, WEBHOOK_URI:
+// the event names or patterns, see MatchEvent. All the events are fired if none are given.
+func WithWebhookEvents(patterns []string) EnqueuerOption {
+	return func(e *Enqueuer) {
+		e.events = patterns
+	}
+}
+
+// WithDestinations configures the enqueuer to fire the events to the subscribed webhook destinations
+// in addition to the merchant webhook.
+func WithDestinations(d destinationSource) EnqueuerOption {
+	return func(e *Enqueuer) {
+		e.destinations = d
+	}
+}
+
 // enqueueTask enqueues a task to the queue.
 func (e *Enqueuer) enqueueTask(ctx context.Context, task *asynq.Task) error {
 	if _, err := e.client.Enqueue(
@@ -84,12 +109,72 @@ func (e *Enqueuer) enqueueTask(ctx context.Context, task *asynq.Task) error {
 	return nil
 }
 
-// FireEvent enqueues a task to fire an event.
-// This function returns an error if the task could not be enqueued.
+// FireEvent enqueues the tasks to fire an event to the merchant webhook and to the webhook destinations,
+// each one subscribed to the event gets its own task, so a failing destination doesn't delay the others.
+// This function returns an error if any of the tasks could not be enqueued.
 func (e *Enqueuer) FireEvent(ctx context.Context, event string, payload interface{}) error {
-	fmt.Println("FireEvent", event, fmt.Sprintf("%+v", payload))
+	// the destinations get the same event ID, so it identifies the event rather than the delivery
+	eventID, err := EventID(event, payload)
+	if err != nil {
+		return err
+	}
+
+	var result error
+	if len(e.events) == 0 || MatchEvent(e.events, event) {
+		result = e.enqueueEvent(ctx, eventID, event, "", payload)
+	}
+	if e.destinations == nil {
+		return result
+	}
+
+	urls, err := e.destinations.Subscribed(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to get webhook destinations: %w", err)
+	}
+	for _, url := range urls {
+		if err := e.enqueueEvent(ctx, eventID, event, url, payload); err != nil && result == nil {
+			result = err
+		}
+	}
+
+	return result
+}
+
+// FireEventTo enqueues a task to fire an event to the given URL, e.g. the payment callback URL.
+// The event is fired to the merchant webhook if the URL is empty.
+func (e *Enqueuer) FireEventTo(ctx context.Context, event, url string, payload interface{}) error {
+	eventID, err := EventID(event, payload)
+	if err != nil {
+		return err
+	}
+	return e.enqueueEvent(ctx, eventID, event, url, payload)
+}
+
+// EventID returns the ID of the event derived from its name and payload, so the same occurrence
+// of the event delivered twice, e.g. republished by the outbox or redelivered by the bus, gets the same ID
+// and is enqueued once within the task deadline, see asynq.Unique, and can be deduplicated by the receiver after it.
+// The payloads carry the time of the occurrence, see events.Occurrence, so the event which occurs again
+// with the same data, e.g. the payment link generated twice, gets a new ID.
+func EventID(event string, payload interface{}) (string, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(event))
+	h.Write([]byte{0})
+	h.Write(b)
+
+	return "evt_" + hex.EncodeToString(h.Sum(nil)[:16]), nil
+}
+
+// enqueueEvent enqueues a task to fire the event with the given ID to the URL.
+func (e *Enqueuer) enqueueEvent(ctx context.Context, eventID, event, url string, payload interface{}) error {
 	task, err := json.Marshal(FireEventPayload{
 		Event:   event,
+		EventID: eventID,
+		URL:     url,
 		Payload: payload,
 	})
 	if err != nil {
@@ -0,0 +1,15 @@
+package webhook
+
+import "errors"
+
+// Predefined errors of the webhook destinations API.
+var (
+	ErrInvalidRequest      = errors.New("invalid_request")
+	ErrDestinationNotFound = errors.New("webhook_destination_not_found")
+)
+
+// Errors of the webhook signature verification, see VerifySignature.
+var (
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrSignatureExpired = errors.New("webhook signature timestamp is outside the tolerance window")
+)
@@ -2,13 +2,23 @@ package webhook
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/easypmnt/checkout-api/events"
 )
 
-type webhookEnqueuer interface {
-	FireEvent(ctx context.Context, event string, payload interface{}) error
-}
+type (
+	webhookEnqueuer interface {
+		FireEvent(ctx context.Context, event string, payload interface{}) error
+	}
+
+	callbackEnqueuer interface {
+		FireEventTo(ctx context.Context, event, url string, payload interface{}) error
+	}
+
+	// CallbackResolver returns the callback URL of the payment with the given ID, empty if it has none.
+	CallbackResolver func(paymentID string) (string, error)
+)
 
 // TranslateEventsToWebhookEvents translates the events from the events package to the webhook events.
 func TranslateEventsToWebhookEvents(enq webhookEnqueuer) events.Listener {
@@ -20,3 +30,25 @@ func TranslateEventsToWebhookEvents(enq webhookEnqueuer) events.Listener {
 		return enq.FireEvent(context.Background(), string(event), payload)
 	}
 }
+
+// TranslateEventsToCallbacks translates the payment events to the webhook events
+// fired to the callback URL of the payment, if it has one.
+// The merchant webhook receives the events via TranslateEventsToWebhookEvents as usual.
+func TranslateEventsToCallbacks(enq callbackEnqueuer, resolve CallbackResolver) events.Listener {
+	return func(event events.EventName, payload interface{}) error {
+		pid := paymentIDOf(payload)
+		if pid == "" {
+			return nil
+		}
+
+		url, err := resolve(pid)
+		if err != nil {
+			return fmt.Errorf("failed to resolve payment callback url: %w", err)
+		}
+		if url == "" {
+			return nil
+		}
+
+		return enq.FireEventTo(context.Background(), string(event), url, payload)
+	}
+}
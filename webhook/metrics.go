@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/easypmnt/checkout-api/internal/metrics"
+)
+
+// Delivery results.
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// Webhook delivery metrics.
+// The per-endpoint failure rate is the ratio of the failed deliveries to all deliveries, e.g.:
+// sum by (endpoint) (rate(webhook_deliveries_total{result="failure"}[5m])) / sum by (endpoint) (rate(webhook_deliveries_total[5m]))
+var (
+	deliveriesTotal = metrics.NewCounterVec(
+		"webhook_deliveries_total",
+		"Total number of webhook delivery attempts.",
+		"endpoint", "event", "result",
+	)
+	deliveryDuration = metrics.NewHistogramVec(
+		"webhook_delivery_duration_seconds",
+		"Duration of webhook delivery attempts in seconds.",
+		nil,
+		"endpoint",
+	)
+	retriesTotal = metrics.NewCounterVec(
+		"webhook_retries_total",
+		"Total number of webhook delivery retries.",
+		"event",
+	)
+	deadLettersTotal = metrics.NewCounterVec(
+		"webhook_dead_letters_total",
+		"Total number of webhooks which exhausted all delivery retries.",
+		"event",
+	)
+)
+
+// observeDelivery records the webhook delivery attempt.
+func observeDelivery(uri, event string, start time.Time, err error) {
+	endpoint := endpointLabel(uri)
+	result := resultSuccess
+	if err != nil {
+		result = resultFailure
+	}
+
+	deliveriesTotal.WithLabelValues(endpoint, event, result).Inc()
+	deliveryDuration.WithLabelValues(endpoint).Observe(time.Since(start).Seconds())
+}
+
+// endpointLabel returns the host of the webhook URI to keep the label cardinality low
+// and to not expose any secrets from the path or query.
+func endpointLabel(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
@@ -2,11 +2,16 @@ package webhook
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/easypmnt/checkout-api/internal/httpclient"
+	"github.com/google/uuid"
 )
 
 type (
@@ -15,19 +20,35 @@ type (
 		client          *http.Client
 		signatureHeader string
 		signatureSecret []byte
-		webhookURI      string
+		explorer        explorer
+		livemode        LivemodeResolver
+		egress          EgressPolicy
+
+		mu         sync.RWMutex // guards webhookURI, see SetWebhookURI
+		webhookURI string
+	}
+
+	// LivemodeResolver returns the mode of the payment with the given ID,
+	// false for the test-mode payments.
+	LivemodeResolver func(paymentID string) (bool, error)
+
+	// explorer builds the block explorer URLs of the transactions, e.g. solana.Explorer.
+	explorer interface {
+		TransactionURL(signature string) string
 	}
 
 	// ServiceOption is a function that configures the webhook service.
 	ServiceOption func(*Service)
 )
 
+// DefaultTimeout is the timeout of the webhook requests.
+const DefaultTimeout = 10 * time.Second
+
 // NewService creates a new webhook service.
 func NewService(opts ...ServiceOption) *Service {
 	s := &Service{
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		// the failed deliveries are retried by the worker, so the client doesn't retry them
+		client:          httpclient.New("webhook", httpclient.WithTimeout(DefaultTimeout)),
 		signatureHeader: DefaultSignatureHeader,
 	}
 
@@ -45,6 +66,15 @@ func WithHTTPClient(client *http.Client) ServiceOption {
 	}
 }
 
+// WithEgressPolicy configures the webhook service to restrict the deliveries by the egress policy.
+// It replaces the HTTP client with the one enforcing the policy.
+func WithEgressPolicy(p EgressPolicy) ServiceOption {
+	return func(s *Service) {
+		s.egress = p
+		s.client = p.Client(DefaultTimeout)
+	}
+}
+
 // WithSignatureHeader configures the webhook service with a custom signature header.
 func WithSignatureHeader(header string) ServiceOption {
 	return func(s *Service) {
@@ -66,14 +96,54 @@ func WithWebhookURI(uri string) ServiceOption {
 	}
 }
 
-// Send post request to webhook url with payload.
-func (s *Service) Send(url string, payload interface{}) (*http.Response, error) {
+// WithExplorer configures the webhook service to add the block explorer URL
+// of the transaction signature to the event payloads as the explorer_url field.
+func WithExplorer(e explorer) ServiceOption {
+	return func(s *Service) {
+		s.explorer = e
+	}
+}
+
+// WithLivemodeResolver configures the webhook service to tag the payment events with the mode of the payment.
+// All events are live-mode ones without the resolver.
+func WithLivemodeResolver(resolve LivemodeResolver) ServiceOption {
+	return func(s *Service) {
+		s.livemode = resolve
+	}
+}
+
+// SetWebhookURI replaces the webhook URI, e.g. on the config reload.
+// The events which are already queued are sent to the new URI.
+func (s *Service) SetWebhookURI(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhookURI = uri
+}
+
+// CheckURL returns ErrEgressDenied if the webhook URL is not allowed by the egress policy,
+// e.g. to validate the webhook URI on the config reload.
+func (s *Service) CheckURL(url string) error {
+	return s.egress.CheckURL(url)
+}
+
+// EgressIPs returns the public IPs the webhooks are sent from, if they're configured.
+func (s *Service) EgressIPs() []string {
+	return s.egress.EgressIPs
+}
+
+// Send post request to webhook url with payload, signed along with the event ID
+// and the current time, see SignPayload.
+func (s *Service) Send(eventID, url string, payload interface{}) (*http.Response, error) {
+	if err := s.egress.check(context.Background(), url); err != nil {
+		return nil, err
+	}
+
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal webhook payload: %w", err)
 	}
 
-	signature, err := SignPayload(body, s.signatureSecret)
+	signature, err := SignPayload(body, eventID, time.Now(), s.signatureSecret)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign webhook payload: %w", err)
 	}
@@ -94,24 +164,49 @@ func (s *Service) Send(url string, payload interface{}) (*http.Response, error)
 }
 
 // FireEvent sends a webhook event to the webhook url.
-func (s *Service) FireEvent(event string, payload interface{}) error {
-	if s.webhookURI == "" {
+// The event ID must be the same for all the delivery attempts, so the receiver can deduplicate them;
+// a new one is generated if it's empty.
+func (s *Service) FireEvent(eventID, event string, payload interface{}) error {
+	s.mu.RLock()
+	uri := s.webhookURI
+	s.mu.RUnlock()
+
+	if uri == "" {
 		return fmt.Errorf("webhook uri is not set")
 	}
 
-	return s.fireEvent(event, s.webhookURI, payload)
+	return s.fireEvent(eventID, event, uri, payload)
+}
+
+// FireEventTo sends a webhook event to the given url, e.g. the payment callback url.
+func (s *Service) FireEventTo(eventID, event, url string, payload interface{}) error {
+	return s.fireEvent(eventID, event, url, payload)
 }
 
 // fireEvent sends a webhook event to the webhook url.
-func (s *Service) fireEvent(event, url string, payload interface{}) error {
+func (s *Service) fireEvent(eventID, event, url string, payload interface{}) (err error) {
+	defer func(start time.Time) { observeDelivery(url, event, start, err) }(time.Now())
+
+	livemode, err := s.livemodeOf(payload)
+	if err != nil {
+		return fmt.Errorf("failed to resolve payment mode: %w", err)
+	}
+
+	if eventID == "" {
+		eventID = uuid.NewString()
+	}
+
 	reqData := WebhookRequestPayload{
-		Event: event,
-		Data:  payload,
+		Event:    event,
+		EventID:  eventID,
+		Livemode: livemode,
+		Data:     s.withExplorerURL(payload),
 	}
-	resp, err := s.Send(url, reqData)
+	resp, err := s.Send(eventID, url, reqData)
 	if err != nil {
 		return fmt.Errorf("failed to send webhook event: %w", err)
 	}
+	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("failed to send webhook event: %s", resp.Status)
@@ -119,3 +214,51 @@ func (s *Service) fireEvent(event, url string, payload interface{}) error {
 
 	return nil
 }
+
+// withExplorerURL returns the event payload with the block explorer URL of its transaction signature.
+// The payload is returned as is if it has no signature or the explorer is not configured.
+func (s *Service) withExplorerURL(payload interface{}) interface{} {
+	if s.explorer == nil {
+		return payload
+	}
+
+	// the payload is decoded into a map by the queue worker, otherwise it's an event struct
+	data, ok := payload.(map[string]interface{})
+	if !ok {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return payload
+		}
+		if err := json.Unmarshal(b, &data); err != nil {
+			return payload
+		}
+	}
+
+	signature, _ := data["signature"].(string)
+	explorerURL := s.explorer.TransactionURL(signature)
+	if explorerURL == "" {
+		return payload
+	}
+
+	result := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		result[k] = v
+	}
+	result["explorer_url"] = explorerURL
+
+	return result
+}
+
+// livemodeOf returns the mode of the event payment. The events without payment are live-mode ones.
+func (s *Service) livemodeOf(payload interface{}) (bool, error) {
+	if s.livemode == nil {
+		return true, nil
+	}
+
+	pid := paymentIDFrom(payload)
+	if pid == "" {
+		return true, nil
+	}
+
+	return s.livemode(pid)
+}
@@ -0,0 +1,99 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/easypmnt/checkout-api/events"
+	"github.com/stretchr/testify/require"
+)
+
+type explorerMock struct{}
+
+func (explorerMock) TransactionURL(signature string) string {
+	if signature == "" {
+		return ""
+	}
+	return "https://explorer.example.com/tx/" + signature
+}
+
+func TestWithExplorerURL(t *testing.T) {
+	s := NewService(WithExplorer(explorerMock{}))
+
+	// decoded payload
+	payload := map[string]interface{}{"payment_id": "pid", "signature": "sig"}
+	result := s.withExplorerURL(payload).(map[string]interface{})
+	require.Equal(t, "https://explorer.example.com/tx/sig", result["explorer_url"])
+	require.NotContains(t, payload, "explorer_url")
+
+	// event struct
+	result = s.withExplorerURL(events.TransactionUpdatedPayload{
+		PaymentID: events.PaymentID{PaymentID: "pid"},
+		Reference: "ref",
+		Signature: "sig",
+	}).(map[string]interface{})
+	require.Equal(t, "https://explorer.example.com/tx/sig", result["explorer_url"])
+	require.Equal(t, "ref", result["reference"])
+
+	// no signature
+	created := events.PaymentCreatedPayload{PaymentID: events.PaymentID{PaymentID: "pid"}}
+	require.Equal(t, created, s.withExplorerURL(created))
+
+	// explorer is not configured
+	require.Equal(t, payload, NewService().withExplorerURL(payload))
+}
+
+func TestLivemodeOf(t *testing.T) {
+	s := NewService(WithLivemodeResolver(func(paymentID string) (bool, error) {
+		return paymentID != "test", nil
+	}))
+
+	livemode, err := s.livemodeOf(map[string]interface{}{"payment_id": "test"})
+	require.NoError(t, err)
+	require.False(t, livemode)
+
+	livemode, err = s.livemodeOf(map[string]interface{}{"payment_id": "pid"})
+	require.NoError(t, err)
+	require.True(t, livemode)
+
+	// no payment
+	livemode, err = s.livemodeOf(map[string]interface{}{"event": "ping"})
+	require.NoError(t, err)
+	require.True(t, livemode)
+
+	// resolver is not configured
+	livemode, err = NewService().livemodeOf(map[string]interface{}{"payment_id": "test"})
+	require.NoError(t, err)
+	require.True(t, livemode)
+}
+
+type callbackEnqueuerMock struct {
+	urls []string
+}
+
+func (e *callbackEnqueuerMock) FireEventTo(ctx context.Context, event, url string, payload interface{}) error {
+	e.urls = append(e.urls, url)
+	return nil
+}
+
+func TestTranslateEventsToCallbacks(t *testing.T) {
+	enq := &callbackEnqueuerMock{}
+	listener := TranslateEventsToCallbacks(enq, func(paymentID string) (string, error) {
+		if paymentID == "pid" {
+			return "https://hooks.example.com/orders/1", nil
+		}
+		return "", nil
+	})
+
+	// event struct of the payment with the callback url
+	require.NoError(t, listener(events.PaymentCreated, events.PaymentCreatedPayload{
+		PaymentID: events.PaymentID{PaymentID: "pid"},
+	}))
+	require.Equal(t, []string{"https://hooks.example.com/orders/1"}, enq.urls)
+
+	// payment without the callback url
+	require.NoError(t, listener(events.PaymentCreated, map[string]interface{}{"payment_id": "other"}))
+	// not a payment event
+	require.NoError(t, listener(events.DepositReceived, map[string]interface{}{"signature": "sig"}))
+	require.Len(t, enq.urls, 1)
+}
@@ -4,38 +4,117 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
-	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/easypmnt/checkout-api/internal/utils"
 )
 
-// SignPayload signs a payload using a secret key and returns the signature as a base64 encoded string
-func SignPayload(payload []byte, secretKey []byte) (string, error) {
-	hash := hmac.New(sha256.New, secretKey)
-	if _, err := hash.Write(payload); err != nil {
-		return "", fmt.Errorf("failed to write payload to hash: %w", err)
+// DefaultSignatureTolerance is the maximum age of the signed webhook accepted by VerifySignature.
+// The receivers reject the older calls, so a captured request can't be replayed later,
+// and the event ID is unique, so the calls replayed within the window can be deduplicated by it.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// signatureVersion is the scheme of the signature in the signature header.
+const signatureVersion = "v1"
+
+// SignPayload signs the webhook body along with the event ID and the timestamp of the delivery
+// and returns the value of the signature header: "t=<unix timestamp>,id=<event ID>,v1=<signature>",
+// where the signature is the base64-encoded HMAC-SHA256 of "<unix timestamp>.<event ID>.<body>".
+func SignPayload(payload []byte, eventID string, timestamp time.Time, secretKey []byte) (string, error) {
+	if strings.ContainsAny(eventID, ",=") {
+		return "", fmt.Errorf("invalid event id: %s", eventID)
 	}
 
-	return utils.BytesToBase64(hash.Sum(nil)), nil
+	signature, err := sign(payload, eventID, timestamp.Unix(), secretKey)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("t=%d,id=%s,%s=%s", timestamp.Unix(), eventID, signatureVersion, utils.BytesToBase64(signature)), nil
 }
 
-// VerifySignature verifies a signature against a payload using a secret key
-func VerifySignature(payload []byte, signature string, secretKey []byte) error {
-	expectedSignature, err := base64.StdEncoding.DecodeString(signature)
+// VerifySignature verifies the signature header of the webhook against its raw body using the secret key,
+// and returns the event ID. The webhooks signed earlier than the tolerance ago, or later than the tolerance
+// from now, are rejected with ErrSignatureExpired; the DefaultSignatureTolerance is used if it's not positive.
+// The merchant backends are expected to call it before decoding the body, e.g.:
+//
+//	body, _ := io.ReadAll(r.Body)
+//	eventID, err := webhook.VerifySignature(body, r.Header.Get(webhook.DefaultSignatureHeader), secret, 0)
+//	if err != nil {
+//		w.WriteHeader(http.StatusBadRequest)
+//		return
+//	}
+//	// skip the event if eventID is already processed
+func VerifySignature(payload []byte, header string, secretKey []byte, tolerance time.Duration) (string, error) {
+	timestamp, eventID, signatures, err := parseSignatureHeader(header)
 	if err != nil {
-		return fmt.Errorf("failed to decode signature: %w", err)
+		return "", err
 	}
 
+	if tolerance <= 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	if age := time.Since(time.Unix(timestamp, 0)); age > tolerance || age < -tolerance {
+		return "", ErrSignatureExpired
+	}
+
+	expected, err := sign(payload, eventID, timestamp, secretKey)
+	if err != nil {
+		return "", err
+	}
+	// the header may carry several signatures, e.g. by each one of the rotated secrets
+	for _, s := range signatures {
+		if hmac.Equal(expected, s) {
+			return eventID, nil
+		}
+	}
+
+	return "", ErrInvalidSignature
+}
+
+// sign returns the HMAC-SHA256 of the signed content of the webhook.
+func sign(payload []byte, eventID string, timestamp int64, secretKey []byte) ([]byte, error) {
 	hash := hmac.New(sha256.New, secretKey)
+	if _, err := fmt.Fprintf(hash, "%d.%s.", timestamp, eventID); err != nil {
+		return nil, fmt.Errorf("failed to write signed content to hash: %w", err)
+	}
 	if _, err := hash.Write(payload); err != nil {
-		return fmt.Errorf("failed to write payload to hash: %w", err)
+		return nil, fmt.Errorf("failed to write payload to hash: %w", err)
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// parseSignatureHeader returns the timestamp, the event ID and the v1 signatures of the signature header.
+// The unknown fields are ignored, so the new signature schemes can be added alongside.
+func parseSignatureHeader(header string) (timestamp int64, eventID string, signatures [][]byte, err error) {
+	for _, field := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			if timestamp, err = strconv.ParseInt(value, 10, 64); err != nil {
+				return 0, "", nil, fmt.Errorf("%w: invalid timestamp", ErrInvalidSignature)
+			}
+		case "id":
+			eventID = value
+		case signatureVersion:
+			s, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return 0, "", nil, fmt.Errorf("%w: failed to decode signature", ErrInvalidSignature)
+			}
+			signatures = append(signatures, s)
+		}
 	}
 
-	actualSignature := hash.Sum(nil)
-	if !hmac.Equal(expectedSignature, actualSignature) {
-		return errors.New("signature verification failed")
+	if timestamp == 0 || eventID == "" || len(signatures) == 0 {
+		return 0, "", nil, fmt.Errorf("%w: malformed signature header", ErrInvalidSignature)
 	}
 
-	return nil
+	return timestamp, eventID, signatures, nil
 }
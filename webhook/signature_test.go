@@ -1,8 +1,11 @@
 package webhook
 
 import (
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/easypmnt/checkout-api/events"
 	"github.com/stretchr/testify/require"
 )
 
@@ -10,10 +13,79 @@ func TestSignature(t *testing.T) {
 	secretKey := []byte("secret")
 	payload := []byte("payload")
 
-	signature, err := SignPayload(payload, secretKey)
+	header, err := SignPayload(payload, "evt_1", time.Now(), secretKey)
 	require.NoError(t, err)
-	require.NotEmpty(t, signature)
+	require.Regexp(t, `^t=\d+,id=evt_1,v1=.+$`, header)
 
-	err = VerifySignature(payload, signature, secretKey)
+	eventID, err := VerifySignature(payload, header, secretKey, 0)
 	require.NoError(t, err)
+	require.Equal(t, "evt_1", eventID)
+
+	_, err = VerifySignature([]byte("tampered"), header, secretKey, 0)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+	_, err = VerifySignature(payload, header, []byte("other"), 0)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+	// the event ID is signed, so it can't be replaced to bypass the deduplication
+	_, err = VerifySignature(payload, strings.Replace(header, "id=evt_1", "id=evt_2", 1), secretKey, 0)
+	require.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = SignPayload(payload, "evt,1", time.Now(), secretKey)
+	require.Error(t, err)
+
+	for _, h := range []string{"", "v1=abc", "t=abc,id=evt_1,v1=abc", header[strings.Index(header, ",")+1:]} {
+		_, err = VerifySignature(payload, h, secretKey, 0)
+		require.ErrorIs(t, err, ErrInvalidSignature, h)
+	}
+}
+
+func TestSignatureTolerance(t *testing.T) {
+	secretKey := []byte("secret")
+	payload := []byte("payload")
+
+	old, err := SignPayload(payload, "evt_1", time.Now().Add(-DefaultSignatureTolerance-time.Minute), secretKey)
+	require.NoError(t, err)
+	_, err = VerifySignature(payload, old, secretKey, 0)
+	require.ErrorIs(t, err, ErrSignatureExpired)
+	_, err = VerifySignature(payload, old, secretKey, time.Hour)
+	require.NoError(t, err)
+
+	future, err := SignPayload(payload, "evt_1", time.Now().Add(DefaultSignatureTolerance+time.Minute), secretKey)
+	require.NoError(t, err)
+	_, err = VerifySignature(payload, future, secretKey, 0)
+	require.ErrorIs(t, err, ErrSignatureExpired)
+
+	// the rotated secrets sign the same content, any of the signatures may match
+	rotated, err := SignPayload(payload, "evt_1", time.Now(), []byte("previous"))
+	require.NoError(t, err)
+	current, err := SignPayload(payload, "evt_1", time.Now(), secretKey)
+	require.NoError(t, err)
+	_, err = VerifySignature(payload, rotated+","+current[strings.LastIndex(current, ",")+1:], secretKey, 0)
+	require.NoError(t, err)
+}
+
+func TestEventID(t *testing.T) {
+	id, err := EventID("payment.succeeded", map[string]string{"payment_id": "pid"})
+	require.NoError(t, err)
+	require.Regexp(t, `^evt_[0-9a-f]{32}$`, id)
+
+	same, err := EventID("payment.succeeded", map[string]string{"payment_id": "pid"})
+	require.NoError(t, err)
+	require.Equal(t, id, same)
+
+	other, err := EventID("payment.failed", map[string]string{"payment_id": "pid"})
+	require.NoError(t, err)
+	require.NotEqual(t, id, other)
+
+	// the same event occurred again with the same data
+	occurred := events.PaymentLinkGeneratedPayload{
+		PaymentID:  events.PaymentID{PaymentID: "pid"},
+		Occurrence: events.Occurrence{OccurredAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)},
+		Link:       "solana:link",
+	}
+	first, err := EventID("payment.link.generated", occurred)
+	require.NoError(t, err)
+	occurred.OccurredAt = occurred.OccurredAt.Add(time.Millisecond)
+	again, err := EventID("payment.link.generated", occurred)
+	require.NoError(t, err)
+	require.NotEqual(t, first, again)
 }
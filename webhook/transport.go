@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/easypmnt/checkout-api/internal/httpencoder"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-kit/kit/transport"
+	httptransport "github.com/go-kit/kit/transport/http"
+	"github.com/google/uuid"
+)
+
+type (
+	logger interface {
+		Log(keyvals ...interface{}) error
+	}
+
+	middlewareFunc func(http.Handler) http.Handler
+)
+
+// MakeHTTPHandler returns an http.Handler that serves the webhook destinations API.
+// All the endpoints require authorization.
+func MakeHTTPHandler(e Endpoints, log logger, authMdw middlewareFunc) http.Handler {
+	r := chi.NewRouter()
+
+	options := []httptransport.ServerOption{
+		httptransport.ServerErrorHandler(transport.NewLogErrorHandler(log)),
+		httptransport.ServerErrorEncoder(httpencoder.EncodeError(log, codeAndMessageFrom)),
+	}
+
+	r.Use(authMdw)
+
+	r.Get("/", httptransport.NewServer(
+		e.ListDestinations,
+		httptransport.NopRequestDecoder,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Post("/", httptransport.NewServer(
+		e.CreateDestination,
+		decodeDestinationRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Put("/{id}", httptransport.NewServer(
+		e.UpdateDestination,
+		decodeDestinationRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	r.Delete("/{id}", httptransport.NewServer(
+		e.DeleteDestination,
+		decodeDeleteDestinationRequest,
+		httpencoder.EncodeResponse,
+		options...,
+	).ServeHTTP)
+
+	return r
+}
+
+// returns http error code by error type
+func codeAndMessageFrom(err error) (int, interface{}) {
+	switch {
+	case errors.Is(err, ErrDestinationNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, ErrInvalidRequest):
+		return http.StatusBadRequest, err.Error()
+	}
+
+	return httpencoder.CodeAndMessageFrom(err)
+}
+
+// decodeDestinationRequest is a transport/http.DecodeRequestFunc that decodes
+// the destination from the request body and its ID from the URL path, if any.
+func decodeDestinationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	var req DestinationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidRequest, err.Error())
+	}
+
+	if id := chi.URLParam(r, "id"); id != "" {
+		destinationID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid destination id", ErrInvalidRequest)
+		}
+		req.ID = destinationID
+	}
+
+	return req, nil
+}
+
+// decodeDeleteDestinationRequest is a transport/http.DecodeRequestFunc that decodes
+// the destination ID from the URL path.
+func decodeDeleteDestinationRequest(_ context.Context, r *http.Request) (interface{}, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid destination id", ErrInvalidRequest)
+	}
+
+	return DeleteDestinationRequest{ID: id}, nil
+}
@@ -21,6 +21,7 @@ type (
 		Event     string      `json:"event"`                // The name of the event that triggered the webhook
 		EventID   string      `json:"event_id,omitempty"`   // The ID of the event that triggered the webhook
 		WebhookID string      `json:"webhook_id,omitempty"` // The ID of the webhook that triggered the webhook
+		Livemode  bool        `json:"livemode"`             // False if the event belongs to a test-mode payment
 		Data      interface{} `json:"data"`                 // The data associated with the event that triggered the webhook
 	}
 
@@ -52,5 +53,7 @@ const (
 // FireEventPayload is the payload for the webhook:fire_event task.
 type FireEventPayload struct {
 	Event   string      `json:"event"`
+	EventID string      `json:"event_id,omitempty"` // the same for all the destinations and the retries of the event
+	URL     string      `json:"url,omitempty"`      // destination of the event, the merchant webhook if empty
 	Payload interface{} `json:"payload"`
 }
@@ -5,23 +5,41 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/easypmnt/checkout-api/events"
 	"github.com/hibiken/asynq"
 )
 
 type (
 	// Worker is a task handler for email delivery.
 	Worker struct {
-		svc service
+		svc  service
+		emit func(events.EventName, interface{})
 	}
 
+	// WorkerOption is a function that configures the webhook worker.
+	WorkerOption func(*Worker)
+
 	service interface {
-		FireEvent(event string, payload interface{}) error
+		FireEvent(eventID, event string, payload interface{}) error
+		FireEventTo(eventID, event, url string, payload interface{}) error
 	}
 )
 
 // NewWorker creates a new email task handler.
-func NewWorker(svc service) *Worker {
-	return &Worker{svc: svc}
+func NewWorker(svc service, opts ...WorkerOption) *Worker {
+	w := &Worker{svc: svc}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// WithEvents configures the worker to emit the webhook.delivered event
+// when a payment event is delivered to the webhook, e.g. for the payment timeline.
+func WithEvents(emit func(events.EventName, interface{})) WorkerOption {
+	return func(w *Worker) {
+		w.emit = emit
+	}
 }
 
 // Register registers task handlers for email delivery.
@@ -36,9 +54,66 @@ func (w *Worker) FireEvent(ctx context.Context, t *asynq.Task) error {
 		return fmt.Errorf("failed to unmarshal payload: %w", err)
 	}
 
-	if err := w.svc.FireEvent(p.Event, p.Payload); err != nil {
+	retried, _ := asynq.GetRetryCount(ctx)
+	if retried > 0 {
+		retriesTotal.WithLabelValues(p.Event).Inc()
+	}
+
+	// the tasks enqueued before the events got IDs are identified by the task ID, which survives the retries
+	eventID := p.EventID
+	if eventID == "" {
+		eventID, _ = asynq.GetTaskID(ctx)
+	}
+
+	var err error
+	if p.URL != "" {
+		err = w.svc.FireEventTo(eventID, p.Event, p.URL, p.Payload)
+	} else {
+		err = w.svc.FireEvent(eventID, p.Event, p.Payload)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to fire webhook event: %w", err)
 	}
 
+	if w.emit != nil {
+		if pid := paymentIDFrom(p.Payload); pid != "" {
+			taskID, _ := asynq.GetTaskID(ctx)
+			w.emit(events.WebhookDelivered, events.WebhookDeliveredPayload{
+				PaymentID: events.PaymentID{PaymentID: pid},
+				TaskID:    taskID,
+				Event:     p.Event,
+				Retried:   retried,
+			})
+		}
+	}
+
 	return nil
 }
+
+// paymentIDOf returns the payment_id field of the event payload, either decoded or an event struct.
+func paymentIDOf(payload interface{}) string {
+	if pid := paymentIDFrom(payload); pid != "" {
+		return pid
+	}
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return ""
+	}
+	var p struct {
+		PaymentID string `json:"payment_id"`
+	}
+	_ = json.Unmarshal(b, &p)
+
+	return p.PaymentID
+}
+
+// paymentIDFrom returns the payment_id field of the decoded event payload, if any.
+func paymentIDFrom(payload interface{}) string {
+	if m, ok := payload.(map[string]interface{}); ok {
+		if pid, ok := m["payment_id"].(string); ok {
+			return pid
+		}
+	}
+	return ""
+}
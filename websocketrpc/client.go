@@ -5,10 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync/atomic"
+	"time"
 
 	"github.com/easypmnt/checkout-api/events"
+	"github.com/easypmnt/checkout-api/internal/logging"
 	"github.com/gorilla/websocket"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -17,11 +18,16 @@ type (
 		conn    *websocket.Conn
 		emitter eventsEmitter
 		log     logger
+		health  *Health // optional
 
 		nextReqID uint64
 
 		subscriptions     *subscriptions
 		responseCallbacks *responseCallbacks
+		watched           map[string]struct{}
+
+		references   ReferenceSource // optional
+		syncInterval time.Duration
 
 		reqChan   chan *Request
 		respChan  chan *Response
@@ -32,12 +38,17 @@ type (
 	EventHandler     func(base58Addr string, event json.RawMessage) error
 	ResponseCallback func(json.RawMessage, error) error
 
+	// ReferenceSource returns the reference accounts of the pending transactions,
+	// which the client must be subscribed to.
+	ReferenceSource func(ctx context.Context) ([]string, error)
+
 	eventsEmitter interface {
 		Emit(eventName events.EventName, payload interface{})
 		On(name events.EventName, listeners ...events.Listener)
 	}
 
 	logger interface {
+		Debugf(format string, args ...interface{})
 		Infof(format string, args ...interface{})
 		Errorf(format string, args ...interface{})
 	}
@@ -52,6 +63,7 @@ func NewClient(conn *websocket.Conn, opts ...ClientOption) *Client {
 
 		subscriptions:     newSubscriptions(),
 		responseCallbacks: newResponseCallbacks(),
+		watched:           make(map[string]struct{}),
 
 		reqChan:   make(chan *Request, 1000),
 		respChan:  make(chan *Response, 1000),
@@ -63,7 +75,7 @@ func NewClient(conn *websocket.Conn, opts ...ClientOption) *Client {
 	}
 
 	if c.log == nil {
-		c.log = logrus.New()
+		c.log = logging.Default().Module("websocketrpc")
 	}
 
 	if c.emitter == nil {
@@ -89,7 +101,14 @@ func (c *Client) ListenNewTransactions(event events.EventName, payload interface
 
 	c.log.Infof("websocketrpc: new transaction created: %s, subscribing...", data.Reference)
 
-	return c.Subscribe(data.Reference)
+	// the transaction may be found by any of its references
+	for _, ref := range append([]string{data.Reference}, data.References...) {
+		if err := c.Subscribe(ref); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // ListenTransactionUpdates listens for transaction updates.
@@ -105,6 +124,10 @@ func (c *Client) ListenTransactionUpdates(event events.EventName, payload interf
 
 	if data.Status != "pending" {
 		c.log.Infof("websocketrpc: transaction %s updated, unsubscribing...", data.Reference)
+		for _, ref := range data.References {
+			// the additional references may not be subscribed, e.g. if the listener has been restarted
+			_ = c.UnsubscribeByAddress(ref)
+		}
 		return c.UnsubscribeByAddress(data.Reference)
 	}
 
@@ -112,7 +135,12 @@ func (c *Client) ListenTransactionUpdates(event events.EventName, payload interf
 }
 
 // Subscribe subscribes for account notifications to the given wallet address.
+// It does nothing if the address is already subscribed to.
 func (c *Client) Subscribe(base58Addr string) error {
+	if !c.subscriptions.Reserve(base58Addr) {
+		return nil
+	}
+
 	err := c.sendRequest(&Request{
 		Version: "2.0",
 		ID:      c.nextReqID,
@@ -120,20 +148,24 @@ func (c *Client) Subscribe(base58Addr string) error {
 		Params:  AccountSubscribeRequestPayload(base58Addr),
 	}, func(resp json.RawMessage, err error) error {
 		if err.Error() != "" {
+			c.subscriptions.Release(base58Addr)
 			return fmt.Errorf("websocketrpc: subscribe: %w", err)
 		}
 
 		var jsonN json.Number
 		if err := json.Unmarshal(resp, &jsonN); err != nil {
+			c.subscriptions.Release(base58Addr)
 			return fmt.Errorf("websocketrpc: subscribe: %w", err)
 		}
 
 		subID, err := jsonN.Float64()
 		if err != nil {
+			c.subscriptions.Release(base58Addr)
 			return fmt.Errorf("websocketrpc: subscribe: %w", err)
 		}
 
 		if subID == 0 {
+			c.subscriptions.Release(base58Addr)
 			return fmt.Errorf("websocketrpc: subscribe: failed to subscribe")
 		}
 
@@ -143,12 +175,74 @@ func (c *Client) Subscribe(base58Addr string) error {
 		return nil
 	})
 	if err != nil {
+		c.subscriptions.Release(base58Addr)
 		return fmt.Errorf("websocketrpc: subscribe: %w", err)
 	}
 
 	return nil
 }
 
+// SyncReferences subscribes to the references of the pending transactions,
+// which are not subscribed yet, e.g. created before the client has started,
+// and unsubscribes from the ones which are not pending anymore, e.g. expired.
+func (c *Client) SyncReferences(ctx context.Context) error {
+	if c.references == nil {
+		return nil
+	}
+
+	// the addresses subscribed after the snapshot may be not returned by the source yet
+	subscribed := c.subscriptions.Addresses()
+
+	refs, err := c.references(ctx)
+	if err != nil {
+		return fmt.Errorf("websocketrpc: sync references: %w", err)
+	}
+
+	pending := make(map[string]struct{}, len(refs))
+	for _, ref := range refs {
+		pending[ref] = struct{}{}
+		if _, ok := subscribed[ref]; ok {
+			continue
+		}
+		if err := c.Subscribe(ref); err != nil {
+			return fmt.Errorf("websocketrpc: sync references: %w", err)
+		}
+	}
+
+	for addr := range subscribed {
+		if _, ok := pending[addr]; ok {
+			continue
+		}
+		if _, ok := c.watched[addr]; ok {
+			continue
+		}
+		// the subscription may be already removed by the transaction update
+		if err := c.UnsubscribeByAddress(addr); err != nil {
+			c.log.Errorf("websocketrpc: sync references: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// referenceSyncer syncs the reference subscriptions periodically.
+func (c *Client) referenceSyncer(ctx context.Context) error {
+	ticker := time.NewTicker(c.syncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.SyncReferences(ctx); err != nil {
+			c.log.Errorf("websocketrpc: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // Unsubscribe unsubscribes from account notifications for the given subscription ID.
 func (c *Client) Unsubscribe(subID float64) error {
 	err := c.sendRequest(&Request{
@@ -237,8 +331,11 @@ func (c *Client) listener(ctx context.Context) error {
 				}
 				continue
 			}
+			if c.health != nil {
+				c.health.touch()
+			}
 
-			c.log.Infof("websocketrpc: listen: received message: %s", msg)
+			c.log.Debugf("websocketrpc: listen: received message: %s", msg)
 
 			var parsedMsg messagePayload
 			if err := json.Unmarshal(msg, &parsedMsg); err != nil {
@@ -271,14 +368,23 @@ func (c *Client) runner(ctx context.Context) error {
 			}
 		case event, open := <-c.eventChan:
 			if open && event.Method == EventAccountNotification {
-				c.log.Infof("websocketrpc: run: received account notification: %s", string(event.Params.Result))
+				c.log.Debugf("websocketrpc: run: received account notification: %s", string(event.Params.Result))
 				if sid, err := event.Params.Subscription.Float64(); err == nil && sid > 0 {
 					base58Addr, ok := c.subscriptions.Get(sid)
 					if !ok {
 						c.log.Errorf("websocketrpc: run: error handling event: subscription ID %d not found", sid)
 						continue
 					}
-					c.log.Infof("websocketrpc: run: emitting account notification for address %s", base58Addr)
+					if _, ok := c.watched[base58Addr]; ok {
+						c.log.Debugf("websocketrpc: run: emitting wallet notification for address %s", base58Addr)
+						c.emitter.Emit(events.WalletAccountNotification,
+							events.AccountPayload{
+								Address: base58Addr,
+							},
+						)
+						continue
+					}
+					c.log.Debugf("websocketrpc: run: emitting account notification for address %s", base58Addr)
 					c.emitter.Emit(events.TransactionReferenceNotification,
 						events.ReferencePayload{
 							Reference: base58Addr,
@@ -299,6 +405,23 @@ func (c *Client) runner(ctx context.Context) error {
 	}
 }
 
+// pinger pings the server periodically, so the pongs keep the idle connection healthy.
+func (c *Client) pinger(ctx context.Context) error {
+	ticker := time.NewTicker(c.health.pingInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(5*time.Second)); err != nil {
+				c.log.Errorf("websocketrpc: ping: %v", err)
+			}
+		}
+	}
+}
+
 // Run websocket rpc service.
 func (c *Client) Run(ctx context.Context) error {
 	eg, _ := errgroup.WithContext(ctx)
@@ -309,8 +432,31 @@ func (c *Client) Run(ctx context.Context) error {
 	eg.Go(func() error {
 		return c.runner(ctx)
 	})
+	if c.health != nil {
+		c.health.connect()
+		defer c.health.disconnect()
+
+		c.conn.SetPongHandler(func(string) error {
+			c.health.touch()
+			return nil
+		})
+		eg.Go(func() error {
+			return c.pinger(ctx)
+		})
+	}
 
 	c.log.Infof("websocketrpc: running...")
+
+	for addr := range c.watched {
+		if err := c.Subscribe(addr); err != nil {
+			c.log.Errorf("websocketrpc: run: failed to subscribe to watched address %s: %v", addr, err)
+		}
+	}
+	if c.references != nil {
+		eg.Go(func() error {
+			return c.referenceSyncer(ctx)
+		})
+	}
 	defer func() { c.log.Infof("websocketrpc: stopped") }()
 
 	if err := eg.Wait(); err != nil {
@@ -1,5 +1,7 @@
 package websocketrpc
 
+import "time"
+
 // WithLogger sets the logger for the client.
 func WithLogger(l logger) ClientOption {
 	return func(c *Client) {
@@ -7,9 +9,41 @@ func WithLogger(l logger) ClientOption {
 	}
 }
 
+// WithHealth sets the connection health tracker and enables the keep-alive pings.
+func WithHealth(h *Health) ClientOption {
+	return func(c *Client) {
+		c.health = h
+	}
+}
+
 // WithEventsEmitter sets the events emitter for the client.
 func WithEventsEmitter(e eventsEmitter) ClientOption {
 	return func(c *Client) {
 		c.emitter = e
 	}
 }
+
+// WithWatchedAddresses sets the wallet addresses to subscribe to for the whole client lifetime,
+// e.g. merchant settlement wallet and its token accounts.
+// Notifications for these addresses are emitted as events.WalletAccountNotification.
+func WithWatchedAddresses(addrs ...string) ClientOption {
+	return func(c *Client) {
+		for _, addr := range addrs {
+			c.watched[addr] = struct{}{}
+		}
+	}
+}
+
+// WithReferences sets the source of the pending transaction references.
+// The client subscribes to them on start and keeps the subscriptions in sync with the source
+// every interval (1 minute if 0), so the references missed by the events are watched too
+// and the ones of the expired transactions are unsubscribed.
+func WithReferences(src ReferenceSource, interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.references = src
+		c.syncInterval = interval
+		if c.syncInterval <= 0 {
+			c.syncInterval = time.Minute
+		}
+	}
+}
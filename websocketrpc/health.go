@@ -0,0 +1,84 @@
+package websocketrpc
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Health tracks the liveness of the websocket connection: the time since the last message or pong.
+// The client pings the server every third of the maximum silence, so an idle connection stays healthy.
+// It's meant for the readiness probe of the instance running the websocket listener.
+type Health struct {
+	maxSilence time.Duration
+	now        func() time.Time
+
+	connected int32 // 1 while the client is running
+	lastSeen  int64 // unix nano time of the last message or pong
+}
+
+// NewHealth creates a new websocket connection health tracker.
+// The maximum silence is one minute if it's zero.
+func NewHealth(maxSilence time.Duration) *Health {
+	if maxSilence <= 0 {
+		maxSilence = time.Minute
+	}
+	return &Health{maxSilence: maxSilence, now: time.Now}
+}
+
+// Check returns an error if the connection is silent for longer than the maximum silence.
+// It returns nil while the client is not running, e.g. on the instances which aren't the leader.
+func (h *Health) Check() error {
+	if atomic.LoadInt32(&h.connected) == 0 {
+		return nil
+	}
+
+	silence := h.now().Sub(time.Unix(0, atomic.LoadInt64(&h.lastSeen)))
+	if silence > h.maxSilence {
+		return fmt.Errorf("solana websocket: no messages for %s", silence.Round(time.Second))
+	}
+
+	return nil
+}
+
+// Probe checks the websocket connectivity: the silence of the connection while the client is running,
+// or a new connection to the endpoint otherwise, e.g. on the instances which aren't the leader.
+func (h *Health) Probe(ctx context.Context, endpoint string) error {
+	if atomic.LoadInt32(&h.connected) == 1 {
+		return h.Check()
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("solana websocket: %w", err)
+	}
+	defer conn.Close()
+
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+
+	return nil
+}
+
+// pingInterval returns the interval of the pings which keep the idle connection healthy.
+func (h *Health) pingInterval() time.Duration {
+	return h.maxSilence / 3
+}
+
+// connect marks the client as running.
+func (h *Health) connect() {
+	h.touch()
+	atomic.StoreInt32(&h.connected, 1)
+}
+
+// disconnect marks the client as stopped.
+func (h *Health) disconnect() {
+	atomic.StoreInt32(&h.connected, 0)
+}
+
+// touch records a message or pong from the server.
+func (h *Health) touch() {
+	atomic.StoreInt64(&h.lastSeen, h.now().UnixNano())
+}
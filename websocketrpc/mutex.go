@@ -46,13 +46,15 @@ func (rc *responseCallbacks) Delete(id uint64) {
 // subscriptions is a map of subscription ID to event name.
 type subscriptions struct {
 	sync.RWMutex
-	m map[float64]string
+	m       map[float64]string
+	pending map[string]struct{} // addresses with the subscribe request in flight
 }
 
 // newSubscriptions returns a new subscriptions.
 func newSubscriptions() *subscriptions {
 	return &subscriptions{
-		m: make(map[float64]string),
+		m:       make(map[float64]string),
+		pending: make(map[string]struct{}),
 	}
 }
 
@@ -61,6 +63,42 @@ func (s *subscriptions) Set(id float64, name string) {
 	s.Lock()
 	defer s.Unlock()
 	s.m[id] = name
+	delete(s.pending, name)
+}
+
+// Reserve marks the given address as being subscribed to.
+// Returns false if the address is already subscribed or the subscription is in flight.
+func (s *subscriptions) Reserve(name string) bool {
+	s.Lock()
+	defer s.Unlock()
+	if _, ok := s.pending[name]; ok {
+		return false
+	}
+	for _, v := range s.m {
+		if v == name {
+			return false
+		}
+	}
+	s.pending[name] = struct{}{}
+	return true
+}
+
+// Release releases the reservation of the given address, e.g. if the subscription failed.
+func (s *subscriptions) Release(name string) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.pending, name)
+}
+
+// Addresses returns a copy of the subscribed addresses.
+func (s *subscriptions) Addresses() map[string]struct{} {
+	s.RLock()
+	defer s.RUnlock()
+	result := make(map[string]struct{}, len(s.m))
+	for _, v := range s.m {
+		result[v] = struct{}{}
+	}
+	return result
 }
 
 // Get gets the event name for the given subscription ID.